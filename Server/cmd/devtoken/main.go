@@ -0,0 +1,95 @@
+// Command devtoken выдает access/refresh токены для пользователя через dev провайдер
+// авторизации (AUTH_DEV_ENABLED=true). Используется для локальной разработки,
+// k6 сценариев из load/ и интеграционных тестов.
+//
+//	go run ./cmd/devtoken -uid alice -name "Alice"
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joho/godotenv"
+
+	"toppet/server/internal/app"
+	provideruserdata "toppet/server/internal/app/clients/provider_user_data"
+	tokenservice "toppet/server/internal/app/token_service"
+	"toppet/server/internal/repository"
+	"toppet/server/internal/service"
+)
+
+func main() {
+	uid := flag.String("uid", "", "ID пользователя у dev провайдера (обязателен)")
+	name := flag.String("name", "", "имя пользователя (используется при первом входе)")
+	flag.Parse()
+
+	if *uid == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	for _, path := range []string{".env", "../.env", "../../.env"} {
+		if err := godotenv.Load(path); err == nil {
+			break
+		}
+	}
+
+	cfg := app.LoadConfigFromEnv()
+	if err := app.ValidateConfig(cfg); err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+
+	devConf, ok := cfg.ProvidersConf[provideruserdata.DevProviderName]
+	if !ok || devConf.ProviderUserData == nil {
+		log.Fatalf("dev provider is disabled: set AUTH_DEV_ENABLED=true")
+	}
+
+	ctx := context.Background()
+	dbPool, err := pgxpool.New(ctx, cfg.DatabaseURL)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer dbPool.Close()
+
+	accessTokenService := tokenservice.NewTokenService(
+		[]byte(cfg.AccessTokenSecret),
+		time.Duration(cfg.AccessTokenTTLSec)*time.Second,
+		"access",
+	)
+	refreshTokenService := tokenservice.NewTokenService(
+		[]byte(cfg.RefreshTokenSecret),
+		time.Duration(cfg.RefreshTokenTTLSec)*time.Second,
+		"refresh",
+	)
+
+	topPetService := service.NewTopPetService(
+		repository.NewRepository(dbPool),
+		nil,
+		accessTokenService,
+		refreshTokenService,
+		map[string]service.ProviderUserData{provideruserdata.DevProviderName: devConf.ProviderUserData},
+		nil,
+		nil,
+		service.EmailLoginConfig{},
+		service.VoteFraudConfig{},
+		service.EmailNotificationConfig{},
+		service.TelegramConfig{},
+		service.WebhookConfig{},
+	)
+
+	authData, err := topPetService.Login(ctx, provideruserdata.DevProviderName, provideruserdata.EncodeDevCode(*uid, *name), "")
+	if err != nil {
+		log.Fatalf("Login failed: %v", err)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(authData); err != nil {
+		log.Fatalf("Failed to print tokens: %v", err)
+	}
+}
@@ -11,9 +11,14 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/rs/cors"
 
+	provideruserdata "toppet/server/internal/app/clients/provider_user_data"
 	appHttp "toppet/server/internal/app/http"
 	"toppet/server/internal/app/http/middleware"
+	"toppet/server/internal/app/mailer"
+	"toppet/server/internal/app/ratelimit"
+	"toppet/server/internal/app/telegram"
 	tokenservice "toppet/server/internal/app/token_service"
+	"toppet/server/internal/app/webhook"
 	"toppet/server/internal/app/ws"
 	"toppet/server/internal/repository"
 	"toppet/server/internal/service"
@@ -46,12 +51,22 @@ type (
 		store             *sessions.CookieStore
 		loginStateStore   map[string]appHttp.StateData
 		loginStateStoreMu sync.Mutex
+		// allowedOrigins - origins для CORS и проверки Origin у WebSocket
+		allowedOrigins []string
+		rateLimit      middleware.RateLimitConfig
+		// workersCtx - контекст фоновых задач; Close отменяет его через stopWorkers
+		workersCtx  context.Context
+		stopWorkers context.CancelFunc
 	}
 )
 
 func NewApp(ctx context.Context, config Config, dbConn *pgxpool.Pool) (*App, error) {
 	mux := http.NewServeMux()
-	hub := ws.NewHub()
+	hub := ws.NewHub(ws.HubLimits{
+		MaxConnectionsPerUser:     config.WSMaxConnectionsPerUser,
+		MaxConnectionsPerIP:       config.WSMaxConnectionsPerIP,
+		MaxSubscriptionsPerClient: config.WSMaxSubscriptionsPerClient,
+	})
 
 	// Build cookie store
 	store := sessions.NewCookieStore([]byte(config.StoreSecret))
@@ -86,8 +101,54 @@ func NewApp(ctx context.Context, config Config, dbConn *pgxpool.Pool) (*App, err
 		}
 	}
 
-	// Build service
-	topPetService := service.NewTopPetService(repo, hub, accessTokenService, refreshTokenService, providersMap)
+	// Build mailer
+	var mail service.Mailer
+	if config.MailBackend == "smtp" {
+		mail = mailer.NewSMTPMailer(config.SMTPHost, config.SMTPPort, config.SMTPUser, config.SMTPPassword, config.MailFrom)
+	} else {
+		mail = mailer.NewLogMailer(config.MailFrom, config.MailLogDir)
+	}
+
+	emailLogin := service.EmailLoginConfig{
+		Secret:      []byte(config.EmailLoginSecret),
+		TTL:         time.Duration(config.EmailLoginTTLSec) * time.Second,
+		CallbackURL: config.APIRoot + "/api/auth/email/callback",
+		RateLimit:   config.EmailLoginRateLimit,
+		RateWindow:  time.Duration(config.EmailLoginRateLimitWindow) * time.Second,
+	}
+
+	emailNotifications := service.EmailNotificationConfig{
+		Secret:         []byte(config.EmailNotificationsSecret),
+		UnsubscribeURL: config.APIRoot + "/api/notifications/unsubscribe",
+		SiteURL:        config.BaseURL,
+	}
+	if config.EmailNotificationsSecret != "" {
+		templates, err := mailer.LoadTemplates()
+		if err != nil {
+			return nil, err
+		}
+		emailNotifications.Templates = templates
+	}
+
+	telegramConfig := service.TelegramConfig{
+		BotUsername: config.TelegramBotUsername,
+		ChannelID:   config.TelegramChannelID,
+		SiteURL:     config.BaseURL,
+	}
+	if config.TelegramBotToken != "" {
+		telegramConfig.Bot = telegram.NewClient(config.TelegramAPIURL, config.TelegramBotToken)
+	}
+
+	webhookConfig := service.WebhookConfig{
+		Sender:            webhook.NewClient(webhook.DefaultTimeout, config.WebhookAllowPrivateNetworks),
+		AllowInsecureURLs: config.WebhookAllowPrivateNetworks,
+	}
+
+	voteFraud := service.VoteFraudConfig{
+		IPHashSecret:  []byte(config.VoteIPHashSecret),
+		NewAccountAge: time.Duration(config.VoteFraudNewAccountHours) * time.Hour,
+		FlagScore:     config.VoteFraudFlagScore,
+	}
 
 	// Build object storage uploader
 	var uploader *objectstorage.Uploader
@@ -106,6 +167,29 @@ func NewApp(ctx context.Context, config Config, dbConn *pgxpool.Pool) (*App, err
 		}
 	}
 
+	// Без хранилища удаление аккаунта чистит только БД
+	var storage service.MediaStorage
+	if uploader != nil {
+		storage = uploader
+	}
+
+	// Build service
+	topPetService := service.NewTopPetService(repo, hub, accessTokenService, refreshTokenService, providersMap, mail, storage, emailLogin, voteFraud, emailNotifications, telegramConfig, webhookConfig)
+
+	// Build rate limiter
+	rateLimit := middleware.RateLimitConfig{TrustProxy: config.TrustProxyHeaders}
+	if config.RateLimitEnabled {
+		policies, err := ratelimit.ParsePolicies(config.RateLimitPolicies)
+		if err != nil {
+			return nil, err
+		}
+		var store ratelimit.Store = ratelimit.NewMemoryStore()
+		if config.RateLimitStore == "postgres" {
+			store = ratelimit.NewPostgresStore(repo)
+		}
+		rateLimit.Limiter = ratelimit.NewLimiter(store, policies)
+	}
+
 	// CORS middleware
 	// Build allowed origins list
 	// Always include default dev origins
@@ -151,7 +235,10 @@ func NewApp(ctx context.Context, config Config, dbConn *pgxpool.Pool) (*App, err
 		store:             store,
 		loginStateStore:   make(map[string]appHttp.StateData),
 		loginStateStoreMu: sync.Mutex{},
+		allowedOrigins:    allowedOrigins,
+		rateLimit:         rateLimit,
 	}
+	app.workersCtx, app.stopWorkers = context.WithCancel(ctx)
 
 	app.registerRoutes()
 
@@ -159,9 +246,14 @@ func NewApp(ctx context.Context, config Config, dbConn *pgxpool.Pool) (*App, err
 	metaHandler := appHttp.NewMetaHTMLHandler(config.BaseURL, config.SPAIndexPath, topPetService)
 	mux.Handle("GET /contests/{contestId}/participants/{participantId}", http.HandlerFunc(metaHandler.ServeParticipant))
 	mux.Handle("GET /contests/{contestId}", http.HandlerFunc(metaHandler.ServeContest))
+	mux.Handle("GET /users/{userId}", http.HandlerFunc(metaHandler.ServeUser))
 	mux.Handle("GET /", http.HandlerFunc(metaHandler.ServeHome))
 
-	handler := corsMiddleware.Handler(mux)
+	var handler http.Handler = mux
+	if config.AuthQueryTokenEnabled {
+		handler = middleware.NewQueryTokenMiddleware(handler)
+	}
+	handler = corsMiddleware.Handler(handler)
 
 	app.server = &http.Server{
 		Addr:              config.Addr,
@@ -184,6 +276,13 @@ func (a *App) registerRoutes() {
 	a.mux.Handle("GET /api/auth/providers", appHttp.NewGetProvidersHandler(a.config.ProvidersConf, "/api/auth/providers"))
 	a.mux.Handle("POST /api/auth/login", appHttp.NewLoginHandler(a.config.ProvidersConf, "/api/auth/login", a.store, a.loginStateStore, &a.loginStateStoreMu))
 	a.mux.Handle("GET /api/auth/callback", appHttp.NewOAuthCallbackHandler(a.config.ProvidersConf, "/api/auth/callback", a.store, a.loginStateStore, &a.loginStateStoreMu, a.service))
+	if devConf, ok := a.config.ProvidersConf[provideruserdata.DevProviderName]; ok {
+		a.mux.Handle("GET /api/auth/dev/authorize", appHttp.NewDevAuthorizeHandler(devConf, "/api/auth/dev/authorize"))
+	}
+	if a.config.EmailLoginSecret != "" {
+		a.mux.Handle("POST /api/auth/email", appHttp.NewEmailLoginHandler(a.service, "/api/auth/email"))
+		a.mux.Handle("GET /api/auth/email/callback", appHttp.NewEmailLoginCallbackHandler(a.service, "/api/auth/email/callback", a.store))
+	}
 	a.mux.Handle("GET /api/auth/me", middleware.NewAuthMiddleware(
 		appHttp.NewGetCurrentUserHandler("/api/auth/me", a.service),
 		a.service,
@@ -192,6 +291,104 @@ func (a *App) registerRoutes() {
 		appHttp.NewUpdateCurrentUserHandler("/api/auth/me", a.service),
 		a.service,
 	))
+	avatarHandler := appHttp.NewUserAvatarHandler("/api/auth/me/avatar", a.service, a.uploader)
+	if a.uploader != nil {
+		a.mux.Handle("POST /api/auth/me/avatar", middleware.NewAuthMiddleware(
+			a.rateLimited(http.HandlerFunc(avatarHandler.Upload), ratelimit.PolicyUpload),
+			a.service,
+		))
+	}
+	a.mux.Handle("DELETE /api/auth/me/avatar", middleware.NewAuthMiddleware(
+		http.HandlerFunc(avatarHandler.Reset),
+		a.service,
+	))
+
+	// Account: выгрузка данных и удаление аккаунта
+	accountHandler := appHttp.NewAccountHandler("/api/auth/me", a.service)
+	a.mux.Handle("GET /api/auth/me/export", middleware.NewAuthMiddleware(
+		http.HandlerFunc(accountHandler.Export),
+		a.service,
+	))
+	a.mux.Handle("DELETE /api/auth/me", middleware.NewAuthMiddleware(
+		http.HandlerFunc(accountHandler.RequestDeletion),
+		a.service,
+	))
+	a.mux.Handle("GET /api/account-deletions/{jobId}", http.HandlerFunc(accountHandler.GetDeletion))
+
+	// Users: публичный профиль и настройки приватности
+	userHandler := appHttp.NewUserHandler("/api/users/{userId}", a.service)
+	a.mux.Handle("GET /api/users/{userId}", http.HandlerFunc(userHandler.GetProfile))
+	a.mux.Handle("GET /api/auth/me/privacy", middleware.NewAuthMiddleware(
+		http.HandlerFunc(userHandler.GetPrivacy),
+		a.service,
+	))
+	a.mux.Handle("PATCH /api/auth/me/privacy", middleware.NewAuthMiddleware(
+		http.HandlerFunc(userHandler.UpdatePrivacy),
+		a.service,
+	))
+
+	emailNotificationHandler := appHttp.NewEmailNotificationHandler("/api/auth/me/notification-preferences", a.service)
+	a.mux.Handle("GET /api/auth/me/notification-preferences", middleware.NewAuthMiddleware(
+		http.HandlerFunc(emailNotificationHandler.GetPreferences),
+		a.service,
+	))
+	a.mux.Handle("PATCH /api/auth/me/notification-preferences", middleware.NewAuthMiddleware(
+		http.HandlerFunc(emailNotificationHandler.UpdatePreferences),
+		a.service,
+	))
+	// Отписка по ссылке из письма работает без входа: пользователь подтверждается подписью токена
+	a.mux.Handle("GET /api/notifications/unsubscribe", http.HandlerFunc(emailNotificationHandler.UnsubscribePage))
+	a.mux.Handle("POST /api/notifications/unsubscribe", http.HandlerFunc(emailNotificationHandler.Unsubscribe))
+
+	// Telegram: привязка аккаунта к боту и анонсы конкурсов в канале
+	telegramHandler := appHttp.NewTelegramHandler("/api/auth/me/telegram", a.service)
+	a.mux.Handle("GET /api/auth/me/telegram", middleware.NewAuthMiddleware(
+		http.HandlerFunc(telegramHandler.GetStatus),
+		a.service,
+	))
+	a.mux.Handle("POST /api/auth/me/telegram/link", middleware.NewAuthMiddleware(
+		http.HandlerFunc(telegramHandler.CreateLink),
+		a.service,
+	))
+	a.mux.Handle("DELETE /api/auth/me/telegram", middleware.NewAuthMiddleware(
+		http.HandlerFunc(telegramHandler.Unlink),
+		a.service,
+	))
+	a.mux.Handle("GET /api/contests/{contestId}/telegram/announcements", middleware.NewAuthMiddleware(
+		http.HandlerFunc(telegramHandler.ListAnnouncements),
+		a.service,
+	))
+	a.mux.Handle("POST /api/contests/{contestId}/telegram/announcements", middleware.NewAuthMiddleware(
+		http.HandlerFunc(telegramHandler.Announce),
+		a.service,
+	))
+
+	// Webhooks конкурса: события для внешних систем организаторов
+	webhookHandler := appHttp.NewWebhookHandler("/api/contests/{contestId}/webhooks", a.service)
+	a.mux.Handle("GET /api/contests/{contestId}/webhooks", middleware.NewAuthMiddleware(
+		http.HandlerFunc(webhookHandler.List),
+		a.service,
+	))
+	a.mux.Handle("POST /api/contests/{contestId}/webhooks", middleware.NewAuthMiddleware(
+		http.HandlerFunc(webhookHandler.Create),
+		a.service,
+	))
+	a.mux.Handle("PATCH /api/webhooks/{webhookId}", middleware.NewAuthMiddleware(
+		http.HandlerFunc(webhookHandler.Update),
+		a.service,
+	))
+	a.mux.Handle("DELETE /api/webhooks/{webhookId}", middleware.NewAuthMiddleware(
+		http.HandlerFunc(webhookHandler.Delete),
+		a.service,
+	))
+	a.mux.Handle("GET /api/webhooks/{webhookId}/deliveries", middleware.NewAuthMiddleware(
+		http.HandlerFunc(webhookHandler.ListDeliveries),
+		a.service,
+	))
+	a.mux.Handle("POST /api/webhooks/{webhookId}/deliveries/{deliveryId}/redeliver", middleware.NewAuthMiddleware(
+		http.HandlerFunc(webhookHandler.Redeliver),
+		a.service,
+	))
 
 	// Contests (public)
 	a.mux.Handle("GET /api/contests", appHttp.NewListContestsHandler("/api/contests", a.service))
@@ -199,7 +396,7 @@ func (a *App) registerRoutes() {
 
 	// Contests (auth required)
 	a.mux.Handle("POST /api/contests", middleware.NewAuthMiddleware(
-		appHttp.NewCreateContestHandler("/api/contests", a.service),
+		a.rateLimited(appHttp.NewCreateContestHandler("/api/contests", a.service), ratelimit.PolicyCreate),
 		a.service,
 	))
 	a.mux.Handle("PATCH /api/contests/{contestId}", middleware.NewAuthMiddleware(
@@ -223,6 +420,29 @@ func (a *App) registerRoutes() {
 		a.service,
 	))
 
+	// Contest members (co-organizers)
+	membersHandler := appHttp.NewContestMembersHandler("/api/contests/{contestId}/members", a.service)
+	a.mux.Handle("GET /api/contests/{contestId}/members", middleware.NewAuthMiddleware(
+		http.HandlerFunc(membersHandler.ListMembers),
+		a.service,
+	))
+	a.mux.Handle("POST /api/contests/{contestId}/members", middleware.NewAuthMiddleware(
+		http.HandlerFunc(membersHandler.InviteMember),
+		a.service,
+	))
+	a.mux.Handle("POST /api/contests/{contestId}/members/accept", middleware.NewAuthMiddleware(
+		http.HandlerFunc(membersHandler.AcceptInvite),
+		a.service,
+	))
+	a.mux.Handle("POST /api/contests/{contestId}/members/transfer", middleware.NewAuthMiddleware(
+		http.HandlerFunc(membersHandler.TransferOwnership),
+		a.service,
+	))
+	a.mux.Handle("DELETE /api/contests/{contestId}/members/{userId}", middleware.NewAuthMiddleware(
+		http.HandlerFunc(membersHandler.RemoveMember),
+		a.service,
+	))
+
 	// Participants (public)
 	a.mux.Handle("GET /api/contests/{contestId}/participants", appHttp.NewListParticipantsHandler("/api/contests/{contestId}/participants", a.service))
 	a.mux.Handle("GET /api/contests/{contestId}/participants/{participantId}", appHttp.NewGetParticipantHandler("/api/contests/{contestId}/participants/{participantId}", a.service))
@@ -233,7 +453,7 @@ func (a *App) registerRoutes() {
 
 	// Participants (auth required)
 	a.mux.Handle("POST /api/contests/{contestId}/participants", middleware.NewAuthMiddleware(
-		appHttp.NewCreateParticipantHandler("/api/contests/{contestId}/participants", a.service),
+		a.rateLimited(appHttp.NewCreateParticipantHandler("/api/contests/{contestId}/participants", a.service), ratelimit.PolicyCreate),
 		a.service,
 	))
 	a.mux.Handle("PATCH /api/participants/{participantId}", middleware.NewAuthMiddleware(
@@ -244,13 +464,30 @@ func (a *App) registerRoutes() {
 		appHttp.NewDeleteParticipantHandler("/api/participants/{participantId}", a.service),
 		a.service,
 	))
+	entryModerationHandler := appHttp.NewEntryModerationHandler("/api/participants/{participantId}", a.service)
+	a.mux.Handle("GET /api/contests/{contestId}/moderation-queue", middleware.NewAuthMiddleware(
+		http.HandlerFunc(entryModerationHandler.ListQueue),
+		a.service,
+	))
+	a.mux.Handle("POST /api/participants/{participantId}/approve", middleware.NewAuthMiddleware(
+		http.HandlerFunc(entryModerationHandler.Approve),
+		a.service,
+	))
+	a.mux.Handle("POST /api/participants/{participantId}/reject", middleware.NewAuthMiddleware(
+		http.HandlerFunc(entryModerationHandler.Reject),
+		a.service,
+	))
+	a.mux.Handle("POST /api/participants/{participantId}/disqualify", middleware.NewAuthMiddleware(
+		appHttp.NewDisqualifyParticipantHandler("/api/participants/{participantId}/disqualify", a.service),
+		a.service,
+	))
 	if a.uploader != nil {
 		a.mux.Handle("POST /api/participants/{participantId}/photos", middleware.NewAuthMiddleware(
-			appHttp.NewUploadPhotoHandler("/api/participants/{participantId}/photos", a.service, a.uploader),
+			a.rateLimited(appHttp.NewUploadPhotoHandler("/api/participants/{participantId}/photos", a.service, a.uploader), ratelimit.PolicyUpload),
 			a.service,
 		))
 		a.mux.Handle("POST /api/participants/{participantId}/video", middleware.NewAuthMiddleware(
-			appHttp.NewUploadVideoHandler("/api/participants/{participantId}/video", a.service, a.uploader),
+			a.rateLimited(appHttp.NewUploadVideoHandler("/api/participants/{participantId}/video", a.service, a.uploader), ratelimit.PolicyUpload),
 			a.service,
 		))
 		a.mux.Handle("DELETE /api/participants/{participantId}/video", middleware.NewAuthMiddleware(
@@ -270,26 +507,151 @@ func (a *App) registerRoutes() {
 	// Photo Likes
 	photoLikeHandler := appHttp.NewPhotoLikeHandler("/api/photos/{photoId}/like", a.service)
 	a.mux.Handle("GET /api/photos/{photoId}/like", photoLikeHandler)
-	a.mux.Handle("POST /api/photos/{photoId}/like", middleware.NewAuthMiddleware(photoLikeHandler, a.service))
-	a.mux.Handle("DELETE /api/photos/{photoId}/like", middleware.NewAuthMiddleware(photoLikeHandler, a.service))
+	a.mux.Handle("POST /api/photos/{photoId}/like", middleware.NewAuthMiddleware(a.rateLimited(photoLikeHandler, ratelimit.PolicyLike), a.service))
+	a.mux.Handle("DELETE /api/photos/{photoId}/like", middleware.NewAuthMiddleware(a.rateLimited(photoLikeHandler, ratelimit.PolicyLike), a.service))
 
 	// Votes
-	a.mux.Handle("GET /api/contests/{contestId}/vote", appHttp.NewVoteHandler("/api/contests/{contestId}/vote", a.service))
+	voteOptions := appHttp.VoteHandlerOptions{TrustProxy: a.config.TrustProxyHeaders}
+	a.mux.Handle("GET /api/contests/{contestId}/vote", appHttp.NewVoteHandler("/api/contests/{contestId}/vote", a.service, voteOptions))
 	a.mux.Handle("POST /api/contests/{contestId}/vote", middleware.NewAuthMiddleware(
-		appHttp.NewVoteHandler("/api/contests/{contestId}/vote", a.service),
+		a.rateLimited(appHttp.NewVoteHandler("/api/contests/{contestId}/vote", a.service, voteOptions), ratelimit.PolicyVote),
 		a.service,
 	))
 	a.mux.Handle("DELETE /api/contests/{contestId}/vote", middleware.NewAuthMiddleware(
-		appHttp.NewVoteHandler("/api/contests/{contestId}/vote", a.service),
+		a.rateLimited(appHttp.NewVoteHandler("/api/contests/{contestId}/vote", a.service, voteOptions), ratelimit.PolicyVote),
+		a.service,
+	))
+	entryRulesHandler := appHttp.NewEntryRulesHandler("/api/contests/{contestId}/entry-rules", a.service)
+	a.mux.Handle("GET /api/contests/{contestId}/entry-rules", http.HandlerFunc(entryRulesHandler.GetRules))
+	a.mux.Handle("PUT /api/contests/{contestId}/entry-rules", middleware.NewAuthMiddleware(
+		http.HandlerFunc(entryRulesHandler.UpdateRules),
+		a.service,
+	))
+	votingPolicyHandler := appHttp.NewVotingPolicyHandler("/api/contests/{contestId}/voting-policy", a.service)
+	a.mux.Handle("GET /api/contests/{contestId}/voting-policy", http.HandlerFunc(votingPolicyHandler.GetPolicy))
+	a.mux.Handle("PUT /api/contests/{contestId}/voting-policy", middleware.NewAuthMiddleware(
+		http.HandlerFunc(votingPolicyHandler.UpdatePolicy),
+		a.service,
+	))
+	a.mux.Handle("GET /api/contests/{contestId}/invited-voters", middleware.NewAuthMiddleware(
+		http.HandlerFunc(votingPolicyHandler.ListInvitedVoters),
+		a.service,
+	))
+	a.mux.Handle("POST /api/contests/{contestId}/invited-voters", middleware.NewAuthMiddleware(
+		http.HandlerFunc(votingPolicyHandler.AddInvitedVoters),
+		a.service,
+	))
+	a.mux.Handle("DELETE /api/contests/{contestId}/invited-voters/{userId}", middleware.NewAuthMiddleware(
+		http.HandlerFunc(votingPolicyHandler.RemoveInvitedVoter),
+		a.service,
+	))
+	votingModeHandler := appHttp.NewVotingModeHandler("/api/contests/{contestId}/voting-mode", a.service)
+	a.mux.Handle("PUT /api/contests/{contestId}/voting-mode", middleware.NewAuthMiddleware(
+		http.HandlerFunc(votingModeHandler.UpdateVotingMode),
+		a.service,
+	))
+	a.mux.Handle("GET /api/contests/{contestId}/results", http.HandlerFunc(votingModeHandler.GetResults))
+	categoriesHandler := appHttp.NewContestCategoriesHandler("/api/contests/{contestId}/categories", a.service)
+	a.mux.Handle("GET /api/contests/{contestId}/categories", http.HandlerFunc(categoriesHandler.ListCategories))
+	a.mux.Handle("POST /api/contests/{contestId}/categories", middleware.NewAuthMiddleware(
+		http.HandlerFunc(categoriesHandler.CreateCategory),
+		a.service,
+	))
+	a.mux.Handle("PUT /api/contests/{contestId}/categories/{categoryId}", middleware.NewAuthMiddleware(
+		http.HandlerFunc(categoriesHandler.UpdateCategory),
+		a.service,
+	))
+	a.mux.Handle("DELETE /api/contests/{contestId}/categories/{categoryId}", middleware.NewAuthMiddleware(
+		http.HandlerFunc(categoriesHandler.DeleteCategory),
+		a.service,
+	))
+	juryHandler := appHttp.NewJuryHandler("/api/contests/{contestId}/jury", a.service)
+	a.mux.Handle("GET /api/contests/{contestId}/jury/criteria", http.HandlerFunc(juryHandler.ListCriteria))
+	a.mux.Handle("POST /api/contests/{contestId}/jury/criteria", middleware.NewAuthMiddleware(
+		http.HandlerFunc(juryHandler.CreateCriterion),
+		a.service,
+	))
+	a.mux.Handle("DELETE /api/contests/{contestId}/jury/criteria/{criterionId}", middleware.NewAuthMiddleware(
+		http.HandlerFunc(juryHandler.DeleteCriterion),
+		a.service,
+	))
+	a.mux.Handle("PUT /api/contests/{contestId}/jury/weight", middleware.NewAuthMiddleware(
+		http.HandlerFunc(juryHandler.UpdateWeight),
+		a.service,
+	))
+	a.mux.Handle("GET /api/contests/{contestId}/jury/scores", middleware.NewAuthMiddleware(
+		http.HandlerFunc(juryHandler.ListScores),
+		a.service,
+	))
+	a.mux.Handle("PUT /api/contests/{contestId}/jury/scores/{participantId}", middleware.NewAuthMiddleware(
+		http.HandlerFunc(juryHandler.SubmitScores),
+		a.service,
+	))
+	bracketHandler := appHttp.NewBracketHandler("/api/contests/{contestId}/bracket", a.service)
+	a.mux.Handle("GET /api/contests/{contestId}/bracket", http.HandlerFunc(bracketHandler.GetBracket))
+	a.mux.Handle("PUT /api/contests/{contestId}/bracket", middleware.NewAuthMiddleware(
+		http.HandlerFunc(bracketHandler.ConfigureBracket),
+		a.service,
+	))
+	a.mux.Handle("POST /api/contests/{contestId}/bracket/start", middleware.NewAuthMiddleware(
+		http.HandlerFunc(bracketHandler.StartBracket),
+		a.service,
+	))
+	a.mux.Handle("POST /api/contests/{contestId}/bracket/matchups/{matchupId}/vote", middleware.NewAuthMiddleware(
+		a.rateLimited(http.HandlerFunc(bracketHandler.VoteInMatchup), ratelimit.PolicyVote),
+		a.service,
+	))
+	voteFraudHandler := appHttp.NewVoteFraudHandler("/api/contests/{contestId}/votes", a.service)
+	a.mux.Handle("GET /api/contests/{contestId}/votes/flagged", middleware.NewAuthMiddleware(
+		http.HandlerFunc(voteFraudHandler.ListFlagged),
+		a.service,
+	))
+	a.mux.Handle("POST /api/contests/{contestId}/votes/void", middleware.NewAuthMiddleware(
+		http.HandlerFunc(voteFraudHandler.VoidVotes),
+		a.service,
+	))
+
+	// Species & Breeds (public)
+	speciesHandler := appHttp.NewSpeciesHandler("/api/species", a.service)
+	a.mux.Handle("GET /api/species", http.HandlerFunc(speciesHandler.ListSpecies))
+	a.mux.Handle("GET /api/species/{species}/breeds", http.HandlerFunc(speciesHandler.ListBreeds))
+
+	// Pets
+	petHandler := appHttp.NewPetHandler("/api/pets", a.service)
+	a.mux.Handle("GET /api/pets", middleware.NewAuthMiddleware(
+		http.HandlerFunc(petHandler.ListMine),
+		a.service,
+	))
+	a.mux.Handle("POST /api/pets", middleware.NewAuthMiddleware(
+		a.rateLimited(http.HandlerFunc(petHandler.Create), ratelimit.PolicyCreate),
+		a.service,
+	))
+	a.mux.Handle("GET /api/pets/{petId}", http.HandlerFunc(petHandler.Get))
+	a.mux.Handle("PATCH /api/pets/{petId}", middleware.NewAuthMiddleware(
+		http.HandlerFunc(petHandler.Update),
+		a.service,
+	))
+	a.mux.Handle("DELETE /api/pets/{petId}", middleware.NewAuthMiddleware(
+		http.HandlerFunc(petHandler.Delete),
+		a.service,
+	))
+	if a.uploader != nil {
+		a.mux.Handle("POST /api/pets/{petId}/photos", middleware.NewAuthMiddleware(
+			a.rateLimited(appHttp.NewUploadPetPhotoHandler("/api/pets/{petId}/photos", a.service, a.uploader), ratelimit.PolicyUpload),
+			a.service,
+		))
+	}
+	a.mux.Handle("DELETE /api/pets/{petId}/photos/{photoId}", middleware.NewAuthMiddleware(
+		http.HandlerFunc(petHandler.DeletePhoto),
 		a.service,
 	))
 
 	// Comments (public)
 	commentsHandler := appHttp.NewCommentsHandler("/api/participants/{participantId}/comments", a.service)
 	a.mux.Handle("GET /api/participants/{participantId}/comments", commentsHandler)
-	a.mux.Handle("POST /api/participants/{participantId}/comments", middleware.NewAuthMiddleware(commentsHandler, a.service))
+	a.mux.Handle("POST /api/participants/{participantId}/comments", middleware.NewAuthMiddleware(a.rateLimited(commentsHandler, ratelimit.PolicyComment), a.service))
 	a.mux.Handle("PATCH /api/comments/{commentId}", middleware.NewAuthMiddleware(
-		http.HandlerFunc(commentsHandler.UpdateComment),
+		a.rateLimited(http.HandlerFunc(commentsHandler.UpdateComment), ratelimit.PolicyComment),
 		a.service,
 	))
 	a.mux.Handle("DELETE /api/comments/{commentId}", middleware.NewAuthMiddleware(
@@ -299,20 +661,102 @@ func (a *App) registerRoutes() {
 
 	// Chat (public)
 	a.mux.Handle("GET /api/contests/{contestId}/chat", appHttp.NewChatHandler("/api/contests/{contestId}/chat", a.service))
-	a.mux.Handle("GET /api/contests/{contestId}/chat/ws", appHttp.NewContestChatWSHandler("/api/contests/{contestId}/chat/ws", a.service, a.service, a.hub))
+	a.mux.Handle("GET /api/contests/{contestId}/chat/ws", appHttp.NewContestChatWSHandler("/api/contests/{contestId}/chat/ws", a.service, a.service, a.hub, appHttp.ContestChatWSOptions{
+		AllowQueryToken: a.config.WSQueryTokenEnabled,
+		AllowedOrigins:  a.allowedOrigins,
+		TrustProxy:      a.config.TrustProxyHeaders,
+		RateLimiter:     a.rateLimit.Limiter,
+	}))
+	a.mux.Handle("POST /api/ws/ticket", middleware.NewAuthMiddleware(
+		appHttp.NewWSTicketHandler("/api/ws/ticket", a.service),
+		a.service,
+	))
 	chatMessageHandler := appHttp.NewChatMessageHandler("/api/chat/{messageId}", a.service)
 	a.mux.Handle("PATCH /api/chat/{messageId}", middleware.NewAuthMiddleware(
-		http.HandlerFunc(chatMessageHandler.UpdateChatMessage),
+		a.rateLimited(http.HandlerFunc(chatMessageHandler.UpdateChatMessage), ratelimit.PolicyChatMessage),
 		a.service,
 	))
 	a.mux.Handle("DELETE /api/chat/{messageId}", middleware.NewAuthMiddleware(
 		http.HandlerFunc(chatMessageHandler.DeleteChatMessage),
 		a.service,
 	))
+
+	// Notifications
+	notificationHandler := appHttp.NewNotificationHandler("/api/notifications", a.service)
+	a.mux.Handle("GET /api/notifications", middleware.NewAuthMiddleware(
+		http.HandlerFunc(notificationHandler.List),
+		a.service,
+	))
+	a.mux.Handle("GET /api/notifications/unread-count", middleware.NewAuthMiddleware(
+		http.HandlerFunc(notificationHandler.UnreadCount),
+		a.service,
+	))
+	a.mux.Handle("POST /api/notifications/read-all", middleware.NewAuthMiddleware(
+		http.HandlerFunc(notificationHandler.MarkAllRead),
+		a.service,
+	))
+	a.mux.Handle("POST /api/notifications/{notificationId}/read", middleware.NewAuthMiddleware(
+		http.HandlerFunc(notificationHandler.MarkRead),
+		a.service,
+	))
+
+	// Moderation
+	moderationHandler := appHttp.NewModerationHandler("/api/moderation/actions", a.service)
+	a.mux.Handle("POST /api/moderation/actions", middleware.NewAuthMiddleware(
+		http.HandlerFunc(moderationHandler.CreateAction),
+		a.service,
+	))
+	a.mux.Handle("GET /api/moderation/actions", middleware.NewAuthMiddleware(
+		http.HandlerFunc(moderationHandler.ListActions),
+		a.service,
+	))
+	userRolesHandler := appHttp.NewUserRolesHandler("/api/admin/users/{userId}/roles/{role}", a.service)
+	a.mux.Handle("PUT /api/admin/users/{userId}/roles/{role}", middleware.NewAuthMiddleware(
+		http.HandlerFunc(userRolesHandler.GrantRole),
+		a.service,
+	))
+	a.mux.Handle("DELETE /api/admin/users/{userId}/roles/{role}", middleware.NewAuthMiddleware(
+		http.HandlerFunc(userRolesHandler.RevokeRole),
+		a.service,
+	))
+}
+
+// rateLimited оборачивает обработчик лимитом policy; внутри AuthMiddleware лимит считается по пользователю
+func (a *App) rateLimited(h http.Handler, policy string) http.Handler {
+	return middleware.NewRateLimitMiddleware(h, a.rateLimit, policy)
 }
 
 func (a *App) ListenAndServe() error {
 	go a.hub.Run()
+	if a.config.VoteFraudScanIntervalSec > 0 {
+		go a.service.RunVoteFraudScoring(a.workersCtx, time.Duration(a.config.VoteFraudScanIntervalSec)*time.Second)
+	}
+	if a.config.BracketTickIntervalSec > 0 {
+		go a.service.RunBracketScheduler(a.workersCtx, time.Duration(a.config.BracketTickIntervalSec)*time.Second)
+	}
+	if a.config.AccountDeletionIntervalSec > 0 {
+		go a.service.RunAccountDeletionWorker(a.workersCtx, time.Duration(a.config.AccountDeletionIntervalSec)*time.Second)
+	}
+	if a.config.EmailOutboxIntervalSec > 0 {
+		go a.service.RunEmailOutboxWorker(a.workersCtx, time.Duration(a.config.EmailOutboxIntervalSec)*time.Second)
+	}
+	if a.config.TelegramBotToken != "" {
+		if a.config.TelegramPollingEnabled {
+			go a.service.RunTelegramBot(a.workersCtx)
+		}
+		if a.config.TelegramOutboxIntervalSec > 0 {
+			go a.service.RunTelegramOutboxWorker(a.workersCtx, time.Duration(a.config.TelegramOutboxIntervalSec)*time.Second)
+		}
+	}
+	if a.config.WebhookDeliveryIntervalSec > 0 {
+		go a.service.RunWebhookDeliveryWorker(a.workersCtx, time.Duration(a.config.WebhookDeliveryIntervalSec)*time.Second)
+	}
 	fmt.Println("start server on", a.config.Addr)
 	return a.server.ListenAndServe()
 }
+
+// Close останавливает фоновые задачи и HTTP сервер
+func (a *App) Close() error {
+	a.stopWorkers()
+	return a.server.Close()
+}
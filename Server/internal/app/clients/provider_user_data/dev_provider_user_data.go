@@ -0,0 +1,66 @@
+package provideruserdata
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"toppet/server/internal/model"
+)
+
+// DevProviderName - ключ dev провайдера в MapProviderOauthConf и в user_auth_providers.
+const DevProviderName = "dev"
+
+// DevProviderUserData возвращает профиль, закодированный прямо в authorization code,
+// без обращения к внешнему OAuth серверу. Используется только локально и в тестах.
+type DevProviderUserData struct{}
+
+type devCode struct {
+	UID  string `json:"uid"`
+	Name string `json:"name"`
+}
+
+func NewDevProviderUserData() *DevProviderUserData {
+	return &DevProviderUserData{}
+}
+
+// EncodeDevCode кодирует выбранный ID и имя пользователя в authorization code dev провайдера.
+func EncodeDevCode(uid, name string) string {
+	raw, _ := json.Marshal(devCode{UID: uid, Name: name})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodeDevCode разбирает authorization code, созданный EncodeDevCode.
+func DecodeDevCode(code string) (uid string, name string, err error) {
+	raw, err := base64.RawURLEncoding.DecodeString(code)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid dev code: %w", err)
+	}
+	var c devCode
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return "", "", fmt.Errorf("invalid dev code: %w", err)
+	}
+	c.UID = strings.TrimSpace(c.UID)
+	if c.UID == "" {
+		return "", "", fmt.Errorf("invalid dev code: uid is required")
+	}
+	return c.UID, strings.TrimSpace(c.Name), nil
+}
+
+func (p *DevProviderUserData) GetUserData(ctx context.Context, authorizationCode string, codeVerifier string) (*model.UserProfileFromProvider, error) {
+	uid, name, err := DecodeDevCode(authorizationCode)
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		name = "Dev " + uid
+	}
+
+	return &model.UserProfileFromProvider{
+		ProviderID:   uid,
+		Name:         name,
+		ProviderName: DevProviderName,
+	}, nil
+}
@@ -0,0 +1,32 @@
+package provideruserdata
+
+import (
+	"context"
+	"testing"
+)
+
+func TestDevProviderUserData_GetUserData(t *testing.T) {
+	p := NewDevProviderUserData()
+
+	profile, err := p.GetUserData(context.Background(), EncodeDevCode("alice", "Alice"), "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if profile.ProviderID != "alice" || profile.Name != "Alice" || profile.ProviderName != DevProviderName {
+		t.Errorf("unexpected profile: %+v", profile)
+	}
+
+	profile, err = p.GetUserData(context.Background(), EncodeDevCode("42", ""), "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if profile.Name != "Dev 42" {
+		t.Errorf("expected default name, got %q", profile.Name)
+	}
+
+	for _, code := range []string{"", "not-base64!", EncodeDevCode("  ", "Nobody")} {
+		if _, err := p.GetUserData(context.Background(), code, ""); err == nil {
+			t.Errorf("expected error for code %q", code)
+		}
+	}
+}
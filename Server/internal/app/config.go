@@ -5,9 +5,11 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 
 	authinterface "toppet/server/internal/app/authinterface"
 	appconfig "toppet/server/internal/app/config"
+	"toppet/server/internal/app/ratelimit"
 )
 
 type Config struct {
@@ -35,6 +37,84 @@ type Config struct {
 	BaseURL string
 	// Path to built SPA index.html for meta-injected HTML (optional; when set, GET /contests/* return HTML with og/twitter meta)
 	SPAIndexPath string
+
+	// Production is true when APP_ENV=production; dev-only features are refused
+	Production bool
+	// AuthDevEnabled enables the dev login provider (AUTH_DEV_ENABLED=true)
+	AuthDevEnabled bool
+
+	// AuthQueryTokenEnabled allows ?accessToken= on regular API routes (AUTH_QUERY_TOKEN_ENABLED, off by default)
+	AuthQueryTokenEnabled bool
+	// WSQueryTokenEnabled allows legacy ?accessToken= on the WebSocket endpoint instead of ?ticket=
+	WSQueryTokenEnabled bool
+
+	// TrustProxyHeaders takes client IP from X-Real-IP / X-Forwarded-For (server behind nginx)
+	TrustProxyHeaders bool
+
+	// WebSocket limits (0 = unlimited)
+	WSMaxConnectionsPerUser     int
+	WSMaxConnectionsPerIP       int
+	WSMaxSubscriptionsPerClient int
+
+	// Rate limiting (token buckets per user / IP)
+	RateLimitEnabled bool
+	// RateLimitStore: "memory" (per process) or "postgres" (shared between replicas)
+	RateLimitStore string
+	// RateLimitPolicies overrides default policies: "comment=10/m:5,vote=30/m"
+	RateLimitPolicies string
+
+	// Vote fraud detection
+	// VoteIPHashSecret HMAC key for voter IP hashes (defaults to STORE_SECRET)
+	VoteIPHashSecret string
+	// VoteFraudScanIntervalSec how often votes are rescored (0 = scoring job disabled)
+	VoteFraudScanIntervalSec int
+	// VoteFraudNewAccountHours accounts younger than this are treated as new
+	VoteFraudNewAccountHours int
+	// VoteFraudFlagScore votes with this score or higher are listed for review
+	VoteFraudFlagScore int
+	// BracketTickIntervalSec how often bracket rounds are opened and decided (0 = scheduler disabled)
+	BracketTickIntervalSec int
+	// AccountDeletionIntervalSec how often queued account deletions are processed (0 = worker disabled)
+	AccountDeletionIntervalSec int
+
+	// Mailer: "log" (письма в лог / MAIL_LOG_DIR) или "smtp"
+	MailBackend  string
+	MailFrom     string
+	MailLogDir   string
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUser     string
+	SMTPPassword string
+
+	// Email magic-link login; disabled when EMAIL_LOGIN_SECRET is empty
+	APIRoot                   string
+	EmailLoginSecret          string
+	EmailLoginTTLSec          int
+	EmailLoginRateLimit       int
+	EmailLoginRateLimitWindow int
+
+	// Email notifications about contest events; disabled when EMAIL_NOTIFICATIONS_SECRET is empty
+	// EmailNotificationsSecret HMAC key for one-click unsubscribe links
+	EmailNotificationsSecret string
+	// EmailOutboxIntervalSec how often the email outbox is flushed (0 = worker disabled)
+	EmailOutboxIntervalSec int
+
+	// Telegram bot; disabled when TELEGRAM_BOT_TOKEN is empty
+	TelegramBotToken    string
+	TelegramBotUsername string
+	// TelegramChannelID channel for contest announcements (@username or numeric ID)
+	TelegramChannelID string
+	// TelegramAPIURL Bot API base URL (a fake server can be used locally)
+	TelegramAPIURL string
+	// TelegramPollingEnabled receive bot commands via getUpdates; only one replica may poll
+	TelegramPollingEnabled bool
+	// TelegramOutboxIntervalSec how often the bot message outbox is flushed (0 = worker disabled)
+	TelegramOutboxIntervalSec int
+
+	// WebhookDeliveryIntervalSec how often contest webhook deliveries are sent (0 = worker disabled)
+	WebhookDeliveryIntervalSec int
+	// WebhookAllowPrivateNetworks allow http:// webhook URLs and private/loopback addresses (local development only)
+	WebhookAllowPrivateNetworks bool
 }
 
 func LoadConfigFromEnv() Config {
@@ -58,6 +138,56 @@ func LoadConfigFromEnv() Config {
 		// Comma-separated
 		cfg.CorsAllowedOrigins = splitComma(envOr("CORS_ALLOWED_ORIGINS", "http://localhost:3000"))
 
+	cfg.Production = appconfig.IsProduction()
+	cfg.AuthDevEnabled = envOrBool("AUTH_DEV_ENABLED", false)
+
+	cfg.AuthQueryTokenEnabled = envOrBool("AUTH_QUERY_TOKEN_ENABLED", false)
+	cfg.WSQueryTokenEnabled = envOrBool("WS_QUERY_TOKEN_ENABLED", true)
+
+	cfg.TrustProxyHeaders = envOrBool("TRUST_PROXY_HEADERS", false)
+
+	cfg.WSMaxConnectionsPerUser = envOrInt("WS_MAX_CONNECTIONS_PER_USER", 10)
+	cfg.WSMaxConnectionsPerIP = envOrInt("WS_MAX_CONNECTIONS_PER_IP", 50)
+	cfg.WSMaxSubscriptionsPerClient = envOrInt("WS_MAX_SUBSCRIPTIONS_PER_CLIENT", 20)
+
+	cfg.RateLimitEnabled = envOrBool("RATE_LIMIT_ENABLED", true)
+	cfg.RateLimitStore = envOr("RATE_LIMIT_STORE", "memory")
+	cfg.RateLimitPolicies = envOr("RATE_LIMIT_POLICIES", "")
+
+	cfg.VoteIPHashSecret = envOr("VOTE_IP_HASH_SECRET", cfg.StoreSecret)
+	cfg.VoteFraudScanIntervalSec = envOrInt("VOTE_FRAUD_SCAN_INTERVAL_SEC", 300)
+	cfg.VoteFraudNewAccountHours = envOrInt("VOTE_FRAUD_NEW_ACCOUNT_HOURS", 72)
+	cfg.VoteFraudFlagScore = envOrInt("VOTE_FRAUD_FLAG_SCORE", 50)
+	cfg.BracketTickIntervalSec = envOrInt("BRACKET_TICK_INTERVAL_SEC", 30)
+	cfg.AccountDeletionIntervalSec = envOrInt("ACCOUNT_DELETION_INTERVAL_SEC", 30)
+
+	cfg.MailBackend = envOr("MAIL_BACKEND", "log")
+	cfg.MailFrom = envOr("MAIL_FROM", "TopPet <noreply@top-pet.ru>")
+	cfg.MailLogDir = envOr("MAIL_LOG_DIR", "")
+	cfg.SMTPHost = envOr("SMTP_HOST", "")
+	cfg.SMTPPort = envOrInt("SMTP_PORT", 587)
+	cfg.SMTPUser = envOr("SMTP_USER", "")
+	cfg.SMTPPassword = envOr("SMTP_PASSWORD", "")
+
+	cfg.APIRoot = envOr("API_ROOT", "http://localhost:8080")
+	cfg.EmailLoginSecret = envOr("EMAIL_LOGIN_SECRET", "")
+	cfg.EmailLoginTTLSec = envOrInt("EMAIL_LOGIN_TTL_SEC", 15*60)
+	cfg.EmailLoginRateLimit = envOrInt("EMAIL_LOGIN_RATE_LIMIT", 5)
+	cfg.EmailLoginRateLimitWindow = envOrInt("EMAIL_LOGIN_RATE_LIMIT_WINDOW_SEC", 3600)
+
+	cfg.EmailNotificationsSecret = envOr("EMAIL_NOTIFICATIONS_SECRET", "")
+	cfg.EmailOutboxIntervalSec = envOrInt("EMAIL_OUTBOX_INTERVAL_SEC", 10)
+
+	cfg.TelegramBotToken = envOr("TELEGRAM_BOT_TOKEN", "")
+	cfg.TelegramBotUsername = strings.TrimPrefix(envOr("TELEGRAM_BOT_USERNAME", ""), "@")
+	cfg.TelegramChannelID = envOr("TELEGRAM_CHANNEL_ID", "")
+	cfg.TelegramAPIURL = envOr("TELEGRAM_API_URL", "https://api.telegram.org")
+	cfg.TelegramPollingEnabled = envOrBool("TELEGRAM_POLLING_ENABLED", true)
+	cfg.TelegramOutboxIntervalSec = envOrInt("TELEGRAM_OUTBOX_INTERVAL_SEC", 5)
+
+	cfg.WebhookDeliveryIntervalSec = envOrInt("WEBHOOK_DELIVERY_INTERVAL_SEC", 5)
+	cfg.WebhookAllowPrivateNetworks = envOrBool("WEBHOOK_ALLOW_PRIVATE_NETWORKS", false)
+
 	cfg.BaseURL = envOr("BASE_URL", "https://top-pet.ru")
 	cfg.SPAIndexPath = envOr("SPA_INDEX_PATH", "")
 	if cfg.SPAIndexPath == "" {
@@ -193,6 +323,62 @@ func ValidateConfig(cfg Config) error {
 		return fmt.Errorf("REFRESH_TOKEN_TTL_SEC must be positive")
 	}
 
+	if cfg.WSMaxConnectionsPerUser < 0 || cfg.WSMaxConnectionsPerIP < 0 || cfg.WSMaxSubscriptionsPerClient < 0 {
+		return fmt.Errorf("WS_MAX_* limits must not be negative")
+	}
+
+	if cfg.RateLimitStore != "memory" && cfg.RateLimitStore != "postgres" {
+		return fmt.Errorf("RATE_LIMIT_STORE must be \"memory\" or \"postgres\"")
+	}
+
+	if _, err := ratelimit.ParsePolicies(cfg.RateLimitPolicies); err != nil {
+		return fmt.Errorf("RATE_LIMIT_POLICIES: %w", err)
+	}
+
+	if cfg.VoteFraudScanIntervalSec < 0 || cfg.VoteFraudNewAccountHours < 0 || cfg.VoteFraudFlagScore < 0 {
+		return fmt.Errorf("VOTE_FRAUD_* settings must not be negative")
+	}
+
+	if cfg.BracketTickIntervalSec < 0 {
+		return fmt.Errorf("BRACKET_TICK_INTERVAL_SEC must not be negative")
+	}
+
+	if cfg.AccountDeletionIntervalSec < 0 {
+		return fmt.Errorf("ACCOUNT_DELETION_INTERVAL_SEC must not be negative")
+	}
+
+	if cfg.EmailOutboxIntervalSec < 0 {
+		return fmt.Errorf("EMAIL_OUTBOX_INTERVAL_SEC must not be negative")
+	}
+
+	if cfg.TelegramBotToken != "" && cfg.TelegramBotUsername == "" {
+		return fmt.Errorf("TELEGRAM_BOT_USERNAME is required when TELEGRAM_BOT_TOKEN is set")
+	}
+
+	if cfg.TelegramOutboxIntervalSec < 0 {
+		return fmt.Errorf("TELEGRAM_OUTBOX_INTERVAL_SEC must not be negative")
+	}
+
+	if cfg.WebhookDeliveryIntervalSec < 0 {
+		return fmt.Errorf("WEBHOOK_DELIVERY_INTERVAL_SEC must not be negative")
+	}
+
+	if cfg.MailBackend != "log" && cfg.MailBackend != "smtp" {
+		return fmt.Errorf("MAIL_BACKEND must be \"log\" or \"smtp\"")
+	}
+
+	if cfg.MailBackend == "smtp" && cfg.SMTPHost == "" {
+		return fmt.Errorf("SMTP_HOST is required when MAIL_BACKEND=smtp")
+	}
+
+	if cfg.EmailLoginSecret != "" && cfg.EmailLoginTTLSec <= 0 {
+		return fmt.Errorf("EMAIL_LOGIN_TTL_SEC must be positive")
+	}
+
+	if cfg.Production && cfg.AuthDevEnabled {
+		return fmt.Errorf("AUTH_DEV_ENABLED is not allowed when APP_ENV=production")
+	}
+
 	return nil
 }
 
@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
 
 	authinterface "toppet/server/internal/app/authinterface"
 	providerUserData "toppet/server/internal/app/clients/provider_user_data"
@@ -127,5 +128,30 @@ func LoadOAuthProviders() (authinterface.MapProviderOauthConf, error) {
 		}
 	}
 
+	// Dev provider: вход под выбранным ID/именем без внешнего OAuth (только локально и в тестах)
+	if devEnabled, _ := strconv.ParseBool(os.Getenv("AUTH_DEV_ENABLED")); devEnabled {
+		if IsProduction() {
+			return nil, fmt.Errorf("AUTH_DEV_ENABLED must not be set when APP_ENV=production")
+		}
+
+		providers[providerUserData.DevProviderName] = &authinterface.ProviderOauthConf{
+			Oauth2Config: &oauth2.Config{
+				ClientID:    providerUserData.DevProviderName,
+				RedirectURL: fmt.Sprintf("%s/api/auth/callback?provider=%s", apiRoot, providerUserData.DevProviderName),
+				Endpoint: oauth2.Endpoint{
+					AuthURL: fmt.Sprintf("%s/api/auth/dev/authorize", apiRoot),
+				},
+			},
+			IconSVG:          icons.GetProviderIcon(providerUserData.DevProviderName),
+			DisplayName:      "Dev",
+			ProviderUserData: providerUserData.NewDevProviderUserData(),
+		}
+	}
+
 	return providers, nil
 }
+
+// IsProduction сообщает, запущен ли сервер в продакшен окружении (APP_ENV=production)
+func IsProduction() bool {
+	return os.Getenv("APP_ENV") == "production"
+}
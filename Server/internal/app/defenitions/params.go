@@ -5,6 +5,7 @@ type ctxKey string
 
 const (
 	UserID                    ctxKey = "user_id"
+	Roles                     ctxKey = "roles"
 	SessionAuthenticationName        = "authentication"
 	Token                             = "token"
 )
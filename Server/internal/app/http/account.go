@@ -0,0 +1,141 @@
+package http
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"toppet/server/internal/app/defenitions"
+	"toppet/server/internal/app/uhttp"
+	"toppet/server/internal/model"
+)
+
+type (
+	serviceAccount interface {
+		ExportUserData(ctx context.Context, userID model.UserID) (*model.UserExport, error)
+		RequestAccountDeletion(ctx context.Context, userID model.UserID) (*model.AccountDeletionJob, error)
+		GetAccountDeletionJob(ctx context.Context, jobID string) (*model.AccountDeletionJob, error)
+	}
+
+	// AccountHandler выгрузка данных и удаление аккаунта: /api/auth/me/export, DELETE /api/auth/me,
+	// статус удаления /api/account-deletions/{jobId}
+	AccountHandler struct {
+		name    string
+		service serviceAccount
+	}
+)
+
+func NewAccountHandler(name string, service serviceAccount) *AccountHandler {
+	return &AccountHandler{name: name, service: service}
+}
+
+// Export отдает архив с данными пользователя; ?format=json - один JSON файл вместо ZIP
+func (h *AccountHandler) Export(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(defenitions.UserID).(model.UserID)
+
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "zip" && format != "json" {
+		uhttp.HandleError(w, uhttp.NewBadRequestError("format must be zip or json", nil))
+		return
+	}
+
+	export, err := h.service.ExportUserData(r.Context(), userID)
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	filename := fmt.Sprintf("toppet-export-%d", userID)
+	if format == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`.json"`)
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(export); err != nil {
+			fmt.Printf("Failed to write export: %v\n", err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`.zip"`)
+	if err := writeUserExportZip(w, export); err != nil {
+		// Заголовки уже отправлены, клиент получит оборванный архив
+		fmt.Printf("Failed to write export archive: %v\n", err)
+	}
+}
+
+// RequestDeletion ставит удаление аккаунта в очередь и возвращает задание для опроса статуса
+func (h *AccountHandler) RequestDeletion(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(defenitions.UserID).(model.UserID)
+
+	job, err := h.service.RequestAccountDeletion(r.Context(), userID)
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	if err := uhttp.SendJSON(w, http.StatusAccepted, uhttp.SuccessResponse{Data: job}); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
+
+// GetDeletion статус удаления аккаунта; доступен без авторизации по ID задания
+func (h *AccountHandler) GetDeletion(w http.ResponseWriter, r *http.Request) {
+	jobID := r.PathValue("jobId")
+	if err := uhttp.ValidateUUID(jobID); err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	job, err := h.service.GetAccountDeletionJob(r.Context(), jobID)
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	if err := uhttp.SendSuccess(w, job); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
+
+// writeUserExportZip раскладывает выгрузку по JSON файлам архива
+func writeUserExportZip(w io.Writer, export *model.UserExport) error {
+	files := []struct {
+		name string
+		data any
+	}{
+		{"profile.json", map[string]any{
+			"exported_at": export.ExportedAt,
+			"user":        export.User,
+			"providers":   export.Providers,
+			"privacy":     export.Privacy,
+		}},
+		{"contests.json", export.Contests},
+		{"participants.json", export.Participants},
+		{"pets.json", export.Pets},
+		{"media.json", export.Media},
+		{"votes.json", export.Votes},
+		{"comments.json", export.Comments},
+		{"chat_messages.json", export.ChatMessages},
+	}
+
+	zw := zip.NewWriter(w)
+	for _, file := range files {
+		fw, err := zw.Create(file.name)
+		if err != nil {
+			return err
+		}
+		enc := json.NewEncoder(fw)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(file.data); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
@@ -0,0 +1,51 @@
+package http
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"toppet/server/internal/model"
+)
+
+func TestWriteUserExportZip(t *testing.T) {
+	export := &model.UserExport{
+		ExportedAt: time.Now(),
+		User:       &model.User{ID: 10, Name: "Анна"},
+		Comments:   []*model.Comment{{ID: "c1", UserID: 10, Text: "Красавица"}},
+	}
+
+	var buf bytes.Buffer
+	if err := writeUserExportZip(&buf, export); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("Invalid archive: %v", err)
+	}
+	files := map[string]*zip.File{}
+	for _, file := range zr.File {
+		files[file.Name] = file
+	}
+	for _, name := range []string{"profile.json", "contests.json", "participants.json", "pets.json", "media.json", "votes.json", "comments.json", "chat_messages.json"} {
+		if files[name] == nil {
+			t.Errorf("Expected %s in archive", name)
+		}
+	}
+
+	rc, err := files["comments.json"].Open()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer rc.Close()
+	var comments []*model.Comment
+	if err := json.NewDecoder(rc).Decode(&comments); err != nil {
+		t.Fatalf("Invalid comments.json: %v", err)
+	}
+	if len(comments) != 1 || comments[0].Text != "Красавица" {
+		t.Errorf("Expected exported comment, got %+v", comments)
+	}
+}
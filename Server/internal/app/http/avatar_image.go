@@ -0,0 +1,132 @@
+package http
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+)
+
+const (
+	avatarSize      = 512
+	avatarThumbSize = 128
+	// minAvatarCropSize меньший квадрат при увеличении до avatarSize слишком размывается
+	minAvatarCropSize = 64
+	// maxAvatarPixels защищает от изображений, которые при декодировании займут гигабайты памяти
+	maxAvatarPixels   = 40_000_000
+	avatarJPEGQuality = 90
+)
+
+var (
+	errAvatarFormat    = errors.New("avatar must be a JPEG, PNG or GIF image")
+	errAvatarTooLarge  = errors.New("avatar image resolution is too large")
+	errAvatarCropRange = errors.New("avatar crop is outside the image")
+	errAvatarTooSmall  = errors.New("avatar crop must be at least 64x64 pixels")
+)
+
+// avatarCrop квадрат для аватара в пикселях исходного изображения; Size == 0 - наибольший квадрат по центру
+type avatarCrop struct {
+	X, Y, Size int
+}
+
+// decodeAvatarImage декодирует изображение, предварительно проверив разрешение по заголовку
+func decodeAvatarImage(data []byte) (image.Image, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, errAvatarFormat
+	}
+	if cfg.Width*cfg.Height > maxAvatarPixels {
+		return nil, errAvatarTooLarge
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, errAvatarFormat
+	}
+	return img, nil
+}
+
+// avatarCropRect возвращает квадрат обрезки внутри границ изображения
+func avatarCropRect(bounds image.Rectangle, crop avatarCrop) (image.Rectangle, error) {
+	if crop.Size == 0 {
+		side := min(bounds.Dx(), bounds.Dy())
+		if side < minAvatarCropSize {
+			return image.Rectangle{}, errAvatarTooSmall
+		}
+		x := bounds.Min.X + (bounds.Dx()-side)/2
+		y := bounds.Min.Y + (bounds.Dy()-side)/2
+		return image.Rect(x, y, x+side, y+side), nil
+	}
+	if crop.Size < minAvatarCropSize {
+		return image.Rectangle{}, errAvatarTooSmall
+	}
+	if crop.X < 0 || crop.Y < 0 {
+		return image.Rectangle{}, errAvatarCropRange
+	}
+	rect := image.Rect(crop.X, crop.Y, crop.X+crop.Size, crop.Y+crop.Size).Add(bounds.Min)
+	if !rect.In(bounds) {
+		return image.Rectangle{}, errAvatarCropRange
+	}
+	return rect, nil
+}
+
+// resizeSquare масштабирует квадрат rect изображения до size x size усреднением по площади.
+// Прозрачные области заливаются белым: JPEG не хранит альфа-канал.
+func resizeSquare(src image.Image, rect image.Rectangle, size int) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	side := rect.Dx()
+	for dy := 0; dy < size; dy++ {
+		y0 := rect.Min.Y + dy*side/size
+		y1 := max(rect.Min.Y+(dy+1)*side/size, y0+1)
+		for dx := 0; dx < size; dx++ {
+			x0 := rect.Min.X + dx*side/size
+			x1 := max(rect.Min.X+(dx+1)*side/size, x0+1)
+
+			var r, g, b, a, n uint64
+			for y := y0; y < y1; y++ {
+				for x := x0; x < x1; x++ {
+					pr, pg, pb, pa := src.At(x, y).RGBA()
+					r, g, b, a = r+uint64(pr), g+uint64(pg), b+uint64(pb), a+uint64(pa)
+					n++
+				}
+			}
+			// Цвета RGBA() уже умножены на альфу: белый фон добавляется долей (1 - alpha)
+			white := 0xffff*n - a
+			i := dst.PixOffset(dx, dy)
+			dst.Pix[i+0] = uint8((r + white) / n >> 8)
+			dst.Pix[i+1] = uint8((g + white) / n >> 8)
+			dst.Pix[i+2] = uint8((b + white) / n >> 8)
+			dst.Pix[i+3] = 0xff
+		}
+	}
+	return dst
+}
+
+// avatarVariants обрезает изображение и готовит JPEG аватара и его уменьшенной копии
+func avatarVariants(img image.Image, crop avatarCrop) (full, thumb []byte, err error) {
+	rect, err := avatarCropRect(img.Bounds(), crop)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fullImg := resizeSquare(img, rect, avatarSize)
+	// Миниатюра считается из уже уменьшенного аватара, а не из исходника
+	thumbImg := resizeSquare(fullImg, fullImg.Bounds(), avatarThumbSize)
+
+	if full, err = encodeAvatarJPEG(fullImg); err != nil {
+		return nil, nil, err
+	}
+	if thumb, err = encodeAvatarJPEG(thumbImg); err != nil {
+		return nil, nil, err
+	}
+	return full, thumb, nil
+}
+
+func encodeAvatarJPEG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: avatarJPEGQuality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,102 @@
+package http
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func TestAvatarCropRect(t *testing.T) {
+	bounds := image.Rect(0, 0, 300, 200)
+
+	tests := []struct {
+		name    string
+		crop    avatarCrop
+		want    image.Rectangle
+		wantErr error
+	}{
+		{name: "centered square", crop: avatarCrop{}, want: image.Rect(50, 0, 250, 200)},
+		{name: "explicit crop", crop: avatarCrop{X: 10, Y: 20, Size: 100}, want: image.Rect(10, 20, 110, 120)},
+		{name: "outside image", crop: avatarCrop{X: 250, Y: 0, Size: 100}, wantErr: errAvatarCropRange},
+		{name: "negative offset", crop: avatarCrop{X: -1, Y: 0, Size: 100}, wantErr: errAvatarCropRange},
+		{name: "too small", crop: avatarCrop{X: 0, Y: 0, Size: 32}, wantErr: errAvatarTooSmall},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := avatarCropRect(bounds, tt.crop)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	if _, err := avatarCropRect(image.Rect(0, 0, 40, 400), avatarCrop{}); !errors.Is(err, errAvatarTooSmall) {
+		t.Errorf("Expected too small for narrow image, got %v", err)
+	}
+}
+
+func TestResizeSquare(t *testing.T) {
+	// Левая половина красная, правая прозрачная
+	src := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 2; x++ {
+			src.Set(x, y, color.NRGBA{R: 255, A: 255})
+		}
+	}
+
+	dst := resizeSquare(src, src.Bounds(), 2)
+	if got := dst.RGBAAt(0, 0); got != (color.RGBA{R: 255, A: 255}) {
+		t.Errorf("Expected red pixel, got %v", got)
+	}
+	if got := dst.RGBAAt(1, 1); got != (color.RGBA{R: 255, G: 255, B: 255, A: 255}) {
+		t.Errorf("Expected transparent area filled with white, got %v", got)
+	}
+}
+
+func TestAvatarVariants(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 640, 480))
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, src); err != nil {
+		t.Fatalf("encode png: %v", err)
+	}
+
+	img, err := decodeAvatarImage(buf.Bytes())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	full, thumb, err := avatarVariants(img, avatarCrop{X: 100, Y: 50, Size: 300})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, tc := range []struct {
+		data []byte
+		size int
+	}{{full, avatarSize}, {thumb, avatarThumbSize}} {
+		cfg, err := jpeg.DecodeConfig(bytes.NewReader(tc.data))
+		if err != nil {
+			t.Fatalf("Expected JPEG output: %v", err)
+		}
+		if cfg.Width != tc.size || cfg.Height != tc.size {
+			t.Errorf("Expected %dx%d, got %dx%d", tc.size, tc.size, cfg.Width, cfg.Height)
+		}
+	}
+
+	if _, err := decodeAvatarImage([]byte("not an image")); !errors.Is(err, errAvatarFormat) {
+		t.Errorf("Expected format error, got %v", err)
+	}
+}
@@ -0,0 +1,142 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"toppet/server/internal/app/defenitions"
+	"toppet/server/internal/app/uhttp"
+	"toppet/server/internal/model"
+)
+
+type (
+	serviceBracket interface {
+		GetBracket(ctx context.Context, contestID model.ContestID, userID *model.UserID) (*model.Bracket, error)
+		ConfigureBracket(ctx context.Context, contestID model.ContestID, actorID model.UserID, seeding model.BracketSeeding, roundDurationMinutes int) (*model.Bracket, error)
+		StartBracket(ctx context.Context, contestID model.ContestID, actorID model.UserID, startsAt *time.Time) (*model.Bracket, error)
+		VoteInMatchup(ctx context.Context, contestID model.ContestID, matchupID string, userID model.UserID, participantID model.ParticipantID) (*model.BracketMatchup, error)
+	}
+
+	// BracketHandler турнирная сетка конкурса: /api/contests/{contestId}/bracket
+	BracketHandler struct {
+		name        string
+		service     serviceBracket
+		authService serviceOptionalAuth
+	}
+
+	configureBracketRequest struct {
+		Seeding              model.BracketSeeding `json:"seeding"`
+		RoundDurationMinutes int                  `json:"round_duration_minutes"`
+	}
+
+	startBracketRequest struct {
+		StartsAt *time.Time `json:"starts_at"`
+	}
+
+	matchupVoteRequest struct {
+		ParticipantID model.ParticipantID `json:"participant_id"`
+	}
+)
+
+func NewBracketHandler(name string, service serviceBracket) *BracketHandler {
+	var authService serviceOptionalAuth
+	if svc, ok := service.(serviceOptionalAuth); ok {
+		authService = svc
+	}
+	return &BracketHandler{name: name, service: service, authService: authService}
+}
+
+func (h *BracketHandler) GetBracket(w http.ResponseWriter, r *http.Request) {
+	contestID := model.ContestID(r.PathValue("contestId"))
+
+	// Для авторизованного пользователя отмечаем его голоса в парах
+	ctx := r.Context()
+	var userID *model.UserID
+	if claims, _ := getOptionalClaims(r, h.authService); claims != nil {
+		ctx = withOptionalClaims(ctx, claims)
+		userID = &claims.UserID
+	}
+
+	bracket, err := h.service.GetBracket(ctx, contestID, userID)
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	if err := uhttp.SendSuccess(w, bracket); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
+
+func (h *BracketHandler) ConfigureBracket(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(defenitions.UserID).(model.UserID)
+	contestID := model.ContestID(r.PathValue("contestId"))
+
+	var req configureBracketRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		uhttp.HandleError(w, uhttp.NewBadRequestError("invalid request body", err))
+		return
+	}
+
+	bracket, err := h.service.ConfigureBracket(r.Context(), contestID, userID, req.Seeding, req.RoundDurationMinutes)
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	if err := uhttp.SendSuccess(w, bracket); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
+
+func (h *BracketHandler) StartBracket(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(defenitions.UserID).(model.UserID)
+	contestID := model.ContestID(r.PathValue("contestId"))
+
+	// Тело необязательно: без starts_at первый раунд начинается сразу
+	var req startBracketRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		uhttp.HandleError(w, uhttp.NewBadRequestError("invalid request body", err))
+		return
+	}
+
+	bracket, err := h.service.StartBracket(r.Context(), contestID, userID, req.StartsAt)
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	if err := uhttp.SendSuccess(w, bracket); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
+
+func (h *BracketHandler) VoteInMatchup(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(defenitions.UserID).(model.UserID)
+	contestID := model.ContestID(r.PathValue("contestId"))
+	matchupID := r.PathValue("matchupId")
+
+	var req matchupVoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		uhttp.HandleError(w, uhttp.NewBadRequestError("invalid request body", err))
+		return
+	}
+
+	matchup, err := h.service.VoteInMatchup(r.Context(), contestID, matchupID, userID, req.ParticipantID)
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	if err := uhttp.SendSuccess(w, matchup); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
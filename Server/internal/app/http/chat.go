@@ -16,13 +16,18 @@ type (
 	}
 
 	ChatHandler struct {
-		name    string
-		service serviceChat
+		name        string
+		service     serviceChat
+		authService serviceOptionalAuth
 	}
 )
 
 func NewChatHandler(name string, service serviceChat) *ChatHandler {
-	return &ChatHandler{name: name, service: service}
+	var authService serviceOptionalAuth
+	if svc, ok := service.(serviceOptionalAuth); ok {
+		authService = svc
+	}
+	return &ChatHandler{name: name, service: service, authService: authService}
 }
 
 func (h *ChatHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -57,7 +62,9 @@ func (h *ChatHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	log.Printf("[ChatHandler] Parameters: limit=%d, offset=%d", limit, offset)
 	log.Printf("[ChatHandler] Calling service.ListChatMessages...")
-	messages, total, err := h.service.ListChatMessages(r.Context(), contestID, limit, offset)
+	ctx := optionalClaimsContext(r, h.authService)
+
+	messages, total, err := h.service.ListChatMessages(ctx, contestID, limit, offset)
 	if err != nil {
 		log.Printf("[ChatHandler] ===== ERROR: Failed to list chat messages =====")
 		log.Printf("[ChatHandler] contestID: %s", contestID)
@@ -20,13 +20,18 @@ type (
 	}
 
 	CommentsHandler struct {
-		name    string
-		service serviceComments
+		name        string
+		service     serviceComments
+		authService serviceOptionalAuth
 	}
 )
 
 func NewCommentsHandler(name string, service serviceComments) *CommentsHandler {
-	return &CommentsHandler{name: name, service: service}
+	var authService serviceOptionalAuth
+	if svc, ok := service.(serviceOptionalAuth); ok {
+		authService = svc
+	}
+	return &CommentsHandler{name: name, service: service, authService: authService}
 }
 
 func (h *CommentsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -46,7 +51,9 @@ func (h *CommentsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 
-		comments, total, err := h.service.ListComments(r.Context(), participantID, limit, offset)
+		ctx := optionalClaimsContext(r, h.authService)
+
+		comments, total, err := h.service.ListComments(ctx, participantID, limit, offset)
 		if err != nil {
 			uhttp.HandleError(w, err)
 			return
@@ -0,0 +1,122 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"toppet/server/internal/app/defenitions"
+	"toppet/server/internal/app/uhttp"
+	"toppet/server/internal/model"
+)
+
+type (
+	serviceContestCategories interface {
+		ListContestCategories(ctx context.Context, contestID model.ContestID) ([]*model.ContestCategory, error)
+		CreateContestCategory(ctx context.Context, contestID model.ContestID, actorID model.UserID, name, description string) (*model.ContestCategory, error)
+		UpdateContestCategory(ctx context.Context, contestID model.ContestID, actorID model.UserID, categoryID model.CategoryID, name, description string) (*model.ContestCategory, error)
+		DeleteContestCategory(ctx context.Context, contestID model.ContestID, actorID model.UserID, categoryID model.CategoryID) error
+	}
+
+	// ContestCategoriesHandler номинации конкурса: /api/contests/{contestId}/categories
+	ContestCategoriesHandler struct {
+		name        string
+		service     serviceContestCategories
+		authService serviceOptionalAuth
+	}
+
+	contestCategoryRequest struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	}
+)
+
+func NewContestCategoriesHandler(name string, service serviceContestCategories) *ContestCategoriesHandler {
+	var authService serviceOptionalAuth
+	if svc, ok := service.(serviceOptionalAuth); ok {
+		authService = svc
+	}
+	return &ContestCategoriesHandler{name: name, service: service, authService: authService}
+}
+
+func (h *ContestCategoriesHandler) ListCategories(w http.ResponseWriter, r *http.Request) {
+	contestID := model.ContestID(r.PathValue("contestId"))
+
+	ctx := optionalClaimsContext(r, h.authService)
+
+	categories, err := h.service.ListContestCategories(ctx, contestID)
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	type resp struct {
+		Items []*model.ContestCategory `json:"items"`
+		Total int64                    `json:"total"`
+	}
+	if err := uhttp.SendSuccess(w, resp{Items: categories, Total: int64(len(categories))}); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
+
+func (h *ContestCategoriesHandler) CreateCategory(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(defenitions.UserID).(model.UserID)
+	contestID := model.ContestID(r.PathValue("contestId"))
+
+	var req contestCategoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		uhttp.HandleError(w, uhttp.NewBadRequestError("invalid request body", err))
+		return
+	}
+
+	category, err := h.service.CreateContestCategory(r.Context(), contestID, userID, req.Name, req.Description)
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	if err := uhttp.SendSuccess(w, category); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
+
+func (h *ContestCategoriesHandler) UpdateCategory(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(defenitions.UserID).(model.UserID)
+	contestID := model.ContestID(r.PathValue("contestId"))
+	categoryID := model.CategoryID(r.PathValue("categoryId"))
+
+	var req contestCategoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		uhttp.HandleError(w, uhttp.NewBadRequestError("invalid request body", err))
+		return
+	}
+
+	category, err := h.service.UpdateContestCategory(r.Context(), contestID, userID, categoryID, req.Name, req.Description)
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	if err := uhttp.SendSuccess(w, category); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
+
+func (h *ContestCategoriesHandler) DeleteCategory(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(defenitions.UserID).(model.UserID)
+	contestID := model.ContestID(r.PathValue("contestId"))
+	categoryID := model.CategoryID(r.PathValue("categoryId"))
+
+	if err := h.service.DeleteContestCategory(r.Context(), contestID, userID, categoryID); err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	if err := uhttp.SendSuccess(w, map[string]bool{"deleted": true}); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
@@ -3,11 +3,16 @@ package http
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log"
+	"math"
 	"net/http"
+	"net/url"
+	"strings"
 
 	"github.com/gorilla/websocket"
 	"toppet/server/internal/app/defenitions"
+	"toppet/server/internal/app/ratelimit"
 	"toppet/server/internal/app/uhttp"
 	wsapp "toppet/server/internal/app/ws"
 	"toppet/server/internal/model"
@@ -18,24 +23,62 @@ type (
 		CreateChatMessage(ctx context.Context, contestID model.ContestID, userID model.UserID, text string) (*model.ChatMessage, error)
 	}
 
-	serviceAuth interface {
+	serviceWSAuth interface {
 		Authorization(ctx context.Context, accessToken string) (*model.Claims, error)
+		ExchangeWSTicket(ctx context.Context, ticket string) (model.UserID, error)
+	}
+
+	// ContestChatWSOptions настройки WebSocket endpoint
+	ContestChatWSOptions struct {
+		// AllowQueryToken разрешает устаревший ?accessToken= для клиентов без поддержки билетов
+		AllowQueryToken bool
+		// AllowedOrigins - тот же список, что и для CORS; запросы с другим Origin отклоняются
+		AllowedOrigins []string
+		// TrustProxy - брать IP клиента из X-Real-IP/X-Forwarded-For для лимита соединений по IP
+		TrustProxy bool
+		// RateLimiter ограничивает частоту сообщений чата (политика chat_message); nil - без ограничений
+		RateLimiter *ratelimit.Limiter
 	}
 
 	ContestChatWSHandler struct {
 		name    string
 		service contestChatService
-		authService serviceAuth
+		authService serviceWSAuth
 		hub     *wsapp.Hub
+		opts     ContestChatWSOptions
+		upgrader websocket.Upgrader
 	}
 )
 
-var upgrader = websocket.Upgrader{
-	CheckOrigin: func(r *http.Request) bool { return true },
+func NewContestChatWSHandler(name string, svc contestChatService, authSvc serviceWSAuth, hub *wsapp.Hub, opts ContestChatWSOptions) *ContestChatWSHandler {
+	h := &ContestChatWSHandler{name: name, service: svc, authService: authSvc, hub: hub, opts: opts}
+	h.upgrader = websocket.Upgrader{CheckOrigin: newOriginChecker(opts.AllowedOrigins)}
+	return h
 }
 
-func NewContestChatWSHandler(name string, svc contestChatService, authSvc serviceAuth, hub *wsapp.Hub) *ContestChatWSHandler {
-	return &ContestChatWSHandler{name: name, service: svc, authService: authSvc, hub: hub}
+// newOriginChecker разрешает запросы без Origin (мобильные и серверные клиенты),
+// same-origin запросы и origins из списка; остальное - защита от cross-site WebSocket hijacking.
+func newOriginChecker(allowedOrigins []string) func(r *http.Request) bool {
+	allowed := make(map[string]struct{}, len(allowedOrigins))
+	for _, origin := range allowedOrigins {
+		allowed[strings.ToLower(strings.TrimRight(origin, "/"))] = struct{}{}
+	}
+
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			return true
+		}
+		if _, ok := allowed[strings.ToLower(origin)]; ok {
+			return true
+		}
+		u, err := url.Parse(origin)
+		if err == nil && strings.EqualFold(u.Host, r.Host) {
+			return true
+		}
+		log.Printf("[WS] Rejecting connection from origin %q", origin)
+		return false
+	}
 }
 
 type wsIncomingMessage struct {
@@ -54,9 +97,22 @@ func (h *ContestChatWSHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 	if userIDVal != nil {
 		userID = userIDVal.(model.UserID)
 		log.Printf("[WS] UserID %d extracted from context", userID)
+	} else if ticket := r.URL.Query().Get("ticket"); ticket != "" {
+		// Preferred: single-use ticket from POST /api/ws/ticket
+		var err error
+		userID, err = h.authService.ExchangeWSTicket(r.Context(), ticket)
+		if err != nil {
+			log.Printf("[WS] ERROR: Invalid ticket: %v", err)
+			uhttp.HandleError(w, uhttp.NewUnauthorizedError("invalid ticket", err))
+			return
+		}
+		log.Printf("[WS] Ticket exchanged, UserID: %d", userID)
 	} else {
-		// Try to get accessToken from query params and validate it
-		accessToken := r.URL.Query().Get("accessToken")
+		// Legacy: accessToken in query params (only when allowed) or Authorization header
+		accessToken := ""
+		if h.opts.AllowQueryToken {
+			accessToken = r.URL.Query().Get("accessToken")
+		}
 		if accessToken == "" {
 			// Try Authorization header
 			authHeader := r.Header.Get("Authorization")
@@ -65,12 +121,12 @@ func (h *ContestChatWSHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 				log.Printf("[WS] Access token found in Authorization header")
 			}
 		} else {
-			log.Printf("[WS] Access token found in query params")
+			log.Printf("[WS] Access token found in query params (deprecated, use ticket)")
 		}
 		
 		if accessToken == "" {
-			log.Printf("[WS] ERROR: No access token provided, rejecting connection")
-			uhttp.HandleError(w, uhttp.NewUnauthorizedError("access token is required", nil))
+			log.Printf("[WS] ERROR: No ticket or access token provided, rejecting connection")
+			uhttp.HandleError(w, uhttp.NewUnauthorizedError("ticket is required", nil))
 			return
 		}
 		
@@ -87,9 +143,16 @@ func (h *ContestChatWSHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 		log.Printf("[WS] Access token validated, UserID: %d", userID)
 	}
 
+	clientIP := uhttp.ClientIP(r, h.opts.TrustProxy)
+	if err := h.hub.AcquireConnection(userID, clientIP); err != nil {
+		uhttp.HandleError(w, uhttp.NewTooManyRequestsError("too many websocket connections", err))
+		return
+	}
+
 	log.Printf("[WS] Upgrading HTTP connection to WebSocket for user %d...", userID)
-	conn, err := upgrader.Upgrade(w, r, nil)
+	conn, err := h.upgrader.Upgrade(w, r, nil)
 	if err != nil {
+		h.hub.ReleaseConnection(userID, clientIP)
 		log.Printf("[WS] ERROR: Failed to upgrade connection: %v", err)
 		return
 	}
@@ -98,6 +161,7 @@ func (h *ContestChatWSHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 	client := &wsapp.Client{
 		Conn:     conn,
 		UserID:   userID,
+		IP:       clientIP,
 		Contests: make(map[model.ContestID]struct{}),
 		Send:     make(chan any, 32),
 		Hub:      h.hub,
@@ -123,7 +187,9 @@ func (h *ContestChatWSHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 		case "subscribe":
 			if msg.ContestID != "" {
 				log.Printf("[WS] User %d subscribing to contest %s", userID, msg.ContestID)
-				client.Subscribe(model.ContestID(msg.ContestID))
+				if err := client.Subscribe(model.ContestID(msg.ContestID)); err != nil {
+					_ = h.hub.SendToClient(client, wsapp.ErrorPayload{Type: wsapp.MessageTypeError, Code: "too_many_subscriptions", Message: err.Error()})
+				}
 			} else {
 				log.Printf("[WS] WARNING: Subscribe message from user %d has empty contest_id", userID)
 			}
@@ -132,6 +198,17 @@ func (h *ContestChatWSHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 				log.Printf("[WS] WARNING: Message from user %d has empty contest_id or text", userID)
 				return
 			}
+			if result, err := h.opts.RateLimiter.Allow(r.Context(), ratelimit.PolicyChatMessage, fmt.Sprintf("user:%d", userID)); err != nil {
+				log.Printf("[WS] WARNING: rate limiter failed for user %d: %v", userID, err)
+			} else if !result.Allowed {
+				_ = h.hub.SendToClient(client, wsapp.ErrorPayload{
+					Type:       wsapp.MessageTypeError,
+					Code:       "rate_limited",
+					Message:    "too many messages",
+					RetryAfter: int(math.Ceil(result.RetryAfter.Seconds())),
+				})
+				return
+			}
 			log.Printf("[WS] User %d sending message to contest %s: %s", userID, msg.ContestID, msg.Text)
 			_, err := h.service.CreateChatMessage(
 				r.Context(),
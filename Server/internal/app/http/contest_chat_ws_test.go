@@ -0,0 +1,37 @@
+package http
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewOriginChecker(t *testing.T) {
+	check := newOriginChecker([]string{"http://localhost:3000", "https://top-pet.ru/"})
+
+	tests := []struct {
+		name   string
+		host   string
+		origin string
+		want   bool
+	}{
+		{name: "no origin", host: "api.top-pet.ru", origin: "", want: true},
+		{name: "allowed origin", host: "api.top-pet.ru", origin: "https://top-pet.ru", want: true},
+		{name: "allowed origin case insensitive", host: "api.top-pet.ru", origin: "HTTP://LOCALHOST:3000", want: true},
+		{name: "same origin", host: "api.top-pet.ru", origin: "https://api.top-pet.ru", want: true},
+		{name: "foreign origin", host: "api.top-pet.ru", origin: "https://evil.example", want: false},
+		{name: "lookalike origin", host: "api.top-pet.ru", origin: "https://top-pet.ru.evil.example", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/contests/1/chat/ws", nil)
+			req.Host = tt.host
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			if got := check(req); got != tt.want {
+				t.Errorf("check(%q) = %v, want %v", tt.origin, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"toppet/server/internal/app/defenitions"
+	"toppet/server/internal/app/uhttp"
+	"toppet/server/internal/model"
+)
+
+type (
+	serviceContestMembers interface {
+		ListContestMembers(ctx context.Context, contestID model.ContestID, userID model.UserID) ([]*model.ContestMember, error)
+		InviteContestMember(ctx context.Context, contestID model.ContestID, actorID, userID model.UserID, role model.ContestMemberRole) (*model.ContestMember, error)
+		AcceptContestInvite(ctx context.Context, contestID model.ContestID, userID model.UserID) (*model.ContestMember, error)
+		RemoveContestMember(ctx context.Context, contestID model.ContestID, actorID, userID model.UserID) error
+		TransferContestOwnership(ctx context.Context, contestID model.ContestID, actorID, newOwnerID model.UserID) (*model.Contest, error)
+	}
+
+	// ContestMembersHandler команда конкурса: /api/contests/{contestId}/members
+	ContestMembersHandler struct {
+		name    string
+		service serviceContestMembers
+	}
+
+	inviteContestMemberRequest struct {
+		UserID model.UserID            `json:"user_id"`
+		Role   model.ContestMemberRole `json:"role"`
+	}
+
+	transferContestOwnershipRequest struct {
+		UserID model.UserID `json:"user_id"`
+	}
+)
+
+func NewContestMembersHandler(name string, service serviceContestMembers) *ContestMembersHandler {
+	return &ContestMembersHandler{name: name, service: service}
+}
+
+func (h *ContestMembersHandler) ListMembers(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(defenitions.UserID).(model.UserID)
+	contestID := model.ContestID(r.PathValue("contestId"))
+
+	members, err := h.service.ListContestMembers(r.Context(), contestID, userID)
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	type resp struct {
+		Items []*model.ContestMember `json:"items"`
+		Total int64                  `json:"total"`
+	}
+	if err := uhttp.SendSuccess(w, resp{Items: members, Total: int64(len(members))}); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
+
+func (h *ContestMembersHandler) InviteMember(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(defenitions.UserID).(model.UserID)
+	contestID := model.ContestID(r.PathValue("contestId"))
+
+	var req inviteContestMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		uhttp.HandleError(w, uhttp.NewBadRequestError("invalid request body", err))
+		return
+	}
+	if req.UserID == 0 {
+		uhttp.HandleError(w, uhttp.NewBadRequestError("user_id is required", nil))
+		return
+	}
+
+	member, err := h.service.InviteContestMember(r.Context(), contestID, userID, req.UserID, req.Role)
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	if err := uhttp.SendSuccess(w, member); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
+
+func (h *ContestMembersHandler) AcceptInvite(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(defenitions.UserID).(model.UserID)
+	contestID := model.ContestID(r.PathValue("contestId"))
+
+	member, err := h.service.AcceptContestInvite(r.Context(), contestID, userID)
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	if err := uhttp.SendSuccess(w, member); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
+
+func (h *ContestMembersHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(defenitions.UserID).(model.UserID)
+	contestID := model.ContestID(r.PathValue("contestId"))
+
+	memberID, err := strconv.ParseInt(r.PathValue("userId"), 10, 64)
+	if err != nil {
+		uhttp.HandleError(w, uhttp.NewBadRequestError("invalid userId", err))
+		return
+	}
+
+	if err := h.service.RemoveContestMember(r.Context(), contestID, userID, model.UserID(memberID)); err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	if err := uhttp.SendSuccess(w, map[string]bool{"removed": true}); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
+
+func (h *ContestMembersHandler) TransferOwnership(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(defenitions.UserID).(model.UserID)
+	contestID := model.ContestID(r.PathValue("contestId"))
+
+	var req transferContestOwnershipRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		uhttp.HandleError(w, uhttp.NewBadRequestError("invalid request body", err))
+		return
+	}
+	if req.UserID == 0 {
+		uhttp.HandleError(w, uhttp.NewBadRequestError("user_id is required", nil))
+		return
+	}
+
+	contest, err := h.service.TransferContestOwnership(r.Context(), contestID, userID, req.UserID)
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	if err := uhttp.SendSuccess(w, contest); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
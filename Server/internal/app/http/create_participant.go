@@ -13,7 +13,7 @@ import (
 
 type (
 	serviceCreateParticipant interface {
-		CreateParticipant(ctx context.Context, contestID model.ContestID, userID model.UserID, petName, petDescription string) (*model.Participant, error)
+		CreateParticipant(ctx context.Context, contestID model.ContestID, userID model.UserID, petID model.PetID, petName, petDescription string, attrs model.PetAttributes, petPhotoIDs []string) (*model.Participant, error)
 	}
 
 	CreateParticipantHandler struct {
@@ -33,8 +33,11 @@ func (h *CreateParticipantHandler) ServeHTTP(w http.ResponseWriter, r *http.Requ
 	logger.Info("Creating participant", "handler", "CreateParticipantHandler", "contestID", contestID, "userID", userID)
 
 	var req struct {
-		PetName        string `json:"pet_name"`
-		PetDescription string `json:"pet_description"`
+		PetID          model.PetID `json:"pet_id"`
+		PetName        string      `json:"pet_name"`
+		PetDescription string      `json:"pet_description"`
+		PhotoIDs       []string    `json:"photo_ids"`
+		model.PetAttributes
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -43,15 +46,16 @@ func (h *CreateParticipantHandler) ServeHTTP(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	logger.Debug("Request data", "handler", "CreateParticipantHandler", "pet_name", req.PetName, "pet_description", req.PetDescription)
+	logger.Debug("Request data", "handler", "CreateParticipantHandler", "pet_id", req.PetID, "pet_name", req.PetName, "pet_description", req.PetDescription)
 
-	if req.PetName == "" {
+	// С pet_id имя берется из профиля питомца
+	if req.PetID == "" && req.PetName == "" {
 		logger.Warn("pet_name is required", "handler", "CreateParticipantHandler")
 		uhttp.HandleError(w, uhttp.NewBadRequestError("pet_name is required", nil))
 		return
 	}
 
-	participant, err := h.service.CreateParticipant(r.Context(), contestID, userID, req.PetName, req.PetDescription)
+	participant, err := h.service.CreateParticipant(r.Context(), contestID, userID, req.PetID, req.PetName, req.PetDescription, req.PetAttributes, req.PhotoIDs)
 	if err != nil {
 		logger.Error("Failed to create participant", "handler", "CreateParticipantHandler", "error", err)
 		uhttp.HandleError(w, err)
@@ -0,0 +1,76 @@
+package http
+
+import (
+	"html/template"
+	"net/http"
+	"net/url"
+
+	authinterface "toppet/server/internal/app/authinterface"
+	provideruserdata "toppet/server/internal/app/clients/provider_user_data"
+	"toppet/server/internal/app/uhttp"
+)
+
+// DevAuthorizeHandler заменяет страницу авторизации внешнего OAuth провайдера для dev провайдера:
+// показывает форму выбора ID/имени и редиректит на обычный /api/auth/callback с кодом.
+type DevAuthorizeHandler struct {
+	name     string
+	provConf *authinterface.ProviderOauthConf
+}
+
+var devAuthorizeTemplate = template.Must(template.New("dev_authorize").Parse(`<!DOCTYPE html>
+<html lang="ru">
+<head><meta charset="utf-8"><title>TopPet dev login</title></head>
+<body>
+<h1>Dev login</h1>
+<form method="GET">
+<input type="hidden" name="state" value="{{.State}}">
+<input type="hidden" name="redirect_uri" value="{{.RedirectURI}}">
+<p><label>ID <input name="uid" required></label></p>
+<p><label>Имя <input name="name"></label></p>
+<p><button type="submit">Войти</button></p>
+</form>
+</body>
+</html>
+`))
+
+func NewDevAuthorizeHandler(provConf *authinterface.ProviderOauthConf, name string) *DevAuthorizeHandler {
+	return &DevAuthorizeHandler{name: name, provConf: provConf}
+}
+
+func (h *DevAuthorizeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	state := q.Get("state")
+	redirectURI := q.Get("redirect_uri")
+
+	if state == "" {
+		uhttp.HandleError(w, uhttp.NewBadRequestError("state required", nil))
+		return
+	}
+	// Redirect only to our own callback to avoid open redirect
+	if h.provConf == nil || h.provConf.Oauth2Config == nil || redirectURI != h.provConf.Oauth2Config.RedirectURL {
+		uhttp.HandleError(w, uhttp.NewBadRequestError("invalid redirect_uri", nil))
+		return
+	}
+
+	uid := q.Get("uid")
+	if uid == "" {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_ = devAuthorizeTemplate.Execute(w, struct {
+			State       string
+			RedirectURI string
+		}{State: state, RedirectURI: redirectURI})
+		return
+	}
+
+	target, err := url.Parse(redirectURI)
+	if err != nil {
+		uhttp.HandleError(w, uhttp.NewBadRequestError("invalid redirect_uri", err))
+		return
+	}
+	params := target.Query()
+	params.Set("code", provideruserdata.EncodeDevCode(uid, q.Get("name")))
+	params.Set("state", state)
+	target.RawQuery = params.Encode()
+
+	http.Redirect(w, r, target.String(), http.StatusFound)
+}
@@ -0,0 +1,53 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"toppet/server/internal/app/defenitions"
+	"toppet/server/internal/app/uhttp"
+	"toppet/server/internal/model"
+)
+
+type (
+	serviceDisqualifyParticipant interface {
+		DisqualifyParticipant(ctx context.Context, participantID model.ParticipantID, actorID model.UserID, reason string) (*model.Participant, error)
+	}
+
+	// DisqualifyParticipantHandler дисквалификация участника: POST /api/participants/{participantId}/disqualify
+	DisqualifyParticipantHandler struct {
+		name    string
+		service serviceDisqualifyParticipant
+	}
+
+	disqualifyParticipantRequest struct {
+		Reason string `json:"reason"`
+	}
+)
+
+func NewDisqualifyParticipantHandler(name string, service serviceDisqualifyParticipant) *DisqualifyParticipantHandler {
+	return &DisqualifyParticipantHandler{name: name, service: service}
+}
+
+func (h *DisqualifyParticipantHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(defenitions.UserID).(model.UserID)
+	participantID := model.ParticipantID(r.PathValue("participantId"))
+
+	var req disqualifyParticipantRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		uhttp.HandleError(w, uhttp.NewBadRequestError("invalid request body", err))
+		return
+	}
+
+	participant, err := h.service.DisqualifyParticipant(r.Context(), participantID, userID, req.Reason)
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	if err := uhttp.SendSuccess(w, participant); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
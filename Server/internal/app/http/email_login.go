@@ -0,0 +1,132 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/gorilla/sessions"
+
+	"toppet/server/internal/app/defenitions"
+	"toppet/server/internal/app/uhttp"
+	"toppet/server/internal/model"
+)
+
+const maxEmailLength = 254
+
+type (
+	serviceEmailLogin interface {
+		RequestEmailLogin(ctx context.Context, email string) error
+		LoginByEmail(ctx context.Context, token string) (*model.AuthData, error)
+	}
+
+	// EmailLoginHandler отправляет ссылку для входа на email (POST /api/auth/email)
+	EmailLoginHandler struct {
+		name    string
+		service serviceEmailLogin
+	}
+
+	// EmailLoginCallbackHandler обрабатывает переход по ссылке из письма
+	// и редиректит на фронтенд с токенами так же, как OAuth callback.
+	EmailLoginCallbackHandler struct {
+		name    string
+		store   *sessions.CookieStore
+		service serviceEmailLogin
+	}
+)
+
+func NewEmailLoginHandler(service serviceEmailLogin, name string) *EmailLoginHandler {
+	return &EmailLoginHandler{
+		name:    name,
+		service: service,
+	}
+}
+
+func (h *EmailLoginHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Email string `json:"email"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		uhttp.HandleError(w, uhttp.NewBadRequestError("invalid json", err))
+		return
+	}
+
+	req.Email = strings.TrimSpace(req.Email)
+	if err := uhttp.ValidateRequired(req.Email, "email"); err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+	if err := uhttp.ValidateMaxLength(req.Email, maxEmailLength, "email"); err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+	if err := uhttp.ValidateEmail(req.Email); err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	if err := h.service.RequestEmailLogin(r.Context(), req.Email); err != nil {
+		if errors.Is(err, model.ErrTooManyRequests) {
+			uhttp.HandleError(w, uhttp.NewTooManyRequestsError("too many login emails, try again later", err))
+			return
+		}
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	if err := uhttp.SendSuccess(w, map[string]bool{"sent": true}); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
+
+func NewEmailLoginCallbackHandler(service serviceEmailLogin, name string, store *sessions.CookieStore) *EmailLoginCallbackHandler {
+	return &EmailLoginCallbackHandler{
+		name:    name,
+		store:   store,
+		service: service,
+	}
+}
+
+func (h *EmailLoginCallbackHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+
+	frontendURL := os.Getenv("FRONTEND_URL")
+	if frontendURL == "" {
+		frontendURL = "http://localhost:3000"
+	}
+
+	if token == "" {
+		redirectURL := fmt.Sprintf("%s/login?provider=email&error=invalid_request&error_description=%s",
+			frontendURL, url.QueryEscape("missing_token"))
+		http.Redirect(w, r, redirectURL, http.StatusFound)
+		return
+	}
+
+	authData, err := h.service.LoginByEmail(r.Context(), token)
+	if err != nil {
+		redirectURL := fmt.Sprintf("%s/login?provider=email&error=invalid_token&error_description=%s",
+			frontendURL, url.QueryEscape("link_invalid_or_expired"))
+		http.Redirect(w, r, redirectURL, http.StatusFound)
+		return
+	}
+
+	// Save session
+	session, err := h.store.Get(r, defenitions.SessionAuthenticationName)
+	if err == nil {
+		session.Values[defenitions.Token] = authData.RefreshToken
+		session.Values[defenitions.UserID] = int64(authData.UserID)
+		session.Save(r, w)
+	}
+
+	redirectURL := fmt.Sprintf("%s/login?provider=email&access_token=%s&refresh_token=%s&user_id=%d",
+		frontendURL, url.QueryEscape(authData.AccessToken), url.QueryEscape(authData.RefreshToken), authData.UserID)
+
+	http.Redirect(w, r, redirectURL, http.StatusFound)
+}
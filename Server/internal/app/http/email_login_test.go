@@ -0,0 +1,23 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"toppet/server/internal/service"
+)
+
+func TestEmailLoginHandler_Disabled(t *testing.T) {
+	// Без EMAIL_LOGIN_SECRET и почты вход по ссылке выключен - это 404, а не внутренняя ошибка
+	handler := NewEmailLoginHandler(&service.TopPetService{}, "/api/auth/email")
+
+	req := httptest.NewRequest(http.MethodPost, "/api/auth/email", strings.NewReader(`{"email":"user@example.com"}`))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d: %s", http.StatusNotFound, w.Code, w.Body.String())
+	}
+}
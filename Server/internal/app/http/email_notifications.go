@@ -0,0 +1,154 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"html/template"
+	"log"
+	"net/http"
+
+	"toppet/server/internal/app/defenitions"
+	"toppet/server/internal/app/uhttp"
+	"toppet/server/internal/model"
+)
+
+type (
+	serviceEmailNotifications interface {
+		GetNotificationPreferences(ctx context.Context, userID model.UserID) (*model.NotificationPreferences, error)
+		UpdateNotificationPreferences(ctx context.Context, userID model.UserID, prefs *model.NotificationPreferences) (*model.NotificationPreferences, error)
+		Unsubscribe(ctx context.Context, token string) (*model.NotificationPreferences, error)
+	}
+
+	// EmailNotificationHandler настройки писем (/api/auth/me/notification-preferences)
+	// и отписка по ссылке из письма (/api/notifications/unsubscribe)
+	EmailNotificationHandler struct {
+		name    string
+		service serviceEmailNotifications
+	}
+
+	notificationPreferencesRequest struct {
+		EmailVotingStarted    *bool `json:"email_voting_started"`
+		EmailResultsPublished *bool `json:"email_results_published"`
+	}
+)
+
+// unsubscribeTemplate страница отписки на двух языках: язык получателя по ссылке неизвестен
+var unsubscribeTemplate = template.Must(template.New("unsubscribe").Parse(`<!DOCTYPE html>
+<html lang="ru">
+<head><meta charset="utf-8"><meta name="viewport" content="width=device-width, initial-scale=1"><title>TopPet</title></head>
+<body style="font-family:Arial,Helvetica,sans-serif;max-width:560px;margin:40px auto;padding:0 16px;color:#222">
+{{if eq .State "confirm"}}
+<h1>Отписаться от писем TopPet?</h1>
+<p>Unsubscribe from TopPet emails?</p>
+<form method="POST">
+<input type="hidden" name="token" value="{{.Token}}">
+<p><button type="submit">Отписаться / Unsubscribe</button></p>
+</form>
+{{else if eq .State "done"}}
+<h1>Вы отписались</h1>
+<p>Больше не будем присылать такие письма. Включить их снова можно в настройках профиля.</p>
+<p>You have been unsubscribed. You can turn these emails back on in your profile settings.</p>
+{{else}}
+<h1>Ссылка недействительна</h1>
+<p>The unsubscribe link is invalid.</p>
+{{end}}
+</body>
+</html>
+`))
+
+func NewEmailNotificationHandler(name string, service serviceEmailNotifications) *EmailNotificationHandler {
+	return &EmailNotificationHandler{name: name, service: service}
+}
+
+func (h *EmailNotificationHandler) GetPreferences(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(defenitions.UserID).(model.UserID)
+
+	prefs, err := h.service.GetNotificationPreferences(r.Context(), userID)
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	if err := uhttp.SendSuccess(w, prefs); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
+
+// UpdatePreferences меняет только переданные поля, остальные сохраняют текущие значения
+func (h *EmailNotificationHandler) UpdatePreferences(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(defenitions.UserID).(model.UserID)
+
+	var req notificationPreferencesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		uhttp.HandleError(w, uhttp.NewBadRequestError("invalid request body", err))
+		return
+	}
+
+	prefs, err := h.service.GetNotificationPreferences(r.Context(), userID)
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+	if req.EmailVotingStarted != nil {
+		prefs.EmailVotingStarted = *req.EmailVotingStarted
+	}
+	if req.EmailResultsPublished != nil {
+		prefs.EmailResultsPublished = *req.EmailResultsPublished
+	}
+
+	updated, err := h.service.UpdateNotificationPreferences(r.Context(), userID, prefs)
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	if err := uhttp.SendSuccess(w, updated); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
+
+// UnsubscribePage GET по ссылке из письма только показывает кнопку подтверждения:
+// почтовые сканеры открывают ссылки из писем и не должны отписывать пользователя.
+func (h *EmailNotificationHandler) UnsubscribePage(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		renderUnsubscribePage(w, http.StatusBadRequest, "invalid", "")
+		return
+	}
+	renderUnsubscribePage(w, http.StatusOK, "confirm", token)
+}
+
+// Unsubscribe отписка: кнопка на странице подтверждения или One-Click POST почтового клиента (RFC 8058)
+func (h *EmailNotificationHandler) Unsubscribe(w http.ResponseWriter, r *http.Request) {
+	token := r.FormValue("token")
+	if token == "" {
+		renderUnsubscribePage(w, http.StatusBadRequest, "invalid", "")
+		return
+	}
+	if _, err := h.service.Unsubscribe(r.Context(), token); err != nil {
+		switch {
+		case errors.Is(err, model.ErrBadRequest):
+			renderUnsubscribePage(w, http.StatusBadRequest, "invalid", "")
+		case errors.Is(err, model.ErrorNotFound):
+			renderUnsubscribePage(w, http.StatusNotFound, "invalid", "")
+		default:
+			uhttp.HandleError(w, err)
+		}
+		return
+	}
+	renderUnsubscribePage(w, http.StatusOK, "done", "")
+}
+
+func renderUnsubscribePage(w http.ResponseWriter, status int, state, token string) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	if err := unsubscribeTemplate.Execute(w, struct {
+		State string
+		Token string
+	}{State: state, Token: token}); err != nil {
+		log.Printf("[EmailNotificationHandler] render unsubscribe page: %v", err)
+	}
+}
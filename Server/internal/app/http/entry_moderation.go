@@ -0,0 +1,90 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"toppet/server/internal/app/defenitions"
+	"toppet/server/internal/app/uhttp"
+	"toppet/server/internal/model"
+)
+
+type (
+	serviceEntryModeration interface {
+		ListEntryModerationQueue(ctx context.Context, contestID model.ContestID, actorID model.UserID, status model.EntryModerationStatus) ([]*model.Participant, error)
+		ModerateEntry(ctx context.Context, participantID model.ParticipantID, actorID model.UserID, decision model.EntryModerationStatus, reason string) (*model.Participant, error)
+	}
+
+	// EntryModerationHandler премодерация заявок: очередь /api/contests/{contestId}/moderation-queue
+	// и решения /api/participants/{participantId}/approve|reject
+	EntryModerationHandler struct {
+		name    string
+		service serviceEntryModeration
+	}
+
+	moderateEntryRequest struct {
+		Reason string `json:"reason"`
+	}
+)
+
+func NewEntryModerationHandler(name string, service serviceEntryModeration) *EntryModerationHandler {
+	return &EntryModerationHandler{name: name, service: service}
+}
+
+func (h *EntryModerationHandler) ListQueue(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(defenitions.UserID).(model.UserID)
+	contestID := model.ContestID(r.PathValue("contestId"))
+	status := model.EntryModerationStatus(r.URL.Query().Get("status"))
+
+	participants, err := h.service.ListEntryModerationQueue(r.Context(), contestID, userID, status)
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+	if participants == nil {
+		participants = []*model.Participant{}
+	}
+
+	type resp struct {
+		Items []*model.Participant `json:"items"`
+		Total int64                `json:"total"`
+	}
+	if err := uhttp.SendSuccess(w, resp{Items: participants, Total: int64(len(participants))}); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
+
+func (h *EntryModerationHandler) Approve(w http.ResponseWriter, r *http.Request) {
+	h.moderate(w, r, model.EntryModerationApproved)
+}
+
+func (h *EntryModerationHandler) Reject(w http.ResponseWriter, r *http.Request) {
+	h.moderate(w, r, model.EntryModerationRejected)
+}
+
+func (h *EntryModerationHandler) moderate(w http.ResponseWriter, r *http.Request, decision model.EntryModerationStatus) {
+	userID := r.Context().Value(defenitions.UserID).(model.UserID)
+	participantID := model.ParticipantID(r.PathValue("participantId"))
+
+	// Тело необязательно для одобрения
+	var req moderateEntryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		uhttp.HandleError(w, uhttp.NewBadRequestError("invalid request body", err))
+		return
+	}
+
+	participant, err := h.service.ModerateEntry(r.Context(), participantID, userID, decision, req.Reason)
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	if err := uhttp.SendSuccess(w, participant); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
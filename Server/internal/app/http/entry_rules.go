@@ -0,0 +1,105 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"toppet/server/internal/app/defenitions"
+	"toppet/server/internal/app/uhttp"
+	"toppet/server/internal/model"
+)
+
+type (
+	serviceEntryRules interface {
+		GetEntryRules(ctx context.Context, contestID model.ContestID) (*model.EntryRules, error)
+		UpdateEntryRules(ctx context.Context, contestID model.ContestID, actorID model.UserID, rules *model.EntryRules) (*model.EntryRules, error)
+	}
+
+	// EntryRulesHandler правила подачи заявок в конкурс: /api/contests/{contestId}/entry-rules
+	EntryRulesHandler struct {
+		name        string
+		service     serviceEntryRules
+		authService serviceOptionalAuth
+	}
+
+	updateEntryRulesRequest struct {
+		MaxEntriesPerUser   int        `json:"max_entries_per_user"`
+		MaxEntries          int        `json:"max_entries"`
+		MaxPhotosPerEntry   int        `json:"max_photos_per_entry"`
+		MaxPhotoSizeMB      int        `json:"max_photo_size_mb"`
+		VideoAllowed        *bool      `json:"video_allowed"`
+		MaxVideoDurationSec int        `json:"max_video_duration_sec"`
+		MaxVideoSizeMB      int        `json:"max_video_size_mb"`
+		RequiredFields      []string   `json:"required_fields"`
+		EntryDeadline       *time.Time `json:"entry_deadline"`
+		RequireApproval     bool       `json:"require_approval"`
+		AllowedSpecies      []string   `json:"allowed_species"`
+		MinAgeMonths        int        `json:"min_age_months"`
+		MaxAgeMonths        int        `json:"max_age_months"`
+	}
+)
+
+func NewEntryRulesHandler(name string, service serviceEntryRules) *EntryRulesHandler {
+	var authService serviceOptionalAuth
+	if svc, ok := service.(serviceOptionalAuth); ok {
+		authService = svc
+	}
+	return &EntryRulesHandler{name: name, service: service, authService: authService}
+}
+
+func (h *EntryRulesHandler) GetRules(w http.ResponseWriter, r *http.Request) {
+	contestID := model.ContestID(r.PathValue("contestId"))
+
+	ctx := optionalClaimsContext(r, h.authService)
+
+	rules, err := h.service.GetEntryRules(ctx, contestID)
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	if err := uhttp.SendSuccess(w, rules); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
+
+func (h *EntryRulesHandler) UpdateRules(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(defenitions.UserID).(model.UserID)
+	contestID := model.ContestID(r.PathValue("contestId"))
+
+	var req updateEntryRulesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		uhttp.HandleError(w, uhttp.NewBadRequestError("invalid request body", err))
+		return
+	}
+
+	// Без явного video_allowed видео разрешено
+	videoAllowed := req.VideoAllowed == nil || *req.VideoAllowed
+	rules, err := h.service.UpdateEntryRules(r.Context(), contestID, userID, &model.EntryRules{
+		MaxEntriesPerUser:   req.MaxEntriesPerUser,
+		MaxEntries:          req.MaxEntries,
+		MaxPhotosPerEntry:   req.MaxPhotosPerEntry,
+		MaxPhotoSizeMB:      req.MaxPhotoSizeMB,
+		VideoAllowed:        videoAllowed,
+		MaxVideoDurationSec: req.MaxVideoDurationSec,
+		MaxVideoSizeMB:      req.MaxVideoSizeMB,
+		RequiredFields:      req.RequiredFields,
+		EntryDeadline:       req.EntryDeadline,
+		RequireApproval:     req.RequireApproval,
+		AllowedSpecies:      req.AllowedSpecies,
+		MinAgeMonths:        req.MinAgeMonths,
+		MaxAgeMonths:        req.MaxAgeMonths,
+	})
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	if err := uhttp.SendSuccess(w, rules); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
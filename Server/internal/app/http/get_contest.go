@@ -13,10 +13,16 @@ type (
 		GetContest(ctx context.Context, contestID model.ContestID) (*model.Contest, error)
 	}
 
+	// serviceContestAccess проверяет права команды конкурса (владелец или организатор)
+	serviceContestAccess interface {
+		CanManageContest(ctx context.Context, contest *model.Contest, userID model.UserID) bool
+	}
+
 	GetContestHandler struct {
-		name        string
-		service     serviceGetContest
-		authService serviceOptionalAuth
+		name          string
+		service       serviceGetContest
+		authService   serviceOptionalAuth
+		accessService serviceContestAccess
 	}
 )
 
@@ -26,7 +32,9 @@ func NewGetContestHandler(name string, service serviceGetContest) *GetContestHan
 		authService = svc
 	}
 
-	return &GetContestHandler{name: name, service: service, authService: authService}
+	accessService, _ := service.(serviceContestAccess)
+
+	return &GetContestHandler{name: name, service: service, authService: authService, accessService: accessService}
 }
 
 func (h *GetContestHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -36,19 +44,20 @@ func (h *GetContestHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	contest, err := h.service.GetContest(r.Context(), contestID)
+	claims, authErr := getOptionalClaims(r, h.authService)
+
+	contest, err := h.service.GetContest(withOptionalClaims(r.Context(), claims), contestID)
 	if err != nil {
 		uhttp.HandleError(w, err)
 		return
 	}
 
 	if contest.Status == model.ContestStatusDraft {
-		userID, ok, authErr := getOptionalUserID(r, h.authService)
 		if authErr != nil {
 			uhttp.HandleError(w, uhttp.NewUnauthorizedError("authentication required", authErr))
 			return
 		}
-		if !ok || contest.CreatedByUserID != userID {
+		if claims == nil || !canManageContest(r.Context(), h.accessService, contest, claims.UserID) {
 			uhttp.HandleError(w, uhttp.NewNotFoundError("contest not found", nil))
 			return
 		}
@@ -59,3 +68,11 @@ func (h *GetContestHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 }
+
+// canManageContest без сервиса доступа сводится к проверке создателя
+func canManageContest(ctx context.Context, accessService serviceContestAccess, contest *model.Contest, userID model.UserID) bool {
+	if contest.CreatedByUserID == userID {
+		return true
+	}
+	return accessService != nil && accessService.CanManageContest(ctx, contest, userID)
+}
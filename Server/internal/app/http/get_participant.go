@@ -34,24 +34,25 @@ func (h *GetParticipantHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 	participantID := model.ParticipantID(r.PathValue("participantId"))
 	
 	// Get userID from context or extract from token (optional auth)
+	ctx := r.Context()
 	var userID *model.UserID
-	if userIDVal := r.Context().Value(defenitions.UserID); userIDVal != nil {
+	if userIDVal := ctx.Value(defenitions.UserID); userIDVal != nil {
 		uid := userIDVal.(model.UserID)
 		userID = &uid
 	} else {
 		// Try to get userID from token if available
-		uid, ok, _ := getOptionalUserID(r, h.authService)
-		if ok {
-			userID = &uid
+		if claims, _ := getOptionalClaims(r, h.authService); claims != nil {
+			ctx = withOptionalClaims(ctx, claims)
+			userID = &claims.UserID
 		}
 	}
 	
 	var participant *model.Participant
 	var err error
 	if userID != nil {
-		participant, err = h.service.GetParticipantWithLikes(r.Context(), participantID, userID)
+		participant, err = h.service.GetParticipantWithLikes(ctx, participantID, userID)
 	} else {
-		participant, err = h.service.GetParticipant(r.Context(), participantID)
+		participant, err = h.service.GetParticipant(ctx, participantID)
 	}
 	if err != nil {
 		uhttp.HandleError(w, err)
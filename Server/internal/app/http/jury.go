@@ -0,0 +1,197 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"toppet/server/internal/app/defenitions"
+	"toppet/server/internal/app/uhttp"
+	"toppet/server/internal/model"
+)
+
+type (
+	serviceJury interface {
+		ListJuryCriteria(ctx context.Context, contestID model.ContestID) ([]*model.JuryCriterion, error)
+		CreateJuryCriterion(ctx context.Context, contestID model.ContestID, actorID model.UserID, criterion *model.JuryCriterion) (*model.JuryCriterion, error)
+		DeleteJuryCriterion(ctx context.Context, contestID model.ContestID, actorID model.UserID, criterionID string) error
+		UpdateJuryWeight(ctx context.Context, contestID model.ContestID, actorID model.UserID, juryWeight int) (*model.Contest, error)
+		ListJuryScores(ctx context.Context, contestID model.ContestID, actorID model.UserID) ([]*model.JuryScore, error)
+		SubmitJuryScores(ctx context.Context, contestID model.ContestID, participantID model.ParticipantID, jurorID model.UserID, scores []*model.JuryScore) ([]*model.JuryScore, error)
+	}
+
+	// JuryHandler критерии, оценки и вес жюри: /api/contests/{contestId}/jury/...
+	JuryHandler struct {
+		name        string
+		service     serviceJury
+		authService serviceOptionalAuth
+	}
+
+	createJuryCriterionRequest struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		Weight      int    `json:"weight"`
+		MaxScore    int    `json:"max_score"`
+	}
+
+	updateJuryWeightRequest struct {
+		JuryWeight int `json:"jury_weight"`
+	}
+
+	submitJuryScoresRequest struct {
+		Scores []struct {
+			CriterionID string `json:"criterion_id"`
+			Score       int    `json:"score"`
+			Comment     string `json:"comment"`
+		} `json:"scores"`
+	}
+)
+
+func NewJuryHandler(name string, service serviceJury) *JuryHandler {
+	var authService serviceOptionalAuth
+	if svc, ok := service.(serviceOptionalAuth); ok {
+		authService = svc
+	}
+	return &JuryHandler{name: name, service: service, authService: authService}
+}
+
+func (h *JuryHandler) ListCriteria(w http.ResponseWriter, r *http.Request) {
+	contestID := model.ContestID(r.PathValue("contestId"))
+
+	ctx := optionalClaimsContext(r, h.authService)
+
+	criteria, err := h.service.ListJuryCriteria(ctx, contestID)
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	type resp struct {
+		Items []*model.JuryCriterion `json:"items"`
+		Total int64                  `json:"total"`
+	}
+	if err := uhttp.SendSuccess(w, resp{Items: criteria, Total: int64(len(criteria))}); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
+
+func (h *JuryHandler) CreateCriterion(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(defenitions.UserID).(model.UserID)
+	contestID := model.ContestID(r.PathValue("contestId"))
+
+	var req createJuryCriterionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		uhttp.HandleError(w, uhttp.NewBadRequestError("invalid request body", err))
+		return
+	}
+
+	criterion, err := h.service.CreateJuryCriterion(r.Context(), contestID, userID, &model.JuryCriterion{
+		Name:        req.Name,
+		Description: req.Description,
+		Weight:      req.Weight,
+		MaxScore:    req.MaxScore,
+	})
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	if err := uhttp.SendSuccess(w, criterion); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
+
+func (h *JuryHandler) DeleteCriterion(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(defenitions.UserID).(model.UserID)
+	contestID := model.ContestID(r.PathValue("contestId"))
+
+	if err := h.service.DeleteJuryCriterion(r.Context(), contestID, userID, r.PathValue("criterionId")); err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	if err := uhttp.SendSuccess(w, map[string]bool{"deleted": true}); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
+
+func (h *JuryHandler) UpdateWeight(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(defenitions.UserID).(model.UserID)
+	contestID := model.ContestID(r.PathValue("contestId"))
+
+	var req updateJuryWeightRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		uhttp.HandleError(w, uhttp.NewBadRequestError("invalid request body", err))
+		return
+	}
+
+	contest, err := h.service.UpdateJuryWeight(r.Context(), contestID, userID, req.JuryWeight)
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	if err := uhttp.SendSuccess(w, contest); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
+
+func (h *JuryHandler) ListScores(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(defenitions.UserID).(model.UserID)
+	contestID := model.ContestID(r.PathValue("contestId"))
+
+	scores, err := h.service.ListJuryScores(r.Context(), contestID, userID)
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	type resp struct {
+		Items []*model.JuryScore `json:"items"`
+		Total int64              `json:"total"`
+	}
+	if err := uhttp.SendSuccess(w, resp{Items: scores, Total: int64(len(scores))}); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
+
+func (h *JuryHandler) SubmitScores(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(defenitions.UserID).(model.UserID)
+	contestID := model.ContestID(r.PathValue("contestId"))
+	participantID := model.ParticipantID(r.PathValue("participantId"))
+
+	var req submitJuryScoresRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		uhttp.HandleError(w, uhttp.NewBadRequestError("invalid request body", err))
+		return
+	}
+
+	scores := make([]*model.JuryScore, 0, len(req.Scores))
+	for _, item := range req.Scores {
+		scores = append(scores, &model.JuryScore{
+			CriterionID: item.CriterionID,
+			Score:       item.Score,
+			Comment:     item.Comment,
+		})
+	}
+
+	saved, err := h.service.SubmitJuryScores(r.Context(), contestID, participantID, userID, scores)
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	type resp struct {
+		Items []*model.JuryScore `json:"items"`
+		Total int64              `json:"total"`
+	}
+	if err := uhttp.SendSuccess(w, resp{Items: saved, Total: int64(len(saved))}); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
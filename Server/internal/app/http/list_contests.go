@@ -15,9 +15,10 @@ type (
 	}
 
 	ListContestsHandler struct {
-		name        string
-		service     serviceListContests
-		authService serviceOptionalAuth
+		name          string
+		service       serviceListContests
+		authService   serviceOptionalAuth
+		accessService serviceContestAccess
 	}
 )
 
@@ -27,7 +28,9 @@ func NewListContestsHandler(name string, service serviceListContests) *ListConte
 		authService = svc
 	}
 
-	return &ListContestsHandler{name: name, service: service, authService: authService}
+	accessService, _ := service.(serviceContestAccess)
+
+	return &ListContestsHandler{name: name, service: service, authService: authService, accessService: accessService}
 }
 
 func (h *ListContestsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -71,7 +74,7 @@ func (h *ListContestsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 			filtered = append(filtered, contest)
 			continue
 		}
-		if hasUser && contest.CreatedByUserID == userID {
+		if hasUser && canManageContest(r.Context(), h.accessService, contest, userID) {
 			filtered = append(filtered, contest)
 		}
 	}
@@ -3,6 +3,7 @@ package http
 import (
 	"context"
 	"net/http"
+	"strconv"
 
 	"toppet/server/internal/app/uhttp"
 	"toppet/server/internal/model"
@@ -10,17 +11,22 @@ import (
 
 type (
 	serviceListParticipants interface {
-		ListParticipantsByContest(ctx context.Context, contestID model.ContestID) ([]*model.Participant, error)
+		ListParticipantsByContest(ctx context.Context, contestID model.ContestID, filter model.ParticipantFilter) ([]*model.Participant, error)
 	}
 
 	ListParticipantsHandler struct {
-		name    string
-		service serviceListParticipants
+		name        string
+		service     serviceListParticipants
+		authService serviceOptionalAuth
 	}
 )
 
 func NewListParticipantsHandler(name string, service serviceListParticipants) *ListParticipantsHandler {
-	return &ListParticipantsHandler{name: name, service: service}
+	var authService serviceOptionalAuth
+	if svc, ok := service.(serviceOptionalAuth); ok {
+		authService = svc
+	}
+	return &ListParticipantsHandler{name: name, service: service, authService: authService}
 }
 
 func (h *ListParticipantsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -30,7 +36,15 @@ func (h *ListParticipantsHandler) ServeHTTP(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	participants, err := h.service.ListParticipantsByContest(r.Context(), contestID)
+	filter, err := parseParticipantFilter(r)
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	ctx := optionalClaimsContext(r, h.authService)
+
+	participants, err := h.service.ListParticipantsByContest(ctx, contestID, filter)
 	if err != nil {
 		uhttp.HandleError(w, err)
 		return
@@ -51,3 +65,25 @@ func (h *ListParticipantsHandler) ServeHTTP(w http.ResponseWriter, r *http.Reque
 		return
 	}
 }
+
+// parseParticipantFilter фильтры списка: ?species=cat&breed=cat_maine_coon&sex=female&min_age_months=0&max_age_months=11
+func parseParticipantFilter(r *http.Request) (model.ParticipantFilter, error) {
+	query := r.URL.Query()
+	filter := model.ParticipantFilter{
+		Species: query.Get("species"),
+		Breed:   query.Get("breed"),
+		Sex:     model.PetSex(query.Get("sex")),
+	}
+	for name, target := range map[string]*int{"min_age_months": &filter.MinAgeMonths, "max_age_months": &filter.MaxAgeMonths} {
+		value := query.Get(name)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return filter, uhttp.NewBadRequestError(name+" must be a non-negative integer", err)
+		}
+		*target = n
+	}
+	return filter, nil
+}
@@ -14,6 +14,7 @@ import (
 
 	"github.com/gorilla/sessions"
 	authinterface "toppet/server/internal/app/authinterface"
+	provideruserdata "toppet/server/internal/app/clients/provider_user_data"
 	"toppet/server/internal/app/uhttp"
 )
 
@@ -67,11 +68,12 @@ func (h *LoginHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	state := randomURLSafe(24)
 
-	// VK doesn't support PKCE, so we allow empty code_challenge and code_verifier for VK
+	// VK doesn't support PKCE, so we allow empty code_challenge and code_verifier for VK.
+	// The dev provider never exchanges the code, so PKCE is optional for it too.
 	challenge := req.CodeChallenge
 	codeVerifier := req.CodeVerifier
 	
-	if req.Provider != "vk" {
+	if req.Provider != "vk" && req.Provider != provideruserdata.DevProviderName {
 		if challenge == "" {
 			uhttp.HandleError(w, uhttp.NewBadRequestError("code_challenge required from client", nil))
 			return
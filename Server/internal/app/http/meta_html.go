@@ -17,8 +17,9 @@ import (
 type (
 	serviceMetaHTML interface {
 		GetContest(ctx context.Context, contestID model.ContestID) (*model.Contest, error)
-		ListParticipantsByContest(ctx context.Context, contestID model.ContestID) ([]*model.Participant, error)
+		ListParticipantsByContest(ctx context.Context, contestID model.ContestID, filter model.ParticipantFilter) ([]*model.Participant, error)
 		GetParticipant(ctx context.Context, participantID model.ParticipantID) (*model.Participant, error)
+		GetPublicUserProfile(ctx context.Context, userID model.UserID) (*model.PublicUserProfile, error)
 	}
 
 	metaHTMLHandler struct {
@@ -236,6 +237,29 @@ func participantDescription(petName, petDesc string) string {
 	return oneLine + cta
 }
 
+// userDescription builds og:description for a user profile: one line from bio, or stats allowed by privacy settings if bio is empty. Max 160 runes.
+func userDescription(profile *model.PublicUserProfile) string {
+	bio := strings.Join(strings.Fields(profile.Bio), " ")
+	if bio != "" {
+		return truncateRunes(bio, ogDescriptionMaxRunes)
+	}
+	var stats []string
+	if profile.Pets != nil {
+		stats = append(stats, "питомцев: "+strconv.Itoa(len(profile.Pets)))
+	}
+	if profile.Contests != nil {
+		stats = append(stats, "конкурсов: "+strconv.Itoa(len(profile.Contests)))
+	}
+	if profile.Results != nil {
+		stats = append(stats, "побед: "+strconv.Itoa(profile.Results.Wins))
+	}
+	description := profile.Name + " на Top-Pet"
+	if len(stats) > 0 {
+		description += " — " + strings.Join(stats, ", ")
+	}
+	return truncateRunes(description, ogDescriptionMaxRunes)
+}
+
 // injectPreviewImage inserts a visible preview image in the body (after <body>), for crawlers and direct opens.
 func (h *metaHTMLHandler) injectPreviewImage(htmlBytes []byte, imageURL, title string) []byte {
 	if imageURL == "" {
@@ -277,6 +301,11 @@ func (h *metaHTMLHandler) injectParticipantPreviewCard(htmlBytes []byte, imageUR
 	return []byte(strings.Replace(string(htmlBytes), oldBody, newBody, 1))
 }
 
+// injectUserPreviewCard inserts the same card as contest pages (avatar + name + description) for user profile pages.
+func (h *metaHTMLHandler) injectUserPreviewCard(htmlBytes []byte, imageURL, title, description string) []byte {
+	return h.injectContestPreviewCard(htmlBytes, imageURL, title, description)
+}
+
 // homeMetaTitle and homeMetaDescription are default og values for the main page.
 const (
 	homeMetaTitle       = "Top-Pet — Платформа для конкурсов красоты животных"
@@ -329,7 +358,7 @@ func (h *metaHTMLHandler) ServeContest(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	participants, _ := h.service.ListParticipantsByContest(r.Context(), contestID)
+	participants, _ := h.service.ListParticipantsByContest(r.Context(), contestID, model.ParticipantFilter{})
 	imageURL := firstParticipantPhotoURL(participants)
 	if imageURL == "" {
 		imageURL = h.defaultImageURL()
@@ -425,3 +454,54 @@ func (h *metaHTMLHandler) ServeParticipant(w http.ResponseWriter, r *http.Reques
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	w.Write(out)
 }
+
+// ServeUser renders a public user profile page. Privacy settings apply as for an anonymous visitor.
+func (h *metaHTMLHandler) ServeUser(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet || !h.canServe() {
+		http.NotFound(w, r)
+		return
+	}
+	userID, err := strconv.ParseInt(r.PathValue("userId"), 10, 64)
+	if err != nil || userID <= 0 {
+		http.NotFound(w, r)
+		return
+	}
+
+	profile, err := h.service.GetPublicUserProfile(r.Context(), model.UserID(userID))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	pageTitle := truncateRunes(profile.Name+" - Top-Pet", ogTitleMaxRunes)
+	description := userDescription(profile)
+	imageURL := profile.AvatarURL
+	if imageURL == "" {
+		imageURL = h.defaultImageURL()
+	} else {
+		imageURL = h.absoluteImageURL(imageURL)
+	}
+	url := h.baseURL + "/users/" + strconv.FormatInt(userID, 10)
+	imageAlt := truncateRunes("Аватар пользователя "+profile.Name, 100)
+	imageWidth, imageHeight := 1200, 630
+	imageSecureURL := ""
+	if imageURL == h.defaultImageURL() {
+		if strings.HasPrefix(h.baseURL, "https://") {
+			imageSecureURL = imageURL
+		}
+	} else if strings.HasPrefix(imageURL, "https://") {
+		imageSecureURL = imageURL
+	}
+	metaTags := h.buildMetaTags(pageTitle, description, url, imageURL, imageAlt, "ru_RU", imageWidth, imageHeight, imageSecureURL)
+
+	htmlBytes, err := h.readIndexHTML()
+	if err != nil {
+		logger.Error("meta HTML ServeUser: failed to read index.html", "path", h.spaIndexPath, "error", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	out := h.injectMetaIntoHTML(htmlBytes, pageTitle, metaTags, url)
+	out = h.injectUserPreviewCard(out, imageURL, pageTitle, description)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(out)
+}
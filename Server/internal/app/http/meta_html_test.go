@@ -17,6 +17,7 @@ type mockMetaHTMLService struct {
 	contest     *model.Contest
 	participants []*model.Participant
 	participant *model.Participant
+	user        *model.PublicUserProfile
 }
 
 func (m *mockMetaHTMLService) GetContest(ctx context.Context, contestID model.ContestID) (*model.Contest, error) {
@@ -26,7 +27,7 @@ func (m *mockMetaHTMLService) GetContest(ctx context.Context, contestID model.Co
 	return nil, nil
 }
 
-func (m *mockMetaHTMLService) ListParticipantsByContest(ctx context.Context, contestID model.ContestID) ([]*model.Participant, error) {
+func (m *mockMetaHTMLService) ListParticipantsByContest(ctx context.Context, contestID model.ContestID, filter model.ParticipantFilter) ([]*model.Participant, error) {
 	return m.participants, nil
 }
 
@@ -37,6 +38,13 @@ func (m *mockMetaHTMLService) GetParticipant(ctx context.Context, participantID
 	return nil, nil
 }
 
+func (m *mockMetaHTMLService) GetPublicUserProfile(ctx context.Context, userID model.UserID) (*model.PublicUserProfile, error) {
+	if m.user != nil && m.user.ID == userID {
+		return m.user, nil
+	}
+	return nil, model.ErrorNotFound
+}
+
 func TestMetaHTML_ServeHome_HTMLAndMeta(t *testing.T) {
 	dir := t.TempDir()
 	indexPath := dir + "/index.html"
@@ -326,6 +334,66 @@ func TestMetaHTML_ServeParticipant_HTMLAndMeta(t *testing.T) {
 	}
 }
 
+func TestMetaHTML_ServeUser_HTMLAndMeta(t *testing.T) {
+	dir := t.TempDir()
+	indexPath := dir + "/index.html"
+	if err := writeMinimalIndex(indexPath); err != nil {
+		t.Fatalf("write index: %v", err)
+	}
+
+	svc := &mockMetaHTMLService{user: &model.PublicUserProfile{
+		ID:        42,
+		Name:      "Анна",
+		AvatarURL: "https://example.com/avatar.jpg",
+		Pets:      []*model.Pet{{ID: "pet-1"}, {ID: "pet-2"}},
+		Results:   &model.UserResults{Wins: 3},
+	}}
+	h := NewMetaHTMLHandler("https://top-pet.ru", indexPath, svc)
+
+	req := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+	req.SetPathValue("userId", "42")
+	rec := httptest.NewRecorder()
+	h.ServeUser(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status: got %d", rec.Code)
+	}
+	html := rec.Body.String()
+
+	checkMetaPresent(t, html, "user", map[string]bool{
+		"og:title":       true,
+		"og:description": true,
+		"og:url":         true,
+		"og:image":       true,
+		"og:image:alt":   true,
+		"twitter:card":   true,
+	})
+	if ogTitle := extractMetaContent(html, `property="og:title"`); ogTitle != "Анна - Top-Pet" {
+		t.Errorf("user og:title: got %q", ogTitle)
+	}
+	// Без био описание собирается из открытых разделов профиля; скрытый раздел конкурсов не упоминается
+	ogDesc := extractMetaContent(html, `property="og:description"`)
+	if ogDesc != "Анна на Top-Pet — питомцев: 2, побед: 3" {
+		t.Errorf("user og:description: got %q", ogDesc)
+	}
+	if ogImage := extractMetaContent(html, `property="og:image"`); ogImage != "https://example.com/avatar.jpg" {
+		t.Errorf("user og:image should be avatar, got %q", ogImage)
+	}
+	if !strings.Contains(html, `href="https://top-pet.ru/users/42"`) {
+		t.Error("user HTML: canonical href should match profile URL")
+	}
+
+	for _, userID := range []string{"43", "abc"} {
+		req := httptest.NewRequest(http.MethodGet, "/users/"+userID, nil)
+		req.SetPathValue("userId", userID)
+		rec := httptest.NewRecorder()
+		h.ServeUser(rec, req)
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("user %s: expected 404, got %d", userID, rec.Code)
+		}
+	}
+}
+
 func writeMinimalIndex(path string) error {
 	content := `<!DOCTYPE html><html><head><title>Top-Pet</title></head><body><div id="root"></div></body></html>`
 	return os.WriteFile(path, []byte(content), 0644)
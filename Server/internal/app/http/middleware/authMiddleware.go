@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"net/http"
-	"net/url"
 
 	"toppet/server/internal/app/defenitions"
 	"toppet/server/internal/model"
@@ -44,31 +43,18 @@ func (m *AuthMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	ctx = context.WithValue(ctx, defenitions.UserID, claims.UserID)
+	ctx = context.WithValue(ctx, defenitions.Roles, claims.Roles)
 	newRequest := r.WithContext(ctx)
 	m.h.ServeHTTP(w, newRequest)
 }
 
 func (m *AuthMiddleware) extractTokenFromHeader(r *http.Request) (string, error) {
-	token := ""
-	u, err := url.Parse(r.RequestURI)
-	if err == nil {
-		queryParams := u.Query()
-		token = queryParams.Get("accessToken")
-		if token != "" {
-			return token, nil
-		}
+	if token := ExtractAccessToken(r); token != "" {
+		return token, nil
 	}
 
-	authHeader := r.Header.Get("Authorization")
-	if authHeader == "" {
+	if r.Header.Get("Authorization") == "" {
 		return "", fmt.Errorf("отсутствует заголовок Authorization")
 	}
-
-	const prefix = "Bearer "
-	if len(authHeader) < len(prefix) || authHeader[:len(prefix)] != prefix {
-		return "", fmt.Errorf("неверный формат заголовка Authorization")
-	}
-
-	token = authHeader[len(prefix):]
-	return token, nil
+	return "", fmt.Errorf("неверный формат заголовка Authorization")
 }
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"toppet/server/internal/app/defenitions"
+	"toppet/server/internal/app/ratelimit"
+	"toppet/server/internal/app/uhttp"
+	"toppet/server/internal/model"
+)
+
+type (
+	// RateLimitConfig общие настройки для всех маршрутов с лимитом
+	RateLimitConfig struct {
+		// Limiter - nil отключает ограничения
+		Limiter *ratelimit.Limiter
+		// TrustProxy - брать IP клиента из X-Real-IP/X-Forwarded-For (nginx из docker/)
+		TrustProxy bool
+	}
+
+	// RateLimitMiddleware ограничивает частоту запросов по политике. Внутри AuthMiddleware ключом
+	// служит ID пользователя, для анонимных запросов - IP клиента.
+	RateLimitMiddleware struct {
+		h      http.Handler
+		config RateLimitConfig
+		policy string
+	}
+)
+
+func NewRateLimitMiddleware(h http.Handler, config RateLimitConfig, policy string) *RateLimitMiddleware {
+	return &RateLimitMiddleware{h: h, config: config, policy: policy}
+}
+
+func (m *RateLimitMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if m.config.Limiter == nil {
+		m.h.ServeHTTP(w, r)
+		return
+	}
+
+	key := "ip:" + uhttp.ClientIP(r, m.config.TrustProxy)
+	if userID, ok := r.Context().Value(defenitions.UserID).(model.UserID); ok {
+		key = fmt.Sprintf("user:%d", userID)
+	}
+
+	result, err := m.config.Limiter.Allow(r.Context(), m.policy, key)
+	if err != nil {
+		// Недоступность хранилища лимитов не должна останавливать API
+		log.Printf("[RateLimit] policy=%s key=%s: %v", m.policy, key, err)
+		m.h.ServeHTTP(w, r)
+		return
+	}
+	if !result.Allowed {
+		uhttp.HandleError(w, uhttp.NewRateLimitedError("too many requests", result.RetryAfter))
+		return
+	}
+
+	m.h.ServeHTTP(w, r)
+}
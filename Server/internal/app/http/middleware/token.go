@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+)
+
+// QueryTokenMiddleware переносит access токен из ?accessToken= в заголовок Authorization, поэтому
+// AuthMiddleware и необязательная авторизация видят его как обычный Bearer токен. App подключает
+// его только при AUTH_QUERY_TOKEN_ENABLED: токены в URL попадают в логи nginx и историю браузера.
+type QueryTokenMiddleware struct {
+	h http.Handler
+}
+
+func NewQueryTokenMiddleware(h http.Handler) *QueryTokenMiddleware {
+	return &QueryTokenMiddleware{h: h}
+}
+
+func (m *QueryTokenMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Для WebSocket действует своя настройка WS_QUERY_TOKEN_ENABLED, ее проверяет обработчик чата
+	if strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		m.h.ServeHTTP(w, r)
+		return
+	}
+
+	query := r.URL.Query()
+	if token := query.Get("accessToken"); token != "" {
+		r = r.Clone(r.Context())
+		r.Header.Set("Authorization", "Bearer "+token)
+		query.Del("accessToken")
+		r.URL.RawQuery = query.Encode()
+	}
+	m.h.ServeHTTP(w, r)
+}
+
+// ExtractAccessToken возвращает access токен из заголовка Authorization: Bearer.
+// Пустая строка - токена нет.
+func ExtractAccessToken(r *http.Request) string {
+	if r == nil {
+		return ""
+	}
+
+	authHeader := r.Header.Get("Authorization")
+	const prefix = "Bearer "
+	if !strings.HasPrefix(authHeader, prefix) {
+		return ""
+	}
+
+	return strings.TrimSpace(authHeader[len(prefix):])
+}
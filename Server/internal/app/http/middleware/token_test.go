@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestQueryTokenMiddleware(t *testing.T) {
+	var token, query string
+	h := NewQueryTokenMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, query = ExtractAccessToken(r), r.URL.RawQuery
+	}))
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/contests?accessToken=abc&page=2", nil))
+	if token != "abc" || query != "page=2" {
+		t.Errorf("Expected token from query and stripped URL, got %q, %q", token, query)
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/api/contests", nil)
+	r.Header.Set("Authorization", "Bearer header")
+	h.ServeHTTP(httptest.NewRecorder(), r)
+	if token != "header" {
+		t.Errorf("Expected token from header, got %q", token)
+	}
+
+	ws := httptest.NewRequest(http.MethodGet, "/api/ws?accessToken=abc", nil)
+	ws.Header.Set("Upgrade", "websocket")
+	h.ServeHTTP(httptest.NewRecorder(), ws)
+	if token != "" || query != "accessToken=abc" {
+		t.Errorf("Expected WebSocket request to be left as is, got %q, %q", token, query)
+	}
+}
+
+func TestExtractAccessToken_IgnoresQuery(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/api/contests?accessToken=abc", nil)
+	if token := ExtractAccessToken(r); token != "" {
+		t.Errorf("Expected query token to be ignored without QueryTokenMiddleware, got %q", token)
+	}
+}
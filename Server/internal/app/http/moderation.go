@@ -0,0 +1,89 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"toppet/server/internal/app/defenitions"
+	"toppet/server/internal/app/uhttp"
+	"toppet/server/internal/model"
+)
+
+type (
+	serviceModeration interface {
+		Moderate(ctx context.Context, actorID model.UserID, action model.ModerationActionType, targetType model.ModerationTargetType, targetID string, reason string) (*model.ModerationAction, error)
+		ListModerationActions(ctx context.Context, actorID model.UserID, limit, offset int) ([]*model.ModerationAction, int64, error)
+	}
+
+	// ModerationHandler действия модераторов: POST/GET /api/moderation/actions
+	ModerationHandler struct {
+		name    string
+		service serviceModeration
+	}
+
+	moderateRequest struct {
+		Action     model.ModerationActionType `json:"action"`
+		TargetType model.ModerationTargetType `json:"target_type"`
+		TargetID   string                     `json:"target_id"`
+		Reason     string                     `json:"reason"`
+	}
+)
+
+func NewModerationHandler(name string, service serviceModeration) *ModerationHandler {
+	return &ModerationHandler{name: name, service: service}
+}
+
+func (h *ModerationHandler) CreateAction(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(defenitions.UserID).(model.UserID)
+
+	var req moderateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		uhttp.HandleError(w, uhttp.NewBadRequestError("invalid request body", err))
+		return
+	}
+
+	action, err := h.service.Moderate(r.Context(), userID, req.Action, req.TargetType, req.TargetID, req.Reason)
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	if err := uhttp.SendSuccess(w, action); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
+
+func (h *ModerationHandler) ListActions(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(defenitions.UserID).(model.UserID)
+
+	limit := 50
+	if l := r.URL.Query().Get("limit"); l != "" {
+		if n, err := strconv.Atoi(l); err == nil {
+			limit = n
+		}
+	}
+	offset := 0
+	if o := r.URL.Query().Get("offset"); o != "" {
+		if n, err := strconv.Atoi(o); err == nil {
+			offset = n
+		}
+	}
+
+	actions, total, err := h.service.ListModerationActions(r.Context(), userID, limit, offset)
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	type resp struct {
+		Items []*model.ModerationAction `json:"items"`
+		Total int64                     `json:"total"`
+	}
+	if err := uhttp.SendSuccess(w, resp{Items: actions, Total: total}); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
@@ -0,0 +1,78 @@
+package http
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"time"
+)
+
+var errMP4NoDuration = errors.New("mp4: movie header not found")
+
+// mp4Duration читает длительность ролика из заголовка mvhd внутри moov (ISO BMFF: MP4, MOV).
+// Файл целиком не читается: просматриваются только заголовки боксов.
+func mp4Duration(r io.ReaderAt, size int64) (time.Duration, error) {
+	moovOffset, moovSize, err := findMP4Box(r, 0, size, "moov")
+	if err != nil {
+		return 0, err
+	}
+	mvhdOffset, mvhdSize, err := findMP4Box(r, moovOffset, moovOffset+moovSize, "mvhd")
+	if err != nil {
+		return 0, err
+	}
+
+	// version(1) + flags(3), далее поля версии 0 (32 бита) или 1 (64 бита)
+	buf := make([]byte, 32)
+	n, err := r.ReadAt(buf[:min(int64(len(buf)), mvhdSize)], mvhdOffset)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return 0, err
+	}
+	buf = buf[:n]
+
+	var timescale, duration uint64
+	switch {
+	case len(buf) >= 20 && buf[0] == 0:
+		timescale = uint64(binary.BigEndian.Uint32(buf[12:16]))
+		duration = uint64(binary.BigEndian.Uint32(buf[16:20]))
+	case len(buf) >= 32 && buf[0] == 1:
+		timescale = uint64(binary.BigEndian.Uint32(buf[20:24]))
+		duration = binary.BigEndian.Uint64(buf[24:32])
+	default:
+		return 0, errMP4NoDuration
+	}
+	if timescale == 0 {
+		return 0, errMP4NoDuration
+	}
+	return time.Duration(float64(duration) / float64(timescale) * float64(time.Second)), nil
+}
+
+// findMP4Box ищет бокс с типом boxType в диапазоне [start, end) и возвращает смещение и размер его содержимого
+func findMP4Box(r io.ReaderAt, start, end int64, boxType string) (int64, int64, error) {
+	header := make([]byte, 16)
+	for offset := start; offset+8 <= end; {
+		if _, err := r.ReadAt(header[:8], offset); err != nil {
+			return 0, 0, err
+		}
+		boxSize := int64(binary.BigEndian.Uint32(header[:4]))
+		headerSize := int64(8)
+		switch boxSize {
+		case 0:
+			// Бокс продолжается до конца файла
+			boxSize = end - offset
+		case 1:
+			if _, err := r.ReadAt(header[8:16], offset+8); err != nil {
+				return 0, 0, err
+			}
+			boxSize = int64(binary.BigEndian.Uint64(header[8:16]))
+			headerSize = 16
+		}
+		if boxSize < headerSize || offset+boxSize > end {
+			return 0, 0, errMP4NoDuration
+		}
+		if string(header[4:8]) == boxType {
+			return offset + headerSize, boxSize - headerSize, nil
+		}
+		offset += boxSize
+	}
+	return 0, 0, errMP4NoDuration
+}
@@ -0,0 +1,50 @@
+package http
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+)
+
+func mp4Box(boxType string, payload ...[]byte) []byte {
+	body := bytes.Join(payload, nil)
+	box := make([]byte, 8, 8+len(body))
+	binary.BigEndian.PutUint32(box[:4], uint32(8+len(body)))
+	copy(box[4:8], boxType)
+	return append(box, body...)
+}
+
+func TestMP4Duration(t *testing.T) {
+	mvhdV0 := make([]byte, 100)
+	binary.BigEndian.PutUint32(mvhdV0[12:16], 1000)
+	binary.BigEndian.PutUint32(mvhdV0[16:20], 42500)
+
+	mvhdV1 := make([]byte, 112)
+	mvhdV1[0] = 1
+	binary.BigEndian.PutUint32(mvhdV1[20:24], 600)
+	binary.BigEndian.PutUint64(mvhdV1[24:32], 600*90)
+
+	tests := []struct {
+		name    string
+		file    []byte
+		want    time.Duration
+		wantErr bool
+	}{
+		{"version 0", bytes.Join([][]byte{mp4Box("ftyp", []byte("isom")), mp4Box("moov", mp4Box("mvhd", mvhdV0))}, nil), 42500 * time.Millisecond, false},
+		{"version 1 after mdat", bytes.Join([][]byte{mp4Box("ftyp"), mp4Box("mdat", make([]byte, 64)), mp4Box("moov", mp4Box("trak"), mp4Box("mvhd", mvhdV1))}, nil), 90 * time.Second, false},
+		{"no moov", mp4Box("ftyp", []byte("isom")), 0, true},
+		{"not mp4", []byte("definitely not a video file"), 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := mp4Duration(bytes.NewReader(tt.file), int64(len(tt.file)))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"toppet/server/internal/app/defenitions"
+	"toppet/server/internal/app/uhttp"
+	"toppet/server/internal/model"
+)
+
+type (
+	serviceNotifications interface {
+		ListNotifications(ctx context.Context, userID model.UserID, unreadOnly bool, limit, offset int) ([]*model.Notification, int64, int64, error)
+		CountUnreadNotifications(ctx context.Context, userID model.UserID) (int64, error)
+		MarkNotificationRead(ctx context.Context, userID model.UserID, notificationID string) (int64, error)
+		MarkAllNotificationsRead(ctx context.Context, userID model.UserID) (int64, error)
+	}
+
+	// NotificationHandler входящие уведомления текущего пользователя: /api/notifications
+	NotificationHandler struct {
+		name    string
+		service serviceNotifications
+	}
+
+	unreadCountResponse struct {
+		UnreadCount int64 `json:"unread_count"`
+	}
+)
+
+func NewNotificationHandler(name string, service serviceNotifications) *NotificationHandler {
+	return &NotificationHandler{name: name, service: service}
+}
+
+// List уведомления, новые первыми; ?unread=true - только непрочитанные
+func (h *NotificationHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(defenitions.UserID).(model.UserID)
+
+	limit := 20
+	if l := r.URL.Query().Get("limit"); l != "" {
+		if n, err := strconv.Atoi(l); err == nil {
+			limit = n
+		}
+	}
+	offset := 0
+	if o := r.URL.Query().Get("offset"); o != "" {
+		if n, err := strconv.Atoi(o); err == nil {
+			offset = n
+		}
+	}
+	unreadOnly := r.URL.Query().Get("unread") == "true"
+
+	notifications, total, unread, err := h.service.ListNotifications(r.Context(), userID, unreadOnly, limit, offset)
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	type resp struct {
+		Items       []*model.Notification `json:"items"`
+		Total       int64                 `json:"total"`
+		UnreadCount int64                 `json:"unread_count"`
+	}
+	if err := uhttp.SendSuccess(w, resp{Items: notifications, Total: total, UnreadCount: unread}); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
+
+func (h *NotificationHandler) UnreadCount(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(defenitions.UserID).(model.UserID)
+
+	unread, err := h.service.CountUnreadNotifications(r.Context(), userID)
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	if err := uhttp.SendSuccess(w, unreadCountResponse{UnreadCount: unread}); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
+
+func (h *NotificationHandler) MarkRead(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(defenitions.UserID).(model.UserID)
+	notificationID := r.PathValue("notificationId")
+	if err := uhttp.ValidateUUID(notificationID); err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	unread, err := h.service.MarkNotificationRead(r.Context(), userID, notificationID)
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	if err := uhttp.SendSuccess(w, unreadCountResponse{UnreadCount: unread}); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
+
+func (h *NotificationHandler) MarkAllRead(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(defenitions.UserID).(model.UserID)
+
+	unread, err := h.service.MarkAllNotificationsRead(r.Context(), userID)
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	if err := uhttp.SendSuccess(w, unreadCountResponse{UnreadCount: unread}); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
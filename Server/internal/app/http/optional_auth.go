@@ -3,9 +3,9 @@ package http
 import (
 	"context"
 	"net/http"
-	"net/url"
-	"strings"
 
+	"toppet/server/internal/app/defenitions"
+	"toppet/server/internal/app/http/middleware"
 	"toppet/server/internal/model"
 )
 
@@ -13,45 +13,45 @@ type serviceOptionalAuth interface {
 	Authorization(ctx context.Context, accessToken string) (*model.Claims, error)
 }
 
-func extractAccessToken(r *http.Request) string {
-	if r == nil {
-		return ""
-	}
-
-	u, err := url.Parse(r.RequestURI)
-	if err == nil {
-		if token := u.Query().Get("accessToken"); token != "" {
-			return token
-		}
-	}
-
-	authHeader := r.Header.Get("Authorization")
-	if authHeader == "" {
-		return ""
-	}
-
-	const prefix = "Bearer "
-	if !strings.HasPrefix(authHeader, prefix) {
-		return ""
+func getOptionalUserID(r *http.Request, authService serviceOptionalAuth) (model.UserID, bool, error) {
+	claims, err := getOptionalClaims(r, authService)
+	if err != nil || claims == nil {
+		return 0, false, err
 	}
 
-	return strings.TrimSpace(authHeader[len(prefix):])
+	return claims.UserID, true, nil
 }
 
-func getOptionalUserID(r *http.Request, authService serviceOptionalAuth) (model.UserID, bool, error) {
+// getOptionalClaims возвращает claims, если запрос содержит access токен, и nil для анонимного запроса
+func getOptionalClaims(r *http.Request, authService serviceOptionalAuth) (*model.Claims, error) {
 	if authService == nil {
-		return 0, false, nil
+		return nil, nil
 	}
 
-	token := extractAccessToken(r)
+	token := middleware.ExtractAccessToken(r)
 	if token == "" {
-		return 0, false, nil
+		return nil, nil
 	}
 
-	claims, err := authService.Authorization(r.Context(), token)
+	return authService.Authorization(r.Context(), token)
+}
+
+// optionalClaimsContext возвращает контекст запроса с пользователем и ролями из access токена, если он передан.
+// Недействительный токен не ошибка: запрос обрабатывается как анонимный (например, скрытый конкурс дает 404).
+func optionalClaimsContext(r *http.Request, authService serviceOptionalAuth) context.Context {
+	claims, err := getOptionalClaims(r, authService)
 	if err != nil {
-		return 0, false, err
+		return r.Context()
 	}
+	return withOptionalClaims(r.Context(), claims)
+}
 
-	return claims.UserID, true, nil
+// withOptionalClaims кладет пользователя и его роли в контекст так же, как AuthMiddleware,
+// чтобы сервис мог применить политику доступа (например, показать скрытый контент модератору)
+func withOptionalClaims(ctx context.Context, claims *model.Claims) context.Context {
+	if claims == nil {
+		return ctx
+	}
+	ctx = context.WithValue(ctx, defenitions.UserID, claims.UserID)
+	return context.WithValue(ctx, defenitions.Roles, claims.Roles)
 }
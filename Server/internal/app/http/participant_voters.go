@@ -11,7 +11,7 @@ import (
 
 type (
 	serviceParticipantVoters interface {
-		ListVotersForParticipant(ctx context.Context, contestID model.ContestID, participantID model.ParticipantID, userID model.UserID) ([]*model.VoterInfo, error)
+		ListVotersForParticipant(ctx context.Context, contestID model.ContestID, categoryID model.CategoryID, participantID model.ParticipantID, userID model.UserID) ([]*model.VoterInfo, error)
 	}
 
 	ParticipantVotersHandler struct {
@@ -39,7 +39,9 @@ func (h *ParticipantVotersHandler) ServeHTTP(w http.ResponseWriter, r *http.Requ
 
 	userID := r.Context().Value(defenitions.UserID).(model.UserID)
 
-	voters, err := h.service.ListVotersForParticipant(r.Context(), contestID, participantID, userID)
+	categoryID := model.CategoryID(r.URL.Query().Get("category_id"))
+
+	voters, err := h.service.ListVotersForParticipant(r.Context(), contestID, categoryID, participantID, userID)
 	if err != nil {
 		uhttp.HandleError(w, err)
 		return
@@ -0,0 +1,161 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"toppet/server/internal/app/defenitions"
+	"toppet/server/internal/app/uhttp"
+	"toppet/server/internal/model"
+)
+
+type (
+	servicePets interface {
+		CreatePet(ctx context.Context, userID model.UserID, pet *model.Pet) (*model.Pet, error)
+		ListUserPets(ctx context.Context, userID model.UserID) ([]*model.Pet, error)
+		GetPet(ctx context.Context, petID model.PetID) (*model.Pet, error)
+		UpdatePet(ctx context.Context, petID model.PetID, userID model.UserID, update *model.Pet) (*model.Pet, error)
+		DeletePet(ctx context.Context, petID model.PetID, userID model.UserID) error
+		DeletePetPhoto(ctx context.Context, petID model.PetID, userID model.UserID, photoID string) error
+	}
+
+	// PetHandler профили питомцев: /api/pets
+	PetHandler struct {
+		name    string
+		service servicePets
+	}
+
+	petRequest struct {
+		Name      string       `json:"name"`
+		Species   string       `json:"species"`
+		Breed     string       `json:"breed"`
+		Sex       model.PetSex `json:"sex"`
+		BirthDate string       `json:"birth_date"`
+		Bio       string       `json:"bio"`
+	}
+)
+
+func NewPetHandler(name string, service servicePets) *PetHandler {
+	return &PetHandler{name: name, service: service}
+}
+
+func (req petRequest) toModel() *model.Pet {
+	return &model.Pet{
+		Name: req.Name,
+		PetAttributes: model.PetAttributes{
+			Species:   req.Species,
+			Breed:     req.Breed,
+			Sex:       req.Sex,
+			BirthDate: req.BirthDate,
+		},
+		Bio: req.Bio,
+	}
+}
+
+func (h *PetHandler) ListMine(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(defenitions.UserID).(model.UserID)
+
+	pets, err := h.service.ListUserPets(r.Context(), userID)
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	type resp struct {
+		Items []*model.Pet `json:"items"`
+		Total int64        `json:"total"`
+	}
+	if err := uhttp.SendSuccess(w, resp{Items: pets, Total: int64(len(pets))}); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
+
+func (h *PetHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(defenitions.UserID).(model.UserID)
+
+	var req petRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		uhttp.HandleError(w, uhttp.NewBadRequestError("invalid request body", err))
+		return
+	}
+
+	pet, err := h.service.CreatePet(r.Context(), userID, req.toModel())
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	if err := uhttp.SendSuccess(w, pet); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
+
+func (h *PetHandler) Get(w http.ResponseWriter, r *http.Request) {
+	petID := model.PetID(r.PathValue("petId"))
+
+	pet, err := h.service.GetPet(r.Context(), petID)
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	if err := uhttp.SendSuccess(w, pet); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
+
+func (h *PetHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(defenitions.UserID).(model.UserID)
+	petID := model.PetID(r.PathValue("petId"))
+
+	var req petRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		uhttp.HandleError(w, uhttp.NewBadRequestError("invalid request body", err))
+		return
+	}
+
+	pet, err := h.service.UpdatePet(r.Context(), petID, userID, req.toModel())
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	if err := uhttp.SendSuccess(w, pet); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
+
+func (h *PetHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(defenitions.UserID).(model.UserID)
+	petID := model.PetID(r.PathValue("petId"))
+
+	if err := h.service.DeletePet(r.Context(), petID, userID); err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	if err := uhttp.SendSuccess(w, map[string]bool{"deleted": true}); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
+
+func (h *PetHandler) DeletePhoto(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(defenitions.UserID).(model.UserID)
+	petID := model.PetID(r.PathValue("petId"))
+
+	if err := h.service.DeletePetPhoto(r.Context(), petID, userID, r.PathValue("photoId")); err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	if err := uhttp.SendSuccess(w, map[string]bool{"deleted": true}); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
@@ -0,0 +1,60 @@
+package http
+
+import (
+	"context"
+	"net/http"
+
+	"toppet/server/internal/app/uhttp"
+	"toppet/server/internal/model"
+)
+
+type (
+	serviceSpecies interface {
+		ListSpecies(ctx context.Context) ([]*model.Species, error)
+		ListBreeds(ctx context.Context, species string) ([]*model.Breed, error)
+	}
+
+	// SpeciesHandler справочник видов и пород: /api/species
+	SpeciesHandler struct {
+		name    string
+		service serviceSpecies
+	}
+)
+
+func NewSpeciesHandler(name string, service serviceSpecies) *SpeciesHandler {
+	return &SpeciesHandler{name: name, service: service}
+}
+
+func (h *SpeciesHandler) ListSpecies(w http.ResponseWriter, r *http.Request) {
+	species, err := h.service.ListSpecies(r.Context())
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	type resp struct {
+		Items []*model.Species `json:"items"`
+		Total int64            `json:"total"`
+	}
+	if err := uhttp.SendSuccess(w, resp{Items: species, Total: int64(len(species))}); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
+
+func (h *SpeciesHandler) ListBreeds(w http.ResponseWriter, r *http.Request) {
+	breeds, err := h.service.ListBreeds(r.Context(), r.PathValue("species"))
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	type resp struct {
+		Items []*model.Breed `json:"items"`
+		Total int64          `json:"total"`
+	}
+	if err := uhttp.SendSuccess(w, resp{Items: breeds, Total: int64(len(breeds))}); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
@@ -0,0 +1,126 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"toppet/server/internal/app/defenitions"
+	"toppet/server/internal/app/uhttp"
+	"toppet/server/internal/model"
+)
+
+type (
+	serviceTelegram interface {
+		GetTelegramStatus(ctx context.Context, userID model.UserID) (*model.TelegramStatus, error)
+		CreateTelegramLink(ctx context.Context, userID model.UserID) (*model.TelegramLink, error)
+		UnlinkTelegram(ctx context.Context, userID model.UserID) error
+		AnnounceContestTelegram(ctx context.Context, contestID model.ContestID, userID model.UserID, text string) (*model.TelegramAnnouncement, error)
+		ListTelegramAnnouncements(ctx context.Context, contestID model.ContestID, userID model.UserID) ([]*model.TelegramAnnouncement, error)
+	}
+
+	// TelegramHandler привязка Telegram (/api/auth/me/telegram)
+	// и анонсы конкурса в канале (/api/contests/{contestId}/telegram/announcements)
+	TelegramHandler struct {
+		name    string
+		service serviceTelegram
+	}
+
+	telegramAnnouncementRequest struct {
+		Text string `json:"text"`
+	}
+)
+
+func NewTelegramHandler(name string, service serviceTelegram) *TelegramHandler {
+	return &TelegramHandler{name: name, service: service}
+}
+
+func (h *TelegramHandler) GetStatus(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(defenitions.UserID).(model.UserID)
+
+	status, err := h.service.GetTelegramStatus(r.Context(), userID)
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	if err := uhttp.SendSuccess(w, status); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
+
+// CreateLink выдает ссылку на бота; аккаунт привязывается после нажатия Start в Telegram
+func (h *TelegramHandler) CreateLink(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(defenitions.UserID).(model.UserID)
+
+	link, err := h.service.CreateTelegramLink(r.Context(), userID)
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	if err := uhttp.SendSuccess(w, link); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
+
+func (h *TelegramHandler) Unlink(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(defenitions.UserID).(model.UserID)
+
+	if err := h.service.UnlinkTelegram(r.Context(), userID); err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	if err := uhttp.SendSuccess(w, map[string]bool{"unlinked": true}); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
+
+func (h *TelegramHandler) ListAnnouncements(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(defenitions.UserID).(model.UserID)
+	contestID := model.ContestID(r.PathValue("contestId"))
+
+	announcements, err := h.service.ListTelegramAnnouncements(r.Context(), contestID, userID)
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	type resp struct {
+		Items []*model.TelegramAnnouncement `json:"items"`
+		Total int64                         `json:"total"`
+	}
+	if err := uhttp.SendSuccess(w, resp{Items: announcements, Total: int64(len(announcements))}); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
+
+// Announce ставит анонс в очередь отправки в канал; тело запроса необязательно
+func (h *TelegramHandler) Announce(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(defenitions.UserID).(model.UserID)
+	contestID := model.ContestID(r.PathValue("contestId"))
+
+	var req telegramAnnouncementRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		uhttp.HandleError(w, uhttp.NewBadRequestError("invalid request body", err))
+		return
+	}
+
+	announcement, err := h.service.AnnounceContestTelegram(r.Context(), contestID, userID, req.Text)
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	if err := uhttp.SendSuccess(w, announcement); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
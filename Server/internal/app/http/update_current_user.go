@@ -3,16 +3,25 @@ package http
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"slices"
+	"strings"
 
 	"toppet/server/internal/app/defenitions"
 	"toppet/server/internal/app/uhttp"
 	"toppet/server/internal/model"
 )
 
+// Лимиты профиля в байтах UTF-8, как их считают валидаторы uhttp
+const (
+	maxUserNameLength = 200
+	maxUserBioLength  = 2000
+)
+
 type (
 	serviceUpdateCurrentUser interface {
-		UpdateUserName(ctx context.Context, userID model.UserID, name string) (*model.User, error)
+		UpdateCurrentUser(ctx context.Context, userID model.UserID, update *model.UserUpdate) (*model.User, error)
 	}
 
 	UpdateCurrentUserHandler struct {
@@ -29,7 +38,9 @@ func (h *UpdateCurrentUserHandler) ServeHTTP(w http.ResponseWriter, r *http.Requ
 	userID := r.Context().Value(defenitions.UserID).(model.UserID)
 
 	var req struct {
-		Name *string `json:"name"`
+		Name   *string `json:"name"`
+		Bio    *string `json:"bio"`
+		Locale *string `json:"locale"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -37,12 +48,38 @@ func (h *UpdateCurrentUserHandler) ServeHTTP(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	if req.Name == nil {
-		uhttp.HandleError(w, uhttp.NewBadRequestError("name is required", nil))
+	if req.Name == nil && req.Bio == nil && req.Locale == nil {
+		uhttp.HandleError(w, uhttp.NewBadRequestError("name, bio or locale is required", nil))
 		return
 	}
 
-	updated, err := h.service.UpdateUserName(r.Context(), userID, *req.Name)
+	update := &model.UserUpdate{}
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if err := uhttp.ValidateString(name, "name", true, maxUserNameLength); err != nil {
+			uhttp.HandleError(w, err)
+			return
+		}
+		update.Name = &name
+	}
+	if req.Bio != nil {
+		bio := strings.TrimSpace(*req.Bio)
+		if err := uhttp.ValidateString(bio, "bio", false, maxUserBioLength); err != nil {
+			uhttp.HandleError(w, err)
+			return
+		}
+		update.Bio = &bio
+	}
+	if req.Locale != nil {
+		if err := uhttp.ValidateString(*req.Locale, "locale", true, 0, validateLocale); err != nil {
+			uhttp.HandleError(w, err)
+			return
+		}
+		locale := model.Locale(*req.Locale)
+		update.Locale = &locale
+	}
+
+	updated, err := h.service.UpdateCurrentUser(r.Context(), userID, update)
 	if err != nil {
 		uhttp.HandleError(w, err)
 		return
@@ -53,3 +90,12 @@ func (h *UpdateCurrentUserHandler) ServeHTTP(w http.ResponseWriter, r *http.Requ
 		return
 	}
 }
+
+// validateLocale - uhttp.Validator для языка профиля
+func validateLocale(value interface{}) error {
+	locale, _ := value.(string)
+	if !slices.Contains(model.SupportedLocales, model.Locale(locale)) {
+		return uhttp.NewBadRequestError(fmt.Sprintf("unsupported locale %q", locale), nil)
+	}
+	return nil
+}
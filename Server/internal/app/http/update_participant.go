@@ -14,7 +14,7 @@ import (
 type (
 	serviceUpdateParticipant interface {
 		GetParticipant(ctx context.Context, participantID model.ParticipantID) (*model.Participant, error)
-		UpdateParticipant(ctx context.Context, participantID model.ParticipantID, userID model.UserID, petName, petDescription string) (*model.Participant, error)
+		UpdateParticipant(ctx context.Context, participantID model.ParticipantID, userID model.UserID, petName, petDescription string, attrs model.PetAttributes) (*model.Participant, error)
 	}
 
 	UpdateParticipantHandler struct {
@@ -42,6 +42,10 @@ func (h *UpdateParticipantHandler) ServeHTTP(w http.ResponseWriter, r *http.Requ
 	var req struct {
 		PetName        *string `json:"pet_name"`
 		PetDescription *string `json:"pet_description"`
+		Species        *string `json:"species"`
+		Breed          *string `json:"breed"`
+		Sex            *string `json:"sex"`
+		BirthDate      *string `json:"birth_date"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -55,8 +59,8 @@ func (h *UpdateParticipantHandler) ServeHTTP(w http.ResponseWriter, r *http.Requ
 	// For now, we'll require both fields or get them from service
 	// Actually, service should handle getting current values if fields are empty
 	// Let's require at least one field to be provided
-	if req.PetName == nil && req.PetDescription == nil {
-		log.Printf("[UpdateParticipantHandler] ERROR: At least one field (pet_name or pet_description) must be provided")
+	if req.PetName == nil && req.PetDescription == nil && req.Species == nil && req.Breed == nil && req.Sex == nil && req.BirthDate == nil {
+		log.Printf("[UpdateParticipantHandler] ERROR: At least one field must be provided")
 		uhttp.HandleError(w, uhttp.NewBadRequestError("at least one field must be provided", nil))
 		return
 	}
@@ -80,6 +84,21 @@ func (h *UpdateParticipantHandler) ServeHTTP(w http.ResponseWriter, r *http.Requ
 		petDescription = *req.PetDescription
 	}
 
+	// Признаки питомца: переданное поле заменяет текущее, пустая строка очищает его
+	attrs := currentParticipant.PetAttributes
+	if req.Species != nil {
+		attrs.Species = *req.Species
+	}
+	if req.Breed != nil {
+		attrs.Breed = *req.Breed
+	}
+	if req.Sex != nil {
+		attrs.Sex = model.PetSex(*req.Sex)
+	}
+	if req.BirthDate != nil {
+		attrs.BirthDate = *req.BirthDate
+	}
+
 	// Require pet_name to be non-empty
 	if petName == "" {
 		log.Printf("[UpdateParticipantHandler] ERROR: pet_name cannot be empty")
@@ -89,7 +108,7 @@ func (h *UpdateParticipantHandler) ServeHTTP(w http.ResponseWriter, r *http.Requ
 
 	log.Printf("[UpdateParticipantHandler] Request data: pet_name=%s, pet_description=%s", petName, petDescription)
 
-	participant, err := h.service.UpdateParticipant(r.Context(), participantID, userID, petName, petDescription)
+	participant, err := h.service.UpdateParticipant(r.Context(), participantID, userID, petName, petDescription, attrs)
 	if err != nil {
 		log.Printf("[UpdateParticipantHandler] ERROR: Failed to update participant: %v", err)
 		uhttp.HandleError(w, err)
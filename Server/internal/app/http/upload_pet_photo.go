@@ -0,0 +1,68 @@
+package http
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	appcontext "toppet/server/internal/app/context"
+	"toppet/server/internal/app/defenitions"
+	"toppet/server/internal/app/uhttp"
+	"toppet/server/internal/model"
+	"toppet/server/internal/storage/objectstorage"
+)
+
+type (
+	serviceAddPetPhoto interface {
+		AddPetPhoto(ctx context.Context, petID model.PetID, userID model.UserID, url string, upload model.MediaUpload) (*model.PetPhoto, error)
+	}
+
+	UploadPetPhotoHandler struct {
+		name     string
+		service  serviceAddPetPhoto
+		uploader *objectstorage.Uploader
+	}
+)
+
+func NewUploadPetPhotoHandler(name string, service serviceAddPetPhoto, uploader *objectstorage.Uploader) *UploadPetPhotoHandler {
+	return &UploadPetPhotoHandler{name: name, service: service, uploader: uploader}
+}
+
+func (h *UploadPetPhotoHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(defenitions.UserID).(model.UserID)
+	petID := model.PetID(r.PathValue("petId"))
+
+	uploadCtx, cancel := appcontext.WithUploadTimeout(r.Context())
+	defer cancel()
+
+	r.Body = http.MaxBytesReader(w, r.Body, (model.MaxPhotoUploadMB+1)<<20)
+	if err := r.ParseMultipartForm(10 << 20); err != nil {
+		uhttp.HandleError(w, uhttp.NewBadRequestError("failed to parse multipart form", err))
+		return
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		uhttp.HandleError(w, uhttp.NewBadRequestError("file is required", err))
+		return
+	}
+	defer file.Close()
+
+	key := "pets/" + string(petID) + "/photos/" + uuid.New().String()
+	url, err := h.uploader.Upload(uploadCtx, key, file, header.Size, header.Header.Get("Content-Type"))
+	if err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to upload file", err))
+		return
+	}
+
+	photo, err := h.service.AddPetPhoto(uploadCtx, petID, userID, url, model.MediaUpload{Size: header.Size})
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	if err := uhttp.SendSuccess(w, photo); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
@@ -2,6 +2,7 @@ package http
 
 import (
 	"context"
+	"log"
 	"net/http"
 
 	"github.com/google/uuid"
@@ -14,7 +15,8 @@ import (
 
 type (
 	serviceAddPhoto interface {
-		AddParticipantPhoto(ctx context.Context, participantID model.ParticipantID, userID model.UserID, url string, thumbURL *string) (*model.Photo, error)
+		CheckParticipantPhotoUpload(ctx context.Context, participantID model.ParticipantID, userID model.UserID, upload model.MediaUpload) error
+		AddParticipantPhoto(ctx context.Context, participantID model.ParticipantID, userID model.UserID, url string, thumbURL *string, upload model.MediaUpload) (*model.Photo, error)
 	}
 
 	UploadPhotoHandler struct {
@@ -36,6 +38,8 @@ func (h *UploadPhotoHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	uploadCtx, cancel := appcontext.WithUploadTimeout(r.Context())
 	defer cancel()
 
+	// Лимит конкурса проверяет сервис, здесь только абсолютный предел размера запроса
+	r.Body = http.MaxBytesReader(w, r.Body, (model.MaxPhotoUploadMB+1)<<20)
 	if err := r.ParseMultipartForm(10 << 20); err != nil {
 		uhttp.HandleError(w, uhttp.NewBadRequestError("failed to parse multipart form", err))
 		return
@@ -48,6 +52,13 @@ func (h *UploadPhotoHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 	defer file.Close()
 
+	upload := model.MediaUpload{Size: header.Size}
+	// Правила конкурса проверяем до загрузки, чтобы отклоненный файл не попал в хранилище
+	if err := h.service.CheckParticipantPhotoUpload(uploadCtx, participantID, userID, upload); err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
 	key := "contests/participants/" + string(participantID) + "/photos/" + uuid.New().String()
 	url, err := h.uploader.Upload(uploadCtx, key, file, header.Size, header.Header.Get("Content-Type"))
 	if err != nil {
@@ -55,8 +66,12 @@ func (h *UploadPhotoHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	photo, err := h.service.AddParticipantPhoto(uploadCtx, participantID, userID, url, nil)
+	photo, err := h.service.AddParticipantPhoto(uploadCtx, participantID, userID, url, nil, upload)
 	if err != nil {
+		// Правила могли измениться во время загрузки: файл без записи в БД не нужен
+		if deleteErr := h.uploader.Delete(uploadCtx, url); deleteErr != nil {
+			log.Printf("[UploadPhotoHandler] failed to delete rejected upload %s: %v", url, deleteErr)
+		}
 		uhttp.HandleError(w, err)
 		return
 	}
@@ -2,6 +2,7 @@ package http
 
 import (
 	"context"
+	"log"
 	"net/http"
 
 	"github.com/google/uuid"
@@ -14,7 +15,8 @@ import (
 
 type (
 	serviceAddVideo interface {
-		AddParticipantVideo(ctx context.Context, participantID model.ParticipantID, userID model.UserID, url string) (*model.Video, error)
+		CheckParticipantVideoUpload(ctx context.Context, participantID model.ParticipantID, userID model.UserID, upload model.MediaUpload) error
+		AddParticipantVideo(ctx context.Context, participantID model.ParticipantID, userID model.UserID, url string, upload model.MediaUpload) (*model.Video, error)
 	}
 
 	UploadVideoHandler struct {
@@ -36,6 +38,8 @@ func (h *UploadVideoHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	uploadCtx, cancel := appcontext.WithUploadTimeout(r.Context())
 	defer cancel()
 
+	// Лимит конкурса проверяет сервис, здесь только абсолютный предел размера запроса
+	r.Body = http.MaxBytesReader(w, r.Body, (model.MaxVideoUploadMB+1)<<20)
 	if err := r.ParseMultipartForm(100 << 20); err != nil {
 		uhttp.HandleError(w, uhttp.NewBadRequestError("failed to parse multipart form", err))
 		return
@@ -48,6 +52,18 @@ func (h *UploadVideoHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 	defer file.Close()
 
+	// Длительность определяем по заголовку MP4; для других форматов она остается неизвестной
+	upload := model.MediaUpload{Size: header.Size}
+	if duration, err := mp4Duration(file, header.Size); err == nil {
+		upload.Duration = duration
+	}
+
+	// Правила конкурса проверяем до загрузки, чтобы отклоненный файл не попал в хранилище
+	if err := h.service.CheckParticipantVideoUpload(uploadCtx, participantID, userID, upload); err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
 	key := "contests/participants/" + string(participantID) + "/video/" + uuid.New().String()
 	url, err := h.uploader.Upload(uploadCtx, key, file, header.Size, header.Header.Get("Content-Type"))
 	if err != nil {
@@ -55,8 +71,12 @@ func (h *UploadVideoHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	video, err := h.service.AddParticipantVideo(uploadCtx, participantID, userID, url)
+	video, err := h.service.AddParticipantVideo(uploadCtx, participantID, userID, url, upload)
 	if err != nil {
+		// Правила могли измениться во время загрузки: файл без записи в БД не нужен
+		if deleteErr := h.uploader.Delete(uploadCtx, url); deleteErr != nil {
+			log.Printf("[UploadVideoHandler] failed to delete rejected upload %s: %v", url, deleteErr)
+		}
 		uhttp.HandleError(w, err)
 		return
 	}
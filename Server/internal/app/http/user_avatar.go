@@ -0,0 +1,154 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	appcontext "toppet/server/internal/app/context"
+	"toppet/server/internal/app/defenitions"
+	"toppet/server/internal/app/uhttp"
+	"toppet/server/internal/model"
+	"toppet/server/internal/storage/objectstorage"
+)
+
+type (
+	serviceUserAvatar interface {
+		SetUserAvatar(ctx context.Context, userID model.UserID, avatarURL, thumbURL string) (*model.User, error)
+		ResetUserAvatar(ctx context.Context, userID model.UserID) (*model.User, error)
+	}
+
+	// UserAvatarHandler аватар текущего пользователя: /api/auth/me/avatar
+	UserAvatarHandler struct {
+		name     string
+		service  serviceUserAvatar
+		uploader *objectstorage.Uploader
+	}
+)
+
+func NewUserAvatarHandler(name string, service serviceUserAvatar, uploader *objectstorage.Uploader) *UserAvatarHandler {
+	return &UserAvatarHandler{name: name, service: service, uploader: uploader}
+}
+
+// Upload принимает изображение (поле file) и необязательную обрезку crop_x, crop_y, crop_size
+// в пикселях исходника, сохраняет квадратный аватар и его уменьшенную копию
+func (h *UserAvatarHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(defenitions.UserID).(model.UserID)
+
+	uploadCtx, cancel := appcontext.WithUploadTimeout(r.Context())
+	defer cancel()
+
+	r.Body = http.MaxBytesReader(w, r.Body, (model.MaxAvatarUploadMB+1)<<20)
+	if err := r.ParseMultipartForm(10 << 20); err != nil {
+		uhttp.HandleError(w, uhttp.NewBadRequestError("failed to parse multipart form", err))
+		return
+	}
+
+	crop, err := parseAvatarCrop(r)
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		uhttp.HandleError(w, uhttp.NewBadRequestError("file is required", err))
+		return
+	}
+	defer file.Close()
+
+	if header.Size > model.MaxAvatarUploadMB<<20 {
+		uhttp.HandleError(w, uhttp.NewBadRequestError("avatar must be at most "+strconv.Itoa(model.MaxAvatarUploadMB)+" MB", nil))
+		return
+	}
+	data, err := io.ReadAll(file)
+	if err != nil {
+		uhttp.HandleError(w, uhttp.NewBadRequestError("failed to read file", err))
+		return
+	}
+
+	img, err := decodeAvatarImage(data)
+	if err != nil {
+		uhttp.HandleError(w, uhttp.NewBadRequestError(err.Error(), err))
+		return
+	}
+	full, thumb, err := avatarVariants(img, crop)
+	if err != nil {
+		if errors.Is(err, errAvatarCropRange) || errors.Is(err, errAvatarTooSmall) {
+			uhttp.HandleError(w, uhttp.NewBadRequestError(err.Error(), err))
+			return
+		}
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to process avatar", err))
+		return
+	}
+
+	key := "users/" + strconv.FormatInt(int64(userID), 10) + "/avatar/" + uuid.New().String()
+	avatarURL, err := h.uploader.Upload(uploadCtx, key+".jpg", bytes.NewReader(full), int64(len(full)), "image/jpeg")
+	if err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to upload file", err))
+		return
+	}
+	thumbURL, err := h.uploader.Upload(uploadCtx, key+"_thumb.jpg", bytes.NewReader(thumb), int64(len(thumb)), "image/jpeg")
+	if err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to upload file", err))
+		return
+	}
+
+	user, err := h.service.SetUserAvatar(uploadCtx, userID, avatarURL, thumbURL)
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	if err := uhttp.SendSuccess(w, user); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
+
+// Reset возвращает аватар из OAuth провайдера
+func (h *UserAvatarHandler) Reset(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(defenitions.UserID).(model.UserID)
+
+	user, err := h.service.ResetUserAvatar(r.Context(), userID)
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	if err := uhttp.SendSuccess(w, user); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
+
+// parseAvatarCrop читает обрезку из формы; без crop_size берется наибольший квадрат по центру
+func parseAvatarCrop(r *http.Request) (avatarCrop, error) {
+	if r.FormValue("crop_size") == "" {
+		return avatarCrop{}, nil
+	}
+
+	var crop avatarCrop
+	for _, field := range []struct {
+		name string
+		dst  *int
+	}{
+		{"crop_x", &crop.X},
+		{"crop_y", &crop.Y},
+		{"crop_size", &crop.Size},
+	} {
+		value, err := strconv.Atoi(r.FormValue(field.name))
+		if err != nil {
+			return avatarCrop{}, uhttp.NewBadRequestError(field.name+" must be an integer", err)
+		}
+		if err := uhttp.ValidateIntRange(value, 0, 1<<16, field.name); err != nil {
+			return avatarCrop{}, err
+		}
+		*field.dst = value
+	}
+	return crop, nil
+}
@@ -0,0 +1,57 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"toppet/server/internal/app/defenitions"
+	"toppet/server/internal/app/uhttp"
+	"toppet/server/internal/model"
+)
+
+type (
+	serviceUserRoles interface {
+		GrantRole(ctx context.Context, actorID, userID model.UserID, role model.Role) error
+		RevokeRole(ctx context.Context, actorID, userID model.UserID, role model.Role) error
+	}
+
+	// UserRolesHandler управление ролями: PUT/DELETE /api/admin/users/{userId}/roles/{role}
+	UserRolesHandler struct {
+		name    string
+		service serviceUserRoles
+	}
+)
+
+func NewUserRolesHandler(name string, service serviceUserRoles) *UserRolesHandler {
+	return &UserRolesHandler{name: name, service: service}
+}
+
+func (h *UserRolesHandler) GrantRole(w http.ResponseWriter, r *http.Request) {
+	h.changeRole(w, r, h.service.GrantRole)
+}
+
+func (h *UserRolesHandler) RevokeRole(w http.ResponseWriter, r *http.Request) {
+	h.changeRole(w, r, h.service.RevokeRole)
+}
+
+func (h *UserRolesHandler) changeRole(w http.ResponseWriter, r *http.Request, change func(ctx context.Context, actorID, userID model.UserID, role model.Role) error) {
+	actorID := r.Context().Value(defenitions.UserID).(model.UserID)
+
+	targetID, err := strconv.ParseInt(r.PathValue("userId"), 10, 64)
+	if err != nil {
+		uhttp.HandleError(w, uhttp.NewBadRequestError("invalid userId", err))
+		return
+	}
+	role := model.Role(r.PathValue("role"))
+
+	if err := change(r.Context(), actorID, model.UserID(targetID), role); err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	if err := uhttp.SendSuccess(w, map[string]any{"user_id": targetID, "role": role}); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
@@ -0,0 +1,115 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"toppet/server/internal/app/defenitions"
+	"toppet/server/internal/app/uhttp"
+	"toppet/server/internal/model"
+)
+
+type (
+	serviceUsers interface {
+		GetPublicUserProfile(ctx context.Context, userID model.UserID) (*model.PublicUserProfile, error)
+		GetPrivacySettings(ctx context.Context, userID model.UserID) (*model.PrivacySettings, error)
+		UpdatePrivacySettings(ctx context.Context, userID model.UserID, settings *model.PrivacySettings) (*model.PrivacySettings, error)
+	}
+
+	// UserHandler публичные профили (/api/users/{userId}) и настройки приватности (/api/auth/me/privacy)
+	UserHandler struct {
+		name        string
+		service     serviceUsers
+		authService serviceOptionalAuth
+	}
+
+	privacySettingsRequest struct {
+		ShowContests *bool `json:"show_contests"`
+		ShowPets     *bool `json:"show_pets"`
+		ShowResults  *bool `json:"show_results"`
+	}
+)
+
+func NewUserHandler(name string, service serviceUsers) *UserHandler {
+	var authService serviceOptionalAuth
+	if svc, ok := service.(serviceOptionalAuth); ok {
+		authService = svc
+	}
+	return &UserHandler{name: name, service: service, authService: authService}
+}
+
+func (h *UserHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.ParseInt(r.PathValue("userId"), 10, 64)
+	if err != nil || userID <= 0 {
+		uhttp.HandleError(w, uhttp.NewBadRequestError("invalid user id", err))
+		return
+	}
+
+	// Владелец профиля и персонал видят разделы, скрытые настройками приватности
+	ctx := optionalClaimsContext(r, h.authService)
+
+	profile, err := h.service.GetPublicUserProfile(ctx, model.UserID(userID))
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	if err := uhttp.SendSuccess(w, profile); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
+
+func (h *UserHandler) GetPrivacy(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(defenitions.UserID).(model.UserID)
+
+	settings, err := h.service.GetPrivacySettings(r.Context(), userID)
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	if err := uhttp.SendSuccess(w, settings); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
+
+// UpdatePrivacy меняет только переданные поля, остальные сохраняют текущие значения
+func (h *UserHandler) UpdatePrivacy(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(defenitions.UserID).(model.UserID)
+
+	var req privacySettingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		uhttp.HandleError(w, uhttp.NewBadRequestError("invalid request body", err))
+		return
+	}
+
+	settings, err := h.service.GetPrivacySettings(r.Context(), userID)
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+	if req.ShowContests != nil {
+		settings.ShowContests = *req.ShowContests
+	}
+	if req.ShowPets != nil {
+		settings.ShowPets = *req.ShowPets
+	}
+	if req.ShowResults != nil {
+		settings.ShowResults = *req.ShowResults
+	}
+
+	updated, err := h.service.UpdatePrivacySettings(r.Context(), userID, settings)
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	if err := uhttp.SendSuccess(w, updated); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
@@ -13,50 +13,76 @@ import (
 
 type (
 	serviceVote interface {
-		Vote(ctx context.Context, contestID model.ContestID, participantID model.ParticipantID, userID model.UserID) (*model.Vote, error)
-		GetUserVote(ctx context.Context, contestID model.ContestID, userID model.UserID) (*model.Vote, error)
-		Unvote(ctx context.Context, contestID model.ContestID, userID model.UserID) (model.ParticipantID, error)
+		Vote(ctx context.Context, contestID model.ContestID, categoryID model.CategoryID, userID model.UserID, choices []model.BallotChoice, client model.VoteClient) (*model.Vote, error)
+		GetUserVote(ctx context.Context, contestID model.ContestID, categoryID model.CategoryID, userID model.UserID) (*model.Vote, error)
+		GetVoteEligibility(ctx context.Context, contestID model.ContestID, categoryID model.CategoryID, userID model.UserID) (*model.VoteEligibility, error)
+		Unvote(ctx context.Context, contestID model.ContestID, categoryID model.CategoryID, userID model.UserID) (model.ParticipantID, error)
 	}
 
 	VoteHandler struct {
 		name    string
 		service serviceVote
 		authService serviceOptionalAuth
+		opts    VoteHandlerOptions
+	}
+
+	// VoteHandlerOptions настройки сбора метаданных голоса
+	VoteHandlerOptions struct {
+		// TrustProxy брать IP клиента из X-Real-IP / X-Forwarded-For
+		TrustProxy bool
 	}
 )
 
-func NewVoteHandler(name string, service serviceVote) *VoteHandler {
+func NewVoteHandler(name string, service serviceVote, opts VoteHandlerOptions) *VoteHandler {
 	var authService serviceOptionalAuth
 	if svc, ok := service.(serviceOptionalAuth); ok {
 		authService = svc
 	}
-	return &VoteHandler{name: name, service: service, authService: authService}
+	return &VoteHandler{name: name, service: service, authService: authService, opts: opts}
 }
 
 func (h *VoteHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	contestID := model.ContestID(r.PathValue("contestId"))
+	// Номинация: ?category_id= (для POST также поле тела), без нее - главный приз
+	categoryID := model.CategoryID(r.URL.Query().Get("category_id"))
 
 	if r.Method == http.MethodGet {
-		// Get user vote (optional auth)
+		// Get user vote and eligibility (optional auth)
+		type resp struct {
+			CategoryID    string               `json:"category_id,omitempty"`
+			ParticipantID string               `json:"participant_id"`
+			Choices       []model.BallotChoice `json:"choices,omitempty"`
+			Voided        bool                 `json:"voided,omitempty"`
+			Eligible      bool                 `json:"eligible"`
+			Reason        string               `json:"reason,omitempty"`
+		}
+
 		userIDVal := r.Context().Value(defenitions.UserID)
 		if userIDVal == nil {
 			optionalUserID, hasUser, authErr := getOptionalUserID(r, h.authService)
 			if authErr != nil || !hasUser {
-				w.WriteHeader(http.StatusNoContent)
+				if err := uhttp.SendSuccess(w, resp{CategoryID: string(categoryID), Reason: model.VoteIneligibleNotAuthenticated}); err != nil {
+					uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+				}
 				return
 			}
 			userIDVal = optionalUserID
 		}
 		userID := userIDVal.(model.UserID)
-		vote, err := h.service.GetUserVote(r.Context(), contestID, userID)
+
+		eligibility, err := h.service.GetVoteEligibility(r.Context(), contestID, categoryID, userID)
 		if err != nil {
-			w.WriteHeader(http.StatusNoContent)
+			uhttp.HandleError(w, err)
 			return
 		}
-		type resp struct {
-			ParticipantID string `json:"participant_id"`
+
+		result := resp{CategoryID: string(categoryID), Eligible: eligibility.Eligible, Reason: eligibility.Reason}
+		if vote, err := h.service.GetUserVote(r.Context(), contestID, categoryID, userID); err == nil && vote != nil {
+			result.ParticipantID = string(vote.ParticipantID)
+			result.Choices = vote.Choices
+			result.Voided = vote.VoidedAt != nil
 		}
-		if err := uhttp.SendSuccess(w, resp{ParticipantID: string(vote.ParticipantID)}); err != nil {
+		if err := uhttp.SendSuccess(w, result); err != nil {
 			uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
 		}
 		return
@@ -64,7 +90,7 @@ func (h *VoteHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	if r.Method == http.MethodDelete {
 		userID := r.Context().Value(defenitions.UserID).(model.UserID)
-		participantID, err := h.service.Unvote(r.Context(), contestID, userID)
+		participantID, err := h.service.Unvote(r.Context(), contestID, categoryID, userID)
 		if err != nil {
 			if errors.Is(err, model.ErrorNotFound) {
 				w.WriteHeader(http.StatusNoContent)
@@ -89,8 +115,13 @@ func (h *VoteHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// POST vote
 	userID := r.Context().Value(defenitions.UserID).(model.UserID)
 
+	// Бюллетень по режиму конкурса: participant_id (single), participant_ids (approval, ranked - по местам),
+	// ratings (stars)
 	var req struct {
-		ParticipantID string `json:"participant_id"`
+		CategoryID     model.CategoryID      `json:"category_id"`
+		ParticipantID  string                `json:"participant_id"`
+		ParticipantIDs []model.ParticipantID `json:"participant_ids"`
+		Ratings        []model.BallotChoice  `json:"ratings"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -98,16 +129,38 @@ func (h *VoteHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	vote, err := h.service.Vote(r.Context(), contestID, model.ParticipantID(req.ParticipantID), userID)
+	if req.CategoryID != "" {
+		categoryID = req.CategoryID
+	}
+
+	var choices []model.BallotChoice
+	switch {
+	case len(req.Ratings) > 0:
+		choices = req.Ratings
+	case len(req.ParticipantIDs) > 0:
+		for _, participantID := range req.ParticipantIDs {
+			choices = append(choices, model.BallotChoice{ParticipantID: participantID})
+		}
+	case req.ParticipantID != "":
+		choices = []model.BallotChoice{{ParticipantID: model.ParticipantID(req.ParticipantID)}}
+	}
+
+	client := model.VoteClient{
+		IP:        uhttp.ClientIP(r, h.opts.TrustProxy),
+		UserAgent: r.UserAgent(),
+	}
+	vote, err := h.service.Vote(r.Context(), contestID, categoryID, userID, choices, client)
 	if err != nil {
 		uhttp.HandleError(w, err)
 		return
 	}
 
 	type resp struct {
-		ParticipantID string `json:"participant_id"`
+		CategoryID    string               `json:"category_id,omitempty"`
+		ParticipantID string               `json:"participant_id"`
+		Choices       []model.BallotChoice `json:"choices,omitempty"`
 	}
-	if err := uhttp.SendSuccess(w, resp{ParticipantID: string(vote.ParticipantID)}); err != nil {
+	if err := uhttp.SendSuccess(w, resp{CategoryID: string(vote.CategoryID), ParticipantID: string(vote.ParticipantID), Choices: vote.Choices}); err != nil {
 		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
 		return
 	}
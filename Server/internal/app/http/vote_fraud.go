@@ -0,0 +1,79 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"toppet/server/internal/app/defenitions"
+	"toppet/server/internal/app/uhttp"
+	"toppet/server/internal/model"
+)
+
+type (
+	serviceVoteFraud interface {
+		ListFlaggedVotes(ctx context.Context, contestID model.ContestID, actorID model.UserID) ([]*model.VoteAudit, error)
+		VoidVotes(ctx context.Context, contestID model.ContestID, actorID model.UserID, voteIDs []string, reason string) (int, error)
+	}
+
+	// VoteFraudHandler проверка подозрительных голосов организатором:
+	// GET /api/contests/{contestId}/votes/flagged, POST /api/contests/{contestId}/votes/void
+	VoteFraudHandler struct {
+		name    string
+		service serviceVoteFraud
+	}
+
+	voidVotesRequest struct {
+		VoteIDs []string `json:"vote_ids"`
+		Reason  string   `json:"reason"`
+	}
+)
+
+func NewVoteFraudHandler(name string, service serviceVoteFraud) *VoteFraudHandler {
+	return &VoteFraudHandler{name: name, service: service}
+}
+
+func (h *VoteFraudHandler) ListFlagged(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(defenitions.UserID).(model.UserID)
+	contestID := model.ContestID(r.PathValue("contestId"))
+
+	votes, err := h.service.ListFlaggedVotes(r.Context(), contestID, userID)
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	type resp struct {
+		Items []*model.VoteAudit `json:"items"`
+		Total int                `json:"total"`
+	}
+	if err := uhttp.SendSuccess(w, resp{Items: votes, Total: len(votes)}); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
+
+func (h *VoteFraudHandler) VoidVotes(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(defenitions.UserID).(model.UserID)
+	contestID := model.ContestID(r.PathValue("contestId"))
+
+	var req voidVotesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		uhttp.HandleError(w, uhttp.NewBadRequestError("invalid request body", err))
+		return
+	}
+
+	voided, err := h.service.VoidVotes(r.Context(), contestID, userID, req.VoteIDs, req.Reason)
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	type resp struct {
+		Voided int `json:"voided"`
+	}
+	if err := uhttp.SendSuccess(w, resp{Voided: voided}); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
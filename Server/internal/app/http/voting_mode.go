@@ -0,0 +1,79 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"toppet/server/internal/app/defenitions"
+	"toppet/server/internal/app/uhttp"
+	"toppet/server/internal/model"
+)
+
+type (
+	serviceVotingMode interface {
+		UpdateVotingMode(ctx context.Context, contestID model.ContestID, actorID model.UserID, mode model.VotingMode, maxChoices, minBallots int) (*model.Contest, error)
+		GetContestResults(ctx context.Context, contestID model.ContestID, categoryID model.CategoryID) (*model.ContestResults, error)
+	}
+
+	// VotingModeHandler режим голосования конкурса: /api/contests/{contestId}/voting-mode
+	// и итоги по режиму: /api/contests/{contestId}/results[?category_id=]
+	VotingModeHandler struct {
+		name        string
+		service     serviceVotingMode
+		authService serviceOptionalAuth
+	}
+
+	updateVotingModeRequest struct {
+		VotingMode model.VotingMode `json:"voting_mode"`
+		MaxChoices int              `json:"max_choices"`
+		MinBallots int              `json:"min_ballots"`
+	}
+)
+
+func NewVotingModeHandler(name string, service serviceVotingMode) *VotingModeHandler {
+	var authService serviceOptionalAuth
+	if svc, ok := service.(serviceOptionalAuth); ok {
+		authService = svc
+	}
+	return &VotingModeHandler{name: name, service: service, authService: authService}
+}
+
+func (h *VotingModeHandler) UpdateVotingMode(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(defenitions.UserID).(model.UserID)
+	contestID := model.ContestID(r.PathValue("contestId"))
+
+	var req updateVotingModeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		uhttp.HandleError(w, uhttp.NewBadRequestError("invalid request body", err))
+		return
+	}
+
+	contest, err := h.service.UpdateVotingMode(r.Context(), contestID, userID, req.VotingMode, req.MaxChoices, req.MinBallots)
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	if err := uhttp.SendSuccess(w, contest); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
+
+func (h *VotingModeHandler) GetResults(w http.ResponseWriter, r *http.Request) {
+	contestID := model.ContestID(r.PathValue("contestId"))
+
+	ctx := optionalClaimsContext(r, h.authService)
+
+	results, err := h.service.GetContestResults(ctx, contestID, model.CategoryID(r.URL.Query().Get("category_id")))
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	if err := uhttp.SendSuccess(w, results); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
@@ -0,0 +1,155 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"toppet/server/internal/app/defenitions"
+	"toppet/server/internal/app/uhttp"
+	"toppet/server/internal/model"
+)
+
+type (
+	serviceVotingPolicy interface {
+		GetVotingPolicy(ctx context.Context, contestID model.ContestID) (*model.VotingPolicy, error)
+		UpdateVotingPolicy(ctx context.Context, contestID model.ContestID, actorID model.UserID, policy *model.VotingPolicy) (*model.VotingPolicy, error)
+		ListInvitedVoters(ctx context.Context, contestID model.ContestID, actorID model.UserID) ([]*model.InvitedVoter, error)
+		AddInvitedVoters(ctx context.Context, contestID model.ContestID, actorID model.UserID, userIDs []model.UserID) error
+		RemoveInvitedVoter(ctx context.Context, contestID model.ContestID, actorID, userID model.UserID) error
+	}
+
+	// VotingPolicyHandler правила голосования конкурса: /api/contests/{contestId}/voting-policy
+	// и список приглашенных голосующих: /api/contests/{contestId}/invited-voters
+	VotingPolicyHandler struct {
+		name        string
+		service     serviceVotingPolicy
+		authService serviceOptionalAuth
+	}
+
+	updateVotingPolicyRequest struct {
+		MinAccountAgeHours     int      `json:"min_account_age_hours"`
+		RequiredProviders      []string `json:"required_providers"`
+		ExcludeOwnParticipants bool     `json:"exclude_own_participants"`
+		InviteOnly             bool     `json:"invite_only"`
+	}
+
+	addInvitedVotersRequest struct {
+		UserIDs []model.UserID `json:"user_ids"`
+	}
+)
+
+func NewVotingPolicyHandler(name string, service serviceVotingPolicy) *VotingPolicyHandler {
+	var authService serviceOptionalAuth
+	if svc, ok := service.(serviceOptionalAuth); ok {
+		authService = svc
+	}
+	return &VotingPolicyHandler{name: name, service: service, authService: authService}
+}
+
+func (h *VotingPolicyHandler) GetPolicy(w http.ResponseWriter, r *http.Request) {
+	contestID := model.ContestID(r.PathValue("contestId"))
+
+	ctx := optionalClaimsContext(r, h.authService)
+
+	policy, err := h.service.GetVotingPolicy(ctx, contestID)
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	if err := uhttp.SendSuccess(w, policy); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
+
+func (h *VotingPolicyHandler) UpdatePolicy(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(defenitions.UserID).(model.UserID)
+	contestID := model.ContestID(r.PathValue("contestId"))
+
+	var req updateVotingPolicyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		uhttp.HandleError(w, uhttp.NewBadRequestError("invalid request body", err))
+		return
+	}
+
+	policy, err := h.service.UpdateVotingPolicy(r.Context(), contestID, userID, &model.VotingPolicy{
+		MinAccountAgeHours:     req.MinAccountAgeHours,
+		RequiredProviders:      req.RequiredProviders,
+		ExcludeOwnParticipants: req.ExcludeOwnParticipants,
+		InviteOnly:             req.InviteOnly,
+	})
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	if err := uhttp.SendSuccess(w, policy); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
+
+func (h *VotingPolicyHandler) ListInvitedVoters(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(defenitions.UserID).(model.UserID)
+	contestID := model.ContestID(r.PathValue("contestId"))
+
+	voters, err := h.service.ListInvitedVoters(r.Context(), contestID, userID)
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	type resp struct {
+		Items []*model.InvitedVoter `json:"items"`
+		Total int64                 `json:"total"`
+	}
+	if err := uhttp.SendSuccess(w, resp{Items: voters, Total: int64(len(voters))}); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
+
+func (h *VotingPolicyHandler) AddInvitedVoters(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(defenitions.UserID).(model.UserID)
+	contestID := model.ContestID(r.PathValue("contestId"))
+
+	var req addInvitedVotersRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		uhttp.HandleError(w, uhttp.NewBadRequestError("invalid request body", err))
+		return
+	}
+
+	if err := h.service.AddInvitedVoters(r.Context(), contestID, userID, req.UserIDs); err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	if err := uhttp.SendSuccess(w, map[string]int{"added": len(req.UserIDs)}); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
+
+func (h *VotingPolicyHandler) RemoveInvitedVoter(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(defenitions.UserID).(model.UserID)
+	contestID := model.ContestID(r.PathValue("contestId"))
+
+	voterID, err := strconv.ParseInt(r.PathValue("userId"), 10, 64)
+	if err != nil {
+		uhttp.HandleError(w, uhttp.NewBadRequestError("invalid userId", err))
+		return
+	}
+
+	if err := h.service.RemoveInvitedVoter(r.Context(), contestID, userID, model.UserID(voterID)); err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	if err := uhttp.SendSuccess(w, map[string]bool{"removed": true}); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
@@ -0,0 +1,178 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"toppet/server/internal/app/defenitions"
+	"toppet/server/internal/app/uhttp"
+	"toppet/server/internal/model"
+)
+
+type (
+	serviceWebhooks interface {
+		ListWebhooks(ctx context.Context, contestID model.ContestID, userID model.UserID) ([]*model.WebhookEndpoint, error)
+		CreateWebhook(ctx context.Context, contestID model.ContestID, userID model.UserID, rawURL string, eventTypes []model.WebhookEventType) (*model.WebhookEndpoint, error)
+		UpdateWebhook(ctx context.Context, webhookID string, userID model.UserID, update *model.WebhookEndpointUpdate, rotateSecret bool) (*model.WebhookEndpoint, error)
+		DeleteWebhook(ctx context.Context, webhookID string, userID model.UserID) error
+		ListWebhookDeliveries(ctx context.Context, webhookID string, userID model.UserID, limit, offset int) ([]*model.WebhookDelivery, int64, error)
+		RedeliverWebhook(ctx context.Context, webhookID, deliveryID string, userID model.UserID) (*model.WebhookDelivery, error)
+	}
+
+	// WebhookHandler webhooks конкурса (/api/contests/{contestId}/webhooks)
+	// и их журнал доставок (/api/webhooks/{webhookId})
+	WebhookHandler struct {
+		name    string
+		service serviceWebhooks
+	}
+
+	createWebhookRequest struct {
+		URL        string                   `json:"url"`
+		EventTypes []model.WebhookEventType `json:"event_types"`
+	}
+
+	updateWebhookRequest struct {
+		URL          *string                   `json:"url"`
+		EventTypes   *[]model.WebhookEventType `json:"event_types"`
+		Active       *bool                     `json:"active"`
+		RotateSecret bool                      `json:"rotate_secret"`
+	}
+)
+
+func NewWebhookHandler(name string, service serviceWebhooks) *WebhookHandler {
+	return &WebhookHandler{name: name, service: service}
+}
+
+func (h *WebhookHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(defenitions.UserID).(model.UserID)
+	contestID := model.ContestID(r.PathValue("contestId"))
+
+	webhooks, err := h.service.ListWebhooks(r.Context(), contestID, userID)
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	type resp struct {
+		Items []*model.WebhookEndpoint `json:"items"`
+		Total int64                    `json:"total"`
+	}
+	if err := uhttp.SendSuccess(w, resp{Items: webhooks, Total: int64(len(webhooks))}); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
+
+// Create регистрирует webhook; ключ подписи есть только в этом ответе
+func (h *WebhookHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(defenitions.UserID).(model.UserID)
+	contestID := model.ContestID(r.PathValue("contestId"))
+
+	var req createWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		uhttp.HandleError(w, uhttp.NewBadRequestError("invalid request body", err))
+		return
+	}
+
+	webhook, err := h.service.CreateWebhook(r.Context(), contestID, userID, req.URL, req.EventTypes)
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	if err := uhttp.SendSuccess(w, webhook); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
+
+// Update меняет только переданные поля; rotate_secret выдает новый ключ подписи
+func (h *WebhookHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(defenitions.UserID).(model.UserID)
+	webhookID := r.PathValue("webhookId")
+
+	var req updateWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		uhttp.HandleError(w, uhttp.NewBadRequestError("invalid request body", err))
+		return
+	}
+
+	update := &model.WebhookEndpointUpdate{URL: req.URL, EventTypes: req.EventTypes, Active: req.Active}
+	webhook, err := h.service.UpdateWebhook(r.Context(), webhookID, userID, update, req.RotateSecret)
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	if err := uhttp.SendSuccess(w, webhook); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
+
+func (h *WebhookHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(defenitions.UserID).(model.UserID)
+	webhookID := r.PathValue("webhookId")
+
+	if err := h.service.DeleteWebhook(r.Context(), webhookID, userID); err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	if err := uhttp.SendSuccess(w, map[string]bool{"deleted": true}); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
+
+// ListDeliveries журнал доставок webhook, новые первыми
+func (h *WebhookHandler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(defenitions.UserID).(model.UserID)
+	webhookID := r.PathValue("webhookId")
+
+	limit := 20
+	if l := r.URL.Query().Get("limit"); l != "" {
+		if n, err := strconv.Atoi(l); err == nil {
+			limit = n
+		}
+	}
+	offset := 0
+	if o := r.URL.Query().Get("offset"); o != "" {
+		if n, err := strconv.Atoi(o); err == nil {
+			offset = n
+		}
+	}
+
+	deliveries, total, err := h.service.ListWebhookDeliveries(r.Context(), webhookID, userID, limit, offset)
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	type resp struct {
+		Items []*model.WebhookDelivery `json:"items"`
+		Total int64                    `json:"total"`
+	}
+	if err := uhttp.SendSuccess(w, resp{Items: deliveries, Total: total}); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
+
+// Redeliver ставит событие доставки в очередь повторно
+func (h *WebhookHandler) Redeliver(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(defenitions.UserID).(model.UserID)
+
+	delivery, err := h.service.RedeliverWebhook(r.Context(), r.PathValue("webhookId"), r.PathValue("deliveryId"), userID)
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	if err := uhttp.SendSuccess(w, delivery); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
@@ -0,0 +1,42 @@
+package http
+
+import (
+	"context"
+	"net/http"
+
+	"toppet/server/internal/app/defenitions"
+	"toppet/server/internal/app/uhttp"
+	"toppet/server/internal/model"
+)
+
+type (
+	serviceWSTicket interface {
+		IssueWSTicket(ctx context.Context, userID model.UserID) (*model.WSTicket, error)
+	}
+
+	// WSTicketHandler выдает одноразовый билет для подключения к WebSocket (POST /api/ws/ticket)
+	WSTicketHandler struct {
+		name    string
+		service serviceWSTicket
+	}
+)
+
+func NewWSTicketHandler(name string, service serviceWSTicket) *WSTicketHandler {
+	return &WSTicketHandler{name: name, service: service}
+}
+
+func (h *WSTicketHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(defenitions.UserID).(model.UserID)
+
+	ticket, err := h.service.IssueWSTicket(r.Context(), userID)
+	if err != nil {
+		uhttp.HandleError(w, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	if err := uhttp.SendSuccess(w, ticket); err != nil {
+		uhttp.HandleError(w, uhttp.NewInternalServerError("failed to send response", err))
+		return
+	}
+}
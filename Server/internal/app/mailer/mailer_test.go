@@ -0,0 +1,62 @@
+package mailer
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"toppet/server/internal/model"
+)
+
+func TestBuildMessage_Multipart(t *testing.T) {
+	raw, err := buildMessage("TopPet <noreply@top-pet.ru>", &model.EmailMessage{
+		To:      "user@example.com",
+		Subject: "Вход в TopPet",
+		Text:    "plain body",
+		HTML:    "<p>html body</p>",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	s := string(raw)
+	for _, want := range []string{"To: user@example.com", "multipart/alternative", "plain body", "<p>html body</p>", "=?utf-8?q?"} {
+		if !strings.Contains(s, want) {
+			t.Errorf("message does not contain %q:\n%s", want, s)
+		}
+	}
+}
+
+func TestLogMailer_WritesFile(t *testing.T) {
+	dir := t.TempDir()
+	m := NewLogMailer("noreply@top-pet.ru", dir)
+	if err := m.Send(context.Background(), &model.EmailMessage{To: "a@b.ru", Subject: "s", Text: "t"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) != 1 || !strings.HasSuffix(entries[0].Name(), ".eml") {
+		t.Fatalf("expected one .eml file, got %v (err %v)", entries, err)
+	}
+}
+
+func TestBuildMessage_Headers(t *testing.T) {
+	raw, err := buildMessage("noreply@top-pet.ru", &model.EmailMessage{
+		To:      "a@b.ru",
+		Subject: "s",
+		Text:    "t",
+		Headers: map[string]string{
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+			"List-Unsubscribe":      "<https://api.top-pet.ru/unsubscribe?token=x>\r\nBcc: victim@example.com",
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	s := string(raw)
+	if !strings.Contains(s, "List-Unsubscribe: <https://api.top-pet.ru/unsubscribe?token=x>Bcc: victim@example.com\r\nList-Unsubscribe-Post: List-Unsubscribe=One-Click\r\n") {
+		t.Errorf("headers are not sorted or not sanitized:\n%s", s)
+	}
+	if strings.Contains(s, "\r\nBcc:") {
+		t.Errorf("header injection:\n%s", s)
+	}
+}
@@ -0,0 +1,89 @@
+package mailer
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"path"
+	"strings"
+	texttemplate "text/template"
+
+	"toppet/server/internal/model"
+)
+
+//go:embed templates/*.tmpl
+var templateFiles embed.FS
+
+// Templates шаблоны писем о событиях (service.EmailTemplates). Каждое письмо - файл
+// templates/<name>.<locale>.tmpl с блоками subject, text и html; общие подвал и разметка
+// лежат в templates/layout.<locale>.tmpl. Тема и текст рендерятся text/template, HTML - html/template.
+type Templates struct {
+	text map[string]*texttemplate.Template
+	html map[string]*htmltemplate.Template
+}
+
+// LoadTemplates разбирает встроенные шаблоны; ошибка означает битый шаблон в сборке.
+func LoadTemplates() (*Templates, error) {
+	t := &Templates{
+		text: make(map[string]*texttemplate.Template),
+		html: make(map[string]*htmltemplate.Template),
+	}
+	files, err := fs.Glob(templateFiles, "templates/*.tmpl")
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		base := strings.TrimSuffix(path.Base(file), ".tmpl")
+		name, locale, ok := strings.Cut(base, ".")
+		if !ok {
+			return nil, fmt.Errorf("template %s: expected <name>.<locale>.tmpl", file)
+		}
+		if name == "layout" {
+			continue
+		}
+		layout := "templates/layout." + locale + ".tmpl"
+
+		textTemplate, err := texttemplate.ParseFS(templateFiles, file, layout)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", file, err)
+		}
+		htmlTemplate, err := htmltemplate.ParseFS(templateFiles, file, layout)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", file, err)
+		}
+		t.text[base] = textTemplate
+		t.html[base] = htmlTemplate
+	}
+	return t, nil
+}
+
+// Render рендерит письмо name на языке locale; для неизвестного языка используется русский.
+// Поле To не заполняется.
+func (t *Templates) Render(name string, locale model.Locale, data any) (*model.EmailMessage, error) {
+	key := name + "." + string(locale)
+	if _, ok := t.text[key]; !ok {
+		key = name + "." + string(model.LocaleRU)
+	}
+	textTemplate, ok := t.text[key]
+	if !ok {
+		return nil, fmt.Errorf("unknown email template %q", name)
+	}
+
+	var subject, text, html bytes.Buffer
+	if err := textTemplate.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return nil, fmt.Errorf("render %s subject: %w", key, err)
+	}
+	if err := textTemplate.ExecuteTemplate(&text, "text", data); err != nil {
+		return nil, fmt.Errorf("render %s text: %w", key, err)
+	}
+	if err := t.html[key].ExecuteTemplate(&html, "html", data); err != nil {
+		return nil, fmt.Errorf("render %s html: %w", key, err)
+	}
+	return &model.EmailMessage{
+		Subject: strings.Join(strings.Fields(subject.String()), " "),
+		Text:    strings.TrimSpace(text.String()) + "\n",
+		HTML:    strings.TrimSpace(html.String()) + "\n",
+	}, nil
+}
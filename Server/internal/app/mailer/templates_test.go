@@ -0,0 +1,65 @@
+package mailer
+
+import (
+	"strings"
+	"testing"
+
+	"toppet/server/internal/model"
+)
+
+type testTemplateData struct {
+	ContestTitle   string
+	ContestURL     string
+	UnsubscribeURL string
+}
+
+func TestTemplates_RenderAllLocales(t *testing.T) {
+	templates, err := LoadTemplates()
+	if err != nil {
+		t.Fatalf("LoadTemplates: %v", err)
+	}
+	data := testTemplateData{
+		ContestTitle:   `Коты <script>`,
+		ContestURL:     "https://top-pet.ru/contests/1",
+		UnsubscribeURL: "https://api.top-pet.ru/api/notifications/unsubscribe?token=abc",
+	}
+	for _, name := range []string{"voting_started", "results_published"} {
+		for _, locale := range model.SupportedLocales {
+			msg, err := templates.Render(name, locale, data)
+			if err != nil {
+				t.Fatalf("%s.%s: %v", name, locale, err)
+			}
+			if !strings.Contains(msg.Subject, data.ContestTitle) || strings.Contains(msg.Subject, "\n") {
+				t.Errorf("%s.%s: bad subject %q", name, locale, msg.Subject)
+			}
+			for _, want := range []string{data.ContestTitle, data.ContestURL, data.UnsubscribeURL} {
+				if !strings.Contains(msg.Text, want) {
+					t.Errorf("%s.%s: text does not contain %q:\n%s", name, locale, want, msg.Text)
+				}
+			}
+			if strings.Contains(msg.HTML, "<script>") || !strings.Contains(msg.HTML, "&lt;script&gt;") {
+				t.Errorf("%s.%s: html is not escaped:\n%s", name, locale, msg.HTML)
+			}
+			if !strings.Contains(msg.HTML, `lang="`+string(locale)+`"`) {
+				t.Errorf("%s.%s: html uses wrong layout", name, locale)
+			}
+		}
+	}
+}
+
+func TestTemplates_FallbackAndUnknown(t *testing.T) {
+	templates, err := LoadTemplates()
+	if err != nil {
+		t.Fatalf("LoadTemplates: %v", err)
+	}
+	msg, err := templates.Render("voting_started", model.Locale("de"), testTemplateData{ContestTitle: "X"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(msg.Subject, "Началось голосование") {
+		t.Errorf("expected russian fallback, got %q", msg.Subject)
+	}
+	if _, err := templates.Render("missing", model.LocaleRU, nil); err == nil {
+		t.Error("expected error for unknown template")
+	}
+}
@@ -0,0 +1,70 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepInterval как часто MemoryStore удаляет полностью восстановившиеся бакеты
+const sweepInterval = time.Minute
+
+type (
+	bucket struct {
+		tokens    float64
+		updatedAt time.Time
+		// fullAt - момент, когда бакет снова станет полным и его можно удалить
+		fullAt time.Time
+	}
+
+	// MemoryStore хранит бакеты в памяти процесса (лимиты не разделяются между репликами)
+	MemoryStore struct {
+		mu        sync.Mutex
+		buckets   map[string]*bucket
+		now       func() time.Time
+		lastSweep time.Time
+	}
+)
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]*bucket), now: time.Now}
+}
+
+func (s *MemoryStore) Take(_ context.Context, key string, policy Policy) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(policy.Burst), updatedAt: now}
+		s.buckets[key] = b
+	}
+
+	elapsed := now.Sub(b.updatedAt).Seconds()
+	b.tokens = math.Min(float64(policy.Burst), b.tokens+elapsed*policy.Rate)
+	b.updatedAt = now
+
+	if b.tokens < 1 {
+		return Result{Allowed: false, RetryAfter: retryAfter(b.tokens, policy.Rate)}, nil
+	}
+
+	b.tokens--
+	b.fullAt = now.Add(time.Duration((float64(policy.Burst) - b.tokens) / policy.Rate * float64(time.Second)))
+	return Result{Allowed: true}, nil
+}
+
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for key, b := range s.buckets {
+		if !b.fullAt.After(now) {
+			delete(s.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package ratelimit
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// staleBucketTTL бакеты без обращений дольше этого срока удаляются из таблицы
+const staleBucketTTL = 24 * time.Hour
+
+type (
+	// BucketRepository атомарно пополняет бакет и берет из него токен (одним запросом к БД)
+	BucketRepository interface {
+		TakeRateLimitToken(ctx context.Context, key string, rate float64, burst int) (tokens float64, allowed bool, err error)
+		DeleteStaleRateLimitBuckets(ctx context.Context, before time.Time) error
+	}
+
+	// PostgresStore хранит бакеты в таблице rate_limit_buckets - лимиты общие для всех реплик
+	PostgresStore struct {
+		repo      BucketRepository
+		mu        sync.Mutex
+		lastSweep time.Time
+	}
+)
+
+func NewPostgresStore(repo BucketRepository) *PostgresStore {
+	return &PostgresStore{repo: repo}
+}
+
+func (s *PostgresStore) Take(ctx context.Context, key string, policy Policy) (Result, error) {
+	s.sweep()
+
+	tokens, allowed, err := s.repo.TakeRateLimitToken(ctx, key, policy.Rate, policy.Burst)
+	if err != nil {
+		return Result{}, err
+	}
+	if !allowed {
+		return Result{Allowed: false, RetryAfter: retryAfter(tokens, policy.Rate)}, nil
+	}
+	return Result{Allowed: true}, nil
+}
+
+func (s *PostgresStore) sweep() {
+	s.mu.Lock()
+	now := time.Now()
+	if now.Sub(s.lastSweep) < sweepInterval*10 {
+		s.mu.Unlock()
+		return
+	}
+	s.lastSweep = now
+	s.mu.Unlock()
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := s.repo.DeleteStaleRateLimitBuckets(ctx, now.Add(-staleBucketTTL)); err != nil {
+			log.Printf("[RateLimit] failed to delete stale buckets: %v", err)
+		}
+	}()
+}
@@ -0,0 +1,145 @@
+// Package ratelimit реализует ограничение частоты запросов по алгоритму token bucket.
+// Бакеты хранятся в памяти процесса (MemoryStore) или в Postgres (PostgresStore),
+// чтобы лимиты действовали на все реплики сервера.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Имена политик, на которые ссылаются маршруты
+const (
+	PolicyChatMessage = "chat_message"
+	PolicyComment     = "comment"
+	PolicyVote        = "vote"
+	PolicyLike        = "like"
+	PolicyUpload      = "upload"
+	PolicyCreate      = "create"
+)
+
+type (
+	// Policy - скорость пополнения бакета (токенов в секунду) и его емкость
+	Policy struct {
+		Rate  float64
+		Burst int
+	}
+
+	// Result результат попытки взять токен
+	Result struct {
+		Allowed bool
+		// RetryAfter через сколько появится следующий токен (только для Allowed=false)
+		RetryAfter time.Duration
+	}
+
+	// Store хранилище бакетов
+	Store interface {
+		Take(ctx context.Context, key string, policy Policy) (Result, error)
+	}
+
+	// Limiter применяет именованные политики к ключам (user:<id>, ip:<addr>)
+	Limiter struct {
+		store    Store
+		policies map[string]Policy
+	}
+)
+
+// DefaultPolicies лимиты по умолчанию; переопределяются через RATE_LIMIT_POLICIES
+func DefaultPolicies() map[string]Policy {
+	return map[string]Policy{
+		PolicyChatMessage: {Rate: 20.0 / 60, Burst: 5},
+		PolicyComment:     {Rate: 10.0 / 60, Burst: 5},
+		PolicyVote:        {Rate: 30.0 / 60, Burst: 10},
+		PolicyLike:        {Rate: 60.0 / 60, Burst: 20},
+		PolicyUpload:      {Rate: 30.0 / 3600, Burst: 10},
+		PolicyCreate:      {Rate: 20.0 / 3600, Burst: 5},
+	}
+}
+
+func NewLimiter(store Store, policies map[string]Policy) *Limiter {
+	return &Limiter{store: store, policies: policies}
+}
+
+// Allow берет токен из бакета policy/key. Неизвестная политика и nil Limiter ничего не ограничивают.
+func (l *Limiter) Allow(ctx context.Context, policy, key string) (Result, error) {
+	if l == nil {
+		return Result{Allowed: true}, nil
+	}
+	p, ok := l.policies[policy]
+	if !ok || p.Rate <= 0 || p.Burst <= 0 {
+		return Result{Allowed: true}, nil
+	}
+	return l.store.Take(ctx, policy+":"+key, p)
+}
+
+// retryAfter - время до накопления одного токена
+func retryAfter(tokens float64, rate float64) time.Duration {
+	wait := (1 - tokens) / rate
+	if wait < 0 {
+		wait = 0
+	}
+	return time.Duration(math.Ceil(wait*1000)) * time.Millisecond
+}
+
+// ParsePolicy разбирает политику вида "20/m" или "20/m:5" (количество / s|m|h : емкость).
+// Без емкости она равна количеству.
+func ParsePolicy(s string) (Policy, error) {
+	s = strings.TrimSpace(s)
+	ratePart, burstPart, hasBurst := strings.Cut(s, ":")
+
+	countStr, unit, ok := strings.Cut(ratePart, "/")
+	if !ok {
+		return Policy{}, fmt.Errorf("invalid rate limit policy %q: expected <count>/<s|m|h>[:burst]", s)
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(countStr))
+	if err != nil || count <= 0 {
+		return Policy{}, fmt.Errorf("invalid rate limit policy %q: count must be positive", s)
+	}
+
+	var period time.Duration
+	switch strings.TrimSpace(unit) {
+	case "s":
+		period = time.Second
+	case "m":
+		period = time.Minute
+	case "h":
+		period = time.Hour
+	default:
+		return Policy{}, fmt.Errorf("invalid rate limit policy %q: unit must be s, m or h", s)
+	}
+
+	burst := count
+	if hasBurst {
+		burst, err = strconv.Atoi(strings.TrimSpace(burstPart))
+		if err != nil || burst <= 0 {
+			return Policy{}, fmt.Errorf("invalid rate limit policy %q: burst must be positive", s)
+		}
+	}
+
+	return Policy{Rate: float64(count) / period.Seconds(), Burst: burst}, nil
+}
+
+// ParsePolicies разбирает список "comment=10/m:5,vote=30/m" поверх политик по умолчанию
+func ParsePolicies(s string) (map[string]Policy, error) {
+	policies := DefaultPolicies()
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid rate limit policy %q: expected name=<count>/<unit>", item)
+		}
+		policy, err := ParsePolicy(value)
+		if err != nil {
+			return nil, err
+		}
+		policies[strings.TrimSpace(name)] = policy
+	}
+	return policies, nil
+}
@@ -0,0 +1,110 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestParsePolicy(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Policy
+		wantErr bool
+	}{
+		{in: "10/s", want: Policy{Rate: 10, Burst: 10}},
+		{in: "60/m:5", want: Policy{Rate: 1, Burst: 5}},
+		{in: "3600/h", want: Policy{Rate: 1, Burst: 3600}},
+		{in: "10", wantErr: true},
+		{in: "0/m", wantErr: true},
+		{in: "10/d", wantErr: true},
+		{in: "10/m:0", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParsePolicy(tt.in)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error for %q", tt.in)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ParsePolicy(%q) = %+v, want %+v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePoliciesOverridesDefaults(t *testing.T) {
+	policies, err := ParsePolicies("comment=1/s:2, custom=5/m")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if policies[PolicyComment] != (Policy{Rate: 1, Burst: 2}) {
+		t.Errorf("comment policy not overridden: %+v", policies[PolicyComment])
+	}
+	if _, ok := policies["custom"]; !ok {
+		t.Errorf("custom policy missing")
+	}
+	if policies[PolicyVote] != DefaultPolicies()[PolicyVote] {
+		t.Errorf("vote policy should keep default")
+	}
+
+	if _, err := ParsePolicies("comment"); err == nil {
+		t.Errorf("expected error for policy without value")
+	}
+}
+
+func TestMemoryStoreTokenBucket(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+	limiter := NewLimiter(store, map[string]Policy{"test": {Rate: 1, Burst: 2}})
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if res, _ := limiter.Allow(ctx, "test", "user:1"); !res.Allowed {
+			t.Fatalf("request %d should be allowed by burst", i+1)
+		}
+	}
+
+	res, _ := limiter.Allow(ctx, "test", "user:1")
+	if res.Allowed {
+		t.Fatalf("third request should be limited")
+	}
+	if res.RetryAfter != time.Second {
+		t.Errorf("RetryAfter = %v, want 1s", res.RetryAfter)
+	}
+
+	// Другие ключи и политики не затронуты
+	if res, _ := limiter.Allow(ctx, "test", "user:2"); !res.Allowed {
+		t.Errorf("other key should not be limited")
+	}
+	if res, _ := limiter.Allow(ctx, "unknown", "user:1"); !res.Allowed {
+		t.Errorf("unknown policy should not limit")
+	}
+
+	now = now.Add(1500 * time.Millisecond)
+	if res, _ := limiter.Allow(ctx, "test", "user:1"); !res.Allowed {
+		t.Errorf("token should be refilled after 1.5s")
+	}
+	res, _ = limiter.Allow(ctx, "test", "user:1")
+	if res.Allowed {
+		t.Errorf("only one token should be refilled")
+	}
+	if res.RetryAfter != 500*time.Millisecond {
+		t.Errorf("RetryAfter = %v, want 500ms", res.RetryAfter)
+	}
+}
+
+func TestNilLimiterAllowsEverything(t *testing.T) {
+	var limiter *Limiter
+	if res, err := limiter.Allow(context.Background(), PolicyComment, "ip:127.0.0.1"); err != nil || !res.Allowed {
+		t.Errorf("nil limiter should allow, got %+v, %v", res, err)
+	}
+}
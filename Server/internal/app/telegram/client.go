@@ -0,0 +1,227 @@
+// Package telegram содержит клиент Telegram Bot API (service.TelegramBot).
+// Адрес API настраивается, поэтому в тестах и локально вместо api.telegram.org
+// можно поднять фейковый сервер.
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"toppet/server/internal/model"
+)
+
+// DefaultAPIURL адрес Bot API по умолчанию
+const DefaultAPIURL = "https://api.telegram.org"
+
+// requestMargin запас к таймауту long polling, чтобы ответ сервера успел дойти до отмены запроса
+const requestMargin = 10 * time.Second
+
+// sendTimeout таймаут обычных запросов
+const sendTimeout = 15 * time.Second
+
+// Client ходит в Bot API по адресу <apiURL>/bot<token>/<method>
+type Client struct {
+	apiURL     string
+	token      string
+	httpClient *http.Client
+}
+
+// APIError ошибка, которую вернул Bot API (ok=false)
+type APIError struct {
+	Method      string
+	Code        int
+	Description string
+	// RetryAfterSec через сколько секунд можно повторить запрос (при 429)
+	RetryAfterSec int
+}
+
+type (
+	apiResponse struct {
+		OK          bool            `json:"ok"`
+		Result      json.RawMessage `json:"result"`
+		ErrorCode   int             `json:"error_code"`
+		Description string          `json:"description"`
+		Parameters  *struct {
+			RetryAfter int `json:"retry_after"`
+		} `json:"parameters"`
+	}
+
+	update struct {
+		UpdateID int64    `json:"update_id"`
+		Message  *message `json:"message"`
+	}
+
+	message struct {
+		MessageID int64  `json:"message_id"`
+		From      *user  `json:"from"`
+		Chat      chat   `json:"chat"`
+		Text      string `json:"text"`
+	}
+
+	user struct {
+		ID       int64  `json:"id"`
+		Username string `json:"username"`
+	}
+
+	chat struct {
+		ID       int64  `json:"id"`
+		Type     string `json:"type"`
+		Username string `json:"username"`
+	}
+
+	getUpdatesRequest struct {
+		Offset         int64    `json:"offset,omitempty"`
+		Timeout        int      `json:"timeout"`
+		AllowedUpdates []string `json:"allowed_updates"`
+	}
+
+	sendMessageRequest struct {
+		ChatID             any                `json:"chat_id"`
+		Text               string             `json:"text"`
+		ParseMode          string             `json:"parse_mode"`
+		LinkPreviewOptions linkPreviewOptions `json:"link_preview_options"`
+	}
+
+	linkPreviewOptions struct {
+		IsDisabled bool `json:"is_disabled"`
+	}
+)
+
+// NewClient создает клиент; пустой apiURL означает DefaultAPIURL
+func NewClient(apiURL, token string) *Client {
+	if apiURL == "" {
+		apiURL = DefaultAPIURL
+	}
+	return &Client{
+		apiURL:     strings.TrimSuffix(apiURL, "/"),
+		token:      token,
+		httpClient: &http.Client{},
+	}
+}
+
+// GetUpdates получает новые сообщения боту начиная с offset, ожидая их до timeout (long polling)
+func (c *Client) GetUpdates(ctx context.Context, offset int64, timeout time.Duration) ([]*model.TelegramUpdate, error) {
+	var updates []update
+	err := c.call(ctx, "getUpdates", timeout+requestMargin, getUpdatesRequest{
+		Offset:         offset,
+		Timeout:        int(timeout.Seconds()),
+		AllowedUpdates: []string{"message"},
+	}, &updates)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.TelegramUpdate, 0, len(updates))
+	for _, u := range updates {
+		item := &model.TelegramUpdate{UpdateID: u.UpdateID}
+		if u.Message != nil {
+			item.ChatID = u.Message.Chat.ID
+			item.ChatType = u.Message.Chat.Type
+			item.Text = u.Message.Text
+			item.Username = u.Message.Chat.Username
+			if u.Message.From != nil && u.Message.From.Username != "" {
+				item.Username = u.Message.From.Username
+			}
+		}
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+// SendMessage отправляет текст с HTML разметкой. chatID - числовой ID чата или @username канала.
+func (c *Client) SendMessage(ctx context.Context, chatID, text string) error {
+	var target any = chatID
+	if id, err := strconv.ParseInt(chatID, 10, 64); err == nil {
+		target = id
+	}
+	return c.call(ctx, "sendMessage", sendTimeout, sendMessageRequest{
+		ChatID:             target,
+		Text:               text,
+		ParseMode:          "HTML",
+		LinkPreviewOptions: linkPreviewOptions{IsDisabled: true},
+	}, nil)
+}
+
+func (c *Client) call(ctx context.Context, method string, timeout time.Duration, request, result any) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.apiURL+"/bot"+c.token+"/"+method, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("telegram %s: %w", method, redactToken(err, method))
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("telegram %s: %w", method, redactToken(err, method))
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+	if err != nil {
+		return fmt.Errorf("telegram %s: read response: %w", method, err)
+	}
+	var apiResp apiResponse
+	if err := json.Unmarshal(raw, &apiResp); err != nil {
+		return fmt.Errorf("telegram %s: status %d: invalid response: %w", method, resp.StatusCode, err)
+	}
+	if !apiResp.OK {
+		apiErr := &APIError{Method: method, Code: apiResp.ErrorCode, Description: apiResp.Description}
+		if apiErr.Code == 0 {
+			apiErr.Code = resp.StatusCode
+		}
+		if apiResp.Parameters != nil {
+			apiErr.RetryAfterSec = apiResp.Parameters.RetryAfter
+		}
+		return apiErr
+	}
+	if result == nil {
+		return nil
+	}
+	if err := json.Unmarshal(apiResp.Result, result); err != nil {
+		return fmt.Errorf("telegram %s: invalid result: %w", method, err)
+	}
+	return nil
+}
+
+// redactToken убирает из ошибки URL запроса: в нем содержится токен бота
+func redactToken(err error, method string) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return &url.Error{Op: urlErr.Op, URL: "bot API " + method, Err: urlErr.Err}
+	}
+	return err
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("telegram %s: %d %s", e.Method, e.Code, e.Description)
+}
+
+// Unwrap позволяет проверять недоступность чата через errors.Is(err, model.ErrTelegramChatUnavailable):
+// бот заблокирован или исключен из чата (403) либо чат не найден.
+func (e *APIError) Unwrap() error {
+	if e.Code == http.StatusForbidden || (e.Code == http.StatusBadRequest && strings.Contains(strings.ToLower(e.Description), "chat not found")) {
+		return model.ErrTelegramChatUnavailable
+	}
+	return nil
+}
+
+// RetryAfter через сколько можно повторить запрос после 429; 0 - ограничения нет
+func (e *APIError) RetryAfter() time.Duration {
+	return time.Duration(e.RetryAfterSec) * time.Second
+}
@@ -0,0 +1,132 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"toppet/server/internal/model"
+)
+
+// fakeBotAPI запоминает запросы и отвечает заготовленным JSON по имени метода
+func fakeBotAPI(t *testing.T, responses map[string]string) (*httptest.Server, *[]map[string]any) {
+	t.Helper()
+	var requests []map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, ok := strings.CutPrefix(r.URL.Path, "/bottest-token/")
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		var req map[string]any
+		_ = json.Unmarshal(body, &req)
+		req["_method"] = method
+		requests = append(requests, req)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, responses[method])
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &requests
+}
+
+func TestClient_GetUpdates(t *testing.T) {
+	srv, requests := fakeBotAPI(t, map[string]string{
+		"getUpdates": `{"ok":true,"result":[
+			{"update_id":10,"message":{"message_id":1,"from":{"id":42,"username":"alice"},"chat":{"id":42,"type":"private"},"text":"/start link_abc"}},
+			{"update_id":11}
+		]}`,
+	})
+
+	updates, err := NewClient(srv.URL, "test-token").GetUpdates(context.Background(), 10, 25*time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(updates) != 2 {
+		t.Fatalf("got %d updates, want 2", len(updates))
+	}
+	want := model.TelegramUpdate{UpdateID: 10, ChatID: 42, ChatType: "private", Username: "alice", Text: "/start link_abc"}
+	if *updates[0] != want {
+		t.Errorf("update = %+v, want %+v", *updates[0], want)
+	}
+	if updates[1].UpdateID != 11 || updates[1].ChatID != 0 {
+		t.Errorf("non-message update = %+v", *updates[1])
+	}
+
+	req := (*requests)[0]
+	if req["offset"] != float64(10) || req["timeout"] != float64(25) {
+		t.Errorf("getUpdates request = %v", req)
+	}
+}
+
+func TestClient_SendMessage(t *testing.T) {
+	srv, requests := fakeBotAPI(t, map[string]string{
+		"sendMessage": `{"ok":true,"result":{"message_id":5,"chat":{"id":42,"type":"private"},"text":"hi"}}`,
+	})
+	client := NewClient(srv.URL, "test-token")
+
+	if err := client.SendMessage(context.Background(), "42", "<b>hi</b>"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := client.SendMessage(context.Background(), "@toppet", "hi"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := (*requests)[0]; got["chat_id"] != float64(42) || got["parse_mode"] != "HTML" || got["text"] != "<b>hi</b>" {
+		t.Errorf("numeric chat request = %v", got)
+	}
+	if got := (*requests)[1]; got["chat_id"] != "@toppet" {
+		t.Errorf("channel request chat_id = %v, want @toppet", got["chat_id"])
+	}
+}
+
+func TestClient_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		response    string
+		unavailable bool
+		retryAfter  time.Duration
+	}{
+		{"blocked", `{"ok":false,"error_code":403,"description":"Forbidden: bot was blocked by the user"}`, true, 0},
+		{"chat not found", `{"ok":false,"error_code":400,"description":"Bad Request: chat not found"}`, true, 0},
+		{"bad markup", `{"ok":false,"error_code":400,"description":"Bad Request: can't parse entities"}`, false, 0},
+		{"flood", `{"ok":false,"error_code":429,"description":"Too Many Requests","parameters":{"retry_after":7}}`, false, 7 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, _ := fakeBotAPI(t, map[string]string{"sendMessage": tt.response})
+
+			err := NewClient(srv.URL, "test-token").SendMessage(context.Background(), "42", "hi")
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("expected APIError, got %v", err)
+			}
+			if got := errors.Is(err, model.ErrTelegramChatUnavailable); got != tt.unavailable {
+				t.Errorf("chat unavailable = %v, want %v", got, tt.unavailable)
+			}
+			if got := apiErr.RetryAfter(); got != tt.retryAfter {
+				t.Errorf("RetryAfter() = %s, want %s", got, tt.retryAfter)
+			}
+		})
+	}
+}
+
+func TestClient_ErrorDoesNotLeakToken(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+
+	err := NewClient(srv.URL, "secret-token").SendMessage(context.Background(), "42", "hi")
+	if err == nil {
+		t.Fatal("expected error for closed server")
+	}
+	if strings.Contains(err.Error(), "secret-token") {
+		t.Errorf("error contains bot token: %v", err)
+	}
+}
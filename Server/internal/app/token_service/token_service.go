@@ -24,9 +24,11 @@ func NewTokenService(secretKey []byte, duration time.Duration, tokenType string)
 	}
 }
 
-func (a *tokenService) GenerateToken(userID model.UserID) (string, error) {
+// GenerateToken выпускает токен; roles попадают в claims, чтобы проверки прав не ходили в БД на каждый запрос
+func (a *tokenService) GenerateToken(userID model.UserID, roles []model.Role) (string, error) {
 	claims := &model.Claims{
 		UserID:    userID,
+		Roles:     roles,
 		TokenType: a.tokenType,
 		StandardClaims: jwt.StandardClaims{
 			ExpiresAt: time.Now().Add(a.duration).Unix(),
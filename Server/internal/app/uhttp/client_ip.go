@@ -0,0 +1,30 @@
+package uhttp
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// ClientIP возвращает IP адрес клиента.
+// При trustProxy=true (сервер стоит за nginx, TRUST_PROXY_HEADERS) используется X-Real-IP,
+// затем последний адрес из X-Forwarded-For (его добавляет наш прокси, остальные клиент может подделать).
+func ClientIP(r *http.Request, trustProxy bool) string {
+	if trustProxy {
+		if ip := strings.TrimSpace(r.Header.Get("X-Real-IP")); ip != "" {
+			return ip
+		}
+		if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+			parts := strings.Split(xff, ",")
+			if ip := strings.TrimSpace(parts[len(parts)-1]); ip != "" {
+				return ip
+			}
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -3,7 +3,10 @@ package uhttp
 import (
 	"errors"
 	"fmt"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
 	"toppet/server/internal/model"
 )
@@ -14,6 +17,8 @@ type AppError struct {
 	Code    int    `json:"code"`    // HTTP статус код
 	Message string `json:"message"` // Сообщение об ошибке
 	Err     error  `json:"-"`       // Внутренняя ошибка (не сериализуется в JSON)
+	// RetryAfter для 429/503 выставляет заголовок Retry-After (в секундах, с округлением вверх)
+	RetryAfter time.Duration `json:"-"`
 }
 
 // Error реализует интерфейс error
@@ -58,6 +63,18 @@ func NewNotFoundError(message string, err error) *AppError {
 	return NewAppError(http.StatusNotFound, message, err)
 }
 
+// NewTooManyRequestsError создает ошибку 429 Too Many Requests
+func NewTooManyRequestsError(message string, err error) *AppError {
+	return NewAppError(http.StatusTooManyRequests, message, err)
+}
+
+// NewRateLimitedError создает ошибку 429 с заголовком Retry-After
+func NewRateLimitedError(message string, retryAfter time.Duration) *AppError {
+	appErr := NewAppError(http.StatusTooManyRequests, message, model.ErrTooManyRequests)
+	appErr.RetryAfter = retryAfter
+	return appErr
+}
+
 // NewInternalServerError создает ошибку 500 Internal Server Error
 func NewInternalServerError(message string, err error) *AppError {
 	return NewAppError(http.StatusInternalServerError, message, err)
@@ -68,10 +85,20 @@ func NewInternalServerError(message string, err error) *AppError {
 func HandleError(w http.ResponseWriter, err error) {
 	var appErr *AppError
 	if errors.As(err, &appErr) {
+		if appErr.RetryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(appErr.RetryAfter.Seconds()))))
+		}
 		SendErrorResponse(w, appErr.Code, appErr.Message)
 		return
 	}
 
+	// Ошибка с кодом причины: статус по базовой ошибке, код передается клиенту
+	var codedErr *model.CodedError
+	if errors.As(err, &codedErr) {
+		sendErrorResponse(w, codedErrorStatus(codedErr), ErrorResponse{Error: true, Message: codedErr.Message, Code: codedErr.Code})
+		return
+	}
+
 	// Маппинг стандартных ошибок модели на HTTP статусы
 	if errors.Is(err, model.ErrorNotFound) {
 		SendErrorResponse(w, http.StatusNotFound, "not found")
@@ -97,14 +124,38 @@ func HandleError(w http.ResponseWriter, err error) {
 		return
 	}
 
+	if errors.Is(err, model.ErrTooManyRequests) {
+		SendErrorResponse(w, http.StatusTooManyRequests, "too many requests")
+		return
+	}
+
 	// Неизвестная ошибка - возвращаем 500
 	SendErrorResponse(w, http.StatusInternalServerError, "internal server error")
 }
 
+func codedErrorStatus(err *model.CodedError) int {
+	switch {
+	case errors.Is(err.Kind, model.ErrorNotFound), errors.Is(err.Kind, model.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err.Kind, model.ErrorForbidden), errors.Is(err.Kind, model.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err.Kind, model.ErrUnauthorized):
+		return http.StatusUnauthorized
+	case errors.Is(err.Kind, model.ErrTooManyRequests):
+		return http.StatusTooManyRequests
+	case errors.Is(err.Kind, model.ErrBadRequest):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
 // ErrorResponse представляет структуру ответа с ошибкой
 type ErrorResponse struct {
 	Error   bool   `json:"error"`
 	Message string `json:"message"`
+	// Code машиночитаемый код причины (для model.CodedError)
+	Code string `json:"code,omitempty"`
 }
 
 // SuccessResponse представляет структуру успешного ответа
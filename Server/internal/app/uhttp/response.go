@@ -19,9 +19,13 @@ func SendSuccessfulResponse(w http.ResponseWriter, jsonContent []byte) {
 
 // SendErrorResponse отправляет JSON ответ с ошибкой
 func SendErrorResponse(w http.ResponseWriter, statusCode int, message string) {
+	sendErrorResponse(w, statusCode, ErrorResponse{Error: true, Message: message})
+}
+
+func sendErrorResponse(w http.ResponseWriter, statusCode int, body ErrorResponse) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
-	jsonData, err := json.Marshal(ErrorResponse{Error: true, Message: message})
+	jsonData, err := json.Marshal(body)
 	if err != nil {
 		// Если не удалось замаршалить ошибку, отправляем простой текст
 		w.WriteHeader(http.StatusInternalServerError)
@@ -0,0 +1,138 @@
+// Package webhook отправляет события конкурсов на webhooks организаторов (service.WebhookSender).
+// Каждый запрос подписывается HMAC-SHA256 от "<timestamp>.<тело>" ключом webhook.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"toppet/server/internal/model"
+)
+
+// Заголовки запроса к webhook
+const (
+	HeaderEvent     = "X-TopPet-Event"
+	HeaderDelivery  = "X-TopPet-Delivery"
+	HeaderTimestamp = "X-TopPet-Timestamp"
+	HeaderSignature = "X-TopPet-Signature"
+)
+
+const (
+	// DefaultTimeout сколько ждать ответа webhook
+	DefaultTimeout = 10 * time.Second
+	// maxResponseBody сколько байт ответа сохраняется в журнал доставок
+	maxResponseBody = 1024
+	userAgent       = "TopPet-Webhooks/1.0"
+)
+
+// ErrAddressNotAllowed адрес webhook указывает во внутреннюю сеть
+var ErrAddressNotAllowed = errors.New("webhook address is not allowed")
+
+// Client отправляет подписанные POST запросы. Редиректы не выполняются: ответ 3xx - неудачная доставка.
+type Client struct {
+	httpClient *http.Client
+}
+
+// NewClient создает клиент. Без allowPrivateNetworks соединения с loopback, частными и link-local
+// адресами запрещены: проверяется адрес, к которому идет подключение, а не имя хоста, поэтому
+// DNS не позволит обойти запрет.
+func NewClient(timeout time.Duration, allowPrivateNetworks bool) *Client {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivateNetworks {
+		dialer.Control = denyPrivateAddresses
+	}
+	transport := &http.Transport{
+		DialContext:         dialer.DialContext,
+		TLSHandshakeTimeout: timeout,
+		MaxIdleConnsPerHost: 2,
+		IdleConnTimeout:     90 * time.Second,
+	}
+	return &Client{httpClient: &http.Client{
+		Transport: transport,
+		Timeout:   timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}}
+}
+
+// Sign подпись тела запроса: "sha256=" + hex(HMAC-SHA256(secret, "<timestamp>.<body>"))
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Send отправляет событие. Ответ возвращается и при ошибке, если webhook ответил не 2xx.
+func (c *Client) Send(ctx context.Context, request *model.WebhookRequest) (*model.WebhookResponse, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, request.URL, bytes.NewReader(request.Body))
+	if err != nil {
+		return nil, err
+	}
+	timestamp := request.Timestamp.Unix()
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("User-Agent", userAgent)
+	httpReq.Header.Set(HeaderEvent, string(request.EventType))
+	httpReq.Header.Set(HeaderDelivery, request.DeliveryID)
+	httpReq.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	httpReq.Header.Set(HeaderSignature, Sign(request.Secret, timestamp, request.Body))
+
+	start := time.Now()
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	response := &model.WebhookResponse{
+		StatusCode: resp.StatusCode,
+		Body:       responseText(body),
+		Duration:   time.Since(start),
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return response, fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return response, nil
+}
+
+// responseText тело ответа для журнала: без нулевых байтов и невалидного UTF-8, которые не примет PostgreSQL
+func responseText(body []byte) string {
+	return strings.ReplaceAll(strings.ToValidUTF8(string(body), ""), "\x00", "")
+}
+
+func denyPrivateAddresses(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !isPublicIP(ip) {
+		return fmt.Errorf("%w: %s", ErrAddressNotAllowed, host)
+	}
+	return nil
+}
+
+// sharedAddressSpace 100.64.0.0/10 (RFC 6598), адреса провайдерского NAT
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+func isPublicIP(ip net.IP) bool {
+	return ip.IsGlobalUnicast() && !ip.IsPrivate() && !sharedAddressSpace.Contains(ip)
+}
@@ -0,0 +1,135 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"toppet/server/internal/model"
+)
+
+func testRequest(url string) *model.WebhookRequest {
+	return &model.WebhookRequest{
+		URL:        url,
+		Secret:     "whsec_test",
+		DeliveryID: "delivery-1",
+		EventType:  model.WebhookEventContestStatusUpdated,
+		Body:       []byte(`{"type":"contest.status_updated"}`),
+		Timestamp:  time.Unix(1700000000, 0),
+	}
+}
+
+func TestSign(t *testing.T) {
+	// echo -n '1700000000.{}' | openssl dgst -sha256 -hmac secret
+	want := "sha256=b8569b78799ff9e3cbff0fc2d63a33a2b57f3282abd07c37ae5e8e7d79a5f163"
+	if got := Sign("secret", 1700000000, []byte("{}")); got != want {
+		t.Errorf("Sign() = %s, want %s", got, want)
+	}
+	if Sign("secret", 1700000001, []byte("{}")) == want {
+		t.Error("timestamp must be part of the signature")
+	}
+}
+
+func TestClient_Send(t *testing.T) {
+	var headers http.Header
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header.Clone()
+		body, _ = io.ReadAll(r.Body)
+		_, _ = io.WriteString(w, "ok\x00")
+	}))
+	defer srv.Close()
+
+	req := testRequest(srv.URL)
+	resp, err := NewClient(time.Second, true).Send(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK || resp.Body != "ok" {
+		t.Errorf("response = %+v", resp)
+	}
+	if string(body) != string(req.Body) {
+		t.Errorf("body = %s, want %s", body, req.Body)
+	}
+	if headers.Get(HeaderEvent) != "contest.status_updated" || headers.Get(HeaderDelivery) != "delivery-1" || headers.Get(HeaderTimestamp) != "1700000000" {
+		t.Errorf("headers = %v", headers)
+	}
+	if got, want := headers.Get(HeaderSignature), Sign("whsec_test", 1700000000, req.Body); got != want {
+		t.Errorf("signature = %s, want %s", got, want)
+	}
+}
+
+func TestClient_SendErrorStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+	}{
+		{"server error", http.StatusInternalServerError},
+		{"redirect is not followed", http.StatusFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.status == http.StatusFound {
+					http.Redirect(w, r, "http://169.254.169.254/", tt.status)
+					return
+				}
+				w.WriteHeader(tt.status)
+				_, _ = io.WriteString(w, strings.Repeat("x", 2*maxResponseBody))
+			}))
+			defer srv.Close()
+
+			resp, err := NewClient(time.Second, true).Send(context.Background(), testRequest(srv.URL))
+			if err == nil {
+				t.Fatal("expected error for non-2xx response")
+			}
+			if resp == nil || resp.StatusCode != tt.status || len(resp.Body) > maxResponseBody {
+				t.Errorf("response = %+v", resp)
+			}
+		})
+	}
+}
+
+func TestClient_DeniesPrivateAddresses(t *testing.T) {
+	called := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer srv.Close()
+
+	_, err := NewClient(time.Second, false).Send(context.Background(), testRequest(srv.URL))
+	if !errors.Is(err, ErrAddressNotAllowed) {
+		t.Errorf("expected ErrAddressNotAllowed, got %v", err)
+	}
+	if called {
+		t.Error("request reached a loopback address")
+	}
+}
+
+func TestIsPublicIP(t *testing.T) {
+	tests := map[string]bool{
+		"8.8.8.8":         true,
+		"2a00:1450::1":    true,
+		"127.0.0.1":       false,
+		"10.1.2.3":        false,
+		"192.168.0.1":     false,
+		"172.16.5.4":      false,
+		"169.254.169.254": false,
+		"100.64.0.1":      false,
+		"0.0.0.0":         false,
+		"::1":             false,
+		"fd00::1":         false,
+		"fe80::1":         false,
+	}
+	for address, want := range tests {
+		if got := isPublicIP(net.ParseIP(address)); got != want {
+			t.Errorf("isPublicIP(%s) = %v, want %v", address, got, want)
+		}
+	}
+}
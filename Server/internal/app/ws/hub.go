@@ -1,6 +1,7 @@
 package ws
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"sync"
@@ -9,17 +10,35 @@ import (
 	"toppet/server/internal/model"
 )
 
+var (
+	// ErrTooManyConnections возвращается, когда у пользователя или IP уже максимум соединений
+	ErrTooManyConnections = errors.New("too many websocket connections")
+	// ErrTooManySubscriptions возвращается, когда клиент подписан на максимум конкурсов
+	ErrTooManySubscriptions = errors.New("too many contest subscriptions")
+)
+
+// HubLimits ограничения хаба. Нулевое значение поля означает "без ограничения".
+type HubLimits struct {
+	MaxConnectionsPerUser     int
+	MaxConnectionsPerIP       int
+	MaxSubscriptionsPerClient int
+}
+
 // Message is an internal hub message envelope sent to subscribers.
+// Empty ContestID with UserID set addresses all connections of the user regardless of subscriptions.
 type Message struct {
 	ContestID model.ContestID
 	UserID    *model.UserID
-	Payload   any
+	// Client адресует сообщение одному соединению (ответ на его же запрос)
+	Client  *Client
+	Payload any
 }
 
 // Client represents a single WebSocket connection.
 type Client struct {
 	Conn     *websocket.Conn
 	UserID   model.UserID
+	IP       string
 	Contests map[model.ContestID]struct{}
 	Send     chan any
 	Hub      *Hub
@@ -27,22 +46,74 @@ type Client struct {
 	closedOnce sync.Once
 }
 
-// Hub manages WebSocket clients grouped by contestID.
+// Hub manages WebSocket clients grouped by contestID and by user.
 type Hub struct {
 	clientsByContest map[model.ContestID]map[*Client]struct{}
+	clientsByUser    map[model.UserID]map[*Client]struct{}
 	register         chan *Client
 	unregister       chan *Client
 	broadcast        chan *Message
 	mu               sync.RWMutex
+
+	limits      HubLimits
+	connsByUser map[model.UserID]int
+	connsByIP   map[string]int
+	connMu      sync.Mutex
 }
 
 // NewHub returns a new Hub instance.
-func NewHub() *Hub {
+func NewHub(limits HubLimits) *Hub {
 	return &Hub{
 		clientsByContest: make(map[model.ContestID]map[*Client]struct{}),
+		clientsByUser:    make(map[model.UserID]map[*Client]struct{}),
 		register:         make(chan *Client),
 		unregister:       make(chan *Client),
 		broadcast:        make(chan *Message, 256),
+		limits:           limits,
+		connsByUser:      make(map[model.UserID]int),
+		connsByIP:        make(map[string]int),
+	}
+}
+
+// AcquireConnection резервирует слот соединения для пользователя и IP до апгрейда соединения.
+// Слот освобождается при UnregisterClient или явным ReleaseConnection, если апгрейд не удался.
+func (h *Hub) AcquireConnection(userID model.UserID, ip string) error {
+	h.connMu.Lock()
+	defer h.connMu.Unlock()
+
+	if h.limits.MaxConnectionsPerUser > 0 && h.connsByUser[userID] >= h.limits.MaxConnectionsPerUser {
+		log.Printf("[WS Hub] User %d reached connection limit (%d)", userID, h.limits.MaxConnectionsPerUser)
+		return ErrTooManyConnections
+	}
+	if h.limits.MaxConnectionsPerIP > 0 && ip != "" && h.connsByIP[ip] >= h.limits.MaxConnectionsPerIP {
+		log.Printf("[WS Hub] IP %s reached connection limit (%d)", ip, h.limits.MaxConnectionsPerIP)
+		return ErrTooManyConnections
+	}
+
+	h.connsByUser[userID]++
+	if ip != "" {
+		h.connsByIP[ip]++
+	}
+	return nil
+}
+
+// ReleaseConnection освобождает слот, зарезервированный AcquireConnection.
+func (h *Hub) ReleaseConnection(userID model.UserID, ip string) {
+	h.connMu.Lock()
+	defer h.connMu.Unlock()
+
+	if h.connsByUser[userID] <= 1 {
+		delete(h.connsByUser, userID)
+	} else {
+		h.connsByUser[userID]--
+	}
+	if ip == "" {
+		return
+	}
+	if h.connsByIP[ip] <= 1 {
+		delete(h.connsByIP, ip)
+	} else {
+		h.connsByIP[ip]--
 	}
 }
 
@@ -71,7 +142,8 @@ func (h *Hub) UnregisterClient(c *Client) {
 }
 
 // Subscribe adds the client to a contest room.
-func (c *Client) Subscribe(contestID model.ContestID) {
+// Returns ErrTooManySubscriptions when the client reached MaxSubscriptionsPerClient.
+func (c *Client) Subscribe(contestID model.ContestID) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	if c.Contests == nil {
@@ -79,7 +151,11 @@ func (c *Client) Subscribe(contestID model.ContestID) {
 	}
 	if _, ok := c.Contests[contestID]; ok {
 		log.Printf("[WS Hub] User %d already subscribed to contest %s", c.UserID, contestID)
-		return
+		return nil
+	}
+	if limit := c.Hub.limits.MaxSubscriptionsPerClient; limit > 0 && len(c.Contests) >= limit {
+		log.Printf("[WS Hub] User %d reached subscription limit (%d), contest %s rejected", c.UserID, limit, contestID)
+		return ErrTooManySubscriptions
 	}
 	c.Contests[contestID] = struct{}{}
 
@@ -91,6 +167,7 @@ func (c *Client) Subscribe(contestID model.ContestID) {
 	}
 	c.Hub.clientsByContest[contestID][c] = struct{}{}
 	log.Printf("[WS Hub] User %d subscribed to contest %s (total clients in room: %d)", c.UserID, contestID, len(c.Hub.clientsByContest[contestID]))
+	return nil
 }
 
 // Unsubscribe removes the client from a contest room.
@@ -173,10 +250,19 @@ func (c *Client) WritePump() {
 
 func (h *Hub) addClient(c *Client) {
 	log.Printf("[WS Hub] Adding client for user %d (total clients will be tracked)", c.UserID)
-	// initial registration does not subscribe to any contest yet
+	// initial registration does not subscribe to any contest yet,
+	// but the client joins the personal channel of its user
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.clientsByUser[c.UserID]; !ok {
+		h.clientsByUser[c.UserID] = make(map[*Client]struct{})
+	}
+	h.clientsByUser[c.UserID][c] = struct{}{}
 }
 
 func (h *Hub) removeClient(c *Client) {
+	h.ReleaseConnection(c.UserID, c.IP)
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
@@ -186,6 +272,13 @@ func (h *Hub) removeClient(c *Client) {
 	contestCount := len(c.Contests)
 	log.Printf("[WS Hub] Removing client for user %d (subscribed to %d contests)", c.UserID, contestCount)
 
+	if clients, ok := h.clientsByUser[c.UserID]; ok {
+		delete(clients, c)
+		if len(clients) == 0 {
+			delete(h.clientsByUser, c.UserID)
+		}
+	}
+
 	for contestID := range c.Contests {
 		if clients, ok := h.clientsByContest[contestID]; ok {
 			delete(clients, c)
@@ -202,6 +295,15 @@ func (h *Hub) dispatch(msg *Message) {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
 
+	if msg.Client != nil {
+		h.dispatchToClient(msg.Client, msg.Payload)
+		return
+	}
+	if msg.ContestID == "" && msg.UserID != nil {
+		h.dispatchToUser(*msg.UserID, msg.Payload)
+		return
+	}
+
 	clients, ok := h.clientsByContest[msg.ContestID]
 	if !ok {
 		log.Printf("[WS Hub] No clients subscribed to contest %s, message not dispatched", msg.ContestID)
@@ -234,6 +336,39 @@ func (h *Hub) dispatch(msg *Message) {
 	log.Printf("[WS Hub] Message dispatched to %d clients in contest %s", sentCount, msg.ContestID)
 }
 
+// dispatchToUser delivers a payload to every connection of the user. Caller holds h.mu.
+func (h *Hub) dispatchToUser(userID model.UserID, payload any) {
+	clients, ok := h.clientsByUser[userID]
+	if !ok {
+		log.Printf("[WS Hub] User %d has no active connections, message not dispatched", userID)
+		return
+	}
+	for c := range clients {
+		select {
+		case c.Send <- payload:
+		default:
+			log.Printf("[WS Hub] WARNING: Send channel full for user %d, closing connection", c.UserID)
+			go c.Close()
+		}
+	}
+	log.Printf("[WS Hub] Message dispatched to %d connections of user %d", len(clients), userID)
+}
+
+// dispatchToClient delivers a payload to a single connection if it is still registered. Caller holds h.mu.
+// Client.Close unregisters the connection before closing Send, so a registered client always has an open channel.
+func (h *Hub) dispatchToClient(c *Client, payload any) {
+	if _, ok := h.clientsByUser[c.UserID][c]; !ok {
+		log.Printf("[WS Hub] Connection of user %d is already closed, message not dispatched", c.UserID)
+		return
+	}
+	select {
+	case c.Send <- payload:
+	default:
+		log.Printf("[WS Hub] WARNING: Send channel full for user %d, closing connection", c.UserID)
+		go c.Close()
+	}
+}
+
 // BroadcastContestMessage sends a payload to all clients subscribed to the contest.
 func (h *Hub) BroadcastContestMessage(contestID model.ContestID, payload any) error {
 	log.Printf("[WS Hub] Broadcasting message to contest %s", contestID)
@@ -255,3 +390,24 @@ func (h *Hub) SendContestMessageToUser(contestID model.ContestID, userID model.U
 	}
 	return nil
 }
+
+// SendToClient sends a payload to one connection through the hub loop. Handlers must use it instead of
+// writing to Client.Send directly: the channel is closed concurrently when the connection drops.
+func (h *Hub) SendToClient(c *Client, payload any) error {
+	select {
+	case h.broadcast <- &Message{Client: c, Payload: payload}:
+	default:
+		log.Printf("[WS Hub] ERROR: Broadcast channel full, dropping message for user %d", c.UserID)
+	}
+	return nil
+}
+
+// SendUserMessage sends a payload to all active connections of the user, whatever contests they are subscribed to.
+func (h *Hub) SendUserMessage(userID model.UserID, payload any) error {
+	select {
+	case h.broadcast <- &Message{UserID: &userID, Payload: payload}:
+	default:
+		log.Printf("[WS Hub] ERROR: Broadcast channel full, dropping message for user %d", userID)
+	}
+	return nil
+}
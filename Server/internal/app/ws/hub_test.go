@@ -0,0 +1,110 @@
+package ws
+
+import (
+	"errors"
+	"testing"
+
+	"toppet/server/internal/model"
+)
+
+func TestHub_ConnectionLimits(t *testing.T) {
+	h := NewHub(HubLimits{MaxConnectionsPerUser: 2, MaxConnectionsPerIP: 3})
+
+	if err := h.AcquireConnection(1, "10.0.0.1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := h.AcquireConnection(1, "10.0.0.1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := h.AcquireConnection(1, "10.0.0.2"); !errors.Is(err, ErrTooManyConnections) {
+		t.Errorf("expected per-user limit, got %v", err)
+	}
+	if err := h.AcquireConnection(2, "10.0.0.1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := h.AcquireConnection(3, "10.0.0.1"); !errors.Is(err, ErrTooManyConnections) {
+		t.Errorf("expected per-IP limit, got %v", err)
+	}
+
+	h.ReleaseConnection(1, "10.0.0.1")
+	if err := h.AcquireConnection(3, "10.0.0.1"); err != nil {
+		t.Errorf("expected slot to be released, got %v", err)
+	}
+}
+
+func TestClient_SubscriptionLimit(t *testing.T) {
+	h := NewHub(HubLimits{MaxSubscriptionsPerClient: 2})
+	c := &Client{UserID: 1, Hub: h}
+
+	for _, id := range []model.ContestID{"a", "b", "a"} {
+		if err := c.Subscribe(id); err != nil {
+			t.Fatalf("subscribe %s: unexpected error: %v", id, err)
+		}
+	}
+	if err := c.Subscribe("c"); !errors.Is(err, ErrTooManySubscriptions) {
+		t.Errorf("expected ErrTooManySubscriptions, got %v", err)
+	}
+}
+
+func TestHub_SendUserMessage(t *testing.T) {
+	h := NewHub(HubLimits{})
+	first := &Client{UserID: 1, Hub: h, Send: make(chan any, 1)}
+	second := &Client{UserID: 1, Hub: h, Send: make(chan any, 1)}
+	other := &Client{UserID: 2, Hub: h, Send: make(chan any, 1)}
+	for _, c := range []*Client{first, second, other} {
+		h.addClient(c)
+	}
+
+	// Личный канал не требует подписки на конкурс
+	userID := model.UserID(1)
+	h.dispatch(&Message{UserID: &userID, Payload: "hello"})
+	for i, c := range []*Client{first, second} {
+		select {
+		case msg := <-c.Send:
+			if msg != "hello" {
+				t.Errorf("connection %d: unexpected payload %v", i, msg)
+			}
+		default:
+			t.Errorf("connection %d: expected message", i)
+		}
+	}
+	select {
+	case msg := <-other.Send:
+		t.Errorf("unexpected message for another user: %v", msg)
+	default:
+	}
+
+	h.removeClient(first)
+	h.removeClient(second)
+	if _, ok := h.clientsByUser[1]; ok {
+		t.Errorf("expected user channel removed with the last connection")
+	}
+}
+
+func TestHub_SendToClient(t *testing.T) {
+	h := NewHub(HubLimits{})
+	c := &Client{UserID: 1, Hub: h, Send: make(chan any, 1)}
+	other := &Client{UserID: 1, Hub: h, Send: make(chan any, 1)}
+	h.addClient(c)
+	h.addClient(other)
+
+	h.dispatch(&Message{Client: c, Payload: "error"})
+	select {
+	case msg := <-c.Send:
+		if msg != "error" {
+			t.Errorf("unexpected payload %v", msg)
+		}
+	default:
+		t.Error("expected message")
+	}
+	select {
+	case msg := <-other.Send:
+		t.Errorf("unexpected message for another connection: %v", msg)
+	default:
+	}
+
+	// Соединение закрыто: Close сначала снимает регистрацию, потом закрывает Send - отправки в закрытый канал нет
+	h.removeClient(c)
+	close(c.Send)
+	h.dispatch(&Message{Client: c, Payload: "late"})
+}
@@ -1,19 +1,39 @@
 package ws
 
-import "toppet/server/internal/model"
+import (
+	"time"
+
+	"toppet/server/internal/model"
+)
 
 // MessageType представляет тип WebSocket сообщения
 type MessageType string
 
 const (
-	MessageTypeContestStatusUpdated MessageType = "contest_status_updated"
-	MessageTypeVoteCreated          MessageType = "vote_created"
-	MessageTypeVoteDeleted          MessageType = "vote_deleted"
-	MessageTypeChatMessage          MessageType = "chat_message"
-	MessageTypeMessageUpdated       MessageType = "message_updated"
-	MessageTypeMessageDeleted       MessageType = "message_deleted"
+	MessageTypeContestStatusUpdated    MessageType = "contest_status_updated"
+	MessageTypeVoteCreated             MessageType = "vote_created"
+	MessageTypeVoteDeleted             MessageType = "vote_deleted"
+	MessageTypeChatMessage             MessageType = "chat_message"
+	MessageTypeMessageUpdated          MessageType = "message_updated"
+	MessageTypeMessageDeleted          MessageType = "message_deleted"
+	MessageTypeRoundStarted            MessageType = "round_started"
+	MessageTypeMatchupDecided          MessageType = "matchup_decided"
+	MessageTypeParticipantModerated    MessageType = "participant_moderated"
+	MessageTypeParticipantDisqualified MessageType = "participant_disqualified"
+	MessageTypeNotification            MessageType = "notification"
+	MessageTypeNotificationsRead       MessageType = "notifications_read"
+	MessageTypeError                   MessageType = "error"
 )
 
+// ErrorPayload представляет payload ошибки, отправляемой конкретному клиенту
+type ErrorPayload struct {
+	Type    MessageType `json:"type"`
+	Code    string      `json:"code"`
+	Message string      `json:"message"`
+	// RetryAfter через сколько секунд можно повторить (для code=rate_limited)
+	RetryAfter int `json:"retry_after,omitempty"`
+}
+
 // ContestStatusUpdatedPayload представляет payload для обновления статуса конкурса
 type ContestStatusUpdatedPayload struct {
 	Type      MessageType     `json:"type"`
@@ -39,16 +59,27 @@ type ChatMessagePayload struct {
 type VoteCountsUpdatedPayload struct {
 	Type                MessageType     `json:"type"`
 	ContestID           model.ContestID `json:"contest_id"`
+	// CategoryID номинация, в которой изменились голоса; пустая - главный приз
+	CategoryID          model.CategoryID `json:"category_id,omitempty"`
 	ParticipantID       model.ParticipantID `json:"participant_id"`
 	ParticipantTotalVotes int64         `json:"participant_total_votes"`
 	ContestTotalVotes   int64           `json:"contest_total_votes"`
+	// VotingMode режим голосования: participant_total_votes - голоса (single), одобрения (approval),
+	// первые места (ranked) или число оценок (stars)
+	VotingMode   model.VotingMode `json:"voting_mode,omitempty"`
+	AverageStars *float64         `json:"average_stars,omitempty"`
 }
 
 // UserVoteUpdatedPayload представляет payload для обновления голоса пользователя
 type UserVoteUpdatedPayload struct {
 	Type          MessageType     `json:"type"`
 	ContestID     model.ContestID `json:"contest_id"`
+	CategoryID    model.CategoryID `json:"category_id,omitempty"`
 	ParticipantID model.ParticipantID `json:"participant_id"`
+	// Choices бюллетень пользователя в режимах approval/ranked/stars
+	Choices []model.BallotChoice `json:"choices,omitempty"`
+	// CanRecast голос аннулирован из-за дисквалификации участника - можно проголосовать заново
+	CanRecast bool `json:"can_recast,omitempty"`
 }
 
 // MessageUpdatedPayload представляет payload для обновления сообщения
@@ -72,6 +103,60 @@ type NewMessagePayload struct {
 	Message   interface{}     `json:"message"`
 }
 
+// RoundStartedPayload открылось голосование в раунде турнирной сетки
+type RoundStartedPayload struct {
+	Type      MessageType             `json:"type"`
+	ContestID model.ContestID         `json:"contest_id"`
+	Round     int                     `json:"round"`
+	StartsAt  time.Time               `json:"starts_at"`
+	EndsAt    time.Time               `json:"ends_at"`
+	Matchups  []*model.BracketMatchup `json:"matchups"`
+}
+
+// MatchupDecidedPayload определен победитель пары. ChampionID заполнен, если это финал.
+type MatchupDecidedPayload struct {
+	Type       MessageType         `json:"type"`
+	ContestID  model.ContestID     `json:"contest_id"`
+	Round      int                 `json:"round"`
+	MatchupID  string              `json:"matchup_id"`
+	WinnerID   model.ParticipantID `json:"winner_id,omitempty"`
+	VotesA     int64               `json:"votes_a"`
+	VotesB     int64               `json:"votes_b"`
+	ChampionID model.ParticipantID `json:"champion_id,omitempty"`
+}
+
+// ParticipantModeratedPayload решение организатора по заявке, отправляется владельцу заявки
+type ParticipantModeratedPayload struct {
+	Type          MessageType                 `json:"type"`
+	ContestID     model.ContestID             `json:"contest_id"`
+	ParticipantID model.ParticipantID         `json:"participant_id"`
+	Status        model.EntryModerationStatus `json:"status"`
+	Reason        string                      `json:"reason,omitempty"`
+}
+
+// ParticipantDisqualifiedPayload участник дисквалифицирован, рассылается всем подписчикам конкурса
+type ParticipantDisqualifiedPayload struct {
+	Type          MessageType         `json:"type"`
+	ContestID     model.ContestID     `json:"contest_id"`
+	ParticipantID model.ParticipantID `json:"participant_id"`
+	Reason        string              `json:"reason"`
+	VoidedVotes   int                 `json:"voided_votes"`
+}
+
+// NotificationPayload новое уведомление, отправляется во все соединения получателя
+// Счетчик непрочитанных клиент увеличивает сам.
+type NotificationPayload struct {
+	Type         MessageType         `json:"type"`
+	Notification *model.Notification `json:"notification"`
+}
+
+// NotificationsReadPayload уведомления прочитаны в другой вкладке; пустой NotificationID - прочитаны все
+type NotificationsReadPayload struct {
+	Type           MessageType `json:"type"`
+	NotificationID string      `json:"notification_id,omitempty"`
+	UnreadCount    int64       `json:"unread_count"`
+}
+
 // NewContestStatusUpdatedPayload создает payload для обновления статуса конкурса
 func NewContestStatusUpdatedPayload(contestID model.ContestID, status string) ContestStatusUpdatedPayload {
 	return ContestStatusUpdatedPayload{
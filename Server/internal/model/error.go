@@ -7,5 +7,30 @@ var (
 	ErrForbidden    = errors.New("forbidden")
 	ErrNotFound     = errors.New("not found")
 	ErrBadRequest   = errors.New("bad request")
+	// ErrTooManyRequests возвращается при превышении лимита запросов
+	ErrTooManyRequests = errors.New("too many requests")
+	// ErrTelegramChatUnavailable бот больше не может писать в чат: пользователь заблокировал бота или чат удален
+	ErrTelegramChatUnavailable = errors.New("telegram chat unavailable")
 )
 
+// CodedError ошибка с машиночитаемым кодом причины (например, почему нельзя голосовать).
+// Kind - базовая ошибка (ErrorForbidden, ErrBadRequest, ...), по ней выбирается HTTP статус.
+type CodedError struct {
+	Kind    error
+	Code    string
+	Message string
+}
+
+// NewCodedError создает ошибку с кодом причины
+func NewCodedError(kind error, code, message string) *CodedError {
+	return &CodedError{Kind: kind, Code: code, Message: message}
+}
+
+func (e *CodedError) Error() string {
+	return e.Code + ": " + e.Message
+}
+
+// Unwrap позволяет проверять ошибку через errors.Is(err, e.Kind)
+func (e *CodedError) Unwrap() error {
+	return e.Kind
+}
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"encoding/json"
 	"errors"
 	"time"
 
@@ -11,11 +12,48 @@ type (
 	UserID        int64
 	ContestID     string
 	ParticipantID string
+	PetID         string
+	CategoryID    string
 	CommentID     string
 	ChatMessageID string
 
 	ContestStatus string
 
+	// VotingMode - способ голосования в конкурсе (contests.voting_mode)
+	VotingMode string
+
+	// Role - роль пользователя на уровне всей платформы (user_roles)
+	Role string
+
+	ModerationActionType string
+	ModerationTargetType string
+
+	// EntryModerationStatus - статус премодерации заявки (contest_participants.moderation_status)
+	EntryModerationStatus string
+
+	// PetSex - пол питомца; пустое значение - не указан
+	PetSex string
+
+	// Locale - язык интерфейса и уведомлений пользователя
+	Locale string
+
+	AccountDeletionStatus string
+	UserMediaKind         string
+	NotificationType      string
+	OutboxStatus          string
+
+	// ContestMemberRole - роль пользователя в рамках одного конкурса (contest_members)
+	ContestMemberRole   string
+	ContestMemberStatus string
+
+	// BracketSeeding - способ посева участников в турнирную сетку
+	BracketSeeding     string
+	BracketStatus      string
+	BracketRoundStatus string
+
+	// WebhookEventType - тип события исходящего webhook
+	WebhookEventType string
+
 	UserProfileFromProvider struct {
 		ProviderID   string `json:"provider_id"`
 		Email        string `json:"email"`
@@ -26,11 +64,25 @@ type (
 		ProviderName string `json:"provider_name"`
 	}
 
+	// User пользователь. AvatarThumbURL - уменьшенная копия загруженного аватара, у аватара провайдера ее нет.
+	// DeletedAt - момент запроса на удаление аккаунта: с него вход и обновление токенов запрещены.
 	User struct {
-		ID        UserID    `json:"id"`
-		Name      string    `json:"name"`
-		AvatarURL string    `json:"avatar_url,omitempty"`
-		CreatedAt time.Time `json:"created_at"`
+		ID             UserID     `json:"id"`
+		Name           string     `json:"name"`
+		AvatarURL      string     `json:"avatar_url,omitempty"`
+		AvatarThumbURL string     `json:"avatar_thumb_url,omitempty"`
+		Bio            string     `json:"bio,omitempty"`
+		Locale         Locale     `json:"locale,omitempty"`
+		Roles          []Role     `json:"roles,omitempty"`
+		CreatedAt      time.Time  `json:"created_at"`
+		DeletedAt      *time.Time `json:"deleted_at,omitempty"`
+	}
+
+	// UserUpdate изменение профиля текущим пользователем; nil - поле не меняется
+	UserUpdate struct {
+		Name   *string
+		Bio    *string
+		Locale *Locale
 	}
 
 	UserAuthProvider struct {
@@ -38,6 +90,265 @@ type (
 		ProviderUID string  `json:"provider_uid"`
 		Provider    string  `json:"provider"`
 		Name        *string `json:"name,omitempty"`
+		// AvatarURL аватар из профиля провайдера при последнем входе
+		AvatarURL *string `json:"avatar_url,omitempty"`
+	}
+
+	// PrivacySettings какие разделы публичного профиля видны другим пользователям (user_privacy_settings).
+	// По умолчанию открыто все.
+	PrivacySettings struct {
+		ShowContests bool `json:"show_contests"`
+		ShowPets     bool `json:"show_pets"`
+		// ShowResults победы и места в завершенных конкурсах
+		ShowResults bool       `json:"show_results"`
+		UpdatedAt   *time.Time `json:"updated_at,omitempty"`
+	}
+
+	// PublicUserProfile публичная страница пользователя. Раздел, скрытый настройками приватности, равен null.
+	PublicUserProfile struct {
+		ID             UserID    `json:"id"`
+		Name           string    `json:"name"`
+		AvatarURL      string    `json:"avatar_url,omitempty"`
+		AvatarThumbURL string    `json:"avatar_thumb_url,omitempty"`
+		Bio            string    `json:"bio,omitempty"`
+		CreatedAt      time.Time `json:"created_at"`
+		// Contests опубликованные конкурсы, созданные пользователем
+		Contests []*Contest   `json:"contests"`
+		Pets     []*Pet       `json:"pets"`
+		Results  *UserResults `json:"results"`
+		// Privacy настройки приватности; заполняются только для самого пользователя
+		Privacy *PrivacySettings `json:"privacy,omitempty"`
+	}
+
+	// UserResults итоги участия питомцев пользователя в конкурсах
+	UserResults struct {
+		Entries int `json:"entries"`
+		Wins    int `json:"wins"`
+		// Podiums число призовых мест (1-3) в завершенных конкурсах
+		Podiums int `json:"podiums"`
+		// Placements заявки с местом в завершенных конкурсах, лучшие места первыми
+		Placements []*PetEntry `json:"placements"`
+	}
+
+	// AccountDeletionJob фоновое удаление аккаунта. Статус опрашивается по ID без авторизации:
+	// сессии пользователя перестают обновляться сразу после запроса.
+	AccountDeletionJob struct {
+		ID         string                `json:"id"`
+		UserID     UserID                `json:"-"`
+		Status     AccountDeletionStatus `json:"status"`
+		Attempts   int                   `json:"attempts"`
+		LastError  string                `json:"-"`
+		CreatedAt  time.Time             `json:"created_at"`
+		StartedAt  *time.Time            `json:"started_at,omitempty"`
+		FinishedAt *time.Time            `json:"finished_at,omitempty"`
+	}
+
+	// UserExport выгрузка персональных данных пользователя
+	UserExport struct {
+		ExportedAt   time.Time           `json:"exported_at"`
+		User         *User               `json:"user"`
+		Providers    []*UserAuthProvider `json:"providers"`
+		Privacy      *PrivacySettings    `json:"privacy"`
+		Contests     []*Contest          `json:"contests"`
+		Participants []*Participant      `json:"participants"`
+		Pets         []*Pet              `json:"pets"`
+		Media        []*UserMedia        `json:"media"`
+		Votes        []*Vote             `json:"votes"`
+		Comments     []*Comment          `json:"comments"`
+		ChatMessages []*ChatMessage      `json:"chat_messages"`
+		// NotificationPreferences настройки писем, null - настройки не сохранялись
+		NotificationPreferences *NotificationPreferences `json:"notification_preferences"`
+		// TelegramAccount привязанный чат Telegram, null - не привязан
+		TelegramAccount *TelegramAccount `json:"telegram_account"`
+	}
+
+	// UserMedia файл, загруженный пользователем; OwnerID - заявка или питомец, к которому он относится
+	UserMedia struct {
+		Kind     UserMediaKind `json:"kind"`
+		OwnerID  string        `json:"owner_id,omitempty"`
+		URL      string        `json:"url"`
+		ThumbURL *string       `json:"thumb_url,omitempty"`
+	}
+
+	// Notification уведомление во входящих пользователя. Title - название конкурса или кличка питомца,
+	// Text - начало комментария; оба сохраняются на момент события.
+	Notification struct {
+		ID            string           `json:"id"`
+		UserID        UserID           `json:"-"`
+		Type          NotificationType `json:"type"`
+		ContestID     ContestID        `json:"contest_id,omitempty"`
+		ParticipantID ParticipantID    `json:"participant_id,omitempty"`
+		CommentID     CommentID        `json:"comment_id,omitempty"`
+		ActorUserID   *UserID          `json:"actor_user_id,omitempty"`
+		ActorName     string           `json:"actor_name,omitempty"`
+		Title         string           `json:"title"`
+		Text          string           `json:"text,omitempty"`
+		ReadAt        *time.Time       `json:"read_at,omitempty"`
+		CreatedAt     time.Time        `json:"created_at"`
+	}
+
+	// NotificationPreferences настройки писем о событиях конкурсов. Email - адрес из провайдера входа,
+	// пустой, если ни один провайдер его не передал (тогда письма не отправляются).
+	NotificationPreferences struct {
+		Email                 string     `json:"email,omitempty"`
+		EmailVotingStarted    bool       `json:"email_voting_started"`
+		EmailResultsPublished bool       `json:"email_results_published"`
+		UpdatedAt             *time.Time `json:"updated_at,omitempty"`
+	}
+
+	// EmailRecipient адресат письма о событии с языком, на котором оно рендерится
+	EmailRecipient struct {
+		UserID UserID
+		Email  string
+		Locale Locale
+	}
+
+	// OutboxEmail письмо в очереди на отправку. UserID равен nil для писем без получателя-пользователя.
+	OutboxEmail struct {
+		ID             string
+		UserID         *UserID
+		To             string
+		Subject        string
+		Text           string
+		HTML           string
+		UnsubscribeURL string
+		Attempts       int
+	}
+
+	// TelegramStatus состояние привязки Telegram на странице настроек. Enabled - бот настроен на сервере.
+	TelegramStatus struct {
+		Enabled     bool       `json:"enabled"`
+		BotUsername string     `json:"bot_username,omitempty"`
+		Linked      bool       `json:"linked"`
+		Username    string     `json:"username,omitempty"`
+		LinkedAt    *time.Time `json:"linked_at,omitempty"`
+	}
+
+	// TelegramAccount личный чат с ботом, привязанный к аккаунту
+	TelegramAccount struct {
+		UserID   UserID    `json:"-"`
+		ChatID   int64     `json:"chat_id"`
+		Username string    `json:"username,omitempty"`
+		LinkedAt time.Time `json:"linked_at"`
+	}
+
+	// TelegramLink одноразовая ссылка на бота для привязки аккаунта
+	TelegramLink struct {
+		URL       string    `json:"url"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+
+	// TelegramSubscription подписка чата на новости конкурса
+	TelegramSubscription struct {
+		ContestID ContestID
+		Title     string
+		Status    ContestStatus
+	}
+
+	// TelegramUpdate входящее сообщение боту. Остальные виды обновлений Bot API сюда не попадают.
+	TelegramUpdate struct {
+		UpdateID int64
+		ChatID   int64
+		// ChatType private, group, supergroup или channel
+		ChatType string
+		Username string
+		Text     string
+	}
+
+	// TelegramMessage сообщение бота в очереди на отправку. ChatID - числовой ID чата или @username канала.
+	TelegramMessage struct {
+		ID       string
+		ChatID   string
+		Text     string
+		Attempts int
+	}
+
+	// TelegramAnnouncement анонс конкурса в канале; Status - статус доставки сообщения
+	TelegramAnnouncement struct {
+		ID             string       `json:"id"`
+		ContestID      ContestID    `json:"contest_id"`
+		ChatID         string       `json:"chat_id"`
+		Text           string       `json:"text"`
+		PostedByUserID *UserID      `json:"posted_by_user_id,omitempty"`
+		Status         OutboxStatus `json:"status"`
+		LastError      string       `json:"last_error,omitempty"`
+		CreatedAt      time.Time    `json:"created_at"`
+		SentAt         *time.Time   `json:"sent_at,omitempty"`
+	}
+
+	// WebhookEndpoint адрес, на который доставляются события конкурса. Secret отдается только при создании.
+	// Пустой EventTypes - все события.
+	WebhookEndpoint struct {
+		ID              string             `json:"id"`
+		ContestID       ContestID          `json:"contest_id"`
+		URL             string             `json:"url"`
+		Secret          string             `json:"secret,omitempty"`
+		EventTypes      []WebhookEventType `json:"event_types"`
+		Active          bool               `json:"active"`
+		CreatedByUserID *UserID            `json:"created_by_user_id,omitempty"`
+		CreatedAt       time.Time          `json:"created_at"`
+		UpdatedAt       time.Time          `json:"updated_at"`
+	}
+
+	// WebhookEndpointUpdate изменение webhook; nil поля сохраняют текущие значения
+	WebhookEndpointUpdate struct {
+		URL        *string             `json:"url"`
+		EventTypes *[]WebhookEventType `json:"event_types"`
+		Active     *bool               `json:"active"`
+	}
+
+	// WebhookEvent событие конкурса; Data - JSON полезной нагрузки, зависит от типа
+	WebhookEvent struct {
+		ID        string           `json:"id"`
+		Type      WebhookEventType `json:"type"`
+		ContestID ContestID        `json:"contest_id"`
+		CreatedAt time.Time        `json:"created_at"`
+		Data      json.RawMessage  `json:"data"`
+	}
+
+	// WebhookDelivery доставка события на webhook. ResponseStatus, ResponseBody и DurationMs - по последней попытке.
+	WebhookDelivery struct {
+		ID             string           `json:"id"`
+		EndpointID     string           `json:"webhook_id"`
+		EventID        string           `json:"event_id"`
+		EventType      WebhookEventType `json:"event_type"`
+		Redelivery     bool             `json:"redelivery"`
+		Status         OutboxStatus     `json:"status"`
+		Attempts       int              `json:"attempts"`
+		ResponseStatus *int             `json:"response_status,omitempty"`
+		ResponseBody   string           `json:"response_body,omitempty"`
+		DurationMs     int              `json:"duration_ms"`
+		LastError      string           `json:"last_error,omitempty"`
+		NextAttemptAt  *time.Time       `json:"next_attempt_at,omitempty"`
+		LastAttemptAt  *time.Time       `json:"last_attempt_at,omitempty"`
+		CreatedAt      time.Time        `json:"created_at"`
+		DeliveredAt    *time.Time       `json:"delivered_at,omitempty"`
+	}
+
+	// WebhookDeliveryJob доставка, забранная воркером: адрес, ключ подписи и событие
+	WebhookDeliveryJob struct {
+		ID       string
+		Attempts int
+		URL      string
+		Secret   string
+		Event    *WebhookEvent
+	}
+
+	// WebhookRequest подписываемый запрос к webhook; Timestamp входит в подпись
+	WebhookRequest struct {
+		URL        string
+		Secret     string
+		DeliveryID string
+		EventType  WebhookEventType
+		Body       []byte
+		Timestamp  time.Time
+	}
+
+	// WebhookResponse ответ webhook; Body обрезан до разумной длины
+	WebhookResponse struct {
+		StatusCode int
+		Body       string
+		Duration   time.Duration
 	}
 
 	Contest struct {
@@ -46,7 +357,12 @@ type (
 		Title           string        `json:"title"`
 		Description     string        `json:"description"`
 		Status          ContestStatus `json:"status"`
+		VotingMode      VotingMode    `json:"voting_mode"`
+		MaxChoices      int           `json:"max_choices"`
+		MinBallots      int           `json:"min_ballots"`
+		JuryWeight      int           `json:"jury_weight"`
 		TotalVotes      int64         `json:"total_votes,omitempty"`
+		Hidden          bool          `json:"hidden,omitempty"`
 		CreatedAt       time.Time     `json:"created_at"`
 		UpdatedAt       time.Time     `json:"updated_at"`
 	}
@@ -58,11 +374,22 @@ type (
 		UserName       string        `json:"user_name,omitempty"`
 		PetName        string        `json:"pet_name"`
 		PetDescription string        `json:"pet_description"`
-		Photos         []*Photo      `json:"photos,omitempty"`
-		Video          *Video        `json:"video,omitempty"`
-		TotalVotes     int64         `json:"total_votes,omitempty"`
-		CreatedAt      time.Time     `json:"created_at"`
-		UpdatedAt      time.Time     `json:"updated_at"`
+		// PetID профиль питомца; имя, описание и признаки заявки - снимок на момент подачи
+		PetID PetID `json:"pet_id,omitempty"`
+		PetAttributes
+		Photos           []*Photo              `json:"photos,omitempty"`
+		Video            *Video                `json:"video,omitempty"`
+		TotalVotes       int64                 `json:"total_votes,omitempty"`
+		AverageStars     *float64              `json:"average_stars,omitempty"`
+		Hidden           bool                  `json:"hidden,omitempty"`
+		ModerationStatus EntryModerationStatus `json:"moderation_status,omitempty"`
+		ModerationReason string                `json:"moderation_reason,omitempty"`
+		ModeratedAt      *time.Time            `json:"moderated_at,omitempty"`
+		// DisqualifiedAt момент дисквалификации: участник остается в конкурсе, но не участвует в итогах
+		DisqualifiedAt         *time.Time `json:"disqualified_at,omitempty"`
+		DisqualificationReason string     `json:"disqualification_reason,omitempty"`
+		CreatedAt              time.Time  `json:"created_at"`
+		UpdatedAt              time.Time  `json:"updated_at"`
 	}
 
 	Photo struct {
@@ -91,13 +418,304 @@ type (
 		UpdatedAt     time.Time     `json:"updated_at"`
 	}
 
+	// Pet профиль питомца: владелец заводит его один раз и подает в разные конкурсы
+	Pet struct {
+		ID          PetID  `json:"id"`
+		OwnerUserID UserID `json:"owner_user_id"`
+		Name        string `json:"name"`
+		PetAttributes
+		Bio    string      `json:"bio"`
+		Photos []*PetPhoto `json:"photos,omitempty"`
+		// Entries история участия в конкурсах и Wins - число побед (только в карточке питомца)
+		Entries   []*PetEntry `json:"entries,omitempty"`
+		Wins      int         `json:"wins"`
+		CreatedAt time.Time   `json:"created_at"`
+		UpdatedAt time.Time   `json:"updated_at"`
+	}
+
+	// PetAttributes структурированные признаки питомца: коды вида и породы из справочника, пол и дата рождения
+	PetAttributes struct {
+		Species string `json:"species,omitempty"`
+		Breed   string `json:"breed,omitempty"`
+		Sex     PetSex `json:"sex,omitempty"`
+		// BirthDate дата рождения в формате YYYY-MM-DD
+		BirthDate string `json:"birth_date,omitempty"`
+	}
+
+	// Species вид животного из справочника
+	Species struct {
+		Code   string `json:"code"`
+		NameRu string `json:"name_ru"`
+		NameEn string `json:"name_en"`
+	}
+
+	// Breed порода из справочника; Species - код вида
+	Breed struct {
+		Code    string `json:"code"`
+		Species string `json:"species"`
+		NameRu  string `json:"name_ru"`
+		NameEn  string `json:"name_en"`
+	}
+
+	// ParticipantFilter фильтр списка участников конкурса; пустые поля не ограничивают выборку
+	ParticipantFilter struct {
+		Species      string
+		Breed        string
+		Sex          PetSex
+		MinAgeMonths int
+		MaxAgeMonths int
+	}
+
+	PetPhoto struct {
+		ID        string    `json:"id"`
+		PetID     PetID     `json:"pet_id"`
+		URL       string    `json:"url"`
+		ThumbURL  *string   `json:"thumb_url,omitempty"`
+		Position  int       `json:"position"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+
+	// PetEntry участие питомца в конкурсе; Place и Won заполнены для завершенных конкурсов
+	PetEntry struct {
+		ParticipantID ParticipantID `json:"participant_id"`
+		ContestID     ContestID     `json:"contest_id"`
+		ContestTitle  string        `json:"contest_title"`
+		ContestStatus ContestStatus `json:"contest_status"`
+		PetID         PetID         `json:"pet_id,omitempty"`
+		PetName       string        `json:"pet_name"`
+		Place         int           `json:"place,omitempty"`
+		Won           bool          `json:"won,omitempty"`
+		CreatedAt     time.Time     `json:"created_at"`
+	}
+
+	// Vote голос пользователя в номинации конкурса; пустой CategoryID - главный приз
 	Vote struct {
 		ID            string        `json:"id"`
 		ContestID     ContestID     `json:"contest_id"`
+		CategoryID    CategoryID    `json:"category_id,omitempty"`
 		ParticipantID ParticipantID `json:"participant_id"`
 		UserID        UserID        `json:"user_id"`
 		CreatedAt     time.Time     `json:"created_at"`
 		UpdatedAt     time.Time     `json:"updated_at"`
+		VoidedAt      *time.Time    `json:"voided_at,omitempty"`
+		// Recastable голос аннулирован из-за дисквалификации участника, автор может проголосовать заново
+		Recastable bool `json:"recastable,omitempty"`
+		// Choices выбор в бюллетене для режимов approval/ranked/stars
+		Choices []BallotChoice `json:"choices,omitempty"`
+	}
+
+	// BallotChoice участник в бюллетене: Rank - место в ранжированном бюллетене (с 1), Stars - оценка 1-5
+	BallotChoice struct {
+		ParticipantID ParticipantID `json:"participant_id"`
+		Rank          int           `json:"rank,omitempty"`
+		Stars         int           `json:"stars,omitempty"`
+	}
+
+	// Ballot действующий (не аннулированный) бюллетень для подсчета итогов
+	Ballot struct {
+		VoteID  string
+		Choices []BallotChoice
+	}
+
+	// ContestResults итоги голосования с учетом режима конкурса
+	ContestResults struct {
+		ContestID    ContestID            `json:"contest_id"`
+		CategoryID   CategoryID           `json:"category_id,omitempty"`
+		VotingMode   VotingMode           `json:"voting_mode"`
+		TotalBallots int64                `json:"total_ballots"`
+		MinBallots   int                  `json:"min_ballots,omitempty"`
+		WinnerID     ParticipantID        `json:"winner_id,omitempty"`
+		Items        []*ParticipantResult `json:"items"`
+		// Rounds туры подсчета мгновенного второго тура (ranked)
+		Rounds []*RunoffRound `json:"rounds,omitempty"`
+		// JuryWeight доля жюри в итоге (%); при JuryWeight > 0 места считаются по CombinedScore
+		JuryWeight int `json:"jury_weight,omitempty"`
+		Jurors     int `json:"jurors,omitempty"`
+	}
+
+	// ParticipantResult итог участника. Votes - голоса (single), одобрения (approval),
+	// первые места в последнем туре (ranked) или число оценок (stars).
+	ParticipantResult struct {
+		ParticipantID   ParticipantID `json:"participant_id"`
+		Votes           int64         `json:"votes"`
+		AverageStars    *float64      `json:"average_stars,omitempty"`
+		Qualified       bool          `json:"qualified"`
+		Place           int           `json:"place,omitempty"`
+		EliminatedRound int           `json:"eliminated_round,omitempty"`
+		// Оценки 0-100: зрительская (по режиму голосования), жюри и итоговая с учетом веса жюри
+		PublicScore   *float64 `json:"public_score,omitempty"`
+		JuryScore     *float64 `json:"jury_score,omitempty"`
+		CombinedScore *float64 `json:"combined_score,omitempty"`
+	}
+
+	// RunoffRound тур мгновенного второго тура: голоса продолжающих участников и выбывший
+	RunoffRound struct {
+		Round      int                     `json:"round"`
+		Counts     map[ParticipantID]int64 `json:"counts"`
+		Exhausted  int64                   `json:"exhausted"`
+		Eliminated ParticipantID           `json:"eliminated,omitempty"`
+	}
+
+	// ContestCategory номинация конкурса ("Самый смешной", "Лучшее видео"):
+	// в каждой номинации пользователь голосует отдельно
+	ContestCategory struct {
+		ID          CategoryID `json:"id"`
+		ContestID   ContestID  `json:"contest_id"`
+		Name        string     `json:"name"`
+		Description string     `json:"description"`
+		Position    int        `json:"position"`
+		CreatedAt   time.Time  `json:"created_at"`
+		UpdatedAt   time.Time  `json:"updated_at"`
+	}
+
+	// JuryCriterion критерий оценки жюри (например, груминг или качество фото) с весом
+	JuryCriterion struct {
+		ID          string    `json:"id"`
+		ContestID   ContestID `json:"contest_id"`
+		Name        string    `json:"name"`
+		Description string    `json:"description"`
+		Weight      int       `json:"weight"`
+		MaxScore    int       `json:"max_score"`
+		Position    int       `json:"position"`
+		CreatedAt   time.Time `json:"created_at"`
+	}
+
+	// JuryScore оценка члена жюри участнику по одному критерию
+	JuryScore struct {
+		CriterionID   string        `json:"criterion_id"`
+		ParticipantID ParticipantID `json:"participant_id"`
+		JurorID       UserID        `json:"juror_id"`
+		JurorName     string        `json:"juror_name,omitempty"`
+		Score         int           `json:"score"`
+		Comment       string        `json:"comment,omitempty"`
+		UpdatedAt     time.Time     `json:"updated_at"`
+	}
+
+	// Bracket турнирная сетка конкурса: участники разбиты на пары, победитель пары проходит дальше
+	Bracket struct {
+		ContestID            ContestID       `json:"contest_id"`
+		Seeding              BracketSeeding  `json:"seeding"`
+		RoundDurationMinutes int             `json:"round_duration_minutes"`
+		Status               BracketStatus   `json:"status"`
+		CurrentRound         int             `json:"current_round"`
+		ChampionID           ParticipantID   `json:"champion_id,omitempty"`
+		Rounds               []*BracketRound `json:"rounds"`
+		CreatedAt            time.Time       `json:"created_at"`
+		UpdatedAt            time.Time       `json:"updated_at"`
+	}
+
+	// BracketRound раунд сетки; голосование в парах раунда открыто с StartsAt до EndsAt
+	BracketRound struct {
+		ContestID ContestID          `json:"-"`
+		Round     int                `json:"round"`
+		Status    BracketRoundStatus `json:"status"`
+		StartsAt  time.Time          `json:"starts_at"`
+		EndsAt    time.Time          `json:"ends_at"`
+		Matchups  []*BracketMatchup  `json:"matchups"`
+	}
+
+	// BracketMatchup пара участников раунда. Пустая сторона - проход без соперника.
+	// Seed - место участника в посеве (1 - сильнейший).
+	BracketMatchup struct {
+		ID             string        `json:"id"`
+		Round          int           `json:"round"`
+		Position       int           `json:"position"`
+		ParticipantAID ParticipantID `json:"participant_a_id,omitempty"`
+		ParticipantBID ParticipantID `json:"participant_b_id,omitempty"`
+		SeedA          int           `json:"seed_a,omitempty"`
+		SeedB          int           `json:"seed_b,omitempty"`
+		VotesA         int64         `json:"votes_a"`
+		VotesB         int64         `json:"votes_b"`
+		WinnerID       ParticipantID `json:"winner_id,omitempty"`
+		DecidedAt      *time.Time    `json:"decided_at,omitempty"`
+		// UserVote за кого проголосовал текущий пользователь
+		UserVote ParticipantID `json:"user_vote,omitempty"`
+	}
+
+	// VoteClient данные запроса, из которых собираются метаданные голоса
+	VoteClient struct {
+		IP        string
+		UserAgent string
+	}
+
+	// VoteMetadata метаданные голоса для антифрода. IP хранится только в виде HMAC хэша.
+	VoteMetadata struct {
+		IPHash           string
+		UserAgent        string
+		AuthProvider     string
+		AccountCreatedAt *time.Time
+	}
+
+	// VoteAudit голос с метаданными и оценкой антифрода
+	VoteAudit struct {
+		ID               string        `json:"id"`
+		ContestID        ContestID     `json:"contest_id"`
+		ParticipantID    ParticipantID `json:"participant_id"`
+		UserID           UserID        `json:"user_id"`
+		UserName         string        `json:"user_name,omitempty"`
+		IPHash           string        `json:"ip_hash"`
+		UserAgent        string        `json:"user_agent"`
+		AuthProvider     string        `json:"auth_provider"`
+		AccountCreatedAt *time.Time    `json:"account_created_at,omitempty"`
+		FraudScore       int           `json:"fraud_score"`
+		FraudReasons     []string      `json:"fraud_reasons"`
+		FlaggedAt        *time.Time    `json:"flagged_at,omitempty"`
+		VotedAt          time.Time     `json:"voted_at"`
+		VoidedAt         *time.Time    `json:"voided_at,omitempty"`
+		VoidedByUserID   *UserID       `json:"voided_by_user_id,omitempty"`
+		VoidReason       string        `json:"void_reason,omitempty"`
+	}
+
+	// VotingPolicy правила допуска к голосованию в конкурсе (contest_voting_policies)
+	VotingPolicy struct {
+		ContestID              ContestID  `json:"contest_id"`
+		MinAccountAgeHours     int        `json:"min_account_age_hours"`
+		RequiredProviders      []string   `json:"required_providers"`
+		ExcludeOwnParticipants bool       `json:"exclude_own_participants"`
+		InviteOnly             bool       `json:"invite_only"`
+		UpdatedAt              *time.Time `json:"updated_at,omitempty"`
+	}
+
+	// EntryRules правила подачи заявок в конкурс (contest_entry_rules). Нулевые лимиты - без ограничений.
+	EntryRules struct {
+		ContestID           ContestID  `json:"contest_id"`
+		MaxEntriesPerUser   int        `json:"max_entries_per_user"`
+		MaxEntries          int        `json:"max_entries"`
+		MaxPhotosPerEntry   int        `json:"max_photos_per_entry"`
+		MaxPhotoSizeMB      int        `json:"max_photo_size_mb"`
+		VideoAllowed        bool       `json:"video_allowed"`
+		MaxVideoDurationSec int        `json:"max_video_duration_sec"`
+		MaxVideoSizeMB      int        `json:"max_video_size_mb"`
+		RequiredFields      []string   `json:"required_fields"`
+		EntryDeadline       *time.Time `json:"entry_deadline,omitempty"`
+		RequireApproval     bool       `json:"require_approval"`
+		// AllowedSpecies коды допустимых видов; пустой список - любой вид
+		AllowedSpecies []string `json:"allowed_species"`
+		// MinAgeMonths и MaxAgeMonths возраст питомца в полных месяцах на момент подачи; 0 - без ограничения
+		MinAgeMonths int        `json:"min_age_months"`
+		MaxAgeMonths int        `json:"max_age_months"`
+		UpdatedAt    *time.Time `json:"updated_at,omitempty"`
+	}
+
+	// MediaUpload сведения о загружаемом файле для проверки правил конкурса
+	MediaUpload struct {
+		Size int64
+		// Duration длительность видео; 0 - определить не удалось
+		Duration time.Duration
+	}
+
+	// InvitedVoter пользователь из списка голосующих конкурса с голосованием по приглашениям
+	InvitedVoter struct {
+		UserID        UserID    `json:"user_id"`
+		UserName      string    `json:"user_name"`
+		AddedByUserID *UserID   `json:"added_by_user_id,omitempty"`
+		CreatedAt     time.Time `json:"created_at"`
+	}
+
+	// VoteEligibility может ли пользователь голосовать; Reason - код причины отказа
+	VoteEligibility struct {
+		Eligible bool   `json:"eligible"`
+		Reason   string `json:"reason,omitempty"`
 	}
 
 	VoterInfo struct {
@@ -112,6 +730,7 @@ type (
 		UserID        UserID        `json:"user_id"`
 		UserName      string        `json:"user_name"`
 		Text          string        `json:"text"`
+		Hidden        bool          `json:"hidden,omitempty"`
 		CreatedAt     time.Time     `json:"created_at"`
 		UpdatedAt     time.Time     `json:"updated_at"`
 	}
@@ -133,8 +752,47 @@ type (
 		AccessToken  string `json:"token"`
 	}
 
+	// WSTicket одноразовый билет для подключения к WebSocket без токена в URL
+	WSTicket struct {
+		Ticket    string    `json:"ticket"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+
+	// EmailMessage письмо, отправляемое через Mailer. Headers - дополнительные заголовки (List-Unsubscribe и т.п.).
+	EmailMessage struct {
+		To      string
+		Subject string
+		Text    string
+		HTML    string
+		Headers map[string]string
+	}
+
+	// ModerationAction запись журнала действий модераторов
+	ModerationAction struct {
+		ID          string               `json:"id"`
+		ActorUserID UserID               `json:"actor_user_id"`
+		Action      ModerationActionType `json:"action"`
+		TargetType  ModerationTargetType `json:"target_type"`
+		TargetID    string               `json:"target_id"`
+		Reason      string               `json:"reason"`
+		CreatedAt   time.Time            `json:"created_at"`
+	}
+
+	// ContestMember организатор или модератор конкурса
+	ContestMember struct {
+		ContestID       ContestID           `json:"contest_id"`
+		UserID          UserID              `json:"user_id"`
+		UserName        string              `json:"user_name,omitempty"`
+		Role            ContestMemberRole   `json:"role"`
+		Status          ContestMemberStatus `json:"status"`
+		InvitedByUserID *UserID             `json:"invited_by_user_id,omitempty"`
+		CreatedAt       time.Time           `json:"created_at"`
+		AcceptedAt      *time.Time          `json:"accepted_at,omitempty"`
+	}
+
 	Claims struct {
 		UserID    UserID `json:"user_id"`
+		Roles     []Role `json:"roles,omitempty"`
 		TokenType string `json:"token_type"`
 		jwt.StandardClaims
 	}
@@ -148,8 +806,137 @@ const (
 	ContestStatusRegistration ContestStatus = "registration"
 	ContestStatusVoting       ContestStatus = "voting"
 	ContestStatusFinished     ContestStatus = "finished"
+
+	VotingModeSingle   VotingMode = "single"
+	VotingModeApproval VotingMode = "approval"
+	VotingModeRanked   VotingMode = "ranked"
+	VotingModeStars    VotingMode = "stars"
+
+	RoleAdmin     Role = "admin"
+	RoleModerator Role = "moderator"
+
+	ModerationActionHide       ModerationActionType = "hide"
+	ModerationActionUnhide     ModerationActionType = "unhide"
+	ModerationActionDelete     ModerationActionType = "delete"
+	ModerationActionDisqualify ModerationActionType = "disqualify"
+
+	ModerationTargetContest     ModerationTargetType = "contest"
+	ModerationTargetParticipant ModerationTargetType = "participant"
+	ModerationTargetComment     ModerationTargetType = "comment"
+	ModerationTargetChatMessage ModerationTargetType = "chat_message"
+
+	EntryModerationPending  EntryModerationStatus = "pending"
+	EntryModerationApproved EntryModerationStatus = "approved"
+	EntryModerationRejected EntryModerationStatus = "rejected"
+
+	PetSexMale   PetSex = "male"
+	PetSexFemale PetSex = "female"
+
+	LocaleRU Locale = "ru"
+	LocaleEN Locale = "en"
+
+	AccountDeletionPending   AccountDeletionStatus = "pending"
+	AccountDeletionRunning   AccountDeletionStatus = "running"
+	AccountDeletionCompleted AccountDeletionStatus = "completed"
+	AccountDeletionFailed    AccountDeletionStatus = "failed"
+
+	UserMediaAvatar           UserMediaKind = "avatar"
+	UserMediaParticipantPhoto UserMediaKind = "participant_photo"
+	UserMediaParticipantVideo UserMediaKind = "participant_video"
+	UserMediaPetPhoto         UserMediaKind = "pet_photo"
+
+	NotificationVotingStarted    NotificationType = "voting_started"
+	NotificationResultsPublished NotificationType = "results_published"
+	NotificationEntryComment     NotificationType = "entry_comment"
+	NotificationEntryDiscussion  NotificationType = "entry_discussion"
+
+	OutboxPending OutboxStatus = "pending"
+	OutboxSent    OutboxStatus = "sent"
+	OutboxFailed  OutboxStatus = "failed"
+
+	// DeletedUserName имя, под которым остается контент удаленного пользователя
+	DeletedUserName = "Удаленный пользователь"
+
+	ContestMemberOwner     ContestMemberRole = "owner"
+	ContestMemberOrganizer ContestMemberRole = "organizer"
+	ContestMemberModerator ContestMemberRole = "moderator"
+	ContestMemberJuror     ContestMemberRole = "juror"
+
+	ContestMemberInvited ContestMemberStatus = "invited"
+	ContestMemberActive  ContestMemberStatus = "active"
+
+	BracketSeedingRandom BracketSeeding = "random"
+	BracketSeedingLikes  BracketSeeding = "likes"
+
+	BracketStatusPending  BracketStatus = "pending"
+	BracketStatusRunning  BracketStatus = "running"
+	BracketStatusFinished BracketStatus = "finished"
+
+	BracketRoundScheduled BracketRoundStatus = "scheduled"
+	BracketRoundActive    BracketRoundStatus = "active"
+	BracketRoundFinished  BracketRoundStatus = "finished"
+
+	WebhookEventParticipantCreated      WebhookEventType = "participant.created"
+	WebhookEventParticipantDisqualified WebhookEventType = "participant.disqualified"
+	WebhookEventContestStatusUpdated    WebhookEventType = "contest.status_updated"
+	WebhookEventVotesUpdated            WebhookEventType = "contest.votes_updated"
+	WebhookEventResultsPublished        WebhookEventType = "contest.results_published"
+	WebhookEventBracketRoundStarted     WebhookEventType = "bracket.round_started"
+	WebhookEventBracketMatchupDecided   WebhookEventType = "bracket.matchup_decided"
+
+	// Причины подозрительности голоса (fraud_reasons)
+	VoteFraudNewAccount      = "new_account"
+	VoteFraudNewAccountBurst = "new_account_burst"
+	VoteFraudSharedIP        = "shared_ip"
+	VoteFraudNoUserAgent     = "no_user_agent"
+
+	// Коды причин, по которым пользователь не может голосовать
+	VoteIneligibleNotAuthenticated = "not_authenticated"
+	VoteIneligibleVotingClosed     = "voting_closed"
+	VoteIneligibleAccountTooNew    = "account_too_new"
+	VoteIneligibleProviderRequired = "provider_required"
+	VoteIneligibleOwnParticipant   = "own_participant"
+	VoteIneligibleNotInvited       = "not_invited"
+	VoteIneligibleVoided           = "vote_voided"
+
+	// Коды причин отказа в приеме заявки или файла
+	EntryRejectedClosed          = "entry_closed"
+	EntryRejectedDeadline        = "entry_deadline_passed"
+	EntryRejectedMaxEntries      = "max_entries_reached"
+	EntryRejectedMaxUserEntries  = "max_user_entries_reached"
+	EntryRejectedMaxPhotos       = "max_photos_reached"
+	EntryRejectedFileTooLarge    = "file_too_large"
+	EntryRejectedVideoNotAllowed = "video_not_allowed"
+	EntryRejectedVideoTooLong    = "video_too_long"
+	EntryRejectedFieldRequired   = "field_required"
+	EntryRejectedSpecies         = "species_not_allowed"
+	EntryRejectedAge             = "age_out_of_range"
+
+	// Поля заявки, которые организатор может сделать обязательными (required_fields)
+	EntryFieldPetDescription = "pet_description"
 )
 
+// Абсолютные ограничения размера загружаемых файлов, лимиты конкурса не могут их превышать
+const (
+	MaxPhotoUploadMB  = 20
+	MaxVideoUploadMB  = 500
+	MaxAvatarUploadMB = 10
+)
+
+// SupportedLocales языки, которые пользователь может выбрать в профиле
+var SupportedLocales = []Locale{LocaleRU, LocaleEN}
+
+// WebhookEventTypes события, на которые можно подписать webhook
+var WebhookEventTypes = []WebhookEventType{
+	WebhookEventParticipantCreated,
+	WebhookEventParticipantDisqualified,
+	WebhookEventContestStatusUpdated,
+	WebhookEventVotesUpdated,
+	WebhookEventResultsPublished,
+	WebhookEventBracketRoundStarted,
+	WebhookEventBracketMatchupDecided,
+}
+
 var (
 	ErrorNotFound  = errors.New("not found")
 	ErrorForbidden = errors.New("forbidden")
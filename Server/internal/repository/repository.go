@@ -3,6 +3,8 @@ package repository
 import (
 	"context"
 
+	sqlc_repository "toppet/server/internal/repository_sqlc"
+
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 )
@@ -16,6 +18,7 @@ type (
 		Exec(context.Context, string, ...interface{}) (pgconn.CommandTag, error)
 		Query(context.Context, string, ...interface{}) (pgx.Rows, error)
 		QueryRow(context.Context, string, ...interface{}) pgx.Row
+		Begin(context.Context) (pgx.Tx, error)
 	}
 )
 
@@ -24,3 +27,17 @@ func NewRepository(conn DBTX) *Repository {
 		conn: conn,
 	}
 }
+
+// inTx выполняет запросы fn в одной транзакции. Если fn вернула ошибку, изменения откатываются.
+func (r *Repository) inTx(ctx context.Context, fn func(reposqlc *sqlc_repository.Queries) error) error {
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := fn(sqlc_repository.New(tx)); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
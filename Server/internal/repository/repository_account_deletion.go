@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"toppet/server/internal/model"
+	sqlc_repository "toppet/server/internal/repository_sqlc"
+)
+
+// CreateAccountDeletionJob помечает пользователя удаленным и ставит задание в очередь.
+// Если незавершенное задание уже есть, возвращает его.
+func (r *Repository) CreateAccountDeletionJob(ctx context.Context, userID model.UserID) (*model.AccountDeletionJob, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	job, err := reposqlc.CreateAccountDeletionJob(ctx, int64(userID))
+	if err != nil {
+		return nil, err
+	}
+	return toModelAccountDeletionJob(job), nil
+}
+
+func (r *Repository) GetAccountDeletionJob(ctx context.Context, jobID string) (*model.AccountDeletionJob, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	jobUUID, err := uuid.Parse(jobID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid job id", model.ErrorNotFound)
+	}
+
+	job, err := reposqlc.GetAccountDeletionJob(ctx, pgtype.UUID{Bytes: jobUUID, Valid: true})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %v", model.ErrorNotFound, err)
+		}
+		return nil, err
+	}
+	return toModelAccountDeletionJob(job), nil
+}
+
+// ClaimAccountDeletionJob забирает самое старое задание, не запускавшееся с staleBefore (новое, ожидающее
+// повтора или зависшее в running), и переводит его в running. Без заданий возвращает model.ErrorNotFound.
+func (r *Repository) ClaimAccountDeletionJob(ctx context.Context, staleBefore time.Time) (*model.AccountDeletionJob, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	job, err := reposqlc.ClaimAccountDeletionJob(ctx, pgtype.Timestamptz{Time: staleBefore, Valid: true})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %v", model.ErrorNotFound, err)
+		}
+		return nil, err
+	}
+	return toModelAccountDeletionJob(job), nil
+}
+
+// FinishAccountDeletionJob сохраняет результат попытки; статус pending возвращает задание в очередь
+func (r *Repository) FinishAccountDeletionJob(ctx context.Context, jobID string, status model.AccountDeletionStatus, lastError string) error {
+	reposqlc := sqlc_repository.New(r.conn)
+	jobUUID, err := uuid.Parse(jobID)
+	if err != nil {
+		return err
+	}
+	return reposqlc.FinishAccountDeletionJob(ctx, &sqlc_repository.FinishAccountDeletionJobParams{
+		ID:        pgtype.UUID{Bytes: jobUUID, Valid: true},
+		Status:    string(status),
+		LastError: lastError,
+	})
+}
+
+// DeleteUserPersonalData удаляет голоса, лайки, медиа, питомцев и привязки пользователя
+// и обезличивает профиль, заменяя имя на name. Повторный вызов безопасен.
+func (r *Repository) DeleteUserPersonalData(ctx context.Context, userID model.UserID, name string) error {
+	reposqlc := sqlc_repository.New(r.conn)
+	return reposqlc.DeleteUserPersonalData(ctx, &sqlc_repository.DeleteUserPersonalDataParams{
+		UserID: int64(userID),
+		Name:   name,
+	})
+}
+
+func toModelAccountDeletionJob(job *sqlc_repository.AccountDeletionJob) *model.AccountDeletionJob {
+	return &model.AccountDeletionJob{
+		ID:         uuidString(job.ID),
+		UserID:     model.UserID(job.UserID),
+		Status:     model.AccountDeletionStatus(job.Status),
+		Attempts:   int(job.Attempts),
+		LastError:  job.LastError,
+		CreatedAt:  job.CreatedAt.Time,
+		StartedAt:  timePtr(job.StartedAt),
+		FinishedAt: timePtr(job.FinishedAt),
+	}
+}
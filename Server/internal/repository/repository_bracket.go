@@ -0,0 +1,441 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"toppet/server/internal/model"
+	sqlc_repository "toppet/server/internal/repository_sqlc"
+)
+
+func (r *Repository) GetContestBracket(ctx context.Context, contestID model.ContestID) (*model.Bracket, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	contestUUID, err := uuid.Parse(string(contestID))
+	if err != nil {
+		return nil, err
+	}
+
+	bracket, err := reposqlc.GetContestBracket(ctx, pgtype.UUID{Bytes: contestUUID, Valid: true})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %v", model.ErrorNotFound, err)
+		}
+		return nil, err
+	}
+	return toModelBracket(bracket), nil
+}
+
+func (r *Repository) UpsertContestBracket(ctx context.Context, bracket *model.Bracket) (*model.Bracket, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	contestUUID, err := uuid.Parse(string(bracket.ContestID))
+	if err != nil {
+		return nil, err
+	}
+
+	saved, err := reposqlc.UpsertContestBracket(ctx, &sqlc_repository.UpsertContestBracketParams{
+		ContestID:            pgtype.UUID{Bytes: contestUUID, Valid: true},
+		Seeding:              string(bracket.Seeding),
+		RoundDurationMinutes: int32(bracket.RoundDurationMinutes),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toModelBracket(saved), nil
+}
+
+// StartContestBracket переводит сетку из pending в running и создает первый раунд одной транзакцией.
+// false - сетку уже запустили.
+func (r *Repository) StartContestBracket(ctx context.Context, round *model.BracketRound) (bool, error) {
+	contestUUID, err := uuid.Parse(string(round.ContestID))
+	if err != nil {
+		return false, err
+	}
+
+	var started bool
+	err = r.inTx(ctx, func(reposqlc *sqlc_repository.Queries) error {
+		affected, err := reposqlc.StartContestBracket(ctx, pgtype.UUID{Bytes: contestUUID, Valid: true})
+		if err != nil || affected == 0 {
+			return err
+		}
+		if err := createBracketRound(ctx, reposqlc, round); err != nil {
+			return err
+		}
+		started = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return started, nil
+}
+
+// FinishBracketRound закрывает активный раунд одной транзакцией: фиксирует победителей пар decided
+// и создает следующий раунд next. Без next сетка завершается с победителем championID.
+// false - раунд уже закрыл другой экземпляр сервера.
+func (r *Repository) FinishBracketRound(ctx context.Context, round *model.BracketRound, decided []*model.BracketMatchup, next *model.BracketRound, championID model.ParticipantID) (bool, error) {
+	contestUUID, err := uuid.Parse(string(round.ContestID))
+	if err != nil {
+		return false, err
+	}
+	contestID := pgtype.UUID{Bytes: contestUUID, Valid: true}
+
+	var finished bool
+	err = r.inTx(ctx, func(reposqlc *sqlc_repository.Queries) error {
+		affected, err := reposqlc.SetBracketRoundStatus(ctx, &sqlc_repository.SetBracketRoundStatusParams{
+			Status:     string(model.BracketRoundFinished),
+			ContestID:  contestID,
+			Round:      int32(round.Round),
+			FromStatus: string(model.BracketRoundActive),
+		})
+		if err != nil || affected == 0 {
+			return err
+		}
+
+		for _, matchup := range decided {
+			if err := decideBracketMatchup(ctx, reposqlc, matchup.ID, matchup.WinnerID); err != nil {
+				return err
+			}
+		}
+
+		if next == nil {
+			if err := updateContestBracketState(ctx, reposqlc, contestID, model.BracketStatusFinished, round.Round, championID); err != nil {
+				return err
+			}
+		} else {
+			if err := createBracketRound(ctx, reposqlc, next); err != nil {
+				return err
+			}
+			if err := updateContestBracketState(ctx, reposqlc, contestID, model.BracketStatusRunning, next.Round, ""); err != nil {
+				return err
+			}
+		}
+		finished = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return finished, nil
+}
+
+func updateContestBracketState(ctx context.Context, reposqlc *sqlc_repository.Queries, contestID pgtype.UUID, status model.BracketStatus, currentRound int, championID model.ParticipantID) error {
+	championUUID, err := parseOptionalParticipantID(championID)
+	if err != nil {
+		return err
+	}
+
+	return reposqlc.UpdateContestBracketState(ctx, &sqlc_repository.UpdateContestBracketStateParams{
+		ContestID:             contestID,
+		Status:                string(status),
+		CurrentRound:          int32(currentRound),
+		ChampionParticipantID: championUUID,
+	})
+}
+
+// createBracketRound создает раунд вместе с парами одним запросом. Пары с известным
+// победителем (проход без соперника) сразу помечаются решенными.
+func createBracketRound(ctx context.Context, reposqlc *sqlc_repository.Queries, round *model.BracketRound) error {
+	contestUUID, err := uuid.Parse(string(round.ContestID))
+	if err != nil {
+		return err
+	}
+
+	params := &sqlc_repository.CreateBracketRoundParams{
+		ContestID:       pgtype.UUID{Bytes: contestUUID, Valid: true},
+		Round:           int32(round.Round),
+		Status:          string(round.Status),
+		StartsAt:        pgtype.Timestamptz{Time: round.StartsAt, Valid: true},
+		EndsAt:          pgtype.Timestamptz{Time: round.EndsAt, Valid: true},
+		Ids:             make([]pgtype.UUID, 0, len(round.Matchups)),
+		Positions:       make([]int32, 0, len(round.Matchups)),
+		ParticipantAIds: make([]pgtype.UUID, 0, len(round.Matchups)),
+		ParticipantBIds: make([]pgtype.UUID, 0, len(round.Matchups)),
+		SeedsA:          make([]int32, 0, len(round.Matchups)),
+		SeedsB:          make([]int32, 0, len(round.Matchups)),
+		WinnerIds:       make([]pgtype.UUID, 0, len(round.Matchups)),
+	}
+	for _, matchup := range round.Matchups {
+		participantA, err := parseOptionalParticipantID(matchup.ParticipantAID)
+		if err != nil {
+			return err
+		}
+		participantB, err := parseOptionalParticipantID(matchup.ParticipantBID)
+		if err != nil {
+			return err
+		}
+		winner, err := parseOptionalParticipantID(matchup.WinnerID)
+		if err != nil {
+			return err
+		}
+
+		id := uuid.New()
+		matchup.ID = id.String()
+		params.Ids = append(params.Ids, pgtype.UUID{Bytes: id, Valid: true})
+		params.Positions = append(params.Positions, int32(matchup.Position))
+		params.ParticipantAIds = append(params.ParticipantAIds, participantA)
+		params.ParticipantBIds = append(params.ParticipantBIds, participantB)
+		params.SeedsA = append(params.SeedsA, int32(matchup.SeedA))
+		params.SeedsB = append(params.SeedsB, int32(matchup.SeedB))
+		params.WinnerIds = append(params.WinnerIds, winner)
+	}
+
+	return reposqlc.CreateBracketRound(ctx, params)
+}
+
+func (r *Repository) ListBracketRounds(ctx context.Context, contestID model.ContestID) ([]*model.BracketRound, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	contestUUID, err := uuid.Parse(string(contestID))
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := reposqlc.ListBracketRounds(ctx, pgtype.UUID{Bytes: contestUUID, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.BracketRound, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, toModelBracketRound(row))
+	}
+	return result, nil
+}
+
+// ListDueBracketRounds возвращает раунды всех конкурсов, которые пора открыть или закрыть
+func (r *Repository) ListDueBracketRounds(ctx context.Context, now time.Time) ([]*model.BracketRound, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	rows, err := reposqlc.ListDueBracketRounds(ctx, pgtype.Timestamptz{Time: now, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.BracketRound, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, toModelBracketRound(row))
+	}
+	return result, nil
+}
+
+// SetBracketRoundStatus меняет статус раунда, только если он все еще в статусе from.
+// false - переход уже выполнил другой экземпляр сервера.
+func (r *Repository) SetBracketRoundStatus(ctx context.Context, contestID model.ContestID, round int, from, to model.BracketRoundStatus) (bool, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	contestUUID, err := uuid.Parse(string(contestID))
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := reposqlc.SetBracketRoundStatus(ctx, &sqlc_repository.SetBracketRoundStatusParams{
+		Status:     string(to),
+		ContestID:  pgtype.UUID{Bytes: contestUUID, Valid: true},
+		Round:      int32(round),
+		FromStatus: string(from),
+	})
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func (r *Repository) ListBracketMatchups(ctx context.Context, contestID model.ContestID) ([]*model.BracketMatchup, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	contestUUID, err := uuid.Parse(string(contestID))
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := reposqlc.ListBracketMatchups(ctx, pgtype.UUID{Bytes: contestUUID, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.BracketMatchup, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, toModelBracketMatchup((*sqlc_repository.GetBracketMatchupRow)(row)))
+	}
+	return result, nil
+}
+
+func (r *Repository) GetBracketMatchup(ctx context.Context, contestID model.ContestID, matchupID string) (*model.BracketMatchup, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	contestUUID, err := uuid.Parse(string(contestID))
+	if err != nil {
+		return nil, err
+	}
+	matchupUUID, err := uuid.Parse(matchupID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid matchup id", model.ErrBadRequest)
+	}
+
+	row, err := reposqlc.GetBracketMatchup(ctx, &sqlc_repository.GetBracketMatchupParams{
+		ContestID: pgtype.UUID{Bytes: contestUUID, Valid: true},
+		ID:        pgtype.UUID{Bytes: matchupUUID, Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %v", model.ErrorNotFound, err)
+		}
+		return nil, err
+	}
+	return toModelBracketMatchup(row), nil
+}
+
+func decideBracketMatchup(ctx context.Context, reposqlc *sqlc_repository.Queries, matchupID string, winnerID model.ParticipantID) error {
+	matchupUUID, err := uuid.Parse(matchupID)
+	if err != nil {
+		return err
+	}
+	winnerUUID, err := parseOptionalParticipantID(winnerID)
+	if err != nil {
+		return err
+	}
+
+	return reposqlc.DecideBracketMatchup(ctx, &sqlc_repository.DecideBracketMatchupParams{
+		ID:       pgtype.UUID{Bytes: matchupUUID, Valid: true},
+		WinnerID: winnerUUID,
+	})
+}
+
+// ForfeitBracketMatchups отдает сопернику все нерешенные пары участника и возвращает их id
+func (r *Repository) ForfeitBracketMatchups(ctx context.Context, contestID model.ContestID, participantID model.ParticipantID) ([]string, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	contestUUID, err := uuid.Parse(string(contestID))
+	if err != nil {
+		return nil, err
+	}
+	participantUUID, err := uuid.Parse(string(participantID))
+	if err != nil {
+		return nil, err
+	}
+
+	ids, err := reposqlc.ForfeitBracketMatchups(ctx, &sqlc_repository.ForfeitBracketMatchupsParams{
+		ContestID:     pgtype.UUID{Bytes: contestUUID, Valid: true},
+		ParticipantID: pgtype.UUID{Bytes: participantUUID, Valid: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]string, 0, len(ids))
+	for _, id := range ids {
+		result = append(result, uuid.UUID(id.Bytes).String())
+	}
+	return result, nil
+}
+
+func (r *Repository) UpsertBracketVote(ctx context.Context, matchupID string, userID model.UserID, participantID model.ParticipantID) error {
+	reposqlc := sqlc_repository.New(r.conn)
+	matchupUUID, err := uuid.Parse(matchupID)
+	if err != nil {
+		return err
+	}
+	participantUUID, err := uuid.Parse(string(participantID))
+	if err != nil {
+		return err
+	}
+
+	return reposqlc.UpsertBracketVote(ctx, &sqlc_repository.UpsertBracketVoteParams{
+		MatchupID:     pgtype.UUID{Bytes: matchupUUID, Valid: true},
+		UserID:        int64(userID),
+		ParticipantID: pgtype.UUID{Bytes: participantUUID, Valid: true},
+	})
+}
+
+// ListBracketVotesByUser возвращает голоса пользователя в парах сетки: matchup id -> участник
+func (r *Repository) ListBracketVotesByUser(ctx context.Context, contestID model.ContestID, userID model.UserID) (map[string]model.ParticipantID, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	contestUUID, err := uuid.Parse(string(contestID))
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := reposqlc.ListBracketVotesByUser(ctx, &sqlc_repository.ListBracketVotesByUserParams{
+		ContestID: pgtype.UUID{Bytes: contestUUID, Valid: true},
+		UserID:    int64(userID),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]model.ParticipantID, len(rows))
+	for _, row := range rows {
+		result[uuidString(row.MatchupID)] = model.ParticipantID(uuidString(row.ParticipantID))
+	}
+	return result, nil
+}
+
+// CountPhotoLikesByParticipant возвращает сумму лайков фото каждого участника конкурса (для посева)
+func (r *Repository) CountPhotoLikesByParticipant(ctx context.Context, contestID model.ContestID) (map[model.ParticipantID]int64, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	contestUUID, err := uuid.Parse(string(contestID))
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := reposqlc.CountPhotoLikesByParticipant(ctx, pgtype.UUID{Bytes: contestUUID, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[model.ParticipantID]int64, len(rows))
+	for _, row := range rows {
+		result[model.ParticipantID(uuidString(row.ParticipantID))] = row.Likes
+	}
+	return result, nil
+}
+
+// parseOptionalParticipantID: пустой id - NULL
+func parseOptionalParticipantID(participantID model.ParticipantID) (pgtype.UUID, error) {
+	if participantID == "" {
+		return pgtype.UUID{}, nil
+	}
+	id, err := uuid.Parse(string(participantID))
+	if err != nil {
+		return pgtype.UUID{}, fmt.Errorf("%w: invalid participant id", model.ErrBadRequest)
+	}
+	return pgtype.UUID{Bytes: id, Valid: true}, nil
+}
+
+func toModelBracket(bracket *sqlc_repository.ContestBracket) *model.Bracket {
+	return &model.Bracket{
+		ContestID:            model.ContestID(uuidString(bracket.ContestID)),
+		Seeding:              model.BracketSeeding(bracket.Seeding),
+		RoundDurationMinutes: int(bracket.RoundDurationMinutes),
+		Status:               model.BracketStatus(bracket.Status),
+		CurrentRound:         int(bracket.CurrentRound),
+		ChampionID:           model.ParticipantID(uuidString(bracket.ChampionParticipantID)),
+		CreatedAt:            bracket.CreatedAt.Time,
+		UpdatedAt:            bracket.UpdatedAt.Time,
+	}
+}
+
+func toModelBracketRound(round *sqlc_repository.ContestBracketRound) *model.BracketRound {
+	return &model.BracketRound{
+		ContestID: model.ContestID(uuidString(round.ContestID)),
+		Round:     int(round.Round),
+		Status:    model.BracketRoundStatus(round.Status),
+		StartsAt:  round.StartsAt.Time,
+		EndsAt:    round.EndsAt.Time,
+	}
+}
+
+func toModelBracketMatchup(row *sqlc_repository.GetBracketMatchupRow) *model.BracketMatchup {
+	return &model.BracketMatchup{
+		ID:             uuidString(row.ID),
+		Round:          int(row.Round),
+		Position:       int(row.Position),
+		ParticipantAID: model.ParticipantID(uuidString(row.ParticipantAID)),
+		ParticipantBID: model.ParticipantID(uuidString(row.ParticipantBID)),
+		SeedA:          int(row.SeedA),
+		SeedB:          int(row.SeedB),
+		VotesA:         row.VotesA,
+		VotesB:         row.VotesB,
+		WinnerID:       model.ParticipantID(uuidString(row.WinnerID)),
+		DecidedAt:      timePtr(row.DecidedAt),
+	}
+}
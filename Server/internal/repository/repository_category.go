@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"toppet/server/internal/model"
+	sqlc_repository "toppet/server/internal/repository_sqlc"
+)
+
+func (r *Repository) CreateContestCategory(ctx context.Context, category *model.ContestCategory) (*model.ContestCategory, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	contestUUID, err := uuid.Parse(string(category.ContestID))
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := reposqlc.CreateContestCategory(ctx, &sqlc_repository.CreateContestCategoryParams{
+		ID:          pgtype.UUID{Bytes: uuid.New(), Valid: true},
+		ContestID:   pgtype.UUID{Bytes: contestUUID, Valid: true},
+		Name:        category.Name,
+		Description: category.Description,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toModelContestCategory(created), nil
+}
+
+func (r *Repository) GetContestCategory(ctx context.Context, contestID model.ContestID, categoryID model.CategoryID) (*model.ContestCategory, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	contestUUID, err := uuid.Parse(string(contestID))
+	if err != nil {
+		return nil, err
+	}
+	categoryUUID, err := uuid.Parse(string(categoryID))
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid category id", model.ErrBadRequest)
+	}
+
+	category, err := reposqlc.GetContestCategory(ctx, &sqlc_repository.GetContestCategoryParams{
+		ContestID: pgtype.UUID{Bytes: contestUUID, Valid: true},
+		ID:        pgtype.UUID{Bytes: categoryUUID, Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %v", model.ErrorNotFound, err)
+		}
+		return nil, err
+	}
+	return toModelContestCategory(category), nil
+}
+
+func (r *Repository) ListContestCategories(ctx context.Context, contestID model.ContestID) ([]*model.ContestCategory, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	contestUUID, err := uuid.Parse(string(contestID))
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := reposqlc.ListContestCategories(ctx, pgtype.UUID{Bytes: contestUUID, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.ContestCategory, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, toModelContestCategory(row))
+	}
+	return result, nil
+}
+
+func (r *Repository) UpdateContestCategory(ctx context.Context, category *model.ContestCategory) (*model.ContestCategory, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	contestUUID, err := uuid.Parse(string(category.ContestID))
+	if err != nil {
+		return nil, err
+	}
+	categoryUUID, err := uuid.Parse(string(category.ID))
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid category id", model.ErrBadRequest)
+	}
+
+	updated, err := reposqlc.UpdateContestCategory(ctx, &sqlc_repository.UpdateContestCategoryParams{
+		ContestID:   pgtype.UUID{Bytes: contestUUID, Valid: true},
+		ID:          pgtype.UUID{Bytes: categoryUUID, Valid: true},
+		Name:        category.Name,
+		Description: category.Description,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %v", model.ErrorNotFound, err)
+		}
+		return nil, err
+	}
+	return toModelContestCategory(updated), nil
+}
+
+// DeleteContestCategory удаляет номинацию вместе с голосами в ней (каскадно)
+func (r *Repository) DeleteContestCategory(ctx context.Context, contestID model.ContestID, categoryID model.CategoryID) error {
+	reposqlc := sqlc_repository.New(r.conn)
+	contestUUID, err := uuid.Parse(string(contestID))
+	if err != nil {
+		return err
+	}
+	categoryUUID, err := uuid.Parse(string(categoryID))
+	if err != nil {
+		return fmt.Errorf("%w: invalid category id", model.ErrBadRequest)
+	}
+
+	return reposqlc.DeleteContestCategory(ctx, &sqlc_repository.DeleteContestCategoryParams{
+		ContestID: pgtype.UUID{Bytes: contestUUID, Valid: true},
+		ID:        pgtype.UUID{Bytes: categoryUUID, Valid: true},
+	})
+}
+
+// parseCategoryID преобразует номинацию в nullable UUID: пустая номинация (главный приз) - NULL
+func parseCategoryID(categoryID model.CategoryID) (pgtype.UUID, error) {
+	if categoryID == "" {
+		return pgtype.UUID{}, nil
+	}
+	id, err := uuid.Parse(string(categoryID))
+	if err != nil {
+		return pgtype.UUID{}, fmt.Errorf("%w: invalid category id", model.ErrBadRequest)
+	}
+	return pgtype.UUID{Bytes: id, Valid: true}, nil
+}
+
+func toModelContestCategory(category *sqlc_repository.ContestCategory) *model.ContestCategory {
+	return &model.ContestCategory{
+		ID:          model.CategoryID(uuidString(category.ID)),
+		ContestID:   model.ContestID(uuidString(category.ContestID)),
+		Name:        category.Name,
+		Description: category.Description,
+		Position:    int(category.Position),
+		CreatedAt:   category.CreatedAt.Time,
+		UpdatedAt:   category.UpdatedAt.Time,
+	}
+}
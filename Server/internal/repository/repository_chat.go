@@ -151,6 +151,28 @@ func (r *Repository) ListChatMessages(ctx context.Context, contestID model.Conte
 	return result, total, nil
 }
 
+// ListChatMessagesByUser все сообщения пользователя в чатах конкурсов, кроме системных
+func (r *Repository) ListChatMessagesByUser(ctx context.Context, userID model.UserID) ([]*model.ChatMessage, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	messages, err := reposqlc.ListChatMessagesByUser(ctx, int64(userID))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.ChatMessage, len(messages))
+	for i, m := range messages {
+		result[i] = &model.ChatMessage{
+			ID:        model.ChatMessageID(uuidString(m.ID)),
+			ContestID: model.ContestID(uuidString(m.ContestID)),
+			UserID:    model.UserID(m.UserID),
+			Text:      m.Text,
+			CreatedAt: m.CreatedAt.Time,
+			UpdatedAt: m.UpdatedAt.Time,
+		}
+	}
+	return result, nil
+}
+
 func (r *Repository) UpdateChatMessage(ctx context.Context, messageID model.ChatMessageID, userID model.UserID, text string) (*model.ChatMessage, error) {
 	reposqlc := sqlc_repository.New(r.conn)
 	messageUUID, err := uuid.Parse(string(messageID))
@@ -55,9 +55,52 @@ func (r *Repository) CreateComment(ctx context.Context, participantID model.Part
 		Text:          comment.Text,
 		CreatedAt:     comment.CreatedAt.Time,
 		UpdatedAt:     comment.UpdatedAt.Time,
+		Hidden:        comment.HiddenAt.Valid,
 	}, nil
 }
 
+// ListParticipantCommenters авторы видимых комментариев к заявке
+func (r *Repository) ListParticipantCommenters(ctx context.Context, participantID model.ParticipantID) ([]model.UserID, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	participantUUID, err := uuid.Parse(string(participantID))
+	if err != nil {
+		return nil, err
+	}
+
+	ids, err := reposqlc.ListParticipantCommenters(ctx, pgtype.UUID{Bytes: participantUUID, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]model.UserID, len(ids))
+	for i, id := range ids {
+		result[i] = model.UserID(id)
+	}
+	return result, nil
+}
+
+// ListCommentsByUser все комментарии пользователя, включая скрытые модераторами
+func (r *Repository) ListCommentsByUser(ctx context.Context, userID model.UserID) ([]*model.Comment, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	comments, err := reposqlc.ListCommentsByUser(ctx, int64(userID))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.Comment, len(comments))
+	for i, c := range comments {
+		result[i] = &model.Comment{
+			ID:            model.CommentID(uuidString(c.ID)),
+			ParticipantID: model.ParticipantID(uuidString(c.ParticipantID)),
+			UserID:        model.UserID(c.UserID),
+			Text:          c.Text,
+			Hidden:        c.HiddenAt.Valid,
+			CreatedAt:     c.CreatedAt.Time,
+			UpdatedAt:     c.UpdatedAt.Time,
+		}
+	}
+	return result, nil
+}
+
 func (r *Repository) GetComment(ctx context.Context, commentID model.CommentID) (*model.Comment, error) {
 	reposqlc := sqlc_repository.New(r.conn)
 	commentUUID, err := uuid.Parse(string(commentID))
@@ -97,6 +140,7 @@ func (r *Repository) GetComment(ctx context.Context, commentID model.CommentID)
 		Text:          comment.Text,
 		CreatedAt:     comment.CreatedAt.Time,
 		UpdatedAt:     comment.UpdatedAt.Time,
+		Hidden:        comment.HiddenAt.Valid,
 	}, nil
 }
 
@@ -177,6 +221,7 @@ func (r *Repository) UpdateComment(ctx context.Context, commentID model.CommentI
 		Text:          comment.Text,
 		CreatedAt:     comment.CreatedAt.Time,
 		UpdatedAt:     comment.UpdatedAt.Time,
+		Hidden:        comment.HiddenAt.Valid,
 	}, nil
 }
 
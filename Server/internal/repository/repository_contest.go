@@ -14,15 +14,30 @@ import (
 )
 
 func (r *Repository) CreateContest(ctx context.Context, userID model.UserID, title, description string) (*model.Contest, error) {
-	reposqlc := sqlc_repository.New(r.conn)
 	contestUUID := uuid.New()
 
-	contest, err := reposqlc.CreateContest(ctx, &sqlc_repository.CreateContestParams{
-		ID:              pgtype.UUID{Bytes: contestUUID, Valid: true},
-		CreatedByUserID: int64(userID),
-		Title:           title,
-		Description:     description,
-		Status:          string(model.ContestStatusDraft),
+	// Создатель конкурса - его владелец в contest_members; конкурс без владельца не создается
+	var contest *sqlc_repository.Contest
+	err := r.inTx(ctx, func(reposqlc *sqlc_repository.Queries) error {
+		var err error
+		contest, err = reposqlc.CreateContest(ctx, &sqlc_repository.CreateContestParams{
+			ID:              pgtype.UUID{Bytes: contestUUID, Valid: true},
+			CreatedByUserID: int64(userID),
+			Title:           title,
+			Description:     description,
+			Status:          string(model.ContestStatusDraft),
+		})
+		if err != nil {
+			return err
+		}
+		_, err = reposqlc.CreateContestMember(ctx, &sqlc_repository.CreateContestMemberParams{
+			ContestID:  contest.ID,
+			UserID:     contest.CreatedByUserID,
+			Role:       string(model.ContestMemberOwner),
+			Status:     string(model.ContestMemberActive),
+			AcceptedAt: contest.CreatedAt,
+		})
+		return err
 	})
 	if err != nil {
 		return nil, err
@@ -39,8 +54,13 @@ func (r *Repository) CreateContest(ctx context.Context, userID model.UserID, tit
 		Title:           contest.Title,
 		Description:     contest.Description,
 		Status:          model.ContestStatus(contest.Status),
+		VotingMode:      model.VotingMode(contest.VotingMode),
+		MaxChoices:      int(contest.MaxChoices),
+		MinBallots:      int(contest.MinBallots),
+		JuryWeight:      int(contest.JuryWeight),
 		CreatedAt:       contest.CreatedAt.Time,
 		UpdatedAt:       contest.UpdatedAt.Time,
+		Hidden:          contest.HiddenAt.Valid,
 	}, nil
 }
 
@@ -70,8 +90,13 @@ func (r *Repository) GetContest(ctx context.Context, contestID model.ContestID)
 		Title:           contest.Title,
 		Description:     contest.Description,
 		Status:          model.ContestStatus(contest.Status),
+		VotingMode:      model.VotingMode(contest.VotingMode),
+		MaxChoices:      int(contest.MaxChoices),
+		MinBallots:      int(contest.MinBallots),
+		JuryWeight:      int(contest.JuryWeight),
 		CreatedAt:       contest.CreatedAt.Time,
 		UpdatedAt:       contest.UpdatedAt.Time,
+		Hidden:          contest.HiddenAt.Valid,
 	}, nil
 }
 
@@ -111,6 +136,10 @@ func (r *Repository) ListContests(ctx context.Context, status *model.ContestStat
 			Title:           c.Title,
 			Description:     c.Description,
 			Status:          model.ContestStatus(c.Status),
+			VotingMode:      model.VotingMode(c.VotingMode),
+			MaxChoices:      int(c.MaxChoices),
+			MinBallots:      int(c.MinBallots),
+			JuryWeight:      int(c.JuryWeight),
 			CreatedAt:       c.CreatedAt.Time,
 			UpdatedAt:       c.UpdatedAt.Time,
 		}
@@ -119,6 +148,64 @@ func (r *Repository) ListContests(ctx context.Context, status *model.ContestStat
 	return result, total, nil
 }
 
+// ListPublicContestsByCreator опубликованные и не скрытые конкурсы пользователя, новые первыми
+func (r *Repository) ListPublicContestsByCreator(ctx context.Context, userID model.UserID, limit int) ([]*model.Contest, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	contests, err := reposqlc.ListPublicContestsByCreator(ctx, &sqlc_repository.ListPublicContestsByCreatorParams{
+		CreatedByUserID: int64(userID),
+		Limit:           int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.Contest, len(contests))
+	for i, c := range contests {
+		result[i] = &model.Contest{
+			ID:              model.ContestID(uuidString(c.ID)),
+			CreatedByUserID: model.UserID(c.CreatedByUserID),
+			Title:           c.Title,
+			Description:     c.Description,
+			Status:          model.ContestStatus(c.Status),
+			VotingMode:      model.VotingMode(c.VotingMode),
+			MaxChoices:      int(c.MaxChoices),
+			MinBallots:      int(c.MinBallots),
+			JuryWeight:      int(c.JuryWeight),
+			CreatedAt:       c.CreatedAt.Time,
+			UpdatedAt:       c.UpdatedAt.Time,
+		}
+	}
+	return result, nil
+}
+
+// ListContestsByCreator все конкурсы пользователя, включая черновики и скрытые
+func (r *Repository) ListContestsByCreator(ctx context.Context, userID model.UserID) ([]*model.Contest, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	contests, err := reposqlc.ListContestsByCreator(ctx, int64(userID))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.Contest, len(contests))
+	for i, c := range contests {
+		result[i] = &model.Contest{
+			ID:              model.ContestID(uuidString(c.ID)),
+			CreatedByUserID: model.UserID(c.CreatedByUserID),
+			Title:           c.Title,
+			Description:     c.Description,
+			Status:          model.ContestStatus(c.Status),
+			VotingMode:      model.VotingMode(c.VotingMode),
+			MaxChoices:      int(c.MaxChoices),
+			MinBallots:      int(c.MinBallots),
+			JuryWeight:      int(c.JuryWeight),
+			Hidden:          c.HiddenAt.Valid,
+			CreatedAt:       c.CreatedAt.Time,
+			UpdatedAt:       c.UpdatedAt.Time,
+		}
+	}
+	return result, nil
+}
+
 func (r *Repository) UpdateContest(ctx context.Context, contestID model.ContestID, title, description string) (*model.Contest, error) {
 	reposqlc := sqlc_repository.New(r.conn)
 	contestUUID, err := uuid.Parse(string(contestID))
@@ -146,8 +233,13 @@ func (r *Repository) UpdateContest(ctx context.Context, contestID model.ContestI
 		Title:           contest.Title,
 		Description:     contest.Description,
 		Status:          model.ContestStatus(contest.Status),
+		VotingMode:      model.VotingMode(contest.VotingMode),
+		MaxChoices:      int(contest.MaxChoices),
+		MinBallots:      int(contest.MinBallots),
+		JuryWeight:      int(contest.JuryWeight),
 		CreatedAt:       contest.CreatedAt.Time,
 		UpdatedAt:       contest.UpdatedAt.Time,
+		Hidden:          contest.HiddenAt.Valid,
 	}, nil
 }
 
@@ -177,8 +269,85 @@ func (r *Repository) UpdateContestStatus(ctx context.Context, contestID model.Co
 		Title:           contest.Title,
 		Description:     contest.Description,
 		Status:          model.ContestStatus(contest.Status),
+		VotingMode:      model.VotingMode(contest.VotingMode),
+		MaxChoices:      int(contest.MaxChoices),
+		MinBallots:      int(contest.MinBallots),
+		JuryWeight:      int(contest.JuryWeight),
+		CreatedAt:       contest.CreatedAt.Time,
+		UpdatedAt:       contest.UpdatedAt.Time,
+		Hidden:          contest.HiddenAt.Valid,
+	}, nil
+}
+
+// UpdateContestVotingMode меняет режим голосования конкурса
+func (r *Repository) UpdateContestVotingMode(ctx context.Context, contestID model.ContestID, mode model.VotingMode, maxChoices, minBallots int) (*model.Contest, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	contestUUID, err := uuid.Parse(string(contestID))
+	if err != nil {
+		return nil, err
+	}
+
+	contest, err := reposqlc.UpdateContestVotingMode(ctx, &sqlc_repository.UpdateContestVotingModeParams{
+		ID:         pgtype.UUID{Bytes: contestUUID, Valid: true},
+		VotingMode: string(mode),
+		MaxChoices: int32(maxChoices),
+		MinBallots: int32(minBallots),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %v", model.ErrorNotFound, err)
+		}
+		return nil, err
+	}
+
+	return &model.Contest{
+		ID:              model.ContestID(uuidString(contest.ID)),
+		CreatedByUserID: model.UserID(contest.CreatedByUserID),
+		Title:           contest.Title,
+		Description:     contest.Description,
+		Status:          model.ContestStatus(contest.Status),
+		VotingMode:      model.VotingMode(contest.VotingMode),
+		MaxChoices:      int(contest.MaxChoices),
+		MinBallots:      int(contest.MinBallots),
+		JuryWeight:      int(contest.JuryWeight),
+		CreatedAt:       contest.CreatedAt.Time,
+		UpdatedAt:       contest.UpdatedAt.Time,
+		Hidden:          contest.HiddenAt.Valid,
+	}, nil
+}
+
+// UpdateContestJuryWeight меняет долю оценки жюри в итоге конкурса
+func (r *Repository) UpdateContestJuryWeight(ctx context.Context, contestID model.ContestID, juryWeight int) (*model.Contest, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	contestUUID, err := uuid.Parse(string(contestID))
+	if err != nil {
+		return nil, err
+	}
+
+	contest, err := reposqlc.UpdateContestJuryWeight(ctx, &sqlc_repository.UpdateContestJuryWeightParams{
+		ID:         pgtype.UUID{Bytes: contestUUID, Valid: true},
+		JuryWeight: int32(juryWeight),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %v", model.ErrorNotFound, err)
+		}
+		return nil, err
+	}
+
+	return &model.Contest{
+		ID:              model.ContestID(uuidString(contest.ID)),
+		CreatedByUserID: model.UserID(contest.CreatedByUserID),
+		Title:           contest.Title,
+		Description:     contest.Description,
+		Status:          model.ContestStatus(contest.Status),
+		VotingMode:      model.VotingMode(contest.VotingMode),
+		MaxChoices:      int(contest.MaxChoices),
+		MinBallots:      int(contest.MinBallots),
+		JuryWeight:      int(contest.JuryWeight),
 		CreatedAt:       contest.CreatedAt.Time,
 		UpdatedAt:       contest.UpdatedAt.Time,
+		Hidden:          contest.HiddenAt.Valid,
 	}, nil
 }
 
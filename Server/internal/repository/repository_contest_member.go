@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"toppet/server/internal/model"
+	sqlc_repository "toppet/server/internal/repository_sqlc"
+)
+
+func (r *Repository) CreateContestMember(ctx context.Context, member *model.ContestMember) (*model.ContestMember, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	contestUUID, err := uuid.Parse(string(member.ContestID))
+	if err != nil {
+		return nil, err
+	}
+
+	params := &sqlc_repository.CreateContestMemberParams{
+		ContestID: pgtype.UUID{Bytes: contestUUID, Valid: true},
+		UserID:    int64(member.UserID),
+		Role:      string(member.Role),
+		Status:    string(member.Status),
+	}
+	if member.InvitedByUserID != nil {
+		invitedBy := int64(*member.InvitedByUserID)
+		params.InvitedByUserID = &invitedBy
+	}
+	if member.AcceptedAt != nil {
+		params.AcceptedAt = pgtype.Timestamptz{Time: *member.AcceptedAt, Valid: true}
+	}
+
+	created, err := reposqlc.CreateContestMember(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return toModelContestMember(created), nil
+}
+
+func (r *Repository) GetContestMember(ctx context.Context, contestID model.ContestID, userID model.UserID) (*model.ContestMember, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	contestUUID, err := uuid.Parse(string(contestID))
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := reposqlc.GetContestMember(ctx, &sqlc_repository.GetContestMemberParams{
+		ContestID: pgtype.UUID{Bytes: contestUUID, Valid: true},
+		UserID:    int64(userID),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %v", model.ErrorNotFound, err)
+		}
+		return nil, err
+	}
+	return toModelContestMember(member), nil
+}
+
+func (r *Repository) ListContestMembers(ctx context.Context, contestID model.ContestID) ([]*model.ContestMember, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	contestUUID, err := uuid.Parse(string(contestID))
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := reposqlc.ListContestMembers(ctx, pgtype.UUID{Bytes: contestUUID, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.ContestMember, len(rows))
+	for i, row := range rows {
+		member := toModelContestMember(&sqlc_repository.ContestMember{
+			ContestID:       row.ContestID,
+			UserID:          row.UserID,
+			Role:            row.Role,
+			Status:          row.Status,
+			InvitedByUserID: row.InvitedByUserID,
+			CreatedAt:       row.CreatedAt,
+			AcceptedAt:      row.AcceptedAt,
+		})
+		member.UserName = row.UserName
+		result[i] = member
+	}
+	return result, nil
+}
+
+func (r *Repository) UpdateContestMemberRole(ctx context.Context, contestID model.ContestID, userID model.UserID, role model.ContestMemberRole) (*model.ContestMember, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	contestUUID, err := uuid.Parse(string(contestID))
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := reposqlc.UpdateContestMemberRole(ctx, &sqlc_repository.UpdateContestMemberRoleParams{
+		ContestID: pgtype.UUID{Bytes: contestUUID, Valid: true},
+		UserID:    int64(userID),
+		Role:      string(role),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %v", model.ErrorNotFound, err)
+		}
+		return nil, err
+	}
+	return toModelContestMember(member), nil
+}
+
+func (r *Repository) AcceptContestMemberInvite(ctx context.Context, contestID model.ContestID, userID model.UserID) (*model.ContestMember, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	contestUUID, err := uuid.Parse(string(contestID))
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := reposqlc.AcceptContestMemberInvite(ctx, &sqlc_repository.AcceptContestMemberInviteParams{
+		ContestID: pgtype.UUID{Bytes: contestUUID, Valid: true},
+		UserID:    int64(userID),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %v", model.ErrorNotFound, err)
+		}
+		return nil, err
+	}
+	return toModelContestMember(member), nil
+}
+
+func (r *Repository) DeleteContestMember(ctx context.Context, contestID model.ContestID, userID model.UserID) error {
+	reposqlc := sqlc_repository.New(r.conn)
+	contestUUID, err := uuid.Parse(string(contestID))
+	if err != nil {
+		return err
+	}
+
+	return reposqlc.DeleteContestMember(ctx, &sqlc_repository.DeleteContestMemberParams{
+		ContestID: pgtype.UUID{Bytes: contestUUID, Valid: true},
+		UserID:    int64(userID),
+	})
+}
+
+// TransferContestOwnership в одной транзакции понижает прежнего владельца до organizer, повышает нового
+// и меняет created_by_user_id конкурса. Порядок важен: в конкурсе не может быть двух владельцев.
+func (r *Repository) TransferContestOwnership(ctx context.Context, contestID model.ContestID, previousOwnerID, newOwnerID model.UserID) error {
+	contestUUID, err := uuid.Parse(string(contestID))
+	if err != nil {
+		return err
+	}
+	contestPgUUID := pgtype.UUID{Bytes: contestUUID, Valid: true}
+
+	return r.inTx(ctx, func(reposqlc *sqlc_repository.Queries) error {
+		if err := reposqlc.DemoteContestOwner(ctx, &sqlc_repository.DemoteContestOwnerParams{
+			ContestID: contestPgUUID,
+			UserID:    int64(previousOwnerID),
+		}); err != nil {
+			return err
+		}
+		promoted, err := reposqlc.PromoteContestOwner(ctx, &sqlc_repository.PromoteContestOwnerParams{
+			ContestID: contestPgUUID,
+			UserID:    int64(newOwnerID),
+		})
+		if err != nil {
+			return err
+		}
+		if promoted == 0 {
+			return fmt.Errorf("%w: new owner is not an active contest member", model.ErrorNotFound)
+		}
+		return reposqlc.UpdateContestOwner(ctx, &sqlc_repository.UpdateContestOwnerParams{
+			ID:              contestPgUUID,
+			CreatedByUserID: int64(newOwnerID),
+		})
+	})
+}
+
+func (r *Repository) GetChatMessageContestID(ctx context.Context, messageID model.ChatMessageID) (model.ContestID, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	messageUUID, err := uuid.Parse(string(messageID))
+	if err != nil {
+		return "", err
+	}
+
+	contestID, err := reposqlc.GetChatMessageContestID(ctx, pgtype.UUID{Bytes: messageUUID, Valid: true})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("%w: %v", model.ErrorNotFound, err)
+		}
+		return "", err
+	}
+	return model.ContestID(uuid.UUID(contestID.Bytes).String()), nil
+}
+
+func toModelContestMember(m *sqlc_repository.ContestMember) *model.ContestMember {
+	member := &model.ContestMember{
+		ContestID: model.ContestID(uuid.UUID(m.ContestID.Bytes).String()),
+		UserID:    model.UserID(m.UserID),
+		Role:      model.ContestMemberRole(m.Role),
+		Status:    model.ContestMemberStatus(m.Status),
+		CreatedAt: m.CreatedAt.Time,
+	}
+	if m.InvitedByUserID != nil {
+		invitedBy := model.UserID(*m.InvitedByUserID)
+		member.InvitedByUserID = &invitedBy
+	}
+	if m.AcceptedAt.Valid {
+		acceptedAt := m.AcceptedAt.Time
+		member.AcceptedAt = &acceptedAt
+	}
+	return member
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"toppet/server/internal/model"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// testRepository подключается к базе с примененными миграциями из TEST_DATABASE_URL; без нее тест пропускается
+func testRepository(t *testing.T) *Repository {
+	t.Helper()
+	databaseURL := os.Getenv("TEST_DATABASE_URL")
+	if databaseURL == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	pool, err := pgxpool.New(context.Background(), databaseURL)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	t.Cleanup(pool.Close)
+	return NewRepository(pool)
+}
+
+func TestRepository_TransferContestOwnership(t *testing.T) {
+	r := testRepository(t)
+	ctx := context.Background()
+
+	owner, err := r.CreateUser(ctx, "transfer-owner")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	organizer, err := r.CreateUser(ctx, "transfer-organizer")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	invited, err := r.CreateUser(ctx, "transfer-invited")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	contest, err := r.CreateContest(ctx, owner.ID, "Transfer", "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	t.Cleanup(func() { _ = r.DeleteContest(ctx, contest.ID) })
+
+	for _, member := range []*model.ContestMember{
+		{ContestID: contest.ID, UserID: organizer.ID, Role: model.ContestMemberOrganizer, Status: model.ContestMemberActive},
+		{ContestID: contest.ID, UserID: invited.ID, Role: model.ContestMemberOrganizer, Status: model.ContestMemberInvited},
+	} {
+		if _, err := r.CreateContestMember(ctx, member); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	// Приглашение не принято: транзакция откатывается, владелец остается прежним
+	if err := r.TransferContestOwnership(ctx, contest.ID, owner.ID, invited.ID); err == nil {
+		t.Fatal("Expected error for invited member")
+	}
+	if member, err := r.GetContestMember(ctx, contest.ID, owner.ID); err != nil || member.Role != model.ContestMemberOwner {
+		t.Fatalf("Expected owner to keep the contest, got %+v, %v", member, err)
+	}
+
+	if err := r.TransferContestOwnership(ctx, contest.ID, owner.ID, organizer.ID); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	previous, err := r.GetContestMember(ctx, contest.ID, owner.ID)
+	if err != nil || previous.Role != model.ContestMemberOrganizer {
+		t.Errorf("Expected previous owner to become organizer, got %+v, %v", previous, err)
+	}
+	next, err := r.GetContestMember(ctx, contest.ID, organizer.ID)
+	if err != nil || next.Role != model.ContestMemberOwner {
+		t.Errorf("Expected new owner, got %+v, %v", next, err)
+	}
+	updated, err := r.GetContest(ctx, contest.ID)
+	if err != nil || updated.CreatedByUserID != organizer.ID {
+		t.Errorf("Expected contest to belong to %d, got %+v, %v", organizer.ID, updated, err)
+	}
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"toppet/server/internal/model"
+	sqlc_repository "toppet/server/internal/repository_sqlc"
+)
+
+func (r *Repository) CreateEmailLoginToken(ctx context.Context, tokenHash, email string, expiresAt time.Time) error {
+	reposqlc := sqlc_repository.New(r.conn)
+	return reposqlc.CreateEmailLoginToken(ctx, &sqlc_repository.CreateEmailLoginTokenParams{
+		TokenHash: tokenHash,
+		Email:     email,
+		ExpiresAt: pgtype.Timestamptz{Time: expiresAt, Valid: true},
+	})
+}
+
+// ConsumeEmailLoginToken помечает токен использованным и возвращает email.
+// Использованный, просроченный или неизвестный токен дает model.ErrorNotFound.
+func (r *Repository) ConsumeEmailLoginToken(ctx context.Context, tokenHash string) (string, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	email, err := reposqlc.ConsumeEmailLoginToken(ctx, tokenHash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("%w: %v", model.ErrorNotFound, err)
+		}
+		return "", err
+	}
+	return email, nil
+}
+
+func (r *Repository) CountEmailLoginTokensSince(ctx context.Context, email string, since time.Time) (int64, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	return reposqlc.CountEmailLoginTokensSince(ctx, &sqlc_repository.CountEmailLoginTokensSinceParams{
+		Email:     email,
+		CreatedAt: pgtype.Timestamptz{Time: since, Valid: true},
+	})
+}
@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"toppet/server/internal/model"
+	sqlc_repository "toppet/server/internal/repository_sqlc"
+)
+
+func (r *Repository) GetNotificationPreferences(ctx context.Context, userID model.UserID) (*model.NotificationPreferences, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	prefs, err := reposqlc.GetNotificationPreferences(ctx, int64(userID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %v", model.ErrorNotFound, err)
+		}
+		return nil, err
+	}
+	return toModelNotificationPreferences(prefs), nil
+}
+
+// UpsertNotificationPreferences сохраняет переключатели писем; адрес не меняется
+func (r *Repository) UpsertNotificationPreferences(ctx context.Context, userID model.UserID, prefs *model.NotificationPreferences) (*model.NotificationPreferences, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	saved, err := reposqlc.UpsertNotificationPreferences(ctx, &sqlc_repository.UpsertNotificationPreferencesParams{
+		UserID:                int64(userID),
+		EmailVotingStarted:    prefs.EmailVotingStarted,
+		EmailResultsPublished: prefs.EmailResultsPublished,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toModelNotificationPreferences(saved), nil
+}
+
+// SetNotificationEmailIfEmpty запоминает адрес для писем, если он еще не известен
+func (r *Repository) SetNotificationEmailIfEmpty(ctx context.Context, userID model.UserID, email string) error {
+	reposqlc := sqlc_repository.New(r.conn)
+	return reposqlc.SetNotificationEmailIfEmpty(ctx, &sqlc_repository.SetNotificationEmailIfEmptyParams{
+		UserID: int64(userID),
+		Email:  &email,
+	})
+}
+
+// ListEmailRecipients возвращает тех из userIDs, кому можно отправить письмо о событии notificationType
+func (r *Repository) ListEmailRecipients(ctx context.Context, userIDs []model.UserID, notificationType model.NotificationType) ([]*model.EmailRecipient, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	ids := make([]int64, len(userIDs))
+	for i, userID := range userIDs {
+		ids[i] = int64(userID)
+	}
+	rows, err := reposqlc.ListEmailRecipients(ctx, &sqlc_repository.ListEmailRecipientsParams{
+		UserIds:          ids,
+		NotificationType: string(notificationType),
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*model.EmailRecipient, len(rows))
+	for i, row := range rows {
+		result[i] = &model.EmailRecipient{
+			UserID: model.UserID(row.UserID),
+			Email:  row.Email,
+			Locale: model.Locale(row.Locale),
+		}
+	}
+	return result, nil
+}
+
+// EnqueueEmails ставит письма в очередь одним запросом
+func (r *Repository) EnqueueEmails(ctx context.Context, emails []*model.OutboxEmail) error {
+	if len(emails) == 0 {
+		return nil
+	}
+	reposqlc := sqlc_repository.New(r.conn)
+	params := &sqlc_repository.EnqueueEmailsParams{
+		UserIds:         make([]int64, len(emails)),
+		ToEmails:        make([]string, len(emails)),
+		Subjects:        make([]string, len(emails)),
+		TextBodies:      make([]string, len(emails)),
+		HtmlBodies:      make([]string, len(emails)),
+		UnsubscribeUrls: make([]string, len(emails)),
+	}
+	for i, email := range emails {
+		if email.UserID != nil {
+			params.UserIds[i] = int64(*email.UserID)
+		}
+		params.ToEmails[i] = email.To
+		params.Subjects[i] = email.Subject
+		params.TextBodies[i] = email.Text
+		params.HtmlBodies[i] = email.HTML
+		params.UnsubscribeUrls[i] = email.UnsubscribeURL
+	}
+	_, err := reposqlc.EnqueueEmails(ctx, params)
+	return err
+}
+
+// ClaimEmailOutbox забирает до limit писем, готовых к отправке на момент now, и резервирует их до leaseUntil
+func (r *Repository) ClaimEmailOutbox(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*model.OutboxEmail, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	rows, err := reposqlc.ClaimEmailOutbox(ctx, &sqlc_repository.ClaimEmailOutboxParams{
+		LeaseUntil: pgtype.Timestamptz{Time: leaseUntil, Valid: true},
+		Now:        pgtype.Timestamptz{Time: now, Valid: true},
+		LimitCount: int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*model.OutboxEmail, len(rows))
+	for i, row := range rows {
+		result[i] = toModelOutboxEmail(row)
+	}
+	return result, nil
+}
+
+func (r *Repository) MarkEmailSent(ctx context.Context, emailID string) error {
+	reposqlc := sqlc_repository.New(r.conn)
+	id, err := uuid.Parse(emailID)
+	if err != nil {
+		return err
+	}
+	return reposqlc.MarkEmailSent(ctx, pgtype.UUID{Bytes: id, Valid: true})
+}
+
+// FailEmailAttempt сохраняет ошибку отправки: pending - повтор в nextAttemptAt, failed - попытки исчерпаны
+func (r *Repository) FailEmailAttempt(ctx context.Context, emailID string, status model.OutboxStatus, lastError string, nextAttemptAt time.Time) error {
+	reposqlc := sqlc_repository.New(r.conn)
+	id, err := uuid.Parse(emailID)
+	if err != nil {
+		return err
+	}
+	return reposqlc.FailEmailAttempt(ctx, &sqlc_repository.FailEmailAttemptParams{
+		ID:            pgtype.UUID{Bytes: id, Valid: true},
+		Status:        string(status),
+		LastError:     lastError,
+		NextAttemptAt: pgtype.Timestamptz{Time: nextAttemptAt, Valid: true},
+	})
+}
+
+func toModelNotificationPreferences(prefs *sqlc_repository.NotificationPreference) *model.NotificationPreferences {
+	result := &model.NotificationPreferences{
+		EmailVotingStarted:    prefs.EmailVotingStarted,
+		EmailResultsPublished: prefs.EmailResultsPublished,
+		UpdatedAt:             timePtr(prefs.UpdatedAt),
+	}
+	if prefs.Email != nil {
+		result.Email = *prefs.Email
+	}
+	return result
+}
+
+func toModelOutboxEmail(row *sqlc_repository.EmailOutbox) *model.OutboxEmail {
+	result := &model.OutboxEmail{
+		ID:             uuidString(row.ID),
+		To:             row.ToEmail,
+		Subject:        row.Subject,
+		Text:           row.TextBody,
+		HTML:           row.HtmlBody,
+		UnsubscribeURL: row.UnsubscribeUrl,
+		Attempts:       int(row.Attempts),
+	}
+	if row.UserID.Valid {
+		userID := model.UserID(row.UserID.Int64)
+		result.UserID = &userID
+	}
+	return result
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"toppet/server/internal/model"
+	sqlc_repository "toppet/server/internal/repository_sqlc"
+)
+
+func (r *Repository) GetContestEntryRules(ctx context.Context, contestID model.ContestID) (*model.EntryRules, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	contestUUID, err := uuid.Parse(string(contestID))
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := reposqlc.GetContestEntryRules(ctx, pgtype.UUID{Bytes: contestUUID, Valid: true})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %v", model.ErrorNotFound, err)
+		}
+		return nil, err
+	}
+	return toModelEntryRules(rules), nil
+}
+
+func (r *Repository) UpsertContestEntryRules(ctx context.Context, rules *model.EntryRules) (*model.EntryRules, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	contestUUID, err := uuid.Parse(string(rules.ContestID))
+	if err != nil {
+		return nil, err
+	}
+
+	requiredFields := rules.RequiredFields
+	if requiredFields == nil {
+		requiredFields = []string{}
+	}
+	allowedSpecies := rules.AllowedSpecies
+	if allowedSpecies == nil {
+		allowedSpecies = []string{}
+	}
+	var deadline pgtype.Timestamptz
+	if rules.EntryDeadline != nil {
+		deadline = pgtype.Timestamptz{Time: *rules.EntryDeadline, Valid: true}
+	}
+
+	saved, err := reposqlc.UpsertContestEntryRules(ctx, &sqlc_repository.UpsertContestEntryRulesParams{
+		ContestID:           pgtype.UUID{Bytes: contestUUID, Valid: true},
+		MaxEntriesPerUser:   int32(rules.MaxEntriesPerUser),
+		MaxEntries:          int32(rules.MaxEntries),
+		MaxPhotosPerEntry:   int32(rules.MaxPhotosPerEntry),
+		MaxPhotoSizeMb:      int32(rules.MaxPhotoSizeMB),
+		VideoAllowed:        rules.VideoAllowed,
+		MaxVideoDurationSec: int32(rules.MaxVideoDurationSec),
+		MaxVideoSizeMb:      int32(rules.MaxVideoSizeMB),
+		RequiredFields:      requiredFields,
+		EntryDeadline:       deadline,
+		RequireApproval:     rules.RequireApproval,
+		AllowedSpecies:      allowedSpecies,
+		MinAgeMonths:        int32(rules.MinAgeMonths),
+		MaxAgeMonths:        int32(rules.MaxAgeMonths),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toModelEntryRules(saved), nil
+}
+
+func (r *Repository) CountParticipantsByContest(ctx context.Context, contestID model.ContestID) (int64, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	contestUUID, err := uuid.Parse(string(contestID))
+	if err != nil {
+		return 0, err
+	}
+	return reposqlc.CountParticipantsByContest(ctx, pgtype.UUID{Bytes: contestUUID, Valid: true})
+}
+
+func (r *Repository) CountParticipantsByContestAndUser(ctx context.Context, contestID model.ContestID, userID model.UserID) (int64, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	contestUUID, err := uuid.Parse(string(contestID))
+	if err != nil {
+		return 0, err
+	}
+	return reposqlc.CountParticipantsByContestAndUser(ctx, &sqlc_repository.CountParticipantsByContestAndUserParams{
+		ContestID: pgtype.UUID{Bytes: contestUUID, Valid: true},
+		UserID:    int64(userID),
+	})
+}
+
+func (r *Repository) CountPhotosByParticipant(ctx context.Context, participantID model.ParticipantID) (int64, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	participantUUID, err := uuid.Parse(string(participantID))
+	if err != nil {
+		return 0, err
+	}
+	return reposqlc.CountPhotosByParticipant(ctx, pgtype.UUID{Bytes: participantUUID, Valid: true})
+}
+
+func toModelEntryRules(rules *sqlc_repository.ContestEntryRule) *model.EntryRules {
+	return &model.EntryRules{
+		ContestID:           model.ContestID(uuidString(rules.ContestID)),
+		MaxEntriesPerUser:   int(rules.MaxEntriesPerUser),
+		MaxEntries:          int(rules.MaxEntries),
+		MaxPhotosPerEntry:   int(rules.MaxPhotosPerEntry),
+		MaxPhotoSizeMB:      int(rules.MaxPhotoSizeMb),
+		VideoAllowed:        rules.VideoAllowed,
+		MaxVideoDurationSec: int(rules.MaxVideoDurationSec),
+		MaxVideoSizeMB:      int(rules.MaxVideoSizeMb),
+		RequiredFields:      rules.RequiredFields,
+		EntryDeadline:       timePtr(rules.EntryDeadline),
+		RequireApproval:     rules.RequireApproval,
+		AllowedSpecies:      rules.AllowedSpecies,
+		MinAgeMonths:        int(rules.MinAgeMonths),
+		MaxAgeMonths:        int(rules.MaxAgeMonths),
+		UpdatedAt:           timePtr(rules.UpdatedAt),
+	}
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"toppet/server/internal/model"
+	sqlc_repository "toppet/server/internal/repository_sqlc"
+)
+
+func (r *Repository) CreateJuryCriterion(ctx context.Context, criterion *model.JuryCriterion) (*model.JuryCriterion, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	contestUUID, err := uuid.Parse(string(criterion.ContestID))
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := reposqlc.CreateJuryCriterion(ctx, &sqlc_repository.CreateJuryCriterionParams{
+		ID:          pgtype.UUID{Bytes: uuid.New(), Valid: true},
+		ContestID:   pgtype.UUID{Bytes: contestUUID, Valid: true},
+		Name:        criterion.Name,
+		Description: criterion.Description,
+		Weight:      int32(criterion.Weight),
+		MaxScore:    int32(criterion.MaxScore),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toModelJuryCriterion(created), nil
+}
+
+func (r *Repository) ListJuryCriteria(ctx context.Context, contestID model.ContestID) ([]*model.JuryCriterion, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	contestUUID, err := uuid.Parse(string(contestID))
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := reposqlc.ListJuryCriteria(ctx, pgtype.UUID{Bytes: contestUUID, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.JuryCriterion, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, toModelJuryCriterion(row))
+	}
+	return result, nil
+}
+
+func (r *Repository) DeleteJuryCriterion(ctx context.Context, contestID model.ContestID, criterionID string) error {
+	reposqlc := sqlc_repository.New(r.conn)
+	contestUUID, err := uuid.Parse(string(contestID))
+	if err != nil {
+		return err
+	}
+	criterionUUID, err := uuid.Parse(criterionID)
+	if err != nil {
+		return fmt.Errorf("%w: invalid criterion id", model.ErrBadRequest)
+	}
+
+	return reposqlc.DeleteJuryCriterion(ctx, &sqlc_repository.DeleteJuryCriterionParams{
+		ContestID: pgtype.UUID{Bytes: contestUUID, Valid: true},
+		ID:        pgtype.UUID{Bytes: criterionUUID, Valid: true},
+	})
+}
+
+// UpsertJuryScores сохраняет оценки члена жюри участнику по нескольким критериям одним запросом
+func (r *Repository) UpsertJuryScores(ctx context.Context, contestID model.ContestID, participantID model.ParticipantID, jurorID model.UserID, scores []*model.JuryScore) error {
+	reposqlc := sqlc_repository.New(r.conn)
+	contestUUID, err := uuid.Parse(string(contestID))
+	if err != nil {
+		return err
+	}
+	participantUUID, err := uuid.Parse(string(participantID))
+	if err != nil {
+		return err
+	}
+
+	criterionIDs := make([]pgtype.UUID, 0, len(scores))
+	values := make([]int32, 0, len(scores))
+	comments := make([]string, 0, len(scores))
+	for _, score := range scores {
+		criterionUUID, err := uuid.Parse(score.CriterionID)
+		if err != nil {
+			return fmt.Errorf("%w: invalid criterion id %q", model.ErrBadRequest, score.CriterionID)
+		}
+		criterionIDs = append(criterionIDs, pgtype.UUID{Bytes: criterionUUID, Valid: true})
+		values = append(values, int32(score.Score))
+		comments = append(comments, score.Comment)
+	}
+
+	return reposqlc.UpsertJuryScores(ctx, &sqlc_repository.UpsertJuryScoresParams{
+		ParticipantID: pgtype.UUID{Bytes: participantUUID, Valid: true},
+		JurorUserID:   int64(jurorID),
+		ContestID:     pgtype.UUID{Bytes: contestUUID, Valid: true},
+		CriterionIds:  criterionIDs,
+		Scores:        values,
+		Comments:      comments,
+	})
+}
+
+func (r *Repository) ListJuryScores(ctx context.Context, contestID model.ContestID) ([]*model.JuryScore, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	contestUUID, err := uuid.Parse(string(contestID))
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := reposqlc.ListJuryScores(ctx, pgtype.UUID{Bytes: contestUUID, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.JuryScore, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, &model.JuryScore{
+			CriterionID:   uuidString(row.CriterionID),
+			ParticipantID: model.ParticipantID(uuidString(row.ParticipantID)),
+			JurorID:       model.UserID(row.JurorUserID),
+			JurorName:     row.JurorName,
+			Score:         int(row.Score),
+			Comment:       row.Comment,
+			UpdatedAt:     row.UpdatedAt.Time,
+		})
+	}
+	return result, nil
+}
+
+func toModelJuryCriterion(criterion *sqlc_repository.ContestJuryCriterium) *model.JuryCriterion {
+	return &model.JuryCriterion{
+		ID:          uuidString(criterion.ID),
+		ContestID:   model.ContestID(uuidString(criterion.ContestID)),
+		Name:        criterion.Name,
+		Description: criterion.Description,
+		Weight:      int(criterion.Weight),
+		MaxScore:    int(criterion.MaxScore),
+		Position:    int(criterion.Position),
+		CreatedAt:   criterion.CreatedAt.Time,
+	}
+}
@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"toppet/server/internal/model"
+	sqlc_repository "toppet/server/internal/repository_sqlc"
+)
+
+func (r *Repository) ListUserRoles(ctx context.Context, userID model.UserID) ([]model.Role, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	roles, err := reposqlc.ListUserRoles(ctx, int64(userID))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]model.Role, len(roles))
+	for i, role := range roles {
+		result[i] = model.Role(role)
+	}
+	return result, nil
+}
+
+// ListActiveUserRoles возвращает роли пользователя; для удаленного или несуществующего аккаунта - model.ErrorNotFound
+func (r *Repository) ListActiveUserRoles(ctx context.Context, userID model.UserID) ([]model.Role, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	roles, err := reposqlc.ListActiveUserRoles(ctx, int64(userID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: user is deleted", model.ErrorNotFound)
+		}
+		return nil, err
+	}
+
+	result := make([]model.Role, len(roles))
+	for i, role := range roles {
+		result[i] = model.Role(role)
+	}
+	return result, nil
+}
+
+func (r *Repository) AddUserRole(ctx context.Context, userID model.UserID, role model.Role, grantedBy model.UserID) error {
+	reposqlc := sqlc_repository.New(r.conn)
+	grantedByID := int64(grantedBy)
+	return reposqlc.AddUserRole(ctx, &sqlc_repository.AddUserRoleParams{
+		UserID:          int64(userID),
+		Role:            string(role),
+		GrantedByUserID: &grantedByID,
+	})
+}
+
+func (r *Repository) RemoveUserRole(ctx context.Context, userID model.UserID, role model.Role) error {
+	reposqlc := sqlc_repository.New(r.conn)
+	return reposqlc.RemoveUserRole(ctx, &sqlc_repository.RemoveUserRoleParams{
+		UserID: int64(userID),
+		Role:   string(role),
+	})
+}
+
+func (r *Repository) CreateModerationAction(ctx context.Context, action *model.ModerationAction) (*model.ModerationAction, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	created, err := reposqlc.CreateModerationAction(ctx, &sqlc_repository.CreateModerationActionParams{
+		ActorUserID: int64(action.ActorUserID),
+		Action:      string(action.Action),
+		TargetType:  string(action.TargetType),
+		TargetID:    action.TargetID,
+		Reason:      action.Reason,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toModelModerationAction(created), nil
+}
+
+func (r *Repository) ListModerationActions(ctx context.Context, limit, offset int) ([]*model.ModerationAction, int64, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	actions, err := reposqlc.ListModerationActions(ctx, &sqlc_repository.ListModerationActionsParams{
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := reposqlc.CountModerationActions(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	result := make([]*model.ModerationAction, len(actions))
+	for i, a := range actions {
+		result[i] = toModelModerationAction(a)
+	}
+	return result, total, nil
+}
+
+// ModerateContent применяет действие модератора и записывает его в moderation_actions одной транзакцией,
+// чтобы скрытый или удаленный контент не остался без записи в журнале. Для сообщения чата возвращает
+// ID конкурса для WS уведомления.
+func (r *Repository) ModerateContent(ctx context.Context, action *model.ModerationAction) (*model.ModerationAction, model.ContestID, error) {
+	targetUUID, err := uuid.Parse(action.TargetID)
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: invalid target_id", model.ErrBadRequest)
+	}
+	targetID := pgtype.UUID{Bytes: targetUUID, Valid: true}
+	deleted := action.Action == model.ModerationActionDelete
+	hidden := hiddenAt(action.Action == model.ModerationActionHide)
+
+	var (
+		created   *model.ModerationAction
+		contestID model.ContestID
+	)
+	err = r.inTx(ctx, func(reposqlc *sqlc_repository.Queries) error {
+		var err error
+		switch action.TargetType {
+		case model.ModerationTargetContest:
+			if deleted {
+				err = reposqlc.DeleteContest(ctx, targetID)
+			} else {
+				err = reposqlc.SetContestHidden(ctx, &sqlc_repository.SetContestHiddenParams{ID: targetID, HiddenAt: hidden})
+			}
+		case model.ModerationTargetParticipant:
+			if deleted {
+				err = deleteParticipant(ctx, reposqlc, model.ParticipantID(action.TargetID))
+			} else {
+				err = reposqlc.SetParticipantHidden(ctx, &sqlc_repository.SetParticipantHiddenParams{ID: targetID, HiddenAt: hidden})
+			}
+		case model.ModerationTargetComment:
+			if deleted {
+				err = reposqlc.DeleteComment(ctx, targetID)
+			} else {
+				err = reposqlc.SetCommentHidden(ctx, &sqlc_repository.SetCommentHiddenParams{ID: targetID, HiddenAt: hidden})
+			}
+		case model.ModerationTargetChatMessage:
+			var messageContestID pgtype.UUID
+			if deleted {
+				messageContestID, err = reposqlc.DeleteChatMessageByID(ctx, targetID)
+			} else {
+				messageContestID, err = reposqlc.SetChatMessageHidden(ctx, &sqlc_repository.SetChatMessageHiddenParams{ID: targetID, HiddenAt: hidden})
+			}
+			contestID, err = chatMessageContestID(messageContestID, err)
+		default:
+			err = fmt.Errorf("%w: invalid target_type %q", model.ErrBadRequest, action.TargetType)
+		}
+		if err != nil {
+			return err
+		}
+
+		row, err := reposqlc.CreateModerationAction(ctx, &sqlc_repository.CreateModerationActionParams{
+			ActorUserID: int64(action.ActorUserID),
+			Action:      string(action.Action),
+			TargetType:  string(action.TargetType),
+			TargetID:    action.TargetID,
+			Reason:      action.Reason,
+		})
+		if err != nil {
+			return err
+		}
+		created = toModelModerationAction(row)
+		return nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return created, contestID, nil
+}
+
+// DeleteChatMessageByID удаляет любое сообщение без проверки автора (для модераторов)
+func (r *Repository) DeleteChatMessageByID(ctx context.Context, messageID model.ChatMessageID) (model.ContestID, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	messageUUID, err := uuid.Parse(string(messageID))
+	if err != nil {
+		return "", err
+	}
+
+	return chatMessageContestID(reposqlc.DeleteChatMessageByID(ctx, pgtype.UUID{Bytes: messageUUID, Valid: true}))
+}
+
+// chatMessageContestID переводит результат запроса к сообщению чата в ID конкурса; нет сообщения - model.ErrorNotFound
+func chatMessageContestID(contestID pgtype.UUID, err error) (model.ContestID, error) {
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("%w: %v", model.ErrorNotFound, err)
+		}
+		return "", err
+	}
+	return model.ContestID(uuid.UUID(contestID.Bytes).String()), nil
+}
+
+func hiddenAt(hidden bool) pgtype.Timestamptz {
+	if !hidden {
+		return pgtype.Timestamptz{}
+	}
+	return pgtype.Timestamptz{Time: time.Now(), Valid: true}
+}
+
+func toModelModerationAction(a *sqlc_repository.ModerationAction) *model.ModerationAction {
+	var id string
+	if a.ID.Valid {
+		id = uuid.UUID(a.ID.Bytes).String()
+	}
+	return &model.ModerationAction{
+		ID:          id,
+		ActorUserID: model.UserID(a.ActorUserID),
+		Action:      model.ModerationActionType(a.Action),
+		TargetType:  model.ModerationTargetType(a.TargetType),
+		TargetID:    a.TargetID,
+		Reason:      a.Reason,
+		CreatedAt:   a.CreatedAt.Time,
+	}
+}
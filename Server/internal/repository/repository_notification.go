@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"toppet/server/internal/model"
+	sqlc_repository "toppet/server/internal/repository_sqlc"
+)
+
+// CreateNotifications создает уведомление notification для каждого из получателей userIDs.
+// Удаленные пользователи пропускаются; возвращает созданные уведомления.
+func (r *Repository) CreateNotifications(ctx context.Context, userIDs []model.UserID, notification *model.Notification) ([]*model.Notification, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+
+	ids := make([]int64, len(userIDs))
+	for i, userID := range userIDs {
+		ids[i] = int64(userID)
+	}
+	params := &sqlc_repository.CreateNotificationsParams{
+		Type:    string(notification.Type),
+		Title:   notification.Title,
+		Text:    notification.Text,
+		UserIds: ids,
+	}
+	var err error
+	if params.ContestID, err = optionalUUID(string(notification.ContestID)); err != nil {
+		return nil, err
+	}
+	if params.ParticipantID, err = optionalUUID(string(notification.ParticipantID)); err != nil {
+		return nil, err
+	}
+	if params.CommentID, err = optionalUUID(string(notification.CommentID)); err != nil {
+		return nil, err
+	}
+	if notification.ActorUserID != nil {
+		params.ActorUserID = pgtype.Int8{Int64: int64(*notification.ActorUserID), Valid: true}
+	}
+
+	rows, err := reposqlc.CreateNotifications(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*model.Notification, len(rows))
+	for i, row := range rows {
+		result[i] = toModelNotification((*sqlc_repository.ListNotificationsRow)(row))
+	}
+	return result, nil
+}
+
+func (r *Repository) ListNotifications(ctx context.Context, userID model.UserID, unreadOnly bool, limit, offset int) ([]*model.Notification, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	rows, err := reposqlc.ListNotifications(ctx, &sqlc_repository.ListNotificationsParams{
+		UserID:      int64(userID),
+		UnreadOnly:  unreadOnly,
+		LimitCount:  int32(limit),
+		OffsetCount: int32(offset),
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*model.Notification, len(rows))
+	for i, row := range rows {
+		result[i] = toModelNotification(row)
+	}
+	return result, nil
+}
+
+// CountNotifications общее число уведомлений пользователя и число непрочитанных
+func (r *Repository) CountNotifications(ctx context.Context, userID model.UserID) (int64, int64, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	row, err := reposqlc.CountNotifications(ctx, int64(userID))
+	if err != nil {
+		return 0, 0, err
+	}
+	return row.Total, row.Unread, nil
+}
+
+// MarkNotificationRead отмечает уведомление прочитанным; чужое или несуществующее - model.ErrorNotFound
+func (r *Repository) MarkNotificationRead(ctx context.Context, userID model.UserID, notificationID string) error {
+	reposqlc := sqlc_repository.New(r.conn)
+	notificationUUID, err := uuid.Parse(notificationID)
+	if err != nil {
+		return fmt.Errorf("%w: invalid notification id", model.ErrorNotFound)
+	}
+
+	rows, err := reposqlc.MarkNotificationRead(ctx, &sqlc_repository.MarkNotificationReadParams{
+		ID:     pgtype.UUID{Bytes: notificationUUID, Valid: true},
+		UserID: int64(userID),
+	})
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("%w: notification %s", model.ErrorNotFound, notificationID)
+	}
+	return nil
+}
+
+// MarkAllNotificationsRead отмечает прочитанными все уведомления пользователя, возвращает их число
+func (r *Repository) MarkAllNotificationsRead(ctx context.Context, userID model.UserID) (int64, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	return reposqlc.MarkAllNotificationsRead(ctx, int64(userID))
+}
+
+func optionalUUID(id string) (pgtype.UUID, error) {
+	if id == "" {
+		return pgtype.UUID{}, nil
+	}
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		return pgtype.UUID{}, err
+	}
+	return pgtype.UUID{Bytes: parsed, Valid: true}, nil
+}
+
+func toModelNotification(row *sqlc_repository.ListNotificationsRow) *model.Notification {
+	notification := &model.Notification{
+		ID:            uuidString(row.ID),
+		UserID:        model.UserID(row.UserID),
+		Type:          model.NotificationType(row.Type),
+		ContestID:     model.ContestID(uuidString(row.ContestID)),
+		ParticipantID: model.ParticipantID(uuidString(row.ParticipantID)),
+		CommentID:     model.CommentID(uuidString(row.CommentID)),
+		ActorName:     row.ActorName,
+		Title:         row.Title,
+		Text:          row.Text,
+		ReadAt:        timePtr(row.ReadAt),
+		CreatedAt:     row.CreatedAt.Time,
+	}
+	if row.ActorUserID.Valid {
+		actorID := model.UserID(row.ActorUserID.Int64)
+		notification.ActorUserID = &actorID
+	}
+	return notification
+}
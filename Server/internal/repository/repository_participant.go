@@ -13,10 +13,11 @@ import (
 	sqlc_repository "toppet/server/internal/repository_sqlc"
 )
 
-func (r *Repository) CreateParticipant(ctx context.Context, contestID model.ContestID, userID model.UserID, petName, petDescription string) (*model.Participant, error) {
+// CreateParticipant создает заявку с учетом лимитов конкурса (0 - без лимита). Заявки одного конкурса
+// создаются по очереди, поэтому параллельные запросы не превышают лимит.
+func (r *Repository) CreateParticipant(ctx context.Context, contestID model.ContestID, userID model.UserID, petID model.PetID, petName, petDescription string, attrs model.PetAttributes, status model.EntryModerationStatus, maxEntries, maxEntriesPerUser int) (*model.Participant, error) {
 	log.Printf("[Repository] CreateParticipant: contestID=%s, userID=%d, petName=%s", contestID, userID, petName)
 	
-	reposqlc := sqlc_repository.New(r.conn)
 	participantUUID := uuid.New()
 	log.Printf("[Repository] CreateParticipant: Generated participantUUID=%s", participantUUID.String())
 	
@@ -27,13 +28,54 @@ func (r *Repository) CreateParticipant(ctx context.Context, contestID model.Cont
 	}
 	log.Printf("[Repository] CreateParticipant: Parsed contestUUID=%s", contestUUID.String())
 
+	petUUID, err := parseOptionalPetID(petID)
+	if err != nil {
+		return nil, err
+	}
+	birthDate, err := toPgDate(attrs.BirthDate)
+	if err != nil {
+		return nil, err
+	}
+
 	log.Printf("[Repository] CreateParticipant: Executing SQL insert")
-	participant, err := reposqlc.CreateParticipant(ctx, &sqlc_repository.CreateParticipantParams{
-		ID:             pgtype.UUID{Bytes: participantUUID, Valid: true},
-		ContestID:      pgtype.UUID{Bytes: contestUUID, Valid: true},
-		UserID:         int64(userID),
-		PetName:        petName,
-		PetDescription: petDescription,
+	params := &sqlc_repository.CreateParticipantParams{
+		ID:                pgtype.UUID{Bytes: participantUUID, Valid: true},
+		ContestID:         pgtype.UUID{Bytes: contestUUID, Valid: true},
+		UserID:            int64(userID),
+		PetName:           petName,
+		PetDescription:    petDescription,
+		ModerationStatus:  string(status),
+		PetID:             petUUID,
+		Species:           textPtr(attrs.Species),
+		Breed:             textPtr(attrs.Breed),
+		Sex:               textPtr(string(attrs.Sex)),
+		BirthDate:         birthDate,
+		MaxEntries:        int32(maxEntries),
+		MaxEntriesPerUser: int32(maxEntriesPerUser),
+	}
+	var participant *sqlc_repository.ContestParticipant
+	err = r.inTx(ctx, func(reposqlc *sqlc_repository.Queries) error {
+		if err := reposqlc.LockContest(ctx, params.ContestID); err != nil {
+			return err
+		}
+		participant, err = reposqlc.CreateParticipant(ctx, params)
+		if !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+
+		// Строка не вставлена - исчерпан один из лимитов
+		count, err := reposqlc.CountParticipantsByContestAndUser(ctx, &sqlc_repository.CountParticipantsByContestAndUserParams{
+			ContestID: params.ContestID,
+			UserID:    params.UserID,
+		})
+		if err != nil {
+			return err
+		}
+		if maxEntriesPerUser > 0 && count >= int64(maxEntriesPerUser) {
+			return model.NewCodedError(model.ErrorForbidden, model.EntryRejectedMaxUserEntries,
+				fmt.Sprintf("you can submit at most %d entries to this contest", maxEntriesPerUser))
+		}
+		return model.NewCodedError(model.ErrorForbidden, model.EntryRejectedMaxEntries, "contest has reached the maximum number of entries")
 	})
 	if err != nil {
 		log.Printf("[Repository] CreateParticipant: ERROR - SQL insert failed: %v", err)
@@ -53,10 +95,15 @@ func (r *Repository) CreateParticipant(ctx context.Context, contestID model.Cont
 		ID:             model.ParticipantID(participantIDStr),
 		ContestID:      model.ContestID(contestIDStr),
 		UserID:         model.UserID(participant.UserID),
-		PetName:        participant.PetName,
-		PetDescription: participant.PetDescription,
-		CreatedAt:      participant.CreatedAt.Time,
-		UpdatedAt:      participant.UpdatedAt.Time,
+		PetName:          participant.PetName,
+		PetDescription:   participant.PetDescription,
+		PetID:            model.PetID(uuidString(participant.PetID)),
+		PetAttributes:    toModelPetAttributes(participant.Species, participant.Breed, participant.Sex, participant.BirthDate),
+		ModerationStatus: model.EntryModerationStatus(participant.ModerationStatus),
+		ModerationReason: participant.ModerationReason,
+		ModeratedAt:      timePtr(participant.ModeratedAt),
+		CreatedAt:        participant.CreatedAt.Time,
+		UpdatedAt:        participant.UpdatedAt.Time,
 	}
 
 	user, err := r.GetUser(ctx, model.UserID(participant.UserID))
@@ -98,8 +145,16 @@ func (r *Repository) GetParticipant(ctx context.Context, participantID model.Par
 		UserName:       participant.UserName,
 		PetName:        participant.PetName,
 		PetDescription: participant.PetDescription,
-		CreatedAt:      participant.CreatedAt.Time,
-		UpdatedAt:      participant.UpdatedAt.Time,
+		CreatedAt:        participant.CreatedAt.Time,
+		UpdatedAt:        participant.UpdatedAt.Time,
+		Hidden:           participant.HiddenAt.Valid,
+		ModerationStatus: model.EntryModerationStatus(participant.ModerationStatus),
+		ModerationReason: participant.ModerationReason,
+		ModeratedAt:      timePtr(participant.ModeratedAt),
+		DisqualifiedAt:   timePtr(participant.DisqualifiedAt),
+		DisqualificationReason: participant.DisqualificationReason,
+		PetID:            model.PetID(uuidString(participant.PetID)),
+		PetAttributes:    toModelPetAttributes(participant.Species, participant.Breed, participant.Sex, participant.BirthDate),
 	}, nil
 }
 
@@ -170,17 +225,137 @@ func (r *Repository) ListParticipantsByContest(ctx context.Context, contestID mo
 			UserName:       p.UserName,
 			PetName:        p.PetName,
 			PetDescription: p.PetDescription,
-			CreatedAt:      p.CreatedAt.Time,
-			UpdatedAt:      p.UpdatedAt.Time,
+			PetID:          model.PetID(uuidString(p.PetID)),
+			PetAttributes:  toModelPetAttributes(p.Species, p.Breed, p.Sex, p.BirthDate),
+			// В публичный список попадают только одобренные заявки
+			ModerationStatus: model.EntryModerationApproved,
+			CreatedAt:        p.CreatedAt.Time,
+			UpdatedAt:        p.UpdatedAt.Time,
 		}
 	}
 
 	return result, nil
 }
 
-func (r *Repository) UpdateParticipant(ctx context.Context, participantID model.ParticipantID, petName, petDescription string) (*model.Participant, error) {
+// ListParticipantsByUser все заявки пользователя, включая скрытые, отклоненные и дисквалифицированные
+func (r *Repository) ListParticipantsByUser(ctx context.Context, userID model.UserID) ([]*model.Participant, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	participants, err := reposqlc.ListParticipantsByUser(ctx, int64(userID))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.Participant, len(participants))
+	for i, p := range participants {
+		result[i] = &model.Participant{
+			ID:                     model.ParticipantID(uuidString(p.ID)),
+			ContestID:              model.ContestID(uuidString(p.ContestID)),
+			UserID:                 model.UserID(p.UserID),
+			PetName:                p.PetName,
+			PetDescription:         p.PetDescription,
+			PetID:                  model.PetID(uuidString(p.PetID)),
+			PetAttributes:          toModelPetAttributes(p.Species, p.Breed, p.Sex, p.BirthDate),
+			Hidden:                 p.HiddenAt.Valid,
+			ModerationStatus:       model.EntryModerationStatus(p.ModerationStatus),
+			ModerationReason:       p.ModerationReason,
+			ModeratedAt:            timePtr(p.ModeratedAt),
+			DisqualifiedAt:         timePtr(p.DisqualifiedAt),
+			DisqualificationReason: p.DisqualificationReason,
+			CreatedAt:              p.CreatedAt.Time,
+			UpdatedAt:              p.UpdatedAt.Time,
+		}
+	}
+	return result, nil
+}
+
+// ListParticipantsByModerationStatus очередь премодерации: заявки конкурса с указанным статусом
+func (r *Repository) ListParticipantsByModerationStatus(ctx context.Context, contestID model.ContestID, status model.EntryModerationStatus) ([]*model.Participant, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	contestUUID, err := uuid.Parse(string(contestID))
+	if err != nil {
+		return nil, err
+	}
+
+	participants, err := reposqlc.ListParticipantsByModerationStatus(ctx, &sqlc_repository.ListParticipantsByModerationStatusParams{
+		ContestID:        pgtype.UUID{Bytes: contestUUID, Valid: true},
+		ModerationStatus: string(status),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.Participant, len(participants))
+	for i, p := range participants {
+		result[i] = &model.Participant{
+			ID:               model.ParticipantID(uuidString(p.ID)),
+			ContestID:        model.ContestID(uuidString(p.ContestID)),
+			UserID:           model.UserID(p.UserID),
+			UserName:         p.UserName,
+			PetName:          p.PetName,
+			PetDescription:   p.PetDescription,
+			ModerationStatus: model.EntryModerationStatus(p.ModerationStatus),
+			ModerationReason: p.ModerationReason,
+			ModeratedAt:      timePtr(p.ModeratedAt),
+			CreatedAt:        p.CreatedAt.Time,
+			UpdatedAt:        p.UpdatedAt.Time,
+		}
+	}
+	return result, nil
+}
+
+// SetParticipantModeration сохраняет решение по заявке; moderatorID 0 - возврат на модерацию без решения
+func (r *Repository) SetParticipantModeration(ctx context.Context, participantID model.ParticipantID, status model.EntryModerationStatus, reason string, moderatorID model.UserID) error {
 	reposqlc := sqlc_repository.New(r.conn)
 	participantUUID, err := uuid.Parse(string(participantID))
+	if err != nil {
+		return err
+	}
+
+	return reposqlc.SetParticipantModeration(ctx, &sqlc_repository.SetParticipantModerationParams{
+		ID:                pgtype.UUID{Bytes: participantUUID, Valid: true},
+		ModerationStatus:  string(status),
+		ModerationReason:  reason,
+		ModeratedByUserID: pgtype.Int8{Int64: int64(moderatorID), Valid: moderatorID != 0},
+	})
+}
+
+// DisqualifyParticipant в одной транзакции помечает участника дисквалифицированным и аннулирует голоса за него.
+// false - участник уже дисквалифицирован, ничего не меняется.
+func (r *Repository) DisqualifyParticipant(ctx context.Context, contestID model.ContestID, participantID model.ParticipantID, actorID model.UserID, reason string) ([]*model.Vote, bool, error) {
+	participantUUID, err := uuid.Parse(string(participantID))
+	if err != nil {
+		return nil, false, err
+	}
+	participantPgUUID := pgtype.UUID{Bytes: participantUUID, Valid: true}
+
+	var voided []*model.Vote
+	disqualified := false
+	err = r.inTx(ctx, func(reposqlc *sqlc_repository.Queries) error {
+		rows, err := reposqlc.DisqualifyParticipant(ctx, &sqlc_repository.DisqualifyParticipantParams{
+			ID:                     participantPgUUID,
+			DisqualifiedByUserID:   pgtype.Int8{Int64: int64(actorID), Valid: actorID != 0},
+			DisqualificationReason: reason,
+		})
+		if err != nil || rows == 0 {
+			return err
+		}
+		disqualified = true
+		voided, err = voidParticipantVotes(ctx, reposqlc, contestID, participantPgUUID, actorID, reason)
+		return err
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return voided, disqualified, nil
+}
+
+func (r *Repository) UpdateParticipant(ctx context.Context, participantID model.ParticipantID, petName, petDescription string, attrs model.PetAttributes) (*model.Participant, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	participantUUID, err := uuid.Parse(string(participantID))
+	if err != nil {
+		return nil, err
+	}
+	birthDate, err := toPgDate(attrs.BirthDate)
 	if err != nil {
 		return nil, err
 	}
@@ -189,6 +364,10 @@ func (r *Repository) UpdateParticipant(ctx context.Context, participantID model.
 		ID:             pgtype.UUID{Bytes: participantUUID, Valid: true},
 		PetName:        petName,
 		PetDescription: petDescription,
+		Species:        textPtr(attrs.Species),
+		Breed:          textPtr(attrs.Breed),
+		Sex:            textPtr(string(attrs.Sex)),
+		BirthDate:      birthDate,
 	})
 	if err != nil {
 		return nil, err
@@ -206,10 +385,15 @@ func (r *Repository) UpdateParticipant(ctx context.Context, participantID model.
 		ID:             model.ParticipantID(participantIDStr),
 		ContestID:      model.ContestID(contestIDStr),
 		UserID:         model.UserID(participant.UserID),
-		PetName:        participant.PetName,
-		PetDescription: participant.PetDescription,
-		CreatedAt:      participant.CreatedAt.Time,
-		UpdatedAt:      participant.UpdatedAt.Time,
+		PetName:          participant.PetName,
+		PetDescription:   participant.PetDescription,
+		PetID:            model.PetID(uuidString(participant.PetID)),
+		PetAttributes:    toModelPetAttributes(participant.Species, participant.Breed, participant.Sex, participant.BirthDate),
+		ModerationStatus: model.EntryModerationStatus(participant.ModerationStatus),
+		ModerationReason: participant.ModerationReason,
+		ModeratedAt:      timePtr(participant.ModeratedAt),
+		CreatedAt:        participant.CreatedAt.Time,
+		UpdatedAt:        participant.UpdatedAt.Time,
 	}
 
 	user, err := r.GetUser(ctx, model.UserID(participant.UserID))
@@ -221,9 +405,13 @@ func (r *Repository) UpdateParticipant(ctx context.Context, participantID model.
 }
 
 func (r *Repository) DeleteParticipant(ctx context.Context, participantID model.ParticipantID) error {
+	return deleteParticipant(ctx, sqlc_repository.New(r.conn), participantID)
+}
+
+// deleteParticipant удаляет участника вместе с фото, видео, комментариями и голосами
+func deleteParticipant(ctx context.Context, reposqlc *sqlc_repository.Queries, participantID model.ParticipantID) error {
 	log.Printf("[Repository] DeleteParticipant: participantID=%s", participantID)
 	
-	reposqlc := sqlc_repository.New(r.conn)
 	participantUUID, err := uuid.Parse(string(participantID))
 	if err != nil {
 		log.Printf("[Repository] DeleteParticipant: ERROR - Failed to parse participantID: %v", err)
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"toppet/server/internal/model"
+)
+
+func TestRepository_CreateParticipant_EntryLimits(t *testing.T) {
+	r := testRepository(t)
+	ctx := context.Background()
+
+	owner, err := r.CreateUser(ctx, "limits-owner")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	contest, err := r.CreateContest(ctx, owner.ID, "Limits", "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	t.Cleanup(func() { _ = r.DeleteContest(ctx, contest.ID) })
+
+	var users []*model.User
+	for _, name := range []string{"limits-first", "limits-second"} {
+		user, err := r.CreateUser(ctx, name)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		users = append(users, user)
+	}
+	create := func(userID model.UserID, maxEntries, maxEntriesPerUser int) error {
+		pet, err := r.CreatePet(ctx, &model.Pet{OwnerUserID: userID, Name: "Barsik"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		t.Cleanup(func() { _ = r.DeletePet(ctx, pet.ID) })
+		_, err = r.CreateParticipant(ctx, contest.ID, userID, pet.ID, pet.Name, "", model.PetAttributes{}, model.EntryModerationApproved, maxEntries, maxEntriesPerUser)
+		return err
+	}
+
+	if err := create(users[0].ID, 2, 1); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var coded *model.CodedError
+	if err := create(users[0].ID, 2, 1); !errors.As(err, &coded) || coded.Code != model.EntryRejectedMaxUserEntries {
+		t.Errorf("Expected %s, got %v", model.EntryRejectedMaxUserEntries, err)
+	}
+	if err := create(users[1].ID, 2, 1); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := create(owner.ID, 2, 1); !errors.As(err, &coded) || coded.Code != model.EntryRejectedMaxEntries {
+		t.Errorf("Expected %s, got %v", model.EntryRejectedMaxEntries, err)
+	}
+}
@@ -0,0 +1,271 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"toppet/server/internal/model"
+	sqlc_repository "toppet/server/internal/repository_sqlc"
+)
+
+func (r *Repository) CreatePet(ctx context.Context, pet *model.Pet) (*model.Pet, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	birthDate, err := toPgDate(pet.BirthDate)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := reposqlc.CreatePet(ctx, &sqlc_repository.CreatePetParams{
+		ID:          pgtype.UUID{Bytes: uuid.New(), Valid: true},
+		OwnerUserID: int64(pet.OwnerUserID),
+		Name:        pet.Name,
+		Species:     textPtr(pet.Species),
+		Breed:       textPtr(pet.Breed),
+		Sex:         textPtr(string(pet.Sex)),
+		BirthDate:   birthDate,
+		Bio:         pet.Bio,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toModelPet(created), nil
+}
+
+func (r *Repository) GetPet(ctx context.Context, petID model.PetID) (*model.Pet, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	petUUID, err := uuid.Parse(string(petID))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", model.ErrorNotFound, err)
+	}
+
+	pet, err := reposqlc.GetPetByID(ctx, pgtype.UUID{Bytes: petUUID, Valid: true})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %v", model.ErrorNotFound, err)
+		}
+		return nil, err
+	}
+	return toModelPet(pet), nil
+}
+
+func (r *Repository) ListPetsByOwner(ctx context.Context, userID model.UserID) ([]*model.Pet, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	pets, err := reposqlc.ListPetsByOwner(ctx, int64(userID))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.Pet, 0, len(pets))
+	for _, pet := range pets {
+		result = append(result, toModelPet(pet))
+	}
+	return result, nil
+}
+
+func (r *Repository) UpdatePet(ctx context.Context, pet *model.Pet) (*model.Pet, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	petUUID, err := uuid.Parse(string(pet.ID))
+	if err != nil {
+		return nil, err
+	}
+	birthDate, err := toPgDate(pet.BirthDate)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := reposqlc.UpdatePet(ctx, &sqlc_repository.UpdatePetParams{
+		ID:        pgtype.UUID{Bytes: petUUID, Valid: true},
+		Name:      pet.Name,
+		Species:   textPtr(pet.Species),
+		Breed:     textPtr(pet.Breed),
+		Sex:       textPtr(string(pet.Sex)),
+		BirthDate: birthDate,
+		Bio:       pet.Bio,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toModelPet(updated), nil
+}
+
+func (r *Repository) DeletePet(ctx context.Context, petID model.PetID) error {
+	reposqlc := sqlc_repository.New(r.conn)
+	petUUID, err := uuid.Parse(string(petID))
+	if err != nil {
+		return err
+	}
+	return reposqlc.DeletePet(ctx, pgtype.UUID{Bytes: petUUID, Valid: true})
+}
+
+// ListPetEntries публичные заявки питомца во всех конкурсах, новые первыми
+func (r *Repository) ListPetEntries(ctx context.Context, petID model.PetID) ([]*model.PetEntry, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	petUUID, err := uuid.Parse(string(petID))
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := reposqlc.ListPetEntries(ctx, pgtype.UUID{Bytes: petUUID, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.PetEntry, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, &model.PetEntry{
+			ParticipantID: model.ParticipantID(uuidString(row.ID)),
+			ContestID:     model.ContestID(uuidString(row.ContestID)),
+			ContestTitle:  row.ContestTitle,
+			ContestStatus: model.ContestStatus(row.ContestStatus),
+			PetName:       row.PetName,
+			CreatedAt:     row.CreatedAt.Time,
+		})
+	}
+	return result, nil
+}
+
+func (r *Repository) AddPetPhoto(ctx context.Context, petID model.PetID, url string, thumbURL *string) (*model.PetPhoto, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	petUUID, err := uuid.Parse(string(petID))
+	if err != nil {
+		return nil, err
+	}
+
+	photo, err := reposqlc.AddPetPhoto(ctx, &sqlc_repository.AddPetPhotoParams{
+		ID:       pgtype.UUID{Bytes: uuid.New(), Valid: true},
+		PetID:    pgtype.UUID{Bytes: petUUID, Valid: true},
+		Url:      url,
+		ThumbUrl: thumbURL,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toModelPetPhoto(photo), nil
+}
+
+func (r *Repository) ListPetPhotos(ctx context.Context, petID model.PetID) ([]*model.PetPhoto, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	petUUID, err := uuid.Parse(string(petID))
+	if err != nil {
+		return nil, err
+	}
+
+	photos, err := reposqlc.ListPetPhotos(ctx, pgtype.UUID{Bytes: petUUID, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.PetPhoto, 0, len(photos))
+	for _, photo := range photos {
+		result = append(result, toModelPetPhoto(photo))
+	}
+	return result, nil
+}
+
+func (r *Repository) CountPetPhotos(ctx context.Context, petID model.PetID) (int64, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	petUUID, err := uuid.Parse(string(petID))
+	if err != nil {
+		return 0, err
+	}
+	return reposqlc.CountPetPhotos(ctx, pgtype.UUID{Bytes: petUUID, Valid: true})
+}
+
+func (r *Repository) DeletePetPhoto(ctx context.Context, petID model.PetID, photoID string) error {
+	reposqlc := sqlc_repository.New(r.conn)
+	petUUID, err := uuid.Parse(string(petID))
+	if err != nil {
+		return err
+	}
+	photoUUID, err := uuid.Parse(photoID)
+	if err != nil {
+		return fmt.Errorf("%w: %v", model.ErrorNotFound, err)
+	}
+
+	rows, err := reposqlc.DeletePetPhoto(ctx, &sqlc_repository.DeletePetPhotoParams{
+		PetID: pgtype.UUID{Bytes: petUUID, Valid: true},
+		ID:    pgtype.UUID{Bytes: photoUUID, Valid: true},
+	})
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return model.ErrorNotFound
+	}
+	return nil
+}
+
+func parseOptionalPetID(petID model.PetID) (pgtype.UUID, error) {
+	if petID == "" {
+		return pgtype.UUID{}, nil
+	}
+	id, err := uuid.Parse(string(petID))
+	if err != nil {
+		return pgtype.UUID{}, fmt.Errorf("%w: invalid pet id", model.ErrBadRequest)
+	}
+	return pgtype.UUID{Bytes: id, Valid: true}, nil
+}
+
+func toModelPet(pet *sqlc_repository.Pet) *model.Pet {
+	return &model.Pet{
+		ID:            model.PetID(uuidString(pet.ID)),
+		OwnerUserID:   model.UserID(pet.OwnerUserID),
+		Name:          pet.Name,
+		PetAttributes: toModelPetAttributes(pet.Species, pet.Breed, pet.Sex, pet.BirthDate),
+		Bio:           pet.Bio,
+		CreatedAt:     pet.CreatedAt.Time,
+		UpdatedAt:     pet.UpdatedAt.Time,
+	}
+}
+
+func toModelPetAttributes(species, breed, sex *string, birthDate pgtype.Date) model.PetAttributes {
+	var attrs model.PetAttributes
+	if species != nil {
+		attrs.Species = *species
+	}
+	if breed != nil {
+		attrs.Breed = *breed
+	}
+	if sex != nil {
+		attrs.Sex = model.PetSex(*sex)
+	}
+	if birthDate.Valid {
+		attrs.BirthDate = birthDate.Time.Format(time.DateOnly)
+	}
+	return attrs
+}
+
+func toModelPetPhoto(photo *sqlc_repository.PetPhoto) *model.PetPhoto {
+	return &model.PetPhoto{
+		ID:        uuidString(photo.ID),
+		PetID:     model.PetID(uuidString(photo.PetID)),
+		URL:       photo.Url,
+		ThumbURL:  photo.ThumbUrl,
+		Position:  int(photo.Position),
+		CreatedAt: photo.CreatedAt.Time,
+	}
+}
+
+// textPtr пустую строку сохраняет как NULL
+func textPtr(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+func toPgDate(value string) (pgtype.Date, error) {
+	if value == "" {
+		return pgtype.Date{}, nil
+	}
+	date, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return pgtype.Date{}, fmt.Errorf("%w: invalid date %q", model.ErrBadRequest, value)
+	}
+	return pgtype.Date{Time: date, Valid: true}, nil
+}
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	sqlc_repository "toppet/server/internal/repository_sqlc"
+)
+
+// TakeRateLimitToken пополняет бакет по прошедшему времени и берет из него токен одним UPSERT,
+// поэтому конкурентные запросы с разных реплик не обходят лимит
+func (r *Repository) TakeRateLimitToken(ctx context.Context, key string, rate float64, burst int) (float64, bool, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	row, err := reposqlc.TakeRateLimitToken(ctx, &sqlc_repository.TakeRateLimitTokenParams{
+		BucketKey: key,
+		Burst:     float64(burst),
+		Rate:      rate,
+	})
+	if err != nil {
+		return 0, false, err
+	}
+	return row.Tokens, row.Allowed, nil
+}
+
+func (r *Repository) DeleteStaleRateLimitBuckets(ctx context.Context, before time.Time) error {
+	reposqlc := sqlc_repository.New(r.conn)
+	return reposqlc.DeleteStaleRateLimitBuckets(ctx, pgtype.Timestamptz{Time: before, Valid: true})
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"toppet/server/internal/model"
+	sqlc_repository "toppet/server/internal/repository_sqlc"
+)
+
+func (r *Repository) ListSpecies(ctx context.Context) ([]*model.Species, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	species, err := reposqlc.ListSpecies(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.Species, 0, len(species))
+	for _, s := range species {
+		result = append(result, &model.Species{Code: s.Code, NameRu: s.NameRu, NameEn: s.NameEn})
+	}
+	return result, nil
+}
+
+func (r *Repository) ListBreeds(ctx context.Context, species string) ([]*model.Breed, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	breeds, err := reposqlc.ListBreedsBySpecies(ctx, species)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.Breed, 0, len(breeds))
+	for _, breed := range breeds {
+		result = append(result, toModelBreed(breed))
+	}
+	return result, nil
+}
+
+func (r *Repository) GetBreed(ctx context.Context, code string) (*model.Breed, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	breed, err := reposqlc.GetBreedByCode(ctx, code)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %v", model.ErrorNotFound, err)
+		}
+		return nil, err
+	}
+	return toModelBreed(breed), nil
+}
+
+func toModelBreed(breed *sqlc_repository.Breed) *model.Breed {
+	return &model.Breed{
+		Code:    breed.Code,
+		Species: breed.SpeciesCode,
+		NameRu:  breed.NameRu,
+		NameEn:  breed.NameEn,
+	}
+}
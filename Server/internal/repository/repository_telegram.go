@@ -0,0 +1,303 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"toppet/server/internal/model"
+	sqlc_repository "toppet/server/internal/repository_sqlc"
+)
+
+// CreateTelegramLinkToken сохраняет хеш токена привязки; прежние токены пользователя перестают действовать
+func (r *Repository) CreateTelegramLinkToken(ctx context.Context, tokenHash string, userID model.UserID, expiresAt time.Time) error {
+	reposqlc := sqlc_repository.New(r.conn)
+	return reposqlc.CreateTelegramLinkToken(ctx, &sqlc_repository.CreateTelegramLinkTokenParams{
+		TokenHash: tokenHash,
+		UserID:    int64(userID),
+		ExpiresAt: pgtype.Timestamptz{Time: expiresAt, Valid: true},
+	})
+}
+
+// ConsumeTelegramLinkToken гасит действующий токен и возвращает пользователя, которому он выдан
+func (r *Repository) ConsumeTelegramLinkToken(ctx context.Context, tokenHash string) (model.UserID, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	userID, err := reposqlc.ConsumeTelegramLinkToken(ctx, tokenHash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, fmt.Errorf("%w: %v", model.ErrorNotFound, err)
+		}
+		return 0, err
+	}
+	return model.UserID(userID), nil
+}
+
+// LinkTelegramAccount привязывает чат к пользователю. Чат, привязанный к другому аккаунту,
+// сначала отвязывается от него; прежний чат пользователя заменяется новым.
+func (r *Repository) LinkTelegramAccount(ctx context.Context, userID model.UserID, chatID int64, username string) (*model.TelegramAccount, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	if err := reposqlc.UnlinkTelegramChat(ctx, &sqlc_repository.UnlinkTelegramChatParams{
+		ChatID: chatID,
+		UserID: int64(userID),
+	}); err != nil {
+		return nil, err
+	}
+	account, err := reposqlc.LinkTelegramAccount(ctx, &sqlc_repository.LinkTelegramAccountParams{
+		UserID:   int64(userID),
+		ChatID:   chatID,
+		Username: username,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toModelTelegramAccount(account), nil
+}
+
+func (r *Repository) GetTelegramAccount(ctx context.Context, userID model.UserID) (*model.TelegramAccount, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	account, err := reposqlc.GetTelegramAccount(ctx, int64(userID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %v", model.ErrorNotFound, err)
+		}
+		return nil, err
+	}
+	return toModelTelegramAccount(account), nil
+}
+
+func (r *Repository) GetTelegramAccountByChat(ctx context.Context, chatID int64) (*model.TelegramAccount, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	account, err := reposqlc.GetTelegramAccountByChat(ctx, chatID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %v", model.ErrorNotFound, err)
+		}
+		return nil, err
+	}
+	return toModelTelegramAccount(account), nil
+}
+
+// DeleteTelegramAccount отвязывает чат пользователя; подписки чата сохраняются
+func (r *Repository) DeleteTelegramAccount(ctx context.Context, userID model.UserID) (int64, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	return reposqlc.DeleteTelegramAccount(ctx, int64(userID))
+}
+
+// DeleteTelegramChat удаляет привязку и подписки чата, недоступного боту
+func (r *Repository) DeleteTelegramChat(ctx context.Context, chatID int64) error {
+	reposqlc := sqlc_repository.New(r.conn)
+	return reposqlc.DeleteTelegramChat(ctx, chatID)
+}
+
+func (r *Repository) SubscribeTelegramChat(ctx context.Context, chatID int64, contestID model.ContestID) error {
+	reposqlc := sqlc_repository.New(r.conn)
+	contestUUID, err := uuid.Parse(string(contestID))
+	if err != nil {
+		return err
+	}
+	return reposqlc.SubscribeTelegramChat(ctx, &sqlc_repository.SubscribeTelegramChatParams{
+		ChatID:    chatID,
+		ContestID: pgtype.UUID{Bytes: contestUUID, Valid: true},
+	})
+}
+
+func (r *Repository) UnsubscribeTelegramChat(ctx context.Context, chatID int64, contestID model.ContestID) (int64, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	contestUUID, err := uuid.Parse(string(contestID))
+	if err != nil {
+		return 0, err
+	}
+	return reposqlc.UnsubscribeTelegramChat(ctx, &sqlc_repository.UnsubscribeTelegramChatParams{
+		ChatID:    chatID,
+		ContestID: pgtype.UUID{Bytes: contestUUID, Valid: true},
+	})
+}
+
+func (r *Repository) ListTelegramSubscriptions(ctx context.Context, chatID int64) ([]*model.TelegramSubscription, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	rows, err := reposqlc.ListTelegramSubscriptions(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*model.TelegramSubscription, len(rows))
+	for i, row := range rows {
+		result[i] = &model.TelegramSubscription{
+			ContestID: model.ContestID(uuidString(row.ID)),
+			Title:     row.Title,
+			Status:    model.ContestStatus(row.Status),
+		}
+	}
+	return result, nil
+}
+
+// ListTelegramContestChats чаты подписчиков конкурса и привязанные чаты его участников (без отклоненных заявок)
+func (r *Repository) ListTelegramContestChats(ctx context.Context, contestID model.ContestID) ([]int64, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	contestUUID, err := uuid.Parse(string(contestID))
+	if err != nil {
+		return nil, err
+	}
+	return reposqlc.ListTelegramContestChats(ctx, pgtype.UUID{Bytes: contestUUID, Valid: true})
+}
+
+// EnqueueTelegramMessages ставит сообщения в очередь одним запросом
+func (r *Repository) EnqueueTelegramMessages(ctx context.Context, messages []*model.TelegramMessage) error {
+	if len(messages) == 0 {
+		return nil
+	}
+	reposqlc := sqlc_repository.New(r.conn)
+	params := &sqlc_repository.EnqueueTelegramMessagesParams{
+		ChatIds: make([]string, len(messages)),
+		Texts:   make([]string, len(messages)),
+	}
+	for i, message := range messages {
+		params.ChatIds[i] = message.ChatID
+		params.Texts[i] = message.Text
+	}
+	_, err := reposqlc.EnqueueTelegramMessages(ctx, params)
+	return err
+}
+
+// ClaimTelegramOutbox забирает до limit сообщений, готовых к отправке на момент now, и резервирует их до leaseUntil
+func (r *Repository) ClaimTelegramOutbox(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*model.TelegramMessage, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	rows, err := reposqlc.ClaimTelegramOutbox(ctx, &sqlc_repository.ClaimTelegramOutboxParams{
+		LeaseUntil: pgtype.Timestamptz{Time: leaseUntil, Valid: true},
+		Now:        pgtype.Timestamptz{Time: now, Valid: true},
+		LimitCount: int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*model.TelegramMessage, len(rows))
+	for i, row := range rows {
+		result[i] = &model.TelegramMessage{
+			ID:       uuidString(row.ID),
+			ChatID:   row.ChatID,
+			Text:     row.Text,
+			Attempts: int(row.Attempts),
+		}
+	}
+	return result, nil
+}
+
+func (r *Repository) MarkTelegramMessageSent(ctx context.Context, messageID string) error {
+	reposqlc := sqlc_repository.New(r.conn)
+	id, err := uuid.Parse(messageID)
+	if err != nil {
+		return err
+	}
+	return reposqlc.MarkTelegramMessageSent(ctx, pgtype.UUID{Bytes: id, Valid: true})
+}
+
+// FailTelegramMessageAttempt сохраняет ошибку отправки: pending - повтор в nextAttemptAt, failed - отправка прекращена
+func (r *Repository) FailTelegramMessageAttempt(ctx context.Context, messageID string, status model.OutboxStatus, lastError string, nextAttemptAt time.Time) error {
+	reposqlc := sqlc_repository.New(r.conn)
+	id, err := uuid.Parse(messageID)
+	if err != nil {
+		return err
+	}
+	return reposqlc.FailTelegramMessageAttempt(ctx, &sqlc_repository.FailTelegramMessageAttemptParams{
+		ID:            pgtype.UUID{Bytes: id, Valid: true},
+		Status:        string(status),
+		LastError:     lastError,
+		NextAttemptAt: pgtype.Timestamptz{Time: nextAttemptAt, Valid: true},
+	})
+}
+
+// CreateTelegramAnnouncement сохраняет анонс и ставит его сообщение в очередь отправки.
+// Анонсы одного конкурса чаще cooldown отклоняются с model.ErrTooManyRequests.
+func (r *Repository) CreateTelegramAnnouncement(ctx context.Context, announcement *model.TelegramAnnouncement, cooldown time.Duration) (*model.TelegramAnnouncement, error) {
+	contestUUID, err := uuid.Parse(string(announcement.ContestID))
+	if err != nil {
+		return nil, err
+	}
+	params := &sqlc_repository.CreateTelegramAnnouncementParams{
+		ChatID:          announcement.ChatID,
+		Text:            announcement.Text,
+		ContestID:       pgtype.UUID{Bytes: contestUUID, Valid: true},
+		CooldownSeconds: cooldown.Seconds(),
+	}
+	if announcement.PostedByUserID != nil {
+		params.PostedByUserID = pgtype.Int8{Int64: int64(*announcement.PostedByUserID), Valid: true}
+	}
+
+	var row *sqlc_repository.CreateTelegramAnnouncementRow
+	err = r.inTx(ctx, func(reposqlc *sqlc_repository.Queries) error {
+		// Параллельные анонсы конкурса ждут друг друга, иначе оба прошли бы проверку интервала
+		if err := reposqlc.LockContest(ctx, params.ContestID); err != nil {
+			return err
+		}
+		row, err = reposqlc.CreateTelegramAnnouncement(ctx, params)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, model.ErrTooManyRequests
+		}
+		return nil, err
+	}
+	return toModelTelegramAnnouncement(&sqlc_repository.ListTelegramAnnouncementsRow{
+		ID:             row.ID,
+		ContestID:      row.ContestID,
+		OutboxID:       row.OutboxID,
+		ChatID:         row.ChatID,
+		Text:           row.Text,
+		PostedByUserID: row.PostedByUserID,
+		CreatedAt:      row.CreatedAt,
+		Status:         row.Status,
+	}), nil
+}
+
+// ListTelegramAnnouncements последние limit анонсов конкурса, новые первыми
+func (r *Repository) ListTelegramAnnouncements(ctx context.Context, contestID model.ContestID, limit int) ([]*model.TelegramAnnouncement, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	contestUUID, err := uuid.Parse(string(contestID))
+	if err != nil {
+		return nil, err
+	}
+	rows, err := reposqlc.ListTelegramAnnouncements(ctx, &sqlc_repository.ListTelegramAnnouncementsParams{
+		ContestID: pgtype.UUID{Bytes: contestUUID, Valid: true},
+		Limit:     int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*model.TelegramAnnouncement, len(rows))
+	for i, row := range rows {
+		result[i] = toModelTelegramAnnouncement(row)
+	}
+	return result, nil
+}
+
+func toModelTelegramAccount(account *sqlc_repository.TelegramAccount) *model.TelegramAccount {
+	return &model.TelegramAccount{
+		UserID:   model.UserID(account.UserID),
+		ChatID:   account.ChatID,
+		Username: account.Username,
+		LinkedAt: account.LinkedAt.Time,
+	}
+}
+
+func toModelTelegramAnnouncement(row *sqlc_repository.ListTelegramAnnouncementsRow) *model.TelegramAnnouncement {
+	result := &model.TelegramAnnouncement{
+		ID:        uuidString(row.ID),
+		ContestID: model.ContestID(uuidString(row.ContestID)),
+		ChatID:    row.ChatID,
+		Text:      row.Text,
+		Status:    model.OutboxStatus(row.Status),
+		LastError: row.LastError,
+		CreatedAt: row.CreatedAt.Time,
+		SentAt:    timePtr(row.SentAt),
+	}
+	if row.PostedByUserID.Valid {
+		userID := model.UserID(row.PostedByUserID.Int64)
+		result.PostedByUserID = &userID
+	}
+	return result
+}
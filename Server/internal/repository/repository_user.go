@@ -17,11 +17,7 @@ func (r *Repository) CreateUser(ctx context.Context, name string) (*model.User,
 		return nil, err
 	}
 
-	return &model.User{
-		ID:        model.UserID(user.UserID),
-		Name:      user.Name,
-		CreatedAt: user.CreatedAt.Time,
-	}, nil
+	return toModelUser(user), nil
 }
 
 func (r *Repository) CreateUserFromProvider(ctx context.Context, userData *model.UserProfileFromProvider) (*model.User, error) {
@@ -47,11 +43,7 @@ func (r *Repository) CreateUserFromProvider(ctx context.Context, userData *model
 		return nil, err
 	}
 
-	return &model.User{
-		ID:        model.UserID(user.UserID),
-		Name:      user.Name,
-		CreatedAt: user.CreatedAt.Time,
-	}, nil
+	return toModelUser(user), nil
 }
 
 func (r *Repository) GetUserAuthProvidersByProviderUid(ctx context.Context, providerUID, provider string) (*model.UserAuthProvider, error) {
@@ -88,11 +80,18 @@ func (r *Repository) AddUserAuthProviders(ctx context.Context, userData *model.U
 		name = &n
 	}
 
+	var avatarURL *string
+	if userData.AvatarURL != "" {
+		a := userData.AvatarURL
+		avatarURL = &a
+	}
+
 	authProvider, err := reposqlc.AddUserAuthProviders(ctx, &sqlc_repository.AddUserAuthProvidersParams{
 		UserID:      int64(userID),
 		ProviderUid: userData.ProviderID,
 		Provider:    userData.ProviderName,
 		Name:        name,
+		AvatarUrl:   avatarURL,
 	})
 	if err != nil {
 		return nil, err
@@ -106,6 +105,7 @@ func (r *Repository) AddUserAuthProviders(ctx context.Context, userData *model.U
 	if authProvider.Name != nil {
 		result.Name = authProvider.Name
 	}
+	result.AvatarURL = authProvider.AvatarUrl
 
 	return result, nil
 }
@@ -127,6 +127,7 @@ func (r *Repository) GetUserAuthProvidersByUserID(ctx context.Context, userID mo
 		if ap.Name != nil {
 			result[i].Name = ap.Name
 		}
+		result[i].AvatarURL = ap.AvatarUrl
 	}
 
 	return result, nil
@@ -142,35 +143,127 @@ func (r *Repository) GetUser(ctx context.Context, userID model.UserID) (*model.U
 		return nil, err
 	}
 
-	return &model.User{
-		ID:        model.UserID(user.UserID),
-		Name:      user.Name,
-		CreatedAt: user.CreatedAt.Time,
-	}, nil
+	return toModelUser(user), nil
 }
 
-func (r *Repository) UpdateUserName(ctx context.Context, userID model.UserID, name string) (*model.User, error) {
+func (r *Repository) UpdateUserProfile(ctx context.Context, userID model.UserID, name, bio string, locale model.Locale) (*model.User, error) {
 	reposqlc := sqlc_repository.New(r.conn)
-	user, err := reposqlc.UpdateUserName(ctx, &sqlc_repository.UpdateUserNameParams{
+	user, err := reposqlc.UpdateUserProfile(ctx, &sqlc_repository.UpdateUserProfileParams{
 		UserID: int64(userID),
 		Name:   name,
+		Bio:    bio,
+		Locale: string(locale),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %v", model.ErrorNotFound, err)
+		}
+		return nil, err
+	}
+
+	return toModelUser(user), nil
+}
+
+// SetUserAvatar заменяет аватар; nil сбрасывает соответствующий размер
+func (r *Repository) SetUserAvatar(ctx context.Context, userID model.UserID, avatarURL, thumbURL *string) (*model.User, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	user, err := reposqlc.SetUserAvatar(ctx, &sqlc_repository.SetUserAvatarParams{
+		UserID:         int64(userID),
+		AvatarUrl:      avatarURL,
+		AvatarThumbUrl: thumbURL,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %v", model.ErrorNotFound, err)
+		}
+		return nil, err
+	}
+
+	return toModelUser(user), nil
+}
+
+func (r *Repository) SetUserAvatarIfEmpty(ctx context.Context, userID model.UserID, avatarURL *string) error {
+	reposqlc := sqlc_repository.New(r.conn)
+	return reposqlc.SetUserAvatarIfEmpty(ctx, &sqlc_repository.SetUserAvatarIfEmptyParams{
+		UserID:    int64(userID),
+		AvatarUrl: avatarURL,
+	})
+}
+
+func (r *Repository) UpdateUserAuthProviderAvatar(ctx context.Context, providerUID, provider string, avatarURL *string) error {
+	reposqlc := sqlc_repository.New(r.conn)
+	return reposqlc.UpdateUserAuthProviderAvatar(ctx, &sqlc_repository.UpdateUserAuthProviderAvatarParams{
+		ProviderUid: providerUID,
+		Provider:    provider,
+		AvatarUrl:   avatarURL,
 	})
+}
+
+// GetLatestProviderAvatar аватар из провайдера, через который пользователь входил последним
+func (r *Repository) GetLatestProviderAvatar(ctx context.Context, userID model.UserID) (string, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	avatarURL, err := reposqlc.GetLatestProviderAvatar(ctx, int64(userID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("%w: %v", model.ErrorNotFound, err)
+		}
+		return "", err
+	}
+	if avatarURL == nil {
+		return "", nil
+	}
+	return *avatarURL, nil
+}
+
+func (r *Repository) GetUserPrivacySettings(ctx context.Context, userID model.UserID) (*model.PrivacySettings, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	settings, err := reposqlc.GetUserPrivacySettings(ctx, int64(userID))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, fmt.Errorf("%w: %v", model.ErrorNotFound, err)
 		}
 		return nil, err
 	}
+	return toModelPrivacySettings(settings), nil
+}
+
+func (r *Repository) UpsertUserPrivacySettings(ctx context.Context, userID model.UserID, settings *model.PrivacySettings) (*model.PrivacySettings, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	saved, err := reposqlc.UpsertUserPrivacySettings(ctx, &sqlc_repository.UpsertUserPrivacySettingsParams{
+		UserID:       int64(userID),
+		ShowContests: settings.ShowContests,
+		ShowPets:     settings.ShowPets,
+		ShowResults:  settings.ShowResults,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toModelPrivacySettings(saved), nil
+}
 
-	return &model.User{
+func toModelUser(user *sqlc_repository.User) *model.User {
+	result := &model.User{
 		ID:        model.UserID(user.UserID),
 		Name:      user.Name,
+		Bio:       user.Bio,
+		Locale:    model.Locale(user.Locale),
 		CreatedAt: user.CreatedAt.Time,
-	}, nil
+		DeletedAt: timePtr(user.DeletedAt),
+	}
+	if user.AvatarUrl != nil {
+		result.AvatarURL = *user.AvatarUrl
+	}
+	if user.AvatarThumbUrl != nil {
+		result.AvatarThumbURL = *user.AvatarThumbUrl
+	}
+	return result
 }
 
-func (r *Repository) SetUserAvatarIfEmpty(ctx context.Context, userID model.UserID, avatarURL *string) error {
-	// This would require a migration to add avatar_url to users table
-	// For now, we'll skip this functionality
-	return nil
+func toModelPrivacySettings(settings *sqlc_repository.UserPrivacySetting) *model.PrivacySettings {
+	return &model.PrivacySettings{
+		ShowContests: settings.ShowContests,
+		ShowPets:     settings.ShowPets,
+		ShowResults:  settings.ShowResults,
+		UpdatedAt:    timePtr(settings.UpdatedAt),
+	}
 }
@@ -13,8 +13,8 @@ import (
 	sqlc_repository "toppet/server/internal/repository_sqlc"
 )
 
-func (r *Repository) UpsertContestVote(ctx context.Context, contestID model.ContestID, participantID model.ParticipantID, userID model.UserID) (*model.Vote, error) {
-	reposqlc := sqlc_repository.New(r.conn)
+// UpsertContestVote сохраняет голос и выбор в бюллетене одной транзакцией; choices пуст для режима single
+func (r *Repository) UpsertContestVote(ctx context.Context, contestID model.ContestID, categoryID model.CategoryID, participantID model.ParticipantID, userID model.UserID, meta *model.VoteMetadata, choices []model.BallotChoice) (*model.Vote, error) {
 	voteUUID := uuid.New()
 	contestUUID, err := uuid.Parse(string(contestID))
 	if err != nil {
@@ -24,48 +24,65 @@ func (r *Repository) UpsertContestVote(ctx context.Context, contestID model.Cont
 	if err != nil {
 		return nil, err
 	}
+	categoryUUID, err := parseCategoryID(categoryID)
+	if err != nil {
+		return nil, err
+	}
 
-	vote, err := reposqlc.UpsertContestVote(ctx, &sqlc_repository.UpsertContestVoteParams{
+	params := &sqlc_repository.UpsertContestVoteParams{
 		ID:            pgtype.UUID{Bytes: voteUUID, Valid: true},
 		ContestID:     pgtype.UUID{Bytes: contestUUID, Valid: true},
 		ParticipantID: pgtype.UUID{Bytes: participantUUID, Valid: true},
 		UserID:        int64(userID),
+		CategoryID:    categoryUUID,
+	}
+	if meta != nil {
+		params.IpHash = meta.IPHash
+		params.UserAgent = meta.UserAgent
+		params.AuthProvider = meta.AuthProvider
+		if meta.AccountCreatedAt != nil {
+			params.AccountCreatedAt = pgtype.Timestamptz{Time: *meta.AccountCreatedAt, Valid: true}
+		}
+	}
+
+	var vote *sqlc_repository.ContestVote
+	err = r.inTx(ctx, func(reposqlc *sqlc_repository.Queries) error {
+		// Аннулированный голос не перезаписывается: upsert не вернет строку
+		vote, err = reposqlc.UpsertContestVote(ctx, params)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("%w: %v", model.ErrorNotFound, err)
+			}
+			return err
+		}
+		if len(choices) == 0 {
+			return nil
+		}
+		return replaceContestVoteChoices(ctx, reposqlc, vote.ID, choices)
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	var voteIDStr, contestIDStr, participantIDStr string
-	if vote.ID.Valid {
-		voteIDStr = uuid.UUID(vote.ID.Bytes).String()
-	}
-	if vote.ContestID.Valid {
-		contestIDStr = uuid.UUID(vote.ContestID.Bytes).String()
-	}
-	if vote.ParticipantID.Valid {
-		participantIDStr = uuid.UUID(vote.ParticipantID.Bytes).String()
-	}
-
-	return &model.Vote{
-		ID:            voteIDStr,
-		ContestID:     model.ContestID(contestIDStr),
-		ParticipantID: model.ParticipantID(participantIDStr),
-		UserID:        model.UserID(vote.UserID),
-		CreatedAt:     vote.CreatedAt.Time,
-		UpdatedAt:     vote.UpdatedAt.Time,
-	}, nil
+	return toModelVote(vote), nil
 }
 
-func (r *Repository) GetContestVoteByUser(ctx context.Context, contestID model.ContestID, userID model.UserID) (*model.Vote, error) {
+func (r *Repository) GetContestVoteByUser(ctx context.Context, contestID model.ContestID, categoryID model.CategoryID, userID model.UserID) (*model.Vote, error) {
 	reposqlc := sqlc_repository.New(r.conn)
 	contestUUID, err := uuid.Parse(string(contestID))
 	if err != nil {
 		return nil, err
 	}
 
+	categoryUUID, err := parseCategoryID(categoryID)
+	if err != nil {
+		return nil, err
+	}
+
 	vote, err := reposqlc.GetContestVoteByUser(ctx, &sqlc_repository.GetContestVoteByUserParams{
-		ContestID: pgtype.UUID{Bytes: contestUUID, Valid: true},
-		UserID:    int64(userID),
+		ContestID:  pgtype.UUID{Bytes: contestUUID, Valid: true},
+		UserID:     int64(userID),
+		CategoryID: categoryUUID,
 	})
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -74,37 +91,40 @@ func (r *Repository) GetContestVoteByUser(ctx context.Context, contestID model.C
 		return nil, err
 	}
 
-	var voteIDStr, contestIDStr, participantIDStr string
-	if vote.ID.Valid {
-		voteIDStr = uuid.UUID(vote.ID.Bytes).String()
-	}
-	if vote.ContestID.Valid {
-		contestIDStr = uuid.UUID(vote.ContestID.Bytes).String()
-	}
-	if vote.ParticipantID.Valid {
-		participantIDStr = uuid.UUID(vote.ParticipantID.Bytes).String()
+	return toModelVote(vote), nil
+}
+
+// ListContestVotesByUser все голоса пользователя, включая аннулированные
+func (r *Repository) ListContestVotesByUser(ctx context.Context, userID model.UserID) ([]*model.Vote, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	votes, err := reposqlc.ListContestVotesByUser(ctx, int64(userID))
+	if err != nil {
+		return nil, err
 	}
 
-	return &model.Vote{
-		ID:            voteIDStr,
-		ContestID:     model.ContestID(contestIDStr),
-		ParticipantID: model.ParticipantID(participantIDStr),
-		UserID:        model.UserID(vote.UserID),
-		CreatedAt:     vote.CreatedAt.Time,
-		UpdatedAt:     vote.UpdatedAt.Time,
-	}, nil
+	result := make([]*model.Vote, len(votes))
+	for i, vote := range votes {
+		result[i] = toModelVote(vote)
+	}
+	return result, nil
 }
 
-func (r *Repository) DeleteContestVoteByUser(ctx context.Context, contestID model.ContestID, userID model.UserID) (model.ParticipantID, error) {
+func (r *Repository) DeleteContestVoteByUser(ctx context.Context, contestID model.ContestID, categoryID model.CategoryID, userID model.UserID) (model.ParticipantID, error) {
 	reposqlc := sqlc_repository.New(r.conn)
 	contestUUID, err := uuid.Parse(string(contestID))
 	if err != nil {
 		return "", err
 	}
 
+	categoryUUID, err := parseCategoryID(categoryID)
+	if err != nil {
+		return "", err
+	}
+
 	participantID, err := reposqlc.DeleteContestVoteByUser(ctx, &sqlc_repository.DeleteContestVoteByUserParams{
-		ContestID: pgtype.UUID{Bytes: contestUUID, Valid: true},
-		UserID:    int64(userID),
+		ContestID:  pgtype.UUID{Bytes: contestUUID, Valid: true},
+		UserID:     int64(userID),
+		CategoryID: categoryUUID,
 	})
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -130,14 +150,22 @@ func (r *Repository) CountVotesByContest(ctx context.Context, contestID model.Co
 	return count, err
 }
 
-func (r *Repository) CountVotesByParticipant(ctx context.Context, participantID model.ParticipantID) (int64, error) {
+// CountVotesByParticipant считает действующие голоса за участника в номинации (пустая - главный приз)
+func (r *Repository) CountVotesByParticipant(ctx context.Context, participantID model.ParticipantID, categoryID model.CategoryID) (int64, error) {
 	reposqlc := sqlc_repository.New(r.conn)
 	participantUUID, err := uuid.Parse(string(participantID))
 	if err != nil {
 		return 0, err
 	}
+	categoryUUID, err := parseCategoryID(categoryID)
+	if err != nil {
+		return 0, err
+	}
 
-	count, err := reposqlc.CountVotesByParticipant(ctx, pgtype.UUID{Bytes: participantUUID, Valid: true})
+	count, err := reposqlc.CountVotesByParticipant(ctx, &sqlc_repository.CountVotesByParticipantParams{
+		ParticipantID: pgtype.UUID{Bytes: participantUUID, Valid: true},
+		CategoryID:    categoryUUID,
+	})
 	return count, err
 }
 
@@ -161,7 +189,7 @@ func (r *Repository) CountVotesByContests(ctx context.Context, contestIDs []mode
 	query := `
 		SELECT contest_id, count(1) as vote_count 
 		FROM contest_votes
-		WHERE contest_id = ANY($1::uuid[])
+		WHERE contest_id = ANY($1::uuid[]) AND voided_at IS NULL
 		GROUP BY contest_id
 	`
 	
@@ -187,7 +215,7 @@ func (r *Repository) CountVotesByContests(ctx context.Context, contestIDs []mode
 	return result, rows.Err()
 }
 
-func (r *Repository) ListVotersByParticipant(ctx context.Context, contestID model.ContestID, participantID model.ParticipantID) ([]*model.VoterInfo, error) {
+func (r *Repository) ListVotersByParticipant(ctx context.Context, contestID model.ContestID, categoryID model.CategoryID, participantID model.ParticipantID) ([]*model.VoterInfo, error) {
 	contestUUID, err := uuid.Parse(string(contestID))
 	if err != nil {
 		return nil, err
@@ -196,11 +224,16 @@ func (r *Repository) ListVotersByParticipant(ctx context.Context, contestID mode
 	if err != nil {
 		return nil, err
 	}
+	categoryUUID, err := parseCategoryID(categoryID)
+	if err != nil {
+		return nil, err
+	}
 
 	reposqlc := sqlc_repository.New(r.conn)
 	rows, err := reposqlc.ListVotersByParticipant(ctx, &sqlc_repository.ListVotersByParticipantParams{
 		ContestID:     pgtype.UUID{Bytes: contestUUID, Valid: true},
 		ParticipantID: pgtype.UUID{Bytes: participantUUID, Valid: true},
+		CategoryID:    categoryUUID,
 	})
 	if err != nil {
 		return nil, err
@@ -220,3 +253,32 @@ func (r *Repository) ListVotersByParticipant(ctx context.Context, contestID mode
 	}
 	return result, nil
 }
+
+func toModelVote(vote *sqlc_repository.ContestVote) *model.Vote {
+	var voteIDStr, contestIDStr, participantIDStr string
+	if vote.ID.Valid {
+		voteIDStr = uuid.UUID(vote.ID.Bytes).String()
+	}
+	if vote.ContestID.Valid {
+		contestIDStr = uuid.UUID(vote.ContestID.Bytes).String()
+	}
+	if vote.ParticipantID.Valid {
+		participantIDStr = uuid.UUID(vote.ParticipantID.Bytes).String()
+	}
+
+	result := &model.Vote{
+		ID:            voteIDStr,
+		ContestID:     model.ContestID(contestIDStr),
+		CategoryID:    model.CategoryID(uuidString(vote.CategoryID)),
+		ParticipantID: model.ParticipantID(participantIDStr),
+		UserID:        model.UserID(vote.UserID),
+		CreatedAt:     vote.CreatedAt.Time,
+		UpdatedAt:     vote.UpdatedAt.Time,
+	}
+	if vote.VoidedAt.Valid {
+		voidedAt := vote.VoidedAt.Time
+		result.VoidedAt = &voidedAt
+		result.Recastable = vote.Recastable
+	}
+	return result
+}
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"toppet/server/internal/model"
+	sqlc_repository "toppet/server/internal/repository_sqlc"
+)
+
+// replaceContestVoteChoices заменяет выбор в бюллетене: лишние строки удаляются, остальные обновляются
+func replaceContestVoteChoices(ctx context.Context, reposqlc *sqlc_repository.Queries, voteID pgtype.UUID, choices []model.BallotChoice) error {
+	participantIDs := make([]pgtype.UUID, 0, len(choices))
+	ranks := make([]int32, 0, len(choices))
+	stars := make([]int32, 0, len(choices))
+	for _, choice := range choices {
+		participantUUID, err := uuid.Parse(string(choice.ParticipantID))
+		if err != nil {
+			return fmt.Errorf("%w: invalid participant id %q", model.ErrBadRequest, choice.ParticipantID)
+		}
+		participantIDs = append(participantIDs, pgtype.UUID{Bytes: participantUUID, Valid: true})
+		ranks = append(ranks, int32(choice.Rank))
+		stars = append(stars, int32(choice.Stars))
+	}
+
+	return reposqlc.ReplaceContestVoteChoices(ctx, &sqlc_repository.ReplaceContestVoteChoicesParams{
+		VoteID:         voteID,
+		ParticipantIds: participantIDs,
+		Ranks:          ranks,
+		Stars:          stars,
+	})
+}
+
+func (r *Repository) ListContestVoteChoices(ctx context.Context, voteID string) ([]model.BallotChoice, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	voteUUID, err := uuid.Parse(voteID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := reposqlc.ListContestVoteChoices(ctx, pgtype.UUID{Bytes: voteUUID, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]model.BallotChoice, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, toModelBallotChoice(row.ParticipantID, row.Rank, row.Stars))
+	}
+	return result, nil
+}
+
+// CountVoteChoicesByParticipant возвращает число действующих бюллетеней номинации с участником и среднюю оценку
+func (r *Repository) CountVoteChoicesByParticipant(ctx context.Context, participantID model.ParticipantID, categoryID model.CategoryID) (int64, float64, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	participantUUID, err := uuid.Parse(string(participantID))
+	if err != nil {
+		return 0, 0, err
+	}
+	categoryUUID, err := parseCategoryID(categoryID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	row, err := reposqlc.CountVoteChoicesByParticipant(ctx, &sqlc_repository.CountVoteChoicesByParticipantParams{
+		ParticipantID: pgtype.UUID{Bytes: participantUUID, Valid: true},
+		CategoryID:    categoryUUID,
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	return row.ChoiceCount, row.AverageStars, nil
+}
+
+// ListContestBallots возвращает действующие бюллетени номинации конкурса. Голос в режиме single - бюллетень из одного участника.
+func (r *Repository) ListContestBallots(ctx context.Context, contestID model.ContestID, categoryID model.CategoryID) ([]*model.Ballot, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	contestUUID, err := uuid.Parse(string(contestID))
+	if err != nil {
+		return nil, err
+	}
+	categoryUUID, err := parseCategoryID(categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := reposqlc.ListContestBallotChoices(ctx, &sqlc_repository.ListContestBallotChoicesParams{
+		ContestID:  pgtype.UUID{Bytes: contestUUID, Valid: true},
+		CategoryID: categoryUUID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Строки отсортированы по vote_id, поэтому бюллетень собирается из соседних строк
+	var result []*model.Ballot
+	var current *model.Ballot
+	for _, row := range rows {
+		voteID := uuidString(row.VoteID)
+		if current == nil || current.VoteID != voteID {
+			current = &model.Ballot{VoteID: voteID}
+			result = append(result, current)
+		}
+		current.Choices = append(current.Choices, toModelBallotChoice(row.ParticipantID, row.Rank, row.Stars))
+	}
+	return result, nil
+}
+
+func toModelBallotChoice(participantID pgtype.UUID, rank int32, stars *int32) model.BallotChoice {
+	choice := model.BallotChoice{
+		ParticipantID: model.ParticipantID(uuidString(participantID)),
+		Rank:          int(rank),
+	}
+	if stars != nil {
+		choice.Stars = int(*stars)
+	}
+	return choice
+}
@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"toppet/server/internal/model"
+	sqlc_repository "toppet/server/internal/repository_sqlc"
+)
+
+// ListContestIDsWithVotesSince возвращает конкурсы, в которых голоса менялись после since
+func (r *Repository) ListContestIDsWithVotesSince(ctx context.Context, since time.Time) ([]model.ContestID, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	rows, err := reposqlc.ListContestIDsWithVotesSince(ctx, pgtype.Timestamptz{Time: since, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]model.ContestID, 0, len(rows))
+	for _, row := range rows {
+		if row.Valid {
+			result = append(result, model.ContestID(uuid.UUID(row.Bytes).String()))
+		}
+	}
+	return result, nil
+}
+
+// ListContestVotesForFraudScoring возвращает действующие голоса конкурса с метаданными
+func (r *Repository) ListContestVotesForFraudScoring(ctx context.Context, contestID model.ContestID) ([]*model.VoteAudit, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	contestUUID, err := uuid.Parse(string(contestID))
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := reposqlc.ListContestVotesForFraudScoring(ctx, pgtype.UUID{Bytes: contestUUID, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.VoteAudit, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, &model.VoteAudit{
+			ID:               uuidString(row.ID),
+			ContestID:        contestID,
+			ParticipantID:    model.ParticipantID(uuidString(row.ParticipantID)),
+			UserID:           model.UserID(row.UserID),
+			IPHash:           row.IpHash,
+			UserAgent:        row.UserAgent,
+			AccountCreatedAt: timePtr(row.AccountCreatedAt),
+			FraudScore:       int(row.FraudScore),
+			FraudReasons:     row.FraudReasons,
+			VotedAt:          row.UpdatedAt.Time,
+		})
+	}
+	return result, nil
+}
+
+func (r *Repository) UpdateContestVoteFraudScore(ctx context.Context, voteID string, score int, reasons []string, flagged bool) error {
+	reposqlc := sqlc_repository.New(r.conn)
+	voteUUID, err := uuid.Parse(voteID)
+	if err != nil {
+		return err
+	}
+	if reasons == nil {
+		reasons = []string{}
+	}
+
+	return reposqlc.UpdateContestVoteFraudScore(ctx, &sqlc_repository.UpdateContestVoteFraudScoreParams{
+		FraudScore:   int32(score),
+		FraudReasons: reasons,
+		Flagged:      flagged,
+		ID:           pgtype.UUID{Bytes: voteUUID, Valid: true},
+	})
+}
+
+// ListFlaggedContestVotes возвращает помеченные голоса конкурса, включая уже аннулированные
+func (r *Repository) ListFlaggedContestVotes(ctx context.Context, contestID model.ContestID) ([]*model.VoteAudit, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	contestUUID, err := uuid.Parse(string(contestID))
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := reposqlc.ListFlaggedContestVotes(ctx, pgtype.UUID{Bytes: contestUUID, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.VoteAudit, 0, len(rows))
+	for _, row := range rows {
+		vote := &model.VoteAudit{
+			ID:               uuidString(row.ID),
+			ContestID:        contestID,
+			ParticipantID:    model.ParticipantID(uuidString(row.ParticipantID)),
+			UserID:           model.UserID(row.UserID),
+			UserName:         row.UserName,
+			IPHash:           row.IpHash,
+			UserAgent:        row.UserAgent,
+			AuthProvider:     row.AuthProvider,
+			AccountCreatedAt: timePtr(row.AccountCreatedAt),
+			FraudScore:       int(row.FraudScore),
+			FraudReasons:     row.FraudReasons,
+			FlaggedAt:        timePtr(row.FlaggedAt),
+			VotedAt:          row.UpdatedAt.Time,
+			VoidedAt:         timePtr(row.VoidedAt),
+			VoidReason:       row.VoidReason,
+		}
+		if row.VoidedByUserID != nil {
+			voidedBy := model.UserID(*row.VoidedByUserID)
+			vote.VoidedByUserID = &voidedBy
+		}
+		result = append(result, vote)
+	}
+	return result, nil
+}
+
+// VoidContestVotes аннулирует голоса конкурса и возвращает затронутые голоса (участник и автор)
+func (r *Repository) VoidContestVotes(ctx context.Context, contestID model.ContestID, voteIDs []string, actorID model.UserID, reason string) ([]*model.Vote, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	contestUUID, err := uuid.Parse(string(contestID))
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]pgtype.UUID, 0, len(voteIDs))
+	for _, voteID := range voteIDs {
+		voteUUID, err := uuid.Parse(voteID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid vote id %q", model.ErrBadRequest, voteID)
+		}
+		ids = append(ids, pgtype.UUID{Bytes: voteUUID, Valid: true})
+	}
+
+	actor := int64(actorID)
+	rows, err := reposqlc.VoidContestVotes(ctx, &sqlc_repository.VoidContestVotesParams{
+		VoidedByUserID: &actor,
+		VoidReason:     reason,
+		ContestID:      pgtype.UUID{Bytes: contestUUID, Valid: true},
+		Ids:            ids,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.Vote, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, &model.Vote{
+			ContestID:     contestID,
+			CategoryID:    model.CategoryID(uuidString(row.CategoryID)),
+			ParticipantID: model.ParticipantID(uuidString(row.ParticipantID)),
+			UserID:        model.UserID(row.UserID),
+		})
+	}
+	return result, nil
+}
+
+// voidParticipantVotes аннулирует все действующие голоса и бюллетени, где выбран участник,
+// оставляя авторам возможность проголосовать заново
+func voidParticipantVotes(ctx context.Context, reposqlc *sqlc_repository.Queries, contestID model.ContestID, participantID pgtype.UUID, actorID model.UserID, reason string) ([]*model.Vote, error) {
+	actor := int64(actorID)
+	rows, err := reposqlc.VoidParticipantVotes(ctx, &sqlc_repository.VoidParticipantVotesParams{
+		VoidedByUserID: &actor,
+		VoidReason:     reason,
+		ParticipantID:  participantID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.Vote, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, &model.Vote{
+			ContestID:     contestID,
+			CategoryID:    model.CategoryID(uuidString(row.CategoryID)),
+			ParticipantID: model.ParticipantID(uuidString(row.ParticipantID)),
+			UserID:        model.UserID(row.UserID),
+		})
+	}
+	return result, nil
+}
+
+func uuidString(id pgtype.UUID) string {
+	if !id.Valid {
+		return ""
+	}
+	return uuid.UUID(id.Bytes).String()
+}
+
+func timePtr(ts pgtype.Timestamptz) *time.Time {
+	if !ts.Valid {
+		return nil
+	}
+	t := ts.Time
+	return &t
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"toppet/server/internal/model"
+)
+
+func TestRepository_UpsertContestVote_RollsBackOnInvalidChoices(t *testing.T) {
+	r := testRepository(t)
+	ctx := context.Background()
+
+	owner, err := r.CreateUser(ctx, "vote-owner")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	voter, err := r.CreateUser(ctx, "vote-voter")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	contest, err := r.CreateContest(ctx, owner.ID, "Votes", "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	t.Cleanup(func() { _ = r.DeleteContest(ctx, contest.ID) })
+	pet, err := r.CreatePet(ctx, &model.Pet{OwnerUserID: owner.ID, Name: "Barsik"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	t.Cleanup(func() { _ = r.DeletePet(ctx, pet.ID) })
+	participant, err := r.CreateParticipant(ctx, contest.ID, owner.ID, pet.ID, pet.Name, "", model.PetAttributes{}, model.EntryModerationApproved, 0, 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Некорректный выбор в бюллетене: голос не должен сохраниться без выбора
+	choices := []model.BallotChoice{{ParticipantID: participant.ID, Rank: 1}, {ParticipantID: "not-a-uuid", Rank: 2}}
+	if _, err := r.UpsertContestVote(ctx, contest.ID, "", participant.ID, voter.ID, nil, choices); err == nil {
+		t.Fatal("Expected error for invalid ballot choice")
+	}
+	if vote, err := r.GetContestVoteByUser(ctx, contest.ID, "", voter.ID); err == nil && vote != nil {
+		t.Fatalf("Expected vote to be rolled back, got %+v", vote)
+	}
+
+	vote, err := r.UpsertContestVote(ctx, contest.ID, "", participant.ID, voter.ID, nil, choices[:1])
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	saved, err := r.ListContestVoteChoices(ctx, vote.ID)
+	if err != nil || len(saved) != 1 || saved[0].ParticipantID != participant.ID {
+		t.Errorf("Expected one ballot choice, got %+v, %v", saved, err)
+	}
+}
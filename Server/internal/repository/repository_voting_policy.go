@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"toppet/server/internal/model"
+	sqlc_repository "toppet/server/internal/repository_sqlc"
+)
+
+func (r *Repository) GetContestVotingPolicy(ctx context.Context, contestID model.ContestID) (*model.VotingPolicy, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	contestUUID, err := uuid.Parse(string(contestID))
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := reposqlc.GetContestVotingPolicy(ctx, pgtype.UUID{Bytes: contestUUID, Valid: true})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %v", model.ErrorNotFound, err)
+		}
+		return nil, err
+	}
+	return toModelVotingPolicy(policy), nil
+}
+
+func (r *Repository) UpsertContestVotingPolicy(ctx context.Context, policy *model.VotingPolicy) (*model.VotingPolicy, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	contestUUID, err := uuid.Parse(string(policy.ContestID))
+	if err != nil {
+		return nil, err
+	}
+
+	providers := policy.RequiredProviders
+	if providers == nil {
+		providers = []string{}
+	}
+
+	saved, err := reposqlc.UpsertContestVotingPolicy(ctx, &sqlc_repository.UpsertContestVotingPolicyParams{
+		ContestID:              pgtype.UUID{Bytes: contestUUID, Valid: true},
+		MinAccountAgeHours:     int32(policy.MinAccountAgeHours),
+		RequiredProviders:      providers,
+		ExcludeOwnParticipants: policy.ExcludeOwnParticipants,
+		InviteOnly:             policy.InviteOnly,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toModelVotingPolicy(saved), nil
+}
+
+func (r *Repository) ListContestInvitedVoters(ctx context.Context, contestID model.ContestID) ([]*model.InvitedVoter, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	contestUUID, err := uuid.Parse(string(contestID))
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := reposqlc.ListContestInvitedVoters(ctx, pgtype.UUID{Bytes: contestUUID, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.InvitedVoter, 0, len(rows))
+	for _, row := range rows {
+		voter := &model.InvitedVoter{
+			UserID:    model.UserID(row.UserID),
+			UserName:  row.UserName,
+			CreatedAt: row.CreatedAt.Time,
+		}
+		if row.AddedByUserID != nil {
+			addedBy := model.UserID(*row.AddedByUserID)
+			voter.AddedByUserID = &addedBy
+		}
+		result = append(result, voter)
+	}
+	return result, nil
+}
+
+func (r *Repository) AddContestInvitedVoter(ctx context.Context, contestID model.ContestID, userID, addedByUserID model.UserID) error {
+	reposqlc := sqlc_repository.New(r.conn)
+	contestUUID, err := uuid.Parse(string(contestID))
+	if err != nil {
+		return err
+	}
+
+	addedBy := int64(addedByUserID)
+	return reposqlc.AddContestInvitedVoter(ctx, &sqlc_repository.AddContestInvitedVoterParams{
+		ContestID:     pgtype.UUID{Bytes: contestUUID, Valid: true},
+		UserID:        int64(userID),
+		AddedByUserID: &addedBy,
+	})
+}
+
+func (r *Repository) DeleteContestInvitedVoter(ctx context.Context, contestID model.ContestID, userID model.UserID) error {
+	reposqlc := sqlc_repository.New(r.conn)
+	contestUUID, err := uuid.Parse(string(contestID))
+	if err != nil {
+		return err
+	}
+
+	return reposqlc.DeleteContestInvitedVoter(ctx, &sqlc_repository.DeleteContestInvitedVoterParams{
+		ContestID: pgtype.UUID{Bytes: contestUUID, Valid: true},
+		UserID:    int64(userID),
+	})
+}
+
+func (r *Repository) IsContestInvitedVoter(ctx context.Context, contestID model.ContestID, userID model.UserID) (bool, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	contestUUID, err := uuid.Parse(string(contestID))
+	if err != nil {
+		return false, err
+	}
+
+	return reposqlc.IsContestInvitedVoter(ctx, &sqlc_repository.IsContestInvitedVoterParams{
+		ContestID: pgtype.UUID{Bytes: contestUUID, Valid: true},
+		UserID:    int64(userID),
+	})
+}
+
+func toModelVotingPolicy(policy *sqlc_repository.ContestVotingPolicy) *model.VotingPolicy {
+	return &model.VotingPolicy{
+		ContestID:              model.ContestID(uuidString(policy.ContestID)),
+		MinAccountAgeHours:     int(policy.MinAccountAgeHours),
+		RequiredProviders:      policy.RequiredProviders,
+		ExcludeOwnParticipants: policy.ExcludeOwnParticipants,
+		InviteOnly:             policy.InviteOnly,
+		UpdatedAt:              timePtr(policy.UpdatedAt),
+	}
+}
@@ -0,0 +1,294 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"toppet/server/internal/model"
+	sqlc_repository "toppet/server/internal/repository_sqlc"
+)
+
+func (r *Repository) CreateWebhookEndpoint(ctx context.Context, endpoint *model.WebhookEndpoint) (*model.WebhookEndpoint, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	contestUUID, err := uuid.Parse(string(endpoint.ContestID))
+	if err != nil {
+		return nil, err
+	}
+	params := &sqlc_repository.CreateWebhookEndpointParams{
+		ContestID:  pgtype.UUID{Bytes: contestUUID, Valid: true},
+		Url:        endpoint.URL,
+		Secret:     endpoint.Secret,
+		EventTypes: webhookEventTypeStrings(endpoint.EventTypes),
+	}
+	if endpoint.CreatedByUserID != nil {
+		params.CreatedByUserID = pgtype.Int8{Int64: int64(*endpoint.CreatedByUserID), Valid: true}
+	}
+	created, err := reposqlc.CreateWebhookEndpoint(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return toModelWebhookEndpoint(created), nil
+}
+
+// GetWebhookEndpoint webhook вместе с ключом подписи
+func (r *Repository) GetWebhookEndpoint(ctx context.Context, endpointID string) (*model.WebhookEndpoint, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	id, err := uuid.Parse(endpointID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid webhook id", model.ErrorNotFound)
+	}
+	endpoint, err := reposqlc.GetWebhookEndpoint(ctx, pgtype.UUID{Bytes: id, Valid: true})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %v", model.ErrorNotFound, err)
+		}
+		return nil, err
+	}
+	return toModelWebhookEndpoint(endpoint), nil
+}
+
+// ListWebhookEndpoints webhooks конкурса в порядке создания
+func (r *Repository) ListWebhookEndpoints(ctx context.Context, contestID model.ContestID) ([]*model.WebhookEndpoint, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	contestUUID, err := uuid.Parse(string(contestID))
+	if err != nil {
+		return nil, err
+	}
+	endpoints, err := reposqlc.ListWebhookEndpoints(ctx, pgtype.UUID{Bytes: contestUUID, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*model.WebhookEndpoint, len(endpoints))
+	for i, endpoint := range endpoints {
+		result[i] = toModelWebhookEndpoint(endpoint)
+	}
+	return result, nil
+}
+
+func (r *Repository) UpdateWebhookEndpoint(ctx context.Context, endpoint *model.WebhookEndpoint) (*model.WebhookEndpoint, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	id, err := uuid.Parse(endpoint.ID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid webhook id", model.ErrorNotFound)
+	}
+	updated, err := reposqlc.UpdateWebhookEndpoint(ctx, &sqlc_repository.UpdateWebhookEndpointParams{
+		ID:         pgtype.UUID{Bytes: id, Valid: true},
+		Url:        endpoint.URL,
+		EventTypes: webhookEventTypeStrings(endpoint.EventTypes),
+		Active:     endpoint.Active,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %v", model.ErrorNotFound, err)
+		}
+		return nil, err
+	}
+	return toModelWebhookEndpoint(updated), nil
+}
+
+// DeleteWebhookEndpoint удаляет webhook вместе с журналом доставок
+func (r *Repository) DeleteWebhookEndpoint(ctx context.Context, endpointID string) error {
+	reposqlc := sqlc_repository.New(r.conn)
+	id, err := uuid.Parse(endpointID)
+	if err != nil {
+		return fmt.Errorf("%w: invalid webhook id", model.ErrorNotFound)
+	}
+	deleted, err := reposqlc.DeleteWebhookEndpoint(ctx, pgtype.UUID{Bytes: id, Valid: true})
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return model.ErrorNotFound
+	}
+	return nil
+}
+
+// EnqueueWebhookEvent сохраняет событие и ставит доставки на подписанные webhooks конкурса.
+// Возвращает число поставленных доставок.
+func (r *Repository) EnqueueWebhookEvent(ctx context.Context, contestID model.ContestID, eventType model.WebhookEventType, data []byte) (int64, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	contestUUID, err := uuid.Parse(string(contestID))
+	if err != nil {
+		return 0, err
+	}
+	return reposqlc.EnqueueWebhookEvent(ctx, &sqlc_repository.EnqueueWebhookEventParams{
+		ContestID: pgtype.UUID{Bytes: contestUUID, Valid: true},
+		Type:      string(eventType),
+		Data:      data,
+	})
+}
+
+// ClaimWebhookDeliveries забирает до limit доставок, готовых к отправке на момент now, и резервирует их до leaseUntil
+func (r *Repository) ClaimWebhookDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*model.WebhookDeliveryJob, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	rows, err := reposqlc.ClaimWebhookDeliveries(ctx, &sqlc_repository.ClaimWebhookDeliveriesParams{
+		LeaseUntil: pgtype.Timestamptz{Time: leaseUntil, Valid: true},
+		Now:        pgtype.Timestamptz{Time: now, Valid: true},
+		LimitCount: int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*model.WebhookDeliveryJob, len(rows))
+	for i, row := range rows {
+		result[i] = &model.WebhookDeliveryJob{
+			ID:       uuidString(row.ID),
+			Attempts: int(row.Attempts),
+			URL:      row.Url,
+			Secret:   row.Secret,
+			Event: &model.WebhookEvent{
+				ID:        uuidString(row.EventID),
+				Type:      model.WebhookEventType(row.Type),
+				ContestID: model.ContestID(uuidString(row.ContestID)),
+				CreatedAt: row.CreatedAt.Time,
+				Data:      row.Data,
+			},
+		}
+	}
+	return result, nil
+}
+
+// FinishWebhookDeliveryAttempt сохраняет результат попытки: sent - доставлено, pending - повтор в nextAttemptAt,
+// failed - доставка прекращена. response nil, если ответа не было.
+func (r *Repository) FinishWebhookDeliveryAttempt(ctx context.Context, deliveryID string, status model.OutboxStatus, response *model.WebhookResponse, lastError string, nextAttemptAt time.Time) error {
+	reposqlc := sqlc_repository.New(r.conn)
+	id, err := uuid.Parse(deliveryID)
+	if err != nil {
+		return err
+	}
+	params := &sqlc_repository.FinishWebhookDeliveryAttemptParams{
+		ID:            pgtype.UUID{Bytes: id, Valid: true},
+		Status:        string(status),
+		LastError:     lastError,
+		NextAttemptAt: pgtype.Timestamptz{Time: nextAttemptAt, Valid: true},
+	}
+	if response != nil {
+		statusCode := int32(response.StatusCode)
+		params.ResponseStatus = &statusCode
+		params.ResponseBody = response.Body
+		params.DurationMs = int32(response.Duration.Milliseconds())
+	}
+	return reposqlc.FinishWebhookDeliveryAttempt(ctx, params)
+}
+
+// ListWebhookDeliveries журнал доставок webhook, новые первыми, и общее число доставок
+func (r *Repository) ListWebhookDeliveries(ctx context.Context, endpointID string, limit, offset int) ([]*model.WebhookDelivery, int64, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	id, err := uuid.Parse(endpointID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: invalid webhook id", model.ErrorNotFound)
+	}
+	endpointUUID := pgtype.UUID{Bytes: id, Valid: true}
+	rows, err := reposqlc.ListWebhookDeliveries(ctx, &sqlc_repository.ListWebhookDeliveriesParams{
+		EndpointID:  endpointUUID,
+		LimitCount:  int32(limit),
+		OffsetCount: int32(offset),
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := reposqlc.CountWebhookDeliveries(ctx, endpointUUID)
+	if err != nil {
+		return nil, 0, err
+	}
+	result := make([]*model.WebhookDelivery, len(rows))
+	for i, row := range rows {
+		result[i] = toModelWebhookDelivery(row)
+	}
+	return result, total, nil
+}
+
+func (r *Repository) GetWebhookDelivery(ctx context.Context, deliveryID string) (*model.WebhookDelivery, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	id, err := uuid.Parse(deliveryID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid delivery id", model.ErrorNotFound)
+	}
+	row, err := reposqlc.GetWebhookDelivery(ctx, pgtype.UUID{Bytes: id, Valid: true})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %v", model.ErrorNotFound, err)
+		}
+		return nil, err
+	}
+	return toModelWebhookDelivery((*sqlc_repository.ListWebhookDeliveriesRow)(row)), nil
+}
+
+// RedeliverWebhook ставит в очередь новую доставку того же события на тот же webhook
+func (r *Repository) RedeliverWebhook(ctx context.Context, deliveryID string) (*model.WebhookDelivery, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	id, err := uuid.Parse(deliveryID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid delivery id", model.ErrorNotFound)
+	}
+	row, err := reposqlc.RedeliverWebhook(ctx, pgtype.UUID{Bytes: id, Valid: true})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %v", model.ErrorNotFound, err)
+		}
+		return nil, err
+	}
+	return toModelWebhookDelivery((*sqlc_repository.ListWebhookDeliveriesRow)(row)), nil
+}
+
+func webhookEventTypeStrings(eventTypes []model.WebhookEventType) []string {
+	result := make([]string, len(eventTypes))
+	for i, eventType := range eventTypes {
+		result[i] = string(eventType)
+	}
+	return result
+}
+
+func toModelWebhookEndpoint(endpoint *sqlc_repository.WebhookEndpoint) *model.WebhookEndpoint {
+	result := &model.WebhookEndpoint{
+		ID:         uuidString(endpoint.ID),
+		ContestID:  model.ContestID(uuidString(endpoint.ContestID)),
+		URL:        endpoint.Url,
+		Secret:     endpoint.Secret,
+		EventTypes: make([]model.WebhookEventType, len(endpoint.EventTypes)),
+		Active:     endpoint.Active,
+		CreatedAt:  endpoint.CreatedAt.Time,
+		UpdatedAt:  endpoint.UpdatedAt.Time,
+	}
+	for i, eventType := range endpoint.EventTypes {
+		result.EventTypes[i] = model.WebhookEventType(eventType)
+	}
+	if endpoint.CreatedByUserID.Valid {
+		userID := model.UserID(endpoint.CreatedByUserID.Int64)
+		result.CreatedByUserID = &userID
+	}
+	return result
+}
+
+func toModelWebhookDelivery(row *sqlc_repository.ListWebhookDeliveriesRow) *model.WebhookDelivery {
+	result := &model.WebhookDelivery{
+		ID:            uuidString(row.ID),
+		EndpointID:    uuidString(row.EndpointID),
+		EventID:       uuidString(row.EventID),
+		EventType:     model.WebhookEventType(row.EventType),
+		Redelivery:    row.Redelivery,
+		Status:        model.OutboxStatus(row.Status),
+		Attempts:      int(row.Attempts),
+		ResponseBody:  row.ResponseBody,
+		DurationMs:    int(row.DurationMs),
+		LastError:     row.LastError,
+		LastAttemptAt: timePtr(row.LastAttemptAt),
+		CreatedAt:     row.CreatedAt.Time,
+		DeliveredAt:   timePtr(row.DeliveredAt),
+	}
+	if row.ResponseStatus != nil {
+		responseStatus := int(*row.ResponseStatus)
+		result.ResponseStatus = &responseStatus
+	}
+	// для ожидающей доставки время следующей попытки, у завершенных оно не имеет смысла
+	if result.Status == model.OutboxPending {
+		result.NextAttemptAt = timePtr(row.NextAttemptAt)
+	}
+	return result
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"toppet/server/internal/model"
+	sqlc_repository "toppet/server/internal/repository_sqlc"
+)
+
+func (r *Repository) CreateWSTicket(ctx context.Context, ticketHash string, userID model.UserID, expiresAt time.Time) error {
+	reposqlc := sqlc_repository.New(r.conn)
+	return reposqlc.CreateWSTicket(ctx, &sqlc_repository.CreateWSTicketParams{
+		TicketHash: ticketHash,
+		UserID:     int64(userID),
+		ExpiresAt:  pgtype.Timestamptz{Time: expiresAt, Valid: true},
+	})
+}
+
+// ConsumeWSTicket удаляет билет и возвращает его владельца.
+// Повторно использованный или просроченный билет дает model.ErrorNotFound.
+func (r *Repository) ConsumeWSTicket(ctx context.Context, ticketHash string) (model.UserID, error) {
+	reposqlc := sqlc_repository.New(r.conn)
+	userID, err := reposqlc.ConsumeWSTicket(ctx, ticketHash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, fmt.Errorf("%w: %v", model.ErrorNotFound, err)
+		}
+		return 0, err
+	}
+	return model.UserID(userID), nil
+}
+
+func (r *Repository) DeleteExpiredWSTickets(ctx context.Context) error {
+	reposqlc := sqlc_repository.New(r.conn)
+	return reposqlc.DeleteExpiredWSTickets(ctx)
+}
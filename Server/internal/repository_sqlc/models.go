@@ -8,6 +8,25 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type AccountDeletionJob struct {
+	ID         pgtype.UUID
+	UserID     int64
+	Status     string
+	Attempts   int32
+	LastError  string
+	CreatedAt  pgtype.Timestamptz
+	StartedAt  pgtype.Timestamptz
+	FinishedAt pgtype.Timestamptz
+	UpdatedAt  pgtype.Timestamptz
+}
+
+type Breed struct {
+	Code        string
+	SpeciesCode string
+	NameRu      string
+	NameEn      string
+}
+
 type Contest struct {
 	ID              pgtype.UUID
 	CreatedByUserID int64
@@ -16,6 +35,40 @@ type Contest struct {
 	Status          string
 	CreatedAt       pgtype.Timestamptz
 	UpdatedAt       pgtype.Timestamptz
+	HiddenAt        pgtype.Timestamptz
+	VotingMode      string
+	MaxChoices      int32
+	MinBallots      int32
+	JuryWeight      int32
+}
+
+type ContestBracket struct {
+	ContestID             pgtype.UUID
+	Seeding               string
+	RoundDurationMinutes  int32
+	Status                string
+	CurrentRound          int32
+	ChampionParticipantID pgtype.UUID
+	CreatedAt             pgtype.Timestamptz
+	UpdatedAt             pgtype.Timestamptz
+}
+
+type ContestBracketRound struct {
+	ContestID pgtype.UUID
+	Round     int32
+	Status    string
+	StartsAt  pgtype.Timestamptz
+	EndsAt    pgtype.Timestamptz
+}
+
+type ContestCategory struct {
+	ID          pgtype.UUID
+	ContestID   pgtype.UUID
+	Name        string
+	Description string
+	Position    int32
+	CreatedAt   pgtype.Timestamptz
+	UpdatedAt   pgtype.Timestamptz
 }
 
 type ContestChatMessage struct {
@@ -26,6 +79,7 @@ type ContestChatMessage struct {
 	IsSystem  bool
 	CreatedAt pgtype.Timestamptz
 	UpdatedAt pgtype.Timestamptz
+	HiddenAt  pgtype.Timestamptz
 }
 
 type ContestComment struct {
@@ -35,16 +89,69 @@ type ContestComment struct {
 	Text          string
 	CreatedAt     pgtype.Timestamptz
 	UpdatedAt     pgtype.Timestamptz
+	HiddenAt      pgtype.Timestamptz
+}
+
+type ContestEntryRule struct {
+	ContestID           pgtype.UUID
+	MaxEntriesPerUser   int32
+	MaxEntries          int32
+	MaxPhotosPerEntry   int32
+	MaxPhotoSizeMb      int32
+	VideoAllowed        bool
+	MaxVideoDurationSec int32
+	MaxVideoSizeMb      int32
+	RequiredFields      []string
+	EntryDeadline       pgtype.Timestamptz
+	UpdatedAt           pgtype.Timestamptz
+	RequireApproval     bool
+	AllowedSpecies      []string
+	MinAgeMonths        int32
+	MaxAgeMonths        int32
+}
+
+type ContestJuryCriterium struct {
+	ID          pgtype.UUID
+	ContestID   pgtype.UUID
+	Name        string
+	Description string
+	Weight      int32
+	MaxScore    int32
+	Position    int32
+	CreatedAt   pgtype.Timestamptz
+}
+
+type ContestMember struct {
+	ContestID       pgtype.UUID
+	UserID          int64
+	Role            string
+	Status          string
+	InvitedByUserID *int64
+	CreatedAt       pgtype.Timestamptz
+	AcceptedAt      pgtype.Timestamptz
 }
 
 type ContestParticipant struct {
-	ID             pgtype.UUID
-	ContestID      pgtype.UUID
-	UserID         int64
-	PetName        string
-	PetDescription string
-	CreatedAt      pgtype.Timestamptz
-	UpdatedAt      pgtype.Timestamptz
+	ID                     pgtype.UUID
+	ContestID              pgtype.UUID
+	UserID                 int64
+	PetName                string
+	PetDescription         string
+	CreatedAt              pgtype.Timestamptz
+	UpdatedAt              pgtype.Timestamptz
+	HiddenAt               pgtype.Timestamptz
+	ModerationStatus       string
+	ModerationReason       string
+	ModeratedByUserID      pgtype.Int8
+	ModeratedAt            pgtype.Timestamptz
+	DisqualifiedAt         pgtype.Timestamptz
+	DisqualifiedByUserID   pgtype.Int8
+	DisqualificationReason string
+	PetID                  pgtype.UUID
+	Species                *string
+	Breed                  *string
+	Sex                    *string
+	BirthDate              pgtype.Date
 }
 
 type ContestParticipantPhoto struct {
@@ -64,12 +171,103 @@ type ContestParticipantVideo struct {
 }
 
 type ContestVote struct {
+	ID               pgtype.UUID
+	ContestID        pgtype.UUID
+	ParticipantID    pgtype.UUID
+	UserID           int64
+	CreatedAt        pgtype.Timestamptz
+	UpdatedAt        pgtype.Timestamptz
+	IpHash           string
+	UserAgent        string
+	AuthProvider     string
+	AccountCreatedAt pgtype.Timestamptz
+	FraudScore       int32
+	FraudReasons     []string
+	FlaggedAt        pgtype.Timestamptz
+	VoidedAt         pgtype.Timestamptz
+	VoidedByUserID   *int64
+	VoidReason       string
+	CategoryID       pgtype.UUID
+	Recastable       bool
+}
+
+type ContestVotingPolicy struct {
+	ContestID              pgtype.UUID
+	MinAccountAgeHours     int32
+	RequiredProviders      []string
+	ExcludeOwnParticipants bool
+	InviteOnly             bool
+	UpdatedAt              pgtype.Timestamptz
+}
+
+type EmailOutbox struct {
+	ID             pgtype.UUID
+	UserID         pgtype.Int8
+	ToEmail        string
+	Subject        string
+	TextBody       string
+	HtmlBody       string
+	UnsubscribeUrl string
+	Status         string
+	Attempts       int32
+	LastError      string
+	NextAttemptAt  pgtype.Timestamptz
+	CreatedAt      pgtype.Timestamptz
+	SentAt         pgtype.Timestamptz
+}
+
+type ModerationAction struct {
+	ID          pgtype.UUID
+	ActorUserID int64
+	Action      string
+	TargetType  string
+	TargetID    string
+	Reason      string
+	CreatedAt   pgtype.Timestamptz
+}
+
+type Notification struct {
 	ID            pgtype.UUID
+	UserID        int64
+	Type          string
 	ContestID     pgtype.UUID
 	ParticipantID pgtype.UUID
-	UserID        int64
+	CommentID     pgtype.UUID
+	ActorUserID   pgtype.Int8
+	Title         string
+	Text          string
+	ReadAt        pgtype.Timestamptz
 	CreatedAt     pgtype.Timestamptz
-	UpdatedAt     pgtype.Timestamptz
+}
+
+type NotificationPreference struct {
+	UserID                int64
+	Email                 *string
+	EmailVotingStarted    bool
+	EmailResultsPublished bool
+	UpdatedAt             pgtype.Timestamptz
+}
+
+type Pet struct {
+	ID          pgtype.UUID
+	OwnerUserID int64
+	Name        string
+	Species     *string
+	Breed       *string
+	BirthDate   pgtype.Date
+	Bio         string
+	CreatedAt   pgtype.Timestamptz
+	UpdatedAt   pgtype.Timestamptz
+	Sex         *string
+}
+
+type PetPhoto struct {
+	ID        pgtype.UUID
+	PetID     pgtype.UUID
+	Url       string
+	ThumbUrl  *string
+	Position  int32
+	CreatedAt pgtype.Timestamptz
 }
 
 type PhotoLike struct {
@@ -79,15 +277,85 @@ type PhotoLike struct {
 	CreatedAt pgtype.Timestamptz
 }
 
+type RateLimitBucket struct {
+	BucketKey string
+	Tokens    float64
+	Allowed   bool
+	UpdatedAt pgtype.Timestamptz
+}
+
+type Species struct {
+	Code     string
+	NameRu   string
+	NameEn   string
+	Position int32
+}
+
+type TelegramAccount struct {
+	UserID   int64
+	ChatID   int64
+	Username string
+	LinkedAt pgtype.Timestamptz
+}
+
+type TelegramAnnouncement struct {
+	ID             pgtype.UUID
+	ContestID      pgtype.UUID
+	OutboxID       pgtype.UUID
+	ChatID         string
+	Text           string
+	PostedByUserID pgtype.Int8
+	CreatedAt      pgtype.Timestamptz
+}
+
+type TelegramOutbox struct {
+	ID            pgtype.UUID
+	ChatID        string
+	Text          string
+	Status        string
+	Attempts      int32
+	LastError     string
+	NextAttemptAt pgtype.Timestamptz
+	CreatedAt     pgtype.Timestamptz
+	SentAt        pgtype.Timestamptz
+}
+
 type User struct {
-	UserID    int64
-	Name      string
-	CreatedAt pgtype.Timestamptz
+	UserID         int64
+	Name           string
+	CreatedAt      pgtype.Timestamptz
+	AvatarUrl      *string
+	Bio            string
+	AvatarThumbUrl *string
+	Locale         string
+	DeletedAt      pgtype.Timestamptz
 }
 
 type UserAuthProvider struct {
-	UserID      int64
-	ProviderUid string
-	Provider    string
-	Name        *string
+	UserID          int64
+	ProviderUid     string
+	Provider        string
+	Name            *string
+	AvatarUrl       *string
+	AvatarUpdatedAt pgtype.Timestamptz
+}
+
+type UserPrivacySetting struct {
+	UserID       int64
+	ShowContests bool
+	ShowPets     bool
+	ShowResults  bool
+	UpdatedAt    pgtype.Timestamptz
+}
+
+type WebhookEndpoint struct {
+	ID              pgtype.UUID
+	ContestID       pgtype.UUID
+	Url             string
+	Secret          string
+	EventTypes      []string
+	Active          bool
+	CreatedByUserID pgtype.Int8
+	CreatedAt       pgtype.Timestamptz
+	UpdatedAt       pgtype.Timestamptz
 }
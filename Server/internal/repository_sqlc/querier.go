@@ -11,67 +11,277 @@ import (
 )
 
 type Querier interface {
+	AcceptContestMemberInvite(ctx context.Context, arg *AcceptContestMemberInviteParams) (*ContestMember, error)
+	AddContestInvitedVoter(ctx context.Context, arg *AddContestInvitedVoterParams) error
 	// Contest Participant Photos
 	AddParticipantPhoto(ctx context.Context, arg *AddParticipantPhotoParams) (*ContestParticipantPhoto, error)
+	// Pet Photos
+	AddPetPhoto(ctx context.Context, arg *AddPetPhotoParams) (*PetPhoto, error)
 	AddUserAuthProviders(ctx context.Context, arg *AddUserAuthProvidersParams) (*UserAuthProvider, error)
+	AddUserRole(ctx context.Context, arg *AddUserRoleParams) error
+	ClaimAccountDeletionJob(ctx context.Context, startedAt pgtype.Timestamptz) (*AccountDeletionJob, error)
+	// Забирает пачку писем к отправке. next_attempt_at сдвигается на lease_until: если процесс упадет
+	// во время отправки, письмо снова станет доступно после истечения аренды.
+	ClaimEmailOutbox(ctx context.Context, arg *ClaimEmailOutboxParams) ([]*EmailOutbox, error)
+	// Забирает пачку сообщений к отправке, аренда устроена так же, как в ClaimEmailOutbox
+	ClaimTelegramOutbox(ctx context.Context, arg *ClaimTelegramOutboxParams) ([]*TelegramOutbox, error)
+	// Забирает пачку доставок вместе с адресом, ключом и событием; аренда устроена так же, как в ClaimEmailOutbox.
+	// Доставки выключенного webhook ждут, пока его не включат снова.
+	ClaimWebhookDeliveries(ctx context.Context, arg *ClaimWebhookDeliveriesParams) ([]*ClaimWebhookDeliveriesRow, error)
+	ConsumeEmailLoginToken(ctx context.Context, tokenHash string) (string, error)
+	ConsumeTelegramLinkToken(ctx context.Context, tokenHash string) (int64, error)
+	ConsumeWSTicket(ctx context.Context, ticketHash string) (int64, error)
 	CountChatMessages(ctx context.Context, contestID pgtype.UUID) (int64, error)
 	CountCommentsByParticipant(ctx context.Context, participantID pgtype.UUID) (int64, error)
 	CountContests(ctx context.Context, dollar_1 string) (int64, error)
+	CountEmailLoginTokensSince(ctx context.Context, arg *CountEmailLoginTokensSinceParams) (int64, error)
+	CountModerationActions(ctx context.Context) (int64, error)
+	CountNotifications(ctx context.Context, userID int64) (*CountNotificationsRow, error)
+	CountParticipantsByContest(ctx context.Context, contestID pgtype.UUID) (int64, error)
+	CountParticipantsByContestAndUser(ctx context.Context, arg *CountParticipantsByContestAndUserParams) (int64, error)
+	CountPetPhotos(ctx context.Context, petID pgtype.UUID) (int64, error)
 	CountPhotoLikes(ctx context.Context, photoID pgtype.UUID) (int64, error)
+	CountPhotoLikesByParticipant(ctx context.Context, contestID pgtype.UUID) ([]*CountPhotoLikesByParticipantRow, error)
+	CountPhotosByParticipant(ctx context.Context, participantID pgtype.UUID) (int64, error)
+	CountVoteChoicesByParticipant(ctx context.Context, arg *CountVoteChoicesByParticipantParams) (*CountVoteChoicesByParticipantRow, error)
 	CountVotesByContest(ctx context.Context, contestID pgtype.UUID) (int64, error)
 	CountVotesByContests(ctx context.Context, dollar_1 []pgtype.UUID) ([]*CountVotesByContestsRow, error)
-	CountVotesByParticipant(ctx context.Context, participantID pgtype.UUID) (int64, error)
+	CountVotesByParticipant(ctx context.Context, arg *CountVotesByParticipantParams) (int64, error)
+	CountWebhookDeliveries(ctx context.Context, endpointID pgtype.UUID) (int64, error)
+	// Account deletion
+	CreateAccountDeletionJob(ctx context.Context, userID int64) (*AccountDeletionJob, error)
+	CreateBracketRound(ctx context.Context, arg *CreateBracketRoundParams) error
 	// Contest Chat Messages
 	CreateChatMessage(ctx context.Context, arg *CreateChatMessageParams) (*ContestChatMessage, error)
 	// Contest Comments
 	CreateComment(ctx context.Context, arg *CreateCommentParams) (*ContestComment, error)
 	// Contests
 	CreateContest(ctx context.Context, arg *CreateContestParams) (*Contest, error)
+	// Contest Categories
+	CreateContestCategory(ctx context.Context, arg *CreateContestCategoryParams) (*ContestCategory, error)
+	// Contest Members
+	CreateContestMember(ctx context.Context, arg *CreateContestMemberParams) (*ContestMember, error)
+	// Email Login Tokens
+	CreateEmailLoginToken(ctx context.Context, arg *CreateEmailLoginTokenParams) error
+	// Contest Jury
+	CreateJuryCriterion(ctx context.Context, arg *CreateJuryCriterionParams) (*ContestJuryCriterium, error)
+	// Moderation Actions
+	CreateModerationAction(ctx context.Context, arg *CreateModerationActionParams) (*ModerationAction, error)
+	// Notifications
+	// Рассылка одного события нескольким получателям; удаленные пользователи пропускаются
+	CreateNotifications(ctx context.Context, arg *CreateNotificationsParams) ([]*CreateNotificationsRow, error)
 	// Contest Participants
+	// Лимиты заявок (0 - без лимита) проверяются в самом запросе: сверх лимита строка не вставляется
 	CreateParticipant(ctx context.Context, arg *CreateParticipantParams) (*ContestParticipant, error)
+	// Pets
+	CreatePet(ctx context.Context, arg *CreatePetParams) (*Pet, error)
+	// Сохраняет анонс и ставит его в очередь отправки одним запросом.
+	// Если предыдущий анонс конкурса моложе cooldown_seconds, ничего не вставляет и не возвращает строк.
+	CreateTelegramAnnouncement(ctx context.Context, arg *CreateTelegramAnnouncementParams) (*CreateTelegramAnnouncementRow, error)
+	// Telegram
+	// Новая ссылка привязки заменяет прежние ссылки пользователя; заодно удаляются просроченные
+	CreateTelegramLinkToken(ctx context.Context, arg *CreateTelegramLinkTokenParams) error
 	// Users
 	CreateUser(ctx context.Context, name string) (*User, error)
+	// WebSocket Tickets
+	CreateWSTicket(ctx context.Context, arg *CreateWSTicketParams) error
+	// Webhooks
+	CreateWebhookEndpoint(ctx context.Context, arg *CreateWebhookEndpointParams) (*WebhookEndpoint, error)
+	DecideBracketMatchup(ctx context.Context, arg *DecideBracketMatchupParams) error
 	DeleteChatMessage(ctx context.Context, arg *DeleteChatMessageParams) (pgtype.UUID, error)
+	DeleteChatMessageByID(ctx context.Context, id pgtype.UUID) (pgtype.UUID, error)
 	DeleteComment(ctx context.Context, id pgtype.UUID) error
 	DeleteCommentsByParticipant(ctx context.Context, participantID pgtype.UUID) error
 	DeleteContest(ctx context.Context, id pgtype.UUID) error
+	DeleteContestCategory(ctx context.Context, arg *DeleteContestCategoryParams) error
+	DeleteContestInvitedVoter(ctx context.Context, arg *DeleteContestInvitedVoterParams) error
+	DeleteContestMember(ctx context.Context, arg *DeleteContestMemberParams) error
 	DeleteContestVoteByUser(ctx context.Context, arg *DeleteContestVoteByUserParams) (pgtype.UUID, error)
+	DeleteExpiredWSTickets(ctx context.Context) error
+	DeleteJuryCriterion(ctx context.Context, arg *DeleteJuryCriterionParams) error
 	DeleteParticipant(ctx context.Context, id pgtype.UUID) error
 	DeleteParticipantPhoto(ctx context.Context, id pgtype.UUID) error
 	DeleteParticipantVideo(ctx context.Context, participantID pgtype.UUID) error
+	DeletePet(ctx context.Context, id pgtype.UUID) error
+	DeletePetPhoto(ctx context.Context, arg *DeletePetPhotoParams) (int64, error)
 	DeletePhotoLike(ctx context.Context, arg *DeletePhotoLikeParams) error
+	DeleteStaleRateLimitBuckets(ctx context.Context, updatedAt pgtype.Timestamptz) error
+	DeleteTelegramAccount(ctx context.Context, userID int64) (int64, error)
+	// Забывает чат, который больше недоступен боту: привязку и подписки
+	DeleteTelegramChat(ctx context.Context, chatID int64) error
+	// Удаляет персональные данные одним запросом (изменяющие CTE выполняются атомарно):
+	// голоса, лайки, медиа заявок, питомцев, привязки провайдеров и Telegram, роли, уведомления, письма и ссылки входа по email;
+	// профиль обезличивается.
+	// Конкурсы, заявки, комментарии и сообщения остаются за обезличенным пользователем.
+	DeleteUserPersonalData(ctx context.Context, arg *DeleteUserPersonalDataParams) error
 	DeleteVotesByParticipant(ctx context.Context, participantID pgtype.UUID) error
+	DeleteWebhookEndpoint(ctx context.Context, id pgtype.UUID) (int64, error)
+	// Передача владения: прежний владелец становится организатором до повышения нового -
+	// частичный уникальный индекс idx_contest_members_owner не допускает двух владельцев
+	DemoteContestOwner(ctx context.Context, arg *DemoteContestOwnerParams) error
+	DisqualifyParticipant(ctx context.Context, arg *DisqualifyParticipantParams) (int64, error)
+	// Пакетная постановка писем в очередь; user_id = 0 - письмо без привязки к пользователю
+	EnqueueEmails(ctx context.Context, arg *EnqueueEmailsParams) (int64, error)
+	// Пакетная постановка сообщений в очередь
+	EnqueueTelegramMessages(ctx context.Context, arg *EnqueueTelegramMessagesParams) (int64, error)
+	// Сохраняет событие и ставит доставки на все включенные webhooks конкурса, подписанные на его тип.
+	// Если подходящих webhooks нет, событие не сохраняется.
+	EnqueueWebhookEvent(ctx context.Context, arg *EnqueueWebhookEventParams) (int64, error)
+	FailEmailAttempt(ctx context.Context, arg *FailEmailAttemptParams) error
+	FailTelegramMessageAttempt(ctx context.Context, arg *FailTelegramMessageAttemptParams) error
+	FinishAccountDeletionJob(ctx context.Context, arg *FinishAccountDeletionJobParams) error
+	FinishWebhookDeliveryAttempt(ctx context.Context, arg *FinishWebhookDeliveryAttemptParams) error
+	// Выбывший участник отдает все нерешенные пары сопернику
+	ForfeitBracketMatchups(ctx context.Context, arg *ForfeitBracketMatchupsParams) ([]pgtype.UUID, error)
+	GetAccountDeletionJob(ctx context.Context, id pgtype.UUID) (*AccountDeletionJob, error)
+	GetBracketMatchup(ctx context.Context, arg *GetBracketMatchupParams) (*GetBracketMatchupRow, error)
+	GetBreedByCode(ctx context.Context, code string) (*Breed, error)
+	GetChatMessageContestID(ctx context.Context, id pgtype.UUID) (pgtype.UUID, error)
 	GetCommentByID(ctx context.Context, id pgtype.UUID) (*ContestComment, error)
+	// Contest Brackets
+	GetContestBracket(ctx context.Context, contestID pgtype.UUID) (*ContestBracket, error)
 	GetContestByID(ctx context.Context, id pgtype.UUID) (*Contest, error)
+	GetContestCategory(ctx context.Context, arg *GetContestCategoryParams) (*ContestCategory, error)
+	// Contest Entry Rules
+	GetContestEntryRules(ctx context.Context, contestID pgtype.UUID) (*ContestEntryRule, error)
+	GetContestMember(ctx context.Context, arg *GetContestMemberParams) (*ContestMember, error)
 	GetContestVoteByUser(ctx context.Context, arg *GetContestVoteByUserParams) (*ContestVote, error)
+	// Contest Voting Policies
+	GetContestVotingPolicy(ctx context.Context, contestID pgtype.UUID) (*ContestVotingPolicy, error)
+	GetLatestProviderAvatar(ctx context.Context, userID int64) (*string, error)
 	GetMaxPhotoPositionByParticipant(ctx context.Context, participantID pgtype.UUID) (interface{}, error)
+	// Email Notifications
+	GetNotificationPreferences(ctx context.Context, userID int64) (*NotificationPreference, error)
 	GetParticipantByContestAndUser(ctx context.Context, arg *GetParticipantByContestAndUserParams) (*GetParticipantByContestAndUserRow, error)
 	GetParticipantByID(ctx context.Context, id pgtype.UUID) (*GetParticipantByIDRow, error)
+	GetPetByID(ctx context.Context, id pgtype.UUID) (*Pet, error)
 	GetPhotoLikeByUser(ctx context.Context, arg *GetPhotoLikeByUserParams) (*PhotoLike, error)
 	GetPhotosByParticipantID(ctx context.Context, participantID pgtype.UUID) ([]*ContestParticipantPhoto, error)
+	GetTelegramAccount(ctx context.Context, userID int64) (*TelegramAccount, error)
+	GetTelegramAccountByChat(ctx context.Context, chatID int64) (*TelegramAccount, error)
 	GetUserAuthProvidersByProviderUid(ctx context.Context, arg *GetUserAuthProvidersByProviderUidParams) (*UserAuthProvider, error)
 	GetUserAuthProvidersByUserID(ctx context.Context, userID int64) ([]*UserAuthProvider, error)
 	GetUserByID(ctx context.Context, userID int64) (*User, error)
+	// User Privacy Settings
+	GetUserPrivacySettings(ctx context.Context, userID int64) (*UserPrivacySetting, error)
 	GetVideoByParticipantID(ctx context.Context, participantID pgtype.UUID) (*ContestParticipantVideo, error)
+	GetWebhookDelivery(ctx context.Context, id pgtype.UUID) (*GetWebhookDeliveryRow, error)
+	GetWebhookEndpoint(ctx context.Context, id pgtype.UUID) (*WebhookEndpoint, error)
+	IsContestInvitedVoter(ctx context.Context, arg *IsContestInvitedVoterParams) (bool, error)
+	LinkTelegramAccount(ctx context.Context, arg *LinkTelegramAccountParams) (*TelegramAccount, error)
+	// Роли пользователя для проверки access токена; удаленный аккаунт не возвращает строк
+	ListActiveUserRoles(ctx context.Context, userID int64) ([]string, error)
+	ListBracketMatchups(ctx context.Context, contestID pgtype.UUID) ([]*ListBracketMatchupsRow, error)
+	ListBracketRounds(ctx context.Context, contestID pgtype.UUID) ([]*ContestBracketRound, error)
+	ListBracketVotesByUser(ctx context.Context, arg *ListBracketVotesByUserParams) ([]*ListBracketVotesByUserRow, error)
+	ListBreedsBySpecies(ctx context.Context, speciesCode string) ([]*Breed, error)
 	ListChatMessages(ctx context.Context, arg *ListChatMessagesParams) ([]*ListChatMessagesRow, error)
+	ListChatMessagesByUser(ctx context.Context, userID int64) ([]*ContestChatMessage, error)
 	ListCommentsByParticipant(ctx context.Context, arg *ListCommentsByParticipantParams) ([]*ListCommentsByParticipantRow, error)
+	ListCommentsByUser(ctx context.Context, userID int64) ([]*ContestComment, error)
+	ListContestBallotChoices(ctx context.Context, arg *ListContestBallotChoicesParams) ([]*ListContestBallotChoicesRow, error)
+	ListContestCategories(ctx context.Context, contestID pgtype.UUID) ([]*ContestCategory, error)
+	ListContestIDsWithVotesSince(ctx context.Context, updatedAt pgtype.Timestamptz) ([]pgtype.UUID, error)
+	ListContestInvitedVoters(ctx context.Context, contestID pgtype.UUID) ([]*ListContestInvitedVotersRow, error)
+	ListContestMembers(ctx context.Context, contestID pgtype.UUID) ([]*ListContestMembersRow, error)
+	ListContestVoteChoices(ctx context.Context, voteID pgtype.UUID) ([]*ListContestVoteChoicesRow, error)
+	ListContestVotesByUser(ctx context.Context, userID int64) ([]*ContestVote, error)
+	ListContestVotesForFraudScoring(ctx context.Context, contestID pgtype.UUID) ([]*ListContestVotesForFraudScoringRow, error)
 	ListContests(ctx context.Context, arg *ListContestsParams) ([]*Contest, error)
+	ListContestsByCreator(ctx context.Context, createdByUserID int64) ([]*Contest, error)
+	ListDueBracketRounds(ctx context.Context, now pgtype.Timestamptz) ([]*ContestBracketRound, error)
+	// Получатели письма о событии: известен адрес и письма этого типа не отключены
+	ListEmailRecipients(ctx context.Context, arg *ListEmailRecipientsParams) ([]*ListEmailRecipientsRow, error)
+	ListFlaggedContestVotes(ctx context.Context, contestID pgtype.UUID) ([]*ListFlaggedContestVotesRow, error)
+	ListJuryCriteria(ctx context.Context, contestID pgtype.UUID) ([]*ContestJuryCriterium, error)
+	ListJuryScores(ctx context.Context, contestID pgtype.UUID) ([]*ListJuryScoresRow, error)
+	ListModerationActions(ctx context.Context, arg *ListModerationActionsParams) ([]*ModerationAction, error)
+	ListNotifications(ctx context.Context, arg *ListNotificationsParams) ([]*ListNotificationsRow, error)
+	// Авторы видимых комментариев к заявке - получатели уведомлений об ответах
+	ListParticipantCommenters(ctx context.Context, participantID pgtype.UUID) ([]int64, error)
 	ListParticipantsByContest(ctx context.Context, contestID pgtype.UUID) ([]*ListParticipantsByContestRow, error)
+	ListParticipantsByModerationStatus(ctx context.Context, arg *ListParticipantsByModerationStatusParams) ([]*ListParticipantsByModerationStatusRow, error)
+	ListParticipantsByUser(ctx context.Context, userID int64) ([]*ContestParticipant, error)
+	ListPetEntries(ctx context.Context, petID pgtype.UUID) ([]*ListPetEntriesRow, error)
+	ListPetPhotos(ctx context.Context, petID pgtype.UUID) ([]*PetPhoto, error)
+	ListPetsByOwner(ctx context.Context, ownerUserID int64) ([]*Pet, error)
 	ListPhotoLikesByPhotos(ctx context.Context, arg *ListPhotoLikesByPhotosParams) ([]*PhotoLike, error)
+	ListPublicContestsByCreator(ctx context.Context, arg *ListPublicContestsByCreatorParams) ([]*Contest, error)
+	// Species & Breeds
+	ListSpecies(ctx context.Context) ([]*Species, error)
+	// Анонсы конкурса со статусом доставки, новые первыми
+	ListTelegramAnnouncements(ctx context.Context, arg *ListTelegramAnnouncementsParams) ([]*ListTelegramAnnouncementsRow, error)
+	// Чаты, которым отправляются новости конкурса: подписчики и привязанные аккаунты участников
+	ListTelegramContestChats(ctx context.Context, contestID pgtype.UUID) ([]int64, error)
+	ListTelegramSubscriptions(ctx context.Context, chatID int64) ([]*ListTelegramSubscriptionsRow, error)
+	// User Roles
+	ListUserRoles(ctx context.Context, userID int64) ([]string, error)
 	ListVotersByParticipant(ctx context.Context, arg *ListVotersByParticipantParams) ([]*ListVotersByParticipantRow, error)
+	// Журнал доставок webhook, новые первыми
+	ListWebhookDeliveries(ctx context.Context, arg *ListWebhookDeliveriesParams) ([]*ListWebhookDeliveriesRow, error)
+	ListWebhookEndpoints(ctx context.Context, contestID pgtype.UUID) ([]*WebhookEndpoint, error)
+	// Блокирует строку конкурса до конца транзакции, чтобы проверка и вставка шли по очереди
+	LockContest(ctx context.Context, id pgtype.UUID) error
+	MarkAllNotificationsRead(ctx context.Context, userID int64) (int64, error)
+	MarkEmailSent(ctx context.Context, id pgtype.UUID) error
+	MarkNotificationRead(ctx context.Context, arg *MarkNotificationReadParams) (int64, error)
+	MarkTelegramMessageSent(ctx context.Context, id pgtype.UUID) error
+	PromoteContestOwner(ctx context.Context, arg *PromoteContestOwnerParams) (int64, error)
+	// Ставит в очередь новую доставку того же события на тот же webhook
+	RedeliverWebhook(ctx context.Context, id pgtype.UUID) (*RedeliverWebhookRow, error)
+	RemoveUserRole(ctx context.Context, arg *RemoveUserRoleParams) error
+	// Contest Vote Choices
+	ReplaceContestVoteChoices(ctx context.Context, arg *ReplaceContestVoteChoicesParams) error
+	SetBracketRoundStatus(ctx context.Context, arg *SetBracketRoundStatusParams) (int64, error)
+	SetChatMessageHidden(ctx context.Context, arg *SetChatMessageHiddenParams) (pgtype.UUID, error)
+	SetCommentHidden(ctx context.Context, arg *SetCommentHiddenParams) error
+	SetContestHidden(ctx context.Context, arg *SetContestHiddenParams) error
+	// Запоминает адрес из провайдера входа, если адрес еще неизвестен
+	SetNotificationEmailIfEmpty(ctx context.Context, arg *SetNotificationEmailIfEmptyParams) error
+	SetParticipantHidden(ctx context.Context, arg *SetParticipantHiddenParams) error
+	SetParticipantModeration(ctx context.Context, arg *SetParticipantModerationParams) error
+	SetUserAvatar(ctx context.Context, arg *SetUserAvatarParams) (*User, error)
+	SetUserAvatarIfEmpty(ctx context.Context, arg *SetUserAvatarIfEmptyParams) error
+	StartContestBracket(ctx context.Context, contestID pgtype.UUID) (int64, error)
+	SubscribeTelegramChat(ctx context.Context, arg *SubscribeTelegramChatParams) error
+	// Rate Limit Buckets
+	TakeRateLimitToken(ctx context.Context, arg *TakeRateLimitTokenParams) (*TakeRateLimitTokenRow, error)
+	// Отвязывает чат от другого аккаунта перед привязкой к user_id
+	UnlinkTelegramChat(ctx context.Context, arg *UnlinkTelegramChatParams) error
+	UnsubscribeTelegramChat(ctx context.Context, arg *UnsubscribeTelegramChatParams) (int64, error)
 	UpdateChatMessage(ctx context.Context, arg *UpdateChatMessageParams) (*ContestChatMessage, error)
 	UpdateComment(ctx context.Context, arg *UpdateCommentParams) (*ContestComment, error)
 	UpdateContest(ctx context.Context, arg *UpdateContestParams) (*Contest, error)
+	UpdateContestBracketState(ctx context.Context, arg *UpdateContestBracketStateParams) error
+	UpdateContestCategory(ctx context.Context, arg *UpdateContestCategoryParams) (*ContestCategory, error)
+	UpdateContestJuryWeight(ctx context.Context, arg *UpdateContestJuryWeightParams) (*Contest, error)
+	UpdateContestMemberRole(ctx context.Context, arg *UpdateContestMemberRoleParams) (*ContestMember, error)
+	UpdateContestOwner(ctx context.Context, arg *UpdateContestOwnerParams) error
 	UpdateContestStatus(ctx context.Context, arg *UpdateContestStatusParams) (*Contest, error)
+	UpdateContestVoteFraudScore(ctx context.Context, arg *UpdateContestVoteFraudScoreParams) error
+	UpdateContestVotingMode(ctx context.Context, arg *UpdateContestVotingModeParams) (*Contest, error)
 	UpdateParticipant(ctx context.Context, arg *UpdateParticipantParams) (*ContestParticipant, error)
 	UpdateParticipantPhotoOrder(ctx context.Context, arg *UpdateParticipantPhotoOrderParams) error
-	UpdateUserName(ctx context.Context, arg *UpdateUserNameParams) (*User, error)
+	UpdatePet(ctx context.Context, arg *UpdatePetParams) (*Pet, error)
+	UpdateUserAuthProviderAvatar(ctx context.Context, arg *UpdateUserAuthProviderAvatarParams) error
+	UpdateUserProfile(ctx context.Context, arg *UpdateUserProfileParams) (*User, error)
+	UpdateWebhookEndpoint(ctx context.Context, arg *UpdateWebhookEndpointParams) (*WebhookEndpoint, error)
+	UpsertBracketVote(ctx context.Context, arg *UpsertBracketVoteParams) error
+	UpsertContestBracket(ctx context.Context, arg *UpsertContestBracketParams) (*ContestBracket, error)
+	UpsertContestEntryRules(ctx context.Context, arg *UpsertContestEntryRulesParams) (*ContestEntryRule, error)
 	// Contest Votes
 	UpsertContestVote(ctx context.Context, arg *UpsertContestVoteParams) (*ContestVote, error)
+	UpsertContestVotingPolicy(ctx context.Context, arg *UpsertContestVotingPolicyParams) (*ContestVotingPolicy, error)
+	UpsertJuryScores(ctx context.Context, arg *UpsertJuryScoresParams) error
+	UpsertNotificationPreferences(ctx context.Context, arg *UpsertNotificationPreferencesParams) (*NotificationPreference, error)
 	// Contest Participant Videos
 	UpsertParticipantVideo(ctx context.Context, arg *UpsertParticipantVideoParams) (*ContestParticipantVideo, error)
 	// Photo Likes
 	UpsertPhotoLike(ctx context.Context, arg *UpsertPhotoLikeParams) (*PhotoLike, error)
+	UpsertUserPrivacySettings(ctx context.Context, arg *UpsertUserPrivacySettingsParams) (*UserPrivacySetting, error)
+	VoidContestVotes(ctx context.Context, arg *VoidContestVotesParams) ([]*VoidContestVotesRow, error)
+	// Аннулирует голоса и бюллетени, в которых выбран участник; автор может проголосовать заново
+	VoidParticipantVotes(ctx context.Context, arg *VoidParticipantVotesParams) ([]*VoidParticipantVotesRow, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -3,31 +3,158 @@
 -- name: CreateUser :one
 INSERT INTO users (name)
 VALUES ($1)
-RETURNING user_id, name, created_at;
+RETURNING user_id, name, created_at, avatar_url, bio, avatar_thumb_url, locale, deleted_at;
 
 -- name: GetUserByID :one
-SELECT user_id, name, created_at FROM users
+SELECT user_id, name, created_at, avatar_url, bio, avatar_thumb_url, locale, deleted_at FROM users
 WHERE user_id = $1;
 
--- name: UpdateUserName :one
+-- name: UpdateUserProfile :one
 UPDATE users
-SET name = $2
+SET name = $2, bio = $3, locale = $4
 WHERE user_id = $1
-RETURNING user_id, name, created_at;
+RETURNING user_id, name, created_at, avatar_url, bio, avatar_thumb_url, locale, deleted_at;
+
+-- name: SetUserAvatar :one
+UPDATE users
+SET avatar_url = $2, avatar_thumb_url = $3
+WHERE user_id = $1
+RETURNING user_id, name, created_at, avatar_url, bio, avatar_thumb_url, locale, deleted_at;
+
+-- name: SetUserAvatarIfEmpty :exec
+UPDATE users
+SET avatar_url = $2
+WHERE user_id = $1 AND avatar_url IS NULL;
 
 -- name: GetUserAuthProvidersByProviderUid :one
 SELECT user_id, provider_uid, provider, name FROM user_auth_providers
 WHERE provider_uid = $1 AND provider = $2;
 
 -- name: AddUserAuthProviders :one
-INSERT INTO user_auth_providers (user_id, provider_uid, provider, name)
-VALUES ($1, $2, $3, $4)
+INSERT INTO user_auth_providers (user_id, provider_uid, provider, name, avatar_url, avatar_updated_at)
+VALUES ($1, $2, $3, $4, $5, NOW())
 RETURNING *;
 
 -- name: GetUserAuthProvidersByUserID :many
 SELECT * FROM user_auth_providers
 WHERE user_id = $1;
 
+-- name: UpdateUserAuthProviderAvatar :exec
+UPDATE user_auth_providers
+SET avatar_url = $3, avatar_updated_at = NOW()
+WHERE provider_uid = $1 AND provider = $2;
+
+-- name: GetLatestProviderAvatar :one
+SELECT avatar_url FROM user_auth_providers
+WHERE user_id = $1 AND avatar_url IS NOT NULL
+ORDER BY avatar_updated_at DESC NULLS LAST
+LIMIT 1;
+
+-- User Privacy Settings
+
+-- name: GetUserPrivacySettings :one
+SELECT * FROM user_privacy_settings
+WHERE user_id = $1;
+
+-- name: UpsertUserPrivacySettings :one
+INSERT INTO user_privacy_settings (user_id, show_contests, show_pets, show_results)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id) DO UPDATE
+SET show_contests = EXCLUDED.show_contests,
+    show_pets = EXCLUDED.show_pets,
+    show_results = EXCLUDED.show_results,
+    updated_at = NOW()
+RETURNING *;
+
+-- Email Login Tokens
+
+-- name: CreateEmailLoginToken :exec
+INSERT INTO email_login_tokens (token_hash, email, expires_at)
+VALUES ($1, $2, $3);
+
+-- name: ConsumeEmailLoginToken :one
+UPDATE email_login_tokens
+SET used_at = NOW()
+WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW()
+RETURNING email;
+
+-- name: CountEmailLoginTokensSince :one
+SELECT count(1) FROM email_login_tokens
+WHERE email = $1 AND created_at > $2;
+
+-- WebSocket Tickets
+
+-- name: CreateWSTicket :exec
+INSERT INTO ws_tickets (ticket_hash, user_id, expires_at)
+VALUES ($1, $2, $3);
+
+-- name: ConsumeWSTicket :one
+DELETE FROM ws_tickets
+WHERE ticket_hash = $1 AND expires_at > NOW()
+RETURNING user_id;
+
+-- name: DeleteExpiredWSTickets :exec
+DELETE FROM ws_tickets
+WHERE expires_at <= NOW();
+
+-- Rate Limit Buckets
+
+-- name: TakeRateLimitToken :one
+INSERT INTO rate_limit_buckets AS b (bucket_key, tokens, allowed, updated_at)
+VALUES (sqlc.arg(bucket_key), sqlc.arg(burst)::float8 - 1, TRUE, NOW())
+ON CONFLICT (bucket_key) DO UPDATE
+SET tokens = CASE
+        WHEN LEAST(sqlc.arg(burst)::float8, b.tokens + EXTRACT(EPOCH FROM NOW() - b.updated_at)::float8 * sqlc.arg(rate)::float8) >= 1
+        THEN LEAST(sqlc.arg(burst)::float8, b.tokens + EXTRACT(EPOCH FROM NOW() - b.updated_at)::float8 * sqlc.arg(rate)::float8) - 1
+        ELSE LEAST(sqlc.arg(burst)::float8, b.tokens + EXTRACT(EPOCH FROM NOW() - b.updated_at)::float8 * sqlc.arg(rate)::float8)
+    END,
+    allowed = LEAST(sqlc.arg(burst)::float8, b.tokens + EXTRACT(EPOCH FROM NOW() - b.updated_at)::float8 * sqlc.arg(rate)::float8) >= 1,
+    updated_at = NOW()
+RETURNING tokens, allowed;
+
+-- name: DeleteStaleRateLimitBuckets :exec
+DELETE FROM rate_limit_buckets
+WHERE updated_at < $1;
+
+-- User Roles
+
+-- name: ListUserRoles :many
+SELECT role FROM user_roles
+WHERE user_id = $1
+ORDER BY role;
+
+-- name: ListActiveUserRoles :one
+-- Роли пользователя для проверки access токена; удаленный аккаунт не возвращает строк
+SELECT COALESCE(array_agg(r.role ORDER BY r.role) FILTER (WHERE r.role IS NOT NULL), '{}')::text[] AS roles
+FROM users u
+LEFT JOIN user_roles r ON r.user_id = u.user_id
+WHERE u.user_id = $1 AND u.deleted_at IS NULL
+GROUP BY u.user_id;
+
+-- name: AddUserRole :exec
+INSERT INTO user_roles (user_id, role, granted_by_user_id)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id, role) DO NOTHING;
+
+-- name: RemoveUserRole :exec
+DELETE FROM user_roles
+WHERE user_id = $1 AND role = $2;
+
+-- Moderation Actions
+
+-- name: CreateModerationAction :one
+INSERT INTO moderation_actions (actor_user_id, action, target_type, target_id, reason)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: ListModerationActions :many
+SELECT * FROM moderation_actions
+ORDER BY created_at DESC
+LIMIT $1 OFFSET $2;
+
+-- name: CountModerationActions :one
+SELECT count(1) FROM moderation_actions;
+
 -- Contests
 
 -- name: CreateContest :one
@@ -40,13 +167,24 @@ SELECT * FROM contests WHERE id = $1;
 
 -- name: ListContests :many
 SELECT * FROM contests
-WHERE (COALESCE($1::text, '') = '' OR status = $1)
+WHERE (COALESCE($1::text, '') = '' OR status = $1) AND hidden_at IS NULL
 ORDER BY created_at DESC
 LIMIT $2 OFFSET $3;
 
 -- name: CountContests :one
 SELECT count(1) FROM contests
-WHERE (COALESCE($1::text, '') = '' OR status = $1);
+WHERE (COALESCE($1::text, '') = '' OR status = $1) AND hidden_at IS NULL;
+
+-- name: ListPublicContestsByCreator :many
+SELECT * FROM contests
+WHERE created_by_user_id = $1 AND status <> 'draft' AND hidden_at IS NULL
+ORDER BY created_at DESC
+LIMIT $2;
+
+-- name: ListContestsByCreator :many
+SELECT * FROM contests
+WHERE created_by_user_id = $1
+ORDER BY created_at DESC;
 
 -- name: UpdateContest :one
 UPDATE contests
@@ -60,15 +198,173 @@ SET status = $2, updated_at = NOW()
 WHERE id = $1
 RETURNING *;
 
+-- name: UpdateContestVotingMode :one
+UPDATE contests
+SET voting_mode = $2, max_choices = $3, min_ballots = $4, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: UpdateContestJuryWeight :one
+UPDATE contests
+SET jury_weight = $2, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
 -- name: DeleteContest :exec
 DELETE FROM contests
 WHERE id = $1;
 
+-- name: SetContestHidden :exec
+UPDATE contests
+SET hidden_at = $2
+WHERE id = $1;
+
+-- Contest Members
+
+-- name: CreateContestMember :one
+INSERT INTO contest_members (contest_id, user_id, role, status, invited_by_user_id, accepted_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetContestMember :one
+SELECT * FROM contest_members
+WHERE contest_id = $1 AND user_id = $2;
+
+-- name: ListContestMembers :many
+SELECT
+    cm.contest_id,
+    cm.user_id,
+    cm.role,
+    cm.status,
+    cm.invited_by_user_id,
+    cm.created_at,
+    cm.accepted_at,
+    u.name AS user_name
+FROM contest_members cm
+JOIN users u ON u.user_id = cm.user_id
+WHERE cm.contest_id = $1
+ORDER BY cm.created_at ASC;
+
+-- name: UpdateContestMemberRole :one
+UPDATE contest_members
+SET role = $3
+WHERE contest_id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: AcceptContestMemberInvite :one
+UPDATE contest_members
+SET status = 'active', accepted_at = NOW()
+WHERE contest_id = $1 AND user_id = $2 AND status = 'invited'
+RETURNING *;
+
+-- name: DeleteContestMember :exec
+DELETE FROM contest_members
+WHERE contest_id = $1 AND user_id = $2;
+
+-- name: DemoteContestOwner :exec
+-- Передача владения: прежний владелец становится организатором до повышения нового -
+-- частичный уникальный индекс idx_contest_members_owner не допускает двух владельцев
+UPDATE contest_members
+SET role = 'organizer'
+WHERE contest_id = $1 AND user_id = $2 AND role = 'owner';
+
+-- name: PromoteContestOwner :execrows
+UPDATE contest_members
+SET role = 'owner'
+WHERE contest_id = $1 AND user_id = $2 AND status = 'active';
+
+-- name: UpdateContestOwner :exec
+UPDATE contests
+SET created_by_user_id = $2, updated_at = NOW()
+WHERE id = $1;
+
+-- Contest Voting Policies
+
+-- name: GetContestVotingPolicy :one
+SELECT * FROM contest_voting_policies
+WHERE contest_id = $1;
+
+-- name: UpsertContestVotingPolicy :one
+INSERT INTO contest_voting_policies (contest_id, min_account_age_hours, required_providers, exclude_own_participants, invite_only)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (contest_id) DO UPDATE
+SET min_account_age_hours = EXCLUDED.min_account_age_hours,
+    required_providers = EXCLUDED.required_providers,
+    exclude_own_participants = EXCLUDED.exclude_own_participants,
+    invite_only = EXCLUDED.invite_only,
+    updated_at = NOW()
+RETURNING *;
+
+-- name: ListContestInvitedVoters :many
+SELECT
+    iv.user_id,
+    u.name AS user_name,
+    iv.added_by_user_id,
+    iv.created_at
+FROM contest_invited_voters iv
+JOIN users u ON u.user_id = iv.user_id
+WHERE iv.contest_id = $1
+ORDER BY iv.created_at ASC;
+
+-- name: AddContestInvitedVoter :exec
+INSERT INTO contest_invited_voters (contest_id, user_id, added_by_user_id)
+VALUES ($1, $2, $3)
+ON CONFLICT (contest_id, user_id) DO NOTHING;
+
+-- name: DeleteContestInvitedVoter :exec
+DELETE FROM contest_invited_voters
+WHERE contest_id = $1 AND user_id = $2;
+
+-- name: IsContestInvitedVoter :one
+SELECT EXISTS (
+    SELECT 1 FROM contest_invited_voters
+    WHERE contest_id = $1 AND user_id = $2
+);
+
+-- Contest Entry Rules
+
+-- name: GetContestEntryRules :one
+SELECT * FROM contest_entry_rules
+WHERE contest_id = $1;
+
+-- name: UpsertContestEntryRules :one
+INSERT INTO contest_entry_rules (
+    contest_id, max_entries_per_user, max_entries, max_photos_per_entry, max_photo_size_mb,
+    video_allowed, max_video_duration_sec, max_video_size_mb, required_fields, entry_deadline, require_approval,
+    allowed_species, min_age_months, max_age_months
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+ON CONFLICT (contest_id) DO UPDATE
+SET max_entries_per_user = EXCLUDED.max_entries_per_user,
+    max_entries = EXCLUDED.max_entries,
+    max_photos_per_entry = EXCLUDED.max_photos_per_entry,
+    max_photo_size_mb = EXCLUDED.max_photo_size_mb,
+    video_allowed = EXCLUDED.video_allowed,
+    max_video_duration_sec = EXCLUDED.max_video_duration_sec,
+    max_video_size_mb = EXCLUDED.max_video_size_mb,
+    required_fields = EXCLUDED.required_fields,
+    entry_deadline = EXCLUDED.entry_deadline,
+    require_approval = EXCLUDED.require_approval,
+    allowed_species = EXCLUDED.allowed_species,
+    min_age_months = EXCLUDED.min_age_months,
+    max_age_months = EXCLUDED.max_age_months,
+    updated_at = NOW()
+RETURNING *;
+
 -- Contest Participants
 
 -- name: CreateParticipant :one
-INSERT INTO contest_participants (id, contest_id, user_id, pet_name, pet_description)
-VALUES ($1, $2, $3, $4, $5)
+-- Лимиты заявок (0 - без лимита) проверяются в самом запросе: сверх лимита строка не вставляется
+INSERT INTO contest_participants (id, contest_id, user_id, pet_name, pet_description, moderation_status, pet_id, species, breed, sex, birth_date)
+SELECT $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+WHERE (sqlc.arg(max_entries)::int = 0 OR (
+        SELECT count(1) FROM contest_participants
+        WHERE contest_id = $2 AND moderation_status <> 'rejected'
+    ) < sqlc.arg(max_entries)::int)
+  AND (sqlc.arg(max_entries_per_user)::int = 0 OR (
+        SELECT count(1) FROM contest_participants
+        WHERE contest_id = $2 AND user_id = $3 AND moderation_status <> 'rejected'
+    ) < sqlc.arg(max_entries_per_user)::int)
 RETURNING *;
 
 -- name: GetParticipantByID :one
@@ -80,7 +376,18 @@ SELECT
     cp.pet_name,
     cp.pet_description,
     cp.created_at,
-    cp.updated_at
+    cp.updated_at,
+    cp.hidden_at,
+    cp.moderation_status,
+    cp.moderation_reason,
+    cp.moderated_at,
+    cp.disqualified_at,
+    cp.disqualification_reason,
+    cp.pet_id,
+    cp.species,
+    cp.breed,
+    cp.sex,
+    cp.birth_date
 FROM contest_participants cp
 LEFT JOIN users u ON u.user_id = cp.user_id
 WHERE cp.id = $1;
@@ -108,15 +415,46 @@ SELECT
     cp.pet_name,
     cp.pet_description,
     cp.created_at,
-    cp.updated_at
+    cp.updated_at,
+    cp.pet_id,
+    cp.species,
+    cp.breed,
+    cp.sex,
+    cp.birth_date
 FROM contest_participants cp
 LEFT JOIN users u ON u.user_id = cp.user_id
-WHERE cp.contest_id = $1
+WHERE cp.contest_id = $1 AND cp.hidden_at IS NULL AND cp.moderation_status = 'approved' AND cp.disqualified_at IS NULL
+ORDER BY cp.created_at ASC;
+
+-- name: ListParticipantsByModerationStatus :many
+SELECT
+    cp.id,
+    cp.contest_id,
+    cp.user_id,
+    COALESCE(u.name, 'Пользователь ' || cp.user_id::text) AS user_name,
+    cp.pet_name,
+    cp.pet_description,
+    cp.created_at,
+    cp.updated_at,
+    cp.moderation_status,
+    cp.moderation_reason,
+    cp.moderated_at
+FROM contest_participants cp
+LEFT JOIN users u ON u.user_id = cp.user_id
+WHERE cp.contest_id = $1 AND cp.moderation_status = $2
 ORDER BY cp.created_at ASC;
 
+-- name: CountParticipantsByContest :one
+SELECT count(1) FROM contest_participants
+WHERE contest_id = $1 AND moderation_status <> 'rejected';
+
+-- name: CountParticipantsByContestAndUser :one
+SELECT count(1) FROM contest_participants
+WHERE contest_id = $1 AND user_id = $2 AND moderation_status <> 'rejected';
+
 -- name: UpdateParticipant :one
 UPDATE contest_participants
-SET pet_name = $2, pet_description = $3, updated_at = NOW()
+SET pet_name = $2, pet_description = $3, species = $4, breed = $5, sex = $6, birth_date = $7, updated_at = NOW()
 WHERE id = $1
 RETURNING *;
 
@@ -124,6 +462,31 @@ RETURNING *;
 DELETE FROM contest_participants
 WHERE id = $1;
 
+-- name: SetParticipantHidden :exec
+UPDATE contest_participants
+SET hidden_at = $2
+WHERE id = $1;
+
+-- name: SetParticipantModeration :exec
+UPDATE contest_participants
+SET moderation_status = $2,
+    moderation_reason = $3,
+    moderated_by_user_id = $4,
+    moderated_at = CASE WHEN $2 = 'pending' THEN NULL ELSE NOW() END
+WHERE id = $1;
+
+-- name: DisqualifyParticipant :execrows
+UPDATE contest_participants
+SET disqualified_at = NOW(),
+    disqualified_by_user_id = $2,
+    disqualification_reason = $3
+WHERE id = $1 AND disqualified_at IS NULL;
+
+-- name: ListParticipantsByUser :many
+SELECT * FROM contest_participants
+WHERE user_id = $1
+ORDER BY created_at DESC;
+
 -- Contest Participant Photos
 
 -- name: AddParticipantPhoto :one
@@ -131,6 +494,10 @@ INSERT INTO contest_participant_photos (id, participant_id, url, thumb_url, posi
 VALUES ($1, $2, $3, $4, $5)
 RETURNING *;
 
+-- name: CountPhotosByParticipant :one
+SELECT count(1) FROM contest_participant_photos
+WHERE participant_id = $1;
+
 -- name: GetPhotosByParticipantID :many
 SELECT * FROM contest_participant_photos
 WHERE participant_id = $1
@@ -167,31 +534,72 @@ WHERE participant_id = $1;
 DELETE FROM contest_participant_videos
 WHERE participant_id = $1;
 
+-- Contest Categories
+
+-- name: CreateContestCategory :one
+INSERT INTO contest_categories (id, contest_id, name, description, position)
+VALUES ($1, $2, $3, $4, (SELECT COALESCE(MAX(position), -1) + 1 FROM contest_categories WHERE contest_id = $2))
+RETURNING *;
+
+-- name: GetContestCategory :one
+SELECT * FROM contest_categories
+WHERE contest_id = $1 AND id = $2;
+
+-- name: ListContestCategories :many
+SELECT * FROM contest_categories
+WHERE contest_id = $1
+ORDER BY position ASC, created_at ASC;
+
+-- name: UpdateContestCategory :one
+UPDATE contest_categories
+SET name = $3, description = $4, updated_at = NOW()
+WHERE contest_id = $1 AND id = $2
+RETURNING *;
+
+-- name: DeleteContestCategory :exec
+DELETE FROM contest_categories
+WHERE contest_id = $1 AND id = $2;
+
 -- Contest Votes
 
 -- name: UpsertContestVote :one
-INSERT INTO contest_votes (id, contest_id, participant_id, user_id)
-VALUES ($1, $2, $3, $4)
-ON CONFLICT (contest_id, user_id) DO UPDATE
-SET participant_id = EXCLUDED.participant_id, updated_at = NOW()
+INSERT INTO contest_votes (id, contest_id, participant_id, user_id, ip_hash, user_agent, auth_provider, account_created_at, category_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+ON CONFLICT (contest_id, category_id, user_id) DO UPDATE
+SET participant_id = EXCLUDED.participant_id,
+    ip_hash = EXCLUDED.ip_hash,
+    user_agent = EXCLUDED.user_agent,
+    auth_provider = EXCLUDED.auth_provider,
+    account_created_at = EXCLUDED.account_created_at,
+    voided_at = NULL,
+    voided_by_user_id = NULL,
+    void_reason = '',
+    recastable = FALSE,
+    updated_at = NOW()
+WHERE contest_votes.voided_at IS NULL OR contest_votes.recastable
 RETURNING *;
 
 -- name: GetContestVoteByUser :one
 SELECT * FROM contest_votes
-WHERE contest_id = $1 AND user_id = $2;
+WHERE contest_id = $1 AND user_id = $2 AND category_id IS NOT DISTINCT FROM sqlc.narg(category_id);
+
+-- name: ListContestVotesByUser :many
+SELECT * FROM contest_votes
+WHERE user_id = $1
+ORDER BY created_at DESC;
 
 -- name: DeleteContestVoteByUser :one
 DELETE FROM contest_votes
-WHERE contest_id = $1 AND user_id = $2
+WHERE contest_id = $1 AND user_id = $2 AND category_id IS NOT DISTINCT FROM sqlc.narg(category_id) AND voided_at IS NULL
 RETURNING participant_id;
 
 -- name: CountVotesByContest :one
 SELECT count(1) FROM contest_votes
-WHERE contest_id = $1;
+WHERE contest_id = $1 AND voided_at IS NULL;
 
 -- name: CountVotesByParticipant :one
 SELECT count(1) FROM contest_votes
-WHERE participant_id = $1;
+WHERE participant_id = $1 AND category_id IS NOT DISTINCT FROM sqlc.narg(category_id) AND voided_at IS NULL;
 
 -- name: ListVotersByParticipant :many
 SELECT
@@ -200,14 +608,261 @@ SELECT
     cv.created_at
 FROM contest_votes cv
 LEFT JOIN users u ON u.user_id = cv.user_id
-WHERE cv.contest_id = $1 AND cv.participant_id = $2
+WHERE cv.contest_id = $1 AND cv.participant_id = $2 AND cv.category_id IS NOT DISTINCT FROM sqlc.narg(category_id) AND cv.voided_at IS NULL
 ORDER BY cv.created_at ASC;
 
 -- name: CountVotesByContests :many
 SELECT contest_id, count(1) as vote_count FROM contest_votes
-WHERE contest_id = ANY($1::uuid[])
+WHERE contest_id = ANY($1::uuid[]) AND voided_at IS NULL
 GROUP BY contest_id;
 
+-- name: ListContestIDsWithVotesSince :many
+SELECT DISTINCT contest_id FROM contest_votes
+WHERE updated_at > $1 AND voided_at IS NULL;
+
+-- name: ListContestVotesForFraudScoring :many
+SELECT id, participant_id, user_id, ip_hash, user_agent, account_created_at, fraud_score, fraud_reasons, updated_at
+FROM contest_votes
+WHERE contest_id = $1 AND voided_at IS NULL
+ORDER BY updated_at ASC;
+
+-- name: UpdateContestVoteFraudScore :exec
+UPDATE contest_votes
+SET fraud_score = sqlc.arg(fraud_score),
+    fraud_reasons = sqlc.arg(fraud_reasons)::text[],
+    flagged_at = CASE WHEN sqlc.arg(flagged)::bool THEN COALESCE(flagged_at, NOW()) ELSE NULL END
+WHERE id = sqlc.arg(id);
+
+-- name: ListFlaggedContestVotes :many
+SELECT
+    cv.id,
+    cv.participant_id,
+    cv.user_id,
+    COALESCE(u.name, 'Пользователь ' || cv.user_id::text) AS user_name,
+    cv.ip_hash,
+    cv.user_agent,
+    cv.auth_provider,
+    cv.account_created_at,
+    cv.fraud_score,
+    cv.fraud_reasons,
+    cv.flagged_at,
+    cv.updated_at,
+    cv.voided_at,
+    cv.voided_by_user_id,
+    cv.void_reason
+FROM contest_votes cv
+LEFT JOIN users u ON u.user_id = cv.user_id
+WHERE cv.contest_id = $1 AND cv.flagged_at IS NOT NULL
+ORDER BY cv.voided_at IS NOT NULL, cv.fraud_score DESC, cv.updated_at ASC;
+
+-- name: VoidContestVotes :many
+UPDATE contest_votes
+SET voided_at = NOW(), voided_by_user_id = sqlc.arg(voided_by_user_id), void_reason = sqlc.arg(void_reason)
+WHERE contest_id = sqlc.arg(contest_id) AND id = ANY(sqlc.arg(ids)::uuid[]) AND voided_at IS NULL
+RETURNING participant_id, user_id, category_id;
+
+-- name: VoidParticipantVotes :many
+-- Аннулирует голоса и бюллетени, в которых выбран участник; автор может проголосовать заново
+UPDATE contest_votes cv
+SET voided_at = NOW(), voided_by_user_id = sqlc.arg(voided_by_user_id), void_reason = sqlc.arg(void_reason), recastable = TRUE
+WHERE cv.voided_at IS NULL AND (
+    cv.participant_id = sqlc.arg(participant_id)
+    OR EXISTS (SELECT 1 FROM contest_vote_choices c WHERE c.vote_id = cv.id AND c.participant_id = sqlc.arg(participant_id))
+)
+RETURNING cv.participant_id, cv.user_id, cv.category_id;
+
+-- Contest Vote Choices
+
+-- name: ReplaceContestVoteChoices :exec
+WITH removed AS (
+    DELETE FROM contest_vote_choices
+    WHERE vote_id = sqlc.arg(vote_id) AND participant_id <> ALL(sqlc.arg(participant_ids)::uuid[])
+)
+INSERT INTO contest_vote_choices (vote_id, participant_id, rank, stars)
+SELECT sqlc.arg(vote_id), c.participant_id, c.rank, NULLIF(c.stars, 0)
+FROM unnest(sqlc.arg(participant_ids)::uuid[], sqlc.arg(ranks)::int[], sqlc.arg(stars)::int[]) AS c(participant_id, rank, stars)
+ON CONFLICT (vote_id, participant_id) DO UPDATE
+SET rank = EXCLUDED.rank, stars = EXCLUDED.stars;
+
+-- name: ListContestVoteChoices :many
+SELECT participant_id, rank, stars FROM contest_vote_choices
+WHERE vote_id = $1
+ORDER BY rank, participant_id;
+
+-- name: CountVoteChoicesByParticipant :one
+SELECT count(1) AS choice_count, COALESCE(avg(c.stars), 0)::float8 AS average_stars
+FROM contest_vote_choices c
+JOIN contest_votes cv ON cv.id = c.vote_id
+WHERE c.participant_id = $1 AND cv.category_id IS NOT DISTINCT FROM sqlc.narg(category_id) AND cv.voided_at IS NULL;
+
+-- name: ListContestBallotChoices :many
+SELECT
+    cv.id AS vote_id,
+    COALESCE(c.participant_id, cv.participant_id)::uuid AS participant_id,
+    COALESCE(c.rank, 0)::int AS rank,
+    c.stars
+FROM contest_votes cv
+LEFT JOIN contest_vote_choices c ON c.vote_id = cv.id
+WHERE cv.contest_id = $1 AND cv.category_id IS NOT DISTINCT FROM sqlc.narg(category_id) AND cv.voided_at IS NULL
+ORDER BY cv.id, rank;
+
+-- Contest Jury
+
+-- name: CreateJuryCriterion :one
+INSERT INTO contest_jury_criteria (id, contest_id, name, description, weight, max_score, position)
+VALUES ($1, $2, $3, $4, $5, $6, (SELECT COALESCE(MAX(position), -1) + 1 FROM contest_jury_criteria WHERE contest_id = $2))
+RETURNING *;
+
+-- name: ListJuryCriteria :many
+SELECT * FROM contest_jury_criteria
+WHERE contest_id = $1
+ORDER BY position ASC, created_at ASC;
+
+-- name: DeleteJuryCriterion :exec
+DELETE FROM contest_jury_criteria
+WHERE contest_id = $1 AND id = $2;
+
+-- name: UpsertJuryScores :exec
+INSERT INTO contest_jury_scores (criterion_id, participant_id, juror_user_id, contest_id, score, comment)
+SELECT s.criterion_id, sqlc.arg(participant_id), sqlc.arg(juror_user_id), sqlc.arg(contest_id), s.score, s.comment
+FROM unnest(sqlc.arg(criterion_ids)::uuid[], sqlc.arg(scores)::int[], sqlc.arg(comments)::text[]) AS s(criterion_id, score, comment)
+ON CONFLICT (criterion_id, participant_id, juror_user_id) DO UPDATE
+SET score = EXCLUDED.score, comment = EXCLUDED.comment, updated_at = NOW();
+
+-- name: ListJuryScores :many
+SELECT
+    js.criterion_id,
+    js.participant_id,
+    js.juror_user_id,
+    COALESCE(u.name, 'Пользователь ' || js.juror_user_id::text) AS juror_name,
+    js.score,
+    js.comment,
+    js.updated_at
+FROM contest_jury_scores js
+LEFT JOIN users u ON u.user_id = js.juror_user_id
+WHERE js.contest_id = $1
+ORDER BY js.participant_id, js.juror_user_id, js.criterion_id;
+
+-- Contest Brackets
+
+-- name: GetContestBracket :one
+SELECT * FROM contest_brackets
+WHERE contest_id = $1;
+
+-- name: UpsertContestBracket :one
+INSERT INTO contest_brackets (contest_id, seeding, round_duration_minutes)
+VALUES ($1, $2, $3)
+ON CONFLICT (contest_id) DO UPDATE
+SET seeding = EXCLUDED.seeding, round_duration_minutes = EXCLUDED.round_duration_minutes, updated_at = NOW()
+RETURNING *;
+
+-- name: StartContestBracket :execrows
+UPDATE contest_brackets
+SET status = 'running', current_round = 1, updated_at = NOW()
+WHERE contest_id = $1 AND status = 'pending';
+
+-- name: UpdateContestBracketState :exec
+UPDATE contest_brackets
+SET status = $2, current_round = $3, champion_participant_id = $4, updated_at = NOW()
+WHERE contest_id = $1;
+
+-- name: CreateBracketRound :exec
+WITH new_round AS (
+    INSERT INTO contest_bracket_rounds (contest_id, round, status, starts_at, ends_at)
+    VALUES (sqlc.arg(contest_id), sqlc.arg(round), sqlc.arg(status), sqlc.arg(starts_at), sqlc.arg(ends_at))
+    RETURNING contest_id, round
+)
+INSERT INTO contest_bracket_matchups (id, contest_id, round, position, participant_a_id, participant_b_id, seed_a, seed_b, winner_id, decided_at)
+SELECT m.id, nr.contest_id, nr.round, m.position, m.participant_a_id, m.participant_b_id, m.seed_a, m.seed_b, m.winner_id,
+    CASE WHEN m.winner_id IS NULL THEN NULL ELSE NOW() END
+FROM new_round nr, unnest(
+    sqlc.arg(ids)::uuid[],
+    sqlc.arg(positions)::int[],
+    sqlc.arg(participant_a_ids)::uuid[],
+    sqlc.arg(participant_b_ids)::uuid[],
+    sqlc.arg(seeds_a)::int[],
+    sqlc.arg(seeds_b)::int[],
+    sqlc.arg(winner_ids)::uuid[]
+) AS m(id, position, participant_a_id, participant_b_id, seed_a, seed_b, winner_id);
+
+-- name: ListBracketRounds :many
+SELECT * FROM contest_bracket_rounds
+WHERE contest_id = $1
+ORDER BY round ASC;
+
+-- name: ListDueBracketRounds :many
+SELECT * FROM contest_bracket_rounds
+WHERE (status = 'scheduled' AND starts_at <= sqlc.arg(now)) OR (status = 'active' AND ends_at <= sqlc.arg(now))
+ORDER BY starts_at ASC;
+
+-- name: SetBracketRoundStatus :execrows
+UPDATE contest_bracket_rounds
+SET status = sqlc.arg(status)
+WHERE contest_id = sqlc.arg(contest_id) AND round = sqlc.arg(round) AND status = sqlc.arg(from_status);
+
+-- name: ListBracketMatchups :many
+SELECT
+    m.id,
+    m.round,
+    m.position,
+    m.participant_a_id,
+    m.participant_b_id,
+    m.seed_a,
+    m.seed_b,
+    m.winner_id,
+    m.decided_at,
+    count(v.user_id) FILTER (WHERE v.participant_id = m.participant_a_id) AS votes_a,
+    count(v.user_id) FILTER (WHERE v.participant_id = m.participant_b_id) AS votes_b
+FROM contest_bracket_matchups m
+LEFT JOIN contest_bracket_votes v ON v.matchup_id = m.id
+WHERE m.contest_id = $1
+GROUP BY m.id
+ORDER BY m.round ASC, m.position ASC;
+
+-- name: GetBracketMatchup :one
+SELECT
+    m.id,
+    m.round,
+    m.position,
+    m.participant_a_id,
+    m.participant_b_id,
+    m.seed_a,
+    m.seed_b,
+    m.winner_id,
+    m.decided_at,
+    count(v.user_id) FILTER (WHERE v.participant_id = m.participant_a_id) AS votes_a,
+    count(v.user_id) FILTER (WHERE v.participant_id = m.participant_b_id) AS votes_b
+FROM contest_bracket_matchups m
+LEFT JOIN contest_bracket_votes v ON v.matchup_id = m.id
+WHERE m.contest_id = $1 AND m.id = $2
+GROUP BY m.id;
+
+-- name: DecideBracketMatchup :exec
+UPDATE contest_bracket_matchups
+SET winner_id = $2, decided_at = NOW()
+WHERE id = $1 AND decided_at IS NULL;
+
+-- name: ForfeitBracketMatchups :many
+-- Выбывший участник отдает все нерешенные пары сопернику
+UPDATE contest_bracket_matchups
+SET winner_id = CASE WHEN participant_a_id = sqlc.arg(participant_id) THEN participant_b_id ELSE participant_a_id END,
+    decided_at = NOW()
+WHERE contest_id = sqlc.arg(contest_id) AND decided_at IS NULL
+    AND (participant_a_id = sqlc.arg(participant_id) OR participant_b_id = sqlc.arg(participant_id))
+RETURNING id;
+
+-- name: UpsertBracketVote :exec
+INSERT INTO contest_bracket_votes (matchup_id, user_id, participant_id)
+VALUES ($1, $2, $3)
+ON CONFLICT (matchup_id, user_id) DO UPDATE
+SET participant_id = EXCLUDED.participant_id, updated_at = NOW();
+
+-- name: ListBracketVotesByUser :many
+SELECT v.matchup_id, v.participant_id
+FROM contest_bracket_votes v
+JOIN contest_bracket_matchups m ON m.id = v.matchup_id
+WHERE m.contest_id = $1 AND v.user_id = $2;
+
 -- Contest Comments
 
 -- name: CreateComment :one
@@ -218,6 +873,16 @@ RETURNING *;
 -- name: GetCommentByID :one
 SELECT * FROM contest_comments WHERE id = $1;
 
+-- name: ListCommentsByUser :many
+SELECT * FROM contest_comments
+WHERE user_id = $1
+ORDER BY created_at DESC;
+
+-- name: ListParticipantCommenters :many
+-- Авторы видимых комментариев к заявке - получатели уведомлений об ответах
+SELECT DISTINCT user_id FROM contest_comments
+WHERE participant_id = $1 AND hidden_at IS NULL;
+
 -- name: ListCommentsByParticipant :many
 SELECT
     cc.id,
@@ -229,13 +894,13 @@ SELECT
     COALESCE(u.name, 'Пользователь ' || cc.user_id::text) AS user_name
 FROM contest_comments cc
 LEFT JOIN users u ON u.user_id = cc.user_id
-WHERE cc.participant_id = $1
+WHERE cc.participant_id = $1 AND cc.hidden_at IS NULL
 ORDER BY cc.created_at ASC
 LIMIT $2 OFFSET $3;
 
 -- name: CountCommentsByParticipant :one
 SELECT count(1) FROM contest_comments
-WHERE participant_id = $1;
+WHERE participant_id = $1 AND hidden_at IS NULL;
 
 -- name: UpdateComment :one
 UPDATE contest_comments
@@ -247,6 +912,11 @@ RETURNING *;
 DELETE FROM contest_comments
 WHERE id = $1;
 
+-- name: SetCommentHidden :exec
+UPDATE contest_comments
+SET hidden_at = $2
+WHERE id = $1;
+
 -- name: DeleteCommentsByParticipant :exec
 DELETE FROM contest_comments
 WHERE participant_id = $1;
@@ -274,13 +944,18 @@ SELECT
     COALESCE(u.name, 'Пользователь ' || ccm.user_id::text) as user_name
 FROM contest_chat_messages ccm
 LEFT JOIN users u ON u.user_id = ccm.user_id
-WHERE ccm.contest_id = $1
+WHERE ccm.contest_id = $1 AND ccm.hidden_at IS NULL
 ORDER BY ccm.created_at ASC
 LIMIT $2 OFFSET $3;
 
 -- name: CountChatMessages :one
 SELECT count(1) FROM contest_chat_messages
-WHERE contest_id = $1;
+WHERE contest_id = $1 AND hidden_at IS NULL;
+
+-- name: ListChatMessagesByUser :many
+SELECT * FROM contest_chat_messages
+WHERE user_id = $1 AND NOT is_system
+ORDER BY created_at DESC;
 
 -- name: UpdateChatMessage :one
 UPDATE contest_chat_messages
@@ -293,6 +968,21 @@ DELETE FROM contest_chat_messages
 WHERE id = $1 AND user_id = $2 AND is_system = FALSE
 RETURNING contest_id;
 
+-- name: GetChatMessageContestID :one
+SELECT contest_id FROM contest_chat_messages
+WHERE id = $1;
+
+-- name: DeleteChatMessageByID :one
+DELETE FROM contest_chat_messages
+WHERE id = $1
+RETURNING contest_id;
+
+-- name: SetChatMessageHidden :one
+UPDATE contest_chat_messages
+SET hidden_at = $2
+WHERE id = $1
+RETURNING contest_id;
+
 -- Photo Likes
 
 -- name: UpsertPhotoLike :one
@@ -318,3 +1008,536 @@ WHERE photo_id = $1;
 SELECT id, photo_id, user_id, created_at
 FROM photo_likes
 WHERE photo_id = ANY($1::uuid[]) AND user_id = $2;
+
+-- name: CountPhotoLikesByParticipant :many
+SELECT p.participant_id, count(l.id) AS likes
+FROM contest_participant_photos p
+JOIN contest_participants cp ON cp.id = p.participant_id
+LEFT JOIN photo_likes l ON l.photo_id = p.id
+WHERE cp.contest_id = $1
+GROUP BY p.participant_id;
+
+-- Pets
+
+-- name: CreatePet :one
+INSERT INTO pets (id, owner_user_id, name, species, breed, sex, birth_date, bio)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: GetPetByID :one
+SELECT * FROM pets WHERE id = $1;
+
+-- name: ListPetsByOwner :many
+SELECT * FROM pets
+WHERE owner_user_id = $1
+ORDER BY created_at ASC;
+
+-- name: UpdatePet :one
+UPDATE pets
+SET name = $2, species = $3, breed = $4, sex = $5, birth_date = $6, bio = $7, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: DeletePet :exec
+DELETE FROM pets WHERE id = $1;
+
+-- name: ListPetEntries :many
+SELECT
+    cp.id,
+    cp.contest_id,
+    c.title AS contest_title,
+    c.status AS contest_status,
+    cp.pet_name,
+    cp.created_at
+FROM contest_participants cp
+JOIN contests c ON c.id = cp.contest_id
+WHERE cp.pet_id = $1 AND cp.hidden_at IS NULL AND cp.moderation_status = 'approved' AND cp.disqualified_at IS NULL AND c.hidden_at IS NULL
+ORDER BY cp.created_at DESC;
+
+-- Pet Photos
+
+-- name: AddPetPhoto :one
+INSERT INTO pet_photos (id, pet_id, url, thumb_url, position)
+VALUES ($1, $2, $3, $4, (SELECT COALESCE(MAX(position), -1) + 1 FROM pet_photos WHERE pet_id = $2))
+RETURNING *;
+
+-- name: ListPetPhotos :many
+SELECT * FROM pet_photos
+WHERE pet_id = $1
+ORDER BY position ASC, created_at ASC;
+
+-- name: CountPetPhotos :one
+SELECT count(1) FROM pet_photos
+WHERE pet_id = $1;
+
+-- name: DeletePetPhoto :execrows
+DELETE FROM pet_photos
+WHERE pet_id = $1 AND id = $2;
+
+-- Species & Breeds
+
+-- name: ListSpecies :many
+SELECT * FROM species
+ORDER BY position ASC, code ASC;
+
+-- name: ListBreedsBySpecies :many
+SELECT * FROM breeds
+WHERE species_code = $1
+ORDER BY name_ru ASC;
+
+-- name: GetBreedByCode :one
+SELECT * FROM breeds WHERE code = $1;
+
+-- Account deletion
+
+-- name: CreateAccountDeletionJob :one
+WITH deleted_user AS (
+    UPDATE users SET deleted_at = COALESCE(deleted_at, NOW())
+    WHERE user_id = $1
+)
+INSERT INTO account_deletion_jobs (user_id)
+VALUES ($1)
+ON CONFLICT (user_id) WHERE status IN ('pending', 'running')
+DO UPDATE SET updated_at = account_deletion_jobs.updated_at
+RETURNING *;
+
+-- name: GetAccountDeletionJob :one
+SELECT * FROM account_deletion_jobs WHERE id = $1;
+
+-- name: ClaimAccountDeletionJob :one
+UPDATE account_deletion_jobs
+SET status = 'running', attempts = attempts + 1, started_at = NOW(), updated_at = NOW()
+WHERE id = (
+    SELECT id FROM account_deletion_jobs
+    WHERE status IN ('pending', 'running') AND (started_at IS NULL OR started_at < $1)
+    ORDER BY created_at ASC
+    LIMIT 1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: FinishAccountDeletionJob :exec
+UPDATE account_deletion_jobs
+SET status = $2,
+    last_error = $3,
+    finished_at = CASE WHEN $2 = 'pending' THEN NULL ELSE NOW() END,
+    updated_at = NOW()
+WHERE id = $1;
+
+-- name: DeleteUserPersonalData :exec
+-- Удаляет персональные данные одним запросом (изменяющие CTE выполняются атомарно):
+-- голоса, лайки, медиа заявок, питомцев, привязки провайдеров и Telegram, роли, уведомления, письма и ссылки входа по email;
+-- профиль обезличивается.
+-- Конкурсы, заявки, комментарии и сообщения остаются за обезличенным пользователем.
+WITH photo_likes_deleted AS (
+    DELETE FROM photo_likes
+    WHERE photo_likes.user_id = $1
+       OR photo_likes.photo_id IN (
+           SELECT p.id FROM contest_participant_photos p
+           JOIN contest_participants cp ON cp.id = p.participant_id
+           WHERE cp.user_id = $1
+       )
+), photos_deleted AS (
+    DELETE FROM contest_participant_photos
+    WHERE participant_id IN (SELECT id FROM contest_participants WHERE contest_participants.user_id = $1)
+), videos_deleted AS (
+    DELETE FROM contest_participant_videos
+    WHERE participant_id IN (SELECT id FROM contest_participants WHERE contest_participants.user_id = $1)
+), votes_deleted AS (
+    DELETE FROM contest_votes WHERE contest_votes.user_id = $1
+), bracket_votes_deleted AS (
+    DELETE FROM contest_bracket_votes WHERE contest_bracket_votes.user_id = $1
+), pets_deleted AS (
+    DELETE FROM pets WHERE owner_user_id = $1
+), providers_deleted AS (
+    DELETE FROM user_auth_providers WHERE user_auth_providers.user_id = $1
+), roles_deleted AS (
+    DELETE FROM user_roles WHERE user_roles.user_id = $1
+), memberships_deleted AS (
+    DELETE FROM contest_members WHERE contest_members.user_id = $1
+), invites_deleted AS (
+    DELETE FROM contest_invited_voters WHERE contest_invited_voters.user_id = $1
+), privacy_deleted AS (
+    DELETE FROM user_privacy_settings WHERE user_privacy_settings.user_id = $1
+), tickets_deleted AS (
+    DELETE FROM ws_tickets WHERE ws_tickets.user_id = $1
+), notifications_deleted AS (
+    DELETE FROM notifications WHERE notifications.user_id = $1
+), notification_preferences_deleted AS (
+    DELETE FROM notification_preferences WHERE notification_preferences.user_id = $1
+), emails_deleted AS (
+    DELETE FROM email_outbox WHERE email_outbox.user_id = $1
+), telegram_tokens_deleted AS (
+    DELETE FROM telegram_link_tokens WHERE telegram_link_tokens.user_id = $1
+), telegram_subscriptions_deleted AS (
+    DELETE FROM telegram_subscriptions
+    WHERE chat_id IN (SELECT chat_id FROM telegram_accounts WHERE telegram_accounts.user_id = $1)
+), telegram_accounts_deleted AS (
+    DELETE FROM telegram_accounts WHERE telegram_accounts.user_id = $1
+), email_login_tokens_deleted AS (
+    DELETE FROM email_login_tokens
+    WHERE email IN (
+        SELECT provider_uid FROM user_auth_providers WHERE user_auth_providers.user_id = $1 AND provider = 'email'
+        UNION
+        SELECT notification_preferences.email FROM notification_preferences WHERE notification_preferences.user_id = $1
+    )
+)
+UPDATE users
+SET name = $2, avatar_url = NULL, avatar_thumb_url = NULL, bio = '', deleted_at = COALESCE(deleted_at, NOW())
+WHERE users.user_id = $1;
+
+-- Notifications
+
+-- name: CreateNotifications :many
+-- Рассылка одного события нескольким получателям; удаленные пользователи пропускаются
+WITH inserted AS (
+    INSERT INTO notifications (user_id, type, contest_id, participant_id, comment_id, actor_user_id, title, text)
+    SELECT users.user_id, sqlc.arg(type), sqlc.arg(contest_id), sqlc.arg(participant_id), sqlc.arg(comment_id), sqlc.arg(actor_user_id), sqlc.arg(title), sqlc.arg(text)
+    FROM users
+    WHERE users.user_id = ANY(sqlc.arg(user_ids)::bigint[]) AND users.deleted_at IS NULL
+    RETURNING *
+)
+SELECT inserted.*, COALESCE(actor.name, '')::text AS actor_name
+FROM inserted
+LEFT JOIN users actor ON actor.user_id = inserted.actor_user_id;
+
+-- name: ListNotifications :many
+SELECT n.*, COALESCE(actor.name, '')::text AS actor_name
+FROM notifications n
+LEFT JOIN users actor ON actor.user_id = n.actor_user_id
+WHERE n.user_id = sqlc.arg(user_id) AND (NOT sqlc.arg(unread_only)::bool OR n.read_at IS NULL)
+ORDER BY n.created_at DESC
+LIMIT sqlc.arg(limit_count) OFFSET sqlc.arg(offset_count);
+
+-- name: CountNotifications :one
+SELECT COUNT(*) AS total, COUNT(*) FILTER (WHERE read_at IS NULL) AS unread
+FROM notifications
+WHERE user_id = $1;
+
+-- name: MarkNotificationRead :execrows
+UPDATE notifications
+SET read_at = COALESCE(read_at, NOW())
+WHERE id = $1 AND user_id = $2;
+
+-- name: MarkAllNotificationsRead :execrows
+UPDATE notifications
+SET read_at = NOW()
+WHERE user_id = $1 AND read_at IS NULL;
+
+-- Email Notifications
+
+-- name: GetNotificationPreferences :one
+SELECT * FROM notification_preferences
+WHERE user_id = $1;
+
+-- name: UpsertNotificationPreferences :one
+INSERT INTO notification_preferences (user_id, email_voting_started, email_results_published)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id) DO UPDATE
+SET email_voting_started = EXCLUDED.email_voting_started,
+    email_results_published = EXCLUDED.email_results_published,
+    updated_at = NOW()
+RETURNING *;
+
+-- name: SetNotificationEmailIfEmpty :exec
+-- Запоминает адрес из провайдера входа, если адрес еще неизвестен
+INSERT INTO notification_preferences (user_id, email)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE
+SET email = EXCLUDED.email, updated_at = NOW()
+WHERE notification_preferences.email IS NULL;
+
+-- name: ListEmailRecipients :many
+-- Получатели письма о событии: известен адрес и письма этого типа не отключены
+SELECT u.user_id, u.locale, np.email::text AS email
+FROM users u
+JOIN notification_preferences np ON np.user_id = u.user_id
+WHERE u.user_id = ANY(sqlc.arg(user_ids)::bigint[])
+  AND u.deleted_at IS NULL
+  AND np.email IS NOT NULL AND np.email <> ''
+  AND CASE sqlc.arg(notification_type)::text
+      WHEN 'voting_started' THEN np.email_voting_started
+      WHEN 'results_published' THEN np.email_results_published
+      ELSE FALSE
+  END
+ORDER BY u.user_id;
+
+-- name: EnqueueEmails :execrows
+-- Пакетная постановка писем в очередь; user_id = 0 - письмо без привязки к пользователю
+INSERT INTO email_outbox (user_id, to_email, subject, text_body, html_body, unsubscribe_url)
+SELECT NULLIF(e.user_id, 0), e.to_email, e.subject, e.text_body, e.html_body, e.unsubscribe_url
+FROM unnest(
+    sqlc.arg(user_ids)::bigint[],
+    sqlc.arg(to_emails)::text[],
+    sqlc.arg(subjects)::text[],
+    sqlc.arg(text_bodies)::text[],
+    sqlc.arg(html_bodies)::text[],
+    sqlc.arg(unsubscribe_urls)::text[]
+) AS e(user_id, to_email, subject, text_body, html_body, unsubscribe_url);
+
+-- name: ClaimEmailOutbox :many
+-- Забирает пачку писем к отправке. next_attempt_at сдвигается на lease_until: если процесс упадет
+-- во время отправки, письмо снова станет доступно после истечения аренды.
+UPDATE email_outbox
+SET attempts = attempts + 1, next_attempt_at = sqlc.arg(lease_until)
+WHERE id IN (
+    SELECT id FROM email_outbox
+    WHERE status = 'pending' AND next_attempt_at <= sqlc.arg(now)
+    ORDER BY next_attempt_at ASC
+    LIMIT sqlc.arg(limit_count)
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: MarkEmailSent :exec
+UPDATE email_outbox
+SET status = 'sent', last_error = '', sent_at = NOW()
+WHERE id = $1;
+
+-- name: FailEmailAttempt :exec
+UPDATE email_outbox
+SET status = $2, last_error = $3, next_attempt_at = $4
+WHERE id = $1;
+
+-- Telegram
+
+-- name: CreateTelegramLinkToken :exec
+-- Новая ссылка привязки заменяет прежние ссылки пользователя; заодно удаляются просроченные
+WITH old_deleted AS (
+    DELETE FROM telegram_link_tokens
+    WHERE telegram_link_tokens.user_id = $2 OR telegram_link_tokens.expires_at <= NOW()
+)
+INSERT INTO telegram_link_tokens (token_hash, user_id, expires_at)
+VALUES ($1, $2, $3);
+
+-- name: ConsumeTelegramLinkToken :one
+DELETE FROM telegram_link_tokens
+WHERE token_hash = $1 AND expires_at > NOW()
+RETURNING user_id;
+
+-- name: UnlinkTelegramChat :exec
+-- Отвязывает чат от другого аккаунта перед привязкой к user_id
+DELETE FROM telegram_accounts
+WHERE chat_id = $1 AND user_id <> $2;
+
+-- name: LinkTelegramAccount :one
+INSERT INTO telegram_accounts (user_id, chat_id, username)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id) DO UPDATE
+SET chat_id = EXCLUDED.chat_id, username = EXCLUDED.username, linked_at = NOW()
+RETURNING *;
+
+-- name: GetTelegramAccount :one
+SELECT * FROM telegram_accounts
+WHERE user_id = $1;
+
+-- name: GetTelegramAccountByChat :one
+SELECT * FROM telegram_accounts
+WHERE chat_id = $1;
+
+-- name: DeleteTelegramAccount :execrows
+DELETE FROM telegram_accounts
+WHERE user_id = $1;
+
+-- name: DeleteTelegramChat :exec
+-- Забывает чат, который больше недоступен боту: привязку и подписки
+WITH subscriptions_deleted AS (
+    DELETE FROM telegram_subscriptions WHERE telegram_subscriptions.chat_id = $1
+)
+DELETE FROM telegram_accounts
+WHERE telegram_accounts.chat_id = $1;
+
+-- name: SubscribeTelegramChat :exec
+INSERT INTO telegram_subscriptions (chat_id, contest_id)
+VALUES ($1, $2)
+ON CONFLICT (chat_id, contest_id) DO NOTHING;
+
+-- name: UnsubscribeTelegramChat :execrows
+DELETE FROM telegram_subscriptions
+WHERE chat_id = $1 AND contest_id = $2;
+
+-- name: ListTelegramSubscriptions :many
+SELECT c.id, c.title, c.status
+FROM telegram_subscriptions s
+JOIN contests c ON c.id = s.contest_id
+WHERE s.chat_id = $1 AND c.hidden_at IS NULL
+ORDER BY s.created_at ASC;
+
+-- name: ListTelegramContestChats :many
+-- Чаты, которым отправляются новости конкурса: подписчики и привязанные аккаунты участников
+SELECT s.chat_id FROM telegram_subscriptions s
+WHERE s.contest_id = $1
+UNION
+SELECT a.chat_id FROM telegram_accounts a
+JOIN contest_participants cp ON cp.user_id = a.user_id
+WHERE cp.contest_id = $1 AND cp.moderation_status <> 'rejected';
+
+-- name: EnqueueTelegramMessages :execrows
+-- Пакетная постановка сообщений в очередь
+INSERT INTO telegram_outbox (chat_id, text)
+SELECT m.chat_id, m.text
+FROM unnest(
+    sqlc.arg(chat_ids)::text[],
+    sqlc.arg(texts)::text[]
+) AS m(chat_id, text);
+
+-- name: ClaimTelegramOutbox :many
+-- Забирает пачку сообщений к отправке, аренда устроена так же, как в ClaimEmailOutbox
+UPDATE telegram_outbox
+SET attempts = attempts + 1, next_attempt_at = sqlc.arg(lease_until)
+WHERE id IN (
+    SELECT id FROM telegram_outbox
+    WHERE status = 'pending' AND next_attempt_at <= sqlc.arg(now)
+    ORDER BY next_attempt_at ASC
+    LIMIT sqlc.arg(limit_count)
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: MarkTelegramMessageSent :exec
+UPDATE telegram_outbox
+SET status = 'sent', last_error = '', sent_at = NOW()
+WHERE id = $1;
+
+-- name: FailTelegramMessageAttempt :exec
+UPDATE telegram_outbox
+SET status = $2, last_error = $3, next_attempt_at = $4
+WHERE id = $1;
+
+-- name: LockContest :exec
+-- Блокирует строку конкурса до конца транзакции, чтобы проверка и вставка шли по очереди
+SELECT 1 FROM contests WHERE id = $1 FOR UPDATE;
+
+-- name: CreateTelegramAnnouncement :one
+-- Сохраняет анонс и ставит его в очередь отправки одним запросом.
+-- Если предыдущий анонс конкурса моложе cooldown_seconds, ничего не вставляет и не возвращает строк.
+WITH queued AS (
+    INSERT INTO telegram_outbox (chat_id, text)
+    SELECT sqlc.arg(chat_id), sqlc.arg(text)
+    WHERE NOT EXISTS (
+        SELECT 1 FROM telegram_announcements
+        WHERE contest_id = sqlc.arg(contest_id)
+          AND EXTRACT(EPOCH FROM NOW() - created_at)::float8 < sqlc.arg(cooldown_seconds)::float8
+    )
+    RETURNING id, status
+), inserted AS (
+    INSERT INTO telegram_announcements (contest_id, outbox_id, chat_id, text, posted_by_user_id)
+    SELECT sqlc.arg(contest_id), queued.id, sqlc.arg(chat_id), sqlc.arg(text), sqlc.arg(posted_by_user_id)
+    FROM queued
+    RETURNING *
+)
+SELECT inserted.*, queued.status AS status
+FROM inserted
+JOIN queued ON queued.id = inserted.outbox_id;
+
+-- name: ListTelegramAnnouncements :many
+-- Анонсы конкурса со статусом доставки, новые первыми
+SELECT a.*, COALESCE(o.status, '')::text AS status, COALESCE(o.last_error, '')::text AS last_error, o.sent_at
+FROM telegram_announcements a
+LEFT JOIN telegram_outbox o ON o.id = a.outbox_id
+WHERE a.contest_id = $1
+ORDER BY a.created_at DESC
+LIMIT $2;
+
+-- Webhooks
+
+-- name: CreateWebhookEndpoint :one
+INSERT INTO webhook_endpoints (contest_id, url, secret, event_types, created_by_user_id)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetWebhookEndpoint :one
+SELECT * FROM webhook_endpoints
+WHERE id = $1;
+
+-- name: ListWebhookEndpoints :many
+SELECT * FROM webhook_endpoints
+WHERE contest_id = $1
+ORDER BY created_at ASC;
+
+-- name: UpdateWebhookEndpoint :one
+UPDATE webhook_endpoints
+SET url = $2, event_types = $3, active = $4, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteWebhookEndpoint :execrows
+DELETE FROM webhook_endpoints
+WHERE id = $1;
+
+-- name: EnqueueWebhookEvent :execrows
+-- Сохраняет событие и ставит доставки на все включенные webhooks конкурса, подписанные на его тип.
+-- Если подходящих webhooks нет, событие не сохраняется.
+WITH targets AS (
+    SELECT e.id FROM webhook_endpoints e
+    WHERE e.contest_id = sqlc.arg(contest_id) AND e.active
+        AND (cardinality(e.event_types) = 0 OR sqlc.arg(type)::text = ANY(e.event_types))
+), event AS (
+    INSERT INTO webhook_events (contest_id, type, data)
+    SELECT sqlc.arg(contest_id), sqlc.arg(type), sqlc.arg(data)
+    WHERE EXISTS (SELECT 1 FROM targets)
+    RETURNING id
+)
+INSERT INTO webhook_deliveries (endpoint_id, event_id)
+SELECT targets.id, event.id
+FROM targets CROSS JOIN event;
+
+-- name: ClaimWebhookDeliveries :many
+-- Забирает пачку доставок вместе с адресом, ключом и событием; аренда устроена так же, как в ClaimEmailOutbox.
+-- Доставки выключенного webhook ждут, пока его не включат снова.
+WITH claimed AS (
+    UPDATE webhook_deliveries
+    SET attempts = attempts + 1, next_attempt_at = sqlc.arg(lease_until)
+    WHERE webhook_deliveries.id IN (
+        SELECT d.id FROM webhook_deliveries d
+        JOIN webhook_endpoints e ON e.id = d.endpoint_id
+        WHERE d.status = 'pending' AND d.next_attempt_at <= sqlc.arg(now) AND e.active
+        ORDER BY d.next_attempt_at ASC
+        LIMIT sqlc.arg(limit_count)
+        FOR UPDATE OF d SKIP LOCKED
+    )
+    RETURNING webhook_deliveries.id, webhook_deliveries.endpoint_id, webhook_deliveries.event_id, webhook_deliveries.attempts
+)
+SELECT claimed.id, claimed.attempts, e.url, e.secret, ev.id AS event_id, ev.contest_id, ev.type, ev.data, ev.created_at
+FROM claimed
+JOIN webhook_endpoints e ON e.id = claimed.endpoint_id
+JOIN webhook_events ev ON ev.id = claimed.event_id;
+
+-- name: FinishWebhookDeliveryAttempt :exec
+UPDATE webhook_deliveries
+SET status = $2, response_status = $3, response_body = $4, duration_ms = $5, last_error = $6,
+    next_attempt_at = $7, last_attempt_at = NOW(),
+    delivered_at = CASE WHEN $2 = 'sent' THEN NOW() ELSE delivered_at END
+WHERE id = $1;
+
+-- name: ListWebhookDeliveries :many
+-- Журнал доставок webhook, новые первыми
+SELECT d.*, ev.type AS event_type
+FROM webhook_deliveries d
+JOIN webhook_events ev ON ev.id = d.event_id
+WHERE d.endpoint_id = sqlc.arg(endpoint_id)
+ORDER BY d.created_at DESC
+LIMIT sqlc.arg(limit_count) OFFSET sqlc.arg(offset_count);
+
+-- name: CountWebhookDeliveries :one
+SELECT COUNT(*) FROM webhook_deliveries
+WHERE endpoint_id = $1;
+
+-- name: GetWebhookDelivery :one
+SELECT d.*, ev.type AS event_type
+FROM webhook_deliveries d
+JOIN webhook_events ev ON ev.id = d.event_id
+WHERE d.id = $1;
+
+-- name: RedeliverWebhook :one
+-- Ставит в очередь новую доставку того же события на тот же webhook
+WITH inserted AS (
+    INSERT INTO webhook_deliveries (endpoint_id, event_id, redelivery)
+    SELECT d.endpoint_id, d.event_id, TRUE
+    FROM webhook_deliveries d
+    WHERE d.id = $1
+    RETURNING *
+)
+SELECT inserted.*, ev.type AS event_type
+FROM inserted
+JOIN webhook_events ev ON ev.id = inserted.event_id;
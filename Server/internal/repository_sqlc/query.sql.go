@@ -11,6 +11,50 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const acceptContestMemberInvite = `-- name: AcceptContestMemberInvite :one
+UPDATE contest_members
+SET status = 'active', accepted_at = NOW()
+WHERE contest_id = $1 AND user_id = $2 AND status = 'invited'
+RETURNING contest_id, user_id, role, status, invited_by_user_id, created_at, accepted_at
+`
+
+type AcceptContestMemberInviteParams struct {
+	ContestID pgtype.UUID
+	UserID    int64
+}
+
+func (q *Queries) AcceptContestMemberInvite(ctx context.Context, arg *AcceptContestMemberInviteParams) (*ContestMember, error) {
+	row := q.db.QueryRow(ctx, acceptContestMemberInvite, arg.ContestID, arg.UserID)
+	var i ContestMember
+	err := row.Scan(
+		&i.ContestID,
+		&i.UserID,
+		&i.Role,
+		&i.Status,
+		&i.InvitedByUserID,
+		&i.CreatedAt,
+		&i.AcceptedAt,
+	)
+	return &i, err
+}
+
+const addContestInvitedVoter = `-- name: AddContestInvitedVoter :exec
+INSERT INTO contest_invited_voters (contest_id, user_id, added_by_user_id)
+VALUES ($1, $2, $3)
+ON CONFLICT (contest_id, user_id) DO NOTHING
+`
+
+type AddContestInvitedVoterParams struct {
+	ContestID     pgtype.UUID
+	UserID        int64
+	AddedByUserID *int64
+}
+
+func (q *Queries) AddContestInvitedVoter(ctx context.Context, arg *AddContestInvitedVoterParams) error {
+	_, err := q.db.Exec(ctx, addContestInvitedVoter, arg.ContestID, arg.UserID, arg.AddedByUserID)
+	return err
+}
+
 const addParticipantPhoto = `-- name: AddParticipantPhoto :one
 
 INSERT INTO contest_participant_photos (id, participant_id, url, thumb_url, position)
@@ -47,10 +91,44 @@ func (q *Queries) AddParticipantPhoto(ctx context.Context, arg *AddParticipantPh
 	return &i, err
 }
 
+const addPetPhoto = `-- name: AddPetPhoto :one
+
+INSERT INTO pet_photos (id, pet_id, url, thumb_url, position)
+VALUES ($1, $2, $3, $4, (SELECT COALESCE(MAX(position), -1) + 1 FROM pet_photos WHERE pet_id = $2))
+RETURNING id, pet_id, url, thumb_url, position, created_at
+`
+
+type AddPetPhotoParams struct {
+	ID       pgtype.UUID
+	PetID    pgtype.UUID
+	Url      string
+	ThumbUrl *string
+}
+
+// Pet Photos
+func (q *Queries) AddPetPhoto(ctx context.Context, arg *AddPetPhotoParams) (*PetPhoto, error) {
+	row := q.db.QueryRow(ctx, addPetPhoto,
+		arg.ID,
+		arg.PetID,
+		arg.Url,
+		arg.ThumbUrl,
+	)
+	var i PetPhoto
+	err := row.Scan(
+		&i.ID,
+		&i.PetID,
+		&i.Url,
+		&i.ThumbUrl,
+		&i.Position,
+		&i.CreatedAt,
+	)
+	return &i, err
+}
+
 const addUserAuthProviders = `-- name: AddUserAuthProviders :one
-INSERT INTO user_auth_providers (user_id, provider_uid, provider, name)
-VALUES ($1, $2, $3, $4)
-RETURNING user_id, provider_uid, provider, name
+INSERT INTO user_auth_providers (user_id, provider_uid, provider, name, avatar_url, avatar_updated_at)
+VALUES ($1, $2, $3, $4, $5, NOW())
+RETURNING user_id, provider_uid, provider, name, avatar_url, avatar_updated_at
 `
 
 type AddUserAuthProvidersParams struct {
@@ -58,6 +136,7 @@ type AddUserAuthProvidersParams struct {
 	ProviderUid string
 	Provider    string
 	Name        *string
+	AvatarUrl   *string
 }
 
 func (q *Queries) AddUserAuthProviders(ctx context.Context, arg *AddUserAuthProvidersParams) (*UserAuthProvider, error) {
@@ -66,6 +145,7 @@ func (q *Queries) AddUserAuthProviders(ctx context.Context, arg *AddUserAuthProv
 		arg.ProviderUid,
 		arg.Provider,
 		arg.Name,
+		arg.AvatarUrl,
 	)
 	var i UserAuthProvider
 	err := row.Scan(
@@ -73,13 +153,277 @@ func (q *Queries) AddUserAuthProviders(ctx context.Context, arg *AddUserAuthProv
 		&i.ProviderUid,
 		&i.Provider,
 		&i.Name,
+		&i.AvatarUrl,
+		&i.AvatarUpdatedAt,
+	)
+	return &i, err
+}
+
+const addUserRole = `-- name: AddUserRole :exec
+INSERT INTO user_roles (user_id, role, granted_by_user_id)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id, role) DO NOTHING
+`
+
+type AddUserRoleParams struct {
+	UserID          int64
+	Role            string
+	GrantedByUserID *int64
+}
+
+func (q *Queries) AddUserRole(ctx context.Context, arg *AddUserRoleParams) error {
+	_, err := q.db.Exec(ctx, addUserRole, arg.UserID, arg.Role, arg.GrantedByUserID)
+	return err
+}
+
+const claimAccountDeletionJob = `-- name: ClaimAccountDeletionJob :one
+UPDATE account_deletion_jobs
+SET status = 'running', attempts = attempts + 1, started_at = NOW(), updated_at = NOW()
+WHERE id = (
+    SELECT id FROM account_deletion_jobs
+    WHERE status IN ('pending', 'running') AND (started_at IS NULL OR started_at < $1)
+    ORDER BY created_at ASC
+    LIMIT 1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id, user_id, status, attempts, last_error, created_at, started_at, finished_at, updated_at
+`
+
+func (q *Queries) ClaimAccountDeletionJob(ctx context.Context, startedAt pgtype.Timestamptz) (*AccountDeletionJob, error) {
+	row := q.db.QueryRow(ctx, claimAccountDeletionJob, startedAt)
+	var i AccountDeletionJob
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Status,
+		&i.Attempts,
+		&i.LastError,
+		&i.CreatedAt,
+		&i.StartedAt,
+		&i.FinishedAt,
+		&i.UpdatedAt,
 	)
 	return &i, err
 }
 
+const claimEmailOutbox = `-- name: ClaimEmailOutbox :many
+UPDATE email_outbox
+SET attempts = attempts + 1, next_attempt_at = $1
+WHERE id IN (
+    SELECT id FROM email_outbox
+    WHERE status = 'pending' AND next_attempt_at <= $2
+    ORDER BY next_attempt_at ASC
+    LIMIT $3
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id, user_id, to_email, subject, text_body, html_body, unsubscribe_url, status, attempts, last_error, next_attempt_at, created_at, sent_at
+`
+
+type ClaimEmailOutboxParams struct {
+	LeaseUntil pgtype.Timestamptz
+	Now        pgtype.Timestamptz
+	LimitCount int32
+}
+
+// Забирает пачку писем к отправке. next_attempt_at сдвигается на lease_until: если процесс упадет
+// во время отправки, письмо снова станет доступно после истечения аренды.
+func (q *Queries) ClaimEmailOutbox(ctx context.Context, arg *ClaimEmailOutboxParams) ([]*EmailOutbox, error) {
+	rows, err := q.db.Query(ctx, claimEmailOutbox, arg.LeaseUntil, arg.Now, arg.LimitCount)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*EmailOutbox
+	for rows.Next() {
+		var i EmailOutbox
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.ToEmail,
+			&i.Subject,
+			&i.TextBody,
+			&i.HtmlBody,
+			&i.UnsubscribeUrl,
+			&i.Status,
+			&i.Attempts,
+			&i.LastError,
+			&i.NextAttemptAt,
+			&i.CreatedAt,
+			&i.SentAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const claimTelegramOutbox = `-- name: ClaimTelegramOutbox :many
+UPDATE telegram_outbox
+SET attempts = attempts + 1, next_attempt_at = $1
+WHERE id IN (
+    SELECT id FROM telegram_outbox
+    WHERE status = 'pending' AND next_attempt_at <= $2
+    ORDER BY next_attempt_at ASC
+    LIMIT $3
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id, chat_id, text, status, attempts, last_error, next_attempt_at, created_at, sent_at
+`
+
+type ClaimTelegramOutboxParams struct {
+	LeaseUntil pgtype.Timestamptz
+	Now        pgtype.Timestamptz
+	LimitCount int32
+}
+
+// Забирает пачку сообщений к отправке, аренда устроена так же, как в ClaimEmailOutbox
+func (q *Queries) ClaimTelegramOutbox(ctx context.Context, arg *ClaimTelegramOutboxParams) ([]*TelegramOutbox, error) {
+	rows, err := q.db.Query(ctx, claimTelegramOutbox, arg.LeaseUntil, arg.Now, arg.LimitCount)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*TelegramOutbox
+	for rows.Next() {
+		var i TelegramOutbox
+		if err := rows.Scan(
+			&i.ID,
+			&i.ChatID,
+			&i.Text,
+			&i.Status,
+			&i.Attempts,
+			&i.LastError,
+			&i.NextAttemptAt,
+			&i.CreatedAt,
+			&i.SentAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const claimWebhookDeliveries = `-- name: ClaimWebhookDeliveries :many
+WITH claimed AS (
+    UPDATE webhook_deliveries
+    SET attempts = attempts + 1, next_attempt_at = $1
+    WHERE webhook_deliveries.id IN (
+        SELECT d.id FROM webhook_deliveries d
+        JOIN webhook_endpoints e ON e.id = d.endpoint_id
+        WHERE d.status = 'pending' AND d.next_attempt_at <= $2 AND e.active
+        ORDER BY d.next_attempt_at ASC
+        LIMIT $3
+        FOR UPDATE OF d SKIP LOCKED
+    )
+    RETURNING webhook_deliveries.id, webhook_deliveries.endpoint_id, webhook_deliveries.event_id, webhook_deliveries.attempts
+)
+SELECT claimed.id, claimed.attempts, e.url, e.secret, ev.id AS event_id, ev.contest_id, ev.type, ev.data, ev.created_at
+FROM claimed
+JOIN webhook_endpoints e ON e.id = claimed.endpoint_id
+JOIN webhook_events ev ON ev.id = claimed.event_id
+`
+
+type ClaimWebhookDeliveriesParams struct {
+	LeaseUntil pgtype.Timestamptz
+	Now        pgtype.Timestamptz
+	LimitCount int32
+}
+
+type ClaimWebhookDeliveriesRow struct {
+	ID        pgtype.UUID
+	Attempts  int32
+	Url       string
+	Secret    string
+	EventID   pgtype.UUID
+	ContestID pgtype.UUID
+	Type      string
+	Data      []byte
+	CreatedAt pgtype.Timestamptz
+}
+
+// Забирает пачку доставок вместе с адресом, ключом и событием; аренда устроена так же, как в ClaimEmailOutbox.
+// Доставки выключенного webhook ждут, пока его не включат снова.
+func (q *Queries) ClaimWebhookDeliveries(ctx context.Context, arg *ClaimWebhookDeliveriesParams) ([]*ClaimWebhookDeliveriesRow, error) {
+	rows, err := q.db.Query(ctx, claimWebhookDeliveries, arg.LeaseUntil, arg.Now, arg.LimitCount)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*ClaimWebhookDeliveriesRow
+	for rows.Next() {
+		var i ClaimWebhookDeliveriesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Attempts,
+			&i.Url,
+			&i.Secret,
+			&i.EventID,
+			&i.ContestID,
+			&i.Type,
+			&i.Data,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const consumeEmailLoginToken = `-- name: ConsumeEmailLoginToken :one
+UPDATE email_login_tokens
+SET used_at = NOW()
+WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW()
+RETURNING email
+`
+
+func (q *Queries) ConsumeEmailLoginToken(ctx context.Context, tokenHash string) (string, error) {
+	row := q.db.QueryRow(ctx, consumeEmailLoginToken, tokenHash)
+	var email string
+	err := row.Scan(&email)
+	return email, err
+}
+
+const consumeTelegramLinkToken = `-- name: ConsumeTelegramLinkToken :one
+DELETE FROM telegram_link_tokens
+WHERE token_hash = $1 AND expires_at > NOW()
+RETURNING user_id
+`
+
+func (q *Queries) ConsumeTelegramLinkToken(ctx context.Context, tokenHash string) (int64, error) {
+	row := q.db.QueryRow(ctx, consumeTelegramLinkToken, tokenHash)
+	var user_id int64
+	err := row.Scan(&user_id)
+	return user_id, err
+}
+
+const consumeWSTicket = `-- name: ConsumeWSTicket :one
+DELETE FROM ws_tickets
+WHERE ticket_hash = $1 AND expires_at > NOW()
+RETURNING user_id
+`
+
+func (q *Queries) ConsumeWSTicket(ctx context.Context, ticketHash string) (int64, error) {
+	row := q.db.QueryRow(ctx, consumeWSTicket, ticketHash)
+	var user_id int64
+	err := row.Scan(&user_id)
+	return user_id, err
+}
+
 const countChatMessages = `-- name: CountChatMessages :one
 SELECT count(1) FROM contest_chat_messages
-WHERE contest_id = $1
+WHERE contest_id = $1 AND hidden_at IS NULL
 `
 
 func (q *Queries) CountChatMessages(ctx context.Context, contestID pgtype.UUID) (int64, error) {
@@ -91,7 +435,7 @@ func (q *Queries) CountChatMessages(ctx context.Context, contestID pgtype.UUID)
 
 const countCommentsByParticipant = `-- name: CountCommentsByParticipant :one
 SELECT count(1) FROM contest_comments
-WHERE participant_id = $1
+WHERE participant_id = $1 AND hidden_at IS NULL
 `
 
 func (q *Queries) CountCommentsByParticipant(ctx context.Context, participantID pgtype.UUID) (int64, error) {
@@ -103,7 +447,7 @@ func (q *Queries) CountCommentsByParticipant(ctx context.Context, participantID
 
 const countContests = `-- name: CountContests :one
 SELECT count(1) FROM contests
-WHERE (COALESCE($1::text, '') = '' OR status = $1)
+WHERE (COALESCE($1::text, '') = '' OR status = $1) AND hidden_at IS NULL
 `
 
 func (q *Queries) CountContests(ctx context.Context, dollar_1 string) (int64, error) {
@@ -113,6 +457,93 @@ func (q *Queries) CountContests(ctx context.Context, dollar_1 string) (int64, er
 	return count, err
 }
 
+const countEmailLoginTokensSince = `-- name: CountEmailLoginTokensSince :one
+SELECT count(1) FROM email_login_tokens
+WHERE email = $1 AND created_at > $2
+`
+
+type CountEmailLoginTokensSinceParams struct {
+	Email     string
+	CreatedAt pgtype.Timestamptz
+}
+
+func (q *Queries) CountEmailLoginTokensSince(ctx context.Context, arg *CountEmailLoginTokensSinceParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countEmailLoginTokensSince, arg.Email, arg.CreatedAt)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countModerationActions = `-- name: CountModerationActions :one
+SELECT count(1) FROM moderation_actions
+`
+
+func (q *Queries) CountModerationActions(ctx context.Context) (int64, error) {
+	row := q.db.QueryRow(ctx, countModerationActions)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countNotifications = `-- name: CountNotifications :one
+SELECT COUNT(*) AS total, COUNT(*) FILTER (WHERE read_at IS NULL) AS unread
+FROM notifications
+WHERE user_id = $1
+`
+
+type CountNotificationsRow struct {
+	Total  int64
+	Unread int64
+}
+
+func (q *Queries) CountNotifications(ctx context.Context, userID int64) (*CountNotificationsRow, error) {
+	row := q.db.QueryRow(ctx, countNotifications, userID)
+	var i CountNotificationsRow
+	err := row.Scan(&i.Total, &i.Unread)
+	return &i, err
+}
+
+const countParticipantsByContest = `-- name: CountParticipantsByContest :one
+SELECT count(1) FROM contest_participants
+WHERE contest_id = $1 AND moderation_status <> 'rejected'
+`
+
+func (q *Queries) CountParticipantsByContest(ctx context.Context, contestID pgtype.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countParticipantsByContest, contestID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countParticipantsByContestAndUser = `-- name: CountParticipantsByContestAndUser :one
+SELECT count(1) FROM contest_participants
+WHERE contest_id = $1 AND user_id = $2 AND moderation_status <> 'rejected'
+`
+
+type CountParticipantsByContestAndUserParams struct {
+	ContestID pgtype.UUID
+	UserID    int64
+}
+
+func (q *Queries) CountParticipantsByContestAndUser(ctx context.Context, arg *CountParticipantsByContestAndUserParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countParticipantsByContestAndUser, arg.ContestID, arg.UserID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countPetPhotos = `-- name: CountPetPhotos :one
+SELECT count(1) FROM pet_photos
+WHERE pet_id = $1
+`
+
+func (q *Queries) CountPetPhotos(ctx context.Context, petID pgtype.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countPetPhotos, petID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const countPhotoLikes = `-- name: CountPhotoLikes :one
 SELECT count(1) FROM photo_likes
 WHERE photo_id = $1
@@ -125,9 +556,79 @@ func (q *Queries) CountPhotoLikes(ctx context.Context, photoID pgtype.UUID) (int
 	return count, err
 }
 
+const countPhotoLikesByParticipant = `-- name: CountPhotoLikesByParticipant :many
+SELECT p.participant_id, count(l.id) AS likes
+FROM contest_participant_photos p
+JOIN contest_participants cp ON cp.id = p.participant_id
+LEFT JOIN photo_likes l ON l.photo_id = p.id
+WHERE cp.contest_id = $1
+GROUP BY p.participant_id
+`
+
+type CountPhotoLikesByParticipantRow struct {
+	ParticipantID pgtype.UUID
+	Likes         int64
+}
+
+func (q *Queries) CountPhotoLikesByParticipant(ctx context.Context, contestID pgtype.UUID) ([]*CountPhotoLikesByParticipantRow, error) {
+	rows, err := q.db.Query(ctx, countPhotoLikesByParticipant, contestID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*CountPhotoLikesByParticipantRow
+	for rows.Next() {
+		var i CountPhotoLikesByParticipantRow
+		if err := rows.Scan(&i.ParticipantID, &i.Likes); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const countPhotosByParticipant = `-- name: CountPhotosByParticipant :one
+SELECT count(1) FROM contest_participant_photos
+WHERE participant_id = $1
+`
+
+func (q *Queries) CountPhotosByParticipant(ctx context.Context, participantID pgtype.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countPhotosByParticipant, participantID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countVoteChoicesByParticipant = `-- name: CountVoteChoicesByParticipant :one
+SELECT count(1) AS choice_count, COALESCE(avg(c.stars), 0)::float8 AS average_stars
+FROM contest_vote_choices c
+JOIN contest_votes cv ON cv.id = c.vote_id
+WHERE c.participant_id = $1 AND cv.category_id IS NOT DISTINCT FROM $2 AND cv.voided_at IS NULL
+`
+
+type CountVoteChoicesByParticipantParams struct {
+	ParticipantID pgtype.UUID
+	CategoryID    pgtype.UUID
+}
+
+type CountVoteChoicesByParticipantRow struct {
+	ChoiceCount  int64
+	AverageStars float64
+}
+
+func (q *Queries) CountVoteChoicesByParticipant(ctx context.Context, arg *CountVoteChoicesByParticipantParams) (*CountVoteChoicesByParticipantRow, error) {
+	row := q.db.QueryRow(ctx, countVoteChoicesByParticipant, arg.ParticipantID, arg.CategoryID)
+	var i CountVoteChoicesByParticipantRow
+	err := row.Scan(&i.ChoiceCount, &i.AverageStars)
+	return &i, err
+}
+
 const countVotesByContest = `-- name: CountVotesByContest :one
 SELECT count(1) FROM contest_votes
-WHERE contest_id = $1
+WHERE contest_id = $1 AND voided_at IS NULL
 `
 
 func (q *Queries) CountVotesByContest(ctx context.Context, contestID pgtype.UUID) (int64, error) {
@@ -139,7 +640,7 @@ func (q *Queries) CountVotesByContest(ctx context.Context, contestID pgtype.UUID
 
 const countVotesByContests = `-- name: CountVotesByContests :many
 SELECT contest_id, count(1) as vote_count FROM contest_votes
-WHERE contest_id = ANY($1::uuid[])
+WHERE contest_id = ANY($1::uuid[]) AND voided_at IS NULL
 GROUP BY contest_id
 `
 
@@ -170,21 +671,122 @@ func (q *Queries) CountVotesByContests(ctx context.Context, dollar_1 []pgtype.UU
 
 const countVotesByParticipant = `-- name: CountVotesByParticipant :one
 SELECT count(1) FROM contest_votes
-WHERE participant_id = $1
+WHERE participant_id = $1 AND category_id IS NOT DISTINCT FROM $2 AND voided_at IS NULL
 `
 
-func (q *Queries) CountVotesByParticipant(ctx context.Context, participantID pgtype.UUID) (int64, error) {
-	row := q.db.QueryRow(ctx, countVotesByParticipant, participantID)
-	var count int64
-	err := row.Scan(&count)
+type CountVotesByParticipantParams struct {
+	ParticipantID pgtype.UUID
+	CategoryID    pgtype.UUID
+}
+
+func (q *Queries) CountVotesByParticipant(ctx context.Context, arg *CountVotesByParticipantParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countVotesByParticipant, arg.ParticipantID, arg.CategoryID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countWebhookDeliveries = `-- name: CountWebhookDeliveries :one
+SELECT COUNT(*) FROM webhook_deliveries
+WHERE endpoint_id = $1
+`
+
+func (q *Queries) CountWebhookDeliveries(ctx context.Context, endpointID pgtype.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countWebhookDeliveries, endpointID)
+	var count int64
+	err := row.Scan(&count)
 	return count, err
 }
 
+const createAccountDeletionJob = `-- name: CreateAccountDeletionJob :one
+
+WITH deleted_user AS (
+    UPDATE users SET deleted_at = COALESCE(deleted_at, NOW())
+    WHERE user_id = $1
+)
+INSERT INTO account_deletion_jobs (user_id)
+VALUES ($1)
+ON CONFLICT (user_id) WHERE status IN ('pending', 'running')
+DO UPDATE SET updated_at = account_deletion_jobs.updated_at
+RETURNING id, user_id, status, attempts, last_error, created_at, started_at, finished_at, updated_at
+`
+
+// Account deletion
+func (q *Queries) CreateAccountDeletionJob(ctx context.Context, userID int64) (*AccountDeletionJob, error) {
+	row := q.db.QueryRow(ctx, createAccountDeletionJob, userID)
+	var i AccountDeletionJob
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Status,
+		&i.Attempts,
+		&i.LastError,
+		&i.CreatedAt,
+		&i.StartedAt,
+		&i.FinishedAt,
+		&i.UpdatedAt,
+	)
+	return &i, err
+}
+
+const createBracketRound = `-- name: CreateBracketRound :exec
+WITH new_round AS (
+    INSERT INTO contest_bracket_rounds (contest_id, round, status, starts_at, ends_at)
+    VALUES ($1, $2, $3, $4, $5)
+    RETURNING contest_id, round
+)
+INSERT INTO contest_bracket_matchups (id, contest_id, round, position, participant_a_id, participant_b_id, seed_a, seed_b, winner_id, decided_at)
+SELECT m.id, nr.contest_id, nr.round, m.position, m.participant_a_id, m.participant_b_id, m.seed_a, m.seed_b, m.winner_id,
+    CASE WHEN m.winner_id IS NULL THEN NULL ELSE NOW() END
+FROM new_round nr, unnest(
+    $6::uuid[],
+    $7::int[],
+    $8::uuid[],
+    $9::uuid[],
+    $10::int[],
+    $11::int[],
+    $12::uuid[]
+) AS m(id, position, participant_a_id, participant_b_id, seed_a, seed_b, winner_id)
+`
+
+type CreateBracketRoundParams struct {
+	ContestID       pgtype.UUID
+	Round           int32
+	Status          string
+	StartsAt        pgtype.Timestamptz
+	EndsAt          pgtype.Timestamptz
+	Ids             []pgtype.UUID
+	Positions       []int32
+	ParticipantAIds []pgtype.UUID
+	ParticipantBIds []pgtype.UUID
+	SeedsA          []int32
+	SeedsB          []int32
+	WinnerIds       []pgtype.UUID
+}
+
+func (q *Queries) CreateBracketRound(ctx context.Context, arg *CreateBracketRoundParams) error {
+	_, err := q.db.Exec(ctx, createBracketRound,
+		arg.ContestID,
+		arg.Round,
+		arg.Status,
+		arg.StartsAt,
+		arg.EndsAt,
+		arg.Ids,
+		arg.Positions,
+		arg.ParticipantAIds,
+		arg.ParticipantBIds,
+		arg.SeedsA,
+		arg.SeedsB,
+		arg.WinnerIds,
+	)
+	return err
+}
+
 const createChatMessage = `-- name: CreateChatMessage :one
 
 INSERT INTO contest_chat_messages (id, contest_id, user_id, text, is_system)
 VALUES ($1, $2, $3, $4, $5)
-RETURNING id, contest_id, user_id, text, is_system, created_at, updated_at
+RETURNING id, contest_id, user_id, text, is_system, created_at, updated_at, hidden_at
 `
 
 type CreateChatMessageParams struct {
@@ -213,6 +815,7 @@ func (q *Queries) CreateChatMessage(ctx context.Context, arg *CreateChatMessageP
 		&i.IsSystem,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.HiddenAt,
 	)
 	return &i, err
 }
@@ -221,7 +824,7 @@ const createComment = `-- name: CreateComment :one
 
 INSERT INTO contest_comments (id, participant_id, user_id, text)
 VALUES ($1, $2, $3, $4)
-RETURNING id, participant_id, user_id, text, created_at, updated_at
+RETURNING id, participant_id, user_id, text, created_at, updated_at, hidden_at
 `
 
 type CreateCommentParams struct {
@@ -247,6 +850,7 @@ func (q *Queries) CreateComment(ctx context.Context, arg *CreateCommentParams) (
 		&i.Text,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.HiddenAt,
 	)
 	return &i, err
 }
@@ -255,7 +859,7 @@ const createContest = `-- name: CreateContest :one
 
 INSERT INTO contests (id, created_by_user_id, title, description, status)
 VALUES ($1, $2, $3, $4, $5)
-RETURNING id, created_by_user_id, title, description, status, created_at, updated_at
+RETURNING id, created_by_user_id, title, description, status, created_at, updated_at, hidden_at, voting_mode, max_choices, min_ballots, jury_weight
 `
 
 type CreateContestParams struct {
@@ -284,534 +888,3855 @@ func (q *Queries) CreateContest(ctx context.Context, arg *CreateContestParams) (
 		&i.Status,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.HiddenAt,
+		&i.VotingMode,
+		&i.MaxChoices,
+		&i.MinBallots,
+		&i.JuryWeight,
 	)
 	return &i, err
 }
 
-const createParticipant = `-- name: CreateParticipant :one
+const createContestCategory = `-- name: CreateContestCategory :one
 
-INSERT INTO contest_participants (id, contest_id, user_id, pet_name, pet_description)
-VALUES ($1, $2, $3, $4, $5)
-RETURNING id, contest_id, user_id, pet_name, pet_description, created_at, updated_at
+INSERT INTO contest_categories (id, contest_id, name, description, position)
+VALUES ($1, $2, $3, $4, (SELECT COALESCE(MAX(position), -1) + 1 FROM contest_categories WHERE contest_id = $2))
+RETURNING id, contest_id, name, description, position, created_at, updated_at
 `
 
-type CreateParticipantParams struct {
-	ID             pgtype.UUID
-	ContestID      pgtype.UUID
-	UserID         int64
-	PetName        string
-	PetDescription string
+type CreateContestCategoryParams struct {
+	ID          pgtype.UUID
+	ContestID   pgtype.UUID
+	Name        string
+	Description string
 }
 
-// Contest Participants
-func (q *Queries) CreateParticipant(ctx context.Context, arg *CreateParticipantParams) (*ContestParticipant, error) {
-	row := q.db.QueryRow(ctx, createParticipant,
+// Contest Categories
+func (q *Queries) CreateContestCategory(ctx context.Context, arg *CreateContestCategoryParams) (*ContestCategory, error) {
+	row := q.db.QueryRow(ctx, createContestCategory,
 		arg.ID,
 		arg.ContestID,
-		arg.UserID,
-		arg.PetName,
-		arg.PetDescription,
+		arg.Name,
+		arg.Description,
 	)
-	var i ContestParticipant
+	var i ContestCategory
 	err := row.Scan(
 		&i.ID,
 		&i.ContestID,
-		&i.UserID,
-		&i.PetName,
-		&i.PetDescription,
+		&i.Name,
+		&i.Description,
+		&i.Position,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
 	return &i, err
 }
 
-const createUser = `-- name: CreateUser :one
+const createContestMember = `-- name: CreateContestMember :one
 
-INSERT INTO users (name)
-VALUES ($1)
-RETURNING user_id, name, created_at
+INSERT INTO contest_members (contest_id, user_id, role, status, invited_by_user_id, accepted_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING contest_id, user_id, role, status, invited_by_user_id, created_at, accepted_at
 `
 
-// Users
-func (q *Queries) CreateUser(ctx context.Context, name string) (*User, error) {
-	row := q.db.QueryRow(ctx, createUser, name)
-	var i User
-	err := row.Scan(&i.UserID, &i.Name, &i.CreatedAt)
-	return &i, err
+type CreateContestMemberParams struct {
+	ContestID       pgtype.UUID
+	UserID          int64
+	Role            string
+	Status          string
+	InvitedByUserID *int64
+	AcceptedAt      pgtype.Timestamptz
 }
 
-const deleteChatMessage = `-- name: DeleteChatMessage :one
-DELETE FROM contest_chat_messages
-WHERE id = $1 AND user_id = $2 AND is_system = FALSE
-RETURNING contest_id
-`
-
-type DeleteChatMessageParams struct {
-	ID     pgtype.UUID
-	UserID int64
+// Contest Members
+func (q *Queries) CreateContestMember(ctx context.Context, arg *CreateContestMemberParams) (*ContestMember, error) {
+	row := q.db.QueryRow(ctx, createContestMember,
+		arg.ContestID,
+		arg.UserID,
+		arg.Role,
+		arg.Status,
+		arg.InvitedByUserID,
+		arg.AcceptedAt,
+	)
+	var i ContestMember
+	err := row.Scan(
+		&i.ContestID,
+		&i.UserID,
+		&i.Role,
+		&i.Status,
+		&i.InvitedByUserID,
+		&i.CreatedAt,
+		&i.AcceptedAt,
+	)
+	return &i, err
 }
 
-func (q *Queries) DeleteChatMessage(ctx context.Context, arg *DeleteChatMessageParams) (pgtype.UUID, error) {
-	row := q.db.QueryRow(ctx, deleteChatMessage, arg.ID, arg.UserID)
-	var contest_id pgtype.UUID
-	err := row.Scan(&contest_id)
-	return contest_id, err
-}
+const createEmailLoginToken = `-- name: CreateEmailLoginToken :exec
 
-const deleteComment = `-- name: DeleteComment :exec
-DELETE FROM contest_comments
-WHERE id = $1
+INSERT INTO email_login_tokens (token_hash, email, expires_at)
+VALUES ($1, $2, $3)
 `
 
-func (q *Queries) DeleteComment(ctx context.Context, id pgtype.UUID) error {
-	_, err := q.db.Exec(ctx, deleteComment, id)
-	return err
+type CreateEmailLoginTokenParams struct {
+	TokenHash string
+	Email     string
+	ExpiresAt pgtype.Timestamptz
 }
 
-const deleteCommentsByParticipant = `-- name: DeleteCommentsByParticipant :exec
-DELETE FROM contest_comments
-WHERE participant_id = $1
-`
-
-func (q *Queries) DeleteCommentsByParticipant(ctx context.Context, participantID pgtype.UUID) error {
-	_, err := q.db.Exec(ctx, deleteCommentsByParticipant, participantID)
+// Email Login Tokens
+func (q *Queries) CreateEmailLoginToken(ctx context.Context, arg *CreateEmailLoginTokenParams) error {
+	_, err := q.db.Exec(ctx, createEmailLoginToken, arg.TokenHash, arg.Email, arg.ExpiresAt)
 	return err
 }
 
-const deleteContest = `-- name: DeleteContest :exec
-DELETE FROM contests
-WHERE id = $1
-`
-
-func (q *Queries) DeleteContest(ctx context.Context, id pgtype.UUID) error {
-	_, err := q.db.Exec(ctx, deleteContest, id)
-	return err
-}
+const createJuryCriterion = `-- name: CreateJuryCriterion :one
 
-const deleteContestVoteByUser = `-- name: DeleteContestVoteByUser :one
-DELETE FROM contest_votes
-WHERE contest_id = $1 AND user_id = $2
-RETURNING participant_id
+INSERT INTO contest_jury_criteria (id, contest_id, name, description, weight, max_score, position)
+VALUES ($1, $2, $3, $4, $5, $6, (SELECT COALESCE(MAX(position), -1) + 1 FROM contest_jury_criteria WHERE contest_id = $2))
+RETURNING id, contest_id, name, description, weight, max_score, position, created_at
 `
 
-type DeleteContestVoteByUserParams struct {
-	ContestID pgtype.UUID
-	UserID    int64
+type CreateJuryCriterionParams struct {
+	ID          pgtype.UUID
+	ContestID   pgtype.UUID
+	Name        string
+	Description string
+	Weight      int32
+	MaxScore    int32
 }
 
-func (q *Queries) DeleteContestVoteByUser(ctx context.Context, arg *DeleteContestVoteByUserParams) (pgtype.UUID, error) {
-	row := q.db.QueryRow(ctx, deleteContestVoteByUser, arg.ContestID, arg.UserID)
-	var participant_id pgtype.UUID
-	err := row.Scan(&participant_id)
-	return participant_id, err
+// Contest Jury
+func (q *Queries) CreateJuryCriterion(ctx context.Context, arg *CreateJuryCriterionParams) (*ContestJuryCriterium, error) {
+	row := q.db.QueryRow(ctx, createJuryCriterion,
+		arg.ID,
+		arg.ContestID,
+		arg.Name,
+		arg.Description,
+		arg.Weight,
+		arg.MaxScore,
+	)
+	var i ContestJuryCriterium
+	err := row.Scan(
+		&i.ID,
+		&i.ContestID,
+		&i.Name,
+		&i.Description,
+		&i.Weight,
+		&i.MaxScore,
+		&i.Position,
+		&i.CreatedAt,
+	)
+	return &i, err
 }
 
-const deleteParticipant = `-- name: DeleteParticipant :exec
-DELETE FROM contest_participants
-WHERE id = $1
+const createModerationAction = `-- name: CreateModerationAction :one
+
+INSERT INTO moderation_actions (actor_user_id, action, target_type, target_id, reason)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, actor_user_id, action, target_type, target_id, reason, created_at
 `
 
-func (q *Queries) DeleteParticipant(ctx context.Context, id pgtype.UUID) error {
-	_, err := q.db.Exec(ctx, deleteParticipant, id)
-	return err
+type CreateModerationActionParams struct {
+	ActorUserID int64
+	Action      string
+	TargetType  string
+	TargetID    string
+	Reason      string
 }
 
-const deleteParticipantPhoto = `-- name: DeleteParticipantPhoto :exec
-DELETE FROM contest_participant_photos
-WHERE id = $1
-`
-
-func (q *Queries) DeleteParticipantPhoto(ctx context.Context, id pgtype.UUID) error {
-	_, err := q.db.Exec(ctx, deleteParticipantPhoto, id)
-	return err
+// Moderation Actions
+func (q *Queries) CreateModerationAction(ctx context.Context, arg *CreateModerationActionParams) (*ModerationAction, error) {
+	row := q.db.QueryRow(ctx, createModerationAction,
+		arg.ActorUserID,
+		arg.Action,
+		arg.TargetType,
+		arg.TargetID,
+		arg.Reason,
+	)
+	var i ModerationAction
+	err := row.Scan(
+		&i.ID,
+		&i.ActorUserID,
+		&i.Action,
+		&i.TargetType,
+		&i.TargetID,
+		&i.Reason,
+		&i.CreatedAt,
+	)
+	return &i, err
 }
 
-const deleteParticipantVideo = `-- name: DeleteParticipantVideo :exec
-DELETE FROM contest_participant_videos
-WHERE participant_id = $1
+const createNotifications = `-- name: CreateNotifications :many
+
+WITH inserted AS (
+    INSERT INTO notifications (user_id, type, contest_id, participant_id, comment_id, actor_user_id, title, text)
+    SELECT users.user_id, $1, $2, $3, $4, $5, $6, $7
+    FROM users
+    WHERE users.user_id = ANY($8::bigint[]) AND users.deleted_at IS NULL
+    RETURNING id, user_id, type, contest_id, participant_id, comment_id, actor_user_id, title, text, read_at, created_at
+)
+SELECT inserted.id, inserted.user_id, inserted.type, inserted.contest_id, inserted.participant_id, inserted.comment_id, inserted.actor_user_id, inserted.title, inserted.text, inserted.read_at, inserted.created_at, COALESCE(actor.name, '')::text AS actor_name
+FROM inserted
+LEFT JOIN users actor ON actor.user_id = inserted.actor_user_id
 `
 
-func (q *Queries) DeleteParticipantVideo(ctx context.Context, participantID pgtype.UUID) error {
-	_, err := q.db.Exec(ctx, deleteParticipantVideo, participantID)
-	return err
+type CreateNotificationsParams struct {
+	Type          string
+	ContestID     pgtype.UUID
+	ParticipantID pgtype.UUID
+	CommentID     pgtype.UUID
+	ActorUserID   pgtype.Int8
+	Title         string
+	Text          string
+	UserIds       []int64
 }
 
-const deletePhotoLike = `-- name: DeletePhotoLike :exec
-DELETE FROM photo_likes
-WHERE photo_id = $1 AND user_id = $2
-`
-
-type DeletePhotoLikeParams struct {
-	PhotoID pgtype.UUID
-	UserID  int64
+type CreateNotificationsRow struct {
+	ID            pgtype.UUID
+	UserID        int64
+	Type          string
+	ContestID     pgtype.UUID
+	ParticipantID pgtype.UUID
+	CommentID     pgtype.UUID
+	ActorUserID   pgtype.Int8
+	Title         string
+	Text          string
+	ReadAt        pgtype.Timestamptz
+	CreatedAt     pgtype.Timestamptz
+	ActorName     string
 }
 
-func (q *Queries) DeletePhotoLike(ctx context.Context, arg *DeletePhotoLikeParams) error {
-	_, err := q.db.Exec(ctx, deletePhotoLike, arg.PhotoID, arg.UserID)
-	return err
+// Notifications
+// Рассылка одного события нескольким получателям; удаленные пользователи пропускаются
+func (q *Queries) CreateNotifications(ctx context.Context, arg *CreateNotificationsParams) ([]*CreateNotificationsRow, error) {
+	rows, err := q.db.Query(ctx, createNotifications,
+		arg.Type,
+		arg.ContestID,
+		arg.ParticipantID,
+		arg.CommentID,
+		arg.ActorUserID,
+		arg.Title,
+		arg.Text,
+		arg.UserIds,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*CreateNotificationsRow
+	for rows.Next() {
+		var i CreateNotificationsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Type,
+			&i.ContestID,
+			&i.ParticipantID,
+			&i.CommentID,
+			&i.ActorUserID,
+			&i.Title,
+			&i.Text,
+			&i.ReadAt,
+			&i.CreatedAt,
+			&i.ActorName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const deleteVotesByParticipant = `-- name: DeleteVotesByParticipant :exec
-DELETE FROM contest_votes
-WHERE participant_id = $1
+const createParticipant = `-- name: CreateParticipant :one
+
+INSERT INTO contest_participants (id, contest_id, user_id, pet_name, pet_description, moderation_status, pet_id, species, breed, sex, birth_date)
+SELECT $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+WHERE ($12::int = 0 OR (
+        SELECT count(1) FROM contest_participants
+        WHERE contest_id = $2 AND moderation_status <> 'rejected'
+    ) < $12::int)
+  AND ($13::int = 0 OR (
+        SELECT count(1) FROM contest_participants
+        WHERE contest_id = $2 AND user_id = $3 AND moderation_status <> 'rejected'
+    ) < $13::int)
+RETURNING id, contest_id, user_id, pet_name, pet_description, created_at, updated_at, hidden_at, moderation_status, moderation_reason, moderated_by_user_id, moderated_at, disqualified_at, disqualified_by_user_id, disqualification_reason, pet_id, species, breed, sex, birth_date
 `
 
-func (q *Queries) DeleteVotesByParticipant(ctx context.Context, participantID pgtype.UUID) error {
-	_, err := q.db.Exec(ctx, deleteVotesByParticipant, participantID)
-	return err
+type CreateParticipantParams struct {
+	ID                pgtype.UUID
+	ContestID         pgtype.UUID
+	UserID            int64
+	PetName           string
+	PetDescription    string
+	ModerationStatus  string
+	PetID             pgtype.UUID
+	Species           *string
+	Breed             *string
+	Sex               *string
+	BirthDate         pgtype.Date
+	MaxEntries        int32
+	MaxEntriesPerUser int32
 }
 
-const getCommentByID = `-- name: GetCommentByID :one
-SELECT id, participant_id, user_id, text, created_at, updated_at FROM contest_comments WHERE id = $1
-`
-
-func (q *Queries) GetCommentByID(ctx context.Context, id pgtype.UUID) (*ContestComment, error) {
-	row := q.db.QueryRow(ctx, getCommentByID, id)
-	var i ContestComment
+// Contest Participants
+// Лимиты заявок (0 - без лимита) проверяются в самом запросе: сверх лимита строка не вставляется
+func (q *Queries) CreateParticipant(ctx context.Context, arg *CreateParticipantParams) (*ContestParticipant, error) {
+	row := q.db.QueryRow(ctx, createParticipant,
+		arg.ID,
+		arg.ContestID,
+		arg.UserID,
+		arg.PetName,
+		arg.PetDescription,
+		arg.ModerationStatus,
+		arg.PetID,
+		arg.Species,
+		arg.Breed,
+		arg.Sex,
+		arg.BirthDate,
+		arg.MaxEntries,
+		arg.MaxEntriesPerUser,
+	)
+	var i ContestParticipant
 	err := row.Scan(
 		&i.ID,
-		&i.ParticipantID,
+		&i.ContestID,
 		&i.UserID,
-		&i.Text,
+		&i.PetName,
+		&i.PetDescription,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.HiddenAt,
+		&i.ModerationStatus,
+		&i.ModerationReason,
+		&i.ModeratedByUserID,
+		&i.ModeratedAt,
+		&i.DisqualifiedAt,
+		&i.DisqualifiedByUserID,
+		&i.DisqualificationReason,
+		&i.PetID,
+		&i.Species,
+		&i.Breed,
+		&i.Sex,
+		&i.BirthDate,
 	)
 	return &i, err
 }
 
-const getContestByID = `-- name: GetContestByID :one
-SELECT id, created_by_user_id, title, description, status, created_at, updated_at FROM contests WHERE id = $1
+const createPet = `-- name: CreatePet :one
+
+INSERT INTO pets (id, owner_user_id, name, species, breed, sex, birth_date, bio)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id, owner_user_id, name, species, breed, birth_date, bio, created_at, updated_at, sex
 `
 
-func (q *Queries) GetContestByID(ctx context.Context, id pgtype.UUID) (*Contest, error) {
-	row := q.db.QueryRow(ctx, getContestByID, id)
-	var i Contest
+type CreatePetParams struct {
+	ID          pgtype.UUID
+	OwnerUserID int64
+	Name        string
+	Species     *string
+	Breed       *string
+	Sex         *string
+	BirthDate   pgtype.Date
+	Bio         string
+}
+
+// Pets
+func (q *Queries) CreatePet(ctx context.Context, arg *CreatePetParams) (*Pet, error) {
+	row := q.db.QueryRow(ctx, createPet,
+		arg.ID,
+		arg.OwnerUserID,
+		arg.Name,
+		arg.Species,
+		arg.Breed,
+		arg.Sex,
+		arg.BirthDate,
+		arg.Bio,
+	)
+	var i Pet
 	err := row.Scan(
 		&i.ID,
-		&i.CreatedByUserID,
-		&i.Title,
-		&i.Description,
-		&i.Status,
+		&i.OwnerUserID,
+		&i.Name,
+		&i.Species,
+		&i.Breed,
+		&i.BirthDate,
+		&i.Bio,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Sex,
 	)
 	return &i, err
 }
 
-const getContestVoteByUser = `-- name: GetContestVoteByUser :one
-SELECT id, contest_id, participant_id, user_id, created_at, updated_at FROM contest_votes
-WHERE contest_id = $1 AND user_id = $2
+const createTelegramAnnouncement = `-- name: CreateTelegramAnnouncement :one
+WITH queued AS (
+    INSERT INTO telegram_outbox (chat_id, text)
+    SELECT $1, $2
+    WHERE NOT EXISTS (
+        SELECT 1 FROM telegram_announcements
+        WHERE contest_id = $3
+          AND EXTRACT(EPOCH FROM NOW() - created_at)::float8 < $4::float8
+    )
+    RETURNING id, status
+), inserted AS (
+    INSERT INTO telegram_announcements (contest_id, outbox_id, chat_id, text, posted_by_user_id)
+    SELECT $3, queued.id, $1, $2, $5
+    FROM queued
+    RETURNING id, contest_id, outbox_id, chat_id, text, posted_by_user_id, created_at
+)
+SELECT inserted.id, inserted.contest_id, inserted.outbox_id, inserted.chat_id, inserted.text, inserted.posted_by_user_id, inserted.created_at, queued.status AS status
+FROM inserted
+JOIN queued ON queued.id = inserted.outbox_id
 `
 
-type GetContestVoteByUserParams struct {
-	ContestID pgtype.UUID
-	UserID    int64
+type CreateTelegramAnnouncementParams struct {
+	ChatID          string
+	Text            string
+	ContestID       pgtype.UUID
+	CooldownSeconds float64
+	PostedByUserID  pgtype.Int8
 }
 
-func (q *Queries) GetContestVoteByUser(ctx context.Context, arg *GetContestVoteByUserParams) (*ContestVote, error) {
-	row := q.db.QueryRow(ctx, getContestVoteByUser, arg.ContestID, arg.UserID)
-	var i ContestVote
+type CreateTelegramAnnouncementRow struct {
+	ID             pgtype.UUID
+	ContestID      pgtype.UUID
+	OutboxID       pgtype.UUID
+	ChatID         string
+	Text           string
+	PostedByUserID pgtype.Int8
+	CreatedAt      pgtype.Timestamptz
+	Status         string
+}
+
+// Сохраняет анонс и ставит его в очередь отправки одним запросом.
+// Если предыдущий анонс конкурса моложе cooldown_seconds, ничего не вставляет и не возвращает строк.
+func (q *Queries) CreateTelegramAnnouncement(ctx context.Context, arg *CreateTelegramAnnouncementParams) (*CreateTelegramAnnouncementRow, error) {
+	row := q.db.QueryRow(ctx, createTelegramAnnouncement,
+		arg.ChatID,
+		arg.Text,
+		arg.ContestID,
+		arg.CooldownSeconds,
+		arg.PostedByUserID,
+	)
+	var i CreateTelegramAnnouncementRow
 	err := row.Scan(
 		&i.ID,
 		&i.ContestID,
-		&i.ParticipantID,
-		&i.UserID,
+		&i.OutboxID,
+		&i.ChatID,
+		&i.Text,
+		&i.PostedByUserID,
 		&i.CreatedAt,
-		&i.UpdatedAt,
+		&i.Status,
 	)
 	return &i, err
 }
 
-const getMaxPhotoPositionByParticipant = `-- name: GetMaxPhotoPositionByParticipant :one
-SELECT COALESCE(MAX(position), 0) AS max_position
-FROM contest_participant_photos
-WHERE participant_id = $1
-`
-
-func (q *Queries) GetMaxPhotoPositionByParticipant(ctx context.Context, participantID pgtype.UUID) (interface{}, error) {
-	row := q.db.QueryRow(ctx, getMaxPhotoPositionByParticipant, participantID)
-	var max_position interface{}
-	err := row.Scan(&max_position)
-	return max_position, err
-}
+const createTelegramLinkToken = `-- name: CreateTelegramLinkToken :exec
 
-const getParticipantByContestAndUser = `-- name: GetParticipantByContestAndUser :one
-SELECT
-    cp.id,
-    cp.contest_id,
-    cp.user_id,
-    COALESCE(u.name, 'Пользователь ' || cp.user_id::text) AS user_name,
-    cp.pet_name,
-    cp.pet_description,
-    cp.created_at,
-    cp.updated_at
-FROM contest_participants cp
-LEFT JOIN users u ON u.user_id = cp.user_id
-WHERE cp.contest_id = $1 AND cp.user_id = $2
+WITH old_deleted AS (
+    DELETE FROM telegram_link_tokens
+    WHERE telegram_link_tokens.user_id = $2 OR telegram_link_tokens.expires_at <= NOW()
+)
+INSERT INTO telegram_link_tokens (token_hash, user_id, expires_at)
+VALUES ($1, $2, $3)
 `
 
-type GetParticipantByContestAndUserParams struct {
-	ContestID pgtype.UUID
+type CreateTelegramLinkTokenParams struct {
+	TokenHash string
 	UserID    int64
+	ExpiresAt pgtype.Timestamptz
 }
 
-type GetParticipantByContestAndUserRow struct {
-	ID             pgtype.UUID
-	ContestID      pgtype.UUID
-	UserID         int64
-	UserName       string
-	PetName        string
-	PetDescription string
-	CreatedAt      pgtype.Timestamptz
-	UpdatedAt      pgtype.Timestamptz
+// Telegram
+// Новая ссылка привязки заменяет прежние ссылки пользователя; заодно удаляются просроченные
+func (q *Queries) CreateTelegramLinkToken(ctx context.Context, arg *CreateTelegramLinkTokenParams) error {
+	_, err := q.db.Exec(ctx, createTelegramLinkToken, arg.TokenHash, arg.UserID, arg.ExpiresAt)
+	return err
 }
 
-func (q *Queries) GetParticipantByContestAndUser(ctx context.Context, arg *GetParticipantByContestAndUserParams) (*GetParticipantByContestAndUserRow, error) {
-	row := q.db.QueryRow(ctx, getParticipantByContestAndUser, arg.ContestID, arg.UserID)
-	var i GetParticipantByContestAndUserRow
+const createUser = `-- name: CreateUser :one
+
+INSERT INTO users (name)
+VALUES ($1)
+RETURNING user_id, name, created_at, avatar_url, bio, avatar_thumb_url, locale, deleted_at
+`
+
+// Users
+func (q *Queries) CreateUser(ctx context.Context, name string) (*User, error) {
+	row := q.db.QueryRow(ctx, createUser, name)
+	var i User
 	err := row.Scan(
-		&i.ID,
-		&i.ContestID,
 		&i.UserID,
-		&i.UserName,
-		&i.PetName,
-		&i.PetDescription,
+		&i.Name,
 		&i.CreatedAt,
-		&i.UpdatedAt,
+		&i.AvatarUrl,
+		&i.Bio,
+		&i.AvatarThumbUrl,
+		&i.Locale,
+		&i.DeletedAt,
 	)
 	return &i, err
 }
 
-const getParticipantByID = `-- name: GetParticipantByID :one
-SELECT
-    cp.id,
-    cp.contest_id,
-    cp.user_id,
-    COALESCE(u.name, 'Пользователь ' || cp.user_id::text) AS user_name,
-    cp.pet_name,
-    cp.pet_description,
-    cp.created_at,
-    cp.updated_at
-FROM contest_participants cp
-LEFT JOIN users u ON u.user_id = cp.user_id
-WHERE cp.id = $1
+const createWebhookEndpoint = `-- name: CreateWebhookEndpoint :one
+
+INSERT INTO webhook_endpoints (contest_id, url, secret, event_types, created_by_user_id)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, contest_id, url, secret, event_types, active, created_by_user_id, created_at, updated_at
 `
 
-type GetParticipantByIDRow struct {
-	ID             pgtype.UUID
-	ContestID      pgtype.UUID
-	UserID         int64
-	UserName       string
-	PetName        string
-	PetDescription string
-	CreatedAt      pgtype.Timestamptz
-	UpdatedAt      pgtype.Timestamptz
+type CreateWebhookEndpointParams struct {
+	ContestID       pgtype.UUID
+	Url             string
+	Secret          string
+	EventTypes      []string
+	CreatedByUserID pgtype.Int8
 }
 
-func (q *Queries) GetParticipantByID(ctx context.Context, id pgtype.UUID) (*GetParticipantByIDRow, error) {
-	row := q.db.QueryRow(ctx, getParticipantByID, id)
-	var i GetParticipantByIDRow
+// Webhooks
+func (q *Queries) CreateWebhookEndpoint(ctx context.Context, arg *CreateWebhookEndpointParams) (*WebhookEndpoint, error) {
+	row := q.db.QueryRow(ctx, createWebhookEndpoint,
+		arg.ContestID,
+		arg.Url,
+		arg.Secret,
+		arg.EventTypes,
+		arg.CreatedByUserID,
+	)
+	var i WebhookEndpoint
 	err := row.Scan(
 		&i.ID,
 		&i.ContestID,
-		&i.UserID,
-		&i.UserName,
-		&i.PetName,
-		&i.PetDescription,
+		&i.Url,
+		&i.Secret,
+		&i.EventTypes,
+		&i.Active,
+		&i.CreatedByUserID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
 	return &i, err
 }
 
-const getPhotoLikeByUser = `-- name: GetPhotoLikeByUser :one
-SELECT id, photo_id, user_id, created_at FROM photo_likes
-WHERE photo_id = $1 AND user_id = $2
+const createWSTicket = `-- name: CreateWSTicket :exec
+
+INSERT INTO ws_tickets (ticket_hash, user_id, expires_at)
+VALUES ($1, $2, $3)
 `
 
-type GetPhotoLikeByUserParams struct {
-	PhotoID pgtype.UUID
-	UserID  int64
+type CreateWSTicketParams struct {
+	TicketHash string
+	UserID     int64
+	ExpiresAt  pgtype.Timestamptz
 }
 
-func (q *Queries) GetPhotoLikeByUser(ctx context.Context, arg *GetPhotoLikeByUserParams) (*PhotoLike, error) {
-	row := q.db.QueryRow(ctx, getPhotoLikeByUser, arg.PhotoID, arg.UserID)
-	var i PhotoLike
-	err := row.Scan(
-		&i.ID,
-		&i.PhotoID,
-		&i.UserID,
-		&i.CreatedAt,
-	)
-	return &i, err
+// WebSocket Tickets
+func (q *Queries) CreateWSTicket(ctx context.Context, arg *CreateWSTicketParams) error {
+	_, err := q.db.Exec(ctx, createWSTicket, arg.TicketHash, arg.UserID, arg.ExpiresAt)
+	return err
 }
 
-const getPhotosByParticipantID = `-- name: GetPhotosByParticipantID :many
-SELECT id, participant_id, url, thumb_url, created_at, position FROM contest_participant_photos
-WHERE participant_id = $1
-ORDER BY position ASC, created_at ASC
+const decideBracketMatchup = `-- name: DecideBracketMatchup :exec
+UPDATE contest_bracket_matchups
+SET winner_id = $2, decided_at = NOW()
+WHERE id = $1 AND decided_at IS NULL
 `
 
-func (q *Queries) GetPhotosByParticipantID(ctx context.Context, participantID pgtype.UUID) ([]*ContestParticipantPhoto, error) {
-	rows, err := q.db.Query(ctx, getPhotosByParticipantID, participantID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []*ContestParticipantPhoto
-	for rows.Next() {
-		var i ContestParticipantPhoto
-		if err := rows.Scan(
-			&i.ID,
-			&i.ParticipantID,
-			&i.Url,
-			&i.ThumbUrl,
-			&i.CreatedAt,
-			&i.Position,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, &i)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+type DecideBracketMatchupParams struct {
+	ID       pgtype.UUID
+	WinnerID pgtype.UUID
 }
 
-const getUserAuthProvidersByProviderUid = `-- name: GetUserAuthProvidersByProviderUid :one
-SELECT user_id, provider_uid, provider, name FROM user_auth_providers
-WHERE provider_uid = $1 AND provider = $2
+func (q *Queries) DecideBracketMatchup(ctx context.Context, arg *DecideBracketMatchupParams) error {
+	_, err := q.db.Exec(ctx, decideBracketMatchup, arg.ID, arg.WinnerID)
+	return err
+}
+
+const deleteChatMessage = `-- name: DeleteChatMessage :one
+DELETE FROM contest_chat_messages
+WHERE id = $1 AND user_id = $2 AND is_system = FALSE
+RETURNING contest_id
 `
 
-type GetUserAuthProvidersByProviderUidParams struct {
-	ProviderUid string
-	Provider    string
+type DeleteChatMessageParams struct {
+	ID     pgtype.UUID
+	UserID int64
 }
 
-func (q *Queries) GetUserAuthProvidersByProviderUid(ctx context.Context, arg *GetUserAuthProvidersByProviderUidParams) (*UserAuthProvider, error) {
-	row := q.db.QueryRow(ctx, getUserAuthProvidersByProviderUid, arg.ProviderUid, arg.Provider)
-	var i UserAuthProvider
-	err := row.Scan(
-		&i.UserID,
-		&i.ProviderUid,
-		&i.Provider,
-		&i.Name,
-	)
-	return &i, err
+func (q *Queries) DeleteChatMessage(ctx context.Context, arg *DeleteChatMessageParams) (pgtype.UUID, error) {
+	row := q.db.QueryRow(ctx, deleteChatMessage, arg.ID, arg.UserID)
+	var contest_id pgtype.UUID
+	err := row.Scan(&contest_id)
+	return contest_id, err
 }
 
-const getUserAuthProvidersByUserID = `-- name: GetUserAuthProvidersByUserID :many
-SELECT user_id, provider_uid, provider, name FROM user_auth_providers
-WHERE user_id = $1
+const deleteChatMessageByID = `-- name: DeleteChatMessageByID :one
+DELETE FROM contest_chat_messages
+WHERE id = $1
+RETURNING contest_id
 `
 
-func (q *Queries) GetUserAuthProvidersByUserID(ctx context.Context, userID int64) ([]*UserAuthProvider, error) {
-	rows, err := q.db.Query(ctx, getUserAuthProvidersByUserID, userID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []*UserAuthProvider
-	for rows.Next() {
-		var i UserAuthProvider
-		if err := rows.Scan(
-			&i.UserID,
-			&i.ProviderUid,
-			&i.Provider,
-			&i.Name,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, &i)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+func (q *Queries) DeleteChatMessageByID(ctx context.Context, id pgtype.UUID) (pgtype.UUID, error) {
+	row := q.db.QueryRow(ctx, deleteChatMessageByID, id)
+	var contest_id pgtype.UUID
+	err := row.Scan(&contest_id)
+	return contest_id, err
 }
 
-const getUserByID = `-- name: GetUserByID :one
-SELECT user_id, name, created_at FROM users
-WHERE user_id = $1
+const deleteComment = `-- name: DeleteComment :exec
+DELETE FROM contest_comments
+WHERE id = $1
 `
 
-func (q *Queries) GetUserByID(ctx context.Context, userID int64) (*User, error) {
-	row := q.db.QueryRow(ctx, getUserByID, userID)
-	var i User
-	err := row.Scan(&i.UserID, &i.Name, &i.CreatedAt)
-	return &i, err
+func (q *Queries) DeleteComment(ctx context.Context, id pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, deleteComment, id)
+	return err
 }
 
-const getVideoByParticipantID = `-- name: GetVideoByParticipantID :one
-SELECT id, participant_id, url, created_at FROM contest_participant_videos
+const deleteCommentsByParticipant = `-- name: DeleteCommentsByParticipant :exec
+DELETE FROM contest_comments
 WHERE participant_id = $1
 `
 
-func (q *Queries) GetVideoByParticipantID(ctx context.Context, participantID pgtype.UUID) (*ContestParticipantVideo, error) {
-	row := q.db.QueryRow(ctx, getVideoByParticipantID, participantID)
-	var i ContestParticipantVideo
-	err := row.Scan(
-		&i.ID,
-		&i.ParticipantID,
-		&i.Url,
-		&i.CreatedAt,
+func (q *Queries) DeleteCommentsByParticipant(ctx context.Context, participantID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, deleteCommentsByParticipant, participantID)
+	return err
+}
+
+const deleteContest = `-- name: DeleteContest :exec
+DELETE FROM contests
+WHERE id = $1
+`
+
+func (q *Queries) DeleteContest(ctx context.Context, id pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, deleteContest, id)
+	return err
+}
+
+const deleteContestCategory = `-- name: DeleteContestCategory :exec
+DELETE FROM contest_categories
+WHERE contest_id = $1 AND id = $2
+`
+
+type DeleteContestCategoryParams struct {
+	ContestID pgtype.UUID
+	ID        pgtype.UUID
+}
+
+func (q *Queries) DeleteContestCategory(ctx context.Context, arg *DeleteContestCategoryParams) error {
+	_, err := q.db.Exec(ctx, deleteContestCategory, arg.ContestID, arg.ID)
+	return err
+}
+
+const deleteContestInvitedVoter = `-- name: DeleteContestInvitedVoter :exec
+DELETE FROM contest_invited_voters
+WHERE contest_id = $1 AND user_id = $2
+`
+
+type DeleteContestInvitedVoterParams struct {
+	ContestID pgtype.UUID
+	UserID    int64
+}
+
+func (q *Queries) DeleteContestInvitedVoter(ctx context.Context, arg *DeleteContestInvitedVoterParams) error {
+	_, err := q.db.Exec(ctx, deleteContestInvitedVoter, arg.ContestID, arg.UserID)
+	return err
+}
+
+const deleteContestMember = `-- name: DeleteContestMember :exec
+DELETE FROM contest_members
+WHERE contest_id = $1 AND user_id = $2
+`
+
+type DeleteContestMemberParams struct {
+	ContestID pgtype.UUID
+	UserID    int64
+}
+
+func (q *Queries) DeleteContestMember(ctx context.Context, arg *DeleteContestMemberParams) error {
+	_, err := q.db.Exec(ctx, deleteContestMember, arg.ContestID, arg.UserID)
+	return err
+}
+
+const deleteContestVoteByUser = `-- name: DeleteContestVoteByUser :one
+DELETE FROM contest_votes
+WHERE contest_id = $1 AND user_id = $2 AND category_id IS NOT DISTINCT FROM $3 AND voided_at IS NULL
+RETURNING participant_id
+`
+
+type DeleteContestVoteByUserParams struct {
+	ContestID  pgtype.UUID
+	UserID     int64
+	CategoryID pgtype.UUID
+}
+
+func (q *Queries) DeleteContestVoteByUser(ctx context.Context, arg *DeleteContestVoteByUserParams) (pgtype.UUID, error) {
+	row := q.db.QueryRow(ctx, deleteContestVoteByUser, arg.ContestID, arg.UserID, arg.CategoryID)
+	var participant_id pgtype.UUID
+	err := row.Scan(&participant_id)
+	return participant_id, err
+}
+
+const deleteExpiredWSTickets = `-- name: DeleteExpiredWSTickets :exec
+DELETE FROM ws_tickets
+WHERE expires_at <= NOW()
+`
+
+func (q *Queries) DeleteExpiredWSTickets(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, deleteExpiredWSTickets)
+	return err
+}
+
+const deleteJuryCriterion = `-- name: DeleteJuryCriterion :exec
+DELETE FROM contest_jury_criteria
+WHERE contest_id = $1 AND id = $2
+`
+
+type DeleteJuryCriterionParams struct {
+	ContestID pgtype.UUID
+	ID        pgtype.UUID
+}
+
+func (q *Queries) DeleteJuryCriterion(ctx context.Context, arg *DeleteJuryCriterionParams) error {
+	_, err := q.db.Exec(ctx, deleteJuryCriterion, arg.ContestID, arg.ID)
+	return err
+}
+
+const deleteParticipant = `-- name: DeleteParticipant :exec
+DELETE FROM contest_participants
+WHERE id = $1
+`
+
+func (q *Queries) DeleteParticipant(ctx context.Context, id pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, deleteParticipant, id)
+	return err
+}
+
+const deleteParticipantPhoto = `-- name: DeleteParticipantPhoto :exec
+DELETE FROM contest_participant_photos
+WHERE id = $1
+`
+
+func (q *Queries) DeleteParticipantPhoto(ctx context.Context, id pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, deleteParticipantPhoto, id)
+	return err
+}
+
+const deleteParticipantVideo = `-- name: DeleteParticipantVideo :exec
+DELETE FROM contest_participant_videos
+WHERE participant_id = $1
+`
+
+func (q *Queries) DeleteParticipantVideo(ctx context.Context, participantID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, deleteParticipantVideo, participantID)
+	return err
+}
+
+const deletePet = `-- name: DeletePet :exec
+DELETE FROM pets WHERE id = $1
+`
+
+func (q *Queries) DeletePet(ctx context.Context, id pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, deletePet, id)
+	return err
+}
+
+const deletePetPhoto = `-- name: DeletePetPhoto :execrows
+DELETE FROM pet_photos
+WHERE pet_id = $1 AND id = $2
+`
+
+type DeletePetPhotoParams struct {
+	PetID pgtype.UUID
+	ID    pgtype.UUID
+}
+
+func (q *Queries) DeletePetPhoto(ctx context.Context, arg *DeletePetPhotoParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deletePetPhoto, arg.PetID, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deletePhotoLike = `-- name: DeletePhotoLike :exec
+DELETE FROM photo_likes
+WHERE photo_id = $1 AND user_id = $2
+`
+
+type DeletePhotoLikeParams struct {
+	PhotoID pgtype.UUID
+	UserID  int64
+}
+
+func (q *Queries) DeletePhotoLike(ctx context.Context, arg *DeletePhotoLikeParams) error {
+	_, err := q.db.Exec(ctx, deletePhotoLike, arg.PhotoID, arg.UserID)
+	return err
+}
+
+const deleteStaleRateLimitBuckets = `-- name: DeleteStaleRateLimitBuckets :exec
+DELETE FROM rate_limit_buckets
+WHERE updated_at < $1
+`
+
+func (q *Queries) DeleteStaleRateLimitBuckets(ctx context.Context, updatedAt pgtype.Timestamptz) error {
+	_, err := q.db.Exec(ctx, deleteStaleRateLimitBuckets, updatedAt)
+	return err
+}
+
+const deleteTelegramAccount = `-- name: DeleteTelegramAccount :execrows
+DELETE FROM telegram_accounts
+WHERE user_id = $1
+`
+
+func (q *Queries) DeleteTelegramAccount(ctx context.Context, userID int64) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteTelegramAccount, userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteTelegramChat = `-- name: DeleteTelegramChat :exec
+WITH subscriptions_deleted AS (
+    DELETE FROM telegram_subscriptions WHERE telegram_subscriptions.chat_id = $1
+)
+DELETE FROM telegram_accounts
+WHERE telegram_accounts.chat_id = $1
+`
+
+// Забывает чат, который больше недоступен боту: привязку и подписки
+func (q *Queries) DeleteTelegramChat(ctx context.Context, chatID int64) error {
+	_, err := q.db.Exec(ctx, deleteTelegramChat, chatID)
+	return err
+}
+
+const deleteUserPersonalData = `-- name: DeleteUserPersonalData :exec
+WITH photo_likes_deleted AS (
+    DELETE FROM photo_likes
+    WHERE photo_likes.user_id = $1
+       OR photo_likes.photo_id IN (
+           SELECT p.id FROM contest_participant_photos p
+           JOIN contest_participants cp ON cp.id = p.participant_id
+           WHERE cp.user_id = $1
+       )
+), photos_deleted AS (
+    DELETE FROM contest_participant_photos
+    WHERE participant_id IN (SELECT id FROM contest_participants WHERE contest_participants.user_id = $1)
+), videos_deleted AS (
+    DELETE FROM contest_participant_videos
+    WHERE participant_id IN (SELECT id FROM contest_participants WHERE contest_participants.user_id = $1)
+), votes_deleted AS (
+    DELETE FROM contest_votes WHERE contest_votes.user_id = $1
+), bracket_votes_deleted AS (
+    DELETE FROM contest_bracket_votes WHERE contest_bracket_votes.user_id = $1
+), pets_deleted AS (
+    DELETE FROM pets WHERE owner_user_id = $1
+), providers_deleted AS (
+    DELETE FROM user_auth_providers WHERE user_auth_providers.user_id = $1
+), roles_deleted AS (
+    DELETE FROM user_roles WHERE user_roles.user_id = $1
+), memberships_deleted AS (
+    DELETE FROM contest_members WHERE contest_members.user_id = $1
+), invites_deleted AS (
+    DELETE FROM contest_invited_voters WHERE contest_invited_voters.user_id = $1
+), privacy_deleted AS (
+    DELETE FROM user_privacy_settings WHERE user_privacy_settings.user_id = $1
+), tickets_deleted AS (
+    DELETE FROM ws_tickets WHERE ws_tickets.user_id = $1
+), notifications_deleted AS (
+    DELETE FROM notifications WHERE notifications.user_id = $1
+), notification_preferences_deleted AS (
+    DELETE FROM notification_preferences WHERE notification_preferences.user_id = $1
+), emails_deleted AS (
+    DELETE FROM email_outbox WHERE email_outbox.user_id = $1
+), telegram_tokens_deleted AS (
+    DELETE FROM telegram_link_tokens WHERE telegram_link_tokens.user_id = $1
+), telegram_subscriptions_deleted AS (
+    DELETE FROM telegram_subscriptions
+    WHERE chat_id IN (SELECT chat_id FROM telegram_accounts WHERE telegram_accounts.user_id = $1)
+), telegram_accounts_deleted AS (
+    DELETE FROM telegram_accounts WHERE telegram_accounts.user_id = $1
+), email_login_tokens_deleted AS (
+    DELETE FROM email_login_tokens
+    WHERE email IN (
+        SELECT provider_uid FROM user_auth_providers WHERE user_auth_providers.user_id = $1 AND provider = 'email'
+        UNION
+        SELECT notification_preferences.email FROM notification_preferences WHERE notification_preferences.user_id = $1
+    )
+)
+UPDATE users
+SET name = $2, avatar_url = NULL, avatar_thumb_url = NULL, bio = '', deleted_at = COALESCE(deleted_at, NOW())
+WHERE users.user_id = $1
+`
+
+type DeleteUserPersonalDataParams struct {
+	UserID int64
+	Name   string
+}
+
+// Удаляет персональные данные одним запросом (изменяющие CTE выполняются атомарно):
+// голоса, лайки, медиа заявок, питомцев, привязки провайдеров и Telegram, роли, уведомления, письма и ссылки входа по email;
+// профиль обезличивается.
+// Конкурсы, заявки, комментарии и сообщения остаются за обезличенным пользователем.
+func (q *Queries) DeleteUserPersonalData(ctx context.Context, arg *DeleteUserPersonalDataParams) error {
+	_, err := q.db.Exec(ctx, deleteUserPersonalData, arg.UserID, arg.Name)
+	return err
+}
+
+const deleteVotesByParticipant = `-- name: DeleteVotesByParticipant :exec
+DELETE FROM contest_votes
+WHERE participant_id = $1
+`
+
+func (q *Queries) DeleteVotesByParticipant(ctx context.Context, participantID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, deleteVotesByParticipant, participantID)
+	return err
+}
+
+const deleteWebhookEndpoint = `-- name: DeleteWebhookEndpoint :execrows
+DELETE FROM webhook_endpoints
+WHERE id = $1
+`
+
+func (q *Queries) DeleteWebhookEndpoint(ctx context.Context, id pgtype.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteWebhookEndpoint, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const demoteContestOwner = `-- name: DemoteContestOwner :exec
+UPDATE contest_members
+SET role = 'organizer'
+WHERE contest_id = $1 AND user_id = $2 AND role = 'owner'
+`
+
+type DemoteContestOwnerParams struct {
+	ContestID pgtype.UUID
+	UserID    int64
+}
+
+// Передача владения: прежний владелец становится организатором до повышения нового -
+// частичный уникальный индекс idx_contest_members_owner не допускает двух владельцев
+func (q *Queries) DemoteContestOwner(ctx context.Context, arg *DemoteContestOwnerParams) error {
+	_, err := q.db.Exec(ctx, demoteContestOwner, arg.ContestID, arg.UserID)
+	return err
+}
+
+const disqualifyParticipant = `-- name: DisqualifyParticipant :execrows
+UPDATE contest_participants
+SET disqualified_at = NOW(),
+    disqualified_by_user_id = $2,
+    disqualification_reason = $3
+WHERE id = $1 AND disqualified_at IS NULL
+`
+
+type DisqualifyParticipantParams struct {
+	ID                     pgtype.UUID
+	DisqualifiedByUserID   pgtype.Int8
+	DisqualificationReason string
+}
+
+func (q *Queries) DisqualifyParticipant(ctx context.Context, arg *DisqualifyParticipantParams) (int64, error) {
+	result, err := q.db.Exec(ctx, disqualifyParticipant, arg.ID, arg.DisqualifiedByUserID, arg.DisqualificationReason)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const enqueueEmails = `-- name: EnqueueEmails :execrows
+INSERT INTO email_outbox (user_id, to_email, subject, text_body, html_body, unsubscribe_url)
+SELECT NULLIF(e.user_id, 0), e.to_email, e.subject, e.text_body, e.html_body, e.unsubscribe_url
+FROM unnest(
+    $1::bigint[],
+    $2::text[],
+    $3::text[],
+    $4::text[],
+    $5::text[],
+    $6::text[]
+) AS e(user_id, to_email, subject, text_body, html_body, unsubscribe_url)
+`
+
+type EnqueueEmailsParams struct {
+	UserIds         []int64
+	ToEmails        []string
+	Subjects        []string
+	TextBodies      []string
+	HtmlBodies      []string
+	UnsubscribeUrls []string
+}
+
+// Пакетная постановка писем в очередь; user_id = 0 - письмо без привязки к пользователю
+func (q *Queries) EnqueueEmails(ctx context.Context, arg *EnqueueEmailsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, enqueueEmails,
+		arg.UserIds,
+		arg.ToEmails,
+		arg.Subjects,
+		arg.TextBodies,
+		arg.HtmlBodies,
+		arg.UnsubscribeUrls,
 	)
-	return &i, err
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const enqueueTelegramMessages = `-- name: EnqueueTelegramMessages :execrows
+INSERT INTO telegram_outbox (chat_id, text)
+SELECT m.chat_id, m.text
+FROM unnest(
+    $1::text[],
+    $2::text[]
+) AS m(chat_id, text)
+`
+
+type EnqueueTelegramMessagesParams struct {
+	ChatIds []string
+	Texts   []string
+}
+
+// Пакетная постановка сообщений в очередь
+func (q *Queries) EnqueueTelegramMessages(ctx context.Context, arg *EnqueueTelegramMessagesParams) (int64, error) {
+	result, err := q.db.Exec(ctx, enqueueTelegramMessages, arg.ChatIds, arg.Texts)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const enqueueWebhookEvent = `-- name: EnqueueWebhookEvent :execrows
+WITH targets AS (
+    SELECT e.id FROM webhook_endpoints e
+    WHERE e.contest_id = $1 AND e.active
+        AND (cardinality(e.event_types) = 0 OR $2::text = ANY(e.event_types))
+), event AS (
+    INSERT INTO webhook_events (contest_id, type, data)
+    SELECT $1, $2, $3
+    WHERE EXISTS (SELECT 1 FROM targets)
+    RETURNING id
+)
+INSERT INTO webhook_deliveries (endpoint_id, event_id)
+SELECT targets.id, event.id
+FROM targets CROSS JOIN event
+`
+
+type EnqueueWebhookEventParams struct {
+	ContestID pgtype.UUID
+	Type      string
+	Data      []byte
+}
+
+// Сохраняет событие и ставит доставки на все включенные webhooks конкурса, подписанные на его тип.
+// Если подходящих webhooks нет, событие не сохраняется.
+func (q *Queries) EnqueueWebhookEvent(ctx context.Context, arg *EnqueueWebhookEventParams) (int64, error) {
+	result, err := q.db.Exec(ctx, enqueueWebhookEvent, arg.ContestID, arg.Type, arg.Data)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const failEmailAttempt = `-- name: FailEmailAttempt :exec
+UPDATE email_outbox
+SET status = $2, last_error = $3, next_attempt_at = $4
+WHERE id = $1
+`
+
+type FailEmailAttemptParams struct {
+	ID            pgtype.UUID
+	Status        string
+	LastError     string
+	NextAttemptAt pgtype.Timestamptz
+}
+
+func (q *Queries) FailEmailAttempt(ctx context.Context, arg *FailEmailAttemptParams) error {
+	_, err := q.db.Exec(ctx, failEmailAttempt,
+		arg.ID,
+		arg.Status,
+		arg.LastError,
+		arg.NextAttemptAt,
+	)
+	return err
+}
+
+const failTelegramMessageAttempt = `-- name: FailTelegramMessageAttempt :exec
+UPDATE telegram_outbox
+SET status = $2, last_error = $3, next_attempt_at = $4
+WHERE id = $1
+`
+
+type FailTelegramMessageAttemptParams struct {
+	ID            pgtype.UUID
+	Status        string
+	LastError     string
+	NextAttemptAt pgtype.Timestamptz
+}
+
+func (q *Queries) FailTelegramMessageAttempt(ctx context.Context, arg *FailTelegramMessageAttemptParams) error {
+	_, err := q.db.Exec(ctx, failTelegramMessageAttempt,
+		arg.ID,
+		arg.Status,
+		arg.LastError,
+		arg.NextAttemptAt,
+	)
+	return err
+}
+
+const finishAccountDeletionJob = `-- name: FinishAccountDeletionJob :exec
+UPDATE account_deletion_jobs
+SET status = $2,
+    last_error = $3,
+    finished_at = CASE WHEN $2 = 'pending' THEN NULL ELSE NOW() END,
+    updated_at = NOW()
+WHERE id = $1
+`
+
+type FinishAccountDeletionJobParams struct {
+	ID        pgtype.UUID
+	Status    string
+	LastError string
+}
+
+func (q *Queries) FinishAccountDeletionJob(ctx context.Context, arg *FinishAccountDeletionJobParams) error {
+	_, err := q.db.Exec(ctx, finishAccountDeletionJob, arg.ID, arg.Status, arg.LastError)
+	return err
+}
+
+const finishWebhookDeliveryAttempt = `-- name: FinishWebhookDeliveryAttempt :exec
+UPDATE webhook_deliveries
+SET status = $2, response_status = $3, response_body = $4, duration_ms = $5, last_error = $6,
+    next_attempt_at = $7, last_attempt_at = NOW(),
+    delivered_at = CASE WHEN $2 = 'sent' THEN NOW() ELSE delivered_at END
+WHERE id = $1
+`
+
+type FinishWebhookDeliveryAttemptParams struct {
+	ID             pgtype.UUID
+	Status         string
+	ResponseStatus *int32
+	ResponseBody   string
+	DurationMs     int32
+	LastError      string
+	NextAttemptAt  pgtype.Timestamptz
+}
+
+func (q *Queries) FinishWebhookDeliveryAttempt(ctx context.Context, arg *FinishWebhookDeliveryAttemptParams) error {
+	_, err := q.db.Exec(ctx, finishWebhookDeliveryAttempt,
+		arg.ID,
+		arg.Status,
+		arg.ResponseStatus,
+		arg.ResponseBody,
+		arg.DurationMs,
+		arg.LastError,
+		arg.NextAttemptAt,
+	)
+	return err
+}
+
+const forfeitBracketMatchups = `-- name: ForfeitBracketMatchups :many
+UPDATE contest_bracket_matchups
+SET winner_id = CASE WHEN participant_a_id = $1 THEN participant_b_id ELSE participant_a_id END,
+    decided_at = NOW()
+WHERE contest_id = $2 AND decided_at IS NULL
+    AND (participant_a_id = $1 OR participant_b_id = $1)
+RETURNING id
+`
+
+type ForfeitBracketMatchupsParams struct {
+	ParticipantID pgtype.UUID
+	ContestID     pgtype.UUID
+}
+
+// Выбывший участник отдает все нерешенные пары сопернику
+func (q *Queries) ForfeitBracketMatchups(ctx context.Context, arg *ForfeitBracketMatchupsParams) ([]pgtype.UUID, error) {
+	rows, err := q.db.Query(ctx, forfeitBracketMatchups, arg.ParticipantID, arg.ContestID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []pgtype.UUID
+	for rows.Next() {
+		var id pgtype.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAccountDeletionJob = `-- name: GetAccountDeletionJob :one
+SELECT id, user_id, status, attempts, last_error, created_at, started_at, finished_at, updated_at FROM account_deletion_jobs WHERE id = $1
+`
+
+func (q *Queries) GetAccountDeletionJob(ctx context.Context, id pgtype.UUID) (*AccountDeletionJob, error) {
+	row := q.db.QueryRow(ctx, getAccountDeletionJob, id)
+	var i AccountDeletionJob
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Status,
+		&i.Attempts,
+		&i.LastError,
+		&i.CreatedAt,
+		&i.StartedAt,
+		&i.FinishedAt,
+		&i.UpdatedAt,
+	)
+	return &i, err
+}
+
+const getBracketMatchup = `-- name: GetBracketMatchup :one
+SELECT
+    m.id,
+    m.round,
+    m.position,
+    m.participant_a_id,
+    m.participant_b_id,
+    m.seed_a,
+    m.seed_b,
+    m.winner_id,
+    m.decided_at,
+    count(v.user_id) FILTER (WHERE v.participant_id = m.participant_a_id) AS votes_a,
+    count(v.user_id) FILTER (WHERE v.participant_id = m.participant_b_id) AS votes_b
+FROM contest_bracket_matchups m
+LEFT JOIN contest_bracket_votes v ON v.matchup_id = m.id
+WHERE m.contest_id = $1 AND m.id = $2
+GROUP BY m.id
+`
+
+type GetBracketMatchupParams struct {
+	ContestID pgtype.UUID
+	ID        pgtype.UUID
+}
+
+type GetBracketMatchupRow struct {
+	ID             pgtype.UUID
+	Round          int32
+	Position       int32
+	ParticipantAID pgtype.UUID
+	ParticipantBID pgtype.UUID
+	SeedA          int32
+	SeedB          int32
+	WinnerID       pgtype.UUID
+	DecidedAt      pgtype.Timestamptz
+	VotesA         int64
+	VotesB         int64
+}
+
+func (q *Queries) GetBracketMatchup(ctx context.Context, arg *GetBracketMatchupParams) (*GetBracketMatchupRow, error) {
+	row := q.db.QueryRow(ctx, getBracketMatchup, arg.ContestID, arg.ID)
+	var i GetBracketMatchupRow
+	err := row.Scan(
+		&i.ID,
+		&i.Round,
+		&i.Position,
+		&i.ParticipantAID,
+		&i.ParticipantBID,
+		&i.SeedA,
+		&i.SeedB,
+		&i.WinnerID,
+		&i.DecidedAt,
+		&i.VotesA,
+		&i.VotesB,
+	)
+	return &i, err
+}
+
+const getBreedByCode = `-- name: GetBreedByCode :one
+SELECT code, species_code, name_ru, name_en FROM breeds WHERE code = $1
+`
+
+func (q *Queries) GetBreedByCode(ctx context.Context, code string) (*Breed, error) {
+	row := q.db.QueryRow(ctx, getBreedByCode, code)
+	var i Breed
+	err := row.Scan(
+		&i.Code,
+		&i.SpeciesCode,
+		&i.NameRu,
+		&i.NameEn,
+	)
+	return &i, err
+}
+
+const getChatMessageContestID = `-- name: GetChatMessageContestID :one
+SELECT contest_id FROM contest_chat_messages
+WHERE id = $1
+`
+
+func (q *Queries) GetChatMessageContestID(ctx context.Context, id pgtype.UUID) (pgtype.UUID, error) {
+	row := q.db.QueryRow(ctx, getChatMessageContestID, id)
+	var contest_id pgtype.UUID
+	err := row.Scan(&contest_id)
+	return contest_id, err
+}
+
+const getCommentByID = `-- name: GetCommentByID :one
+SELECT id, participant_id, user_id, text, created_at, updated_at, hidden_at FROM contest_comments WHERE id = $1
+`
+
+func (q *Queries) GetCommentByID(ctx context.Context, id pgtype.UUID) (*ContestComment, error) {
+	row := q.db.QueryRow(ctx, getCommentByID, id)
+	var i ContestComment
+	err := row.Scan(
+		&i.ID,
+		&i.ParticipantID,
+		&i.UserID,
+		&i.Text,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.HiddenAt,
+	)
+	return &i, err
+}
+
+const getContestBracket = `-- name: GetContestBracket :one
+
+SELECT contest_id, seeding, round_duration_minutes, status, current_round, champion_participant_id, created_at, updated_at FROM contest_brackets
+WHERE contest_id = $1
+`
+
+// Contest Brackets
+func (q *Queries) GetContestBracket(ctx context.Context, contestID pgtype.UUID) (*ContestBracket, error) {
+	row := q.db.QueryRow(ctx, getContestBracket, contestID)
+	var i ContestBracket
+	err := row.Scan(
+		&i.ContestID,
+		&i.Seeding,
+		&i.RoundDurationMinutes,
+		&i.Status,
+		&i.CurrentRound,
+		&i.ChampionParticipantID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return &i, err
+}
+
+const getContestByID = `-- name: GetContestByID :one
+SELECT id, created_by_user_id, title, description, status, created_at, updated_at, hidden_at, voting_mode, max_choices, min_ballots, jury_weight FROM contests WHERE id = $1
+`
+
+func (q *Queries) GetContestByID(ctx context.Context, id pgtype.UUID) (*Contest, error) {
+	row := q.db.QueryRow(ctx, getContestByID, id)
+	var i Contest
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedByUserID,
+		&i.Title,
+		&i.Description,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.HiddenAt,
+		&i.VotingMode,
+		&i.MaxChoices,
+		&i.MinBallots,
+		&i.JuryWeight,
+	)
+	return &i, err
+}
+
+const getContestCategory = `-- name: GetContestCategory :one
+SELECT id, contest_id, name, description, position, created_at, updated_at FROM contest_categories
+WHERE contest_id = $1 AND id = $2
+`
+
+type GetContestCategoryParams struct {
+	ContestID pgtype.UUID
+	ID        pgtype.UUID
+}
+
+func (q *Queries) GetContestCategory(ctx context.Context, arg *GetContestCategoryParams) (*ContestCategory, error) {
+	row := q.db.QueryRow(ctx, getContestCategory, arg.ContestID, arg.ID)
+	var i ContestCategory
+	err := row.Scan(
+		&i.ID,
+		&i.ContestID,
+		&i.Name,
+		&i.Description,
+		&i.Position,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return &i, err
+}
+
+const getContestEntryRules = `-- name: GetContestEntryRules :one
+
+SELECT contest_id, max_entries_per_user, max_entries, max_photos_per_entry, max_photo_size_mb, video_allowed, max_video_duration_sec, max_video_size_mb, required_fields, entry_deadline, updated_at, require_approval, allowed_species, min_age_months, max_age_months FROM contest_entry_rules
+WHERE contest_id = $1
+`
+
+// Contest Entry Rules
+func (q *Queries) GetContestEntryRules(ctx context.Context, contestID pgtype.UUID) (*ContestEntryRule, error) {
+	row := q.db.QueryRow(ctx, getContestEntryRules, contestID)
+	var i ContestEntryRule
+	err := row.Scan(
+		&i.ContestID,
+		&i.MaxEntriesPerUser,
+		&i.MaxEntries,
+		&i.MaxPhotosPerEntry,
+		&i.MaxPhotoSizeMb,
+		&i.VideoAllowed,
+		&i.MaxVideoDurationSec,
+		&i.MaxVideoSizeMb,
+		&i.RequiredFields,
+		&i.EntryDeadline,
+		&i.UpdatedAt,
+		&i.RequireApproval,
+		&i.AllowedSpecies,
+		&i.MinAgeMonths,
+		&i.MaxAgeMonths,
+	)
+	return &i, err
+}
+
+const getContestMember = `-- name: GetContestMember :one
+SELECT contest_id, user_id, role, status, invited_by_user_id, created_at, accepted_at FROM contest_members
+WHERE contest_id = $1 AND user_id = $2
+`
+
+type GetContestMemberParams struct {
+	ContestID pgtype.UUID
+	UserID    int64
+}
+
+func (q *Queries) GetContestMember(ctx context.Context, arg *GetContestMemberParams) (*ContestMember, error) {
+	row := q.db.QueryRow(ctx, getContestMember, arg.ContestID, arg.UserID)
+	var i ContestMember
+	err := row.Scan(
+		&i.ContestID,
+		&i.UserID,
+		&i.Role,
+		&i.Status,
+		&i.InvitedByUserID,
+		&i.CreatedAt,
+		&i.AcceptedAt,
+	)
+	return &i, err
+}
+
+const getContestVoteByUser = `-- name: GetContestVoteByUser :one
+SELECT id, contest_id, participant_id, user_id, created_at, updated_at, ip_hash, user_agent, auth_provider, account_created_at, fraud_score, fraud_reasons, flagged_at, voided_at, voided_by_user_id, void_reason, category_id, recastable FROM contest_votes
+WHERE contest_id = $1 AND user_id = $2 AND category_id IS NOT DISTINCT FROM $3
+`
+
+type GetContestVoteByUserParams struct {
+	ContestID  pgtype.UUID
+	UserID     int64
+	CategoryID pgtype.UUID
+}
+
+func (q *Queries) GetContestVoteByUser(ctx context.Context, arg *GetContestVoteByUserParams) (*ContestVote, error) {
+	row := q.db.QueryRow(ctx, getContestVoteByUser, arg.ContestID, arg.UserID, arg.CategoryID)
+	var i ContestVote
+	err := row.Scan(
+		&i.ID,
+		&i.ContestID,
+		&i.ParticipantID,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.IpHash,
+		&i.UserAgent,
+		&i.AuthProvider,
+		&i.AccountCreatedAt,
+		&i.FraudScore,
+		&i.FraudReasons,
+		&i.FlaggedAt,
+		&i.VoidedAt,
+		&i.VoidedByUserID,
+		&i.VoidReason,
+		&i.CategoryID,
+		&i.Recastable,
+	)
+	return &i, err
+}
+
+const getContestVotingPolicy = `-- name: GetContestVotingPolicy :one
+
+SELECT contest_id, min_account_age_hours, required_providers, exclude_own_participants, invite_only, updated_at FROM contest_voting_policies
+WHERE contest_id = $1
+`
+
+// Contest Voting Policies
+func (q *Queries) GetContestVotingPolicy(ctx context.Context, contestID pgtype.UUID) (*ContestVotingPolicy, error) {
+	row := q.db.QueryRow(ctx, getContestVotingPolicy, contestID)
+	var i ContestVotingPolicy
+	err := row.Scan(
+		&i.ContestID,
+		&i.MinAccountAgeHours,
+		&i.RequiredProviders,
+		&i.ExcludeOwnParticipants,
+		&i.InviteOnly,
+		&i.UpdatedAt,
+	)
+	return &i, err
+}
+
+const getLatestProviderAvatar = `-- name: GetLatestProviderAvatar :one
+SELECT avatar_url FROM user_auth_providers
+WHERE user_id = $1 AND avatar_url IS NOT NULL
+ORDER BY avatar_updated_at DESC NULLS LAST
+LIMIT 1
+`
+
+func (q *Queries) GetLatestProviderAvatar(ctx context.Context, userID int64) (*string, error) {
+	row := q.db.QueryRow(ctx, getLatestProviderAvatar, userID)
+	var avatar_url *string
+	err := row.Scan(&avatar_url)
+	return avatar_url, err
+}
+
+const getMaxPhotoPositionByParticipant = `-- name: GetMaxPhotoPositionByParticipant :one
+SELECT COALESCE(MAX(position), 0) AS max_position
+FROM contest_participant_photos
+WHERE participant_id = $1
+`
+
+func (q *Queries) GetMaxPhotoPositionByParticipant(ctx context.Context, participantID pgtype.UUID) (interface{}, error) {
+	row := q.db.QueryRow(ctx, getMaxPhotoPositionByParticipant, participantID)
+	var max_position interface{}
+	err := row.Scan(&max_position)
+	return max_position, err
+}
+
+const getNotificationPreferences = `-- name: GetNotificationPreferences :one
+
+SELECT user_id, email, email_voting_started, email_results_published, updated_at FROM notification_preferences
+WHERE user_id = $1
+`
+
+// Email Notifications
+func (q *Queries) GetNotificationPreferences(ctx context.Context, userID int64) (*NotificationPreference, error) {
+	row := q.db.QueryRow(ctx, getNotificationPreferences, userID)
+	var i NotificationPreference
+	err := row.Scan(
+		&i.UserID,
+		&i.Email,
+		&i.EmailVotingStarted,
+		&i.EmailResultsPublished,
+		&i.UpdatedAt,
+	)
+	return &i, err
+}
+
+const getParticipantByContestAndUser = `-- name: GetParticipantByContestAndUser :one
+SELECT
+    cp.id,
+    cp.contest_id,
+    cp.user_id,
+    COALESCE(u.name, 'Пользователь ' || cp.user_id::text) AS user_name,
+    cp.pet_name,
+    cp.pet_description,
+    cp.created_at,
+    cp.updated_at
+FROM contest_participants cp
+LEFT JOIN users u ON u.user_id = cp.user_id
+WHERE cp.contest_id = $1 AND cp.user_id = $2
+`
+
+type GetParticipantByContestAndUserParams struct {
+	ContestID pgtype.UUID
+	UserID    int64
+}
+
+type GetParticipantByContestAndUserRow struct {
+	ID             pgtype.UUID
+	ContestID      pgtype.UUID
+	UserID         int64
+	UserName       string
+	PetName        string
+	PetDescription string
+	CreatedAt      pgtype.Timestamptz
+	UpdatedAt      pgtype.Timestamptz
+}
+
+func (q *Queries) GetParticipantByContestAndUser(ctx context.Context, arg *GetParticipantByContestAndUserParams) (*GetParticipantByContestAndUserRow, error) {
+	row := q.db.QueryRow(ctx, getParticipantByContestAndUser, arg.ContestID, arg.UserID)
+	var i GetParticipantByContestAndUserRow
+	err := row.Scan(
+		&i.ID,
+		&i.ContestID,
+		&i.UserID,
+		&i.UserName,
+		&i.PetName,
+		&i.PetDescription,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return &i, err
+}
+
+const getParticipantByID = `-- name: GetParticipantByID :one
+SELECT
+    cp.id,
+    cp.contest_id,
+    cp.user_id,
+    COALESCE(u.name, 'Пользователь ' || cp.user_id::text) AS user_name,
+    cp.pet_name,
+    cp.pet_description,
+    cp.created_at,
+    cp.updated_at,
+    cp.hidden_at,
+    cp.moderation_status,
+    cp.moderation_reason,
+    cp.moderated_at,
+    cp.disqualified_at,
+    cp.disqualification_reason,
+    cp.pet_id,
+    cp.species,
+    cp.breed,
+    cp.sex,
+    cp.birth_date
+FROM contest_participants cp
+LEFT JOIN users u ON u.user_id = cp.user_id
+WHERE cp.id = $1
+`
+
+type GetParticipantByIDRow struct {
+	ID                     pgtype.UUID
+	ContestID              pgtype.UUID
+	UserID                 int64
+	UserName               string
+	PetName                string
+	PetDescription         string
+	CreatedAt              pgtype.Timestamptz
+	UpdatedAt              pgtype.Timestamptz
+	HiddenAt               pgtype.Timestamptz
+	ModerationStatus       string
+	ModerationReason       string
+	ModeratedAt            pgtype.Timestamptz
+	DisqualifiedAt         pgtype.Timestamptz
+	DisqualificationReason string
+	PetID                  pgtype.UUID
+	Species                *string
+	Breed                  *string
+	Sex                    *string
+	BirthDate              pgtype.Date
+}
+
+func (q *Queries) GetParticipantByID(ctx context.Context, id pgtype.UUID) (*GetParticipantByIDRow, error) {
+	row := q.db.QueryRow(ctx, getParticipantByID, id)
+	var i GetParticipantByIDRow
+	err := row.Scan(
+		&i.ID,
+		&i.ContestID,
+		&i.UserID,
+		&i.UserName,
+		&i.PetName,
+		&i.PetDescription,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.HiddenAt,
+		&i.ModerationStatus,
+		&i.ModerationReason,
+		&i.ModeratedAt,
+		&i.DisqualifiedAt,
+		&i.DisqualificationReason,
+		&i.PetID,
+		&i.Species,
+		&i.Breed,
+		&i.Sex,
+		&i.BirthDate,
+	)
+	return &i, err
+}
+
+const getPetByID = `-- name: GetPetByID :one
+SELECT id, owner_user_id, name, species, breed, birth_date, bio, created_at, updated_at, sex FROM pets WHERE id = $1
+`
+
+func (q *Queries) GetPetByID(ctx context.Context, id pgtype.UUID) (*Pet, error) {
+	row := q.db.QueryRow(ctx, getPetByID, id)
+	var i Pet
+	err := row.Scan(
+		&i.ID,
+		&i.OwnerUserID,
+		&i.Name,
+		&i.Species,
+		&i.Breed,
+		&i.BirthDate,
+		&i.Bio,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Sex,
+	)
+	return &i, err
+}
+
+const getPhotoLikeByUser = `-- name: GetPhotoLikeByUser :one
+SELECT id, photo_id, user_id, created_at FROM photo_likes
+WHERE photo_id = $1 AND user_id = $2
+`
+
+type GetPhotoLikeByUserParams struct {
+	PhotoID pgtype.UUID
+	UserID  int64
+}
+
+func (q *Queries) GetPhotoLikeByUser(ctx context.Context, arg *GetPhotoLikeByUserParams) (*PhotoLike, error) {
+	row := q.db.QueryRow(ctx, getPhotoLikeByUser, arg.PhotoID, arg.UserID)
+	var i PhotoLike
+	err := row.Scan(
+		&i.ID,
+		&i.PhotoID,
+		&i.UserID,
+		&i.CreatedAt,
+	)
+	return &i, err
+}
+
+const getPhotosByParticipantID = `-- name: GetPhotosByParticipantID :many
+SELECT id, participant_id, url, thumb_url, created_at, position FROM contest_participant_photos
+WHERE participant_id = $1
+ORDER BY position ASC, created_at ASC
+`
+
+func (q *Queries) GetPhotosByParticipantID(ctx context.Context, participantID pgtype.UUID) ([]*ContestParticipantPhoto, error) {
+	rows, err := q.db.Query(ctx, getPhotosByParticipantID, participantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*ContestParticipantPhoto
+	for rows.Next() {
+		var i ContestParticipantPhoto
+		if err := rows.Scan(
+			&i.ID,
+			&i.ParticipantID,
+			&i.Url,
+			&i.ThumbUrl,
+			&i.CreatedAt,
+			&i.Position,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTelegramAccount = `-- name: GetTelegramAccount :one
+SELECT user_id, chat_id, username, linked_at FROM telegram_accounts
+WHERE user_id = $1
+`
+
+func (q *Queries) GetTelegramAccount(ctx context.Context, userID int64) (*TelegramAccount, error) {
+	row := q.db.QueryRow(ctx, getTelegramAccount, userID)
+	var i TelegramAccount
+	err := row.Scan(
+		&i.UserID,
+		&i.ChatID,
+		&i.Username,
+		&i.LinkedAt,
+	)
+	return &i, err
+}
+
+const getTelegramAccountByChat = `-- name: GetTelegramAccountByChat :one
+SELECT user_id, chat_id, username, linked_at FROM telegram_accounts
+WHERE chat_id = $1
+`
+
+func (q *Queries) GetTelegramAccountByChat(ctx context.Context, chatID int64) (*TelegramAccount, error) {
+	row := q.db.QueryRow(ctx, getTelegramAccountByChat, chatID)
+	var i TelegramAccount
+	err := row.Scan(
+		&i.UserID,
+		&i.ChatID,
+		&i.Username,
+		&i.LinkedAt,
+	)
+	return &i, err
+}
+
+const getUserAuthProvidersByProviderUid = `-- name: GetUserAuthProvidersByProviderUid :one
+SELECT user_id, provider_uid, provider, name FROM user_auth_providers
+WHERE provider_uid = $1 AND provider = $2
+`
+
+type GetUserAuthProvidersByProviderUidParams struct {
+	ProviderUid string
+	Provider    string
+}
+
+func (q *Queries) GetUserAuthProvidersByProviderUid(ctx context.Context, arg *GetUserAuthProvidersByProviderUidParams) (*UserAuthProvider, error) {
+	row := q.db.QueryRow(ctx, getUserAuthProvidersByProviderUid, arg.ProviderUid, arg.Provider)
+	var i UserAuthProvider
+	err := row.Scan(
+		&i.UserID,
+		&i.ProviderUid,
+		&i.Provider,
+		&i.Name,
+	)
+	return &i, err
+}
+
+const getUserAuthProvidersByUserID = `-- name: GetUserAuthProvidersByUserID :many
+SELECT user_id, provider_uid, provider, name, avatar_url, avatar_updated_at FROM user_auth_providers
+WHERE user_id = $1
+`
+
+func (q *Queries) GetUserAuthProvidersByUserID(ctx context.Context, userID int64) ([]*UserAuthProvider, error) {
+	rows, err := q.db.Query(ctx, getUserAuthProvidersByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*UserAuthProvider
+	for rows.Next() {
+		var i UserAuthProvider
+		if err := rows.Scan(
+			&i.UserID,
+			&i.ProviderUid,
+			&i.Provider,
+			&i.Name,
+			&i.AvatarUrl,
+			&i.AvatarUpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserByID = `-- name: GetUserByID :one
+SELECT user_id, name, created_at, avatar_url, bio, avatar_thumb_url, locale, deleted_at FROM users
+WHERE user_id = $1
+`
+
+func (q *Queries) GetUserByID(ctx context.Context, userID int64) (*User, error) {
+	row := q.db.QueryRow(ctx, getUserByID, userID)
+	var i User
+	err := row.Scan(
+		&i.UserID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.AvatarUrl,
+		&i.Bio,
+		&i.AvatarThumbUrl,
+		&i.Locale,
+		&i.DeletedAt,
+	)
+	return &i, err
+}
+
+const getUserPrivacySettings = `-- name: GetUserPrivacySettings :one
+
+SELECT user_id, show_contests, show_pets, show_results, updated_at FROM user_privacy_settings
+WHERE user_id = $1
+`
+
+// User Privacy Settings
+func (q *Queries) GetUserPrivacySettings(ctx context.Context, userID int64) (*UserPrivacySetting, error) {
+	row := q.db.QueryRow(ctx, getUserPrivacySettings, userID)
+	var i UserPrivacySetting
+	err := row.Scan(
+		&i.UserID,
+		&i.ShowContests,
+		&i.ShowPets,
+		&i.ShowResults,
+		&i.UpdatedAt,
+	)
+	return &i, err
+}
+
+const getVideoByParticipantID = `-- name: GetVideoByParticipantID :one
+SELECT id, participant_id, url, created_at FROM contest_participant_videos
+WHERE participant_id = $1
+`
+
+func (q *Queries) GetVideoByParticipantID(ctx context.Context, participantID pgtype.UUID) (*ContestParticipantVideo, error) {
+	row := q.db.QueryRow(ctx, getVideoByParticipantID, participantID)
+	var i ContestParticipantVideo
+	err := row.Scan(
+		&i.ID,
+		&i.ParticipantID,
+		&i.Url,
+		&i.CreatedAt,
+	)
+	return &i, err
+}
+
+const getWebhookDelivery = `-- name: GetWebhookDelivery :one
+SELECT d.id, d.endpoint_id, d.event_id, d.redelivery, d.status, d.attempts, d.response_status, d.response_body, d.duration_ms, d.last_error, d.next_attempt_at, d.last_attempt_at, d.created_at, d.delivered_at, ev.type AS event_type
+FROM webhook_deliveries d
+JOIN webhook_events ev ON ev.id = d.event_id
+WHERE d.id = $1
+`
+
+type GetWebhookDeliveryRow struct {
+	ID             pgtype.UUID
+	EndpointID     pgtype.UUID
+	EventID        pgtype.UUID
+	Redelivery     bool
+	Status         string
+	Attempts       int32
+	ResponseStatus *int32
+	ResponseBody   string
+	DurationMs     int32
+	LastError      string
+	NextAttemptAt  pgtype.Timestamptz
+	LastAttemptAt  pgtype.Timestamptz
+	CreatedAt      pgtype.Timestamptz
+	DeliveredAt    pgtype.Timestamptz
+	EventType      string
+}
+
+func (q *Queries) GetWebhookDelivery(ctx context.Context, id pgtype.UUID) (*GetWebhookDeliveryRow, error) {
+	row := q.db.QueryRow(ctx, getWebhookDelivery, id)
+	var i GetWebhookDeliveryRow
+	err := row.Scan(
+		&i.ID,
+		&i.EndpointID,
+		&i.EventID,
+		&i.Redelivery,
+		&i.Status,
+		&i.Attempts,
+		&i.ResponseStatus,
+		&i.ResponseBody,
+		&i.DurationMs,
+		&i.LastError,
+		&i.NextAttemptAt,
+		&i.LastAttemptAt,
+		&i.CreatedAt,
+		&i.DeliveredAt,
+		&i.EventType,
+	)
+	return &i, err
+}
+
+const getWebhookEndpoint = `-- name: GetWebhookEndpoint :one
+SELECT id, contest_id, url, secret, event_types, active, created_by_user_id, created_at, updated_at FROM webhook_endpoints
+WHERE id = $1
+`
+
+func (q *Queries) GetWebhookEndpoint(ctx context.Context, id pgtype.UUID) (*WebhookEndpoint, error) {
+	row := q.db.QueryRow(ctx, getWebhookEndpoint, id)
+	var i WebhookEndpoint
+	err := row.Scan(
+		&i.ID,
+		&i.ContestID,
+		&i.Url,
+		&i.Secret,
+		&i.EventTypes,
+		&i.Active,
+		&i.CreatedByUserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return &i, err
+}
+
+const isContestInvitedVoter = `-- name: IsContestInvitedVoter :one
+SELECT EXISTS (
+    SELECT 1 FROM contest_invited_voters
+    WHERE contest_id = $1 AND user_id = $2
+)
+`
+
+type IsContestInvitedVoterParams struct {
+	ContestID pgtype.UUID
+	UserID    int64
+}
+
+func (q *Queries) IsContestInvitedVoter(ctx context.Context, arg *IsContestInvitedVoterParams) (bool, error) {
+	row := q.db.QueryRow(ctx, isContestInvitedVoter, arg.ContestID, arg.UserID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const linkTelegramAccount = `-- name: LinkTelegramAccount :one
+INSERT INTO telegram_accounts (user_id, chat_id, username)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id) DO UPDATE
+SET chat_id = EXCLUDED.chat_id, username = EXCLUDED.username, linked_at = NOW()
+RETURNING user_id, chat_id, username, linked_at
+`
+
+type LinkTelegramAccountParams struct {
+	UserID   int64
+	ChatID   int64
+	Username string
+}
+
+func (q *Queries) LinkTelegramAccount(ctx context.Context, arg *LinkTelegramAccountParams) (*TelegramAccount, error) {
+	row := q.db.QueryRow(ctx, linkTelegramAccount, arg.UserID, arg.ChatID, arg.Username)
+	var i TelegramAccount
+	err := row.Scan(
+		&i.UserID,
+		&i.ChatID,
+		&i.Username,
+		&i.LinkedAt,
+	)
+	return &i, err
+}
+
+const listActiveUserRoles = `-- name: ListActiveUserRoles :one
+SELECT COALESCE(array_agg(r.role ORDER BY r.role) FILTER (WHERE r.role IS NOT NULL), '{}')::text[] AS roles
+FROM users u
+LEFT JOIN user_roles r ON r.user_id = u.user_id
+WHERE u.user_id = $1 AND u.deleted_at IS NULL
+GROUP BY u.user_id
+`
+
+// Роли пользователя для проверки access токена; удаленный аккаунт не возвращает строк
+func (q *Queries) ListActiveUserRoles(ctx context.Context, userID int64) ([]string, error) {
+	row := q.db.QueryRow(ctx, listActiveUserRoles, userID)
+	var roles []string
+	err := row.Scan(&roles)
+	return roles, err
+}
+
+const listBracketMatchups = `-- name: ListBracketMatchups :many
+SELECT
+    m.id,
+    m.round,
+    m.position,
+    m.participant_a_id,
+    m.participant_b_id,
+    m.seed_a,
+    m.seed_b,
+    m.winner_id,
+    m.decided_at,
+    count(v.user_id) FILTER (WHERE v.participant_id = m.participant_a_id) AS votes_a,
+    count(v.user_id) FILTER (WHERE v.participant_id = m.participant_b_id) AS votes_b
+FROM contest_bracket_matchups m
+LEFT JOIN contest_bracket_votes v ON v.matchup_id = m.id
+WHERE m.contest_id = $1
+GROUP BY m.id
+ORDER BY m.round ASC, m.position ASC
+`
+
+type ListBracketMatchupsRow struct {
+	ID             pgtype.UUID
+	Round          int32
+	Position       int32
+	ParticipantAID pgtype.UUID
+	ParticipantBID pgtype.UUID
+	SeedA          int32
+	SeedB          int32
+	WinnerID       pgtype.UUID
+	DecidedAt      pgtype.Timestamptz
+	VotesA         int64
+	VotesB         int64
+}
+
+func (q *Queries) ListBracketMatchups(ctx context.Context, contestID pgtype.UUID) ([]*ListBracketMatchupsRow, error) {
+	rows, err := q.db.Query(ctx, listBracketMatchups, contestID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*ListBracketMatchupsRow
+	for rows.Next() {
+		var i ListBracketMatchupsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Round,
+			&i.Position,
+			&i.ParticipantAID,
+			&i.ParticipantBID,
+			&i.SeedA,
+			&i.SeedB,
+			&i.WinnerID,
+			&i.DecidedAt,
+			&i.VotesA,
+			&i.VotesB,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBracketRounds = `-- name: ListBracketRounds :many
+SELECT contest_id, round, status, starts_at, ends_at FROM contest_bracket_rounds
+WHERE contest_id = $1
+ORDER BY round ASC
+`
+
+func (q *Queries) ListBracketRounds(ctx context.Context, contestID pgtype.UUID) ([]*ContestBracketRound, error) {
+	rows, err := q.db.Query(ctx, listBracketRounds, contestID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*ContestBracketRound
+	for rows.Next() {
+		var i ContestBracketRound
+		if err := rows.Scan(
+			&i.ContestID,
+			&i.Round,
+			&i.Status,
+			&i.StartsAt,
+			&i.EndsAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBracketVotesByUser = `-- name: ListBracketVotesByUser :many
+SELECT v.matchup_id, v.participant_id
+FROM contest_bracket_votes v
+JOIN contest_bracket_matchups m ON m.id = v.matchup_id
+WHERE m.contest_id = $1 AND v.user_id = $2
+`
+
+type ListBracketVotesByUserParams struct {
+	ContestID pgtype.UUID
+	UserID    int64
+}
+
+type ListBracketVotesByUserRow struct {
+	MatchupID     pgtype.UUID
+	ParticipantID pgtype.UUID
+}
+
+func (q *Queries) ListBracketVotesByUser(ctx context.Context, arg *ListBracketVotesByUserParams) ([]*ListBracketVotesByUserRow, error) {
+	rows, err := q.db.Query(ctx, listBracketVotesByUser, arg.ContestID, arg.UserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*ListBracketVotesByUserRow
+	for rows.Next() {
+		var i ListBracketVotesByUserRow
+		if err := rows.Scan(&i.MatchupID, &i.ParticipantID); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBreedsBySpecies = `-- name: ListBreedsBySpecies :many
+SELECT code, species_code, name_ru, name_en FROM breeds
+WHERE species_code = $1
+ORDER BY name_ru ASC
+`
+
+func (q *Queries) ListBreedsBySpecies(ctx context.Context, speciesCode string) ([]*Breed, error) {
+	rows, err := q.db.Query(ctx, listBreedsBySpecies, speciesCode)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*Breed
+	for rows.Next() {
+		var i Breed
+		if err := rows.Scan(
+			&i.Code,
+			&i.SpeciesCode,
+			&i.NameRu,
+			&i.NameEn,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listChatMessages = `-- name: ListChatMessages :many
+SELECT 
+    ccm.id,
+    ccm.contest_id,
+    ccm.user_id,
+    ccm.text,
+    ccm.is_system,
+    ccm.created_at,
+    ccm.updated_at,
+    COALESCE(u.name, 'Пользователь ' || ccm.user_id::text) as user_name
+FROM contest_chat_messages ccm
+LEFT JOIN users u ON u.user_id = ccm.user_id
+WHERE ccm.contest_id = $1 AND ccm.hidden_at IS NULL
+ORDER BY ccm.created_at ASC
+LIMIT $2 OFFSET $3
+`
+
+type ListChatMessagesParams struct {
+	ContestID pgtype.UUID
+	Limit     int32
+	Offset    int32
+}
+
+type ListChatMessagesRow struct {
+	ID        pgtype.UUID
+	ContestID pgtype.UUID
+	UserID    int64
+	Text      string
+	IsSystem  bool
+	CreatedAt pgtype.Timestamptz
+	UpdatedAt pgtype.Timestamptz
+	UserName  string
+}
+
+func (q *Queries) ListChatMessages(ctx context.Context, arg *ListChatMessagesParams) ([]*ListChatMessagesRow, error) {
+	rows, err := q.db.Query(ctx, listChatMessages, arg.ContestID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*ListChatMessagesRow
+	for rows.Next() {
+		var i ListChatMessagesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ContestID,
+			&i.UserID,
+			&i.Text,
+			&i.IsSystem,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.UserName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listChatMessagesByUser = `-- name: ListChatMessagesByUser :many
+SELECT id, contest_id, user_id, text, is_system, created_at, updated_at, hidden_at FROM contest_chat_messages
+WHERE user_id = $1 AND NOT is_system
+ORDER BY created_at DESC
+`
+
+func (q *Queries) ListChatMessagesByUser(ctx context.Context, userID int64) ([]*ContestChatMessage, error) {
+	rows, err := q.db.Query(ctx, listChatMessagesByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*ContestChatMessage
+	for rows.Next() {
+		var i ContestChatMessage
+		if err := rows.Scan(
+			&i.ID,
+			&i.ContestID,
+			&i.UserID,
+			&i.Text,
+			&i.IsSystem,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.HiddenAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCommentsByParticipant = `-- name: ListCommentsByParticipant :many
+SELECT
+    cc.id,
+    cc.participant_id,
+    cc.user_id,
+    cc.text,
+    cc.created_at,
+    cc.updated_at,
+    COALESCE(u.name, 'Пользователь ' || cc.user_id::text) AS user_name
+FROM contest_comments cc
+LEFT JOIN users u ON u.user_id = cc.user_id
+WHERE cc.participant_id = $1 AND cc.hidden_at IS NULL
+ORDER BY cc.created_at ASC
+LIMIT $2 OFFSET $3
+`
+
+type ListCommentsByParticipantParams struct {
+	ParticipantID pgtype.UUID
+	Limit         int32
+	Offset        int32
+}
+
+type ListCommentsByParticipantRow struct {
+	ID            pgtype.UUID
+	ParticipantID pgtype.UUID
+	UserID        int64
+	Text          string
+	CreatedAt     pgtype.Timestamptz
+	UpdatedAt     pgtype.Timestamptz
+	UserName      string
+}
+
+func (q *Queries) ListCommentsByParticipant(ctx context.Context, arg *ListCommentsByParticipantParams) ([]*ListCommentsByParticipantRow, error) {
+	rows, err := q.db.Query(ctx, listCommentsByParticipant, arg.ParticipantID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*ListCommentsByParticipantRow
+	for rows.Next() {
+		var i ListCommentsByParticipantRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ParticipantID,
+			&i.UserID,
+			&i.Text,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.UserName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCommentsByUser = `-- name: ListCommentsByUser :many
+SELECT id, participant_id, user_id, text, created_at, updated_at, hidden_at FROM contest_comments
+WHERE user_id = $1
+ORDER BY created_at DESC
+`
+
+func (q *Queries) ListCommentsByUser(ctx context.Context, userID int64) ([]*ContestComment, error) {
+	rows, err := q.db.Query(ctx, listCommentsByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*ContestComment
+	for rows.Next() {
+		var i ContestComment
+		if err := rows.Scan(
+			&i.ID,
+			&i.ParticipantID,
+			&i.UserID,
+			&i.Text,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.HiddenAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listContestBallotChoices = `-- name: ListContestBallotChoices :many
+SELECT
+    cv.id AS vote_id,
+    COALESCE(c.participant_id, cv.participant_id)::uuid AS participant_id,
+    COALESCE(c.rank, 0)::int AS rank,
+    c.stars
+FROM contest_votes cv
+LEFT JOIN contest_vote_choices c ON c.vote_id = cv.id
+WHERE cv.contest_id = $1 AND cv.category_id IS NOT DISTINCT FROM $2 AND cv.voided_at IS NULL
+ORDER BY cv.id, rank
+`
+
+type ListContestBallotChoicesParams struct {
+	ContestID  pgtype.UUID
+	CategoryID pgtype.UUID
+}
+
+type ListContestBallotChoicesRow struct {
+	VoteID        pgtype.UUID
+	ParticipantID pgtype.UUID
+	Rank          int32
+	Stars         *int32
+}
+
+func (q *Queries) ListContestBallotChoices(ctx context.Context, arg *ListContestBallotChoicesParams) ([]*ListContestBallotChoicesRow, error) {
+	rows, err := q.db.Query(ctx, listContestBallotChoices, arg.ContestID, arg.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*ListContestBallotChoicesRow
+	for rows.Next() {
+		var i ListContestBallotChoicesRow
+		if err := rows.Scan(
+			&i.VoteID,
+			&i.ParticipantID,
+			&i.Rank,
+			&i.Stars,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listContestCategories = `-- name: ListContestCategories :many
+SELECT id, contest_id, name, description, position, created_at, updated_at FROM contest_categories
+WHERE contest_id = $1
+ORDER BY position ASC, created_at ASC
+`
+
+func (q *Queries) ListContestCategories(ctx context.Context, contestID pgtype.UUID) ([]*ContestCategory, error) {
+	rows, err := q.db.Query(ctx, listContestCategories, contestID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*ContestCategory
+	for rows.Next() {
+		var i ContestCategory
+		if err := rows.Scan(
+			&i.ID,
+			&i.ContestID,
+			&i.Name,
+			&i.Description,
+			&i.Position,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listContestIDsWithVotesSince = `-- name: ListContestIDsWithVotesSince :many
+SELECT DISTINCT contest_id FROM contest_votes
+WHERE updated_at > $1 AND voided_at IS NULL
+`
+
+func (q *Queries) ListContestIDsWithVotesSince(ctx context.Context, updatedAt pgtype.Timestamptz) ([]pgtype.UUID, error) {
+	rows, err := q.db.Query(ctx, listContestIDsWithVotesSince, updatedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []pgtype.UUID
+	for rows.Next() {
+		var contest_id pgtype.UUID
+		if err := rows.Scan(&contest_id); err != nil {
+			return nil, err
+		}
+		items = append(items, contest_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listContestInvitedVoters = `-- name: ListContestInvitedVoters :many
+SELECT
+    iv.user_id,
+    u.name AS user_name,
+    iv.added_by_user_id,
+    iv.created_at
+FROM contest_invited_voters iv
+JOIN users u ON u.user_id = iv.user_id
+WHERE iv.contest_id = $1
+ORDER BY iv.created_at ASC
+`
+
+type ListContestInvitedVotersRow struct {
+	UserID        int64
+	UserName      string
+	AddedByUserID *int64
+	CreatedAt     pgtype.Timestamptz
+}
+
+func (q *Queries) ListContestInvitedVoters(ctx context.Context, contestID pgtype.UUID) ([]*ListContestInvitedVotersRow, error) {
+	rows, err := q.db.Query(ctx, listContestInvitedVoters, contestID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*ListContestInvitedVotersRow
+	for rows.Next() {
+		var i ListContestInvitedVotersRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.UserName,
+			&i.AddedByUserID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listContestMembers = `-- name: ListContestMembers :many
+SELECT
+    cm.contest_id,
+    cm.user_id,
+    cm.role,
+    cm.status,
+    cm.invited_by_user_id,
+    cm.created_at,
+    cm.accepted_at,
+    u.name AS user_name
+FROM contest_members cm
+JOIN users u ON u.user_id = cm.user_id
+WHERE cm.contest_id = $1
+ORDER BY cm.created_at ASC
+`
+
+type ListContestMembersRow struct {
+	ContestID       pgtype.UUID
+	UserID          int64
+	Role            string
+	Status          string
+	InvitedByUserID *int64
+	CreatedAt       pgtype.Timestamptz
+	AcceptedAt      pgtype.Timestamptz
+	UserName        string
+}
+
+func (q *Queries) ListContestMembers(ctx context.Context, contestID pgtype.UUID) ([]*ListContestMembersRow, error) {
+	rows, err := q.db.Query(ctx, listContestMembers, contestID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*ListContestMembersRow
+	for rows.Next() {
+		var i ListContestMembersRow
+		if err := rows.Scan(
+			&i.ContestID,
+			&i.UserID,
+			&i.Role,
+			&i.Status,
+			&i.InvitedByUserID,
+			&i.CreatedAt,
+			&i.AcceptedAt,
+			&i.UserName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listContests = `-- name: ListContests :many
+SELECT id, created_by_user_id, title, description, status, created_at, updated_at, hidden_at, voting_mode, max_choices, min_ballots, jury_weight FROM contests
+WHERE (COALESCE($1::text, '') = '' OR status = $1) AND hidden_at IS NULL
+ORDER BY created_at DESC
+LIMIT $2 OFFSET $3
+`
+
+type ListContestsParams struct {
+	Column1 string
+	Limit   int32
+	Offset  int32
+}
+
+func (q *Queries) ListContests(ctx context.Context, arg *ListContestsParams) ([]*Contest, error) {
+	rows, err := q.db.Query(ctx, listContests, arg.Column1, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*Contest
+	for rows.Next() {
+		var i Contest
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedByUserID,
+			&i.Title,
+			&i.Description,
+			&i.Status,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.HiddenAt,
+			&i.VotingMode,
+			&i.MaxChoices,
+			&i.MinBallots,
+			&i.JuryWeight,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listContestsByCreator = `-- name: ListContestsByCreator :many
+SELECT id, created_by_user_id, title, description, status, created_at, updated_at, hidden_at, voting_mode, max_choices, min_ballots, jury_weight FROM contests
+WHERE created_by_user_id = $1
+ORDER BY created_at DESC
+`
+
+func (q *Queries) ListContestsByCreator(ctx context.Context, createdByUserID int64) ([]*Contest, error) {
+	rows, err := q.db.Query(ctx, listContestsByCreator, createdByUserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*Contest
+	for rows.Next() {
+		var i Contest
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedByUserID,
+			&i.Title,
+			&i.Description,
+			&i.Status,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.HiddenAt,
+			&i.VotingMode,
+			&i.MaxChoices,
+			&i.MinBallots,
+			&i.JuryWeight,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listContestVoteChoices = `-- name: ListContestVoteChoices :many
+SELECT participant_id, rank, stars FROM contest_vote_choices
+WHERE vote_id = $1
+ORDER BY rank, participant_id
+`
+
+type ListContestVoteChoicesRow struct {
+	ParticipantID pgtype.UUID
+	Rank          int32
+	Stars         *int32
+}
+
+func (q *Queries) ListContestVoteChoices(ctx context.Context, voteID pgtype.UUID) ([]*ListContestVoteChoicesRow, error) {
+	rows, err := q.db.Query(ctx, listContestVoteChoices, voteID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*ListContestVoteChoicesRow
+	for rows.Next() {
+		var i ListContestVoteChoicesRow
+		if err := rows.Scan(&i.ParticipantID, &i.Rank, &i.Stars); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listContestVotesByUser = `-- name: ListContestVotesByUser :many
+SELECT id, contest_id, participant_id, user_id, created_at, updated_at, ip_hash, user_agent, auth_provider, account_created_at, fraud_score, fraud_reasons, flagged_at, voided_at, voided_by_user_id, void_reason, category_id, recastable FROM contest_votes
+WHERE user_id = $1
+ORDER BY created_at DESC
+`
+
+func (q *Queries) ListContestVotesByUser(ctx context.Context, userID int64) ([]*ContestVote, error) {
+	rows, err := q.db.Query(ctx, listContestVotesByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*ContestVote
+	for rows.Next() {
+		var i ContestVote
+		if err := rows.Scan(
+			&i.ID,
+			&i.ContestID,
+			&i.ParticipantID,
+			&i.UserID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.IpHash,
+			&i.UserAgent,
+			&i.AuthProvider,
+			&i.AccountCreatedAt,
+			&i.FraudScore,
+			&i.FraudReasons,
+			&i.FlaggedAt,
+			&i.VoidedAt,
+			&i.VoidedByUserID,
+			&i.VoidReason,
+			&i.CategoryID,
+			&i.Recastable,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listContestVotesForFraudScoring = `-- name: ListContestVotesForFraudScoring :many
+SELECT id, participant_id, user_id, ip_hash, user_agent, account_created_at, fraud_score, fraud_reasons, updated_at
+FROM contest_votes
+WHERE contest_id = $1 AND voided_at IS NULL
+ORDER BY updated_at ASC
+`
+
+type ListContestVotesForFraudScoringRow struct {
+	ID               pgtype.UUID
+	ParticipantID    pgtype.UUID
+	UserID           int64
+	IpHash           string
+	UserAgent        string
+	AccountCreatedAt pgtype.Timestamptz
+	FraudScore       int32
+	FraudReasons     []string
+	UpdatedAt        pgtype.Timestamptz
+}
+
+func (q *Queries) ListContestVotesForFraudScoring(ctx context.Context, contestID pgtype.UUID) ([]*ListContestVotesForFraudScoringRow, error) {
+	rows, err := q.db.Query(ctx, listContestVotesForFraudScoring, contestID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*ListContestVotesForFraudScoringRow
+	for rows.Next() {
+		var i ListContestVotesForFraudScoringRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ParticipantID,
+			&i.UserID,
+			&i.IpHash,
+			&i.UserAgent,
+			&i.AccountCreatedAt,
+			&i.FraudScore,
+			&i.FraudReasons,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDueBracketRounds = `-- name: ListDueBracketRounds :many
+SELECT contest_id, round, status, starts_at, ends_at FROM contest_bracket_rounds
+WHERE (status = 'scheduled' AND starts_at <= $1) OR (status = 'active' AND ends_at <= $1)
+ORDER BY starts_at ASC
+`
+
+func (q *Queries) ListDueBracketRounds(ctx context.Context, now pgtype.Timestamptz) ([]*ContestBracketRound, error) {
+	rows, err := q.db.Query(ctx, listDueBracketRounds, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*ContestBracketRound
+	for rows.Next() {
+		var i ContestBracketRound
+		if err := rows.Scan(
+			&i.ContestID,
+			&i.Round,
+			&i.Status,
+			&i.StartsAt,
+			&i.EndsAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listEmailRecipients = `-- name: ListEmailRecipients :many
+SELECT u.user_id, u.locale, np.email::text AS email
+FROM users u
+JOIN notification_preferences np ON np.user_id = u.user_id
+WHERE u.user_id = ANY($1::bigint[])
+  AND u.deleted_at IS NULL
+  AND np.email IS NOT NULL AND np.email <> ''
+  AND CASE $2::text
+      WHEN 'voting_started' THEN np.email_voting_started
+      WHEN 'results_published' THEN np.email_results_published
+      ELSE FALSE
+  END
+ORDER BY u.user_id
+`
+
+type ListEmailRecipientsParams struct {
+	UserIds          []int64
+	NotificationType string
+}
+
+type ListEmailRecipientsRow struct {
+	UserID int64
+	Locale string
+	Email  string
+}
+
+// Получатели письма о событии: известен адрес и письма этого типа не отключены
+func (q *Queries) ListEmailRecipients(ctx context.Context, arg *ListEmailRecipientsParams) ([]*ListEmailRecipientsRow, error) {
+	rows, err := q.db.Query(ctx, listEmailRecipients, arg.UserIds, arg.NotificationType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*ListEmailRecipientsRow
+	for rows.Next() {
+		var i ListEmailRecipientsRow
+		if err := rows.Scan(&i.UserID, &i.Locale, &i.Email); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFlaggedContestVotes = `-- name: ListFlaggedContestVotes :many
+SELECT
+    cv.id,
+    cv.participant_id,
+    cv.user_id,
+    COALESCE(u.name, 'Пользователь ' || cv.user_id::text) AS user_name,
+    cv.ip_hash,
+    cv.user_agent,
+    cv.auth_provider,
+    cv.account_created_at,
+    cv.fraud_score,
+    cv.fraud_reasons,
+    cv.flagged_at,
+    cv.updated_at,
+    cv.voided_at,
+    cv.voided_by_user_id,
+    cv.void_reason
+FROM contest_votes cv
+LEFT JOIN users u ON u.user_id = cv.user_id
+WHERE cv.contest_id = $1 AND cv.flagged_at IS NOT NULL
+ORDER BY cv.voided_at IS NOT NULL, cv.fraud_score DESC, cv.updated_at ASC
+`
+
+type ListFlaggedContestVotesRow struct {
+	ID               pgtype.UUID
+	ParticipantID    pgtype.UUID
+	UserID           int64
+	UserName         string
+	IpHash           string
+	UserAgent        string
+	AuthProvider     string
+	AccountCreatedAt pgtype.Timestamptz
+	FraudScore       int32
+	FraudReasons     []string
+	FlaggedAt        pgtype.Timestamptz
+	UpdatedAt        pgtype.Timestamptz
+	VoidedAt         pgtype.Timestamptz
+	VoidedByUserID   *int64
+	VoidReason       string
+}
+
+func (q *Queries) ListFlaggedContestVotes(ctx context.Context, contestID pgtype.UUID) ([]*ListFlaggedContestVotesRow, error) {
+	rows, err := q.db.Query(ctx, listFlaggedContestVotes, contestID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*ListFlaggedContestVotesRow
+	for rows.Next() {
+		var i ListFlaggedContestVotesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ParticipantID,
+			&i.UserID,
+			&i.UserName,
+			&i.IpHash,
+			&i.UserAgent,
+			&i.AuthProvider,
+			&i.AccountCreatedAt,
+			&i.FraudScore,
+			&i.FraudReasons,
+			&i.FlaggedAt,
+			&i.UpdatedAt,
+			&i.VoidedAt,
+			&i.VoidedByUserID,
+			&i.VoidReason,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listJuryCriteria = `-- name: ListJuryCriteria :many
+SELECT id, contest_id, name, description, weight, max_score, position, created_at FROM contest_jury_criteria
+WHERE contest_id = $1
+ORDER BY position ASC, created_at ASC
+`
+
+func (q *Queries) ListJuryCriteria(ctx context.Context, contestID pgtype.UUID) ([]*ContestJuryCriterium, error) {
+	rows, err := q.db.Query(ctx, listJuryCriteria, contestID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*ContestJuryCriterium
+	for rows.Next() {
+		var i ContestJuryCriterium
+		if err := rows.Scan(
+			&i.ID,
+			&i.ContestID,
+			&i.Name,
+			&i.Description,
+			&i.Weight,
+			&i.MaxScore,
+			&i.Position,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listJuryScores = `-- name: ListJuryScores :many
+SELECT
+    js.criterion_id,
+    js.participant_id,
+    js.juror_user_id,
+    COALESCE(u.name, 'Пользователь ' || js.juror_user_id::text) AS juror_name,
+    js.score,
+    js.comment,
+    js.updated_at
+FROM contest_jury_scores js
+LEFT JOIN users u ON u.user_id = js.juror_user_id
+WHERE js.contest_id = $1
+ORDER BY js.participant_id, js.juror_user_id, js.criterion_id
+`
+
+type ListJuryScoresRow struct {
+	CriterionID   pgtype.UUID
+	ParticipantID pgtype.UUID
+	JurorUserID   int64
+	JurorName     string
+	Score         int32
+	Comment       string
+	UpdatedAt     pgtype.Timestamptz
+}
+
+func (q *Queries) ListJuryScores(ctx context.Context, contestID pgtype.UUID) ([]*ListJuryScoresRow, error) {
+	rows, err := q.db.Query(ctx, listJuryScores, contestID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*ListJuryScoresRow
+	for rows.Next() {
+		var i ListJuryScoresRow
+		if err := rows.Scan(
+			&i.CriterionID,
+			&i.ParticipantID,
+			&i.JurorUserID,
+			&i.JurorName,
+			&i.Score,
+			&i.Comment,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listModerationActions = `-- name: ListModerationActions :many
+SELECT id, actor_user_id, action, target_type, target_id, reason, created_at FROM moderation_actions
+ORDER BY created_at DESC
+LIMIT $1 OFFSET $2
+`
+
+type ListModerationActionsParams struct {
+	Limit  int32
+	Offset int32
+}
+
+func (q *Queries) ListModerationActions(ctx context.Context, arg *ListModerationActionsParams) ([]*ModerationAction, error) {
+	rows, err := q.db.Query(ctx, listModerationActions, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*ModerationAction
+	for rows.Next() {
+		var i ModerationAction
+		if err := rows.Scan(
+			&i.ID,
+			&i.ActorUserID,
+			&i.Action,
+			&i.TargetType,
+			&i.TargetID,
+			&i.Reason,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listNotifications = `-- name: ListNotifications :many
+SELECT n.id, n.user_id, n.type, n.contest_id, n.participant_id, n.comment_id, n.actor_user_id, n.title, n.text, n.read_at, n.created_at, COALESCE(actor.name, '')::text AS actor_name
+FROM notifications n
+LEFT JOIN users actor ON actor.user_id = n.actor_user_id
+WHERE n.user_id = $1 AND (NOT $2::bool OR n.read_at IS NULL)
+ORDER BY n.created_at DESC
+LIMIT $3 OFFSET $4
+`
+
+type ListNotificationsParams struct {
+	UserID      int64
+	UnreadOnly  bool
+	LimitCount  int32
+	OffsetCount int32
+}
+
+type ListNotificationsRow struct {
+	ID            pgtype.UUID
+	UserID        int64
+	Type          string
+	ContestID     pgtype.UUID
+	ParticipantID pgtype.UUID
+	CommentID     pgtype.UUID
+	ActorUserID   pgtype.Int8
+	Title         string
+	Text          string
+	ReadAt        pgtype.Timestamptz
+	CreatedAt     pgtype.Timestamptz
+	ActorName     string
+}
+
+func (q *Queries) ListNotifications(ctx context.Context, arg *ListNotificationsParams) ([]*ListNotificationsRow, error) {
+	rows, err := q.db.Query(ctx, listNotifications,
+		arg.UserID,
+		arg.UnreadOnly,
+		arg.LimitCount,
+		arg.OffsetCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*ListNotificationsRow
+	for rows.Next() {
+		var i ListNotificationsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Type,
+			&i.ContestID,
+			&i.ParticipantID,
+			&i.CommentID,
+			&i.ActorUserID,
+			&i.Title,
+			&i.Text,
+			&i.ReadAt,
+			&i.CreatedAt,
+			&i.ActorName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listParticipantCommenters = `-- name: ListParticipantCommenters :many
+SELECT DISTINCT user_id FROM contest_comments
+WHERE participant_id = $1 AND hidden_at IS NULL
+`
+
+// Авторы видимых комментариев к заявке - получатели уведомлений об ответах
+func (q *Queries) ListParticipantCommenters(ctx context.Context, participantID pgtype.UUID) ([]int64, error) {
+	rows, err := q.db.Query(ctx, listParticipantCommenters, participantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var user_id int64
+		if err := rows.Scan(&user_id); err != nil {
+			return nil, err
+		}
+		items = append(items, user_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listParticipantsByContest = `-- name: ListParticipantsByContest :many
+SELECT
+    cp.id,
+    cp.contest_id,
+    cp.user_id,
+    COALESCE(u.name, 'Пользователь ' || cp.user_id::text) AS user_name,
+    cp.pet_name,
+    cp.pet_description,
+    cp.created_at,
+    cp.updated_at,
+    cp.pet_id,
+    cp.species,
+    cp.breed,
+    cp.sex,
+    cp.birth_date
+FROM contest_participants cp
+LEFT JOIN users u ON u.user_id = cp.user_id
+WHERE cp.contest_id = $1 AND cp.hidden_at IS NULL AND cp.moderation_status = 'approved' AND cp.disqualified_at IS NULL
+ORDER BY cp.created_at ASC
+`
+
+type ListParticipantsByContestRow struct {
+	ID             pgtype.UUID
+	ContestID      pgtype.UUID
+	UserID         int64
+	UserName       string
+	PetName        string
+	PetDescription string
+	CreatedAt      pgtype.Timestamptz
+	UpdatedAt      pgtype.Timestamptz
+	PetID          pgtype.UUID
+	Species        *string
+	Breed          *string
+	Sex            *string
+	BirthDate      pgtype.Date
+}
+
+func (q *Queries) ListParticipantsByContest(ctx context.Context, contestID pgtype.UUID) ([]*ListParticipantsByContestRow, error) {
+	rows, err := q.db.Query(ctx, listParticipantsByContest, contestID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*ListParticipantsByContestRow
+	for rows.Next() {
+		var i ListParticipantsByContestRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ContestID,
+			&i.UserID,
+			&i.UserName,
+			&i.PetName,
+			&i.PetDescription,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.PetID,
+			&i.Species,
+			&i.Breed,
+			&i.Sex,
+			&i.BirthDate,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listParticipantsByModerationStatus = `-- name: ListParticipantsByModerationStatus :many
+SELECT
+    cp.id,
+    cp.contest_id,
+    cp.user_id,
+    COALESCE(u.name, 'Пользователь ' || cp.user_id::text) AS user_name,
+    cp.pet_name,
+    cp.pet_description,
+    cp.created_at,
+    cp.updated_at,
+    cp.moderation_status,
+    cp.moderation_reason,
+    cp.moderated_at
+FROM contest_participants cp
+LEFT JOIN users u ON u.user_id = cp.user_id
+WHERE cp.contest_id = $1 AND cp.moderation_status = $2
+ORDER BY cp.created_at ASC
+`
+
+type ListParticipantsByModerationStatusParams struct {
+	ContestID        pgtype.UUID
+	ModerationStatus string
+}
+
+type ListParticipantsByModerationStatusRow struct {
+	ID               pgtype.UUID
+	ContestID        pgtype.UUID
+	UserID           int64
+	UserName         string
+	PetName          string
+	PetDescription   string
+	CreatedAt        pgtype.Timestamptz
+	UpdatedAt        pgtype.Timestamptz
+	ModerationStatus string
+	ModerationReason string
+	ModeratedAt      pgtype.Timestamptz
+}
+
+func (q *Queries) ListParticipantsByModerationStatus(ctx context.Context, arg *ListParticipantsByModerationStatusParams) ([]*ListParticipantsByModerationStatusRow, error) {
+	rows, err := q.db.Query(ctx, listParticipantsByModerationStatus, arg.ContestID, arg.ModerationStatus)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*ListParticipantsByModerationStatusRow
+	for rows.Next() {
+		var i ListParticipantsByModerationStatusRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ContestID,
+			&i.UserID,
+			&i.UserName,
+			&i.PetName,
+			&i.PetDescription,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ModerationStatus,
+			&i.ModerationReason,
+			&i.ModeratedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listParticipantsByUser = `-- name: ListParticipantsByUser :many
+SELECT id, contest_id, user_id, pet_name, pet_description, created_at, updated_at, hidden_at, moderation_status, moderation_reason, moderated_by_user_id, moderated_at, disqualified_at, disqualified_by_user_id, disqualification_reason, pet_id, species, breed, sex, birth_date FROM contest_participants
+WHERE user_id = $1
+ORDER BY created_at DESC
+`
+
+func (q *Queries) ListParticipantsByUser(ctx context.Context, userID int64) ([]*ContestParticipant, error) {
+	rows, err := q.db.Query(ctx, listParticipantsByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*ContestParticipant
+	for rows.Next() {
+		var i ContestParticipant
+		if err := rows.Scan(
+			&i.ID,
+			&i.ContestID,
+			&i.UserID,
+			&i.PetName,
+			&i.PetDescription,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.HiddenAt,
+			&i.ModerationStatus,
+			&i.ModerationReason,
+			&i.ModeratedByUserID,
+			&i.ModeratedAt,
+			&i.DisqualifiedAt,
+			&i.DisqualifiedByUserID,
+			&i.DisqualificationReason,
+			&i.PetID,
+			&i.Species,
+			&i.Breed,
+			&i.Sex,
+			&i.BirthDate,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPetEntries = `-- name: ListPetEntries :many
+SELECT
+    cp.id,
+    cp.contest_id,
+    c.title AS contest_title,
+    c.status AS contest_status,
+    cp.pet_name,
+    cp.created_at
+FROM contest_participants cp
+JOIN contests c ON c.id = cp.contest_id
+WHERE cp.pet_id = $1 AND cp.hidden_at IS NULL AND cp.moderation_status = 'approved' AND cp.disqualified_at IS NULL AND c.hidden_at IS NULL
+ORDER BY cp.created_at DESC
+`
+
+type ListPetEntriesRow struct {
+	ID            pgtype.UUID
+	ContestID     pgtype.UUID
+	ContestTitle  string
+	ContestStatus string
+	PetName       string
+	CreatedAt     pgtype.Timestamptz
+}
+
+func (q *Queries) ListPetEntries(ctx context.Context, petID pgtype.UUID) ([]*ListPetEntriesRow, error) {
+	rows, err := q.db.Query(ctx, listPetEntries, petID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*ListPetEntriesRow
+	for rows.Next() {
+		var i ListPetEntriesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ContestID,
+			&i.ContestTitle,
+			&i.ContestStatus,
+			&i.PetName,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPetPhotos = `-- name: ListPetPhotos :many
+SELECT id, pet_id, url, thumb_url, position, created_at FROM pet_photos
+WHERE pet_id = $1
+ORDER BY position ASC, created_at ASC
+`
+
+func (q *Queries) ListPetPhotos(ctx context.Context, petID pgtype.UUID) ([]*PetPhoto, error) {
+	rows, err := q.db.Query(ctx, listPetPhotos, petID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*PetPhoto
+	for rows.Next() {
+		var i PetPhoto
+		if err := rows.Scan(
+			&i.ID,
+			&i.PetID,
+			&i.Url,
+			&i.ThumbUrl,
+			&i.Position,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPetsByOwner = `-- name: ListPetsByOwner :many
+SELECT id, owner_user_id, name, species, breed, birth_date, bio, created_at, updated_at, sex FROM pets
+WHERE owner_user_id = $1
+ORDER BY created_at ASC
+`
+
+func (q *Queries) ListPetsByOwner(ctx context.Context, ownerUserID int64) ([]*Pet, error) {
+	rows, err := q.db.Query(ctx, listPetsByOwner, ownerUserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*Pet
+	for rows.Next() {
+		var i Pet
+		if err := rows.Scan(
+			&i.ID,
+			&i.OwnerUserID,
+			&i.Name,
+			&i.Species,
+			&i.Breed,
+			&i.BirthDate,
+			&i.Bio,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Sex,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPhotoLikesByPhotos = `-- name: ListPhotoLikesByPhotos :many
+SELECT id, photo_id, user_id, created_at
+FROM photo_likes
+WHERE photo_id = ANY($1::uuid[]) AND user_id = $2
+`
+
+type ListPhotoLikesByPhotosParams struct {
+	Column1 []pgtype.UUID
+	UserID  int64
+}
+
+func (q *Queries) ListPhotoLikesByPhotos(ctx context.Context, arg *ListPhotoLikesByPhotosParams) ([]*PhotoLike, error) {
+	rows, err := q.db.Query(ctx, listPhotoLikesByPhotos, arg.Column1, arg.UserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*PhotoLike
+	for rows.Next() {
+		var i PhotoLike
+		if err := rows.Scan(
+			&i.ID,
+			&i.PhotoID,
+			&i.UserID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPublicContestsByCreator = `-- name: ListPublicContestsByCreator :many
+SELECT id, created_by_user_id, title, description, status, created_at, updated_at, hidden_at, voting_mode, max_choices, min_ballots, jury_weight FROM contests
+WHERE created_by_user_id = $1 AND status <> 'draft' AND hidden_at IS NULL
+ORDER BY created_at DESC
+LIMIT $2
+`
+
+type ListPublicContestsByCreatorParams struct {
+	CreatedByUserID int64
+	Limit           int32
+}
+
+func (q *Queries) ListPublicContestsByCreator(ctx context.Context, arg *ListPublicContestsByCreatorParams) ([]*Contest, error) {
+	rows, err := q.db.Query(ctx, listPublicContestsByCreator, arg.CreatedByUserID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*Contest
+	for rows.Next() {
+		var i Contest
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedByUserID,
+			&i.Title,
+			&i.Description,
+			&i.Status,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.HiddenAt,
+			&i.VotingMode,
+			&i.MaxChoices,
+			&i.MinBallots,
+			&i.JuryWeight,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSpecies = `-- name: ListSpecies :many
+
+SELECT code, name_ru, name_en, position FROM species
+ORDER BY position ASC, code ASC
+`
+
+// Species & Breeds
+func (q *Queries) ListSpecies(ctx context.Context) ([]*Species, error) {
+	rows, err := q.db.Query(ctx, listSpecies)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*Species
+	for rows.Next() {
+		var i Species
+		if err := rows.Scan(
+			&i.Code,
+			&i.NameRu,
+			&i.NameEn,
+			&i.Position,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTelegramAnnouncements = `-- name: ListTelegramAnnouncements :many
+SELECT a.id, a.contest_id, a.outbox_id, a.chat_id, a.text, a.posted_by_user_id, a.created_at, COALESCE(o.status, '')::text AS status, COALESCE(o.last_error, '')::text AS last_error, o.sent_at
+FROM telegram_announcements a
+LEFT JOIN telegram_outbox o ON o.id = a.outbox_id
+WHERE a.contest_id = $1
+ORDER BY a.created_at DESC
+LIMIT $2
+`
+
+type ListTelegramAnnouncementsParams struct {
+	ContestID pgtype.UUID
+	Limit     int32
+}
+
+type ListTelegramAnnouncementsRow struct {
+	ID             pgtype.UUID
+	ContestID      pgtype.UUID
+	OutboxID       pgtype.UUID
+	ChatID         string
+	Text           string
+	PostedByUserID pgtype.Int8
+	CreatedAt      pgtype.Timestamptz
+	Status         string
+	LastError      string
+	SentAt         pgtype.Timestamptz
+}
+
+// Анонсы конкурса со статусом доставки, новые первыми
+func (q *Queries) ListTelegramAnnouncements(ctx context.Context, arg *ListTelegramAnnouncementsParams) ([]*ListTelegramAnnouncementsRow, error) {
+	rows, err := q.db.Query(ctx, listTelegramAnnouncements, arg.ContestID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*ListTelegramAnnouncementsRow
+	for rows.Next() {
+		var i ListTelegramAnnouncementsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ContestID,
+			&i.OutboxID,
+			&i.ChatID,
+			&i.Text,
+			&i.PostedByUserID,
+			&i.CreatedAt,
+			&i.Status,
+			&i.LastError,
+			&i.SentAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTelegramContestChats = `-- name: ListTelegramContestChats :many
+SELECT s.chat_id FROM telegram_subscriptions s
+WHERE s.contest_id = $1
+UNION
+SELECT a.chat_id FROM telegram_accounts a
+JOIN contest_participants cp ON cp.user_id = a.user_id
+WHERE cp.contest_id = $1 AND cp.moderation_status <> 'rejected'
+`
+
+// Чаты, которым отправляются новости конкурса: подписчики и привязанные аккаунты участников
+func (q *Queries) ListTelegramContestChats(ctx context.Context, contestID pgtype.UUID) ([]int64, error) {
+	rows, err := q.db.Query(ctx, listTelegramContestChats, contestID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var chat_id int64
+		if err := rows.Scan(&chat_id); err != nil {
+			return nil, err
+		}
+		items = append(items, chat_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTelegramSubscriptions = `-- name: ListTelegramSubscriptions :many
+SELECT c.id, c.title, c.status
+FROM telegram_subscriptions s
+JOIN contests c ON c.id = s.contest_id
+WHERE s.chat_id = $1 AND c.hidden_at IS NULL
+ORDER BY s.created_at ASC
+`
+
+type ListTelegramSubscriptionsRow struct {
+	ID     pgtype.UUID
+	Title  string
+	Status string
+}
+
+func (q *Queries) ListTelegramSubscriptions(ctx context.Context, chatID int64) ([]*ListTelegramSubscriptionsRow, error) {
+	rows, err := q.db.Query(ctx, listTelegramSubscriptions, chatID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*ListTelegramSubscriptionsRow
+	for rows.Next() {
+		var i ListTelegramSubscriptionsRow
+		if err := rows.Scan(&i.ID, &i.Title, &i.Status); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUserRoles = `-- name: ListUserRoles :many
+
+SELECT role FROM user_roles
+WHERE user_id = $1
+ORDER BY role
+`
+
+// User Roles
+func (q *Queries) ListUserRoles(ctx context.Context, userID int64) ([]string, error) {
+	rows, err := q.db.Query(ctx, listUserRoles, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var role string
+		if err := rows.Scan(&role); err != nil {
+			return nil, err
+		}
+		items = append(items, role)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listVotersByParticipant = `-- name: ListVotersByParticipant :many
+SELECT
+    cv.user_id,
+    COALESCE(u.name, 'Пользователь ' || cv.user_id::text) AS user_name,
+    cv.created_at
+FROM contest_votes cv
+LEFT JOIN users u ON u.user_id = cv.user_id
+WHERE cv.contest_id = $1 AND cv.participant_id = $2 AND cv.category_id IS NOT DISTINCT FROM $3 AND cv.voided_at IS NULL
+ORDER BY cv.created_at ASC
+`
+
+type ListVotersByParticipantParams struct {
+	ContestID     pgtype.UUID
+	ParticipantID pgtype.UUID
+	CategoryID    pgtype.UUID
+}
+
+type ListVotersByParticipantRow struct {
+	UserID    int64
+	UserName  string
+	CreatedAt pgtype.Timestamptz
+}
+
+func (q *Queries) ListVotersByParticipant(ctx context.Context, arg *ListVotersByParticipantParams) ([]*ListVotersByParticipantRow, error) {
+	rows, err := q.db.Query(ctx, listVotersByParticipant, arg.ContestID, arg.ParticipantID, arg.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*ListVotersByParticipantRow
+	for rows.Next() {
+		var i ListVotersByParticipantRow
+		if err := rows.Scan(&i.UserID, &i.UserName, &i.CreatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const listChatMessages = `-- name: ListChatMessages :many
-SELECT 
-    ccm.id,
-    ccm.contest_id,
-    ccm.user_id,
-    ccm.text,
-    ccm.is_system,
-    ccm.created_at,
-    ccm.updated_at,
-    COALESCE(u.name, 'Пользователь ' || ccm.user_id::text) as user_name
-FROM contest_chat_messages ccm
-LEFT JOIN users u ON u.user_id = ccm.user_id
-WHERE ccm.contest_id = $1
-ORDER BY ccm.created_at ASC
+const listWebhookDeliveries = `-- name: ListWebhookDeliveries :many
+SELECT d.id, d.endpoint_id, d.event_id, d.redelivery, d.status, d.attempts, d.response_status, d.response_body, d.duration_ms, d.last_error, d.next_attempt_at, d.last_attempt_at, d.created_at, d.delivered_at, ev.type AS event_type
+FROM webhook_deliveries d
+JOIN webhook_events ev ON ev.id = d.event_id
+WHERE d.endpoint_id = $1
+ORDER BY d.created_at DESC
 LIMIT $2 OFFSET $3
 `
 
-type ListChatMessagesParams struct {
-	ContestID pgtype.UUID
-	Limit     int32
-	Offset    int32
+type ListWebhookDeliveriesParams struct {
+	EndpointID  pgtype.UUID
+	LimitCount  int32
+	OffsetCount int32
 }
 
-type ListChatMessagesRow struct {
-	ID        pgtype.UUID
-	ContestID pgtype.UUID
-	UserID    int64
-	Text      string
-	IsSystem  bool
-	CreatedAt pgtype.Timestamptz
-	UpdatedAt pgtype.Timestamptz
-	UserName  string
+type ListWebhookDeliveriesRow struct {
+	ID             pgtype.UUID
+	EndpointID     pgtype.UUID
+	EventID        pgtype.UUID
+	Redelivery     bool
+	Status         string
+	Attempts       int32
+	ResponseStatus *int32
+	ResponseBody   string
+	DurationMs     int32
+	LastError      string
+	NextAttemptAt  pgtype.Timestamptz
+	LastAttemptAt  pgtype.Timestamptz
+	CreatedAt      pgtype.Timestamptz
+	DeliveredAt    pgtype.Timestamptz
+	EventType      string
 }
 
-func (q *Queries) ListChatMessages(ctx context.Context, arg *ListChatMessagesParams) ([]*ListChatMessagesRow, error) {
-	rows, err := q.db.Query(ctx, listChatMessages, arg.ContestID, arg.Limit, arg.Offset)
+// Журнал доставок webhook, новые первыми
+func (q *Queries) ListWebhookDeliveries(ctx context.Context, arg *ListWebhookDeliveriesParams) ([]*ListWebhookDeliveriesRow, error) {
+	rows, err := q.db.Query(ctx, listWebhookDeliveries, arg.EndpointID, arg.LimitCount, arg.OffsetCount)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []*ListChatMessagesRow
+	var items []*ListWebhookDeliveriesRow
 	for rows.Next() {
-		var i ListChatMessagesRow
+		var i ListWebhookDeliveriesRow
 		if err := rows.Scan(
 			&i.ID,
-			&i.ContestID,
-			&i.UserID,
-			&i.Text,
-			&i.IsSystem,
+			&i.EndpointID,
+			&i.EventID,
+			&i.Redelivery,
+			&i.Status,
+			&i.Attempts,
+			&i.ResponseStatus,
+			&i.ResponseBody,
+			&i.DurationMs,
+			&i.LastError,
+			&i.NextAttemptAt,
+			&i.LastAttemptAt,
 			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.UserName,
+			&i.DeliveredAt,
+			&i.EventType,
 		); err != nil {
 			return nil, err
 		}
@@ -823,55 +4748,31 @@ func (q *Queries) ListChatMessages(ctx context.Context, arg *ListChatMessagesPar
 	return items, nil
 }
 
-const listCommentsByParticipant = `-- name: ListCommentsByParticipant :many
-SELECT
-    cc.id,
-    cc.participant_id,
-    cc.user_id,
-    cc.text,
-    cc.created_at,
-    cc.updated_at,
-    COALESCE(u.name, 'Пользователь ' || cc.user_id::text) AS user_name
-FROM contest_comments cc
-LEFT JOIN users u ON u.user_id = cc.user_id
-WHERE cc.participant_id = $1
-ORDER BY cc.created_at ASC
-LIMIT $2 OFFSET $3
+const listWebhookEndpoints = `-- name: ListWebhookEndpoints :many
+SELECT id, contest_id, url, secret, event_types, active, created_by_user_id, created_at, updated_at FROM webhook_endpoints
+WHERE contest_id = $1
+ORDER BY created_at ASC
 `
 
-type ListCommentsByParticipantParams struct {
-	ParticipantID pgtype.UUID
-	Limit         int32
-	Offset        int32
-}
-
-type ListCommentsByParticipantRow struct {
-	ID            pgtype.UUID
-	ParticipantID pgtype.UUID
-	UserID        int64
-	Text          string
-	CreatedAt     pgtype.Timestamptz
-	UpdatedAt     pgtype.Timestamptz
-	UserName      string
-}
-
-func (q *Queries) ListCommentsByParticipant(ctx context.Context, arg *ListCommentsByParticipantParams) ([]*ListCommentsByParticipantRow, error) {
-	rows, err := q.db.Query(ctx, listCommentsByParticipant, arg.ParticipantID, arg.Limit, arg.Offset)
+func (q *Queries) ListWebhookEndpoints(ctx context.Context, contestID pgtype.UUID) ([]*WebhookEndpoint, error) {
+	rows, err := q.db.Query(ctx, listWebhookEndpoints, contestID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []*ListCommentsByParticipantRow
+	var items []*WebhookEndpoint
 	for rows.Next() {
-		var i ListCommentsByParticipantRow
+		var i WebhookEndpoint
 		if err := rows.Scan(
 			&i.ID,
-			&i.ParticipantID,
-			&i.UserID,
-			&i.Text,
+			&i.ContestID,
+			&i.Url,
+			&i.Secret,
+			&i.EventTypes,
+			&i.Active,
+			&i.CreatedByUserID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
-			&i.UserName,
 		); err != nil {
 			return nil, err
 		}
@@ -883,186 +4784,477 @@ func (q *Queries) ListCommentsByParticipant(ctx context.Context, arg *ListCommen
 	return items, nil
 }
 
-const listContests = `-- name: ListContests :many
-SELECT id, created_by_user_id, title, description, status, created_at, updated_at FROM contests
-WHERE (COALESCE($1::text, '') = '' OR status = $1)
-ORDER BY created_at DESC
-LIMIT $2 OFFSET $3
+const lockContest = `-- name: LockContest :exec
+SELECT 1 FROM contests WHERE id = $1 FOR UPDATE
+`
+
+// Блокирует строку конкурса до конца транзакции, чтобы проверка и вставка шли по очереди
+func (q *Queries) LockContest(ctx context.Context, id pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, lockContest, id)
+	return err
+}
+
+const markAllNotificationsRead = `-- name: MarkAllNotificationsRead :execrows
+UPDATE notifications
+SET read_at = NOW()
+WHERE user_id = $1 AND read_at IS NULL
+`
+
+func (q *Queries) MarkAllNotificationsRead(ctx context.Context, userID int64) (int64, error) {
+	result, err := q.db.Exec(ctx, markAllNotificationsRead, userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const markEmailSent = `-- name: MarkEmailSent :exec
+UPDATE email_outbox
+SET status = 'sent', last_error = '', sent_at = NOW()
+WHERE id = $1
+`
+
+func (q *Queries) MarkEmailSent(ctx context.Context, id pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, markEmailSent, id)
+	return err
+}
+
+const markNotificationRead = `-- name: MarkNotificationRead :execrows
+UPDATE notifications
+SET read_at = COALESCE(read_at, NOW())
+WHERE id = $1 AND user_id = $2
+`
+
+type MarkNotificationReadParams struct {
+	ID     pgtype.UUID
+	UserID int64
+}
+
+func (q *Queries) MarkNotificationRead(ctx context.Context, arg *MarkNotificationReadParams) (int64, error) {
+	result, err := q.db.Exec(ctx, markNotificationRead, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const markTelegramMessageSent = `-- name: MarkTelegramMessageSent :exec
+UPDATE telegram_outbox
+SET status = 'sent', last_error = '', sent_at = NOW()
+WHERE id = $1
+`
+
+func (q *Queries) MarkTelegramMessageSent(ctx context.Context, id pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, markTelegramMessageSent, id)
+	return err
+}
+
+const promoteContestOwner = `-- name: PromoteContestOwner :execrows
+UPDATE contest_members
+SET role = 'owner'
+WHERE contest_id = $1 AND user_id = $2 AND status = 'active'
+`
+
+type PromoteContestOwnerParams struct {
+	ContestID pgtype.UUID
+	UserID    int64
+}
+
+func (q *Queries) PromoteContestOwner(ctx context.Context, arg *PromoteContestOwnerParams) (int64, error) {
+	result, err := q.db.Exec(ctx, promoteContestOwner, arg.ContestID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const redeliverWebhook = `-- name: RedeliverWebhook :one
+WITH inserted AS (
+    INSERT INTO webhook_deliveries (endpoint_id, event_id, redelivery)
+    SELECT d.endpoint_id, d.event_id, TRUE
+    FROM webhook_deliveries d
+    WHERE d.id = $1
+    RETURNING id, endpoint_id, event_id, redelivery, status, attempts, response_status, response_body, duration_ms, last_error, next_attempt_at, last_attempt_at, created_at, delivered_at
+)
+SELECT inserted.id, inserted.endpoint_id, inserted.event_id, inserted.redelivery, inserted.status, inserted.attempts, inserted.response_status, inserted.response_body, inserted.duration_ms, inserted.last_error, inserted.next_attempt_at, inserted.last_attempt_at, inserted.created_at, inserted.delivered_at, ev.type AS event_type
+FROM inserted
+JOIN webhook_events ev ON ev.id = inserted.event_id
+`
+
+type RedeliverWebhookRow struct {
+	ID             pgtype.UUID
+	EndpointID     pgtype.UUID
+	EventID        pgtype.UUID
+	Redelivery     bool
+	Status         string
+	Attempts       int32
+	ResponseStatus *int32
+	ResponseBody   string
+	DurationMs     int32
+	LastError      string
+	NextAttemptAt  pgtype.Timestamptz
+	LastAttemptAt  pgtype.Timestamptz
+	CreatedAt      pgtype.Timestamptz
+	DeliveredAt    pgtype.Timestamptz
+	EventType      string
+}
+
+// Ставит в очередь новую доставку того же события на тот же webhook
+func (q *Queries) RedeliverWebhook(ctx context.Context, id pgtype.UUID) (*RedeliverWebhookRow, error) {
+	row := q.db.QueryRow(ctx, redeliverWebhook, id)
+	var i RedeliverWebhookRow
+	err := row.Scan(
+		&i.ID,
+		&i.EndpointID,
+		&i.EventID,
+		&i.Redelivery,
+		&i.Status,
+		&i.Attempts,
+		&i.ResponseStatus,
+		&i.ResponseBody,
+		&i.DurationMs,
+		&i.LastError,
+		&i.NextAttemptAt,
+		&i.LastAttemptAt,
+		&i.CreatedAt,
+		&i.DeliveredAt,
+		&i.EventType,
+	)
+	return &i, err
+}
+
+const removeUserRole = `-- name: RemoveUserRole :exec
+DELETE FROM user_roles
+WHERE user_id = $1 AND role = $2
+`
+
+type RemoveUserRoleParams struct {
+	UserID int64
+	Role   string
+}
+
+func (q *Queries) RemoveUserRole(ctx context.Context, arg *RemoveUserRoleParams) error {
+	_, err := q.db.Exec(ctx, removeUserRole, arg.UserID, arg.Role)
+	return err
+}
+
+const replaceContestVoteChoices = `-- name: ReplaceContestVoteChoices :exec
+
+WITH removed AS (
+    DELETE FROM contest_vote_choices
+    WHERE vote_id = $1 AND participant_id <> ALL($2::uuid[])
+)
+INSERT INTO contest_vote_choices (vote_id, participant_id, rank, stars)
+SELECT $1, c.participant_id, c.rank, NULLIF(c.stars, 0)
+FROM unnest($2::uuid[], $3::int[], $4::int[]) AS c(participant_id, rank, stars)
+ON CONFLICT (vote_id, participant_id) DO UPDATE
+SET rank = EXCLUDED.rank, stars = EXCLUDED.stars
+`
+
+type ReplaceContestVoteChoicesParams struct {
+	VoteID         pgtype.UUID
+	ParticipantIds []pgtype.UUID
+	Ranks          []int32
+	Stars          []int32
+}
+
+// Contest Vote Choices
+func (q *Queries) ReplaceContestVoteChoices(ctx context.Context, arg *ReplaceContestVoteChoicesParams) error {
+	_, err := q.db.Exec(ctx, replaceContestVoteChoices,
+		arg.VoteID,
+		arg.ParticipantIds,
+		arg.Ranks,
+		arg.Stars,
+	)
+	return err
+}
+
+const setBracketRoundStatus = `-- name: SetBracketRoundStatus :execrows
+UPDATE contest_bracket_rounds
+SET status = $1
+WHERE contest_id = $2 AND round = $3 AND status = $4
+`
+
+type SetBracketRoundStatusParams struct {
+	Status     string
+	ContestID  pgtype.UUID
+	Round      int32
+	FromStatus string
+}
+
+func (q *Queries) SetBracketRoundStatus(ctx context.Context, arg *SetBracketRoundStatusParams) (int64, error) {
+	result, err := q.db.Exec(ctx, setBracketRoundStatus,
+		arg.Status,
+		arg.ContestID,
+		arg.Round,
+		arg.FromStatus,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const setChatMessageHidden = `-- name: SetChatMessageHidden :one
+UPDATE contest_chat_messages
+SET hidden_at = $2
+WHERE id = $1
+RETURNING contest_id
+`
+
+type SetChatMessageHiddenParams struct {
+	ID       pgtype.UUID
+	HiddenAt pgtype.Timestamptz
+}
+
+func (q *Queries) SetChatMessageHidden(ctx context.Context, arg *SetChatMessageHiddenParams) (pgtype.UUID, error) {
+	row := q.db.QueryRow(ctx, setChatMessageHidden, arg.ID, arg.HiddenAt)
+	var contest_id pgtype.UUID
+	err := row.Scan(&contest_id)
+	return contest_id, err
+}
+
+const setCommentHidden = `-- name: SetCommentHidden :exec
+UPDATE contest_comments
+SET hidden_at = $2
+WHERE id = $1
+`
+
+type SetCommentHiddenParams struct {
+	ID       pgtype.UUID
+	HiddenAt pgtype.Timestamptz
+}
+
+func (q *Queries) SetCommentHidden(ctx context.Context, arg *SetCommentHiddenParams) error {
+	_, err := q.db.Exec(ctx, setCommentHidden, arg.ID, arg.HiddenAt)
+	return err
+}
+
+const setContestHidden = `-- name: SetContestHidden :exec
+UPDATE contests
+SET hidden_at = $2
+WHERE id = $1
+`
+
+type SetContestHiddenParams struct {
+	ID       pgtype.UUID
+	HiddenAt pgtype.Timestamptz
+}
+
+func (q *Queries) SetContestHidden(ctx context.Context, arg *SetContestHiddenParams) error {
+	_, err := q.db.Exec(ctx, setContestHidden, arg.ID, arg.HiddenAt)
+	return err
+}
+
+const setNotificationEmailIfEmpty = `-- name: SetNotificationEmailIfEmpty :exec
+INSERT INTO notification_preferences (user_id, email)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE
+SET email = EXCLUDED.email, updated_at = NOW()
+WHERE notification_preferences.email IS NULL
+`
+
+type SetNotificationEmailIfEmptyParams struct {
+	UserID int64
+	Email  *string
+}
+
+// Запоминает адрес из провайдера входа, если адрес еще неизвестен
+func (q *Queries) SetNotificationEmailIfEmpty(ctx context.Context, arg *SetNotificationEmailIfEmptyParams) error {
+	_, err := q.db.Exec(ctx, setNotificationEmailIfEmpty, arg.UserID, arg.Email)
+	return err
+}
+
+const setParticipantHidden = `-- name: SetParticipantHidden :exec
+UPDATE contest_participants
+SET hidden_at = $2
+WHERE id = $1
+`
+
+type SetParticipantHiddenParams struct {
+	ID       pgtype.UUID
+	HiddenAt pgtype.Timestamptz
+}
+
+func (q *Queries) SetParticipantHidden(ctx context.Context, arg *SetParticipantHiddenParams) error {
+	_, err := q.db.Exec(ctx, setParticipantHidden, arg.ID, arg.HiddenAt)
+	return err
+}
+
+const setParticipantModeration = `-- name: SetParticipantModeration :exec
+UPDATE contest_participants
+SET moderation_status = $2,
+    moderation_reason = $3,
+    moderated_by_user_id = $4,
+    moderated_at = CASE WHEN $2 = 'pending' THEN NULL ELSE NOW() END
+WHERE id = $1
+`
+
+type SetParticipantModerationParams struct {
+	ID                pgtype.UUID
+	ModerationStatus  string
+	ModerationReason  string
+	ModeratedByUserID pgtype.Int8
+}
+
+func (q *Queries) SetParticipantModeration(ctx context.Context, arg *SetParticipantModerationParams) error {
+	_, err := q.db.Exec(ctx, setParticipantModeration,
+		arg.ID,
+		arg.ModerationStatus,
+		arg.ModerationReason,
+		arg.ModeratedByUserID,
+	)
+	return err
+}
+
+const setUserAvatar = `-- name: SetUserAvatar :one
+UPDATE users
+SET avatar_url = $2, avatar_thumb_url = $3
+WHERE user_id = $1
+RETURNING user_id, name, created_at, avatar_url, bio, avatar_thumb_url, locale, deleted_at
+`
+
+type SetUserAvatarParams struct {
+	UserID         int64
+	AvatarUrl      *string
+	AvatarThumbUrl *string
+}
+
+func (q *Queries) SetUserAvatar(ctx context.Context, arg *SetUserAvatarParams) (*User, error) {
+	row := q.db.QueryRow(ctx, setUserAvatar, arg.UserID, arg.AvatarUrl, arg.AvatarThumbUrl)
+	var i User
+	err := row.Scan(
+		&i.UserID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.AvatarUrl,
+		&i.Bio,
+		&i.AvatarThumbUrl,
+		&i.Locale,
+		&i.DeletedAt,
+	)
+	return &i, err
+}
+
+const setUserAvatarIfEmpty = `-- name: SetUserAvatarIfEmpty :exec
+UPDATE users
+SET avatar_url = $2
+WHERE user_id = $1 AND avatar_url IS NULL
 `
 
-type ListContestsParams struct {
-	Column1 string
-	Limit   int32
-	Offset  int32
+type SetUserAvatarIfEmptyParams struct {
+	UserID    int64
+	AvatarUrl *string
 }
 
-func (q *Queries) ListContests(ctx context.Context, arg *ListContestsParams) ([]*Contest, error) {
-	rows, err := q.db.Query(ctx, listContests, arg.Column1, arg.Limit, arg.Offset)
+func (q *Queries) SetUserAvatarIfEmpty(ctx context.Context, arg *SetUserAvatarIfEmptyParams) error {
+	_, err := q.db.Exec(ctx, setUserAvatarIfEmpty, arg.UserID, arg.AvatarUrl)
+	return err
+}
+
+const startContestBracket = `-- name: StartContestBracket :execrows
+UPDATE contest_brackets
+SET status = 'running', current_round = 1, updated_at = NOW()
+WHERE contest_id = $1 AND status = 'pending'
+`
+
+func (q *Queries) StartContestBracket(ctx context.Context, contestID pgtype.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, startContestBracket, contestID)
 	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []*Contest
-	for rows.Next() {
-		var i Contest
-		if err := rows.Scan(
-			&i.ID,
-			&i.CreatedByUserID,
-			&i.Title,
-			&i.Description,
-			&i.Status,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, &i)
+		return 0, err
 	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+	return result.RowsAffected(), nil
 }
 
-const listParticipantsByContest = `-- name: ListParticipantsByContest :many
-SELECT
-    cp.id,
-    cp.contest_id,
-    cp.user_id,
-    COALESCE(u.name, 'Пользователь ' || cp.user_id::text) AS user_name,
-    cp.pet_name,
-    cp.pet_description,
-    cp.created_at,
-    cp.updated_at
-FROM contest_participants cp
-LEFT JOIN users u ON u.user_id = cp.user_id
-WHERE cp.contest_id = $1
-ORDER BY cp.created_at ASC
+const subscribeTelegramChat = `-- name: SubscribeTelegramChat :exec
+INSERT INTO telegram_subscriptions (chat_id, contest_id)
+VALUES ($1, $2)
+ON CONFLICT (chat_id, contest_id) DO NOTHING
 `
 
-type ListParticipantsByContestRow struct {
-	ID             pgtype.UUID
-	ContestID      pgtype.UUID
-	UserID         int64
-	UserName       string
-	PetName        string
-	PetDescription string
-	CreatedAt      pgtype.Timestamptz
-	UpdatedAt      pgtype.Timestamptz
+type SubscribeTelegramChatParams struct {
+	ChatID    int64
+	ContestID pgtype.UUID
 }
 
-func (q *Queries) ListParticipantsByContest(ctx context.Context, contestID pgtype.UUID) ([]*ListParticipantsByContestRow, error) {
-	rows, err := q.db.Query(ctx, listParticipantsByContest, contestID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []*ListParticipantsByContestRow
-	for rows.Next() {
-		var i ListParticipantsByContestRow
-		if err := rows.Scan(
-			&i.ID,
-			&i.ContestID,
-			&i.UserID,
-			&i.UserName,
-			&i.PetName,
-			&i.PetDescription,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, &i)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+func (q *Queries) SubscribeTelegramChat(ctx context.Context, arg *SubscribeTelegramChatParams) error {
+	_, err := q.db.Exec(ctx, subscribeTelegramChat, arg.ChatID, arg.ContestID)
+	return err
 }
 
-const listPhotoLikesByPhotos = `-- name: ListPhotoLikesByPhotos :many
-SELECT id, photo_id, user_id, created_at
-FROM photo_likes
-WHERE photo_id = ANY($1::uuid[]) AND user_id = $2
+const takeRateLimitToken = `-- name: TakeRateLimitToken :one
+
+INSERT INTO rate_limit_buckets AS b (bucket_key, tokens, allowed, updated_at)
+VALUES ($1, $2::float8 - 1, TRUE, NOW())
+ON CONFLICT (bucket_key) DO UPDATE
+SET tokens = CASE
+        WHEN LEAST($2::float8, b.tokens + EXTRACT(EPOCH FROM NOW() - b.updated_at)::float8 * $3::float8) >= 1
+        THEN LEAST($2::float8, b.tokens + EXTRACT(EPOCH FROM NOW() - b.updated_at)::float8 * $3::float8) - 1
+        ELSE LEAST($2::float8, b.tokens + EXTRACT(EPOCH FROM NOW() - b.updated_at)::float8 * $3::float8)
+    END,
+    allowed = LEAST($2::float8, b.tokens + EXTRACT(EPOCH FROM NOW() - b.updated_at)::float8 * $3::float8) >= 1,
+    updated_at = NOW()
+RETURNING tokens, allowed
 `
 
-type ListPhotoLikesByPhotosParams struct {
-	Column1 []pgtype.UUID
-	UserID  int64
+type TakeRateLimitTokenParams struct {
+	BucketKey string
+	Burst     float64
+	Rate      float64
 }
 
-func (q *Queries) ListPhotoLikesByPhotos(ctx context.Context, arg *ListPhotoLikesByPhotosParams) ([]*PhotoLike, error) {
-	rows, err := q.db.Query(ctx, listPhotoLikesByPhotos, arg.Column1, arg.UserID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []*PhotoLike
-	for rows.Next() {
-		var i PhotoLike
-		if err := rows.Scan(
-			&i.ID,
-			&i.PhotoID,
-			&i.UserID,
-			&i.CreatedAt,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, &i)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+type TakeRateLimitTokenRow struct {
+	Tokens  float64
+	Allowed bool
 }
 
-const listVotersByParticipant = `-- name: ListVotersByParticipant :many
-SELECT
-    cv.user_id,
-    COALESCE(u.name, 'Пользователь ' || cv.user_id::text) AS user_name,
-    cv.created_at
-FROM contest_votes cv
-LEFT JOIN users u ON u.user_id = cv.user_id
-WHERE cv.contest_id = $1 AND cv.participant_id = $2
-ORDER BY cv.created_at ASC
+// Rate Limit Buckets
+func (q *Queries) TakeRateLimitToken(ctx context.Context, arg *TakeRateLimitTokenParams) (*TakeRateLimitTokenRow, error) {
+	row := q.db.QueryRow(ctx, takeRateLimitToken, arg.BucketKey, arg.Burst, arg.Rate)
+	var i TakeRateLimitTokenRow
+	err := row.Scan(&i.Tokens, &i.Allowed)
+	return &i, err
+}
+
+const unlinkTelegramChat = `-- name: UnlinkTelegramChat :exec
+DELETE FROM telegram_accounts
+WHERE chat_id = $1 AND user_id <> $2
 `
 
-type ListVotersByParticipantParams struct {
-	ContestID     pgtype.UUID
-	ParticipantID pgtype.UUID
+type UnlinkTelegramChatParams struct {
+	ChatID int64
+	UserID int64
 }
 
-type ListVotersByParticipantRow struct {
-	UserID    int64
-	UserName  string
-	CreatedAt pgtype.Timestamptz
+// Отвязывает чат от другого аккаунта перед привязкой к user_id
+func (q *Queries) UnlinkTelegramChat(ctx context.Context, arg *UnlinkTelegramChatParams) error {
+	_, err := q.db.Exec(ctx, unlinkTelegramChat, arg.ChatID, arg.UserID)
+	return err
 }
 
-func (q *Queries) ListVotersByParticipant(ctx context.Context, arg *ListVotersByParticipantParams) ([]*ListVotersByParticipantRow, error) {
-	rows, err := q.db.Query(ctx, listVotersByParticipant, arg.ContestID, arg.ParticipantID)
+const unsubscribeTelegramChat = `-- name: UnsubscribeTelegramChat :execrows
+DELETE FROM telegram_subscriptions
+WHERE chat_id = $1 AND contest_id = $2
+`
+
+type UnsubscribeTelegramChatParams struct {
+	ChatID    int64
+	ContestID pgtype.UUID
+}
+
+func (q *Queries) UnsubscribeTelegramChat(ctx context.Context, arg *UnsubscribeTelegramChatParams) (int64, error) {
+	result, err := q.db.Exec(ctx, unsubscribeTelegramChat, arg.ChatID, arg.ContestID)
 	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []*ListVotersByParticipantRow
-	for rows.Next() {
-		var i ListVotersByParticipantRow
-		if err := rows.Scan(&i.UserID, &i.UserName, &i.CreatedAt); err != nil {
-			return nil, err
-		}
-		items = append(items, &i)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
+		return 0, err
 	}
-	return items, nil
+	return result.RowsAffected(), nil
 }
 
 const updateChatMessage = `-- name: UpdateChatMessage :one
 UPDATE contest_chat_messages
 SET text = $1, updated_at = NOW()
 WHERE id = $2 AND user_id = $3 AND is_system = FALSE
-RETURNING id, contest_id, user_id, text, is_system, created_at, updated_at
+RETURNING id, contest_id, user_id, text, is_system, created_at, updated_at, hidden_at
 `
 
 type UpdateChatMessageParams struct {
@@ -1082,6 +5274,7 @@ func (q *Queries) UpdateChatMessage(ctx context.Context, arg *UpdateChatMessageP
 		&i.IsSystem,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.HiddenAt,
 	)
 	return &i, err
 }
@@ -1090,7 +5283,7 @@ const updateComment = `-- name: UpdateComment :one
 UPDATE contest_comments
 SET text = $1, updated_at = NOW()
 WHERE id = $2 AND user_id = $3
-RETURNING id, participant_id, user_id, text, created_at, updated_at
+RETURNING id, participant_id, user_id, text, created_at, updated_at, hidden_at
 `
 
 type UpdateCommentParams struct {
@@ -1103,31 +5296,197 @@ func (q *Queries) UpdateComment(ctx context.Context, arg *UpdateCommentParams) (
 	row := q.db.QueryRow(ctx, updateComment, arg.Text, arg.ID, arg.UserID)
 	var i ContestComment
 	err := row.Scan(
-		&i.ID,
-		&i.ParticipantID,
+		&i.ID,
+		&i.ParticipantID,
+		&i.UserID,
+		&i.Text,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.HiddenAt,
+	)
+	return &i, err
+}
+
+const updateContest = `-- name: UpdateContest :one
+UPDATE contests
+SET title = $2, description = $3, updated_at = NOW()
+WHERE id = $1
+RETURNING id, created_by_user_id, title, description, status, created_at, updated_at, hidden_at, voting_mode, max_choices, min_ballots, jury_weight
+`
+
+type UpdateContestParams struct {
+	ID          pgtype.UUID
+	Title       string
+	Description string
+}
+
+func (q *Queries) UpdateContest(ctx context.Context, arg *UpdateContestParams) (*Contest, error) {
+	row := q.db.QueryRow(ctx, updateContest, arg.ID, arg.Title, arg.Description)
+	var i Contest
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedByUserID,
+		&i.Title,
+		&i.Description,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.HiddenAt,
+		&i.VotingMode,
+		&i.MaxChoices,
+		&i.MinBallots,
+		&i.JuryWeight,
+	)
+	return &i, err
+}
+
+const updateContestBracketState = `-- name: UpdateContestBracketState :exec
+UPDATE contest_brackets
+SET status = $2, current_round = $3, champion_participant_id = $4, updated_at = NOW()
+WHERE contest_id = $1
+`
+
+type UpdateContestBracketStateParams struct {
+	ContestID             pgtype.UUID
+	Status                string
+	CurrentRound          int32
+	ChampionParticipantID pgtype.UUID
+}
+
+func (q *Queries) UpdateContestBracketState(ctx context.Context, arg *UpdateContestBracketStateParams) error {
+	_, err := q.db.Exec(ctx, updateContestBracketState,
+		arg.ContestID,
+		arg.Status,
+		arg.CurrentRound,
+		arg.ChampionParticipantID,
+	)
+	return err
+}
+
+const updateContestCategory = `-- name: UpdateContestCategory :one
+UPDATE contest_categories
+SET name = $3, description = $4, updated_at = NOW()
+WHERE contest_id = $1 AND id = $2
+RETURNING id, contest_id, name, description, position, created_at, updated_at
+`
+
+type UpdateContestCategoryParams struct {
+	ContestID   pgtype.UUID
+	ID          pgtype.UUID
+	Name        string
+	Description string
+}
+
+func (q *Queries) UpdateContestCategory(ctx context.Context, arg *UpdateContestCategoryParams) (*ContestCategory, error) {
+	row := q.db.QueryRow(ctx, updateContestCategory,
+		arg.ContestID,
+		arg.ID,
+		arg.Name,
+		arg.Description,
+	)
+	var i ContestCategory
+	err := row.Scan(
+		&i.ID,
+		&i.ContestID,
+		&i.Name,
+		&i.Description,
+		&i.Position,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return &i, err
+}
+
+const updateContestJuryWeight = `-- name: UpdateContestJuryWeight :one
+UPDATE contests
+SET jury_weight = $2, updated_at = NOW()
+WHERE id = $1
+RETURNING id, created_by_user_id, title, description, status, created_at, updated_at, hidden_at, voting_mode, max_choices, min_ballots, jury_weight
+`
+
+type UpdateContestJuryWeightParams struct {
+	ID         pgtype.UUID
+	JuryWeight int32
+}
+
+func (q *Queries) UpdateContestJuryWeight(ctx context.Context, arg *UpdateContestJuryWeightParams) (*Contest, error) {
+	row := q.db.QueryRow(ctx, updateContestJuryWeight, arg.ID, arg.JuryWeight)
+	var i Contest
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedByUserID,
+		&i.Title,
+		&i.Description,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.HiddenAt,
+		&i.VotingMode,
+		&i.MaxChoices,
+		&i.MinBallots,
+		&i.JuryWeight,
+	)
+	return &i, err
+}
+
+const updateContestMemberRole = `-- name: UpdateContestMemberRole :one
+UPDATE contest_members
+SET role = $3
+WHERE contest_id = $1 AND user_id = $2
+RETURNING contest_id, user_id, role, status, invited_by_user_id, created_at, accepted_at
+`
+
+type UpdateContestMemberRoleParams struct {
+	ContestID pgtype.UUID
+	UserID    int64
+	Role      string
+}
+
+func (q *Queries) UpdateContestMemberRole(ctx context.Context, arg *UpdateContestMemberRoleParams) (*ContestMember, error) {
+	row := q.db.QueryRow(ctx, updateContestMemberRole, arg.ContestID, arg.UserID, arg.Role)
+	var i ContestMember
+	err := row.Scan(
+		&i.ContestID,
 		&i.UserID,
-		&i.Text,
+		&i.Role,
+		&i.Status,
+		&i.InvitedByUserID,
 		&i.CreatedAt,
-		&i.UpdatedAt,
+		&i.AcceptedAt,
 	)
 	return &i, err
 }
 
-const updateContest = `-- name: UpdateContest :one
+const updateContestOwner = `-- name: UpdateContestOwner :exec
 UPDATE contests
-SET title = $2, description = $3, updated_at = NOW()
+SET created_by_user_id = $2, updated_at = NOW()
 WHERE id = $1
-RETURNING id, created_by_user_id, title, description, status, created_at, updated_at
 `
 
-type UpdateContestParams struct {
-	ID          pgtype.UUID
-	Title       string
-	Description string
+type UpdateContestOwnerParams struct {
+	ID              pgtype.UUID
+	CreatedByUserID int64
 }
 
-func (q *Queries) UpdateContest(ctx context.Context, arg *UpdateContestParams) (*Contest, error) {
-	row := q.db.QueryRow(ctx, updateContest, arg.ID, arg.Title, arg.Description)
+func (q *Queries) UpdateContestOwner(ctx context.Context, arg *UpdateContestOwnerParams) error {
+	_, err := q.db.Exec(ctx, updateContestOwner, arg.ID, arg.CreatedByUserID)
+	return err
+}
+
+const updateContestStatus = `-- name: UpdateContestStatus :one
+UPDATE contests
+SET status = $2, updated_at = NOW()
+WHERE id = $1
+RETURNING id, created_by_user_id, title, description, status, created_at, updated_at, hidden_at, voting_mode, max_choices, min_ballots, jury_weight
+`
+
+type UpdateContestStatusParams struct {
+	ID     pgtype.UUID
+	Status string
+}
+
+func (q *Queries) UpdateContestStatus(ctx context.Context, arg *UpdateContestStatusParams) (*Contest, error) {
+	row := q.db.QueryRow(ctx, updateContestStatus, arg.ID, arg.Status)
 	var i Contest
 	err := row.Scan(
 		&i.ID,
@@ -1137,24 +5496,61 @@ func (q *Queries) UpdateContest(ctx context.Context, arg *UpdateContestParams) (
 		&i.Status,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.HiddenAt,
+		&i.VotingMode,
+		&i.MaxChoices,
+		&i.MinBallots,
+		&i.JuryWeight,
 	)
 	return &i, err
 }
 
-const updateContestStatus = `-- name: UpdateContestStatus :one
+const updateContestVoteFraudScore = `-- name: UpdateContestVoteFraudScore :exec
+UPDATE contest_votes
+SET fraud_score = $1,
+    fraud_reasons = $2::text[],
+    flagged_at = CASE WHEN $3::bool THEN COALESCE(flagged_at, NOW()) ELSE NULL END
+WHERE id = $4
+`
+
+type UpdateContestVoteFraudScoreParams struct {
+	FraudScore   int32
+	FraudReasons []string
+	Flagged      bool
+	ID           pgtype.UUID
+}
+
+func (q *Queries) UpdateContestVoteFraudScore(ctx context.Context, arg *UpdateContestVoteFraudScoreParams) error {
+	_, err := q.db.Exec(ctx, updateContestVoteFraudScore,
+		arg.FraudScore,
+		arg.FraudReasons,
+		arg.Flagged,
+		arg.ID,
+	)
+	return err
+}
+
+const updateContestVotingMode = `-- name: UpdateContestVotingMode :one
 UPDATE contests
-SET status = $2, updated_at = NOW()
+SET voting_mode = $2, max_choices = $3, min_ballots = $4, updated_at = NOW()
 WHERE id = $1
-RETURNING id, created_by_user_id, title, description, status, created_at, updated_at
+RETURNING id, created_by_user_id, title, description, status, created_at, updated_at, hidden_at, voting_mode, max_choices, min_ballots, jury_weight
 `
 
-type UpdateContestStatusParams struct {
-	ID     pgtype.UUID
-	Status string
+type UpdateContestVotingModeParams struct {
+	ID         pgtype.UUID
+	VotingMode string
+	MaxChoices int32
+	MinBallots int32
 }
 
-func (q *Queries) UpdateContestStatus(ctx context.Context, arg *UpdateContestStatusParams) (*Contest, error) {
-	row := q.db.QueryRow(ctx, updateContestStatus, arg.ID, arg.Status)
+func (q *Queries) UpdateContestVotingMode(ctx context.Context, arg *UpdateContestVotingModeParams) (*Contest, error) {
+	row := q.db.QueryRow(ctx, updateContestVotingMode,
+		arg.ID,
+		arg.VotingMode,
+		arg.MaxChoices,
+		arg.MinBallots,
+	)
 	var i Contest
 	err := row.Scan(
 		&i.ID,
@@ -1164,25 +5560,42 @@ func (q *Queries) UpdateContestStatus(ctx context.Context, arg *UpdateContestSta
 		&i.Status,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.HiddenAt,
+		&i.VotingMode,
+		&i.MaxChoices,
+		&i.MinBallots,
+		&i.JuryWeight,
 	)
 	return &i, err
 }
 
 const updateParticipant = `-- name: UpdateParticipant :one
 UPDATE contest_participants
-SET pet_name = $2, pet_description = $3, updated_at = NOW()
+SET pet_name = $2, pet_description = $3, species = $4, breed = $5, sex = $6, birth_date = $7, updated_at = NOW()
 WHERE id = $1
-RETURNING id, contest_id, user_id, pet_name, pet_description, created_at, updated_at
+RETURNING id, contest_id, user_id, pet_name, pet_description, created_at, updated_at, hidden_at, moderation_status, moderation_reason, moderated_by_user_id, moderated_at, disqualified_at, disqualified_by_user_id, disqualification_reason, pet_id, species, breed, sex, birth_date
 `
 
 type UpdateParticipantParams struct {
 	ID             pgtype.UUID
 	PetName        string
 	PetDescription string
+	Species        *string
+	Breed          *string
+	Sex            *string
+	BirthDate      pgtype.Date
 }
 
 func (q *Queries) UpdateParticipant(ctx context.Context, arg *UpdateParticipantParams) (*ContestParticipant, error) {
-	row := q.db.QueryRow(ctx, updateParticipant, arg.ID, arg.PetName, arg.PetDescription)
+	row := q.db.QueryRow(ctx, updateParticipant,
+		arg.ID,
+		arg.PetName,
+		arg.PetDescription,
+		arg.Species,
+		arg.Breed,
+		arg.Sex,
+		arg.BirthDate,
+	)
 	var i ContestParticipant
 	err := row.Scan(
 		&i.ID,
@@ -1192,6 +5605,19 @@ func (q *Queries) UpdateParticipant(ctx context.Context, arg *UpdateParticipantP
 		&i.PetDescription,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.HiddenAt,
+		&i.ModerationStatus,
+		&i.ModerationReason,
+		&i.ModeratedByUserID,
+		&i.ModeratedAt,
+		&i.DisqualifiedAt,
+		&i.DisqualifiedByUserID,
+		&i.DisqualificationReason,
+		&i.PetID,
+		&i.Species,
+		&i.Breed,
+		&i.Sex,
+		&i.BirthDate,
 	)
 	return &i, err
 }
@@ -1213,39 +5639,294 @@ func (q *Queries) UpdateParticipantPhotoOrder(ctx context.Context, arg *UpdatePa
 	return err
 }
 
-const updateUserName = `-- name: UpdateUserName :one
+const updatePet = `-- name: UpdatePet :one
+UPDATE pets
+SET name = $2, species = $3, breed = $4, sex = $5, birth_date = $6, bio = $7, updated_at = NOW()
+WHERE id = $1
+RETURNING id, owner_user_id, name, species, breed, birth_date, bio, created_at, updated_at, sex
+`
+
+type UpdatePetParams struct {
+	ID        pgtype.UUID
+	Name      string
+	Species   *string
+	Breed     *string
+	Sex       *string
+	BirthDate pgtype.Date
+	Bio       string
+}
+
+func (q *Queries) UpdatePet(ctx context.Context, arg *UpdatePetParams) (*Pet, error) {
+	row := q.db.QueryRow(ctx, updatePet,
+		arg.ID,
+		arg.Name,
+		arg.Species,
+		arg.Breed,
+		arg.Sex,
+		arg.BirthDate,
+		arg.Bio,
+	)
+	var i Pet
+	err := row.Scan(
+		&i.ID,
+		&i.OwnerUserID,
+		&i.Name,
+		&i.Species,
+		&i.Breed,
+		&i.BirthDate,
+		&i.Bio,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Sex,
+	)
+	return &i, err
+}
+
+const updateUserAuthProviderAvatar = `-- name: UpdateUserAuthProviderAvatar :exec
+UPDATE user_auth_providers
+SET avatar_url = $3, avatar_updated_at = NOW()
+WHERE provider_uid = $1 AND provider = $2
+`
+
+type UpdateUserAuthProviderAvatarParams struct {
+	ProviderUid string
+	Provider    string
+	AvatarUrl   *string
+}
+
+func (q *Queries) UpdateUserAuthProviderAvatar(ctx context.Context, arg *UpdateUserAuthProviderAvatarParams) error {
+	_, err := q.db.Exec(ctx, updateUserAuthProviderAvatar, arg.ProviderUid, arg.Provider, arg.AvatarUrl)
+	return err
+}
+
+const updateUserProfile = `-- name: UpdateUserProfile :one
 UPDATE users
-SET name = $2
+SET name = $2, bio = $3, locale = $4
 WHERE user_id = $1
-RETURNING user_id, name, created_at
+RETURNING user_id, name, created_at, avatar_url, bio, avatar_thumb_url, locale, deleted_at
 `
 
-type UpdateUserNameParams struct {
+type UpdateUserProfileParams struct {
 	UserID int64
 	Name   string
+	Bio    string
+	Locale string
 }
 
-func (q *Queries) UpdateUserName(ctx context.Context, arg *UpdateUserNameParams) (*User, error) {
-	row := q.db.QueryRow(ctx, updateUserName, arg.UserID, arg.Name)
+func (q *Queries) UpdateUserProfile(ctx context.Context, arg *UpdateUserProfileParams) (*User, error) {
+	row := q.db.QueryRow(ctx, updateUserProfile,
+		arg.UserID,
+		arg.Name,
+		arg.Bio,
+		arg.Locale,
+	)
 	var i User
-	err := row.Scan(&i.UserID, &i.Name, &i.CreatedAt)
+	err := row.Scan(
+		&i.UserID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.AvatarUrl,
+		&i.Bio,
+		&i.AvatarThumbUrl,
+		&i.Locale,
+		&i.DeletedAt,
+	)
 	return &i, err
 }
 
-const upsertContestVote = `-- name: UpsertContestVote :one
+const updateWebhookEndpoint = `-- name: UpdateWebhookEndpoint :one
+UPDATE webhook_endpoints
+SET url = $2, event_types = $3, active = $4, updated_at = NOW()
+WHERE id = $1
+RETURNING id, contest_id, url, secret, event_types, active, created_by_user_id, created_at, updated_at
+`
 
-INSERT INTO contest_votes (id, contest_id, participant_id, user_id)
-VALUES ($1, $2, $3, $4)
-ON CONFLICT (contest_id, user_id) DO UPDATE
+type UpdateWebhookEndpointParams struct {
+	ID         pgtype.UUID
+	Url        string
+	EventTypes []string
+	Active     bool
+}
+
+func (q *Queries) UpdateWebhookEndpoint(ctx context.Context, arg *UpdateWebhookEndpointParams) (*WebhookEndpoint, error) {
+	row := q.db.QueryRow(ctx, updateWebhookEndpoint,
+		arg.ID,
+		arg.Url,
+		arg.EventTypes,
+		arg.Active,
+	)
+	var i WebhookEndpoint
+	err := row.Scan(
+		&i.ID,
+		&i.ContestID,
+		&i.Url,
+		&i.Secret,
+		&i.EventTypes,
+		&i.Active,
+		&i.CreatedByUserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return &i, err
+}
+
+const upsertBracketVote = `-- name: UpsertBracketVote :exec
+INSERT INTO contest_bracket_votes (matchup_id, user_id, participant_id)
+VALUES ($1, $2, $3)
+ON CONFLICT (matchup_id, user_id) DO UPDATE
 SET participant_id = EXCLUDED.participant_id, updated_at = NOW()
-RETURNING id, contest_id, participant_id, user_id, created_at, updated_at
 `
 
-type UpsertContestVoteParams struct {
-	ID            pgtype.UUID
-	ContestID     pgtype.UUID
-	ParticipantID pgtype.UUID
+type UpsertBracketVoteParams struct {
+	MatchupID     pgtype.UUID
 	UserID        int64
+	ParticipantID pgtype.UUID
+}
+
+func (q *Queries) UpsertBracketVote(ctx context.Context, arg *UpsertBracketVoteParams) error {
+	_, err := q.db.Exec(ctx, upsertBracketVote, arg.MatchupID, arg.UserID, arg.ParticipantID)
+	return err
+}
+
+const upsertContestBracket = `-- name: UpsertContestBracket :one
+INSERT INTO contest_brackets (contest_id, seeding, round_duration_minutes)
+VALUES ($1, $2, $3)
+ON CONFLICT (contest_id) DO UPDATE
+SET seeding = EXCLUDED.seeding, round_duration_minutes = EXCLUDED.round_duration_minutes, updated_at = NOW()
+RETURNING contest_id, seeding, round_duration_minutes, status, current_round, champion_participant_id, created_at, updated_at
+`
+
+type UpsertContestBracketParams struct {
+	ContestID            pgtype.UUID
+	Seeding              string
+	RoundDurationMinutes int32
+}
+
+func (q *Queries) UpsertContestBracket(ctx context.Context, arg *UpsertContestBracketParams) (*ContestBracket, error) {
+	row := q.db.QueryRow(ctx, upsertContestBracket, arg.ContestID, arg.Seeding, arg.RoundDurationMinutes)
+	var i ContestBracket
+	err := row.Scan(
+		&i.ContestID,
+		&i.Seeding,
+		&i.RoundDurationMinutes,
+		&i.Status,
+		&i.CurrentRound,
+		&i.ChampionParticipantID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return &i, err
+}
+
+const upsertContestEntryRules = `-- name: UpsertContestEntryRules :one
+INSERT INTO contest_entry_rules (
+    contest_id, max_entries_per_user, max_entries, max_photos_per_entry, max_photo_size_mb,
+    video_allowed, max_video_duration_sec, max_video_size_mb, required_fields, entry_deadline, require_approval,
+    allowed_species, min_age_months, max_age_months
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+ON CONFLICT (contest_id) DO UPDATE
+SET max_entries_per_user = EXCLUDED.max_entries_per_user,
+    max_entries = EXCLUDED.max_entries,
+    max_photos_per_entry = EXCLUDED.max_photos_per_entry,
+    max_photo_size_mb = EXCLUDED.max_photo_size_mb,
+    video_allowed = EXCLUDED.video_allowed,
+    max_video_duration_sec = EXCLUDED.max_video_duration_sec,
+    max_video_size_mb = EXCLUDED.max_video_size_mb,
+    required_fields = EXCLUDED.required_fields,
+    entry_deadline = EXCLUDED.entry_deadline,
+    require_approval = EXCLUDED.require_approval,
+    allowed_species = EXCLUDED.allowed_species,
+    min_age_months = EXCLUDED.min_age_months,
+    max_age_months = EXCLUDED.max_age_months,
+    updated_at = NOW()
+RETURNING contest_id, max_entries_per_user, max_entries, max_photos_per_entry, max_photo_size_mb, video_allowed, max_video_duration_sec, max_video_size_mb, required_fields, entry_deadline, updated_at, require_approval, allowed_species, min_age_months, max_age_months
+`
+
+type UpsertContestEntryRulesParams struct {
+	ContestID           pgtype.UUID
+	MaxEntriesPerUser   int32
+	MaxEntries          int32
+	MaxPhotosPerEntry   int32
+	MaxPhotoSizeMb      int32
+	VideoAllowed        bool
+	MaxVideoDurationSec int32
+	MaxVideoSizeMb      int32
+	RequiredFields      []string
+	EntryDeadline       pgtype.Timestamptz
+	RequireApproval     bool
+	AllowedSpecies      []string
+	MinAgeMonths        int32
+	MaxAgeMonths        int32
+}
+
+func (q *Queries) UpsertContestEntryRules(ctx context.Context, arg *UpsertContestEntryRulesParams) (*ContestEntryRule, error) {
+	row := q.db.QueryRow(ctx, upsertContestEntryRules,
+		arg.ContestID,
+		arg.MaxEntriesPerUser,
+		arg.MaxEntries,
+		arg.MaxPhotosPerEntry,
+		arg.MaxPhotoSizeMb,
+		arg.VideoAllowed,
+		arg.MaxVideoDurationSec,
+		arg.MaxVideoSizeMb,
+		arg.RequiredFields,
+		arg.EntryDeadline,
+		arg.RequireApproval,
+		arg.AllowedSpecies,
+		arg.MinAgeMonths,
+		arg.MaxAgeMonths,
+	)
+	var i ContestEntryRule
+	err := row.Scan(
+		&i.ContestID,
+		&i.MaxEntriesPerUser,
+		&i.MaxEntries,
+		&i.MaxPhotosPerEntry,
+		&i.MaxPhotoSizeMb,
+		&i.VideoAllowed,
+		&i.MaxVideoDurationSec,
+		&i.MaxVideoSizeMb,
+		&i.RequiredFields,
+		&i.EntryDeadline,
+		&i.UpdatedAt,
+		&i.RequireApproval,
+		&i.AllowedSpecies,
+		&i.MinAgeMonths,
+		&i.MaxAgeMonths,
+	)
+	return &i, err
+}
+
+const upsertContestVote = `-- name: UpsertContestVote :one
+
+INSERT INTO contest_votes (id, contest_id, participant_id, user_id, ip_hash, user_agent, auth_provider, account_created_at, category_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+ON CONFLICT (contest_id, category_id, user_id) DO UPDATE
+SET participant_id = EXCLUDED.participant_id,
+    ip_hash = EXCLUDED.ip_hash,
+    user_agent = EXCLUDED.user_agent,
+    auth_provider = EXCLUDED.auth_provider,
+    account_created_at = EXCLUDED.account_created_at,
+    voided_at = NULL,
+    voided_by_user_id = NULL,
+    void_reason = '',
+    recastable = FALSE,
+    updated_at = NOW()
+WHERE contest_votes.voided_at IS NULL OR contest_votes.recastable
+RETURNING id, contest_id, participant_id, user_id, created_at, updated_at, ip_hash, user_agent, auth_provider, account_created_at, fraud_score, fraud_reasons, flagged_at, voided_at, voided_by_user_id, void_reason, category_id, recastable
+`
+
+type UpsertContestVoteParams struct {
+	ID               pgtype.UUID
+	ContestID        pgtype.UUID
+	ParticipantID    pgtype.UUID
+	UserID           int64
+	IpHash           string
+	UserAgent        string
+	AuthProvider     string
+	AccountCreatedAt pgtype.Timestamptz
+	CategoryID       pgtype.UUID
 }
 
 // Contest Votes
@@ -1255,6 +5936,11 @@ func (q *Queries) UpsertContestVote(ctx context.Context, arg *UpsertContestVoteP
 		arg.ContestID,
 		arg.ParticipantID,
 		arg.UserID,
+		arg.IpHash,
+		arg.UserAgent,
+		arg.AuthProvider,
+		arg.AccountCreatedAt,
+		arg.CategoryID,
 	)
 	var i ContestVote
 	err := row.Scan(
@@ -1264,6 +5950,116 @@ func (q *Queries) UpsertContestVote(ctx context.Context, arg *UpsertContestVoteP
 		&i.UserID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.IpHash,
+		&i.UserAgent,
+		&i.AuthProvider,
+		&i.AccountCreatedAt,
+		&i.FraudScore,
+		&i.FraudReasons,
+		&i.FlaggedAt,
+		&i.VoidedAt,
+		&i.VoidedByUserID,
+		&i.VoidReason,
+		&i.CategoryID,
+		&i.Recastable,
+	)
+	return &i, err
+}
+
+const upsertContestVotingPolicy = `-- name: UpsertContestVotingPolicy :one
+INSERT INTO contest_voting_policies (contest_id, min_account_age_hours, required_providers, exclude_own_participants, invite_only)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (contest_id) DO UPDATE
+SET min_account_age_hours = EXCLUDED.min_account_age_hours,
+    required_providers = EXCLUDED.required_providers,
+    exclude_own_participants = EXCLUDED.exclude_own_participants,
+    invite_only = EXCLUDED.invite_only,
+    updated_at = NOW()
+RETURNING contest_id, min_account_age_hours, required_providers, exclude_own_participants, invite_only, updated_at
+`
+
+type UpsertContestVotingPolicyParams struct {
+	ContestID              pgtype.UUID
+	MinAccountAgeHours     int32
+	RequiredProviders      []string
+	ExcludeOwnParticipants bool
+	InviteOnly             bool
+}
+
+func (q *Queries) UpsertContestVotingPolicy(ctx context.Context, arg *UpsertContestVotingPolicyParams) (*ContestVotingPolicy, error) {
+	row := q.db.QueryRow(ctx, upsertContestVotingPolicy,
+		arg.ContestID,
+		arg.MinAccountAgeHours,
+		arg.RequiredProviders,
+		arg.ExcludeOwnParticipants,
+		arg.InviteOnly,
+	)
+	var i ContestVotingPolicy
+	err := row.Scan(
+		&i.ContestID,
+		&i.MinAccountAgeHours,
+		&i.RequiredProviders,
+		&i.ExcludeOwnParticipants,
+		&i.InviteOnly,
+		&i.UpdatedAt,
+	)
+	return &i, err
+}
+
+const upsertJuryScores = `-- name: UpsertJuryScores :exec
+INSERT INTO contest_jury_scores (criterion_id, participant_id, juror_user_id, contest_id, score, comment)
+SELECT s.criterion_id, $1, $2, $3, s.score, s.comment
+FROM unnest($4::uuid[], $5::int[], $6::text[]) AS s(criterion_id, score, comment)
+ON CONFLICT (criterion_id, participant_id, juror_user_id) DO UPDATE
+SET score = EXCLUDED.score, comment = EXCLUDED.comment, updated_at = NOW()
+`
+
+type UpsertJuryScoresParams struct {
+	ParticipantID pgtype.UUID
+	JurorUserID   int64
+	ContestID     pgtype.UUID
+	CriterionIds  []pgtype.UUID
+	Scores        []int32
+	Comments      []string
+}
+
+func (q *Queries) UpsertJuryScores(ctx context.Context, arg *UpsertJuryScoresParams) error {
+	_, err := q.db.Exec(ctx, upsertJuryScores,
+		arg.ParticipantID,
+		arg.JurorUserID,
+		arg.ContestID,
+		arg.CriterionIds,
+		arg.Scores,
+		arg.Comments,
+	)
+	return err
+}
+
+const upsertNotificationPreferences = `-- name: UpsertNotificationPreferences :one
+INSERT INTO notification_preferences (user_id, email_voting_started, email_results_published)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id) DO UPDATE
+SET email_voting_started = EXCLUDED.email_voting_started,
+    email_results_published = EXCLUDED.email_results_published,
+    updated_at = NOW()
+RETURNING user_id, email, email_voting_started, email_results_published, updated_at
+`
+
+type UpsertNotificationPreferencesParams struct {
+	UserID                int64
+	EmailVotingStarted    bool
+	EmailResultsPublished bool
+}
+
+func (q *Queries) UpsertNotificationPreferences(ctx context.Context, arg *UpsertNotificationPreferencesParams) (*NotificationPreference, error) {
+	row := q.db.QueryRow(ctx, upsertNotificationPreferences, arg.UserID, arg.EmailVotingStarted, arg.EmailResultsPublished)
+	var i NotificationPreference
+	err := row.Scan(
+		&i.UserID,
+		&i.Email,
+		&i.EmailVotingStarted,
+		&i.EmailResultsPublished,
+		&i.UpdatedAt,
 	)
 	return &i, err
 }
@@ -1323,3 +6119,127 @@ func (q *Queries) UpsertPhotoLike(ctx context.Context, arg *UpsertPhotoLikeParam
 	)
 	return &i, err
 }
+
+const upsertUserPrivacySettings = `-- name: UpsertUserPrivacySettings :one
+INSERT INTO user_privacy_settings (user_id, show_contests, show_pets, show_results)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id) DO UPDATE
+SET show_contests = EXCLUDED.show_contests,
+    show_pets = EXCLUDED.show_pets,
+    show_results = EXCLUDED.show_results,
+    updated_at = NOW()
+RETURNING user_id, show_contests, show_pets, show_results, updated_at
+`
+
+type UpsertUserPrivacySettingsParams struct {
+	UserID       int64
+	ShowContests bool
+	ShowPets     bool
+	ShowResults  bool
+}
+
+func (q *Queries) UpsertUserPrivacySettings(ctx context.Context, arg *UpsertUserPrivacySettingsParams) (*UserPrivacySetting, error) {
+	row := q.db.QueryRow(ctx, upsertUserPrivacySettings,
+		arg.UserID,
+		arg.ShowContests,
+		arg.ShowPets,
+		arg.ShowResults,
+	)
+	var i UserPrivacySetting
+	err := row.Scan(
+		&i.UserID,
+		&i.ShowContests,
+		&i.ShowPets,
+		&i.ShowResults,
+		&i.UpdatedAt,
+	)
+	return &i, err
+}
+
+const voidContestVotes = `-- name: VoidContestVotes :many
+UPDATE contest_votes
+SET voided_at = NOW(), voided_by_user_id = $1, void_reason = $2
+WHERE contest_id = $3 AND id = ANY($4::uuid[]) AND voided_at IS NULL
+RETURNING participant_id, user_id, category_id
+`
+
+type VoidContestVotesParams struct {
+	VoidedByUserID *int64
+	VoidReason     string
+	ContestID      pgtype.UUID
+	Ids            []pgtype.UUID
+}
+
+type VoidContestVotesRow struct {
+	ParticipantID pgtype.UUID
+	UserID        int64
+	CategoryID    pgtype.UUID
+}
+
+func (q *Queries) VoidContestVotes(ctx context.Context, arg *VoidContestVotesParams) ([]*VoidContestVotesRow, error) {
+	rows, err := q.db.Query(ctx, voidContestVotes,
+		arg.VoidedByUserID,
+		arg.VoidReason,
+		arg.ContestID,
+		arg.Ids,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*VoidContestVotesRow
+	for rows.Next() {
+		var i VoidContestVotesRow
+		if err := rows.Scan(&i.ParticipantID, &i.UserID, &i.CategoryID); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const voidParticipantVotes = `-- name: VoidParticipantVotes :many
+UPDATE contest_votes cv
+SET voided_at = NOW(), voided_by_user_id = $1, void_reason = $2, recastable = TRUE
+WHERE cv.voided_at IS NULL AND (
+    cv.participant_id = $3
+    OR EXISTS (SELECT 1 FROM contest_vote_choices c WHERE c.vote_id = cv.id AND c.participant_id = $3)
+)
+RETURNING cv.participant_id, cv.user_id, cv.category_id
+`
+
+type VoidParticipantVotesParams struct {
+	VoidedByUserID *int64
+	VoidReason     string
+	ParticipantID  pgtype.UUID
+}
+
+type VoidParticipantVotesRow struct {
+	ParticipantID pgtype.UUID
+	UserID        int64
+	CategoryID    pgtype.UUID
+}
+
+// Аннулирует голоса и бюллетени, в которых выбран участник; автор может проголосовать заново
+func (q *Queries) VoidParticipantVotes(ctx context.Context, arg *VoidParticipantVotesParams) ([]*VoidParticipantVotesRow, error) {
+	rows, err := q.db.Query(ctx, voidParticipantVotes, arg.VoidedByUserID, arg.VoidReason, arg.ParticipantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*VoidParticipantVotesRow
+	for rows.Next() {
+		var i VoidParticipantVotesRow
+		if err := rows.Scan(&i.ParticipantID, &i.UserID, &i.CategoryID); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
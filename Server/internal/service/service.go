@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"toppet/server/internal/model"
 )
@@ -13,6 +14,99 @@ type (
 		refreshTokenService TokenService
 		hub                 Hub
 		providersUserData   map[string]ProviderUserData
+		mailer              Mailer
+		storage             MediaStorage
+		emailLogin          EmailLoginConfig
+		voteFraud           VoteFraudConfig
+		emailNotifications  EmailNotificationConfig
+		telegram            TelegramConfig
+		webhooks            WebhookConfig
+	}
+
+	// EmailLoginConfig настройки входа по ссылке из письма
+	EmailLoginConfig struct {
+		// Secret ключ HMAC подписи токенов
+		Secret []byte
+		// TTL время жизни ссылки
+		TTL time.Duration
+		// CallbackURL адрес, на который ведет ссылка (к нему добавляется ?token=...)
+		CallbackURL string
+		// RateLimit максимум писем на один адрес за RateWindow
+		RateLimit  int
+		RateWindow time.Duration
+	}
+
+	// EmailNotificationConfig настройки писем о событиях конкурсов. Без Templates или Secret письма не отправляются.
+	EmailNotificationConfig struct {
+		Templates EmailTemplates
+		// Secret ключ HMAC подписи ссылок отписки
+		Secret []byte
+		// UnsubscribeURL адрес отписки в один клик (к нему добавляется ?token=...)
+		UnsubscribeURL string
+		// SiteURL адрес сайта, от которого строятся ссылки на конкурсы
+		SiteURL string
+	}
+
+	// TelegramConfig настройки Telegram бота. Без Bot и BotUsername бот выключен.
+	TelegramConfig struct {
+		Bot TelegramBot
+		// BotUsername имя бота без @, из него строятся ссылки t.me/<bot>
+		BotUsername string
+		// ChannelID канал для анонсов конкурсов: @username или числовой ID; пустой - анонсы выключены
+		ChannelID string
+		// SiteURL адрес сайта, от которого строятся ссылки на конкурсы
+		SiteURL string
+	}
+
+	// WebhookConfig настройки исходящих webhooks. Без Sender события не сохраняются и не отправляются.
+	WebhookConfig struct {
+		Sender WebhookSender
+		// AllowInsecureURLs разрешает http:// адреса (для локальной разработки)
+		AllowInsecureURLs bool
+	}
+
+	// VoteFraudConfig настройки антифрода голосования. Нулевые значения заменяются значениями по умолчанию.
+	VoteFraudConfig struct {
+		// IPHashSecret ключ HMAC для хэширования IP (в базе IP в открытом виде не хранится)
+		IPHashSecret []byte
+		// NewAccountAge аккаунт младше этого возраста на момент голосования считается новым
+		NewAccountAge time.Duration
+		// BurstWindow/BurstSize: BurstSize голосов новых аккаунтов за одного участника в пределах BurstWindow
+		BurstWindow time.Duration
+		BurstSize   int
+		// SharedIPVoters число разных голосующих с одного IP, начиная с которого голоса подозрительны
+		SharedIPVoters int
+		// FlagScore порог оценки, с которого голос попадает в список на проверку
+		FlagScore int
+	}
+
+	// Mailer интерфейс для отправки писем
+	Mailer interface {
+		Send(ctx context.Context, msg *model.EmailMessage) error
+	}
+
+	// EmailTemplates шаблоны писем о событиях; locale без своего шаблона получает русский вариант
+	EmailTemplates interface {
+		Render(name string, locale model.Locale, data any) (*model.EmailMessage, error)
+	}
+
+	// TelegramBot клиент Telegram Bot API. Ошибка SendMessage для чата, в который бот больше не может писать,
+	// оборачивает model.ErrTelegramChatUnavailable; при ограничении частоты ошибка реализует RetryAfter() time.Duration.
+	TelegramBot interface {
+		GetUpdates(ctx context.Context, offset int64, timeout time.Duration) ([]*model.TelegramUpdate, error)
+		SendMessage(ctx context.Context, chatID, text string) error
+	}
+
+	// WebhookSender подписывает и отправляет событие на webhook. Если webhook ответил не 2xx,
+	// вместе с ошибкой возвращается его ответ.
+	WebhookSender interface {
+		Send(ctx context.Context, request *model.WebhookRequest) (*model.WebhookResponse, error)
+	}
+
+	// MediaStorage объектное хранилище загруженных файлов
+	MediaStorage interface {
+		// Delete удаляет файл по URL, выданному при загрузке; чужие URL (например, аватар провайдера) пропускаются
+		Delete(ctx context.Context, storedURL string) error
 	}
 
 	ProviderUserData interface {
@@ -24,31 +118,150 @@ type (
 		CreateUser(ctx context.Context, name string) (*model.User, error)
 		CreateUserFromProvider(ctx context.Context, userData *model.UserProfileFromProvider) (*model.User, error)
 		GetUser(ctx context.Context, userID model.UserID) (*model.User, error)
-		UpdateUserName(ctx context.Context, userID model.UserID, name string) (*model.User, error)
+		UpdateUserProfile(ctx context.Context, userID model.UserID, name, bio string, locale model.Locale) (*model.User, error)
+		SetUserAvatar(ctx context.Context, userID model.UserID, avatarURL, thumbURL *string) (*model.User, error)
 		GetUserAuthProvidersByProviderUid(ctx context.Context, providerUID, provider string) (*model.UserAuthProvider, error)
 		AddUserAuthProviders(ctx context.Context, userData *model.UserProfileFromProvider, userID model.UserID) (*model.UserAuthProvider, error)
 		GetUserAuthProvidersByUserID(ctx context.Context, userID model.UserID) ([]*model.UserAuthProvider, error)
 		SetUserAvatarIfEmpty(ctx context.Context, userID model.UserID, avatarURL *string) error
+		UpdateUserAuthProviderAvatar(ctx context.Context, providerUID, provider string, avatarURL *string) error
+		GetLatestProviderAvatar(ctx context.Context, userID model.UserID) (string, error)
+		GetUserPrivacySettings(ctx context.Context, userID model.UserID) (*model.PrivacySettings, error)
+		UpsertUserPrivacySettings(ctx context.Context, userID model.UserID, settings *model.PrivacySettings) (*model.PrivacySettings, error)
+
+		// Account deletion
+		CreateAccountDeletionJob(ctx context.Context, userID model.UserID) (*model.AccountDeletionJob, error)
+		GetAccountDeletionJob(ctx context.Context, jobID string) (*model.AccountDeletionJob, error)
+		ClaimAccountDeletionJob(ctx context.Context, staleBefore time.Time) (*model.AccountDeletionJob, error)
+		FinishAccountDeletionJob(ctx context.Context, jobID string, status model.AccountDeletionStatus, lastError string) error
+		DeleteUserPersonalData(ctx context.Context, userID model.UserID, name string) error
+
+		// Notifications
+		CreateNotifications(ctx context.Context, userIDs []model.UserID, notification *model.Notification) ([]*model.Notification, error)
+		ListNotifications(ctx context.Context, userID model.UserID, unreadOnly bool, limit, offset int) ([]*model.Notification, error)
+		CountNotifications(ctx context.Context, userID model.UserID) (int64, int64, error)
+		MarkNotificationRead(ctx context.Context, userID model.UserID, notificationID string) error
+		MarkAllNotificationsRead(ctx context.Context, userID model.UserID) (int64, error)
+
+		// Email notifications
+		GetNotificationPreferences(ctx context.Context, userID model.UserID) (*model.NotificationPreferences, error)
+		UpsertNotificationPreferences(ctx context.Context, userID model.UserID, prefs *model.NotificationPreferences) (*model.NotificationPreferences, error)
+		SetNotificationEmailIfEmpty(ctx context.Context, userID model.UserID, email string) error
+		ListEmailRecipients(ctx context.Context, userIDs []model.UserID, notificationType model.NotificationType) ([]*model.EmailRecipient, error)
+		EnqueueEmails(ctx context.Context, emails []*model.OutboxEmail) error
+		ClaimEmailOutbox(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*model.OutboxEmail, error)
+		MarkEmailSent(ctx context.Context, emailID string) error
+		FailEmailAttempt(ctx context.Context, emailID string, status model.OutboxStatus, lastError string, nextAttemptAt time.Time) error
+
+		// Telegram
+		CreateTelegramLinkToken(ctx context.Context, tokenHash string, userID model.UserID, expiresAt time.Time) error
+		ConsumeTelegramLinkToken(ctx context.Context, tokenHash string) (model.UserID, error)
+		LinkTelegramAccount(ctx context.Context, userID model.UserID, chatID int64, username string) (*model.TelegramAccount, error)
+		GetTelegramAccount(ctx context.Context, userID model.UserID) (*model.TelegramAccount, error)
+		GetTelegramAccountByChat(ctx context.Context, chatID int64) (*model.TelegramAccount, error)
+		DeleteTelegramAccount(ctx context.Context, userID model.UserID) (int64, error)
+		DeleteTelegramChat(ctx context.Context, chatID int64) error
+		SubscribeTelegramChat(ctx context.Context, chatID int64, contestID model.ContestID) error
+		UnsubscribeTelegramChat(ctx context.Context, chatID int64, contestID model.ContestID) (int64, error)
+		ListTelegramSubscriptions(ctx context.Context, chatID int64) ([]*model.TelegramSubscription, error)
+		ListTelegramContestChats(ctx context.Context, contestID model.ContestID) ([]int64, error)
+		EnqueueTelegramMessages(ctx context.Context, messages []*model.TelegramMessage) error
+		ClaimTelegramOutbox(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*model.TelegramMessage, error)
+		MarkTelegramMessageSent(ctx context.Context, messageID string) error
+		FailTelegramMessageAttempt(ctx context.Context, messageID string, status model.OutboxStatus, lastError string, nextAttemptAt time.Time) error
+		CreateTelegramAnnouncement(ctx context.Context, announcement *model.TelegramAnnouncement, cooldown time.Duration) (*model.TelegramAnnouncement, error)
+		ListTelegramAnnouncements(ctx context.Context, contestID model.ContestID, limit int) ([]*model.TelegramAnnouncement, error)
+
+		// Webhooks
+		CreateWebhookEndpoint(ctx context.Context, endpoint *model.WebhookEndpoint) (*model.WebhookEndpoint, error)
+		GetWebhookEndpoint(ctx context.Context, endpointID string) (*model.WebhookEndpoint, error)
+		ListWebhookEndpoints(ctx context.Context, contestID model.ContestID) ([]*model.WebhookEndpoint, error)
+		UpdateWebhookEndpoint(ctx context.Context, endpoint *model.WebhookEndpoint) (*model.WebhookEndpoint, error)
+		DeleteWebhookEndpoint(ctx context.Context, endpointID string) error
+		EnqueueWebhookEvent(ctx context.Context, contestID model.ContestID, eventType model.WebhookEventType, data []byte) (int64, error)
+		ClaimWebhookDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*model.WebhookDeliveryJob, error)
+		FinishWebhookDeliveryAttempt(ctx context.Context, deliveryID string, status model.OutboxStatus, response *model.WebhookResponse, lastError string, nextAttemptAt time.Time) error
+		ListWebhookDeliveries(ctx context.Context, endpointID string, limit, offset int) ([]*model.WebhookDelivery, int64, error)
+		GetWebhookDelivery(ctx context.Context, deliveryID string) (*model.WebhookDelivery, error)
+		RedeliverWebhook(ctx context.Context, deliveryID string) (*model.WebhookDelivery, error)
+
+		// Email login
+		CreateEmailLoginToken(ctx context.Context, tokenHash, email string, expiresAt time.Time) error
+		ConsumeEmailLoginToken(ctx context.Context, tokenHash string) (string, error)
+		CountEmailLoginTokensSince(ctx context.Context, email string, since time.Time) (int64, error)
+
+		// WebSocket tickets
+		CreateWSTicket(ctx context.Context, ticketHash string, userID model.UserID, expiresAt time.Time) error
+		ConsumeWSTicket(ctx context.Context, ticketHash string) (model.UserID, error)
+		DeleteExpiredWSTickets(ctx context.Context) error
+
+		// Roles & Moderation
+		ListUserRoles(ctx context.Context, userID model.UserID) ([]model.Role, error)
+		ListActiveUserRoles(ctx context.Context, userID model.UserID) ([]model.Role, error)
+		AddUserRole(ctx context.Context, userID model.UserID, role model.Role, grantedBy model.UserID) error
+		RemoveUserRole(ctx context.Context, userID model.UserID, role model.Role) error
+		CreateModerationAction(ctx context.Context, action *model.ModerationAction) (*model.ModerationAction, error)
+		ListModerationActions(ctx context.Context, limit, offset int) ([]*model.ModerationAction, int64, error)
+		ModerateContent(ctx context.Context, action *model.ModerationAction) (*model.ModerationAction, model.ContestID, error)
+		DeleteChatMessageByID(ctx context.Context, messageID model.ChatMessageID) (model.ContestID, error)
 
 		// Contest
 		CreateContest(ctx context.Context, userID model.UserID, title, description string) (*model.Contest, error)
 		GetContest(ctx context.Context, contestID model.ContestID) (*model.Contest, error)
 		ListContests(ctx context.Context, status *model.ContestStatus, limit, offset int) ([]*model.Contest, int64, error)
+		ListPublicContestsByCreator(ctx context.Context, userID model.UserID, limit int) ([]*model.Contest, error)
+		ListContestsByCreator(ctx context.Context, userID model.UserID) ([]*model.Contest, error)
 		UpdateContest(ctx context.Context, contestID model.ContestID, title, description string) (*model.Contest, error)
 		UpdateContestStatus(ctx context.Context, contestID model.ContestID, status model.ContestStatus) (*model.Contest, error)
+		UpdateContestVotingMode(ctx context.Context, contestID model.ContestID, mode model.VotingMode, maxChoices, minBallots int) (*model.Contest, error)
+		UpdateContestJuryWeight(ctx context.Context, contestID model.ContestID, juryWeight int) (*model.Contest, error)
 		DeleteContest(ctx context.Context, contestID model.ContestID) error
 
+		// Contest members
+		CreateContestMember(ctx context.Context, member *model.ContestMember) (*model.ContestMember, error)
+		GetContestMember(ctx context.Context, contestID model.ContestID, userID model.UserID) (*model.ContestMember, error)
+		ListContestMembers(ctx context.Context, contestID model.ContestID) ([]*model.ContestMember, error)
+		UpdateContestMemberRole(ctx context.Context, contestID model.ContestID, userID model.UserID, role model.ContestMemberRole) (*model.ContestMember, error)
+		AcceptContestMemberInvite(ctx context.Context, contestID model.ContestID, userID model.UserID) (*model.ContestMember, error)
+		DeleteContestMember(ctx context.Context, contestID model.ContestID, userID model.UserID) error
+		TransferContestOwnership(ctx context.Context, contestID model.ContestID, previousOwnerID, newOwnerID model.UserID) error
+
 		// Participant
-		CreateParticipant(ctx context.Context, contestID model.ContestID, userID model.UserID, petName, petDescription string) (*model.Participant, error)
+		CreateParticipant(ctx context.Context, contestID model.ContestID, userID model.UserID, petID model.PetID, petName, petDescription string, attrs model.PetAttributes, status model.EntryModerationStatus, maxEntries, maxEntriesPerUser int) (*model.Participant, error)
 		GetParticipant(ctx context.Context, participantID model.ParticipantID) (*model.Participant, error)
 		GetParticipantByContestAndUser(ctx context.Context, contestID model.ContestID, userID model.UserID) (*model.Participant, error)
+		ListParticipantsByUser(ctx context.Context, userID model.UserID) ([]*model.Participant, error)
 		ListParticipantsByContest(ctx context.Context, contestID model.ContestID) ([]*model.Participant, error)
-		UpdateParticipant(ctx context.Context, participantID model.ParticipantID, petName, petDescription string) (*model.Participant, error)
+		UpdateParticipant(ctx context.Context, participantID model.ParticipantID, petName, petDescription string, attrs model.PetAttributes) (*model.Participant, error)
 		DeleteParticipant(ctx context.Context, participantID model.ParticipantID) error
+		CountParticipantsByContest(ctx context.Context, contestID model.ContestID) (int64, error)
+		CountParticipantsByContestAndUser(ctx context.Context, contestID model.ContestID, userID model.UserID) (int64, error)
+		ListParticipantsByModerationStatus(ctx context.Context, contestID model.ContestID, status model.EntryModerationStatus) ([]*model.Participant, error)
+		SetParticipantModeration(ctx context.Context, participantID model.ParticipantID, status model.EntryModerationStatus, reason string, moderatorID model.UserID) error
+		// DisqualifyParticipant дисквалифицирует участника и аннулирует голоса за него одной транзакцией
+		DisqualifyParticipant(ctx context.Context, contestID model.ContestID, participantID model.ParticipantID, actorID model.UserID, reason string) ([]*model.Vote, bool, error)
+
+		// Pets
+		CreatePet(ctx context.Context, pet *model.Pet) (*model.Pet, error)
+		GetPet(ctx context.Context, petID model.PetID) (*model.Pet, error)
+		ListPetsByOwner(ctx context.Context, userID model.UserID) ([]*model.Pet, error)
+		UpdatePet(ctx context.Context, pet *model.Pet) (*model.Pet, error)
+		DeletePet(ctx context.Context, petID model.PetID) error
+		ListPetEntries(ctx context.Context, petID model.PetID) ([]*model.PetEntry, error)
+		AddPetPhoto(ctx context.Context, petID model.PetID, url string, thumbURL *string) (*model.PetPhoto, error)
+		ListPetPhotos(ctx context.Context, petID model.PetID) ([]*model.PetPhoto, error)
+		CountPetPhotos(ctx context.Context, petID model.PetID) (int64, error)
+		DeletePetPhoto(ctx context.Context, petID model.PetID, photoID string) error
+
+		// Species & Breeds
+		ListSpecies(ctx context.Context) ([]*model.Species, error)
+		ListBreeds(ctx context.Context, species string) ([]*model.Breed, error)
+		GetBreed(ctx context.Context, code string) (*model.Breed, error)
 
 		// Photos & Videos
 		AddParticipantPhoto(ctx context.Context, participantID model.ParticipantID, url string, thumbURL *string) (*model.Photo, error)
 		GetPhotosByParticipantID(ctx context.Context, participantID model.ParticipantID) ([]*model.Photo, error)
+		CountPhotosByParticipant(ctx context.Context, participantID model.ParticipantID) (int64, error)
 		DeleteParticipantPhoto(ctx context.Context, participantID model.ParticipantID, photoID string) error
 		UpdateParticipantPhotoOrder(ctx context.Context, participantID model.ParticipantID, photoIDs []string) error
 		UpsertParticipantVideo(ctx context.Context, participantID model.ParticipantID, url string) (*model.Video, error)
@@ -56,26 +269,83 @@ type (
 		DeleteParticipantVideo(ctx context.Context, participantID model.ParticipantID) error
 
 		// Votes
-		UpsertContestVote(ctx context.Context, contestID model.ContestID, participantID model.ParticipantID, userID model.UserID) (*model.Vote, error)
-		GetContestVoteByUser(ctx context.Context, contestID model.ContestID, userID model.UserID) (*model.Vote, error)
-		DeleteContestVoteByUser(ctx context.Context, contestID model.ContestID, userID model.UserID) (model.ParticipantID, error)
+		UpsertContestVote(ctx context.Context, contestID model.ContestID, categoryID model.CategoryID, participantID model.ParticipantID, userID model.UserID, meta *model.VoteMetadata, choices []model.BallotChoice) (*model.Vote, error)
+		GetContestVoteByUser(ctx context.Context, contestID model.ContestID, categoryID model.CategoryID, userID model.UserID) (*model.Vote, error)
+		ListContestVotesByUser(ctx context.Context, userID model.UserID) ([]*model.Vote, error)
+		DeleteContestVoteByUser(ctx context.Context, contestID model.ContestID, categoryID model.CategoryID, userID model.UserID) (model.ParticipantID, error)
 		CountVotesByContest(ctx context.Context, contestID model.ContestID) (int64, error)
-		CountVotesByParticipant(ctx context.Context, participantID model.ParticipantID) (int64, error)
+		CountVotesByParticipant(ctx context.Context, participantID model.ParticipantID, categoryID model.CategoryID) (int64, error)
 		CountVotesByContests(ctx context.Context, contestIDs []model.ContestID) (map[model.ContestID]int64, error)
-		ListVotersByParticipant(ctx context.Context, contestID model.ContestID, participantID model.ParticipantID) ([]*model.VoterInfo, error)
+		ListVotersByParticipant(ctx context.Context, contestID model.ContestID, categoryID model.CategoryID, participantID model.ParticipantID) ([]*model.VoterInfo, error)
+
+		// Categories
+		CreateContestCategory(ctx context.Context, category *model.ContestCategory) (*model.ContestCategory, error)
+		GetContestCategory(ctx context.Context, contestID model.ContestID, categoryID model.CategoryID) (*model.ContestCategory, error)
+		ListContestCategories(ctx context.Context, contestID model.ContestID) ([]*model.ContestCategory, error)
+		UpdateContestCategory(ctx context.Context, category *model.ContestCategory) (*model.ContestCategory, error)
+		DeleteContestCategory(ctx context.Context, contestID model.ContestID, categoryID model.CategoryID) error
+
+		// Ballot choices (approval / ranked / stars)
+		ListContestVoteChoices(ctx context.Context, voteID string) ([]model.BallotChoice, error)
+		CountVoteChoicesByParticipant(ctx context.Context, participantID model.ParticipantID, categoryID model.CategoryID) (int64, float64, error)
+		ListContestBallots(ctx context.Context, contestID model.ContestID, categoryID model.CategoryID) ([]*model.Ballot, error)
+
+		// Entry rules
+		GetContestEntryRules(ctx context.Context, contestID model.ContestID) (*model.EntryRules, error)
+		UpsertContestEntryRules(ctx context.Context, rules *model.EntryRules) (*model.EntryRules, error)
+
+		// Voting policies
+		GetContestVotingPolicy(ctx context.Context, contestID model.ContestID) (*model.VotingPolicy, error)
+		UpsertContestVotingPolicy(ctx context.Context, policy *model.VotingPolicy) (*model.VotingPolicy, error)
+		ListContestInvitedVoters(ctx context.Context, contestID model.ContestID) ([]*model.InvitedVoter, error)
+		AddContestInvitedVoter(ctx context.Context, contestID model.ContestID, userID, addedByUserID model.UserID) error
+		DeleteContestInvitedVoter(ctx context.Context, contestID model.ContestID, userID model.UserID) error
+		IsContestInvitedVoter(ctx context.Context, contestID model.ContestID, userID model.UserID) (bool, error)
+
+		// Jury
+		CreateJuryCriterion(ctx context.Context, criterion *model.JuryCriterion) (*model.JuryCriterion, error)
+		ListJuryCriteria(ctx context.Context, contestID model.ContestID) ([]*model.JuryCriterion, error)
+		DeleteJuryCriterion(ctx context.Context, contestID model.ContestID, criterionID string) error
+		UpsertJuryScores(ctx context.Context, contestID model.ContestID, participantID model.ParticipantID, jurorID model.UserID, scores []*model.JuryScore) error
+		ListJuryScores(ctx context.Context, contestID model.ContestID) ([]*model.JuryScore, error)
+
+		// Bracket
+		GetContestBracket(ctx context.Context, contestID model.ContestID) (*model.Bracket, error)
+		UpsertContestBracket(ctx context.Context, bracket *model.Bracket) (*model.Bracket, error)
+		StartContestBracket(ctx context.Context, round *model.BracketRound) (bool, error)
+		FinishBracketRound(ctx context.Context, round *model.BracketRound, decided []*model.BracketMatchup, next *model.BracketRound, championID model.ParticipantID) (bool, error)
+		ListBracketRounds(ctx context.Context, contestID model.ContestID) ([]*model.BracketRound, error)
+		ListDueBracketRounds(ctx context.Context, now time.Time) ([]*model.BracketRound, error)
+		SetBracketRoundStatus(ctx context.Context, contestID model.ContestID, round int, from, to model.BracketRoundStatus) (bool, error)
+		ListBracketMatchups(ctx context.Context, contestID model.ContestID) ([]*model.BracketMatchup, error)
+		GetBracketMatchup(ctx context.Context, contestID model.ContestID, matchupID string) (*model.BracketMatchup, error)
+		ForfeitBracketMatchups(ctx context.Context, contestID model.ContestID, participantID model.ParticipantID) ([]string, error)
+		UpsertBracketVote(ctx context.Context, matchupID string, userID model.UserID, participantID model.ParticipantID) error
+		ListBracketVotesByUser(ctx context.Context, contestID model.ContestID, userID model.UserID) (map[string]model.ParticipantID, error)
+
+		// Vote fraud
+		ListContestIDsWithVotesSince(ctx context.Context, since time.Time) ([]model.ContestID, error)
+		ListContestVotesForFraudScoring(ctx context.Context, contestID model.ContestID) ([]*model.VoteAudit, error)
+		UpdateContestVoteFraudScore(ctx context.Context, voteID string, score int, reasons []string, flagged bool) error
+		ListFlaggedContestVotes(ctx context.Context, contestID model.ContestID) ([]*model.VoteAudit, error)
+		VoidContestVotes(ctx context.Context, contestID model.ContestID, voteIDs []string, actorID model.UserID, reason string) ([]*model.Vote, error)
 
 		// Comments
 		CreateComment(ctx context.Context, participantID model.ParticipantID, userID model.UserID, text string) (*model.Comment, error)
 		GetComment(ctx context.Context, commentID model.CommentID) (*model.Comment, error)
 		ListCommentsByParticipant(ctx context.Context, participantID model.ParticipantID, limit, offset int) ([]*model.Comment, int64, error)
+		ListCommentsByUser(ctx context.Context, userID model.UserID) ([]*model.Comment, error)
+		ListParticipantCommenters(ctx context.Context, participantID model.ParticipantID) ([]model.UserID, error)
 		UpdateComment(ctx context.Context, commentID model.CommentID, userID model.UserID, text string) (*model.Comment, error)
 		DeleteComment(ctx context.Context, commentID model.CommentID, userID model.UserID) error
 
 		// Chat
 		CreateChatMessage(ctx context.Context, contestID model.ContestID, userID model.UserID, text string, isSystem bool) (*model.ChatMessage, error)
 		ListChatMessages(ctx context.Context, contestID model.ContestID, limit, offset int) ([]*model.ChatMessage, int64, error)
+		ListChatMessagesByUser(ctx context.Context, userID model.UserID) ([]*model.ChatMessage, error)
 		UpdateChatMessage(ctx context.Context, messageID model.ChatMessageID, userID model.UserID, text string) (*model.ChatMessage, error)
 		DeleteChatMessage(ctx context.Context, messageID model.ChatMessageID, userID model.UserID) (model.ContestID, error)
+		GetChatMessageContestID(ctx context.Context, messageID model.ChatMessageID) (model.ContestID, error)
 
 		// Photo Likes
 		UpsertPhotoLike(ctx context.Context, photoID string, userID model.UserID) (*model.PhotoLike, error)
@@ -83,11 +353,12 @@ type (
 		GetPhotoLikeByUser(ctx context.Context, photoID string, userID model.UserID) (*model.PhotoLike, error)
 		CountPhotoLikes(ctx context.Context, photoID string) (int64, error)
 		ListPhotoLikesByPhotos(ctx context.Context, photoIDs []string, userID model.UserID) (map[string]*model.PhotoLike, error)
+		CountPhotoLikesByParticipant(ctx context.Context, contestID model.ContestID) (map[model.ParticipantID]int64, error)
 	}
 
 	// TokenService интерфейс для работы с JWT токенами
 	TokenService interface {
-		GenerateToken(userID model.UserID) (string, error)
+		GenerateToken(userID model.UserID, roles []model.Role) (string, error)
 		ValidateToken(tokenString string) (*model.Claims, error)
 	}
 
@@ -95,16 +366,25 @@ type (
 	Hub interface {
 		BroadcastContestMessage(contestID model.ContestID, payload any) error
 		SendContestMessageToUser(contestID model.ContestID, userID model.UserID, payload any) error
+		// SendUserMessage отправляет payload во все соединения пользователя независимо от подписок
+		SendUserMessage(userID model.UserID, payload any) error
 	}
 )
 
 // NewTopPetService создает новый экземпляр TopPetService с указанными зависимостями
-func NewTopPetService(repository Repository, hub Hub, accessTokenService TokenService, refreshTokenService TokenService, providersUserData map[string]ProviderUserData) *TopPetService {
+func NewTopPetService(repository Repository, hub Hub, accessTokenService TokenService, refreshTokenService TokenService, providersUserData map[string]ProviderUserData, mailer Mailer, storage MediaStorage, emailLogin EmailLoginConfig, voteFraud VoteFraudConfig, emailNotifications EmailNotificationConfig, telegram TelegramConfig, webhooks WebhookConfig) *TopPetService {
 	return &TopPetService{
 		repository:          repository,
 		hub:                 hub,
 		accessTokenService:  accessTokenService,
 		refreshTokenService: refreshTokenService,
 		providersUserData:   providersUserData,
+		mailer:              mailer,
+		storage:             storage,
+		emailLogin:          emailLogin,
+		voteFraud:           voteFraud.withDefaults(),
+		emailNotifications:  emailNotifications,
+		telegram:            telegram,
+		webhooks:            webhooks,
 	}
 }
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"toppet/server/internal/model"
+)
+
+const (
+	// accountDeletionMaxAttempts после стольких неудачных попыток задание помечается failed
+	accountDeletionMaxAttempts = 5
+	// accountDeletionRetryAfter задержка перед повтором упавшего задания; задание, которое дольше
+	// остается в running, считается брошенным остановленным экземпляром и забирается заново
+	accountDeletionRetryAfter = 10 * time.Minute
+)
+
+// ExportUserData собирает все данные пользователя для выгрузки: профиль, привязанные провайдеры,
+// настройки писем, привязку Telegram, конкурсы, заявки, питомцев, ссылки на загруженные файлы, голоса, комментарии и сообщения чатов.
+func (s *TopPetService) ExportUserData(ctx context.Context, userID model.UserID) (*model.UserExport, error) {
+	user, err := s.repository.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	user.Roles = s.userRoles(ctx, userID)
+
+	export := &model.UserExport{ExportedAt: time.Now().UTC(), User: user}
+	if export.Providers, err = s.repository.GetUserAuthProvidersByUserID(ctx, userID); err != nil {
+		return nil, err
+	}
+	if export.Privacy, err = s.privacySettings(ctx, userID); err != nil {
+		return nil, err
+	}
+	if export.NotificationPreferences, err = s.repository.GetNotificationPreferences(ctx, userID); err != nil && !errors.Is(err, model.ErrorNotFound) {
+		return nil, err
+	}
+	if export.TelegramAccount, err = s.repository.GetTelegramAccount(ctx, userID); err != nil && !errors.Is(err, model.ErrorNotFound) {
+		return nil, err
+	}
+	if export.Contests, err = s.repository.ListContestsByCreator(ctx, userID); err != nil {
+		return nil, err
+	}
+	if export.Participants, err = s.userParticipants(ctx, userID); err != nil {
+		return nil, err
+	}
+	if export.Pets, err = s.userPets(ctx, userID); err != nil {
+		return nil, err
+	}
+	export.Media = userMedia(user, export.Participants, export.Pets)
+
+	if export.Votes, err = s.repository.ListContestVotesByUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	for _, vote := range export.Votes {
+		if vote.Choices, err = s.repository.ListContestVoteChoices(ctx, vote.ID); err != nil {
+			return nil, err
+		}
+	}
+	if export.Comments, err = s.repository.ListCommentsByUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	if export.ChatMessages, err = s.repository.ListChatMessagesByUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	for _, comment := range export.Comments {
+		comment.UserName = user.Name
+	}
+	for _, message := range export.ChatMessages {
+		message.UserName = user.Name
+	}
+	return export, nil
+}
+
+// RequestAccountDeletion ставит удаление аккаунта в очередь. Аккаунт сразу помечается удаленным:
+// вход и обновление токенов запрещены, уже выданные access токены отклоняются со следующего запроса.
+// Повторный запрос возвращает незавершенное задание.
+func (s *TopPetService) RequestAccountDeletion(ctx context.Context, userID model.UserID) (*model.AccountDeletionJob, error) {
+	return s.repository.CreateAccountDeletionJob(ctx, userID)
+}
+
+// GetAccountDeletionJob статус удаления аккаунта. ID задания случаен и выдается только владельцу,
+// поэтому статус доступен без авторизации: после запроса сессия пользователя уже не обновляется.
+func (s *TopPetService) GetAccountDeletionJob(ctx context.Context, jobID string) (*model.AccountDeletionJob, error) {
+	return s.repository.GetAccountDeletionJob(ctx, jobID)
+}
+
+// ProcessAccountDeletions выполняет все задания удаления, готовые к запуску. Задание забирается
+// через SKIP LOCKED, поэтому воркер может работать на нескольких экземплярах.
+func (s *TopPetService) ProcessAccountDeletions(ctx context.Context, now time.Time) error {
+	for {
+		job, err := s.repository.ClaimAccountDeletionJob(ctx, now.Add(-accountDeletionRetryAfter))
+		if errors.Is(err, model.ErrorNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		s.runAccountDeletionJob(ctx, job)
+	}
+}
+
+// RunAccountDeletionWorker периодически выполняет задания удаления аккаунтов
+func (s *TopPetService) RunAccountDeletionWorker(ctx context.Context, interval time.Duration) {
+	runPeriodically(ctx, interval, func(ctx context.Context) {
+		if err := s.ProcessAccountDeletions(ctx, time.Now()); err != nil {
+			log.Printf("[Service] RunAccountDeletionWorker: %v", err)
+		}
+	})
+}
+
+func (s *TopPetService) runAccountDeletionJob(ctx context.Context, job *model.AccountDeletionJob) {
+	status, lastError := model.AccountDeletionCompleted, ""
+	if err := s.deleteAccountData(ctx, job.UserID); err != nil {
+		log.Printf("[Service] runAccountDeletionJob: jobID=%s, userID=%d, attempt=%d: %v", job.ID, job.UserID, job.Attempts, err)
+		status, lastError = model.AccountDeletionPending, err.Error()
+		if job.Attempts >= accountDeletionMaxAttempts {
+			status = model.AccountDeletionFailed
+		}
+	}
+	if err := s.repository.FinishAccountDeletionJob(ctx, job.ID, status, lastError); err != nil {
+		log.Printf("[Service] runAccountDeletionJob: jobID=%s: failed to save status %s: %v", job.ID, status, err)
+	}
+}
+
+// deleteAccountData удаляет загруженные файлы из хранилища, затем персональные данные из БД.
+// Файлы удаляются первыми: после очистки БД ссылки на них уже не найти. Шаги идемпотентны,
+// поэтому упавшее на середине задание можно просто повторить.
+func (s *TopPetService) deleteAccountData(ctx context.Context, userID model.UserID) error {
+	user, err := s.repository.GetUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if s.storage != nil {
+		participants, err := s.userParticipants(ctx, userID)
+		if err != nil {
+			return err
+		}
+		pets, err := s.userPets(ctx, userID)
+		if err != nil {
+			return err
+		}
+		for _, media := range userMedia(user, participants, pets) {
+			if err := s.storage.Delete(ctx, media.URL); err != nil {
+				return fmt.Errorf("delete %s: %w", media.URL, err)
+			}
+			if media.ThumbURL != nil {
+				if err := s.storage.Delete(ctx, *media.ThumbURL); err != nil {
+					return fmt.Errorf("delete %s: %w", *media.ThumbURL, err)
+				}
+			}
+		}
+	}
+
+	return s.repository.DeleteUserPersonalData(ctx, userID, model.DeletedUserName)
+}
+
+// userParticipants все заявки пользователя с фото и видео
+func (s *TopPetService) userParticipants(ctx context.Context, userID model.UserID) ([]*model.Participant, error) {
+	participants, err := s.repository.ListParticipantsByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, participant := range participants {
+		if participant.Photos, err = s.repository.GetPhotosByParticipantID(ctx, participant.ID); err != nil {
+			return nil, err
+		}
+		video, err := s.repository.GetVideoByParticipantID(ctx, participant.ID)
+		if err != nil && !errors.Is(err, model.ErrorNotFound) {
+			return nil, err
+		}
+		participant.Video = video
+	}
+	return participants, nil
+}
+
+// userPets питомцы пользователя с галереей
+func (s *TopPetService) userPets(ctx context.Context, userID model.UserID) ([]*model.Pet, error) {
+	pets, err := s.repository.ListPetsByOwner(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, pet := range pets {
+		if pet.Photos, err = s.repository.ListPetPhotos(ctx, pet.ID); err != nil {
+			return nil, err
+		}
+	}
+	return pets, nil
+}
+
+// userMedia ссылки на все файлы пользователя: аватар, фото и видео заявок, галереи питомцев
+func userMedia(user *model.User, participants []*model.Participant, pets []*model.Pet) []*model.UserMedia {
+	var media []*model.UserMedia
+	if user.AvatarURL != "" {
+		avatar := &model.UserMedia{Kind: model.UserMediaAvatar, URL: user.AvatarURL}
+		if user.AvatarThumbURL != "" {
+			avatar.ThumbURL = &user.AvatarThumbURL
+		}
+		media = append(media, avatar)
+	}
+	for _, participant := range participants {
+		for _, photo := range participant.Photos {
+			media = append(media, &model.UserMedia{Kind: model.UserMediaParticipantPhoto, OwnerID: string(participant.ID), URL: photo.URL, ThumbURL: photo.ThumbURL})
+		}
+		if participant.Video != nil {
+			media = append(media, &model.UserMedia{Kind: model.UserMediaParticipantVideo, OwnerID: string(participant.ID), URL: participant.Video.URL})
+		}
+	}
+	for _, pet := range pets {
+		for _, photo := range pet.Photos {
+			media = append(media, &model.UserMedia{Kind: model.UserMediaPetPhoto, OwnerID: string(pet.ID), URL: photo.URL, ThumbURL: photo.ThumbURL})
+		}
+	}
+	return media
+}
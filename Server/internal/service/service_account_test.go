@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"toppet/server/internal/model"
+)
+
+type fakeMediaStorage struct {
+	deleted []string
+	err     error
+}
+
+func (f *fakeMediaStorage) Delete(ctx context.Context, storedURL string) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.deleted = append(f.deleted, storedURL)
+	return nil
+}
+
+func newAccountMockRepository() *mockRepository {
+	thumb := "https://cdn/p1_thumb.jpg"
+	return &mockRepository{
+		users: map[model.UserID]*model.User{
+			10: {ID: 10, Name: "Анна", Bio: "Кошатница", AvatarURL: "https://cdn/avatar.jpg", AvatarThumbURL: "https://cdn/avatar_thumb.jpg"},
+			11: {ID: 11, Name: "Борис"},
+		},
+		authProviders: map[model.UserID][]*model.UserAuthProvider{
+			10: {{UserID: 10, Provider: "vk"}},
+		},
+		participants: map[model.ParticipantID]*model.Participant{
+			"p1": {ID: "p1", UserID: 10, PetName: "Мурка"},
+			"p2": {ID: "p2", UserID: 11, PetName: "Шарик"},
+		},
+		participantPhotos: map[model.ParticipantID][]*model.Photo{
+			"p1": {{ID: "ph1", ParticipantID: "p1", URL: "https://cdn/p1.jpg", ThumbURL: &thumb}},
+			"p2": {{ID: "ph2", ParticipantID: "p2", URL: "https://cdn/p2.jpg"}},
+		},
+		pets: map[model.PetID]*model.Pet{
+			"pet1": {ID: "pet1", OwnerUserID: 10, Name: "Мурка"},
+		},
+		petPhotos:        []*model.PetPhoto{{ID: "pp1", PetID: "pet1", URL: "https://cdn/pet1.jpg"}},
+		participantVotes: []*model.Vote{{ID: "v1", UserID: 10}, {ID: "v2", UserID: 11}},
+		comments:         []*model.Comment{{ID: "c1", UserID: 10, Text: "Красавица"}, {ID: "c2", UserID: 11}},
+		chatMessages:     []*model.ChatMessage{{ID: "m1", UserID: 10, Text: "Привет"}},
+	}
+}
+
+func TestTopPetService_ExportUserData(t *testing.T) {
+	mockRepo := newAccountMockRepository()
+	service := &TopPetService{repository: mockRepo}
+
+	export, err := service.ExportUserData(context.Background(), 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if export.User.Name != "Анна" || len(export.Providers) != 1 || export.Privacy == nil {
+		t.Errorf("Expected profile with providers and privacy, got %+v", export)
+	}
+	if len(export.Participants) != 1 || export.Participants[0].ID != "p1" || len(export.Participants[0].Photos) != 1 {
+		t.Errorf("Expected only own participant with photos, got %+v", export.Participants)
+	}
+	if len(export.Votes) != 1 || len(export.Comments) != 1 || len(export.ChatMessages) != 1 {
+		t.Errorf("Expected only own votes, comments and messages, got %d/%d/%d", len(export.Votes), len(export.Comments), len(export.ChatMessages))
+	}
+	if export.Comments[0].UserName != "Анна" {
+		t.Errorf("Expected comment author name filled, got %q", export.Comments[0].UserName)
+	}
+
+	kinds := map[model.UserMediaKind]int{}
+	for _, media := range export.Media {
+		kinds[media.Kind]++
+	}
+	if kinds[model.UserMediaAvatar] != 1 || kinds[model.UserMediaParticipantPhoto] != 1 || kinds[model.UserMediaPetPhoto] != 1 {
+		t.Errorf("Expected avatar, participant photo and pet photo, got %v", kinds)
+	}
+}
+
+func TestTopPetService_RequestAccountDeletion(t *testing.T) {
+	mockRepo := newAccountMockRepository()
+	service := &TopPetService{repository: mockRepo}
+	ctx := context.Background()
+
+	job, err := service.RequestAccountDeletion(ctx, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if job.Status != model.AccountDeletionPending {
+		t.Errorf("Expected pending job, got %s", job.Status)
+	}
+
+	// Повторный запрос возвращает то же задание
+	again, err := service.RequestAccountDeletion(ctx, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if again.ID != job.ID {
+		t.Errorf("Expected existing job %s, got %s", job.ID, again.ID)
+	}
+
+	// Сессии отозваны: новые токены не выдаются
+	if _, err := service.issueTokens(ctx, 10); !errors.Is(err, model.ErrorForbidden) {
+		t.Errorf("Expected forbidden for deleted account, got %v", err)
+	}
+
+	status, err := service.GetAccountDeletionJob(ctx, job.ID)
+	if err != nil || status.ID != job.ID {
+		t.Errorf("Expected job status, got %+v, %v", status, err)
+	}
+}
+
+func TestTopPetService_ProcessAccountDeletions(t *testing.T) {
+	mockRepo := newAccountMockRepository()
+	storage := &fakeMediaStorage{}
+	service := &TopPetService{repository: mockRepo, storage: storage}
+	ctx := context.Background()
+
+	job, err := service.RequestAccountDeletion(ctx, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := service.ProcessAccountDeletions(ctx, time.Now()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if job.Status != model.AccountDeletionCompleted {
+		t.Errorf("Expected completed job, got %s (%s)", job.Status, job.LastError)
+	}
+	want := map[string]bool{
+		"https://cdn/avatar.jpg": true, "https://cdn/avatar_thumb.jpg": true,
+		"https://cdn/p1.jpg": true, "https://cdn/p1_thumb.jpg": true, "https://cdn/pet1.jpg": true,
+	}
+	if len(storage.deleted) != len(want) {
+		t.Errorf("Expected %d deleted files, got %v", len(want), storage.deleted)
+	}
+	for _, url := range storage.deleted {
+		if !want[url] {
+			t.Errorf("Unexpected deleted file %s", url)
+		}
+	}
+	if len(mockRepo.deletedUserData) != 1 || mockRepo.deletedUserData[0] != 10 {
+		t.Errorf("Expected personal data of user 10 deleted, got %v", mockRepo.deletedUserData)
+	}
+	if user := mockRepo.users[10]; user.Name != model.DeletedUserName || user.AvatarURL != "" {
+		t.Errorf("Expected anonymized user, got %+v", user)
+	}
+}
+
+func TestTopPetService_ProcessAccountDeletions_Retry(t *testing.T) {
+	mockRepo := newAccountMockRepository()
+	storage := &fakeMediaStorage{err: errors.New("storage unavailable")}
+	service := &TopPetService{repository: mockRepo, storage: storage}
+	ctx := context.Background()
+
+	job, err := service.RequestAccountDeletion(ctx, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if err := service.ProcessAccountDeletions(ctx, time.Now()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if job.Status != model.AccountDeletionPending || job.LastError == "" || job.Attempts != 1 {
+		t.Errorf("Expected job back in queue with error after one attempt, got %s (%q), %d attempts", job.Status, job.LastError, job.Attempts)
+	}
+	if len(mockRepo.deletedUserData) != 0 {
+		t.Errorf("Expected database untouched while files are not deleted")
+	}
+
+	// Каждый повтор - только после задержки
+	for i := 2; i <= accountDeletionMaxAttempts; i++ {
+		startedAt := job.StartedAt.Add(-accountDeletionRetryAfter - time.Second)
+		job.StartedAt = &startedAt
+		if err := service.ProcessAccountDeletions(ctx, time.Now()); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if job.Attempts != i {
+			t.Fatalf("Expected %d attempts, got %d", i, job.Attempts)
+		}
+	}
+	if job.Status != model.AccountDeletionFailed || job.Attempts != accountDeletionMaxAttempts {
+		t.Errorf("Expected failed after %d attempts, got %s after %d", accountDeletionMaxAttempts, job.Status, job.Attempts)
+	}
+}
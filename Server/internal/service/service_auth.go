@@ -20,6 +20,11 @@ func (s *TopPetService) Login(ctx context.Context, providerKey string, authoriza
 		return nil, err
 	}
 
+	return s.loginWithProfile(ctx, userProfileFromProvider)
+}
+
+// loginWithProfile находит или создает пользователя по профилю провайдера и выдает токены.
+func (s *TopPetService) loginWithProfile(ctx context.Context, userProfileFromProvider *model.UserProfileFromProvider) (*model.AuthData, error) {
 	userAuthProvider, err := s.repository.GetUserAuthProvidersByProviderUid(ctx, userProfileFromProvider.ProviderID, userProfileFromProvider.ProviderName)
 	if err != nil && !errors.Is(err, model.ErrorNotFound) {
 		return nil, err
@@ -46,46 +51,64 @@ func (s *TopPetService) Login(ctx context.Context, providerKey string, authoriza
 
 	// Set avatar if empty and provider returned one
 	if userProfileFromProvider.AvatarURL != "" {
+		// Запоминаем актуальный аватар провайдера, чтобы пользователь мог к нему вернуться
+		if userAuthProvider != nil {
+			_ = s.repository.UpdateUserAuthProviderAvatar(ctx, userProfileFromProvider.ProviderID, userProfileFromProvider.ProviderName, &userProfileFromProvider.AvatarURL)
+		}
 		_ = s.repository.SetUserAvatarIfEmpty(ctx, userID, &userProfileFromProvider.AvatarURL)
 	}
 
-	refreshToken, err := s.refreshTokenService.GenerateToken(userID)
+	authData, err := s.issueTokens(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
 
-	accessToken, err := s.accessTokenService.GenerateToken(userID)
+	// Первый адрес, переданный провайдером, становится адресом для писем о конкурсах
+	if email := normalizeEmail(userProfileFromProvider.Email); email != "" {
+		_ = s.repository.SetNotificationEmailIfEmpty(ctx, userID, email)
+	}
+	return authData, nil
+}
+
+// issueTokens выпускает пару токенов; в access токен кладутся актуальные роли пользователя.
+// Удаленному аккаунту токены не выдаются: так отзываются его сессии.
+func (s *TopPetService) issueTokens(ctx context.Context, userID model.UserID) (*model.AuthData, error) {
+	user, err := s.repository.GetUser(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
+	if user.DeletedAt != nil {
+		return nil, fmt.Errorf("%w: account is deleted", model.ErrorForbidden)
+	}
 
-	return &model.AuthData{
-		UserID:       userID,
-		RefreshToken: refreshToken,
-		AccessToken:  accessToken,
-	}, nil
-}
-
-// RefreshToken validates refresh token and issues new access + refresh tokens.
-func (s *TopPetService) RefreshToken(ctx context.Context, refreshToken string) (*model.AuthData, error) {
-	claims, err := s.refreshTokenService.ValidateToken(refreshToken)
+	roles, err := s.repository.ListUserRoles(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
 
-	newAccessToken, err := s.accessTokenService.GenerateToken(claims.UserID)
+	refreshToken, err := s.refreshTokenService.GenerateToken(userID, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	newRefreshToken, err := s.refreshTokenService.GenerateToken(claims.UserID)
+	accessToken, err := s.accessTokenService.GenerateToken(userID, roles)
 	if err != nil {
 		return nil, err
 	}
 
 	return &model.AuthData{
-		UserID:       claims.UserID,
-		RefreshToken: newRefreshToken,
-		AccessToken:  newAccessToken,
+		UserID:       userID,
+		RefreshToken: refreshToken,
+		AccessToken:  accessToken,
 	}, nil
 }
+
+// RefreshToken validates refresh token and issues new access + refresh tokens.
+func (s *TopPetService) RefreshToken(ctx context.Context, refreshToken string) (*model.AuthData, error) {
+	claims, err := s.refreshTokenService.ValidateToken(refreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.issueTokens(ctx, claims.UserID)
+}
@@ -2,10 +2,113 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
 
+	"toppet/server/internal/app/defenitions"
 	"toppet/server/internal/model"
 )
 
+// Authorization проверяет access токен. Роли в claims заменяются текущими ролями из БД,
+// поэтому выданная или отозванная роль действует со следующего запроса, а не после истечения токена.
+// Токены удаленного аккаунта отклоняются сразу.
 func (s *TopPetService) Authorization(ctx context.Context, accessToken string) (*model.Claims, error) {
-	return s.accessTokenService.ValidateToken(accessToken)
+	claims, err := s.accessTokenService.ValidateToken(accessToken)
+	if err != nil {
+		return nil, err
+	}
+	roles, err := s.activeUserRoles(ctx, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+	claims.Roles = roles
+	return claims, nil
+}
+
+// activeUserRoles роли пользователя, чей токен или билет предъявлен; удаленный аккаунт - ErrUnauthorized
+func (s *TopPetService) activeUserRoles(ctx context.Context, userID model.UserID) ([]model.Role, error) {
+	roles, err := s.repository.ListActiveUserRoles(ctx, userID)
+	if errors.Is(err, model.ErrorNotFound) {
+		return nil, fmt.Errorf("%w: account is deleted", model.ErrUnauthorized)
+	}
+	return roles, err
+}
+
+// userRoles возвращает роли пользователя. Для текущего пользователя запроса роли берутся
+// из контекста (Authorization перечитывает их из БД на каждый запрос), иначе читаются из БД.
+func (s *TopPetService) userRoles(ctx context.Context, userID model.UserID) []model.Role {
+	if ctxUserID, ok := ctx.Value(defenitions.UserID).(model.UserID); ok && ctxUserID == userID {
+		if roles, ok := ctx.Value(defenitions.Roles).([]model.Role); ok {
+			return roles
+		}
+	}
+
+	roles, err := s.repository.ListUserRoles(ctx, userID)
+	if err != nil {
+		return nil
+	}
+	return roles
+}
+
+// hasRole проверяет, есть ли у пользователя хотя бы одна из ролей
+func (s *TopPetService) hasRole(ctx context.Context, userID model.UserID, roles ...model.Role) bool {
+	if userID == 0 {
+		return false
+	}
+	for _, role := range s.userRoles(ctx, userID) {
+		if slices.Contains(roles, role) {
+			return true
+		}
+	}
+	return false
+}
+
+// isAdmin - администратор платформы
+func (s *TopPetService) isAdmin(ctx context.Context, userID model.UserID) bool {
+	return s.hasRole(ctx, userID, model.RoleAdmin)
+}
+
+// isStaff - администратор или модератор: может скрывать и удалять любой контент
+func (s *TopPetService) isStaff(ctx context.Context, userID model.UserID) bool {
+	return s.hasRole(ctx, userID, model.RoleAdmin, model.RoleModerator)
+}
+
+// canViewHidden - скрытый модератором контент виден только персоналу;
+// публичные запросы без авторизации получают not found.
+func (s *TopPetService) canViewHidden(ctx context.Context) bool {
+	userID, ok := ctx.Value(defenitions.UserID).(model.UserID)
+	return ok && s.isStaff(ctx, userID)
+}
+
+// contestMemberRole возвращает роль пользователя в конкурсе. Владелец определяется по created_by_user_id,
+// остальные роли - по активной записи в contest_members; приглашение без принятия прав не дает.
+func (s *TopPetService) contestMemberRole(ctx context.Context, contest *model.Contest, userID model.UserID) model.ContestMemberRole {
+	if userID == 0 {
+		return ""
+	}
+	if contest.CreatedByUserID == userID {
+		return model.ContestMemberOwner
+	}
+
+	member, err := s.repository.GetContestMember(ctx, contest.ID, userID)
+	if err != nil || member.Status != model.ContestMemberActive {
+		return ""
+	}
+	return member.Role
+}
+
+// CanManageContest - владелец или организатор: редактирование, статусы, просмотр голосов
+func (s *TopPetService) CanManageContest(ctx context.Context, contest *model.Contest, userID model.UserID) bool {
+	switch s.contestMemberRole(ctx, contest, userID) {
+	case model.ContestMemberOwner, model.ContestMemberOrganizer:
+		return true
+	}
+	return false
+}
+
+// canModerateContest - любой член команды конкурса, кроме жюри, может удалять комментарии и сообщения чата
+func (s *TopPetService) canModerateContest(ctx context.Context, contest *model.Contest, userID model.UserID) bool {
+	role := s.contestMemberRole(ctx, contest, userID)
+	return role != "" && role != model.ContestMemberJuror
 }
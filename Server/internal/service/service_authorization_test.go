@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"toppet/server/internal/model"
+)
+
+// fakeTokenService принимает токен "valid" и выдает claims с ролями, зашитыми при выпуске
+type fakeTokenService struct {
+	claims *model.Claims
+}
+
+func (f *fakeTokenService) GenerateToken(userID model.UserID, roles []model.Role) (string, error) {
+	return "valid", nil
+}
+
+func (f *fakeTokenService) ValidateToken(tokenString string) (*model.Claims, error) {
+	if tokenString != "valid" {
+		return nil, errors.New("invalid token")
+	}
+	claims := *f.claims
+	return &claims, nil
+}
+
+func TestTopPetService_Authorization(t *testing.T) {
+	mockRepo := &mockRepository{userRoles: map[model.UserID][]model.Role{10: {model.RoleModerator}}}
+	tokens := &fakeTokenService{claims: &model.Claims{UserID: 10, Roles: []model.Role{model.RoleAdmin}}}
+	service := &TopPetService{repository: mockRepo, accessTokenService: tokens}
+	ctx := context.Background()
+
+	if _, err := service.Authorization(ctx, "broken"); err == nil {
+		t.Error("Expected error for invalid token")
+	}
+
+	// Роль admin отозвана после выпуска токена: действуют роли из БД
+	claims, err := service.Authorization(ctx, "valid")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(claims.Roles) != 1 || claims.Roles[0] != model.RoleModerator {
+		t.Errorf("Expected current roles from the database, got %v", claims.Roles)
+	}
+
+	// После запроса на удаление аккаунта еще не истекший токен не принимается
+	deletedAt := time.Now()
+	mockRepo.users = map[model.UserID]*model.User{10: {ID: 10, DeletedAt: &deletedAt}}
+	if _, err := service.Authorization(ctx, "valid"); !errors.Is(err, model.ErrUnauthorized) {
+		t.Errorf("Expected ErrUnauthorized for deleted account, got %v", err)
+	}
+}
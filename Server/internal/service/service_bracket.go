@@ -0,0 +1,603 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"slices"
+	"time"
+
+	wsapp "toppet/server/internal/app/ws"
+	"toppet/server/internal/model"
+)
+
+const (
+	defaultBracketRoundMinutes = 24 * 60
+	minBracketRoundMinutes     = 5
+	maxBracketRoundMinutes     = 7 * 24 * 60
+)
+
+// GetBracket возвращает турнирную сетку конкурса с раундами и парами (публично).
+// Если передан userID, в парах отмечается, за кого голосовал пользователь.
+func (s *TopPetService) GetBracket(ctx context.Context, contestID model.ContestID, userID *model.UserID) (*model.Bracket, error) {
+	if _, err := s.GetContest(ctx, contestID); err != nil {
+		return nil, err
+	}
+
+	bracket, err := s.bracketWithRounds(ctx, contestID)
+	if err != nil {
+		return nil, err
+	}
+
+	if userID != nil {
+		votes, err := s.repository.ListBracketVotesByUser(ctx, contestID, *userID)
+		if err != nil {
+			return nil, err
+		}
+		for _, round := range bracket.Rounds {
+			for _, matchup := range round.Matchups {
+				matchup.UserVote = votes[matchup.ID]
+			}
+		}
+	}
+
+	return bracket, nil
+}
+
+// ConfigureBracket задает способ посева и длительность раунда. Менять настройки можно до запуска сетки.
+func (s *TopPetService) ConfigureBracket(ctx context.Context, contestID model.ContestID, actorID model.UserID, seeding model.BracketSeeding, roundDurationMinutes int) (*model.Bracket, error) {
+	if _, err := s.bracketManagedContest(ctx, contestID, actorID); err != nil {
+		return nil, err
+	}
+
+	if seeding == "" {
+		seeding = model.BracketSeedingRandom
+	}
+	if seeding != model.BracketSeedingRandom && seeding != model.BracketSeedingLikes {
+		return nil, fmt.Errorf("%w: seeding must be %q or %q", model.ErrBadRequest, model.BracketSeedingRandom, model.BracketSeedingLikes)
+	}
+	if roundDurationMinutes == 0 {
+		roundDurationMinutes = defaultBracketRoundMinutes
+	}
+	if roundDurationMinutes < minBracketRoundMinutes || roundDurationMinutes > maxBracketRoundMinutes {
+		return nil, fmt.Errorf("%w: round_duration_minutes must be between %d and %d", model.ErrBadRequest, minBracketRoundMinutes, maxBracketRoundMinutes)
+	}
+
+	existing, err := s.repository.GetContestBracket(ctx, contestID)
+	if err != nil && !errors.Is(err, model.ErrorNotFound) {
+		return nil, err
+	}
+	if existing != nil && existing.Status != model.BracketStatusPending {
+		return nil, fmt.Errorf("%w: bracket has already started", model.ErrBadRequest)
+	}
+
+	bracket, err := s.repository.UpsertContestBracket(ctx, &model.Bracket{
+		ContestID:            contestID,
+		Seeding:              seeding,
+		RoundDurationMinutes: roundDurationMinutes,
+	})
+	if err != nil {
+		return nil, err
+	}
+	bracket.Rounds = []*model.BracketRound{}
+	return bracket, nil
+}
+
+// StartBracket рассаживает участников по парам первого раунда и запускает сетку.
+// Запуск возможен на этапе голосования; startsAt - отложенное начало первого раунда.
+// Если участников не степень двойки, сильнейшие по посеву проходят первый раунд без соперника.
+func (s *TopPetService) StartBracket(ctx context.Context, contestID model.ContestID, actorID model.UserID, startsAt *time.Time) (*model.Bracket, error) {
+	contest, err := s.bracketManagedContest(ctx, contestID, actorID)
+	if err != nil {
+		return nil, err
+	}
+	if contest.Status != model.ContestStatusVoting {
+		return nil, fmt.Errorf("%w: bracket can only be started during voting stage", model.ErrBadRequest)
+	}
+
+	bracket, err := s.repository.GetContestBracket(ctx, contestID)
+	if errors.Is(err, model.ErrorNotFound) {
+		bracket, err = s.repository.UpsertContestBracket(ctx, &model.Bracket{
+			ContestID:            contestID,
+			Seeding:              model.BracketSeedingRandom,
+			RoundDurationMinutes: defaultBracketRoundMinutes,
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+	if bracket.Status != model.BracketStatusPending {
+		return nil, fmt.Errorf("%w: bracket has already started", model.ErrBadRequest)
+	}
+
+	participants, err := s.repository.ListParticipantsByContest(ctx, contestID)
+	if err != nil {
+		return nil, err
+	}
+	if len(participants) < 2 {
+		return nil, fmt.Errorf("%w: bracket needs at least 2 participants", model.ErrBadRequest)
+	}
+	seeded, err := s.seedBracket(ctx, contestID, bracket.Seeding, participants)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	start := now
+	if startsAt != nil && startsAt.After(now) {
+		start = *startsAt
+	}
+	round := &model.BracketRound{
+		ContestID: contestID,
+		Round:     1,
+		Status:    model.BracketRoundScheduled,
+		StartsAt:  start,
+		EndsAt:    start.Add(time.Duration(bracket.RoundDurationMinutes) * time.Minute),
+		Matchups:  firstRoundMatchups(seeded),
+	}
+	if !start.After(now) {
+		round.Status = model.BracketRoundActive
+	}
+
+	started, err := s.repository.StartContestBracket(ctx, round)
+	if err != nil {
+		return nil, err
+	}
+	if !started {
+		return nil, fmt.Errorf("%w: bracket has already started", model.ErrBadRequest)
+	}
+
+	if round.Status == model.BracketRoundActive {
+		s.broadcastRoundStarted(ctx, round)
+	}
+
+	return s.bracketWithRounds(ctx, contestID)
+}
+
+// VoteInMatchup отдает (или переносит) голос пользователя в паре. Голосовать можно, пока открыт раунд пары;
+// действуют те же правила голосования конкурса, что и для основного голосования.
+func (s *TopPetService) VoteInMatchup(ctx context.Context, contestID model.ContestID, matchupID string, userID model.UserID, participantID model.ParticipantID) (*model.BracketMatchup, error) {
+	contest, err := s.repository.GetContest(ctx, contestID)
+	if err != nil {
+		return nil, err
+	}
+
+	matchup, err := s.repository.GetBracketMatchup(ctx, contestID, matchupID)
+	if err != nil {
+		return nil, err
+	}
+	if participantID == "" || (participantID != matchup.ParticipantAID && participantID != matchup.ParticipantBID) {
+		return nil, fmt.Errorf("%w: participant is not in this matchup", model.ErrBadRequest)
+	}
+	if matchup.DecidedAt != nil {
+		return nil, model.NewCodedError(model.ErrorForbidden, model.VoteIneligibleVotingClosed, "matchup is already decided")
+	}
+
+	round, err := s.bracketRound(ctx, contestID, matchup.Round)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if round.Status != model.BracketRoundActive || now.Before(round.StartsAt) || !now.Before(round.EndsAt) {
+		return nil, model.NewCodedError(model.ErrorForbidden, model.VoteIneligibleVotingClosed, "voting in this round is closed")
+	}
+
+	participant, err := s.repository.GetParticipant(ctx, participantID)
+	if err != nil {
+		return nil, err
+	}
+	if !isEntryPublic(participant) {
+		return nil, fmt.Errorf("%w: participant is not approved", model.ErrBadRequest)
+	}
+	if err := s.checkVoteEligibility(ctx, contest, []*model.Participant{participant}, userID); err != nil {
+		return nil, err
+	}
+
+	if err := s.repository.UpsertBracketVote(ctx, matchupID, userID, participantID); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.repository.GetBracketMatchup(ctx, contestID, matchupID)
+	if err != nil {
+		return nil, err
+	}
+	updated.UserVote = participantID
+	return updated, nil
+}
+
+// AdvanceBrackets открывает раунды, время которых пришло, и подводит итоги закончившихся:
+// победители пар проходят в следующий раунд, который начинается сразу. Переходы статусов
+// раунда защищены от повторного выполнения, поэтому планировщик может работать на нескольких экземплярах.
+func (s *TopPetService) AdvanceBrackets(ctx context.Context, now time.Time) error {
+	rounds, err := s.repository.ListDueBracketRounds(ctx, now)
+	if err != nil {
+		return err
+	}
+	for _, round := range rounds {
+		if err := s.advanceBracketRound(ctx, round, now); err != nil {
+			log.Printf("[Service] AdvanceBrackets: contestID=%s, round=%d: %v", round.ContestID, round.Round, err)
+		}
+	}
+	return nil
+}
+
+// RunBracketScheduler периодически продвигает турнирные сетки
+func (s *TopPetService) RunBracketScheduler(ctx context.Context, interval time.Duration) {
+	runPeriodically(ctx, interval, func(ctx context.Context) {
+		if err := s.AdvanceBrackets(ctx, time.Now()); err != nil {
+			log.Printf("[Service] RunBracketScheduler: %v", err)
+		}
+	})
+}
+
+func (s *TopPetService) advanceBracketRound(ctx context.Context, round *model.BracketRound, now time.Time) error {
+	if round.Status == model.BracketRoundScheduled {
+		activated, err := s.repository.SetBracketRoundStatus(ctx, round.ContestID, round.Round, model.BracketRoundScheduled, model.BracketRoundActive)
+		if err != nil {
+			return err
+		}
+		if !activated {
+			return nil
+		}
+		round.Status = model.BracketRoundActive
+		if round.Matchups, err = s.roundMatchups(ctx, round.ContestID, round.Round); err != nil {
+			return err
+		}
+		s.broadcastRoundStarted(ctx, round)
+	}
+
+	if round.EndsAt.After(now) {
+		return nil
+	}
+	return s.finishBracketRound(ctx, round, now)
+}
+
+// finishBracketRound подводит итоги раунда. Закрытие раунда, решения по парам и следующий раунд
+// сохраняются одной транзакцией, поэтому сбой не оставляет сетку без продолжения.
+func (s *TopPetService) finishBracketRound(ctx context.Context, round *model.BracketRound, now time.Time) error {
+	bracket, err := s.repository.GetContestBracket(ctx, round.ContestID)
+	if err != nil {
+		return err
+	}
+	matchups, err := s.roundMatchups(ctx, round.ContestID, round.Round)
+	if err != nil {
+		return err
+	}
+
+	entrants, err := s.bracketEntrants(ctx, round.ContestID)
+	if err != nil {
+		return err
+	}
+
+	decided := make([]*model.BracketMatchup, 0, len(matchups))
+	for _, matchup := range matchups {
+		if matchup.DecidedAt != nil {
+			continue
+		}
+		matchup.WinnerID = decideMatchup(matchup)
+		if winnerID := forfeitWinner(matchup, entrants); winnerID != "" {
+			matchup.WinnerID = winnerID
+		}
+		decidedAt := now
+		matchup.DecidedAt = &decidedAt
+		decided = append(decided, matchup)
+	}
+
+	// Финал: победитель пары - победитель сетки
+	if len(matchups) <= 1 {
+		var championID model.ParticipantID
+		if len(matchups) == 1 {
+			championID = matchups[0].WinnerID
+		}
+		finished, err := s.repository.FinishBracketRound(ctx, round, decided, nil, championID)
+		if err != nil || !finished {
+			return err
+		}
+		s.broadcastMatchupsDecided(ctx, round.ContestID, decided, championID)
+		return nil
+	}
+
+	next := &model.BracketRound{
+		ContestID: round.ContestID,
+		Round:     round.Round + 1,
+		Status:    model.BracketRoundActive,
+		StartsAt:  now,
+		EndsAt:    now.Add(time.Duration(bracket.RoundDurationMinutes) * time.Minute),
+		Matchups:  nextRoundMatchups(matchups, round.Round+1),
+	}
+	// Выбывший после победы в паре участник сразу отдает пару следующего раунда сопернику
+	for _, matchup := range next.Matchups {
+		if matchup.WinnerID == "" {
+			matchup.WinnerID = forfeitWinner(matchup, entrants)
+		}
+	}
+	finished, err := s.repository.FinishBracketRound(ctx, round, decided, next, "")
+	if err != nil || !finished {
+		return err
+	}
+
+	s.broadcastMatchupsDecided(ctx, round.ContestID, decided, "")
+	s.broadcastRoundStarted(ctx, next)
+	return nil
+}
+
+// forfeitBracketMatchups засчитывает поражение во всех нерешенных парах выбывшего участника
+// (дисквалификация): побеждает соперник. Ошибки только логируются - незакрытую пару
+// все равно отдаст сопернику подведение итогов раунда.
+func (s *TopPetService) forfeitBracketMatchups(ctx context.Context, contestID model.ContestID, participantID model.ParticipantID) {
+	ids, err := s.repository.ForfeitBracketMatchups(ctx, contestID, participantID)
+	if err != nil {
+		log.Printf("[Service] forfeitBracketMatchups: participantID=%s: %v", participantID, err)
+		return
+	}
+	if len(ids) == 0 {
+		return
+	}
+
+	matchups, err := s.repository.ListBracketMatchups(ctx, contestID)
+	if err != nil {
+		log.Printf("[Service] forfeitBracketMatchups: participantID=%s: %v", participantID, err)
+		return
+	}
+	decided := slices.DeleteFunc(matchups, func(matchup *model.BracketMatchup) bool {
+		return !slices.Contains(ids, matchup.ID)
+	})
+	s.broadcastMatchupsDecided(ctx, contestID, decided, "")
+}
+
+// bracketEntrants - участники, которые еще могут побеждать в парах: одобренные, не скрытые и не дисквалифицированные
+func (s *TopPetService) bracketEntrants(ctx context.Context, contestID model.ContestID) (map[model.ParticipantID]bool, error) {
+	participants, err := s.repository.ListParticipantsByContest(ctx, contestID)
+	if err != nil {
+		return nil, err
+	}
+	entrants := make(map[model.ParticipantID]bool, len(participants))
+	for _, participant := range participants {
+		entrants[participant.ID] = true
+	}
+	return entrants, nil
+}
+
+func (s *TopPetService) bracketManagedContest(ctx context.Context, contestID model.ContestID, actorID model.UserID) (*model.Contest, error) {
+	contest, err := s.repository.GetContest(ctx, contestID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.CanManageContest(ctx, contest, actorID) {
+		return nil, model.ErrorForbidden
+	}
+	if contest.Status == model.ContestStatusFinished {
+		return nil, fmt.Errorf("%w: contest is finished", model.ErrBadRequest)
+	}
+	return contest, nil
+}
+
+func (s *TopPetService) bracketWithRounds(ctx context.Context, contestID model.ContestID) (*model.Bracket, error) {
+	bracket, err := s.repository.GetContestBracket(ctx, contestID)
+	if err != nil {
+		return nil, err
+	}
+	rounds, err := s.repository.ListBracketRounds(ctx, contestID)
+	if err != nil {
+		return nil, err
+	}
+	matchups, err := s.repository.ListBracketMatchups(ctx, contestID)
+	if err != nil {
+		return nil, err
+	}
+
+	byRound := make(map[int]*model.BracketRound, len(rounds))
+	for _, round := range rounds {
+		round.Matchups = []*model.BracketMatchup{}
+		byRound[round.Round] = round
+	}
+	for _, matchup := range matchups {
+		if round, ok := byRound[matchup.Round]; ok {
+			round.Matchups = append(round.Matchups, matchup)
+		}
+	}
+	bracket.Rounds = rounds
+	return bracket, nil
+}
+
+func (s *TopPetService) bracketRound(ctx context.Context, contestID model.ContestID, number int) (*model.BracketRound, error) {
+	rounds, err := s.repository.ListBracketRounds(ctx, contestID)
+	if err != nil {
+		return nil, err
+	}
+	for _, round := range rounds {
+		if round.Round == number {
+			return round, nil
+		}
+	}
+	return nil, model.ErrorNotFound
+}
+
+func (s *TopPetService) roundMatchups(ctx context.Context, contestID model.ContestID, round int) ([]*model.BracketMatchup, error) {
+	matchups, err := s.repository.ListBracketMatchups(ctx, contestID)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(matchups, func(matchup *model.BracketMatchup) bool {
+		return matchup.Round != round
+	}), nil
+}
+
+// seedBracket упорядочивает участников по посеву: первый - сильнейший (посев 1).
+// При посеве по лайкам равные участники остаются в порядке регистрации.
+func (s *TopPetService) seedBracket(ctx context.Context, contestID model.ContestID, seeding model.BracketSeeding, participants []*model.Participant) ([]model.ParticipantID, error) {
+	ids := make([]model.ParticipantID, 0, len(participants))
+	for _, participant := range participants {
+		ids = append(ids, participant.ID)
+	}
+
+	switch seeding {
+	case model.BracketSeedingLikes:
+		likes, err := s.repository.CountPhotoLikesByParticipant(ctx, contestID)
+		if err != nil {
+			return nil, err
+		}
+		slices.SortStableFunc(ids, func(a, b model.ParticipantID) int {
+			return cmp.Compare(likes[b], likes[a])
+		})
+	default:
+		rand.Shuffle(len(ids), func(i, j int) {
+			ids[i], ids[j] = ids[j], ids[i]
+		})
+	}
+	return ids, nil
+}
+
+func (s *TopPetService) broadcastRoundStarted(ctx context.Context, round *model.BracketRound) {
+	s.emitWebhookEvent(ctx, round.ContestID, model.WebhookEventBracketRoundStarted, webhookRoundStartedData{
+		Round:    round.Round,
+		StartsAt: round.StartsAt,
+		EndsAt:   round.EndsAt,
+		Matchups: round.Matchups,
+	})
+	if s.hub == nil {
+		return
+	}
+	_ = s.hub.BroadcastContestMessage(round.ContestID, wsapp.RoundStartedPayload{
+		Type:      wsapp.MessageTypeRoundStarted,
+		ContestID: round.ContestID,
+		Round:     round.Round,
+		StartsAt:  round.StartsAt,
+		EndsAt:    round.EndsAt,
+		Matchups:  round.Matchups,
+	})
+}
+
+func (s *TopPetService) broadcastMatchupsDecided(ctx context.Context, contestID model.ContestID, matchups []*model.BracketMatchup, championID model.ParticipantID) {
+	for _, matchup := range matchups {
+		s.emitWebhookEvent(ctx, contestID, model.WebhookEventBracketMatchupDecided, webhookMatchupDecidedData{
+			Round:      matchup.Round,
+			MatchupID:  matchup.ID,
+			WinnerID:   matchup.WinnerID,
+			VotesA:     matchup.VotesA,
+			VotesB:     matchup.VotesB,
+			ChampionID: championID,
+		})
+		if s.hub == nil {
+			continue
+		}
+		_ = s.hub.BroadcastContestMessage(contestID, wsapp.MatchupDecidedPayload{
+			Type:       wsapp.MessageTypeMatchupDecided,
+			ContestID:  contestID,
+			Round:      matchup.Round,
+			MatchupID:  matchup.ID,
+			WinnerID:   matchup.WinnerID,
+			VotesA:     matchup.VotesA,
+			VotesB:     matchup.VotesB,
+			ChampionID: championID,
+		})
+	}
+}
+
+// bracketSeedOrder возвращает порядок посевов в сетке размера size (степень двойки):
+// соседние элементы - пары первого раунда, 1 играет с size, а 1 и 2 встречаются только в финале.
+func bracketSeedOrder(size int) []int {
+	order := []int{1}
+	for len(order) < size {
+		total := 2*len(order) + 1
+		next := make([]int, 0, 2*len(order))
+		for _, seed := range order {
+			next = append(next, seed, total-seed)
+		}
+		order = next
+	}
+	return order
+}
+
+// firstRoundMatchups рассаживает участников (в порядке посева) по парам первого раунда.
+// Недостающие до степени двойки места - проходы без соперника у сильнейших посевов.
+func firstRoundMatchups(seeded []model.ParticipantID) []*model.BracketMatchup {
+	size := 2
+	for size < len(seeded) {
+		size *= 2
+	}
+
+	order := bracketSeedOrder(size)
+	matchups := make([]*model.BracketMatchup, 0, size/2)
+	for i := 0; i < size; i += 2 {
+		matchup := &model.BracketMatchup{Round: 1, Position: i / 2}
+		if seed := order[i]; seed <= len(seeded) {
+			matchup.ParticipantAID, matchup.SeedA = seeded[seed-1], seed
+		}
+		if seed := order[i+1]; seed <= len(seeded) {
+			matchup.ParticipantBID, matchup.SeedB = seeded[seed-1], seed
+		}
+		if matchup.ParticipantAID == "" || matchup.ParticipantBID == "" {
+			matchup.WinnerID = decideMatchup(matchup)
+		}
+		matchups = append(matchups, matchup)
+	}
+	return matchups
+}
+
+// nextRoundMatchups составляет пары следующего раунда из победителей соседних пар
+func nextRoundMatchups(previous []*model.BracketMatchup, round int) []*model.BracketMatchup {
+	matchups := make([]*model.BracketMatchup, 0, (len(previous)+1)/2)
+	for i := 0; i < len(previous); i += 2 {
+		matchup := &model.BracketMatchup{Round: round, Position: i / 2}
+		matchup.ParticipantAID, matchup.SeedA = matchupWinner(previous[i])
+		if i+1 < len(previous) {
+			matchup.ParticipantBID, matchup.SeedB = matchupWinner(previous[i+1])
+		}
+		if matchup.ParticipantAID == "" || matchup.ParticipantBID == "" {
+			matchup.WinnerID = decideMatchup(matchup)
+		}
+		matchups = append(matchups, matchup)
+	}
+	return matchups
+}
+
+// decideMatchup определяет победителя пары: больше голосов, при равенстве - лучший (меньший) посев.
+// Если одной стороны нет, побеждает другая.
+func decideMatchup(matchup *model.BracketMatchup) model.ParticipantID {
+	switch {
+	case matchup.ParticipantAID == "":
+		return matchup.ParticipantBID
+	case matchup.ParticipantBID == "":
+		return matchup.ParticipantAID
+	case matchup.VotesA != matchup.VotesB:
+		if matchup.VotesA > matchup.VotesB {
+			return matchup.ParticipantAID
+		}
+		return matchup.ParticipantBID
+	case matchup.SeedA <= matchup.SeedB:
+		return matchup.ParticipantAID
+	default:
+		return matchup.ParticipantBID
+	}
+}
+
+// forfeitWinner - победитель пары, в которой одна сторона выбыла из конкурса, или пустая строка,
+// если обе стороны в игре (или выбыли обе)
+func forfeitWinner(matchup *model.BracketMatchup, entrants map[model.ParticipantID]bool) model.ParticipantID {
+	if matchup.ParticipantAID == "" || matchup.ParticipantBID == "" {
+		return ""
+	}
+	switch activeA, activeB := entrants[matchup.ParticipantAID], entrants[matchup.ParticipantBID]; {
+	case activeA && !activeB:
+		return matchup.ParticipantAID
+	case activeB && !activeA:
+		return matchup.ParticipantBID
+	default:
+		return ""
+	}
+}
+
+func matchupWinner(matchup *model.BracketMatchup) (model.ParticipantID, int) {
+	switch {
+	case matchup.WinnerID == "":
+		return "", 0
+	case matchup.WinnerID == matchup.ParticipantAID:
+		return matchup.WinnerID, matchup.SeedA
+	default:
+		return matchup.WinnerID, matchup.SeedB
+	}
+}
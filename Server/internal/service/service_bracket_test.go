@@ -0,0 +1,265 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"toppet/server/internal/model"
+)
+
+func TestBracketSeedOrder(t *testing.T) {
+	if got := bracketSeedOrder(8); !slices.Equal(got, []int{1, 8, 4, 5, 2, 7, 3, 6}) {
+		t.Errorf("Unexpected seed order for 8: %v", got)
+	}
+	if got := bracketSeedOrder(2); !slices.Equal(got, []int{1, 2}) {
+		t.Errorf("Unexpected seed order for 2: %v", got)
+	}
+}
+
+func TestFirstRoundMatchups(t *testing.T) {
+	matchups := firstRoundMatchups([]model.ParticipantID{"s1", "s2", "s3", "s4", "s5"})
+	if len(matchups) != 4 {
+		t.Fatalf("Expected 4 matchups for 5 participants, got %d", len(matchups))
+	}
+
+	expected := []struct {
+		a, b   model.ParticipantID
+		winner model.ParticipantID
+	}{
+		{a: "s1", winner: "s1"},
+		{a: "s4", b: "s5"},
+		{a: "s2", winner: "s2"},
+		{a: "s3", winner: "s3"},
+	}
+	for i, want := range expected {
+		got := matchups[i]
+		if got.ParticipantAID != want.a || got.ParticipantBID != want.b || got.WinnerID != want.winner || got.Position != i {
+			t.Errorf("Matchup %d: expected %+v, got %+v", i, want, got)
+		}
+	}
+}
+
+func TestDecideMatchup(t *testing.T) {
+	tests := []struct {
+		name    string
+		matchup model.BracketMatchup
+		want    model.ParticipantID
+	}{
+		{"more votes wins", model.BracketMatchup{ParticipantAID: "a", ParticipantBID: "b", SeedA: 1, SeedB: 2, VotesA: 3, VotesB: 5}, "b"},
+		{"tie goes to better seed", model.BracketMatchup{ParticipantAID: "a", ParticipantBID: "b", SeedA: 4, SeedB: 2, VotesA: 3, VotesB: 3}, "b"},
+		{"bye", model.BracketMatchup{ParticipantBID: "b", SeedB: 7}, "b"},
+		{"empty matchup", model.BracketMatchup{}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := decideMatchup(&tt.matchup); got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func newBracketTestService(status *model.ContestStatus) (*TopPetService, *mockRepository) {
+	mockRepo := &mockRepository{
+		participants: map[model.ParticipantID]*model.Participant{
+			"cat":     {ID: "cat", ContestID: "contest-id", UserID: 10},
+			"dog":     {ID: "dog", ContestID: "contest-id", UserID: 11},
+			"parrot":  {ID: "parrot", ContestID: "contest-id", UserID: 12},
+			"hamster": {ID: "hamster", ContestID: "contest-id", UserID: 13},
+		},
+		photoLikes: map[model.ParticipantID]int64{"cat": 5, "dog": 40, "parrot": 12, "hamster": 1},
+		getContestFunc: func(ctx context.Context, contestID model.ContestID) (*model.Contest, error) {
+			return &model.Contest{ID: contestID, CreatedByUserID: 1, Status: *status}, nil
+		},
+	}
+	for _, id := range []model.ParticipantID{"cat", "dog", "parrot", "hamster"} {
+		mockRepo.contestParticipants = append(mockRepo.contestParticipants, mockRepo.participants[id])
+	}
+	return &TopPetService{repository: mockRepo}, mockRepo
+}
+
+func TestTopPetService_StartBracket(t *testing.T) {
+	status := model.ContestStatusRegistration
+	service, mockRepo := newBracketTestService(&status)
+	ctx := context.Background()
+
+	if _, err := service.ConfigureBracket(ctx, "contest-id", 2, model.BracketSeedingLikes, 60); !errors.Is(err, model.ErrorForbidden) {
+		t.Errorf("Expected forbidden for non-organizer, got %v", err)
+	}
+	if _, err := service.ConfigureBracket(ctx, "contest-id", 1, "votes", 60); !errors.Is(err, model.ErrBadRequest) {
+		t.Errorf("Expected bad request for unknown seeding, got %v", err)
+	}
+	if _, err := service.ConfigureBracket(ctx, "contest-id", 1, model.BracketSeedingLikes, 60); err != nil {
+		t.Fatalf("Unexpected error configuring bracket: %v", err)
+	}
+	if _, err := service.StartBracket(ctx, "contest-id", 1, nil); !errors.Is(err, model.ErrBadRequest) {
+		t.Errorf("Expected bad request before voting stage, got %v", err)
+	}
+
+	status = model.ContestStatusVoting
+	bracket, err := service.StartBracket(ctx, "contest-id", 1, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if bracket.Status != model.BracketStatusRunning || len(bracket.Rounds) != 1 {
+		t.Fatalf("Expected running bracket with one round, got %+v", bracket)
+	}
+
+	round := bracket.Rounds[0]
+	if round.Status != model.BracketRoundActive || round.EndsAt.Sub(round.StartsAt) != time.Hour {
+		t.Errorf("Expected active hour-long first round, got %+v", round)
+	}
+	// По лайкам: dog(1) - hamster(4), parrot(2) - cat(3)
+	if len(round.Matchups) != 2 ||
+		round.Matchups[0].ParticipantAID != "dog" || round.Matchups[0].ParticipantBID != "hamster" ||
+		round.Matchups[1].ParticipantAID != "parrot" || round.Matchups[1].ParticipantBID != "cat" {
+		t.Errorf("Unexpected seeding: %+v, %+v", round.Matchups[0], round.Matchups[1])
+	}
+
+	if _, err := service.StartBracket(ctx, "contest-id", 1, nil); !errors.Is(err, model.ErrBadRequest) {
+		t.Errorf("Expected bad request for second start, got %v", err)
+	}
+	if _, err := service.ConfigureBracket(ctx, "contest-id", 1, model.BracketSeedingRandom, 60); !errors.Is(err, model.ErrBadRequest) {
+		t.Errorf("Expected bad request for reconfiguring started bracket, got %v", err)
+	}
+	if len(mockRepo.bracketRounds) != 1 {
+		t.Errorf("Expected one stored round, got %d", len(mockRepo.bracketRounds))
+	}
+}
+
+func TestTopPetService_VoteInMatchup(t *testing.T) {
+	status := model.ContestStatusVoting
+	service, mockRepo := newBracketTestService(&status)
+	ctx := context.Background()
+
+	if _, err := service.ConfigureBracket(ctx, "contest-id", 1, model.BracketSeedingLikes, 60); err != nil {
+		t.Fatalf("Unexpected error configuring bracket: %v", err)
+	}
+	if _, err := service.StartBracket(ctx, "contest-id", 1, nil); err != nil {
+		t.Fatalf("Unexpected error starting bracket: %v", err)
+	}
+	matchupID := mockRepo.bracketMatchups[0].ID // dog - hamster
+
+	if _, err := service.VoteInMatchup(ctx, "contest-id", matchupID, 2, "cat"); !errors.Is(err, model.ErrBadRequest) {
+		t.Errorf("Expected bad request for participant outside matchup, got %v", err)
+	}
+
+	matchup, err := service.VoteInMatchup(ctx, "contest-id", matchupID, 2, "hamster")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if matchup.VotesB != 1 || matchup.UserVote != "hamster" {
+		t.Errorf("Expected one vote for hamster, got %+v", matchup)
+	}
+
+	// Повторный голос переносится, а не добавляется
+	matchup, err = service.VoteInMatchup(ctx, "contest-id", matchupID, 2, "dog")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if matchup.VotesA != 1 || matchup.VotesB != 0 {
+		t.Errorf("Expected vote moved to dog, got %+v", matchup)
+	}
+
+	mockRepo.bracketRounds[0].EndsAt = time.Now().Add(-time.Minute)
+	_, err = service.VoteInMatchup(ctx, "contest-id", matchupID, 3, "dog")
+	var coded *model.CodedError
+	if !errors.As(err, &coded) || coded.Code != model.VoteIneligibleVotingClosed {
+		t.Errorf("Expected voting_closed after round end, got %v", err)
+	}
+}
+
+func TestTopPetService_AdvanceBrackets(t *testing.T) {
+	status := model.ContestStatusVoting
+	service, mockRepo := newBracketTestService(&status)
+	ctx := context.Background()
+
+	if _, err := service.ConfigureBracket(ctx, "contest-id", 1, model.BracketSeedingLikes, 60); err != nil {
+		t.Fatalf("Unexpected error configuring bracket: %v", err)
+	}
+	if _, err := service.StartBracket(ctx, "contest-id", 1, nil); err != nil {
+		t.Fatalf("Unexpected error starting bracket: %v", err)
+	}
+	// hamster обыгрывает dog по голосам, parrot - cat проходит parrot по посеву (ничья)
+	if _, err := service.VoteInMatchup(ctx, "contest-id", mockRepo.bracketMatchups[0].ID, 2, "hamster"); err != nil {
+		t.Fatalf("Unexpected error voting: %v", err)
+	}
+
+	now := time.Now().Add(61 * time.Minute)
+	if err := service.AdvanceBrackets(ctx, now); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(mockRepo.bracketRounds) != 2 || mockRepo.bracketRounds[0].Status != model.BracketRoundFinished {
+		t.Fatalf("Expected finished first round and a new round, got %+v", mockRepo.bracketRounds)
+	}
+	final := mockRepo.bracketMatchups[2]
+	if final.Round != 2 || final.ParticipantAID != "hamster" || final.SeedA != 4 || final.ParticipantBID != "parrot" || final.SeedB != 2 {
+		t.Errorf("Unexpected final: %+v", final)
+	}
+	if mockRepo.bracket.CurrentRound != 2 || mockRepo.bracket.Status != model.BracketStatusRunning {
+		t.Errorf("Expected bracket in round 2, got %+v", mockRepo.bracket)
+	}
+
+	// Повторный проход в то же время ничего не меняет
+	if err := service.AdvanceBrackets(ctx, now); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(mockRepo.bracketRounds) != 2 {
+		t.Errorf("Expected no extra rounds, got %d", len(mockRepo.bracketRounds))
+	}
+
+	if err := service.AdvanceBrackets(ctx, now.Add(61*time.Minute)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if mockRepo.bracket.Status != model.BracketStatusFinished || mockRepo.bracket.ChampionID != "parrot" {
+		t.Errorf("Expected parrot to win the final on seed, got %+v", mockRepo.bracket)
+	}
+}
+
+func TestTopPetService_BracketWithdrawnParticipants(t *testing.T) {
+	status := model.ContestStatusVoting
+	service, mockRepo := newBracketTestService(&status)
+	ctx := context.Background()
+
+	if _, err := service.ConfigureBracket(ctx, "contest-id", 1, model.BracketSeedingLikes, 60); err != nil {
+		t.Fatalf("Unexpected error configuring bracket: %v", err)
+	}
+	if _, err := service.StartBracket(ctx, "contest-id", 1, nil); err != nil {
+		t.Fatalf("Unexpected error starting bracket: %v", err)
+	}
+	first, second := mockRepo.bracketMatchups[0].ID, mockRepo.bracketMatchups[1].ID // dog - hamster, parrot - cat
+	if _, err := service.VoteInMatchup(ctx, "contest-id", first, 2, "dog"); err != nil {
+		t.Fatalf("Unexpected error voting: %v", err)
+	}
+	if _, err := service.VoteInMatchup(ctx, "contest-id", second, 2, "cat"); err != nil {
+		t.Fatalf("Unexpected error voting: %v", err)
+	}
+
+	// Дисквалификация сразу отдает открытую пару сопернику, несмотря на голоса
+	if _, err := service.DisqualifyParticipant(ctx, "dog", 1, "фото из интернета"); err != nil {
+		t.Fatalf("Unexpected error disqualifying: %v", err)
+	}
+	if matchup := mockRepo.bracketMatchups[0]; matchup.WinnerID != "hamster" || matchup.DecidedAt == nil {
+		t.Errorf("Expected hamster to win by forfeit, got %+v", matchup)
+	}
+
+	// За скрытую заявку голосовать нельзя, а при подведении итогов она проигрывает
+	mockRepo.participants["cat"].Hidden = true
+	mockRepo.contestParticipants = []*model.Participant{mockRepo.participants["hamster"], mockRepo.participants["parrot"]}
+	if _, err := service.VoteInMatchup(ctx, "contest-id", second, 3, "cat"); !errors.Is(err, model.ErrBadRequest) {
+		t.Errorf("Expected bad request for hidden participant, got %v", err)
+	}
+	if err := service.AdvanceBrackets(ctx, time.Now().Add(61*time.Minute)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if matchup := mockRepo.bracketMatchups[1]; matchup.WinnerID != "parrot" {
+		t.Errorf("Expected parrot to win against hidden cat, got %+v", matchup)
+	}
+	final := mockRepo.bracketMatchups[2]
+	if final.ParticipantAID != "hamster" || final.ParticipantBID != "parrot" {
+		t.Errorf("Unexpected final: %+v", final)
+	}
+}
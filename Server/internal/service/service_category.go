@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"toppet/server/internal/model"
+)
+
+const (
+	maxContestCategories = 10
+	maxCategoryNameChars = 100
+	maxCategoryDescChars = 500
+)
+
+// ListContestCategories возвращает номинации конкурса (публично). Главный приз в список не входит.
+func (s *TopPetService) ListContestCategories(ctx context.Context, contestID model.ContestID) ([]*model.ContestCategory, error) {
+	if _, err := s.GetContest(ctx, contestID); err != nil {
+		return nil, err
+	}
+	return s.repository.ListContestCategories(ctx, contestID)
+}
+
+// CreateContestCategory добавляет номинацию. Номинации задаются до начала голосования.
+func (s *TopPetService) CreateContestCategory(ctx context.Context, contestID model.ContestID, actorID model.UserID, name, description string) (*model.ContestCategory, error) {
+	contest, err := s.categoryManagedContest(ctx, contestID, actorID)
+	if err != nil {
+		return nil, err
+	}
+	if contest.Status != model.ContestStatusDraft && contest.Status != model.ContestStatusRegistration {
+		return nil, fmt.Errorf("%w: categories can only be added before voting starts", model.ErrBadRequest)
+	}
+
+	name, description, err = normalizeCategory(name, description)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repository.ListContestCategories(ctx, contestID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= maxContestCategories {
+		return nil, fmt.Errorf("%w: at most %d categories per contest", model.ErrBadRequest, maxContestCategories)
+	}
+	if categoryNameTaken(existing, "", name) {
+		return nil, fmt.Errorf("%w: category %q already exists", model.ErrBadRequest, name)
+	}
+
+	return s.repository.CreateContestCategory(ctx, &model.ContestCategory{
+		ContestID:   contestID,
+		Name:        name,
+		Description: description,
+	})
+}
+
+// UpdateContestCategory переименовывает номинацию (до завершения конкурса)
+func (s *TopPetService) UpdateContestCategory(ctx context.Context, contestID model.ContestID, actorID model.UserID, categoryID model.CategoryID, name, description string) (*model.ContestCategory, error) {
+	if _, err := s.categoryManagedContest(ctx, contestID, actorID); err != nil {
+		return nil, err
+	}
+
+	name, description, err := normalizeCategory(name, description)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repository.ListContestCategories(ctx, contestID)
+	if err != nil {
+		return nil, err
+	}
+	if categoryNameTaken(existing, categoryID, name) {
+		return nil, fmt.Errorf("%w: category %q already exists", model.ErrBadRequest, name)
+	}
+
+	return s.repository.UpdateContestCategory(ctx, &model.ContestCategory{
+		ID:          categoryID,
+		ContestID:   contestID,
+		Name:        name,
+		Description: description,
+	})
+}
+
+// DeleteContestCategory удаляет номинацию. После начала голосования удалять нельзя - пропали бы голоса.
+func (s *TopPetService) DeleteContestCategory(ctx context.Context, contestID model.ContestID, actorID model.UserID, categoryID model.CategoryID) error {
+	contest, err := s.categoryManagedContest(ctx, contestID, actorID)
+	if err != nil {
+		return err
+	}
+	if contest.Status != model.ContestStatusDraft && contest.Status != model.ContestStatusRegistration {
+		return fmt.Errorf("%w: categories can only be removed before voting starts", model.ErrBadRequest)
+	}
+	if _, err := s.repository.GetContestCategory(ctx, contestID, categoryID); err != nil {
+		return err
+	}
+	return s.repository.DeleteContestCategory(ctx, contestID, categoryID)
+}
+
+// checkContestCategory проверяет, что номинация принадлежит конкурсу. Пустая номинация - главный приз.
+func (s *TopPetService) checkContestCategory(ctx context.Context, contestID model.ContestID, categoryID model.CategoryID) error {
+	if categoryID == "" {
+		return nil
+	}
+	_, err := s.repository.GetContestCategory(ctx, contestID, categoryID)
+	return err
+}
+
+func (s *TopPetService) categoryManagedContest(ctx context.Context, contestID model.ContestID, actorID model.UserID) (*model.Contest, error) {
+	contest, err := s.repository.GetContest(ctx, contestID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.CanManageContest(ctx, contest, actorID) {
+		return nil, model.ErrorForbidden
+	}
+	if contest.Status == model.ContestStatusFinished {
+		return nil, fmt.Errorf("%w: contest is finished", model.ErrBadRequest)
+	}
+	return contest, nil
+}
+
+func normalizeCategory(name, description string) (string, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > maxCategoryNameChars {
+		return "", "", fmt.Errorf("%w: name must be 1-%d characters", model.ErrBadRequest, maxCategoryNameChars)
+	}
+	description = strings.TrimSpace(description)
+	if utf8.RuneCountInString(description) > maxCategoryDescChars {
+		return "", "", fmt.Errorf("%w: description must be at most %d characters", model.ErrBadRequest, maxCategoryDescChars)
+	}
+	return name, description, nil
+}
+
+func categoryNameTaken(categories []*model.ContestCategory, exceptID model.CategoryID, name string) bool {
+	for _, category := range categories {
+		if category.ID != exceptID && strings.EqualFold(category.Name, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"toppet/server/internal/model"
+)
+
+func TestTopPetService_CreateContestCategory(t *testing.T) {
+	status := model.ContestStatusRegistration
+	mockRepo := &mockRepository{
+		getContestFunc: func(ctx context.Context, contestID model.ContestID) (*model.Contest, error) {
+			return &model.Contest{ID: contestID, CreatedByUserID: 1, Status: status}, nil
+		},
+	}
+	service := &TopPetService{repository: mockRepo}
+	ctx := context.Background()
+
+	if _, err := service.CreateContestCategory(ctx, "contest-id", 2, "Самый пушистый", ""); !errors.Is(err, model.ErrorForbidden) {
+		t.Errorf("Expected forbidden for non-organizer, got %v", err)
+	}
+	if _, err := service.CreateContestCategory(ctx, "contest-id", 1, "   ", ""); !errors.Is(err, model.ErrBadRequest) {
+		t.Errorf("Expected bad request for empty name, got %v", err)
+	}
+
+	category, err := service.CreateContestCategory(ctx, "contest-id", 1, "  Самый пушистый ", "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if category.Name != "Самый пушистый" || category.ContestID != "contest-id" {
+		t.Errorf("Unexpected category: %+v", category)
+	}
+	if _, err := service.CreateContestCategory(ctx, "contest-id", 1, "самый ПУШИСТЫЙ", ""); !errors.Is(err, model.ErrBadRequest) {
+		t.Errorf("Expected bad request for duplicate name, got %v", err)
+	}
+
+	status = model.ContestStatusVoting
+	if _, err := service.CreateContestCategory(ctx, "contest-id", 1, "Лучшее видео", ""); !errors.Is(err, model.ErrBadRequest) {
+		t.Errorf("Expected bad request once voting started, got %v", err)
+	}
+	if err := service.DeleteContestCategory(ctx, "contest-id", 1, category.ID); !errors.Is(err, model.ErrBadRequest) {
+		t.Errorf("Expected bad request for deleting category during voting, got %v", err)
+	}
+}
+
+func TestTopPetService_VoteInCategory(t *testing.T) {
+	mockRepo := &mockRepository{
+		participants: map[model.ParticipantID]*model.Participant{
+			"pet": {ID: "pet", ContestID: "contest-id", UserID: 3},
+		},
+		categories: []*model.ContestCategory{
+			{ID: "funniest", ContestID: "contest-id", Name: "Самый смешной"},
+			{ID: "foreign", ContestID: "other-contest", Name: "Чужая номинация"},
+		},
+		getContestFunc: func(ctx context.Context, contestID model.ContestID) (*model.Contest, error) {
+			return &model.Contest{ID: contestID, Status: model.ContestStatusVoting}, nil
+		},
+	}
+	service := &TopPetService{repository: mockRepo}
+	ctx := context.Background()
+	ballot := []model.BallotChoice{{ParticipantID: "pet"}}
+
+	if _, err := service.Vote(ctx, "contest-id", "foreign", 2, ballot, model.VoteClient{}); !errors.Is(err, model.ErrorNotFound) {
+		t.Errorf("Expected not found for category of another contest, got %v", err)
+	}
+
+	vote, err := service.Vote(ctx, "contest-id", "funniest", 2, ballot, model.VoteClient{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if vote.CategoryID != "funniest" || vote.ParticipantID != "pet" {
+		t.Errorf("Expected vote for pet in funniest, got %+v", vote)
+	}
+
+	main, err := service.Vote(ctx, "contest-id", "", 2, ballot, model.VoteClient{})
+	if err != nil {
+		t.Fatalf("Unexpected error voting for main prize: %v", err)
+	}
+	if main.CategoryID != "" {
+		t.Errorf("Expected main prize vote without category, got %q", main.CategoryID)
+	}
+}
+
+func TestTopPetService_GetContestResultsByCategory(t *testing.T) {
+	mockRepo := &mockRepository{
+		categories: []*model.ContestCategory{{ID: "funniest", ContestID: "contest-id"}},
+		getContestFunc: func(ctx context.Context, contestID model.ContestID) (*model.Contest, error) {
+			return &model.Contest{ID: contestID, Status: model.ContestStatusFinished}, nil
+		},
+	}
+	service := &TopPetService{repository: mockRepo}
+	ctx := context.Background()
+
+	results, err := service.GetContestResults(ctx, "contest-id", "funniest")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if results.CategoryID != "funniest" {
+		t.Errorf("Expected results for funniest, got %q", results.CategoryID)
+	}
+	if _, err := service.GetContestResults(ctx, "contest-id", "unknown"); !errors.Is(err, model.ErrorNotFound) {
+		t.Errorf("Expected not found for unknown category, got %v", err)
+	}
+}
@@ -61,7 +61,7 @@ func (s *TopPetService) ListChatMessages(ctx context.Context, contestID model.Co
 		limit = 100
 	}
 
-	contest, err := s.repository.GetContest(ctx, contestID)
+	contest, err := s.visibleContest(ctx, contestID)
 	if err != nil {
 		log.Printf("[Service] ListChatMessages: ERROR - Failed to get contest: %v", err)
 		return nil, 0, err
@@ -111,7 +111,14 @@ func (s *TopPetService) UpdateChatMessage(ctx context.Context, messageID model.C
 func (s *TopPetService) DeleteChatMessage(ctx context.Context, messageID model.ChatMessageID, userID model.UserID) error {
 	contestID, err := s.repository.DeleteChatMessage(ctx, messageID, userID)
 	if err != nil {
-		return err
+		// Not the author: moderators may delete any message
+		if s.isStaff(ctx, userID) {
+			_, err = s.Moderate(ctx, userID, model.ModerationActionDelete, model.ModerationTargetChatMessage, string(messageID), "")
+			return err
+		}
+		if contestID, err = s.deleteChatMessageAsContestMember(ctx, messageID, userID); err != nil {
+			return err
+		}
 	}
 
 	if s.hub != nil {
@@ -125,3 +132,19 @@ func (s *TopPetService) DeleteChatMessage(ctx context.Context, messageID model.C
 
 	return nil
 }
+
+// deleteChatMessageAsContestMember удаляет чужое сообщение, если пользователь входит в команду конкурса
+func (s *TopPetService) deleteChatMessageAsContestMember(ctx context.Context, messageID model.ChatMessageID, userID model.UserID) (model.ContestID, error) {
+	contestID, err := s.repository.GetChatMessageContestID(ctx, messageID)
+	if err != nil {
+		return "", err
+	}
+	contest, err := s.repository.GetContest(ctx, contestID)
+	if err != nil {
+		return "", err
+	}
+	if !s.canModerateContest(ctx, contest, userID) {
+		return "", model.ErrorForbidden
+	}
+	return s.repository.DeleteChatMessageByID(ctx, messageID)
+}
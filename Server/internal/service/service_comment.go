@@ -34,7 +34,12 @@ func (s *TopPetService) CreateComment(ctx context.Context, participantID model.P
 		return nil, errors.New("comments are only allowed during registration or voting")
 	}
 
-	return s.repository.CreateComment(ctx, participantID, userID, text)
+	comment, err := s.repository.CreateComment(ctx, participantID, userID, text)
+	if err != nil {
+		return nil, err
+	}
+	s.notifyComment(ctx, participant, comment)
+	return comment, nil
 }
 
 func (s *TopPetService) ListComments(ctx context.Context, participantID model.ParticipantID, limit, offset int) ([]*model.Comment, int64, error) {
@@ -45,6 +50,17 @@ func (s *TopPetService) ListComments(ctx context.Context, participantID model.Pa
 		limit = 100
 	}
 
+	participant, err := s.repository.GetParticipant(ctx, participantID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if participant.Hidden && !s.canViewHidden(ctx) {
+		return nil, 0, model.ErrorNotFound
+	}
+	if _, err := s.visibleContest(ctx, participant.ContestID); err != nil {
+		return nil, 0, err
+	}
+
 	return s.repository.ListCommentsByParticipant(ctx, participantID, limit, offset)
 }
 
@@ -89,6 +105,12 @@ func (s *TopPetService) DeleteComment(ctx context.Context, commentID model.Comme
 		return err
 	}
 
+	// Moderators may delete any comment regardless of contest status
+	if comment.UserID != userID && s.isStaff(ctx, userID) {
+		_, err = s.Moderate(ctx, userID, model.ModerationActionDelete, model.ModerationTargetComment, string(commentID), "")
+		return err
+	}
+
 	participant, err := s.repository.GetParticipant(ctx, comment.ParticipantID)
 	if err != nil {
 		return err
@@ -102,7 +124,7 @@ func (s *TopPetService) DeleteComment(ctx context.Context, commentID model.Comme
 	}
 
 	if comment.UserID != userID {
-		if contest.CreatedByUserID != userID {
+		if !s.canModerateContest(ctx, contest, userID) {
 			return errors.New("only comment author or contest owner can delete comment")
 		}
 	}
@@ -30,7 +30,7 @@ func (s *TopPetService) GetContest(ctx context.Context, contestID model.ContestI
 	dbCtx, cancel := appcontext.WithDatabaseTimeout(ctx)
 	defer cancel()
 
-	contest, err := s.repository.GetContest(dbCtx, contestID)
+	contest, err := s.visibleContest(dbCtx, contestID)
 	if err != nil {
 		return nil, err
 	}
@@ -44,6 +44,19 @@ func (s *TopPetService) GetContest(ctx context.Context, contestID model.ContestI
 	return contest, nil
 }
 
+// visibleContest загружает конкурс с учетом модерации: скрытый конкурс и все, что к нему относится
+// (участники, комментарии, чат), для всех, кроме персонала, выглядит как несуществующий
+func (s *TopPetService) visibleContest(ctx context.Context, contestID model.ContestID) (*model.Contest, error) {
+	contest, err := s.repository.GetContest(ctx, contestID)
+	if err != nil {
+		return nil, err
+	}
+	if contest.Hidden && !s.canViewHidden(ctx) {
+		return nil, model.ErrorNotFound
+	}
+	return contest, nil
+}
+
 func (s *TopPetService) ListContests(ctx context.Context, status *model.ContestStatus, limit, offset int) ([]*model.Contest, int64, error) {
 	if limit <= 0 {
 		limit = 20
@@ -95,8 +108,8 @@ func (s *TopPetService) UpdateContest(ctx context.Context, contestID model.Conte
 		return nil, err
 	}
 
-	// Only admin can update
-	if contest.CreatedByUserID != userID {
+	// Only owner or organizer can update
+	if !s.CanManageContest(ctx, contest, userID) {
 		return nil, errors.New("only contest admin can update contest")
 	}
 
@@ -114,8 +127,8 @@ func (s *TopPetService) PublishContest(ctx context.Context, contestID model.Cont
 		return nil, err
 	}
 
-	// Only admin can publish
-	if contest.CreatedByUserID != userID {
+	// Only owner or organizer can publish
+	if !s.CanManageContest(ctx, contest, userID) {
 		return nil, errors.New("only contest admin can publish contest")
 	}
 
@@ -124,7 +137,12 @@ func (s *TopPetService) PublishContest(ctx context.Context, contestID model.Cont
 		return nil, fmt.Errorf("contest must be in draft status to publish, current status: %s", contest.Status)
 	}
 
-	return s.repository.UpdateContestStatus(ctx, contestID, model.ContestStatusRegistration)
+	updated, err := s.repository.UpdateContestStatus(ctx, contestID, model.ContestStatusRegistration)
+	if err != nil {
+		return nil, err
+	}
+	s.emitContestStatusWebhooks(ctx, updated, contest.Status)
+	return updated, nil
 }
 
 func (s *TopPetService) FinishContest(ctx context.Context, contestID model.ContestID, userID model.UserID) (*model.Contest, error) {
@@ -133,8 +151,8 @@ func (s *TopPetService) FinishContest(ctx context.Context, contestID model.Conte
 		return nil, err
 	}
 
-	// Only admin can finish
-	if contest.CreatedByUserID != userID {
+	// Only owner or organizer can finish
+	if !s.CanManageContest(ctx, contest, userID) {
 		return nil, errors.New("only contest admin can finish contest")
 	}
 
@@ -143,7 +161,13 @@ func (s *TopPetService) FinishContest(ctx context.Context, contestID model.Conte
 		return nil, fmt.Errorf("contest must be in voting status to finish, current status: %s", contest.Status)
 	}
 
-	return s.repository.UpdateContestStatus(ctx, contestID, model.ContestStatusFinished)
+	updated, err := s.repository.UpdateContestStatus(ctx, contestID, model.ContestStatusFinished)
+	if err != nil {
+		return nil, err
+	}
+	s.notifyContestStatus(ctx, updated, userID)
+	s.emitContestStatusWebhooks(ctx, updated, contest.Status)
+	return updated, nil
 }
 
 func (s *TopPetService) UpdateContestStatus(ctx context.Context, contestID model.ContestID, userID model.UserID, status model.ContestStatus) (*model.Contest, error) {
@@ -152,7 +176,7 @@ func (s *TopPetService) UpdateContestStatus(ctx context.Context, contestID model
 		return nil, err
 	}
 
-	if contest.CreatedByUserID != userID {
+	if !s.CanManageContest(ctx, contest, userID) {
 		return nil, errors.New("only contest admin can update contest status")
 	}
 
@@ -174,6 +198,10 @@ func (s *TopPetService) UpdateContestStatus(ctx context.Context, contestID model
 		payload := wsapp.NewContestStatusUpdatedPayload(contestID, string(status))
 		_ = s.hub.BroadcastContestMessage(contestID, payload)
 	}
+	if contest.Status != status {
+		s.notifyContestStatus(ctx, updated, userID)
+		s.emitContestStatusWebhooks(ctx, updated, contest.Status)
+	}
 
 	return updated, nil
 }
@@ -184,8 +212,12 @@ func (s *TopPetService) DeleteContest(ctx context.Context, contestID model.Conte
 		return err
 	}
 
-	// Only admin can delete
+	// Only admin can delete; moderators delete through the moderation log
 	if contest.CreatedByUserID != userID {
+		if s.isStaff(ctx, userID) {
+			_, err = s.Moderate(ctx, userID, model.ModerationActionDelete, model.ModerationTargetContest, string(contestID), "")
+			return err
+		}
 		return errors.New("only contest admin can delete contest")
 	}
 
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"toppet/server/internal/model"
+)
+
+// ListContestMembers возвращает команду конкурса (включая неподтвержденные приглашения).
+// Список видят члены команды и персонал платформы.
+func (s *TopPetService) ListContestMembers(ctx context.Context, contestID model.ContestID, userID model.UserID) ([]*model.ContestMember, error) {
+	contest, err := s.repository.GetContest(ctx, contestID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.canModerateContest(ctx, contest, userID) && !s.isStaff(ctx, userID) {
+		return nil, model.ErrorForbidden
+	}
+
+	return s.repository.ListContestMembers(ctx, contestID)
+}
+
+// InviteContestMember приглашает пользователя в команду конкурса. Организаторов и модераторов
+// назначает только владелец, членов жюри - владелец и организаторы. Если пользователь уже в команде,
+// меняется его роль.
+func (s *TopPetService) InviteContestMember(ctx context.Context, contestID model.ContestID, actorID, userID model.UserID, role model.ContestMemberRole) (*model.ContestMember, error) {
+	switch role {
+	case model.ContestMemberOrganizer, model.ContestMemberModerator, model.ContestMemberJuror:
+	default:
+		return nil, fmt.Errorf("%w: invalid role %q", model.ErrBadRequest, role)
+	}
+
+	contest, err := s.repository.GetContest(ctx, contestID)
+	if err != nil {
+		return nil, err
+	}
+	if contest.CreatedByUserID != actorID && !(role == model.ContestMemberJuror && s.CanManageContest(ctx, contest, actorID)) {
+		return nil, fmt.Errorf("%w: only contest owner can manage members", model.ErrorForbidden)
+	}
+	if userID == contest.CreatedByUserID {
+		return nil, fmt.Errorf("%w: owner is already a member", model.ErrBadRequest)
+	}
+
+	if _, err := s.repository.GetUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repository.GetContestMember(ctx, contestID, userID)
+	if err == nil && existing != nil {
+		// Организатор не может понизить другого члена команды до жюри
+		if existing.Role != model.ContestMemberJuror && contest.CreatedByUserID != actorID {
+			return nil, fmt.Errorf("%w: only contest owner can change member roles", model.ErrorForbidden)
+		}
+		return s.repository.UpdateContestMemberRole(ctx, contestID, userID, role)
+	}
+	if !errors.Is(err, model.ErrorNotFound) {
+		return nil, err
+	}
+
+	return s.repository.CreateContestMember(ctx, &model.ContestMember{
+		ContestID:       contestID,
+		UserID:          userID,
+		Role:            role,
+		Status:          model.ContestMemberInvited,
+		InvitedByUserID: &actorID,
+	})
+}
+
+// AcceptContestInvite принимает приглашение текущего пользователя
+func (s *TopPetService) AcceptContestInvite(ctx context.Context, contestID model.ContestID, userID model.UserID) (*model.ContestMember, error) {
+	return s.repository.AcceptContestMemberInvite(ctx, contestID, userID)
+}
+
+// RemoveContestMember убирает пользователя из команды. Владелец может убрать любого, организаторы -
+// членов жюри, остальные - только себя (выйти из команды или отклонить приглашение).
+func (s *TopPetService) RemoveContestMember(ctx context.Context, contestID model.ContestID, actorID, userID model.UserID) error {
+	contest, err := s.repository.GetContest(ctx, contestID)
+	if err != nil {
+		return err
+	}
+	if userID == contest.CreatedByUserID {
+		return fmt.Errorf("%w: transfer ownership before leaving the contest", model.ErrBadRequest)
+	}
+
+	member, err := s.repository.GetContestMember(ctx, contestID, userID)
+	if err != nil {
+		return err
+	}
+	// Членов жюри могут убирать и организаторы
+	canRemove := actorID == userID || contest.CreatedByUserID == actorID ||
+		(member.Role == model.ContestMemberJuror && s.CanManageContest(ctx, contest, actorID))
+	if !canRemove {
+		return fmt.Errorf("%w: only contest owner can manage members", model.ErrorForbidden)
+	}
+
+	return s.repository.DeleteContestMember(ctx, contestID, userID)
+}
+
+// TransferContestOwnership передает конкурс активному члену команды; прежний владелец становится организатором
+func (s *TopPetService) TransferContestOwnership(ctx context.Context, contestID model.ContestID, actorID, newOwnerID model.UserID) (*model.Contest, error) {
+	contest, err := s.repository.GetContest(ctx, contestID)
+	if err != nil {
+		return nil, err
+	}
+	if contest.CreatedByUserID != actorID {
+		return nil, fmt.Errorf("%w: only contest owner can transfer ownership", model.ErrorForbidden)
+	}
+	if newOwnerID == actorID {
+		return nil, fmt.Errorf("%w: user is already the owner", model.ErrBadRequest)
+	}
+
+	member, err := s.repository.GetContestMember(ctx, contestID, newOwnerID)
+	if err != nil {
+		return nil, err
+	}
+	if member.Status != model.ContestMemberActive {
+		return nil, fmt.Errorf("%w: new owner must accept the invitation first", model.ErrBadRequest)
+	}
+
+	if err := s.repository.TransferContestOwnership(ctx, contestID, actorID, newOwnerID); err != nil {
+		return nil, err
+	}
+
+	return s.repository.GetContest(ctx, contestID)
+}
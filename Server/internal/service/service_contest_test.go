@@ -3,7 +3,11 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"slices"
+	"sort"
 	"testing"
+	"time"
 
 	"toppet/server/internal/model"
 )
@@ -18,6 +22,80 @@ type mockRepository struct {
 	listContestsFunc       func(ctx context.Context, status *model.ContestStatus, limit, offset int) ([]*model.Contest, int64, error)
 	countVotesByContestFunc func(ctx context.Context, contestID model.ContestID) (int64, error)
 	countVotesByContestsFunc func(ctx context.Context, contestIDs []model.ContestID) (map[model.ContestID]int64, error)
+	userRoles              map[model.UserID][]model.Role
+	moderationActions      []*model.ModerationAction
+	contestMembers         map[model.UserID]*model.ContestMember
+	voidedVoteIDs          []string
+	users                  map[model.UserID]*model.User
+	authProviders          map[model.UserID][]*model.UserAuthProvider
+	participants           map[model.ParticipantID]*model.Participant
+	votingPolicy           *model.VotingPolicy
+	invitedVoters          map[model.UserID]bool
+	voteChoices            []model.BallotChoice
+	votingModeUpdates      int
+	juryCriteria           []*model.JuryCriterion
+	juryScores             []*model.JuryScore
+	categories             []*model.ContestCategory
+	contestParticipants    []*model.Participant
+	photoLikes             map[model.ParticipantID]int64
+	bracket                *model.Bracket
+	bracketRounds          []*model.BracketRound
+	bracketMatchups        []*model.BracketMatchup
+	bracketVotes           map[string]map[model.UserID]model.ParticipantID
+	entryRules             *model.EntryRules
+	participantCount       int64
+	userParticipantCount   int64
+	photoCount             int64
+	participantVotes       []*model.Vote
+	userVote               *model.Vote
+	chatMessages           []*model.ChatMessage
+	pets                   map[model.PetID]*model.Pet
+	petPhotos              []*model.PetPhoto
+	petEntries             []*model.PetEntry
+	ballots                []*model.Ballot
+	species                []*model.Species
+	breeds                 []*model.Breed
+	privacy                *model.PrivacySettings
+	creatorContests        []*model.Contest
+	participantPhotos      map[model.ParticipantID][]*model.Photo
+	comments               []*model.Comment
+	deletionJobs           []*model.AccountDeletionJob
+	deletedUserData        []model.UserID
+	notifications          []*model.Notification
+	notificationPrefs      map[model.UserID]*model.NotificationPreferences
+	outbox                 []*mockOutboxEmail
+	telegramLinkTokens     map[string]model.UserID
+	telegramAccounts       map[model.UserID]*model.TelegramAccount
+	telegramSubscriptions  map[int64][]model.ContestID
+	telegramOutbox         []*mockTelegramMessage
+	telegramAnnouncements  []*model.TelegramAnnouncement
+	webhookEndpoints       []*model.WebhookEndpoint
+	webhookEvents          map[string]*model.WebhookEvent
+	webhookDeliveries      []*model.WebhookDelivery
+	transferredOwnerID     model.UserID
+	wsTickets              map[string]*mockWSTicket
+}
+
+// mockOutboxEmail письмо в очереди мока вместе с состоянием доставки
+type mockOutboxEmail struct {
+	*model.OutboxEmail
+	status        model.OutboxStatus
+	lastError     string
+	nextAttemptAt time.Time
+}
+
+// mockWSTicket билет WebSocket в моке: кому выдан и до какого времени действует
+type mockWSTicket struct {
+	userID    model.UserID
+	expiresAt time.Time
+}
+
+// mockTelegramMessage сообщение бота в очереди мока вместе с состоянием доставки
+type mockTelegramMessage struct {
+	*model.TelegramMessage
+	status        model.OutboxStatus
+	lastError     string
+	nextAttemptAt time.Time
 }
 
 func (m *mockRepository) CreateContest(ctx context.Context, userID model.UserID, title, description string) (*model.Contest, error) {
@@ -48,6 +126,15 @@ func (m *mockRepository) UpdateContestStatus(ctx context.Context, contestID mode
 	return nil, nil
 }
 
+func (m *mockRepository) UpdateContestVotingMode(ctx context.Context, contestID model.ContestID, mode model.VotingMode, maxChoices, minBallots int) (*model.Contest, error) {
+	m.votingModeUpdates++
+	return &model.Contest{ID: contestID, VotingMode: mode, MaxChoices: maxChoices, MinBallots: minBallots}, nil
+}
+
+func (m *mockRepository) UpdateContestJuryWeight(ctx context.Context, contestID model.ContestID, juryWeight int) (*model.Contest, error) {
+	return &model.Contest{ID: contestID, JuryWeight: juryWeight}, nil
+}
+
 func (m *mockRepository) DeleteContest(ctx context.Context, contestID model.ContestID) error {
 	if m.deleteContestFunc != nil {
 		return m.deleteContestFunc(ctx, contestID)
@@ -62,6 +149,10 @@ func (m *mockRepository) ListContests(ctx context.Context, status *model.Contest
 	return nil, 0, nil
 }
 
+func (m *mockRepository) ListPublicContestsByCreator(ctx context.Context, userID model.UserID, limit int) ([]*model.Contest, error) {
+	return m.creatorContests, nil
+}
+
 func (m *mockRepository) CountVotesByContest(ctx context.Context, contestID model.ContestID) (int64, error) {
 	if m.countVotesByContestFunc != nil {
 		return m.countVotesByContestFunc(ctx, contestID)
@@ -79,38 +170,1057 @@ func (m *mockRepository) CountVotesByContests(ctx context.Context, contestIDs []
 // Реализуем остальные методы интерфейса Repository (заглушки)
 func (m *mockRepository) CreateUser(ctx context.Context, name string) (*model.User, error) { return nil, nil }
 func (m *mockRepository) CreateUserFromProvider(ctx context.Context, userData *model.UserProfileFromProvider) (*model.User, error) { return nil, nil }
-func (m *mockRepository) GetUser(ctx context.Context, userID model.UserID) (*model.User, error) { return nil, nil }
-func (m *mockRepository) UpdateUserName(ctx context.Context, userID model.UserID, name string) (*model.User, error) { return nil, nil }
+func (m *mockRepository) GetUser(ctx context.Context, userID model.UserID) (*model.User, error) {
+	if m.users != nil {
+		if user, ok := m.users[userID]; ok {
+			return user, nil
+		}
+		return nil, model.ErrorNotFound
+	}
+	return nil, nil
+}
+func (m *mockRepository) UpdateUserProfile(ctx context.Context, userID model.UserID, name, bio string, locale model.Locale) (*model.User, error) {
+	user := m.users[userID]
+	user.Name, user.Bio, user.Locale = name, bio, locale
+	return user, nil
+}
+func (m *mockRepository) SetUserAvatar(ctx context.Context, userID model.UserID, avatarURL, thumbURL *string) (*model.User, error) {
+	user := m.users[userID]
+	user.AvatarURL, user.AvatarThumbURL = "", ""
+	if avatarURL != nil {
+		user.AvatarURL = *avatarURL
+	}
+	if thumbURL != nil {
+		user.AvatarThumbURL = *thumbURL
+	}
+	return user, nil
+}
 func (m *mockRepository) GetUserAuthProvidersByProviderUid(ctx context.Context, providerUID, provider string) (*model.UserAuthProvider, error) { return nil, nil }
 func (m *mockRepository) AddUserAuthProviders(ctx context.Context, userData *model.UserProfileFromProvider, userID model.UserID) (*model.UserAuthProvider, error) { return nil, nil }
-func (m *mockRepository) GetUserAuthProvidersByUserID(ctx context.Context, userID model.UserID) ([]*model.UserAuthProvider, error) { return nil, nil }
+func (m *mockRepository) GetUserAuthProvidersByUserID(ctx context.Context, userID model.UserID) ([]*model.UserAuthProvider, error) { return m.authProviders[userID], nil }
 func (m *mockRepository) SetUserAvatarIfEmpty(ctx context.Context, userID model.UserID, avatarURL *string) error { return nil }
+func (m *mockRepository) UpdateUserAuthProviderAvatar(ctx context.Context, providerUID, provider string, avatarURL *string) error { return nil }
+func (m *mockRepository) GetLatestProviderAvatar(ctx context.Context, userID model.UserID) (string, error) {
+	for _, provider := range m.authProviders[userID] {
+		if provider.AvatarURL != nil {
+			return *provider.AvatarURL, nil
+		}
+	}
+	return "", model.ErrorNotFound
+}
+func (m *mockRepository) GetUserPrivacySettings(ctx context.Context, userID model.UserID) (*model.PrivacySettings, error) {
+	if m.privacy == nil {
+		return nil, model.ErrorNotFound
+	}
+	return m.privacy, nil
+}
+func (m *mockRepository) UpsertUserPrivacySettings(ctx context.Context, userID model.UserID, settings *model.PrivacySettings) (*model.PrivacySettings, error) {
+	m.privacy = settings
+	return settings, nil
+}
+func (m *mockRepository) CreateAccountDeletionJob(ctx context.Context, userID model.UserID) (*model.AccountDeletionJob, error) {
+	now := time.Now()
+	if user, ok := m.users[userID]; ok && user.DeletedAt == nil {
+		user.DeletedAt = &now
+	}
+	for _, job := range m.deletionJobs {
+		if job.UserID == userID && (job.Status == model.AccountDeletionPending || job.Status == model.AccountDeletionRunning) {
+			return job, nil
+		}
+	}
+	job := &model.AccountDeletionJob{ID: fmt.Sprintf("job-%d", len(m.deletionJobs)+1), UserID: userID, Status: model.AccountDeletionPending, CreatedAt: now}
+	m.deletionJobs = append(m.deletionJobs, job)
+	return job, nil
+}
+func (m *mockRepository) GetAccountDeletionJob(ctx context.Context, jobID string) (*model.AccountDeletionJob, error) {
+	for _, job := range m.deletionJobs {
+		if job.ID == jobID {
+			return job, nil
+		}
+	}
+	return nil, model.ErrorNotFound
+}
+func (m *mockRepository) ClaimAccountDeletionJob(ctx context.Context, staleBefore time.Time) (*model.AccountDeletionJob, error) {
+	for _, job := range m.deletionJobs {
+		ready := job.Status == model.AccountDeletionPending || job.Status == model.AccountDeletionRunning
+		if ready && (job.StartedAt == nil || job.StartedAt.Before(staleBefore)) {
+			now := time.Now()
+			job.Status, job.StartedAt = model.AccountDeletionRunning, &now
+			job.Attempts++
+			return job, nil
+		}
+	}
+	return nil, model.ErrorNotFound
+}
+func (m *mockRepository) FinishAccountDeletionJob(ctx context.Context, jobID string, status model.AccountDeletionStatus, lastError string) error {
+	job, err := m.GetAccountDeletionJob(ctx, jobID)
+	if err != nil {
+		return err
+	}
+	job.Status, job.LastError = status, lastError
+	return nil
+}
+func (m *mockRepository) DeleteUserPersonalData(ctx context.Context, userID model.UserID, name string) error {
+	if user, ok := m.users[userID]; ok {
+		user.Name, user.AvatarURL, user.AvatarThumbURL, user.Bio = name, "", "", ""
+	}
+	for id, pet := range m.pets {
+		if pet.OwnerUserID == userID {
+			delete(m.pets, id)
+		}
+	}
+	delete(m.authProviders, userID)
+	m.deletedUserData = append(m.deletedUserData, userID)
+	return nil
+}
+func (m *mockRepository) ListContestsByCreator(ctx context.Context, userID model.UserID) ([]*model.Contest, error) { return m.creatorContests, nil }
+func (m *mockRepository) ListParticipantsByUser(ctx context.Context, userID model.UserID) ([]*model.Participant, error) {
+	var participants []*model.Participant
+	for _, participant := range m.participants {
+		if participant.UserID == userID {
+			participants = append(participants, participant)
+		}
+	}
+	sort.Slice(participants, func(i, j int) bool { return participants[i].ID < participants[j].ID })
+	return participants, nil
+}
+func (m *mockRepository) ListContestVotesByUser(ctx context.Context, userID model.UserID) ([]*model.Vote, error) {
+	var votes []*model.Vote
+	for _, vote := range m.participantVotes {
+		if vote.UserID == userID {
+			votes = append(votes, vote)
+		}
+	}
+	return votes, nil
+}
+func (m *mockRepository) ListCommentsByUser(ctx context.Context, userID model.UserID) ([]*model.Comment, error) {
+	var comments []*model.Comment
+	for _, comment := range m.comments {
+		if comment.UserID == userID {
+			comments = append(comments, comment)
+		}
+	}
+	return comments, nil
+}
+func (m *mockRepository) ListChatMessagesByUser(ctx context.Context, userID model.UserID) ([]*model.ChatMessage, error) {
+	var messages []*model.ChatMessage
+	for _, message := range m.chatMessages {
+		if message.UserID == userID {
+			messages = append(messages, message)
+		}
+	}
+	return messages, nil
+}
+func (m *mockRepository) ListParticipantCommenters(ctx context.Context, participantID model.ParticipantID) ([]model.UserID, error) {
+	var userIDs []model.UserID
+	for _, comment := range m.comments {
+		if comment.ParticipantID == participantID {
+			userIDs = append(userIDs, comment.UserID)
+		}
+	}
+	return userIDs, nil
+}
+func (m *mockRepository) CreateNotifications(ctx context.Context, userIDs []model.UserID, notification *model.Notification) ([]*model.Notification, error) {
+	var created []*model.Notification
+	for _, userID := range userIDs {
+		n := *notification
+		n.ID, n.UserID, n.CreatedAt = fmt.Sprintf("n-%d", len(m.notifications)+1), userID, time.Now()
+		m.notifications = append(m.notifications, &n)
+		created = append(created, &n)
+	}
+	return created, nil
+}
+func (m *mockRepository) ListNotifications(ctx context.Context, userID model.UserID, unreadOnly bool, limit, offset int) ([]*model.Notification, error) {
+	var result []*model.Notification
+	for _, n := range m.notifications {
+		if n.UserID == userID && (!unreadOnly || n.ReadAt == nil) {
+			result = append(result, n)
+		}
+	}
+	return result, nil
+}
+func (m *mockRepository) CountNotifications(ctx context.Context, userID model.UserID) (int64, int64, error) {
+	var total, unread int64
+	for _, n := range m.notifications {
+		if n.UserID == userID {
+			total++
+			if n.ReadAt == nil {
+				unread++
+			}
+		}
+	}
+	return total, unread, nil
+}
+func (m *mockRepository) MarkNotificationRead(ctx context.Context, userID model.UserID, notificationID string) error {
+	for _, n := range m.notifications {
+		if n.ID == notificationID && n.UserID == userID {
+			now := time.Now()
+			n.ReadAt = &now
+			return nil
+		}
+	}
+	return model.ErrorNotFound
+}
+func (m *mockRepository) MarkAllNotificationsRead(ctx context.Context, userID model.UserID) (int64, error) {
+	var count int64
+	for _, n := range m.notifications {
+		if n.UserID == userID && n.ReadAt == nil {
+			now := time.Now()
+			n.ReadAt = &now
+			count++
+		}
+	}
+	return count, nil
+}
+func (m *mockRepository) GetNotificationPreferences(ctx context.Context, userID model.UserID) (*model.NotificationPreferences, error) {
+	prefs, ok := m.notificationPrefs[userID]
+	if !ok {
+		return nil, model.ErrorNotFound
+	}
+	copied := *prefs
+	return &copied, nil
+}
+func (m *mockRepository) UpsertNotificationPreferences(ctx context.Context, userID model.UserID, prefs *model.NotificationPreferences) (*model.NotificationPreferences, error) {
+	if m.notificationPrefs == nil {
+		m.notificationPrefs = make(map[model.UserID]*model.NotificationPreferences)
+	}
+	saved := &model.NotificationPreferences{EmailVotingStarted: prefs.EmailVotingStarted, EmailResultsPublished: prefs.EmailResultsPublished}
+	if existing, ok := m.notificationPrefs[userID]; ok {
+		saved.Email = existing.Email
+	}
+	m.notificationPrefs[userID] = saved
+	copied := *saved
+	return &copied, nil
+}
+func (m *mockRepository) SetNotificationEmailIfEmpty(ctx context.Context, userID model.UserID, email string) error {
+	if m.notificationPrefs == nil {
+		m.notificationPrefs = make(map[model.UserID]*model.NotificationPreferences)
+	}
+	prefs, ok := m.notificationPrefs[userID]
+	if !ok {
+		m.notificationPrefs[userID] = &model.NotificationPreferences{Email: email, EmailVotingStarted: true, EmailResultsPublished: true}
+	} else if prefs.Email == "" {
+		prefs.Email = email
+	}
+	return nil
+}
+func (m *mockRepository) ListEmailRecipients(ctx context.Context, userIDs []model.UserID, notificationType model.NotificationType) ([]*model.EmailRecipient, error) {
+	var result []*model.EmailRecipient
+	for _, userID := range userIDs {
+		prefs, ok := m.notificationPrefs[userID]
+		if !ok || prefs.Email == "" {
+			continue
+		}
+		if (notificationType == model.NotificationVotingStarted && !prefs.EmailVotingStarted) ||
+			(notificationType == model.NotificationResultsPublished && !prefs.EmailResultsPublished) {
+			continue
+		}
+		locale := model.LocaleRU
+		if user, ok := m.users[userID]; ok {
+			if user.DeletedAt != nil {
+				continue
+			}
+			if user.Locale != "" {
+				locale = user.Locale
+			}
+		}
+		result = append(result, &model.EmailRecipient{UserID: userID, Email: prefs.Email, Locale: locale})
+	}
+	return result, nil
+}
+func (m *mockRepository) EnqueueEmails(ctx context.Context, emails []*model.OutboxEmail) error {
+	for _, email := range emails {
+		queued := *email
+		queued.ID = fmt.Sprintf("e-%d", len(m.outbox)+1)
+		m.outbox = append(m.outbox, &mockOutboxEmail{OutboxEmail: &queued, status: model.OutboxPending})
+	}
+	return nil
+}
+func (m *mockRepository) ClaimEmailOutbox(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*model.OutboxEmail, error) {
+	var claimed []*model.OutboxEmail
+	for _, email := range m.outbox {
+		if len(claimed) == limit {
+			break
+		}
+		if email.status != model.OutboxPending || email.nextAttemptAt.After(now) {
+			continue
+		}
+		email.Attempts++
+		email.nextAttemptAt = leaseUntil
+		copied := *email.OutboxEmail
+		claimed = append(claimed, &copied)
+	}
+	return claimed, nil
+}
+func (m *mockRepository) MarkEmailSent(ctx context.Context, emailID string) error {
+	for _, email := range m.outbox {
+		if email.ID == emailID {
+			email.status, email.lastError = model.OutboxSent, ""
+			return nil
+		}
+	}
+	return model.ErrorNotFound
+}
+func (m *mockRepository) FailEmailAttempt(ctx context.Context, emailID string, status model.OutboxStatus, lastError string, nextAttemptAt time.Time) error {
+	for _, email := range m.outbox {
+		if email.ID == emailID {
+			email.status, email.lastError, email.nextAttemptAt = status, lastError, nextAttemptAt
+			return nil
+		}
+	}
+	return model.ErrorNotFound
+}
+func (m *mockRepository) CreateTelegramLinkToken(ctx context.Context, tokenHash string, userID model.UserID, expiresAt time.Time) error {
+	if m.telegramLinkTokens == nil {
+		m.telegramLinkTokens = make(map[string]model.UserID)
+	}
+	m.telegramLinkTokens[tokenHash] = userID
+	return nil
+}
+func (m *mockRepository) ConsumeTelegramLinkToken(ctx context.Context, tokenHash string) (model.UserID, error) {
+	userID, ok := m.telegramLinkTokens[tokenHash]
+	if !ok {
+		return 0, model.ErrorNotFound
+	}
+	delete(m.telegramLinkTokens, tokenHash)
+	return userID, nil
+}
+func (m *mockRepository) LinkTelegramAccount(ctx context.Context, userID model.UserID, chatID int64, username string) (*model.TelegramAccount, error) {
+	if m.telegramAccounts == nil {
+		m.telegramAccounts = make(map[model.UserID]*model.TelegramAccount)
+	}
+	for otherID, account := range m.telegramAccounts {
+		if account.ChatID == chatID && otherID != userID {
+			delete(m.telegramAccounts, otherID)
+		}
+	}
+	account := &model.TelegramAccount{UserID: userID, ChatID: chatID, Username: username, LinkedAt: time.Now()}
+	m.telegramAccounts[userID] = account
+	return account, nil
+}
+func (m *mockRepository) GetTelegramAccount(ctx context.Context, userID model.UserID) (*model.TelegramAccount, error) {
+	account, ok := m.telegramAccounts[userID]
+	if !ok {
+		return nil, model.ErrorNotFound
+	}
+	return account, nil
+}
+func (m *mockRepository) GetTelegramAccountByChat(ctx context.Context, chatID int64) (*model.TelegramAccount, error) {
+	for _, account := range m.telegramAccounts {
+		if account.ChatID == chatID {
+			return account, nil
+		}
+	}
+	return nil, model.ErrorNotFound
+}
+func (m *mockRepository) DeleteTelegramAccount(ctx context.Context, userID model.UserID) (int64, error) {
+	if _, ok := m.telegramAccounts[userID]; !ok {
+		return 0, nil
+	}
+	delete(m.telegramAccounts, userID)
+	return 1, nil
+}
+func (m *mockRepository) DeleteTelegramChat(ctx context.Context, chatID int64) error {
+	delete(m.telegramSubscriptions, chatID)
+	for userID, account := range m.telegramAccounts {
+		if account.ChatID == chatID {
+			delete(m.telegramAccounts, userID)
+		}
+	}
+	return nil
+}
+func (m *mockRepository) SubscribeTelegramChat(ctx context.Context, chatID int64, contestID model.ContestID) error {
+	if m.telegramSubscriptions == nil {
+		m.telegramSubscriptions = make(map[int64][]model.ContestID)
+	}
+	if !slices.Contains(m.telegramSubscriptions[chatID], contestID) {
+		m.telegramSubscriptions[chatID] = append(m.telegramSubscriptions[chatID], contestID)
+	}
+	return nil
+}
+func (m *mockRepository) UnsubscribeTelegramChat(ctx context.Context, chatID int64, contestID model.ContestID) (int64, error) {
+	contestIDs := m.telegramSubscriptions[chatID]
+	i := slices.Index(contestIDs, contestID)
+	if i < 0 {
+		return 0, nil
+	}
+	m.telegramSubscriptions[chatID] = slices.Delete(contestIDs, i, i+1)
+	return 1, nil
+}
+func (m *mockRepository) ListTelegramSubscriptions(ctx context.Context, chatID int64) ([]*model.TelegramSubscription, error) {
+	var result []*model.TelegramSubscription
+	for _, contestID := range m.telegramSubscriptions[chatID] {
+		result = append(result, &model.TelegramSubscription{ContestID: contestID})
+	}
+	return result, nil
+}
+func (m *mockRepository) ListTelegramContestChats(ctx context.Context, contestID model.ContestID) ([]int64, error) {
+	var chatIDs []int64
+	for chatID, contestIDs := range m.telegramSubscriptions {
+		if slices.Contains(contestIDs, contestID) {
+			chatIDs = append(chatIDs, chatID)
+		}
+	}
+	for _, participant := range m.contestParticipants {
+		account, ok := m.telegramAccounts[participant.UserID]
+		if ok && participant.ModerationStatus != model.EntryModerationRejected && !slices.Contains(chatIDs, account.ChatID) {
+			chatIDs = append(chatIDs, account.ChatID)
+		}
+	}
+	slices.Sort(chatIDs)
+	return chatIDs, nil
+}
+func (m *mockRepository) EnqueueTelegramMessages(ctx context.Context, messages []*model.TelegramMessage) error {
+	for _, message := range messages {
+		queued := *message
+		queued.ID = fmt.Sprintf("t-%d", len(m.telegramOutbox)+1)
+		m.telegramOutbox = append(m.telegramOutbox, &mockTelegramMessage{TelegramMessage: &queued, status: model.OutboxPending})
+	}
+	return nil
+}
+func (m *mockRepository) ClaimTelegramOutbox(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*model.TelegramMessage, error) {
+	var claimed []*model.TelegramMessage
+	for _, message := range m.telegramOutbox {
+		if len(claimed) == limit {
+			break
+		}
+		if message.status != model.OutboxPending || message.nextAttemptAt.After(now) {
+			continue
+		}
+		message.Attempts++
+		message.nextAttemptAt = leaseUntil
+		copied := *message.TelegramMessage
+		claimed = append(claimed, &copied)
+	}
+	return claimed, nil
+}
+func (m *mockRepository) MarkTelegramMessageSent(ctx context.Context, messageID string) error {
+	for _, message := range m.telegramOutbox {
+		if message.ID == messageID {
+			message.status, message.lastError = model.OutboxSent, ""
+			return nil
+		}
+	}
+	return model.ErrorNotFound
+}
+func (m *mockRepository) FailTelegramMessageAttempt(ctx context.Context, messageID string, status model.OutboxStatus, lastError string, nextAttemptAt time.Time) error {
+	for _, message := range m.telegramOutbox {
+		if message.ID == messageID {
+			message.status, message.lastError, message.nextAttemptAt = status, lastError, nextAttemptAt
+			return nil
+		}
+	}
+	return model.ErrorNotFound
+}
+func (m *mockRepository) CreateTelegramAnnouncement(ctx context.Context, announcement *model.TelegramAnnouncement, cooldown time.Duration) (*model.TelegramAnnouncement, error) {
+	for _, previous := range m.telegramAnnouncements {
+		if previous.ContestID == announcement.ContestID && time.Since(previous.CreatedAt) < cooldown {
+			return nil, model.ErrTooManyRequests
+		}
+	}
+	if err := m.EnqueueTelegramMessages(ctx, []*model.TelegramMessage{{ChatID: announcement.ChatID, Text: announcement.Text}}); err != nil {
+		return nil, err
+	}
+	created := *announcement
+	created.ID = fmt.Sprintf("a-%d", len(m.telegramAnnouncements)+1)
+	created.Status = model.OutboxPending
+	created.CreatedAt = time.Now()
+	m.telegramAnnouncements = append([]*model.TelegramAnnouncement{&created}, m.telegramAnnouncements...)
+	return &created, nil
+}
+func (m *mockRepository) ListTelegramAnnouncements(ctx context.Context, contestID model.ContestID, limit int) ([]*model.TelegramAnnouncement, error) {
+	var result []*model.TelegramAnnouncement
+	for _, announcement := range m.telegramAnnouncements {
+		if announcement.ContestID == contestID && len(result) < limit {
+			result = append(result, announcement)
+		}
+	}
+	return result, nil
+}
+func (m *mockRepository) CreateWebhookEndpoint(ctx context.Context, endpoint *model.WebhookEndpoint) (*model.WebhookEndpoint, error) {
+	created := *endpoint
+	created.ID = fmt.Sprintf("w-%d", len(m.webhookEndpoints)+1)
+	created.Active = true
+	created.CreatedAt = time.Now()
+	created.UpdatedAt = created.CreatedAt
+	m.webhookEndpoints = append(m.webhookEndpoints, &created)
+	copied := created
+	return &copied, nil
+}
+func (m *mockRepository) GetWebhookEndpoint(ctx context.Context, endpointID string) (*model.WebhookEndpoint, error) {
+	for _, endpoint := range m.webhookEndpoints {
+		if endpoint.ID == endpointID {
+			copied := *endpoint
+			return &copied, nil
+		}
+	}
+	return nil, model.ErrorNotFound
+}
+func (m *mockRepository) ListWebhookEndpoints(ctx context.Context, contestID model.ContestID) ([]*model.WebhookEndpoint, error) {
+	var result []*model.WebhookEndpoint
+	for _, endpoint := range m.webhookEndpoints {
+		if endpoint.ContestID == contestID {
+			copied := *endpoint
+			result = append(result, &copied)
+		}
+	}
+	return result, nil
+}
+func (m *mockRepository) UpdateWebhookEndpoint(ctx context.Context, endpoint *model.WebhookEndpoint) (*model.WebhookEndpoint, error) {
+	for i, existing := range m.webhookEndpoints {
+		if existing.ID == endpoint.ID {
+			updated := *endpoint
+			updated.UpdatedAt = time.Now()
+			m.webhookEndpoints[i] = &updated
+			copied := updated
+			return &copied, nil
+		}
+	}
+	return nil, model.ErrorNotFound
+}
+func (m *mockRepository) DeleteWebhookEndpoint(ctx context.Context, endpointID string) error {
+	for i, endpoint := range m.webhookEndpoints {
+		if endpoint.ID == endpointID {
+			m.webhookEndpoints = append(m.webhookEndpoints[:i], m.webhookEndpoints[i+1:]...)
+			return nil
+		}
+	}
+	return model.ErrorNotFound
+}
+func (m *mockRepository) EnqueueWebhookEvent(ctx context.Context, contestID model.ContestID, eventType model.WebhookEventType, data []byte) (int64, error) {
+	var targets []*model.WebhookEndpoint
+	for _, endpoint := range m.webhookEndpoints {
+		if endpoint.ContestID == contestID && endpoint.Active && (len(endpoint.EventTypes) == 0 || slices.Contains(endpoint.EventTypes, eventType)) {
+			targets = append(targets, endpoint)
+		}
+	}
+	if len(targets) == 0 {
+		return 0, nil
+	}
+	if m.webhookEvents == nil {
+		m.webhookEvents = make(map[string]*model.WebhookEvent)
+	}
+	event := &model.WebhookEvent{ID: fmt.Sprintf("e-%d", len(m.webhookEvents)+1), Type: eventType, ContestID: contestID, CreatedAt: time.Now(), Data: data}
+	m.webhookEvents[event.ID] = event
+	for _, endpoint := range targets {
+		m.webhookDeliveries = append(m.webhookDeliveries, &model.WebhookDelivery{
+			ID: fmt.Sprintf("d-%d", len(m.webhookDeliveries)+1), EndpointID: endpoint.ID, EventID: event.ID, EventType: eventType,
+			Status: model.OutboxPending, NextAttemptAt: &time.Time{}, CreatedAt: event.CreatedAt,
+		})
+	}
+	return int64(len(targets)), nil
+}
+func (m *mockRepository) ClaimWebhookDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*model.WebhookDeliveryJob, error) {
+	var claimed []*model.WebhookDeliveryJob
+	for _, delivery := range m.webhookDeliveries {
+		if len(claimed) >= limit || delivery.Status != model.OutboxPending || delivery.NextAttemptAt.After(now) {
+			continue
+		}
+		endpoint, err := m.GetWebhookEndpoint(ctx, delivery.EndpointID)
+		if err != nil || !endpoint.Active {
+			continue
+		}
+		delivery.Attempts++
+		lease := leaseUntil
+		delivery.NextAttemptAt = &lease
+		claimed = append(claimed, &model.WebhookDeliveryJob{ID: delivery.ID, Attempts: delivery.Attempts, URL: endpoint.URL, Secret: endpoint.Secret, Event: m.webhookEvents[delivery.EventID]})
+	}
+	return claimed, nil
+}
+func (m *mockRepository) FinishWebhookDeliveryAttempt(ctx context.Context, deliveryID string, status model.OutboxStatus, response *model.WebhookResponse, lastError string, nextAttemptAt time.Time) error {
+	for _, delivery := range m.webhookDeliveries {
+		if delivery.ID != deliveryID {
+			continue
+		}
+		now := time.Now()
+		delivery.Status, delivery.LastError, delivery.NextAttemptAt, delivery.LastAttemptAt = status, lastError, &nextAttemptAt, &now
+		delivery.ResponseStatus, delivery.ResponseBody = nil, ""
+		if response != nil {
+			statusCode := response.StatusCode
+			delivery.ResponseStatus, delivery.ResponseBody = &statusCode, response.Body
+		}
+		if status == model.OutboxSent {
+			delivery.DeliveredAt = &now
+		}
+		return nil
+	}
+	return model.ErrorNotFound
+}
+func (m *mockRepository) ListWebhookDeliveries(ctx context.Context, endpointID string, limit, offset int) ([]*model.WebhookDelivery, int64, error) {
+	var all []*model.WebhookDelivery
+	for i := len(m.webhookDeliveries) - 1; i >= 0; i-- {
+		if m.webhookDeliveries[i].EndpointID == endpointID {
+			all = append(all, m.webhookDeliveries[i])
+		}
+	}
+	total := int64(len(all))
+	if offset >= len(all) {
+		return nil, total, nil
+	}
+	return all[offset:min(offset+limit, len(all))], total, nil
+}
+func (m *mockRepository) GetWebhookDelivery(ctx context.Context, deliveryID string) (*model.WebhookDelivery, error) {
+	for _, delivery := range m.webhookDeliveries {
+		if delivery.ID == deliveryID {
+			copied := *delivery
+			return &copied, nil
+		}
+	}
+	return nil, model.ErrorNotFound
+}
+func (m *mockRepository) RedeliverWebhook(ctx context.Context, deliveryID string) (*model.WebhookDelivery, error) {
+	original, err := m.GetWebhookDelivery(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	redelivery := &model.WebhookDelivery{
+		ID: fmt.Sprintf("d-%d", len(m.webhookDeliveries)+1), EndpointID: original.EndpointID, EventID: original.EventID, EventType: original.EventType,
+		Redelivery: true, Status: model.OutboxPending, NextAttemptAt: &time.Time{}, CreatedAt: time.Now(),
+	}
+	m.webhookDeliveries = append(m.webhookDeliveries, redelivery)
+	copied := *redelivery
+	return &copied, nil
+}
+func (m *mockRepository) CreateEmailLoginToken(ctx context.Context, tokenHash, email string, expiresAt time.Time) error { return nil }
+func (m *mockRepository) ConsumeEmailLoginToken(ctx context.Context, tokenHash string) (string, error) { return "", model.ErrorNotFound }
+func (m *mockRepository) CountEmailLoginTokensSince(ctx context.Context, email string, since time.Time) (int64, error) { return 0, nil }
+func (m *mockRepository) CreateWSTicket(ctx context.Context, ticketHash string, userID model.UserID, expiresAt time.Time) error {
+	if m.wsTickets == nil {
+		m.wsTickets = make(map[string]*mockWSTicket)
+	}
+	m.wsTickets[ticketHash] = &mockWSTicket{userID: userID, expiresAt: expiresAt}
+	return nil
+}
+func (m *mockRepository) ConsumeWSTicket(ctx context.Context, ticketHash string) (model.UserID, error) {
+	ticket, ok := m.wsTickets[ticketHash]
+	delete(m.wsTickets, ticketHash)
+	if !ok || !time.Now().Before(ticket.expiresAt) {
+		return 0, model.ErrorNotFound
+	}
+	return ticket.userID, nil
+}
+func (m *mockRepository) DeleteExpiredWSTickets(ctx context.Context) error { return nil }
+func (m *mockRepository) ListUserRoles(ctx context.Context, userID model.UserID) ([]model.Role, error) { return m.userRoles[userID], nil }
+func (m *mockRepository) ListActiveUserRoles(ctx context.Context, userID model.UserID) ([]model.Role, error) {
+	if user, ok := m.users[userID]; ok && user.DeletedAt != nil {
+		return nil, model.ErrorNotFound
+	}
+	return m.userRoles[userID], nil
+}
+func (m *mockRepository) AddUserRole(ctx context.Context, userID model.UserID, role model.Role, grantedBy model.UserID) error { return nil }
+func (m *mockRepository) RemoveUserRole(ctx context.Context, userID model.UserID, role model.Role) error { return nil }
+func (m *mockRepository) CreateModerationAction(ctx context.Context, action *model.ModerationAction) (*model.ModerationAction, error) { m.moderationActions = append(m.moderationActions, action); return action, nil }
+func (m *mockRepository) ListModerationActions(ctx context.Context, limit, offset int) ([]*model.ModerationAction, int64, error) { return nil, 0, nil }
+func (m *mockRepository) ModerateContent(ctx context.Context, action *model.ModerationAction) (*model.ModerationAction, model.ContestID, error) {
+	if action.TargetType == model.ModerationTargetContest && action.Action == model.ModerationActionDelete && m.deleteContestFunc != nil {
+		if err := m.deleteContestFunc(ctx, model.ContestID(action.TargetID)); err != nil {
+			return nil, "", err
+		}
+	}
+	m.moderationActions = append(m.moderationActions, action)
+	return action, "", nil
+}
+func (m *mockRepository) DeleteChatMessageByID(ctx context.Context, messageID model.ChatMessageID) (model.ContestID, error) { return "", nil }
 // ListContests, UpdateContest, UpdateContestStatus, DeleteContest реализованы ниже с поддержкой моков
-func (m *mockRepository) CreateParticipant(ctx context.Context, contestID model.ContestID, userID model.UserID, petName, petDescription string) (*model.Participant, error) { return nil, nil }
-func (m *mockRepository) GetParticipant(ctx context.Context, participantID model.ParticipantID) (*model.Participant, error) { return nil, nil }
+func (m *mockRepository) CreateContestMember(ctx context.Context, member *model.ContestMember) (*model.ContestMember, error) { return member, nil }
+func (m *mockRepository) GetContestMember(ctx context.Context, contestID model.ContestID, userID model.UserID) (*model.ContestMember, error) {
+	if member, ok := m.contestMembers[userID]; ok {
+		return member, nil
+	}
+	return nil, model.ErrorNotFound
+}
+func (m *mockRepository) ListContestMembers(ctx context.Context, contestID model.ContestID) ([]*model.ContestMember, error) {
+	members := make([]*model.ContestMember, 0, len(m.contestMembers))
+	for _, member := range m.contestMembers {
+		members = append(members, member)
+	}
+	return members, nil
+}
+func (m *mockRepository) UpdateContestMemberRole(ctx context.Context, contestID model.ContestID, userID model.UserID, role model.ContestMemberRole) (*model.ContestMember, error) { return nil, nil }
+func (m *mockRepository) AcceptContestMemberInvite(ctx context.Context, contestID model.ContestID, userID model.UserID) (*model.ContestMember, error) { return nil, nil }
+func (m *mockRepository) DeleteContestMember(ctx context.Context, contestID model.ContestID, userID model.UserID) error { return nil }
+// TransferContestOwnership мока повторяет порядок репозитория и, как индекс idx_contest_members_owner,
+// не допускает двух владельцев ни на одном шаге
+func (m *mockRepository) TransferContestOwnership(ctx context.Context, contestID model.ContestID, previousOwnerID, newOwnerID model.UserID) error {
+	if previous, ok := m.contestMembers[previousOwnerID]; ok && previous.Role == model.ContestMemberOwner {
+		previous.Role = model.ContestMemberOrganizer
+	}
+	next, ok := m.contestMembers[newOwnerID]
+	if !ok || next.Status != model.ContestMemberActive {
+		return model.ErrorNotFound
+	}
+	for _, member := range m.contestMembers {
+		if member.Role == model.ContestMemberOwner {
+			return fmt.Errorf("duplicate key value violates unique constraint \"idx_contest_members_owner\"")
+		}
+	}
+	next.Role = model.ContestMemberOwner
+	m.transferredOwnerID = newOwnerID
+	return nil
+}
+func (m *mockRepository) GetChatMessageContestID(ctx context.Context, messageID model.ChatMessageID) (model.ContestID, error) { return "", nil }
+func (m *mockRepository) CreateParticipant(ctx context.Context, contestID model.ContestID, userID model.UserID, petID model.PetID, petName, petDescription string, attrs model.PetAttributes, status model.EntryModerationStatus, maxEntries, maxEntriesPerUser int) (*model.Participant, error) {
+	return &model.Participant{ID: "participant-id", ContestID: contestID, UserID: userID, PetID: petID, PetName: petName, PetDescription: petDescription, PetAttributes: attrs, ModerationStatus: status}, nil
+}
+func (m *mockRepository) GetParticipant(ctx context.Context, participantID model.ParticipantID) (*model.Participant, error) {
+	if m.participants != nil {
+		if participant, ok := m.participants[participantID]; ok {
+			return participant, nil
+		}
+		return nil, model.ErrorNotFound
+	}
+	return nil, nil
+}
 func (m *mockRepository) GetParticipantByContestAndUser(ctx context.Context, contestID model.ContestID, userID model.UserID) (*model.Participant, error) { return nil, nil }
-func (m *mockRepository) ListParticipantsByContest(ctx context.Context, contestID model.ContestID) ([]*model.Participant, error) { return nil, nil }
-func (m *mockRepository) UpdateParticipant(ctx context.Context, participantID model.ParticipantID, petName, petDescription string) (*model.Participant, error) { return nil, nil }
+func (m *mockRepository) ListParticipantsByContest(ctx context.Context, contestID model.ContestID) ([]*model.Participant, error) { return m.contestParticipants, nil }
+func (m *mockRepository) UpdateParticipant(ctx context.Context, participantID model.ParticipantID, petName, petDescription string, attrs model.PetAttributes) (*model.Participant, error) {
+	participant, ok := m.participants[participantID]
+	if !ok {
+		return nil, model.ErrorNotFound
+	}
+	participant.PetName, participant.PetDescription, participant.PetAttributes = petName, petDescription, attrs
+	return participant, nil
+}
 func (m *mockRepository) DeleteParticipant(ctx context.Context, participantID model.ParticipantID) error { return nil }
-func (m *mockRepository) AddParticipantPhoto(ctx context.Context, participantID model.ParticipantID, url string, thumbURL *string) (*model.Photo, error) { return nil, nil }
-func (m *mockRepository) GetPhotosByParticipantID(ctx context.Context, participantID model.ParticipantID) ([]*model.Photo, error) { return nil, nil }
+func (m *mockRepository) CountParticipantsByContest(ctx context.Context, contestID model.ContestID) (int64, error) { return m.participantCount, nil }
+func (m *mockRepository) CountParticipantsByContestAndUser(ctx context.Context, contestID model.ContestID, userID model.UserID) (int64, error) { return m.userParticipantCount, nil }
+func (m *mockRepository) ListParticipantsByModerationStatus(ctx context.Context, contestID model.ContestID, status model.EntryModerationStatus) ([]*model.Participant, error) {
+	var result []*model.Participant
+	for _, participant := range m.participants {
+		if participant.ContestID == contestID && participant.ModerationStatus == status {
+			result = append(result, participant)
+		}
+	}
+	return result, nil
+}
+func (m *mockRepository) SetParticipantModeration(ctx context.Context, participantID model.ParticipantID, status model.EntryModerationStatus, reason string, moderatorID model.UserID) error {
+	participant := m.participants[participantID]
+	participant.ModerationStatus = status
+	participant.ModerationReason = reason
+	return nil
+}
+func (m *mockRepository) DisqualifyParticipant(ctx context.Context, contestID model.ContestID, participantID model.ParticipantID, actorID model.UserID, reason string) ([]*model.Vote, bool, error) {
+	participant := m.participants[participantID]
+	if participant.DisqualifiedAt != nil {
+		return nil, false, nil
+	}
+	now := time.Now()
+	participant.DisqualifiedAt = &now
+	participant.DisqualificationReason = reason
+	voided := m.participantVotes
+	m.participantVotes = nil
+	return voided, true, nil
+}
+func (m *mockRepository) CreatePet(ctx context.Context, pet *model.Pet) (*model.Pet, error) {
+	if m.pets == nil {
+		m.pets = make(map[model.PetID]*model.Pet)
+	}
+	pet.ID = model.PetID(fmt.Sprintf("pet-%d", len(m.pets)+1))
+	m.pets[pet.ID] = pet
+	return pet, nil
+}
+func (m *mockRepository) GetPet(ctx context.Context, petID model.PetID) (*model.Pet, error) {
+	if pet, ok := m.pets[petID]; ok {
+		return pet, nil
+	}
+	return nil, model.ErrorNotFound
+}
+func (m *mockRepository) ListPetsByOwner(ctx context.Context, userID model.UserID) ([]*model.Pet, error) {
+	var pets []*model.Pet
+	for _, pet := range m.pets {
+		if pet.OwnerUserID == userID {
+			pets = append(pets, pet)
+		}
+	}
+	sort.Slice(pets, func(i, j int) bool { return pets[i].ID < pets[j].ID })
+	return pets, nil
+}
+func (m *mockRepository) UpdatePet(ctx context.Context, pet *model.Pet) (*model.Pet, error) {
+	m.pets[pet.ID] = pet
+	return pet, nil
+}
+func (m *mockRepository) DeletePet(ctx context.Context, petID model.PetID) error {
+	delete(m.pets, petID)
+	return nil
+}
+func (m *mockRepository) ListPetEntries(ctx context.Context, petID model.PetID) ([]*model.PetEntry, error) { return m.petEntries, nil }
+func (m *mockRepository) AddPetPhoto(ctx context.Context, petID model.PetID, url string, thumbURL *string) (*model.PetPhoto, error) {
+	return &model.PetPhoto{PetID: petID, URL: url}, nil
+}
+func (m *mockRepository) ListPetPhotos(ctx context.Context, petID model.PetID) ([]*model.PetPhoto, error) { return m.petPhotos, nil }
+func (m *mockRepository) CountPetPhotos(ctx context.Context, petID model.PetID) (int64, error) { return int64(len(m.petPhotos)), nil }
+func (m *mockRepository) DeletePetPhoto(ctx context.Context, petID model.PetID, photoID string) error { return nil }
+func (m *mockRepository) ListSpecies(ctx context.Context) ([]*model.Species, error) { return m.species, nil }
+func (m *mockRepository) ListBreeds(ctx context.Context, species string) ([]*model.Breed, error) { return m.breeds, nil }
+func (m *mockRepository) GetBreed(ctx context.Context, code string) (*model.Breed, error) {
+	for _, breed := range m.breeds {
+		if breed.Code == code {
+			return breed, nil
+		}
+	}
+	return nil, model.ErrorNotFound
+}
+func (m *mockRepository) AddParticipantPhoto(ctx context.Context, participantID model.ParticipantID, url string, thumbURL *string) (*model.Photo, error) {
+	m.photoCount++
+	return &model.Photo{ParticipantID: participantID, URL: url}, nil
+}
+func (m *mockRepository) CountPhotosByParticipant(ctx context.Context, participantID model.ParticipantID) (int64, error) { return m.photoCount, nil }
+func (m *mockRepository) GetPhotosByParticipantID(ctx context.Context, participantID model.ParticipantID) ([]*model.Photo, error) { return m.participantPhotos[participantID], nil }
 func (m *mockRepository) DeleteParticipantPhoto(ctx context.Context, participantID model.ParticipantID, photoID string) error { return nil }
 func (m *mockRepository) UpdateParticipantPhotoOrder(ctx context.Context, participantID model.ParticipantID, photoIDs []string) error { return nil }
-func (m *mockRepository) UpsertParticipantVideo(ctx context.Context, participantID model.ParticipantID, url string) (*model.Video, error) { return nil, nil }
+func (m *mockRepository) UpsertParticipantVideo(ctx context.Context, participantID model.ParticipantID, url string) (*model.Video, error) {
+	return &model.Video{ParticipantID: participantID, URL: url}, nil
+}
 func (m *mockRepository) GetVideoByParticipantID(ctx context.Context, participantID model.ParticipantID) (*model.Video, error) { return nil, nil }
 func (m *mockRepository) DeleteParticipantVideo(ctx context.Context, participantID model.ParticipantID) error { return nil }
-func (m *mockRepository) UpsertContestVote(ctx context.Context, contestID model.ContestID, participantID model.ParticipantID, userID model.UserID) (*model.Vote, error) { return nil, nil }
-func (m *mockRepository) GetContestVoteByUser(ctx context.Context, contestID model.ContestID, userID model.UserID) (*model.Vote, error) { return nil, nil }
-func (m *mockRepository) DeleteContestVoteByUser(ctx context.Context, contestID model.ContestID, userID model.UserID) (model.ParticipantID, error) { return "", nil }
-func (m *mockRepository) ListVotersByParticipant(ctx context.Context, contestID model.ContestID, participantID model.ParticipantID) ([]*model.VoterInfo, error) { return nil, nil }
+func (m *mockRepository) UpsertContestVote(ctx context.Context, contestID model.ContestID, categoryID model.CategoryID, participantID model.ParticipantID, userID model.UserID, meta *model.VoteMetadata, choices []model.BallotChoice) (*model.Vote, error) {
+	if len(choices) > 0 {
+		m.voteChoices = choices
+	}
+	return &model.Vote{ID: "vote-id", ContestID: contestID, CategoryID: categoryID, ParticipantID: participantID, UserID: userID}, nil
+}
+func (m *mockRepository) GetContestVoteByUser(ctx context.Context, contestID model.ContestID, categoryID model.CategoryID, userID model.UserID) (*model.Vote, error) {
+	if m.userVote != nil && m.userVote.CategoryID != categoryID {
+		return nil, nil
+	}
+	return m.userVote, nil
+}
+func (m *mockRepository) DeleteContestVoteByUser(ctx context.Context, contestID model.ContestID, categoryID model.CategoryID, userID model.UserID) (model.ParticipantID, error) { return "", nil }
+func (m *mockRepository) ListVotersByParticipant(ctx context.Context, contestID model.ContestID, categoryID model.CategoryID, participantID model.ParticipantID) ([]*model.VoterInfo, error) { return nil, nil }
+func (m *mockRepository) CreateContestCategory(ctx context.Context, category *model.ContestCategory) (*model.ContestCategory, error) {
+	category.ID = model.CategoryID(fmt.Sprintf("category-%d", len(m.categories)+1))
+	m.categories = append(m.categories, category)
+	return category, nil
+}
+func (m *mockRepository) GetContestCategory(ctx context.Context, contestID model.ContestID, categoryID model.CategoryID) (*model.ContestCategory, error) {
+	for _, category := range m.categories {
+		if category.ID == categoryID && category.ContestID == contestID {
+			return category, nil
+		}
+	}
+	return nil, model.ErrorNotFound
+}
+func (m *mockRepository) ListContestCategories(ctx context.Context, contestID model.ContestID) ([]*model.ContestCategory, error) { return m.categories, nil }
+func (m *mockRepository) UpdateContestCategory(ctx context.Context, category *model.ContestCategory) (*model.ContestCategory, error) { return category, nil }
+func (m *mockRepository) DeleteContestCategory(ctx context.Context, contestID model.ContestID, categoryID model.CategoryID) error { return nil }
+func (m *mockRepository) ListContestVoteChoices(ctx context.Context, voteID string) ([]model.BallotChoice, error) { return m.voteChoices, nil }
+func (m *mockRepository) CountVoteChoicesByParticipant(ctx context.Context, participantID model.ParticipantID, categoryID model.CategoryID) (int64, float64, error) { return 0, 0, nil }
+func (m *mockRepository) ListContestBallots(ctx context.Context, contestID model.ContestID, categoryID model.CategoryID) ([]*model.Ballot, error) { return m.ballots, nil }
+func (m *mockRepository) GetContestEntryRules(ctx context.Context, contestID model.ContestID) (*model.EntryRules, error) {
+	if m.entryRules == nil {
+		return nil, model.ErrorNotFound
+	}
+	return m.entryRules, nil
+}
+func (m *mockRepository) UpsertContestEntryRules(ctx context.Context, rules *model.EntryRules) (*model.EntryRules, error) {
+	m.entryRules = rules
+	return rules, nil
+}
+func (m *mockRepository) GetContestVotingPolicy(ctx context.Context, contestID model.ContestID) (*model.VotingPolicy, error) {
+	if m.votingPolicy == nil {
+		return nil, model.ErrorNotFound
+	}
+	return m.votingPolicy, nil
+}
+func (m *mockRepository) UpsertContestVotingPolicy(ctx context.Context, policy *model.VotingPolicy) (*model.VotingPolicy, error) {
+	m.votingPolicy = policy
+	return policy, nil
+}
+func (m *mockRepository) ListContestInvitedVoters(ctx context.Context, contestID model.ContestID) ([]*model.InvitedVoter, error) { return nil, nil }
+func (m *mockRepository) AddContestInvitedVoter(ctx context.Context, contestID model.ContestID, userID, addedByUserID model.UserID) error { return nil }
+func (m *mockRepository) DeleteContestInvitedVoter(ctx context.Context, contestID model.ContestID, userID model.UserID) error { return nil }
+func (m *mockRepository) IsContestInvitedVoter(ctx context.Context, contestID model.ContestID, userID model.UserID) (bool, error) { return m.invitedVoters[userID], nil }
+func (m *mockRepository) CreateJuryCriterion(ctx context.Context, criterion *model.JuryCriterion) (*model.JuryCriterion, error) {
+	m.juryCriteria = append(m.juryCriteria, criterion)
+	return criterion, nil
+}
+func (m *mockRepository) ListJuryCriteria(ctx context.Context, contestID model.ContestID) ([]*model.JuryCriterion, error) { return m.juryCriteria, nil }
+func (m *mockRepository) DeleteJuryCriterion(ctx context.Context, contestID model.ContestID, criterionID string) error { return nil }
+func (m *mockRepository) UpsertJuryScores(ctx context.Context, contestID model.ContestID, participantID model.ParticipantID, jurorID model.UserID, scores []*model.JuryScore) error {
+	for _, score := range scores {
+		m.juryScores = append(m.juryScores, &model.JuryScore{CriterionID: score.CriterionID, ParticipantID: participantID, JurorID: jurorID, Score: score.Score})
+	}
+	return nil
+}
+func (m *mockRepository) ListJuryScores(ctx context.Context, contestID model.ContestID) ([]*model.JuryScore, error) {
+	return append([]*model.JuryScore(nil), m.juryScores...), nil
+}
+func (m *mockRepository) GetContestBracket(ctx context.Context, contestID model.ContestID) (*model.Bracket, error) {
+	if m.bracket == nil {
+		return nil, model.ErrorNotFound
+	}
+	bracket := *m.bracket
+	return &bracket, nil
+}
+func (m *mockRepository) UpsertContestBracket(ctx context.Context, bracket *model.Bracket) (*model.Bracket, error) {
+	if m.bracket == nil {
+		m.bracket = &model.Bracket{ContestID: bracket.ContestID, Status: model.BracketStatusPending}
+	}
+	m.bracket.Seeding = bracket.Seeding
+	m.bracket.RoundDurationMinutes = bracket.RoundDurationMinutes
+	return m.GetContestBracket(ctx, bracket.ContestID)
+}
+func (m *mockRepository) StartContestBracket(ctx context.Context, round *model.BracketRound) (bool, error) {
+	if m.bracket == nil || m.bracket.Status != model.BracketStatusPending {
+		return false, nil
+	}
+	m.bracket.Status, m.bracket.CurrentRound = model.BracketStatusRunning, 1
+	m.createBracketRound(round)
+	return true, nil
+}
+func (m *mockRepository) FinishBracketRound(ctx context.Context, round *model.BracketRound, decided []*model.BracketMatchup, next *model.BracketRound, championID model.ParticipantID) (bool, error) {
+	if finished, _ := m.SetBracketRoundStatus(ctx, round.ContestID, round.Round, model.BracketRoundActive, model.BracketRoundFinished); !finished {
+		return false, nil
+	}
+	for _, matchup := range decided {
+		m.decideBracketMatchup(matchup.ID, matchup.WinnerID)
+	}
+	if next == nil {
+		m.bracket.Status, m.bracket.CurrentRound, m.bracket.ChampionID = model.BracketStatusFinished, round.Round, championID
+		return true, nil
+	}
+	m.createBracketRound(next)
+	m.bracket.Status, m.bracket.CurrentRound, m.bracket.ChampionID = model.BracketStatusRunning, next.Round, ""
+	return true, nil
+}
+func (m *mockRepository) createBracketRound(round *model.BracketRound) {
+	stored := *round
+	stored.Matchups = nil
+	m.bracketRounds = append(m.bracketRounds, &stored)
+	for _, matchup := range round.Matchups {
+		matchup.ID = fmt.Sprintf("matchup-%d", len(m.bracketMatchups)+1)
+		saved := *matchup
+		if saved.WinnerID != "" {
+			decidedAt := round.StartsAt
+			saved.DecidedAt = &decidedAt
+		}
+		m.bracketMatchups = append(m.bracketMatchups, &saved)
+	}
+}
+func (m *mockRepository) ListBracketRounds(ctx context.Context, contestID model.ContestID) ([]*model.BracketRound, error) {
+	rounds := make([]*model.BracketRound, 0, len(m.bracketRounds))
+	for _, round := range m.bracketRounds {
+		copied := *round
+		rounds = append(rounds, &copied)
+	}
+	return rounds, nil
+}
+func (m *mockRepository) ListDueBracketRounds(ctx context.Context, now time.Time) ([]*model.BracketRound, error) {
+	var rounds []*model.BracketRound
+	for _, round := range m.bracketRounds {
+		if (round.Status == model.BracketRoundScheduled && !round.StartsAt.After(now)) || (round.Status == model.BracketRoundActive && !round.EndsAt.After(now)) {
+			copied := *round
+			rounds = append(rounds, &copied)
+		}
+	}
+	return rounds, nil
+}
+func (m *mockRepository) SetBracketRoundStatus(ctx context.Context, contestID model.ContestID, round int, from, to model.BracketRoundStatus) (bool, error) {
+	for _, stored := range m.bracketRounds {
+		if stored.Round == round && stored.Status == from {
+			stored.Status = to
+			return true, nil
+		}
+	}
+	return false, nil
+}
+func (m *mockRepository) ListBracketMatchups(ctx context.Context, contestID model.ContestID) ([]*model.BracketMatchup, error) {
+	matchups := make([]*model.BracketMatchup, 0, len(m.bracketMatchups))
+	for _, matchup := range m.bracketMatchups {
+		matchups = append(matchups, m.countedMatchup(matchup))
+	}
+	return matchups, nil
+}
+func (m *mockRepository) GetBracketMatchup(ctx context.Context, contestID model.ContestID, matchupID string) (*model.BracketMatchup, error) {
+	for _, matchup := range m.bracketMatchups {
+		if matchup.ID == matchupID {
+			return m.countedMatchup(matchup), nil
+		}
+	}
+	return nil, model.ErrorNotFound
+}
+func (m *mockRepository) countedMatchup(matchup *model.BracketMatchup) *model.BracketMatchup {
+	copied := *matchup
+	for _, participantID := range m.bracketVotes[matchup.ID] {
+		switch participantID {
+		case copied.ParticipantAID:
+			copied.VotesA++
+		case copied.ParticipantBID:
+			copied.VotesB++
+		}
+	}
+	return &copied
+}
+func (m *mockRepository) decideBracketMatchup(matchupID string, winnerID model.ParticipantID) {
+	for _, matchup := range m.bracketMatchups {
+		if matchup.ID == matchupID && matchup.DecidedAt == nil {
+			now := time.Now()
+			matchup.WinnerID, matchup.DecidedAt = winnerID, &now
+		}
+	}
+}
+func (m *mockRepository) ForfeitBracketMatchups(ctx context.Context, contestID model.ContestID, participantID model.ParticipantID) ([]string, error) {
+	var ids []string
+	for _, matchup := range m.bracketMatchups {
+		if matchup.DecidedAt != nil || (matchup.ParticipantAID != participantID && matchup.ParticipantBID != participantID) {
+			continue
+		}
+		winnerID := matchup.ParticipantAID
+		if winnerID == participantID {
+			winnerID = matchup.ParticipantBID
+		}
+		m.decideBracketMatchup(matchup.ID, winnerID)
+		ids = append(ids, matchup.ID)
+	}
+	return ids, nil
+}
+func (m *mockRepository) UpsertBracketVote(ctx context.Context, matchupID string, userID model.UserID, participantID model.ParticipantID) error {
+	if m.bracketVotes == nil {
+		m.bracketVotes = make(map[string]map[model.UserID]model.ParticipantID)
+	}
+	if m.bracketVotes[matchupID] == nil {
+		m.bracketVotes[matchupID] = make(map[model.UserID]model.ParticipantID)
+	}
+	m.bracketVotes[matchupID][userID] = participantID
+	return nil
+}
+func (m *mockRepository) ListBracketVotesByUser(ctx context.Context, contestID model.ContestID, userID model.UserID) (map[string]model.ParticipantID, error) {
+	votes := make(map[string]model.ParticipantID)
+	for matchupID, byUser := range m.bracketVotes {
+		if participantID, ok := byUser[userID]; ok {
+			votes[matchupID] = participantID
+		}
+	}
+	return votes, nil
+}
+func (m *mockRepository) ListContestIDsWithVotesSince(ctx context.Context, since time.Time) ([]model.ContestID, error) { return nil, nil }
+func (m *mockRepository) ListContestVotesForFraudScoring(ctx context.Context, contestID model.ContestID) ([]*model.VoteAudit, error) { return nil, nil }
+func (m *mockRepository) UpdateContestVoteFraudScore(ctx context.Context, voteID string, score int, reasons []string, flagged bool) error { return nil }
+func (m *mockRepository) ListFlaggedContestVotes(ctx context.Context, contestID model.ContestID) ([]*model.VoteAudit, error) { return nil, nil }
+func (m *mockRepository) VoidContestVotes(ctx context.Context, contestID model.ContestID, voteIDs []string, actorID model.UserID, reason string) ([]*model.Vote, error) {
+	m.voidedVoteIDs = append(m.voidedVoteIDs, voteIDs...)
+	votes := make([]*model.Vote, 0, len(voteIDs))
+	for _, voteID := range voteIDs {
+		votes = append(votes, &model.Vote{ID: voteID, ContestID: contestID, ParticipantID: "participant-id", UserID: 10})
+	}
+	return votes, nil
+}
 // CountVotesByContest, CountVotesByContests реализованы ниже с поддержкой моков
-func (m *mockRepository) CountVotesByParticipant(ctx context.Context, participantID model.ParticipantID) (int64, error) { return 0, nil }
-func (m *mockRepository) CreateComment(ctx context.Context, participantID model.ParticipantID, userID model.UserID, text string) (*model.Comment, error) { return nil, nil }
+func (m *mockRepository) CountVotesByParticipant(ctx context.Context, participantID model.ParticipantID, categoryID model.CategoryID) (int64, error) { return 0, nil }
+func (m *mockRepository) CreateComment(ctx context.Context, participantID model.ParticipantID, userID model.UserID, text string) (*model.Comment, error) {
+	comment := &model.Comment{ID: model.CommentID(fmt.Sprintf("c-%d", len(m.comments)+1)), ParticipantID: participantID, UserID: userID, Text: text}
+	m.comments = append(m.comments, comment)
+	return comment, nil
+}
 func (m *mockRepository) GetComment(ctx context.Context, commentID model.CommentID) (*model.Comment, error) { return nil, nil }
 func (m *mockRepository) ListCommentsByParticipant(ctx context.Context, participantID model.ParticipantID, limit, offset int) ([]*model.Comment, int64, error) { return nil, 0, nil }
 func (m *mockRepository) UpdateComment(ctx context.Context, commentID model.CommentID, userID model.UserID, text string) (*model.Comment, error) { return nil, nil }
 func (m *mockRepository) DeleteComment(ctx context.Context, commentID model.CommentID, userID model.UserID) error { return nil }
-func (m *mockRepository) CreateChatMessage(ctx context.Context, contestID model.ContestID, userID model.UserID, text string, isSystem bool) (*model.ChatMessage, error) { return nil, nil }
+func (m *mockRepository) CreateChatMessage(ctx context.Context, contestID model.ContestID, userID model.UserID, text string, isSystem bool) (*model.ChatMessage, error) {
+	message := &model.ChatMessage{ContestID: contestID, UserID: userID, Text: text, IsSystem: isSystem}
+	m.chatMessages = append(m.chatMessages, message)
+	return message, nil
+}
 func (m *mockRepository) ListChatMessages(ctx context.Context, contestID model.ContestID, limit, offset int) ([]*model.ChatMessage, int64, error) { return nil, 0, nil }
 func (m *mockRepository) UpdateChatMessage(ctx context.Context, messageID model.ChatMessageID, userID model.UserID, text string) (*model.ChatMessage, error) { return nil, nil }
 func (m *mockRepository) DeleteChatMessage(ctx context.Context, messageID model.ChatMessageID, userID model.UserID) (model.ContestID, error) { return "", nil }
@@ -119,6 +1229,7 @@ func (m *mockRepository) DeletePhotoLike(ctx context.Context, photoID string, us
 func (m *mockRepository) GetPhotoLikeByUser(ctx context.Context, photoID string, userID model.UserID) (*model.PhotoLike, error) { return nil, nil }
 func (m *mockRepository) CountPhotoLikes(ctx context.Context, photoID string) (int64, error) { return 0, nil }
 func (m *mockRepository) ListPhotoLikesByPhotos(ctx context.Context, photoIDs []string, userID model.UserID) (map[string]*model.PhotoLike, error) { return nil, nil }
+func (m *mockRepository) CountPhotoLikesByParticipant(ctx context.Context, contestID model.ContestID) (map[model.ParticipantID]int64, error) { return m.photoLikes, nil }
 // CountVotesByContests реализован выше с поддержкой моков
 
 func TestTopPetService_CreateContest(t *testing.T) {
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	wsapp "toppet/server/internal/app/ws"
+	"toppet/server/internal/model"
+)
+
+// DisqualifyParticipant дисквалифицирует участника на любом этапе конкурса. Запись остается с причиной,
+// но исключается из списков и итогов; голоса за участника аннулируются, и их авторы могут проголосовать заново.
+// Доступно владельцу и организаторам конкурса и персоналу платформы.
+func (s *TopPetService) DisqualifyParticipant(ctx context.Context, participantID model.ParticipantID, actorID model.UserID, reason string) (*model.Participant, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, fmt.Errorf("%w: reason is required to disqualify a participant", model.ErrBadRequest)
+	}
+	if utf8.RuneCountInString(reason) > maxModerationReasonLength {
+		return nil, fmt.Errorf("%w: reason must be at most %d characters", model.ErrBadRequest, maxModerationReasonLength)
+	}
+
+	participant, err := s.repository.GetParticipant(ctx, participantID)
+	if err != nil {
+		return nil, err
+	}
+	contest, err := s.repository.GetContest(ctx, participant.ContestID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.CanManageContest(ctx, contest, actorID) && !s.isStaff(ctx, actorID) {
+		return nil, model.ErrorForbidden
+	}
+
+	voided, disqualified, err := s.repository.DisqualifyParticipant(ctx, contest.ID, participantID, actorID, reason)
+	if err != nil {
+		return nil, err
+	}
+	if !disqualified {
+		return nil, fmt.Errorf("%w: participant is already disqualified", model.ErrBadRequest)
+	}
+	log.Printf("[Service] DisqualifyParticipant: participant %s disqualified by user %d, %d votes voided", participantID, actorID, len(voided))
+
+	if _, err := s.repository.CreateModerationAction(ctx, &model.ModerationAction{
+		ActorUserID: actorID,
+		Action:      model.ModerationActionDisqualify,
+		TargetType:  model.ModerationTargetParticipant,
+		TargetID:    string(participantID),
+		Reason:      reason,
+	}); err != nil {
+		log.Printf("[Service] DisqualifyParticipant: failed to record moderation action: %v", err)
+	}
+
+	now := time.Now()
+	participant.DisqualifiedAt = &now
+	participant.DisqualificationReason = reason
+
+	s.notifyVotesVoided(ctx, contest, voided, true)
+	s.forfeitBracketMatchups(ctx, contest.ID, participantID)
+	s.announceDisqualification(ctx, participant, actorID, len(voided))
+
+	return participant, nil
+}
+
+// announceDisqualification оставляет системное сообщение в чате конкурса - оно служит историей конкурса -
+// и сообщает подписчикам, что участник выбыл
+func (s *TopPetService) announceDisqualification(ctx context.Context, participant *model.Participant, actorID model.UserID, voidedVotes int) {
+	text := fmt.Sprintf("Участник «%s» дисквалифицирован. Причина: %s", participant.PetName, participant.DisqualificationReason)
+	message, err := s.repository.CreateChatMessage(ctx, participant.ContestID, actorID, text, true)
+	if err != nil {
+		log.Printf("[Service] DisqualifyParticipant: failed to create system chat message: %v", err)
+	}
+	s.emitWebhookEvent(ctx, participant.ContestID, model.WebhookEventParticipantDisqualified, webhookDisqualificationData{
+		ParticipantID: participant.ID,
+		Reason:        participant.DisqualificationReason,
+		VoidedVotes:   voidedVotes,
+	})
+
+	if s.hub == nil {
+		return
+	}
+	if message != nil {
+		_ = s.hub.BroadcastContestMessage(participant.ContestID, wsapp.NewMessagePayload{
+			Type:      wsapp.MessageTypeChatMessage,
+			ContestID: participant.ContestID,
+			Message:   message,
+		})
+	}
+	_ = s.hub.BroadcastContestMessage(participant.ContestID, wsapp.ParticipantDisqualifiedPayload{
+		Type:          wsapp.MessageTypeParticipantDisqualified,
+		ContestID:     participant.ContestID,
+		ParticipantID: participant.ID,
+		Reason:        participant.DisqualificationReason,
+		VoidedVotes:   voidedVotes,
+	})
+}
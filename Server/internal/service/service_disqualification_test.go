@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"toppet/server/internal/app/defenitions"
+	wsapp "toppet/server/internal/app/ws"
+	"toppet/server/internal/model"
+)
+
+func TestTopPetService_DisqualifyParticipant(t *testing.T) {
+	mockRepo := &mockRepository{
+		participants: map[model.ParticipantID]*model.Participant{
+			"cat": {ID: "cat", ContestID: "contest-id", UserID: 10, PetName: "Мурзик"},
+		},
+		participantVotes: []*model.Vote{
+			{ContestID: "contest-id", ParticipantID: "cat", UserID: 20},
+			{ContestID: "contest-id", ParticipantID: "cat", UserID: 21},
+		},
+		getContestFunc: func(ctx context.Context, contestID model.ContestID) (*model.Contest, error) {
+			return &model.Contest{ID: contestID, CreatedByUserID: 1, Status: model.ContestStatusFinished}, nil
+		},
+	}
+	hub := &recordingHub{}
+	service := &TopPetService{repository: mockRepo, hub: hub}
+	ctx := context.Background()
+
+	if _, err := service.DisqualifyParticipant(ctx, "cat", 1, "  "); !errors.Is(err, model.ErrBadRequest) {
+		t.Errorf("Expected bad request without reason, got %v", err)
+	}
+	if _, err := service.DisqualifyParticipant(ctx, "cat", 10, "Фото из интернета"); !errors.Is(err, model.ErrorForbidden) {
+		t.Errorf("Expected forbidden for entry owner, got %v", err)
+	}
+
+	// Дисквалификация возможна и после завершения конкурса
+	participant, err := service.DisqualifyParticipant(ctx, "cat", 1, "Фото из интернета")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if participant.DisqualifiedAt == nil || participant.DisqualificationReason != "Фото из интернета" {
+		t.Errorf("Expected disqualified participant with reason, got %+v", participant)
+	}
+
+	if len(mockRepo.moderationActions) != 1 || mockRepo.moderationActions[0].Action != model.ModerationActionDisqualify {
+		t.Errorf("Expected disqualify moderation action, got %+v", mockRepo.moderationActions)
+	}
+	if len(mockRepo.chatMessages) != 1 || !mockRepo.chatMessages[0].IsSystem {
+		t.Errorf("Expected system chat message in contest history, got %+v", mockRepo.chatMessages)
+	}
+
+	for _, voterID := range []model.UserID{20, 21} {
+		messages := hub.userMessages[voterID]
+		if len(messages) != 1 {
+			t.Fatalf("Expected one message for voter %d, got %v", voterID, messages)
+		}
+		payload, ok := messages[0].(wsapp.UserVoteUpdatedPayload)
+		if !ok || payload.Type != wsapp.MessageTypeVoteDeleted || !payload.CanRecast {
+			t.Errorf("Expected recastable vote_deleted for voter %d, got %+v", voterID, messages[0])
+		}
+	}
+	var announced bool
+	for _, payload := range hub.broadcasts {
+		if p, ok := payload.(wsapp.ParticipantDisqualifiedPayload); ok {
+			announced = p.ParticipantID == "cat" && p.VoidedVotes == 2
+		}
+	}
+	if !announced {
+		t.Errorf("Expected participant_disqualified broadcast, got %+v", hub.broadcasts)
+	}
+
+	// Дисквалифицированная заявка скрыта от посторонних, но видна владельцу
+	if _, err := service.GetParticipant(ctx, "cat"); !errors.Is(err, model.ErrorNotFound) {
+		t.Errorf("Expected not found for anonymous viewer, got %v", err)
+	}
+	if _, err := service.GetParticipant(context.WithValue(ctx, defenitions.UserID, model.UserID(10)), "cat"); err != nil {
+		t.Errorf("Expected owner to see disqualified entry, got %v", err)
+	}
+
+	if _, err := service.DisqualifyParticipant(ctx, "cat", 1, "Повторно"); !errors.Is(err, model.ErrBadRequest) {
+		t.Errorf("Expected bad request for second disqualification, got %v", err)
+	}
+}
+
+func TestTopPetService_VoteAfterDisqualification(t *testing.T) {
+	voidedAt := time.Now()
+	mockRepo := &mockRepository{
+		participants: map[model.ParticipantID]*model.Participant{
+			"cat": {ID: "cat", ContestID: "contest-id", UserID: 10, DisqualifiedAt: &voidedAt},
+			"dog": {ID: "dog", ContestID: "contest-id", UserID: 11},
+		},
+		userVote: &model.Vote{ID: "vote-id", ContestID: "contest-id", ParticipantID: "cat", UserID: 2, VoidedAt: &voidedAt, Recastable: true},
+		getContestFunc: func(ctx context.Context, contestID model.ContestID) (*model.Contest, error) {
+			return &model.Contest{ID: contestID, Status: model.ContestStatusVoting}, nil
+		},
+	}
+	service := &TopPetService{repository: mockRepo}
+	ctx := context.Background()
+
+	if _, err := service.Vote(ctx, "contest-id", "", 2, []model.BallotChoice{{ParticipantID: "cat"}}, model.VoteClient{}); !errors.Is(err, model.ErrBadRequest) {
+		t.Errorf("Expected bad request for disqualified participant, got %v", err)
+	}
+	if _, err := service.Vote(ctx, "contest-id", "", 2, []model.BallotChoice{{ParticipantID: "dog"}}, model.VoteClient{}); err != nil {
+		t.Errorf("Expected recast after disqualification, got %v", err)
+	}
+
+	// Голос, аннулированный за накрутку, по-прежнему не дает проголосовать
+	mockRepo.userVote.Recastable = false
+	_, err := service.Vote(ctx, "contest-id", "", 2, []model.BallotChoice{{ParticipantID: "dog"}}, model.VoteClient{})
+	var coded *model.CodedError
+	if !errors.As(err, &coded) || coded.Code != model.VoteIneligibleVoided {
+		t.Errorf("Expected voided code for fraud-voided vote, got %v", err)
+	}
+}
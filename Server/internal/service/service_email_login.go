@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"toppet/server/internal/model"
+)
+
+// EmailProviderName - провайдер в user_auth_providers для входа по ссылке из письма
+const EmailProviderName = "email"
+
+// RequestEmailLogin отправляет на email одноразовую ссылку для входа.
+func (s *TopPetService) RequestEmailLogin(ctx context.Context, email string) error {
+	if s.mailer == nil || len(s.emailLogin.Secret) == 0 {
+		return fmt.Errorf("%w: email login is disabled", model.ErrorNotFound)
+	}
+	email = normalizeEmail(email)
+
+	if s.emailLogin.RateLimit > 0 {
+		sent, err := s.repository.CountEmailLoginTokensSince(ctx, email, time.Now().Add(-s.emailLogin.RateWindow))
+		if err != nil {
+			return err
+		}
+		if sent >= int64(s.emailLogin.RateLimit) {
+			return model.ErrTooManyRequests
+		}
+	}
+
+	token, err := s.newEmailLoginToken()
+	if err != nil {
+		return err
+	}
+	if err := s.repository.CreateEmailLoginToken(ctx, hashToken(token), email, time.Now().Add(s.emailLogin.TTL)); err != nil {
+		return err
+	}
+
+	link := s.emailLogin.CallbackURL + "?token=" + url.QueryEscape(token)
+	minutes := int(s.emailLogin.TTL.Minutes())
+	return s.mailer.Send(ctx, &model.EmailMessage{
+		To:      email,
+		Subject: "Вход в TopPet",
+		Text: fmt.Sprintf("Чтобы войти в TopPet, перейдите по ссылке:\n%s\n\nСсылка действует %d мин. и может быть использована один раз. "+
+			"Если вы не запрашивали вход, просто проигнорируйте это письмо.\n", link, minutes),
+		HTML: fmt.Sprintf(`<p>Чтобы войти в TopPet, перейдите по ссылке:</p><p><a href="%s">Войти в TopPet</a></p>`+
+			`<p>Ссылка действует %d мин. и может быть использована один раз. Если вы не запрашивали вход, просто проигнорируйте это письмо.</p>`, link, minutes),
+	})
+}
+
+// LoginByEmail проверяет токен из ссылки, гасит его и выдает те же AuthData, что и OAuth вход.
+func (s *TopPetService) LoginByEmail(ctx context.Context, token string) (*model.AuthData, error) {
+	if len(s.emailLogin.Secret) == 0 {
+		return nil, fmt.Errorf("%w: email login is disabled", model.ErrorNotFound)
+	}
+	if !s.verifyEmailLoginToken(token) {
+		return nil, fmt.Errorf("%w: invalid email login token", model.ErrUnauthorized)
+	}
+
+	email, err := s.repository.ConsumeEmailLoginToken(ctx, hashToken(token))
+	if err != nil {
+		return nil, fmt.Errorf("%w: email login token is invalid, expired or already used", model.ErrUnauthorized)
+	}
+
+	name := email
+	if at := strings.Index(email, "@"); at > 0 {
+		name = email[:at]
+	}
+
+	return s.loginWithProfile(ctx, &model.UserProfileFromProvider{
+		ProviderID:   email,
+		Email:        email,
+		Name:         name,
+		ProviderName: EmailProviderName,
+	})
+}
+
+// newEmailLoginToken формирует токен вида <nonce>.<hmac(nonce)>
+func (s *TopPetService) newEmailLoginToken() (string, error) {
+	nonce := make([]byte, 32)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(nonce)
+	return encoded + "." + s.signEmailLoginNonce(encoded), nil
+}
+
+func (s *TopPetService) verifyEmailLoginToken(token string) bool {
+	nonce, sig, ok := strings.Cut(token, ".")
+	if !ok || nonce == "" {
+		return false
+	}
+	return hmac.Equal([]byte(sig), []byte(s.signEmailLoginNonce(nonce)))
+}
+
+func (s *TopPetService) signEmailLoginNonce(nonce string) string {
+	mac := hmac.New(sha256.New, s.emailLogin.Secret)
+	mac.Write([]byte(nonce))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// hashToken - в БД хранятся только хеши одноразовых токенов
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"toppet/server/internal/model"
+)
+
+type mockMailer struct {
+	sent []*model.EmailMessage
+	// err, если задана, возвращается вместо отправки
+	err error
+}
+
+func (m *mockMailer) Send(ctx context.Context, msg *model.EmailMessage) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+func TestTopPetService_EmailLoginToken(t *testing.T) {
+	s := &TopPetService{emailLogin: EmailLoginConfig{Secret: []byte("secret")}}
+
+	token, err := s.newEmailLoginToken()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !s.verifyEmailLoginToken(token) {
+		t.Errorf("expected token %q to be valid", token)
+	}
+
+	other := &TopPetService{emailLogin: EmailLoginConfig{Secret: []byte("other")}}
+	for _, bad := range []string{"", "abc", token + "x", strings.SplitN(token, ".", 2)[0] + "."} {
+		if s.verifyEmailLoginToken(bad) {
+			t.Errorf("expected token %q to be invalid", bad)
+		}
+	}
+	if other.verifyEmailLoginToken(token) {
+		t.Errorf("token signed with another secret must be invalid")
+	}
+}
+
+func TestTopPetService_RequestEmailLogin(t *testing.T) {
+	mailer := &mockMailer{}
+	s := &TopPetService{
+		repository: &mockRepository{},
+		mailer:     mailer,
+		emailLogin: EmailLoginConfig{
+			Secret:      []byte("secret"),
+			TTL:         15 * time.Minute,
+			CallbackURL: "https://api.top-pet.ru/api/auth/email/callback",
+			RateLimit:   5,
+			RateWindow:  time.Hour,
+		},
+	}
+
+	if err := s.RequestEmailLogin(context.Background(), "  User@Example.com "); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(mailer.sent) != 1 {
+		t.Fatalf("expected 1 email, got %d", len(mailer.sent))
+	}
+	msg := mailer.sent[0]
+	if msg.To != "user@example.com" {
+		t.Errorf("expected normalized address, got %q", msg.To)
+	}
+	if !strings.Contains(msg.Text, "https://api.top-pet.ru/api/auth/email/callback?token=") {
+		t.Errorf("email does not contain login link: %s", msg.Text)
+	}
+
+	if _, err := s.LoginByEmail(context.Background(), "forged.token"); !errors.Is(err, model.ErrUnauthorized) {
+		t.Errorf("expected ErrUnauthorized for forged token, got %v", err)
+	}
+}
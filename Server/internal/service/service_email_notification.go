@@ -0,0 +1,246 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"toppet/server/internal/model"
+)
+
+const (
+	// emailOutboxBatchSize сколько писем забирается за один запрос к очереди
+	emailOutboxBatchSize = 50
+	// emailOutboxLease на это время забранное письмо скрыто от других воркеров; если процесс упал
+	// во время отправки, письмо уйдет повторно после истечения аренды
+	emailOutboxLease = 5 * time.Minute
+	// emailMaxAttempts после стольких неудачных попыток письмо помечается failed
+	emailMaxAttempts = 8
+	// emailRetryBase задержка перед первым повтором; дальше она удваивается, но не больше emailRetryMax
+	emailRetryBase = time.Minute
+	emailRetryMax  = 6 * time.Hour
+
+	// unsubscribeAll вид отписки, выключающий все письма о событиях
+	unsubscribeAll = "all"
+)
+
+// emailTemplateData данные шаблонов писем о событиях конкурса
+type emailTemplateData struct {
+	ContestTitle   string
+	ContestURL     string
+	UnsubscribeURL string
+}
+
+func (s *TopPetService) emailNotificationsEnabled() bool {
+	return s.emailNotifications.Templates != nil && len(s.emailNotifications.Secret) > 0
+}
+
+// GetNotificationPreferences настройки писем; без сохраненных настроек все письма включены
+func (s *TopPetService) GetNotificationPreferences(ctx context.Context, userID model.UserID) (*model.NotificationPreferences, error) {
+	prefs, err := s.repository.GetNotificationPreferences(ctx, userID)
+	if errors.Is(err, model.ErrorNotFound) {
+		return &model.NotificationPreferences{EmailVotingStarted: true, EmailResultsPublished: true}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return prefs, nil
+}
+
+// UpdateNotificationPreferences сохраняет переключатели писем. Адрес берется из провайдера входа и здесь не меняется.
+func (s *TopPetService) UpdateNotificationPreferences(ctx context.Context, userID model.UserID, prefs *model.NotificationPreferences) (*model.NotificationPreferences, error) {
+	return s.repository.UpsertNotificationPreferences(ctx, userID, prefs)
+}
+
+// Unsubscribe выключает письма по подписанной ссылке из письма; вход для этого не нужен.
+// Повторный переход по ссылке безопасен.
+func (s *TopPetService) Unsubscribe(ctx context.Context, token string) (*model.NotificationPreferences, error) {
+	if !s.emailNotificationsEnabled() {
+		return nil, fmt.Errorf("%w: email notifications are disabled", model.ErrorNotFound)
+	}
+	userID, kind, ok := s.parseUnsubscribeToken(token)
+	if !ok {
+		return nil, fmt.Errorf("%w: invalid unsubscribe token", model.ErrBadRequest)
+	}
+
+	prefs, err := s.GetNotificationPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	switch model.NotificationType(kind) {
+	case model.NotificationVotingStarted:
+		prefs.EmailVotingStarted = false
+	case model.NotificationResultsPublished:
+		prefs.EmailResultsPublished = false
+	default:
+		prefs.EmailVotingStarted = false
+		prefs.EmailResultsPublished = false
+	}
+	return s.repository.UpsertNotificationPreferences(ctx, userID, prefs)
+}
+
+// enqueueContestEmails ставит в очередь письма о событии конкурса тем из recipients, у кого известен адрес
+// и письма этого типа не отключены. Письмо рендерится сразу на языке получателя. Ошибки только логируются.
+func (s *TopPetService) enqueueContestEmails(ctx context.Context, contest *model.Contest, notificationType model.NotificationType, recipients []model.UserID) {
+	if !s.emailNotificationsEnabled() || len(recipients) == 0 {
+		return
+	}
+	emailRecipients, err := s.repository.ListEmailRecipients(ctx, recipients, notificationType)
+	if err != nil {
+		log.Printf("[Service] enqueueContestEmails: contestID=%s, type=%s: %v", contest.ID, notificationType, err)
+		return
+	}
+
+	contestURL := strings.TrimSuffix(s.emailNotifications.SiteURL, "/") + "/contests/" + url.PathEscape(string(contest.ID))
+	emails := make([]*model.OutboxEmail, 0, len(emailRecipients))
+	for _, recipient := range emailRecipients {
+		unsubscribeURL := s.unsubscribeURL(recipient.UserID, string(notificationType))
+		msg, err := s.emailNotifications.Templates.Render(string(notificationType), recipient.Locale, emailTemplateData{
+			ContestTitle:   contest.Title,
+			ContestURL:     contestURL,
+			UnsubscribeURL: unsubscribeURL,
+		})
+		if err != nil {
+			log.Printf("[Service] enqueueContestEmails: render %s: %v", notificationType, err)
+			return
+		}
+		userID := recipient.UserID
+		emails = append(emails, &model.OutboxEmail{
+			UserID:         &userID,
+			To:             recipient.Email,
+			Subject:        msg.Subject,
+			Text:           msg.Text,
+			HTML:           msg.HTML,
+			UnsubscribeURL: unsubscribeURL,
+		})
+	}
+	if err := s.repository.EnqueueEmails(ctx, emails); err != nil {
+		log.Printf("[Service] enqueueContestEmails: contestID=%s, emails=%d: %v", contest.ID, len(emails), err)
+	}
+}
+
+// ProcessEmailOutbox отправляет письма, готовые к отправке на момент now. Неудачная отправка
+// повторяется с экспоненциальной задержкой; письма забираются через SKIP LOCKED, поэтому воркер
+// может работать на нескольких экземплярах.
+func (s *TopPetService) ProcessEmailOutbox(ctx context.Context, now time.Time) error {
+	if s.mailer == nil {
+		return nil
+	}
+	for {
+		emails, err := s.repository.ClaimEmailOutbox(ctx, now, now.Add(emailOutboxLease), emailOutboxBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(emails) == 0 {
+			return nil
+		}
+		for _, email := range emails {
+			s.sendOutboxEmail(ctx, email, now)
+		}
+		if len(emails) < emailOutboxBatchSize {
+			return nil
+		}
+	}
+}
+
+// RunEmailOutboxWorker периодически отправляет письма из очереди
+func (s *TopPetService) RunEmailOutboxWorker(ctx context.Context, interval time.Duration) {
+	runPeriodically(ctx, interval, func(ctx context.Context) {
+		if err := s.ProcessEmailOutbox(ctx, time.Now()); err != nil {
+			log.Printf("[Service] RunEmailOutboxWorker: %v", err)
+		}
+	})
+}
+
+func (s *TopPetService) sendOutboxEmail(ctx context.Context, email *model.OutboxEmail, now time.Time) {
+	msg := &model.EmailMessage{
+		To:      email.To,
+		Subject: email.Subject,
+		Text:    email.Text,
+		HTML:    email.HTML,
+	}
+	if email.UnsubscribeURL != "" {
+		// RFC 8058: почтовый клиент показывает кнопку отписки и отправляет POST на этот адрес
+		msg.Headers = map[string]string{
+			"List-Unsubscribe":      "<" + email.UnsubscribeURL + ">",
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		}
+	}
+
+	sendErr := s.mailer.Send(ctx, msg)
+	if sendErr == nil {
+		if err := s.repository.MarkEmailSent(ctx, email.ID); err != nil {
+			log.Printf("[Service] sendOutboxEmail: emailID=%s: failed to mark sent: %v", email.ID, err)
+		}
+		return
+	}
+
+	log.Printf("[Service] sendOutboxEmail: emailID=%s, attempt=%d: %v", email.ID, email.Attempts, sendErr)
+	status := model.OutboxPending
+	if email.Attempts >= emailMaxAttempts {
+		status = model.OutboxFailed
+	}
+	if err := s.repository.FailEmailAttempt(ctx, email.ID, status, sendErr.Error(), now.Add(retryDelay(email.Attempts, emailRetryBase, emailRetryMax))); err != nil {
+		log.Printf("[Service] sendOutboxEmail: emailID=%s: failed to save status %s: %v", email.ID, status, err)
+	}
+}
+
+// retryDelay экспоненциальная задержка перед следующей попыткой после attempts неудачных:
+// base, 2*base, 4*base... но не больше maxDelay. Общая для всех исходящих очередей.
+func retryDelay(attempts int, base, maxDelay time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < attempts && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxDelay)
+}
+
+// unsubscribeURL ссылка отписки от писем вида kind (тип уведомления или unsubscribeAll)
+func (s *TopPetService) unsubscribeURL(userID model.UserID, kind string) string {
+	return s.emailNotifications.UnsubscribeURL + "?token=" + url.QueryEscape(s.newUnsubscribeToken(userID, kind))
+}
+
+// newUnsubscribeToken формирует токен вида <userID>.<kind>.<hmac>. Срока действия нет:
+// ссылка из старого письма тоже должна работать.
+func (s *TopPetService) newUnsubscribeToken(userID model.UserID, kind string) string {
+	payload := strconv.FormatInt(int64(userID), 10) + "." + kind
+	return payload + "." + s.signUnsubscribePayload(payload)
+}
+
+func (s *TopPetService) parseUnsubscribeToken(token string) (model.UserID, string, bool) {
+	i := strings.LastIndex(token, ".")
+	if i < 0 {
+		return 0, "", false
+	}
+	payload, sig := token[:i], token[i+1:]
+	if !hmac.Equal([]byte(sig), []byte(s.signUnsubscribePayload(payload))) {
+		return 0, "", false
+	}
+	rawUserID, kind, ok := strings.Cut(payload, ".")
+	if !ok {
+		return 0, "", false
+	}
+	userID, err := strconv.ParseInt(rawUserID, 10, 64)
+	if err != nil || userID <= 0 {
+		return 0, "", false
+	}
+	switch kind {
+	case string(model.NotificationVotingStarted), string(model.NotificationResultsPublished), unsubscribeAll:
+		return model.UserID(userID), kind, true
+	}
+	return 0, "", false
+}
+
+func (s *TopPetService) signUnsubscribePayload(payload string) string {
+	mac := hmac.New(sha256.New, s.emailNotifications.Secret)
+	mac.Write([]byte("unsubscribe:" + payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,273 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"toppet/server/internal/model"
+)
+
+// stubEmailTemplates рендерит письмо из имени шаблона, языка и данных
+type stubEmailTemplates struct{}
+
+func (stubEmailTemplates) Render(name string, locale model.Locale, data any) (*model.EmailMessage, error) {
+	d := data.(emailTemplateData)
+	return &model.EmailMessage{
+		Subject: name + " " + string(locale) + " " + d.ContestTitle,
+		Text:    d.ContestURL + " " + d.UnsubscribeURL,
+		HTML:    "<p>" + d.ContestTitle + "</p>",
+	}, nil
+}
+
+func newEmailNotificationService(mockRepo *mockRepository, mailer *mockMailer) *TopPetService {
+	return &TopPetService{
+		repository: mockRepo,
+		mailer:     mailer,
+		emailNotifications: EmailNotificationConfig{
+			Templates:      stubEmailTemplates{},
+			Secret:         []byte("secret"),
+			UnsubscribeURL: "https://api.top-pet.ru/api/notifications/unsubscribe",
+			SiteURL:        "https://top-pet.ru/",
+		},
+	}
+}
+
+func tokenFromURL(t *testing.T, rawURL string) string {
+	t.Helper()
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatalf("bad unsubscribe url %q: %v", rawURL, err)
+	}
+	return u.Query().Get("token")
+}
+
+func TestTopPetService_ContestStatusEmails(t *testing.T) {
+	contest := &model.Contest{ID: "contest-id", CreatedByUserID: 1, Title: "Самый пушистый", Status: model.ContestStatusRegistration}
+	mockRepo := &mockRepository{
+		getContestFunc: func(ctx context.Context, contestID model.ContestID) (*model.Contest, error) {
+			copied := *contest
+			return &copied, nil
+		},
+		updateContestStatusFunc: func(ctx context.Context, contestID model.ContestID, status model.ContestStatus) (*model.Contest, error) {
+			contest.Status = status
+			copied := *contest
+			return &copied, nil
+		},
+		contestParticipants: []*model.Participant{
+			{ID: "p1", UserID: 10, ModerationStatus: model.EntryModerationApproved},
+			{ID: "p2", UserID: 10, ModerationStatus: model.EntryModerationApproved},
+			{ID: "p3", UserID: 11, ModerationStatus: model.EntryModerationApproved},
+			{ID: "p4", UserID: 12, ModerationStatus: model.EntryModerationApproved},
+			{ID: "p6", UserID: 1, ModerationStatus: model.EntryModerationApproved},
+		},
+		users: map[model.UserID]*model.User{
+			11: {ID: 11, Locale: model.LocaleEN},
+		},
+		notificationPrefs: map[model.UserID]*model.NotificationPreferences{
+			1:  {Email: "owner@example.com", EmailVotingStarted: true, EmailResultsPublished: true},
+			10: {Email: "ten@example.com", EmailVotingStarted: true, EmailResultsPublished: true},
+			11: {Email: "eleven@example.com", EmailVotingStarted: true, EmailResultsPublished: false},
+		},
+	}
+	service := newEmailNotificationService(mockRepo, &mockMailer{})
+	ctx := context.Background()
+
+	if _, err := service.UpdateContestStatus(ctx, "contest-id", 1, model.ContestStatusVoting); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// 12 без адреса, 1 - организатор, сменивший статус
+	if len(mockRepo.outbox) != 2 {
+		t.Fatalf("Expected 2 queued emails, got %d", len(mockRepo.outbox))
+	}
+	ten, eleven := mockRepo.outbox[0], mockRepo.outbox[1]
+	if ten.To != "ten@example.com" || ten.Subject != "voting_started ru Самый пушистый" {
+		t.Errorf("Unexpected email for user 10: %+v", ten.OutboxEmail)
+	}
+	if eleven.To != "eleven@example.com" || !strings.HasPrefix(eleven.Subject, "voting_started en") {
+		t.Errorf("Expected english email for user 11, got %+v", eleven.OutboxEmail)
+	}
+	if !strings.Contains(ten.Text, "https://top-pet.ru/contests/contest-id") {
+		t.Errorf("Expected contest link, got %q", ten.Text)
+	}
+	if !strings.HasPrefix(ten.UnsubscribeURL, "https://api.top-pet.ru/api/notifications/unsubscribe?token=") {
+		t.Errorf("Unexpected unsubscribe url %q", ten.UnsubscribeURL)
+	}
+
+	// Итоги: пользователь 11 отключил такие письма
+	if _, err := service.FinishContest(ctx, "contest-id", 1); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(mockRepo.outbox) != 3 || mockRepo.outbox[2].To != "ten@example.com" || !strings.HasPrefix(mockRepo.outbox[2].Subject, "results_published") {
+		t.Errorf("Expected one results email to user 10, got %d emails", len(mockRepo.outbox))
+	}
+}
+
+func TestTopPetService_ContestStatusEmailsDisabledWithoutSecret(t *testing.T) {
+	mockRepo := &mockRepository{
+		contestParticipants: []*model.Participant{{ID: "p1", UserID: 10}},
+		notificationPrefs: map[model.UserID]*model.NotificationPreferences{
+			10: {Email: "ten@example.com", EmailVotingStarted: true, EmailResultsPublished: true},
+		},
+	}
+	service := newEmailNotificationService(mockRepo, &mockMailer{})
+	service.emailNotifications.Secret = nil
+
+	service.notifyContestStatus(context.Background(), &model.Contest{ID: "c", Status: model.ContestStatusVoting}, 1)
+	if len(mockRepo.outbox) != 0 {
+		t.Errorf("Expected no emails without secret, got %d", len(mockRepo.outbox))
+	}
+}
+
+func TestTopPetService_Unsubscribe(t *testing.T) {
+	mockRepo := &mockRepository{
+		notificationPrefs: map[model.UserID]*model.NotificationPreferences{
+			10: {Email: "ten@example.com", EmailVotingStarted: true, EmailResultsPublished: true},
+		},
+	}
+	service := newEmailNotificationService(mockRepo, &mockMailer{})
+	ctx := context.Background()
+
+	token := tokenFromURL(t, service.unsubscribeURL(10, string(model.NotificationVotingStarted)))
+	prefs, err := service.Unsubscribe(ctx, token)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if prefs.EmailVotingStarted || !prefs.EmailResultsPublished {
+		t.Errorf("Expected only voting_started emails to be disabled, got %+v", prefs)
+	}
+	// Повторный переход по ссылке безопасен, адрес сохраняется
+	if _, err := service.Unsubscribe(ctx, token); err != nil {
+		t.Fatalf("Unexpected error on repeated unsubscribe: %v", err)
+	}
+	if mockRepo.notificationPrefs[10].Email != "ten@example.com" {
+		t.Errorf("Unsubscribe must not drop the address")
+	}
+
+	// Отписка от всего для пользователя без сохраненных настроек
+	all := service.newUnsubscribeToken(20, unsubscribeAll)
+	if prefs, err := service.Unsubscribe(ctx, all); err != nil || prefs.EmailVotingStarted || prefs.EmailResultsPublished {
+		t.Errorf("Expected all emails disabled, got %+v (err %v)", prefs, err)
+	}
+
+	other := newEmailNotificationService(&mockRepository{}, &mockMailer{})
+	other.emailNotifications.Secret = []byte("other")
+	forged := other.newUnsubscribeToken(10, unsubscribeAll)
+	for _, bad := range []string{"", "10.all", "10.all.", forged, strings.Replace(token, "10.", "11.", 1), service.newUnsubscribeToken(10, "entry_comment")} {
+		if _, err := service.Unsubscribe(ctx, bad); !errors.Is(err, model.ErrBadRequest) {
+			t.Errorf("Token %q: expected ErrBadRequest, got %v", bad, err)
+		}
+	}
+}
+
+func TestTopPetService_ProcessEmailOutbox(t *testing.T) {
+	userID := model.UserID(10)
+	mockRepo := &mockRepository{}
+	_ = mockRepo.EnqueueEmails(context.Background(), []*model.OutboxEmail{
+		{UserID: &userID, To: "ten@example.com", Subject: "s", Text: "t", UnsubscribeURL: "https://api/unsubscribe?token=x"},
+		{To: "admin@example.com", Subject: "s", Text: "t"},
+	})
+	mailer := &mockMailer{}
+	service := newEmailNotificationService(mockRepo, mailer)
+	now := time.Now()
+
+	if err := service.ProcessEmailOutbox(context.Background(), now); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(mailer.sent) != 2 {
+		t.Fatalf("Expected 2 sent emails, got %d", len(mailer.sent))
+	}
+	if mailer.sent[0].Headers["List-Unsubscribe"] != "<https://api/unsubscribe?token=x>" ||
+		mailer.sent[0].Headers["List-Unsubscribe-Post"] != "List-Unsubscribe=One-Click" {
+		t.Errorf("Expected one-click unsubscribe headers, got %v", mailer.sent[0].Headers)
+	}
+	if mailer.sent[1].Headers != nil {
+		t.Errorf("Expected no unsubscribe headers without url, got %v", mailer.sent[1].Headers)
+	}
+	for _, email := range mockRepo.outbox {
+		if email.status != model.OutboxSent {
+			t.Errorf("Email %s: expected sent, got %s", email.ID, email.status)
+		}
+	}
+}
+
+func TestTopPetService_ProcessEmailOutboxRetry(t *testing.T) {
+	mockRepo := &mockRepository{}
+	_ = mockRepo.EnqueueEmails(context.Background(), []*model.OutboxEmail{{To: "ten@example.com", Subject: "s", Text: "t"}})
+	mailer := &mockMailer{err: errors.New("smtp is down")}
+	service := newEmailNotificationService(mockRepo, mailer)
+	email := mockRepo.outbox[0]
+	now := time.Now()
+
+	if err := service.ProcessEmailOutbox(context.Background(), now); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if email.status != model.OutboxPending || email.lastError != "smtp is down" || !email.nextAttemptAt.Equal(now.Add(emailRetryBase)) {
+		t.Fatalf("Expected retry in %s, got status=%s next=%s err=%q", emailRetryBase, email.status, email.nextAttemptAt.Sub(now), email.lastError)
+	}
+
+	// До срока повтора письмо не забирается
+	if err := service.ProcessEmailOutbox(context.Background(), now.Add(30*time.Second)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if email.Attempts != 1 {
+		t.Errorf("Expected no attempt before retry time, got %d attempts", email.Attempts)
+	}
+
+	for email.status == model.OutboxPending {
+		now = email.nextAttemptAt
+		if err := service.ProcessEmailOutbox(context.Background(), now); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if email.status != model.OutboxFailed || email.Attempts != emailMaxAttempts {
+		t.Errorf("Expected failed after %d attempts, got status=%s attempts=%d", emailMaxAttempts, email.status, email.Attempts)
+	}
+
+	// Почта снова работает, но исчерпавшее попытки письмо больше не отправляется
+	mailer.err = nil
+	if err := service.ProcessEmailOutbox(context.Background(), now.Add(24*time.Hour)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(mailer.sent) != 0 {
+		t.Errorf("Failed email must not be resent, got %d", len(mailer.sent))
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	for attempts, want := range map[int]time.Duration{
+		1:  time.Minute,
+		2:  2 * time.Minute,
+		4:  8 * time.Minute,
+		20: emailRetryMax,
+	} {
+		if got := retryDelay(attempts, emailRetryBase, emailRetryMax); got != want {
+			t.Errorf("retryDelay(%d) = %s, want %s", attempts, got, want)
+		}
+	}
+}
+
+func TestTopPetService_NotificationPreferences(t *testing.T) {
+	mockRepo := &mockRepository{
+		notificationPrefs: map[model.UserID]*model.NotificationPreferences{
+			10: {Email: "ten@example.com", EmailVotingStarted: true, EmailResultsPublished: true},
+		},
+	}
+	service := &TopPetService{repository: mockRepo}
+	ctx := context.Background()
+
+	if prefs, err := service.GetNotificationPreferences(ctx, 99); err != nil || !prefs.EmailVotingStarted || !prefs.EmailResultsPublished || prefs.Email != "" {
+		t.Errorf("Expected all emails enabled by default, got %+v (err %v)", prefs, err)
+	}
+
+	updated, err := service.UpdateNotificationPreferences(ctx, 10, &model.NotificationPreferences{Email: "spoofed@example.com", EmailResultsPublished: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if updated.EmailVotingStarted || !updated.EmailResultsPublished || updated.Email != "ten@example.com" {
+		t.Errorf("Expected toggles saved and address kept, got %+v", updated)
+	}
+}
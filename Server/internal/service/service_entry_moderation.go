@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"unicode/utf8"
+
+	"toppet/server/internal/app/defenitions"
+	wsapp "toppet/server/internal/app/ws"
+	"toppet/server/internal/model"
+)
+
+// ListEntryModerationQueue заявки конкурса с указанным статусом премодерации (по умолчанию - ожидающие решения).
+// Доступно команде конкурса, кроме жюри, и персоналу платформы.
+func (s *TopPetService) ListEntryModerationQueue(ctx context.Context, contestID model.ContestID, actorID model.UserID, status model.EntryModerationStatus) ([]*model.Participant, error) {
+	if status == "" {
+		status = model.EntryModerationPending
+	}
+	switch status {
+	case model.EntryModerationPending, model.EntryModerationApproved, model.EntryModerationRejected:
+	default:
+		return nil, fmt.Errorf("%w: unknown moderation status %q", model.ErrBadRequest, status)
+	}
+
+	contest, err := s.repository.GetContest(ctx, contestID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.canModerateEntries(ctx, contest, actorID) {
+		return nil, model.ErrorForbidden
+	}
+
+	participants, err := s.repository.ListParticipantsByModerationStatus(ctx, contestID, status)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range participants {
+		photos, _ := s.repository.GetPhotosByParticipantID(ctx, p.ID)
+		p.Photos = photos
+
+		video, _ := s.repository.GetVideoByParticipantID(ctx, p.ID)
+		if video != nil {
+			p.Video = video
+		}
+	}
+	return participants, nil
+}
+
+// ModerateEntry одобряет или отклоняет заявку. Для отклонения нужна причина - ее увидит владелец заявки.
+// Решение можно пересмотреть, пока конкурс не завершен.
+func (s *TopPetService) ModerateEntry(ctx context.Context, participantID model.ParticipantID, actorID model.UserID, decision model.EntryModerationStatus, reason string) (*model.Participant, error) {
+	reason = strings.TrimSpace(reason)
+	switch decision {
+	case model.EntryModerationApproved:
+		reason = ""
+	case model.EntryModerationRejected:
+		if reason == "" {
+			return nil, fmt.Errorf("%w: reason is required to reject an entry", model.ErrBadRequest)
+		}
+		if utf8.RuneCountInString(reason) > maxModerationReasonLength {
+			return nil, fmt.Errorf("%w: reason must be at most %d characters", model.ErrBadRequest, maxModerationReasonLength)
+		}
+	default:
+		return nil, fmt.Errorf("%w: unknown moderation decision %q", model.ErrBadRequest, decision)
+	}
+
+	participant, err := s.repository.GetParticipant(ctx, participantID)
+	if err != nil {
+		return nil, err
+	}
+	contest, err := s.repository.GetContest(ctx, participant.ContestID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.canModerateEntries(ctx, contest, actorID) {
+		return nil, model.ErrorForbidden
+	}
+	if contest.Status == model.ContestStatusFinished {
+		return nil, fmt.Errorf("%w: contest is finished", model.ErrBadRequest)
+	}
+
+	if err := s.repository.SetParticipantModeration(ctx, participantID, decision, reason, actorID); err != nil {
+		return nil, err
+	}
+	log.Printf("[Service] ModerateEntry: participant %s %s by user %d", participantID, decision, actorID)
+
+	previousStatus := participant.ModerationStatus
+	participant.ModerationStatus = decision
+	participant.ModerationReason = reason
+	s.notifyEntryModerated(participant)
+	if decision == model.EntryModerationApproved && previousStatus != model.EntryModerationApproved {
+		s.emitParticipantCreatedWebhook(ctx, participant)
+	}
+
+	return participant, nil
+}
+
+// resubmitEntry возвращает измененную заявку на премодерацию, если конкурс ее требует
+func (s *TopPetService) resubmitEntry(ctx context.Context, participant *model.Participant, rules *model.EntryRules) error {
+	if !rules.RequireApproval || participant.ModerationStatus == model.EntryModerationPending {
+		return nil
+	}
+	if err := s.repository.SetParticipantModeration(ctx, participant.ID, model.EntryModerationPending, "", 0); err != nil {
+		return err
+	}
+	participant.ModerationStatus = model.EntryModerationPending
+	participant.ModerationReason = ""
+	participant.ModeratedAt = nil
+	return nil
+}
+
+// canModerateEntries - команда конкурса (кроме жюри) или персонал платформы
+func (s *TopPetService) canModerateEntries(ctx context.Context, contest *model.Contest, userID model.UserID) bool {
+	return s.canModerateContest(ctx, contest, userID) || s.isStaff(ctx, userID)
+}
+
+// canViewEntry - неодобренная или дисквалифицированная заявка видна только владельцу, команде конкурса и персоналу
+func (s *TopPetService) canViewEntry(ctx context.Context, participant *model.Participant) bool {
+	if isEntryPublic(participant) {
+		return true
+	}
+	userID, ok := ctx.Value(defenitions.UserID).(model.UserID)
+	if !ok {
+		return false
+	}
+	if participant.UserID == userID || s.isStaff(ctx, userID) {
+		return true
+	}
+	contest, err := s.repository.GetContest(ctx, participant.ContestID)
+	return err == nil && s.canModerateContest(ctx, contest, userID)
+}
+
+// isEntryPublic - заявка не скрыта модератором, не ждет решения, не отклонена и не дисквалифицирована:
+// видна всем, за нее можно голосовать
+func isEntryPublic(participant *model.Participant) bool {
+	if participant.Hidden || participant.DisqualifiedAt != nil {
+		return false
+	}
+	return participant.ModerationStatus != model.EntryModerationPending && participant.ModerationStatus != model.EntryModerationRejected
+}
+
+func (s *TopPetService) notifyEntryModerated(participant *model.Participant) {
+	if s.hub == nil {
+		return
+	}
+	_ = s.hub.SendContestMessageToUser(participant.ContestID, participant.UserID, wsapp.ParticipantModeratedPayload{
+		Type:          wsapp.MessageTypeParticipantModerated,
+		ContestID:     participant.ContestID,
+		ParticipantID: participant.ID,
+		Status:        participant.ModerationStatus,
+		Reason:        participant.ModerationReason,
+	})
+}
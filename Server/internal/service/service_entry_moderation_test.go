@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"toppet/server/internal/app/defenitions"
+	wsapp "toppet/server/internal/app/ws"
+	"toppet/server/internal/model"
+)
+
+// recordingHub запоминает сообщения, отправленные конкретным пользователям и всем подписчикам конкурса
+type recordingHub struct {
+	userMessages     map[model.UserID][]any
+	personalMessages map[model.UserID][]any
+	broadcasts       []any
+}
+
+func (h *recordingHub) BroadcastContestMessage(contestID model.ContestID, payload any) error {
+	h.broadcasts = append(h.broadcasts, payload)
+	return nil
+}
+
+func (h *recordingHub) SendContestMessageToUser(contestID model.ContestID, userID model.UserID, payload any) error {
+	if h.userMessages == nil {
+		h.userMessages = map[model.UserID][]any{}
+	}
+	h.userMessages[userID] = append(h.userMessages[userID], payload)
+	return nil
+}
+
+func (h *recordingHub) SendUserMessage(userID model.UserID, payload any) error {
+	if h.personalMessages == nil {
+		h.personalMessages = map[model.UserID][]any{}
+	}
+	h.personalMessages[userID] = append(h.personalMessages[userID], payload)
+	return nil
+}
+
+func TestTopPetService_EntryModeration(t *testing.T) {
+	mockRepo := &mockRepository{
+		entryRules: &model.EntryRules{RequireApproval: true, VideoAllowed: true},
+		participants: map[model.ParticipantID]*model.Participant{
+			"cat": {ID: "cat", ContestID: "contest-id", UserID: 10, ModerationStatus: model.EntryModerationPending},
+		},
+		getContestFunc: func(ctx context.Context, contestID model.ContestID) (*model.Contest, error) {
+			return &model.Contest{ID: contestID, CreatedByUserID: 1, Status: model.ContestStatusRegistration}, nil
+		},
+	}
+	hub := &recordingHub{}
+	service := &TopPetService{repository: mockRepo, hub: hub}
+	ctx := context.Background()
+
+	created, err := service.CreateParticipant(ctx, "contest-id", 11, "", "Шарик", "", model.PetAttributes{}, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if created.ModerationStatus != model.EntryModerationPending {
+		t.Errorf("Expected pending entry when approval is required, got %q", created.ModerationStatus)
+	}
+
+	// Ожидающая заявка не видна анонимно, но видна владельцу и организатору
+	if _, err := service.GetParticipant(ctx, "cat"); !errors.Is(err, model.ErrorNotFound) {
+		t.Errorf("Expected not found for anonymous viewer, got %v", err)
+	}
+	for _, viewer := range []model.UserID{10, 1} {
+		if _, err := service.GetParticipant(context.WithValue(ctx, defenitions.UserID, viewer), "cat"); err != nil {
+			t.Errorf("Expected user %d to see pending entry, got %v", viewer, err)
+		}
+	}
+
+	queue, err := service.ListEntryModerationQueue(ctx, "contest-id", 1, "")
+	if err != nil || len(queue) != 1 || queue[0].ID != "cat" {
+		t.Fatalf("Expected cat in moderation queue, got %v, %v", queue, err)
+	}
+	if _, err := service.ListEntryModerationQueue(ctx, "contest-id", 10, ""); !errors.Is(err, model.ErrorForbidden) {
+		t.Errorf("Expected forbidden queue for participant owner, got %v", err)
+	}
+
+	if _, err := service.ModerateEntry(ctx, "cat", 10, model.EntryModerationApproved, ""); !errors.Is(err, model.ErrorForbidden) {
+		t.Errorf("Expected forbidden for owner approving own entry, got %v", err)
+	}
+	if _, err := service.ModerateEntry(ctx, "cat", 1, model.EntryModerationRejected, "  "); !errors.Is(err, model.ErrBadRequest) {
+		t.Errorf("Expected bad request for rejection without reason, got %v", err)
+	}
+
+	rejected, err := service.ModerateEntry(ctx, "cat", 1, model.EntryModerationRejected, "На фото нет питомца")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if rejected.ModerationStatus != model.EntryModerationRejected || rejected.ModerationReason != "На фото нет питомца" {
+		t.Errorf("Expected rejected entry with reason, got %+v", rejected)
+	}
+	if len(hub.userMessages[10]) != 1 {
+		t.Fatalf("Expected owner to be notified, got %v", hub.userMessages)
+	}
+	if payload, ok := hub.userMessages[10][0].(wsapp.ParticipantModeratedPayload); !ok || payload.Status != model.EntryModerationRejected {
+		t.Errorf("Unexpected notification: %+v", hub.userMessages[10][0])
+	}
+
+	// Новое фото возвращает отклоненную заявку на модерацию
+	if _, err := service.AddParticipantPhoto(ctx, "cat", 10, "url", nil, model.MediaUpload{Size: 1 << 20}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if status := mockRepo.participants["cat"].ModerationStatus; status != model.EntryModerationPending {
+		t.Errorf("Expected entry back in queue after new photo, got %q", status)
+	}
+
+	if _, err := service.ModerateEntry(ctx, "cat", 1, model.EntryModerationApproved, ""); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := service.GetParticipant(ctx, "cat"); err != nil {
+		t.Errorf("Expected approved entry to be public, got %v", err)
+	}
+}
+
+func TestTopPetService_VoteForHiddenEntry(t *testing.T) {
+	mockRepo := &mockRepository{
+		participants: map[model.ParticipantID]*model.Participant{
+			"cat": {ID: "cat", ContestID: "contest-id", UserID: 10, ModerationStatus: model.EntryModerationApproved, Hidden: true},
+			"dog": {ID: "dog", ContestID: "contest-id", UserID: 11, ModerationStatus: model.EntryModerationApproved},
+		},
+		getContestFunc: func(ctx context.Context, contestID model.ContestID) (*model.Contest, error) {
+			return &model.Contest{ID: contestID, Status: model.ContestStatusVoting}, nil
+		},
+	}
+	service := &TopPetService{repository: mockRepo}
+	ctx := context.Background()
+
+	// Скрытая модератором заявка не принимает голоса, даже если была одобрена
+	if _, err := service.Vote(ctx, "contest-id", "", 2, []model.BallotChoice{{ParticipantID: "cat"}}, model.VoteClient{}); !errors.Is(err, model.ErrBadRequest) {
+		t.Errorf("Expected bad request for hidden entry, got %v", err)
+	}
+	if _, err := service.Vote(ctx, "contest-id", "", 2, []model.BallotChoice{{ParticipantID: "dog"}}, model.VoteClient{}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"toppet/server/internal/model"
+)
+
+const (
+	defaultMaxPhotoSizeMB  = 10
+	defaultMaxVideoSizeMB  = 100
+	maxEntriesLimit        = 10000
+	maxPhotosPerEntryLimit = 50
+	maxVideoDurationLimit  = 3600
+)
+
+// entryRequiredFields поля заявки, которые можно сделать обязательными
+var entryRequiredFields = []string{model.EntryFieldPetDescription}
+
+// GetEntryRules возвращает правила подачи заявок в конкурс. Если правила не заданы - действуют значения по умолчанию.
+func (s *TopPetService) GetEntryRules(ctx context.Context, contestID model.ContestID) (*model.EntryRules, error) {
+	if _, err := s.GetContest(ctx, contestID); err != nil {
+		return nil, err
+	}
+	return s.entryRules(ctx, contestID)
+}
+
+func (s *TopPetService) entryRules(ctx context.Context, contestID model.ContestID) (*model.EntryRules, error) {
+	rules, err := s.repository.GetContestEntryRules(ctx, contestID)
+	if errors.Is(err, model.ErrorNotFound) {
+		return &model.EntryRules{
+			ContestID:      contestID,
+			MaxPhotoSizeMB: defaultMaxPhotoSizeMB,
+			VideoAllowed:   true,
+			MaxVideoSizeMB: defaultMaxVideoSizeMB,
+			RequiredFields: []string{},
+			AllowedSpecies: []string{},
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// UpdateEntryRules сохраняет правила подачи заявок (владелец и организаторы конкурса).
+// Нулевые размеры файлов означают значения по умолчанию.
+func (s *TopPetService) UpdateEntryRules(ctx context.Context, contestID model.ContestID, actorID model.UserID, rules *model.EntryRules) (*model.EntryRules, error) {
+	contest, err := s.repository.GetContest(ctx, contestID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.CanManageContest(ctx, contest, actorID) {
+		return nil, model.ErrorForbidden
+	}
+	if contest.Status == model.ContestStatusFinished {
+		return nil, fmt.Errorf("%w: contest is finished", model.ErrBadRequest)
+	}
+
+	if rules.MaxEntries < 0 || rules.MaxEntries > maxEntriesLimit {
+		return nil, fmt.Errorf("%w: max_entries must be between 0 and %d", model.ErrBadRequest, maxEntriesLimit)
+	}
+	if rules.MaxEntriesPerUser < 0 || rules.MaxEntriesPerUser > maxEntriesLimit {
+		return nil, fmt.Errorf("%w: max_entries_per_user must be between 0 and %d", model.ErrBadRequest, maxEntriesLimit)
+	}
+	if rules.MaxPhotosPerEntry < 0 || rules.MaxPhotosPerEntry > maxPhotosPerEntryLimit {
+		return nil, fmt.Errorf("%w: max_photos_per_entry must be between 0 and %d", model.ErrBadRequest, maxPhotosPerEntryLimit)
+	}
+	if rules.MaxVideoDurationSec < 0 || rules.MaxVideoDurationSec > maxVideoDurationLimit {
+		return nil, fmt.Errorf("%w: max_video_duration_sec must be between 0 and %d", model.ErrBadRequest, maxVideoDurationLimit)
+	}
+
+	photoSize := rules.MaxPhotoSizeMB
+	if photoSize == 0 {
+		photoSize = defaultMaxPhotoSizeMB
+	}
+	if photoSize < 0 || photoSize > model.MaxPhotoUploadMB {
+		return nil, fmt.Errorf("%w: max_photo_size_mb must be between 1 and %d", model.ErrBadRequest, model.MaxPhotoUploadMB)
+	}
+	videoSize := rules.MaxVideoSizeMB
+	if videoSize == 0 {
+		videoSize = defaultMaxVideoSizeMB
+	}
+	if videoSize < 0 || videoSize > model.MaxVideoUploadMB {
+		return nil, fmt.Errorf("%w: max_video_size_mb must be between 1 and %d", model.ErrBadRequest, model.MaxVideoUploadMB)
+	}
+
+	requiredFields := make([]string, 0, len(rules.RequiredFields))
+	for _, field := range rules.RequiredFields {
+		field = strings.ToLower(strings.TrimSpace(field))
+		if !slices.Contains(entryRequiredFields, field) {
+			return nil, fmt.Errorf("%w: unknown required field %q", model.ErrBadRequest, field)
+		}
+		if !slices.Contains(requiredFields, field) {
+			requiredFields = append(requiredFields, field)
+		}
+	}
+
+	allowedSpecies := make([]string, 0, len(rules.AllowedSpecies))
+	for _, code := range rules.AllowedSpecies {
+		code = strings.ToLower(strings.TrimSpace(code))
+		if slices.Contains(allowedSpecies, code) {
+			continue
+		}
+		if err := s.checkSpecies(ctx, code); err != nil {
+			return nil, err
+		}
+		allowedSpecies = append(allowedSpecies, code)
+	}
+	if rules.MinAgeMonths < 0 || rules.MinAgeMonths > maxAgeMonthsLimit || rules.MaxAgeMonths < 0 || rules.MaxAgeMonths > maxAgeMonthsLimit {
+		return nil, fmt.Errorf("%w: age limits must be between 0 and %d months", model.ErrBadRequest, maxAgeMonthsLimit)
+	}
+	if rules.MaxAgeMonths > 0 && rules.MinAgeMonths > rules.MaxAgeMonths {
+		return nil, fmt.Errorf("%w: min_age_months must not exceed max_age_months", model.ErrBadRequest)
+	}
+
+	return s.repository.UpsertContestEntryRules(ctx, &model.EntryRules{
+		ContestID:           contestID,
+		MaxEntriesPerUser:   rules.MaxEntriesPerUser,
+		MaxEntries:          rules.MaxEntries,
+		MaxPhotosPerEntry:   rules.MaxPhotosPerEntry,
+		MaxPhotoSizeMB:      photoSize,
+		VideoAllowed:        rules.VideoAllowed,
+		MaxVideoDurationSec: rules.MaxVideoDurationSec,
+		MaxVideoSizeMB:      videoSize,
+		RequiredFields:      requiredFields,
+		EntryDeadline:       rules.EntryDeadline,
+		RequireApproval:     rules.RequireApproval,
+		AllowedSpecies:      allowedSpecies,
+		MinAgeMonths:        rules.MinAgeMonths,
+		MaxAgeMonths:        rules.MaxAgeMonths,
+	})
+}
+
+// checkEntryOpen проверяет, что конкурс принимает заявки и файлы: этап и срок подачи
+func checkEntryOpen(contest *model.Contest, rules *model.EntryRules, now time.Time) error {
+	if contest.Status != model.ContestStatusDraft && contest.Status != model.ContestStatusRegistration {
+		return model.NewCodedError(model.ErrorForbidden, model.EntryRejectedClosed, "entries are only accepted during draft or registration")
+	}
+	if rules.EntryDeadline != nil && !now.Before(*rules.EntryDeadline) {
+		return model.NewCodedError(model.ErrorForbidden, model.EntryRejectedDeadline, "entry deadline has passed")
+	}
+	return nil
+}
+
+// checkRequiredFields проверяет заполненность обязательных полей заявки
+func checkRequiredFields(rules *model.EntryRules, petDescription string) error {
+	if slices.Contains(rules.RequiredFields, model.EntryFieldPetDescription) && strings.TrimSpace(petDescription) == "" {
+		return model.NewCodedError(model.ErrBadRequest, model.EntryRejectedFieldRequired, "pet_description is required in this contest")
+	}
+	return nil
+}
+
+// checkUploadSize сравнивает размер файла с лимитом в мегабайтах
+func checkUploadSize(upload model.MediaUpload, limitMB int) error {
+	if limitMB > 0 && upload.Size > int64(limitMB)<<20 {
+		return model.NewCodedError(model.ErrBadRequest, model.EntryRejectedFileTooLarge,
+			fmt.Sprintf("file is larger than %d MB", limitMB))
+	}
+	return nil
+}
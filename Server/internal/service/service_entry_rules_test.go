@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"toppet/server/internal/model"
+)
+
+func newEntryRulesTestService(status model.ContestStatus, rules *model.EntryRules) (*TopPetService, *mockRepository) {
+	mockRepo := &mockRepository{
+		entryRules: rules,
+		participants: map[model.ParticipantID]*model.Participant{
+			"cat": {ID: "cat", ContestID: "contest-id", UserID: 10},
+		},
+		getContestFunc: func(ctx context.Context, contestID model.ContestID) (*model.Contest, error) {
+			return &model.Contest{ID: contestID, CreatedByUserID: 1, Status: status}, nil
+		},
+	}
+	return &TopPetService{repository: mockRepo}, mockRepo
+}
+
+func entryRejectionCode(err error) string {
+	var coded *model.CodedError
+	if errors.As(err, &coded) {
+		return coded.Code
+	}
+	return ""
+}
+
+func TestTopPetService_UpdateEntryRules(t *testing.T) {
+	service, mockRepo := newEntryRulesTestService(model.ContestStatusRegistration, nil)
+	ctx := context.Background()
+
+	rules, err := service.GetEntryRules(ctx, "contest-id")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if rules.MaxPhotoSizeMB != defaultMaxPhotoSizeMB || rules.MaxVideoSizeMB != defaultMaxVideoSizeMB || !rules.VideoAllowed {
+		t.Errorf("Expected default rules, got %+v", rules)
+	}
+
+	if _, err := service.UpdateEntryRules(ctx, "contest-id", 2, &model.EntryRules{}); !errors.Is(err, model.ErrorForbidden) {
+		t.Errorf("Expected forbidden for non-organizer, got %v", err)
+	}
+	invalid := []*model.EntryRules{
+		{MaxEntries: -1},
+		{MaxPhotoSizeMB: model.MaxPhotoUploadMB + 1},
+		{MaxVideoSizeMB: -5},
+		{RequiredFields: []string{"pet_name"}},
+	}
+	for _, rules := range invalid {
+		if _, err := service.UpdateEntryRules(ctx, "contest-id", 1, rules); !errors.Is(err, model.ErrBadRequest) {
+			t.Errorf("Expected bad request for %+v, got %v", rules, err)
+		}
+	}
+
+	saved, err := service.UpdateEntryRules(ctx, "contest-id", 1, &model.EntryRules{
+		MaxEntries:     50,
+		RequiredFields: []string{" Pet_Description", "pet_description"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if saved.MaxPhotoSizeMB != defaultMaxPhotoSizeMB || len(saved.RequiredFields) != 1 || mockRepo.entryRules != saved {
+		t.Errorf("Expected normalized rules to be stored, got %+v", saved)
+	}
+}
+
+func TestTopPetService_CreateParticipantEntryRules(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name        string
+		status      model.ContestStatus
+		rules       *model.EntryRules
+		total, mine int64
+		description string
+		wantCode    string
+	}{
+		{name: "no rules", status: model.ContestStatusRegistration},
+		{name: "voting stage", status: model.ContestStatusVoting, wantCode: model.EntryRejectedClosed},
+		{name: "deadline passed", status: model.ContestStatusRegistration, rules: &model.EntryRules{EntryDeadline: &past}, wantCode: model.EntryRejectedDeadline},
+		{name: "contest full", status: model.ContestStatusRegistration, rules: &model.EntryRules{MaxEntries: 10}, total: 10, wantCode: model.EntryRejectedMaxEntries},
+		{name: "user limit", status: model.ContestStatusRegistration, rules: &model.EntryRules{MaxEntriesPerUser: 1}, total: 3, mine: 1, wantCode: model.EntryRejectedMaxUserEntries},
+		{name: "under limits", status: model.ContestStatusRegistration, rules: &model.EntryRules{MaxEntries: 10, MaxEntriesPerUser: 2}, total: 9, mine: 1},
+		{name: "description required", status: model.ContestStatusRegistration, rules: &model.EntryRules{RequiredFields: []string{model.EntryFieldPetDescription}}, wantCode: model.EntryRejectedFieldRequired},
+		{name: "description given", status: model.ContestStatusRegistration, rules: &model.EntryRules{RequiredFields: []string{model.EntryFieldPetDescription}}, description: "Рыжий кот"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, mockRepo := newEntryRulesTestService(tt.status, tt.rules)
+			mockRepo.participantCount = tt.total
+			mockRepo.userParticipantCount = tt.mine
+
+			_, err := service.CreateParticipant(context.Background(), "contest-id", 10, "", "Барсик", tt.description, model.PetAttributes{}, nil)
+			if tt.wantCode == "" && err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if code := entryRejectionCode(err); code != tt.wantCode {
+				t.Errorf("Expected code %q, got %v", tt.wantCode, err)
+			}
+		})
+	}
+}
+
+func TestTopPetService_AddParticipantMediaEntryRules(t *testing.T) {
+	ctx := context.Background()
+	service, mockRepo := newEntryRulesTestService(model.ContestStatusRegistration, &model.EntryRules{
+		MaxPhotosPerEntry:   2,
+		MaxPhotoSizeMB:      5,
+		VideoAllowed:        true,
+		MaxVideoDurationSec: 60,
+		MaxVideoSizeMB:      50,
+	})
+
+	if _, err := service.AddParticipantPhoto(ctx, "cat", 10, "url", nil, model.MediaUpload{Size: 6 << 20}); entryRejectionCode(err) != model.EntryRejectedFileTooLarge {
+		t.Errorf("Expected file_too_large, got %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := service.AddParticipantPhoto(ctx, "cat", 10, "url", nil, model.MediaUpload{Size: 1 << 20}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if _, err := service.AddParticipantPhoto(ctx, "cat", 10, "url", nil, model.MediaUpload{Size: 1 << 20}); entryRejectionCode(err) != model.EntryRejectedMaxPhotos {
+		t.Errorf("Expected max_photos_reached, got %v", err)
+	}
+
+	videos := []struct {
+		upload   model.MediaUpload
+		wantCode string
+	}{
+		{model.MediaUpload{Size: 10 << 20, Duration: 30 * time.Second}, ""},
+		{model.MediaUpload{Size: 60 << 20, Duration: 30 * time.Second}, model.EntryRejectedFileTooLarge},
+		{model.MediaUpload{Size: 10 << 20, Duration: 2 * time.Minute}, model.EntryRejectedVideoTooLong},
+		{model.MediaUpload{Size: 10 << 20}, model.EntryRejectedVideoTooLong},
+	}
+	for _, tt := range videos {
+		_, err := service.AddParticipantVideo(ctx, "cat", 10, "url", tt.upload)
+		if code := entryRejectionCode(err); code != tt.wantCode || (tt.wantCode == "" && err != nil) {
+			t.Errorf("Upload %+v: expected code %q, got %v", tt.upload, tt.wantCode, err)
+		}
+	}
+
+	mockRepo.entryRules.VideoAllowed = false
+	if _, err := service.AddParticipantVideo(ctx, "cat", 10, "url", model.MediaUpload{Size: 1 << 20}); entryRejectionCode(err) != model.EntryRejectedVideoNotAllowed {
+		t.Errorf("Expected video_not_allowed, got %v", err)
+	}
+}
@@ -0,0 +1,348 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"toppet/server/internal/model"
+)
+
+const (
+	maxJuryCriteria          = 20
+	maxJuryCriterionName     = 100
+	maxJuryCriterionDesc     = 500
+	maxJuryCriterionWeight   = 100
+	maxJuryScore             = 100
+	defaultJuryCriterionMax  = 10
+	maxJuryScoreCommentChars = 1000
+	maxJuryWeight            = 100
+)
+
+// isContestJuror - активный член жюри конкурса (роль juror в contest_members)
+func (s *TopPetService) isContestJuror(ctx context.Context, contest *model.Contest, userID model.UserID) bool {
+	return s.contestMemberRole(ctx, contest, userID) == model.ContestMemberJuror
+}
+
+// ListJuryCriteria возвращает критерии оценки жюри (публично)
+func (s *TopPetService) ListJuryCriteria(ctx context.Context, contestID model.ContestID) ([]*model.JuryCriterion, error) {
+	if _, err := s.GetContest(ctx, contestID); err != nil {
+		return nil, err
+	}
+	return s.repository.ListJuryCriteria(ctx, contestID)
+}
+
+// CreateJuryCriterion добавляет критерий оценки (владелец и организаторы, до завершения конкурса)
+func (s *TopPetService) CreateJuryCriterion(ctx context.Context, contestID model.ContestID, actorID model.UserID, criterion *model.JuryCriterion) (*model.JuryCriterion, error) {
+	contest, err := s.juryManagedContest(ctx, contestID, actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(criterion.Name)
+	if name == "" || utf8.RuneCountInString(name) > maxJuryCriterionName {
+		return nil, fmt.Errorf("%w: name must be 1-%d characters", model.ErrBadRequest, maxJuryCriterionName)
+	}
+	description := strings.TrimSpace(criterion.Description)
+	if utf8.RuneCountInString(description) > maxJuryCriterionDesc {
+		return nil, fmt.Errorf("%w: description must be at most %d characters", model.ErrBadRequest, maxJuryCriterionDesc)
+	}
+	weight := criterion.Weight
+	if weight == 0 {
+		weight = 1
+	}
+	if weight < 1 || weight > maxJuryCriterionWeight {
+		return nil, fmt.Errorf("%w: weight must be between 1 and %d", model.ErrBadRequest, maxJuryCriterionWeight)
+	}
+	maxScore := criterion.MaxScore
+	if maxScore == 0 {
+		maxScore = defaultJuryCriterionMax
+	}
+	if maxScore < 1 || maxScore > maxJuryScore {
+		return nil, fmt.Errorf("%w: max_score must be between 1 and %d", model.ErrBadRequest, maxJuryScore)
+	}
+
+	existing, err := s.repository.ListJuryCriteria(ctx, contest.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= maxJuryCriteria {
+		return nil, fmt.Errorf("%w: at most %d criteria per contest", model.ErrBadRequest, maxJuryCriteria)
+	}
+
+	return s.repository.CreateJuryCriterion(ctx, &model.JuryCriterion{
+		ContestID:   contest.ID,
+		Name:        name,
+		Description: description,
+		Weight:      weight,
+		MaxScore:    maxScore,
+	})
+}
+
+// DeleteJuryCriterion удаляет критерий вместе с выставленными по нему оценками
+func (s *TopPetService) DeleteJuryCriterion(ctx context.Context, contestID model.ContestID, actorID model.UserID, criterionID string) error {
+	if _, err := s.juryManagedContest(ctx, contestID, actorID); err != nil {
+		return err
+	}
+	return s.repository.DeleteJuryCriterion(ctx, contestID, criterionID)
+}
+
+// UpdateJuryWeight задает долю оценки жюри в итоге, 0-100%. При 0 итог определяет только голосование.
+func (s *TopPetService) UpdateJuryWeight(ctx context.Context, contestID model.ContestID, actorID model.UserID, juryWeight int) (*model.Contest, error) {
+	if _, err := s.juryManagedContest(ctx, contestID, actorID); err != nil {
+		return nil, err
+	}
+	if juryWeight < 0 || juryWeight > maxJuryWeight {
+		return nil, fmt.Errorf("%w: jury_weight must be between 0 and %d", model.ErrBadRequest, maxJuryWeight)
+	}
+	return s.repository.UpdateContestJuryWeight(ctx, contestID, juryWeight)
+}
+
+func (s *TopPetService) juryManagedContest(ctx context.Context, contestID model.ContestID, actorID model.UserID) (*model.Contest, error) {
+	contest, err := s.repository.GetContest(ctx, contestID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.CanManageContest(ctx, contest, actorID) {
+		return nil, model.ErrorForbidden
+	}
+	if contest.Status == model.ContestStatusFinished {
+		return nil, fmt.Errorf("%w: contest is finished", model.ErrBadRequest)
+	}
+	return contest, nil
+}
+
+// SubmitJuryScores сохраняет оценки члена жюри участнику. Оценивать можно во время голосования,
+// повторная отправка перезаписывает оценки по переданным критериям.
+func (s *TopPetService) SubmitJuryScores(ctx context.Context, contestID model.ContestID, participantID model.ParticipantID, jurorID model.UserID, scores []*model.JuryScore) ([]*model.JuryScore, error) {
+	contest, err := s.repository.GetContest(ctx, contestID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.isContestJuror(ctx, contest, jurorID) {
+		return nil, fmt.Errorf("%w: only jury members can score participants", model.ErrorForbidden)
+	}
+	if contest.Status != model.ContestStatusVoting {
+		return nil, fmt.Errorf("%w: jury scoring is only allowed during voting stage", model.ErrBadRequest)
+	}
+
+	participant, err := s.repository.GetParticipant(ctx, participantID)
+	if err != nil {
+		return nil, err
+	}
+	if participant.ContestID != contestID {
+		return nil, model.ErrorNotFound
+	}
+	if !isEntryPublic(participant) {
+		return nil, fmt.Errorf("%w: participant is not approved", model.ErrBadRequest)
+	}
+	if participant.UserID == jurorID {
+		return nil, fmt.Errorf("%w: jury members cannot score their own pets", model.ErrorForbidden)
+	}
+
+	if len(scores) == 0 {
+		return nil, fmt.Errorf("%w: scores are required", model.ErrBadRequest)
+	}
+	criteria, err := s.repository.ListJuryCriteria(ctx, contestID)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool, len(scores))
+	for _, score := range scores {
+		idx := slices.IndexFunc(criteria, func(c *model.JuryCriterion) bool { return c.ID == score.CriterionID })
+		if idx < 0 {
+			return nil, fmt.Errorf("%w: unknown criterion %q", model.ErrBadRequest, score.CriterionID)
+		}
+		if seen[score.CriterionID] {
+			return nil, fmt.Errorf("%w: duplicate criterion %q", model.ErrBadRequest, score.CriterionID)
+		}
+		seen[score.CriterionID] = true
+		if score.Score < 0 || score.Score > criteria[idx].MaxScore {
+			return nil, fmt.Errorf("%w: score for %q must be between 0 and %d", model.ErrBadRequest, criteria[idx].Name, criteria[idx].MaxScore)
+		}
+		score.Comment = strings.TrimSpace(score.Comment)
+		if utf8.RuneCountInString(score.Comment) > maxJuryScoreCommentChars {
+			return nil, fmt.Errorf("%w: comment must be at most %d characters", model.ErrBadRequest, maxJuryScoreCommentChars)
+		}
+	}
+
+	if err := s.repository.UpsertJuryScores(ctx, contestID, participantID, jurorID, scores); err != nil {
+		return nil, err
+	}
+
+	all, err := s.repository.ListJuryScores(ctx, contestID)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(all, func(score *model.JuryScore) bool {
+		return score.ParticipantID != participantID || score.JurorID != jurorID
+	}), nil
+}
+
+// ListJuryScores возвращает оценки жюри: организаторам и персоналу - все, члену жюри - только свои
+func (s *TopPetService) ListJuryScores(ctx context.Context, contestID model.ContestID, actorID model.UserID) ([]*model.JuryScore, error) {
+	contest, err := s.repository.GetContest(ctx, contestID)
+	if err != nil {
+		return nil, err
+	}
+
+	canSeeAll := s.CanManageContest(ctx, contest, actorID) || s.isStaff(ctx, actorID)
+	if !canSeeAll && !s.isContestJuror(ctx, contest, actorID) {
+		return nil, model.ErrorForbidden
+	}
+
+	scores, err := s.repository.ListJuryScores(ctx, contestID)
+	if err != nil {
+		return nil, err
+	}
+	if canSeeAll {
+		return scores, nil
+	}
+	return slices.DeleteFunc(scores, func(score *model.JuryScore) bool { return score.JurorID != actorID }), nil
+}
+
+// addJuryResults добавляет к итогам оценки жюри. Учитываются только оценки действующих членов жюри.
+func (s *TopPetService) addJuryResults(ctx context.Context, contest *model.Contest, results *model.ContestResults) error {
+	criteria, err := s.repository.ListJuryCriteria(ctx, contest.ID)
+	if err != nil {
+		return err
+	}
+	if len(criteria) == 0 && contest.JuryWeight == 0 {
+		return nil
+	}
+
+	members, err := s.repository.ListContestMembers(ctx, contest.ID)
+	if err != nil {
+		return err
+	}
+	jurors := make(map[model.UserID]bool)
+	for _, member := range members {
+		if member.Role == model.ContestMemberJuror && member.Status == model.ContestMemberActive {
+			jurors[member.UserID] = true
+		}
+	}
+
+	scores, err := s.repository.ListJuryScores(ctx, contest.ID)
+	if err != nil {
+		return err
+	}
+	scores = slices.DeleteFunc(scores, func(score *model.JuryScore) bool { return !jurors[score.JurorID] })
+
+	results.Jurors = len(jurors)
+	combineJuryResults(results, contest.JuryWeight, juryScores(criteria, scores))
+	return nil
+}
+
+// juryScores оценка жюри участника 0-100: по каждому критерию - среднее по членам жюри
+// от максимального балла, затем среднее по всем критериям с учетом их весов. Критерий,
+// по которому участника не оценили, считается за 0, чтобы частичная оценка не давала преимущества.
+func juryScores(criteria []*model.JuryCriterion, scores []*model.JuryScore) map[model.ParticipantID]float64 {
+	totalWeight := 0
+	for _, criterion := range criteria {
+		totalWeight += criterion.Weight
+	}
+	if totalWeight == 0 {
+		return map[model.ParticipantID]float64{}
+	}
+
+	type key struct {
+		participantID model.ParticipantID
+		criterionID   string
+	}
+	sums := make(map[key]float64)
+	counts := make(map[key]int)
+	for _, score := range scores {
+		k := key{score.ParticipantID, score.CriterionID}
+		sums[k] += float64(score.Score)
+		counts[k]++
+	}
+
+	weighted := make(map[model.ParticipantID]float64)
+	for k, sum := range sums {
+		idx := slices.IndexFunc(criteria, func(c *model.JuryCriterion) bool { return c.ID == k.criterionID })
+		if idx < 0 {
+			continue
+		}
+		criterion := criteria[idx]
+		weighted[k.participantID] += sum / float64(counts[k]) / float64(criterion.MaxScore) * float64(criterion.Weight)
+	}
+
+	result := make(map[model.ParticipantID]float64, len(weighted))
+	for participantID, value := range weighted {
+		result[participantID] = roundScore(value / float64(totalWeight) * 100)
+	}
+	return result
+}
+
+// combineJuryResults проставляет зрительскую, жюри и итоговую оценки. При juryWeight > 0
+// места и победитель пересчитываются по итоговой оценке.
+func combineJuryResults(results *model.ContestResults, juryWeight int, jury map[model.ParticipantID]float64) {
+	results.JuryWeight = juryWeight
+
+	var maxVotes int64
+	for _, item := range results.Items {
+		maxVotes = max(maxVotes, item.Votes)
+	}
+	for _, item := range results.Items {
+		public := publicScore(results, item, maxVotes)
+		item.PublicScore = &public
+		if value, ok := jury[item.ParticipantID]; ok {
+			item.JuryScore = &value
+		}
+		if juryWeight > 0 {
+			combined := roundScore(public*float64(100-juryWeight)/100 + jury[item.ParticipantID]*float64(juryWeight)/100)
+			item.CombinedScore = &combined
+		}
+	}
+	if juryWeight == 0 {
+		return
+	}
+
+	combinedOf := func(item *model.ParticipantResult) float64 { return *item.CombinedScore }
+	slices.SortStableFunc(results.Items, func(a, b *model.ParticipantResult) int {
+		return cmp.Or(cmp.Compare(combinedOf(b), combinedOf(a)), cmp.Compare(a.ParticipantID, b.ParticipantID))
+	})
+	results.WinnerID = ""
+	for i, item := range results.Items {
+		item.Place = 0
+		if combinedOf(item) == 0 {
+			continue
+		}
+		item.Place = i + 1
+		if i > 0 && results.Items[i-1].Place > 0 && combinedOf(results.Items[i-1]) == combinedOf(item) {
+			item.Place = results.Items[i-1].Place
+		}
+	}
+	if len(results.Items) > 0 && results.Items[0].Place == 1 &&
+		(len(results.Items) == 1 || results.Items[1].Place != 1) {
+		results.WinnerID = results.Items[0].ParticipantID
+	}
+}
+
+// publicScore зрительская оценка 0-100 по режиму голосования: доля от лидера (single, approval),
+// место после мгновенного второго тура (ranked) или средняя оценка (stars)
+func publicScore(results *model.ContestResults, item *model.ParticipantResult, maxVotes int64) float64 {
+	switch results.VotingMode {
+	case model.VotingModeRanked:
+		if item.Place == 0 {
+			return 0
+		}
+		if len(results.Items) == 1 {
+			return 100
+		}
+		return roundScore(float64(len(results.Items)-item.Place) / float64(len(results.Items)-1) * 100)
+	case model.VotingModeStars:
+		if !item.Qualified || item.AverageStars == nil {
+			return 0
+		}
+		return roundScore(*item.AverageStars / maxStars * 100)
+	default:
+		if maxVotes == 0 {
+			return 0
+		}
+		return roundScore(float64(item.Votes) / float64(maxVotes) * 100)
+	}
+}
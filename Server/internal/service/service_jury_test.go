@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"toppet/server/internal/model"
+)
+
+func TestJuryScores(t *testing.T) {
+	criteria := []*model.JuryCriterion{
+		{ID: "grooming", Weight: 3, MaxScore: 10},
+		{ID: "photo", Weight: 1, MaxScore: 5},
+	}
+	scores := []*model.JuryScore{
+		// a: груминг 8 и 10 (в среднем 90%), фото 5 (100%) -> (90*3 + 100) / 4 = 92.5
+		{CriterionID: "grooming", ParticipantID: "a", JurorID: 1, Score: 8},
+		{CriterionID: "grooming", ParticipantID: "a", JurorID: 2, Score: 10},
+		{CriterionID: "photo", ParticipantID: "a", JurorID: 1, Score: 5},
+		// b: только фото 1 из 5, груминг не оценен и считается за 0 -> (0*3 + 20) / 4 = 5
+		{CriterionID: "photo", ParticipantID: "b", JurorID: 2, Score: 1},
+		// c: только груминг 10 из 10 - частичная оценка не обгоняет полную -> (100*3 + 0) / 4 = 75
+		{CriterionID: "grooming", ParticipantID: "c", JurorID: 1, Score: 10},
+	}
+
+	got := juryScores(criteria, scores)
+	if got["a"] != 92.5 || got["b"] != 5 || got["c"] != 75 {
+		t.Errorf("Unexpected jury scores: %v", got)
+	}
+	if _, ok := got["d"]; ok {
+		t.Errorf("Participant without scores should have no jury score")
+	}
+}
+
+func TestCombineJuryResults(t *testing.T) {
+	contest := &model.Contest{ID: "contest-id"}
+	// Публика: a - 4 голоса, b - 2; жюри отдает предпочтение b
+	ballots := []*model.Ballot{
+		ballot("v1", "a"), ballot("v2", "a"), ballot("v3", "a"), ballot("v4", "a"),
+		ballot("v5", "b"), ballot("v6", "b"),
+	}
+	jury := map[model.ParticipantID]float64{"a": 20, "b": 100}
+
+	publicOnly := tallyResults(contest, []model.ParticipantID{"a", "b"}, ballots)
+	combineJuryResults(publicOnly, 0, jury)
+	if publicOnly.WinnerID != "a" || publicOnly.Items[0].CombinedScore != nil {
+		t.Errorf("Zero jury weight must keep public result, got %+v", publicOnly)
+	}
+	if b := resultByID(publicOnly, "b"); b.JuryScore == nil || *b.JuryScore != 100 || *b.PublicScore != 50 {
+		t.Errorf("Expected informational jury score for b, got %+v", b)
+	}
+
+	// a: 100*0.5 + 20*0.5 = 60, b: 50*0.5 + 100*0.5 = 75
+	combined := tallyResults(contest, []model.ParticipantID{"a", "b"}, ballots)
+	combineJuryResults(combined, 50, jury)
+	if combined.WinnerID != "b" || combined.JuryWeight != 50 {
+		t.Fatalf("Expected b to win with jury weight 50, got %+v", combined)
+	}
+	if a := resultByID(combined, "a"); a.CombinedScore == nil || *a.CombinedScore != 60 || a.Place != 2 {
+		t.Errorf("Unexpected combined result for a: %+v", a)
+	}
+}
+
+func TestTopPetService_SubmitJuryScores(t *testing.T) {
+	mockRepo := &mockRepository{
+		contestMembers: map[model.UserID]*model.ContestMember{
+			2: {UserID: 2, Role: model.ContestMemberJuror, Status: model.ContestMemberActive},
+			3: {UserID: 3, Role: model.ContestMemberJuror, Status: model.ContestMemberInvited},
+		},
+		participants: map[model.ParticipantID]*model.Participant{
+			"pet":     {ID: "pet", ContestID: "contest-id", UserID: 5},
+			"own-pet": {ID: "own-pet", ContestID: "contest-id", UserID: 2},
+		},
+		juryCriteria: []*model.JuryCriterion{{ID: "grooming", Name: "Груминг", Weight: 1, MaxScore: 10}},
+		getContestFunc: func(ctx context.Context, contestID model.ContestID) (*model.Contest, error) {
+			return &model.Contest{ID: contestID, CreatedByUserID: 1, Status: model.ContestStatusVoting}, nil
+		},
+	}
+	service := &TopPetService{repository: mockRepo}
+	ctx := context.Background()
+	score := func(criterionID string, value int) []*model.JuryScore {
+		return []*model.JuryScore{{CriterionID: criterionID, Score: value}}
+	}
+
+	if _, err := service.SubmitJuryScores(ctx, "contest-id", "pet", 1, score("grooming", 5)); !errors.Is(err, model.ErrorForbidden) {
+		t.Errorf("Expected forbidden for organizer who is not a juror, got %v", err)
+	}
+	if _, err := service.SubmitJuryScores(ctx, "contest-id", "pet", 3, score("grooming", 5)); !errors.Is(err, model.ErrorForbidden) {
+		t.Errorf("Expected forbidden for pending juror invitation, got %v", err)
+	}
+	if _, err := service.SubmitJuryScores(ctx, "contest-id", "own-pet", 2, score("grooming", 5)); !errors.Is(err, model.ErrorForbidden) {
+		t.Errorf("Expected forbidden for scoring own pet, got %v", err)
+	}
+	if _, err := service.SubmitJuryScores(ctx, "contest-id", "pet", 2, score("grooming", 11)); !errors.Is(err, model.ErrBadRequest) {
+		t.Errorf("Expected bad request for score above max, got %v", err)
+	}
+	if _, err := service.SubmitJuryScores(ctx, "contest-id", "pet", 2, score("cuteness", 5)); !errors.Is(err, model.ErrBadRequest) {
+		t.Errorf("Expected bad request for unknown criterion, got %v", err)
+	}
+
+	saved, err := service.SubmitJuryScores(ctx, "contest-id", "pet", 2, score("grooming", 9))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(saved) != 1 || saved[0].Score != 9 || saved[0].JurorID != 2 {
+		t.Errorf("Unexpected saved scores: %+v", saved)
+	}
+}
+
+func TestTopPetService_InviteJuror(t *testing.T) {
+	mockRepo := &mockRepository{
+		contestMembers: map[model.UserID]*model.ContestMember{
+			2: {UserID: 2, Role: model.ContestMemberOrganizer, Status: model.ContestMemberActive},
+		},
+		getContestFunc: func(ctx context.Context, contestID model.ContestID) (*model.Contest, error) {
+			return &model.Contest{ID: contestID, CreatedByUserID: 1}, nil
+		},
+	}
+	service := &TopPetService{repository: mockRepo}
+	ctx := context.Background()
+
+	if _, err := service.InviteContestMember(ctx, "contest-id", 2, 7, model.ContestMemberJuror); err != nil {
+		t.Errorf("Organizer should be able to appoint a juror, got %v", err)
+	}
+	if _, err := service.InviteContestMember(ctx, "contest-id", 2, 7, model.ContestMemberModerator); !errors.Is(err, model.ErrorForbidden) {
+		t.Errorf("Expected forbidden for organizer inviting a moderator, got %v", err)
+	}
+}
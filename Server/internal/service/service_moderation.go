@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	wsapp "toppet/server/internal/app/ws"
+	"toppet/server/internal/model"
+)
+
+const maxModerationReasonLength = 500
+
+// Moderate скрывает, возвращает или удаляет любой конкурс, участника, комментарий или сообщение чата.
+// Доступно администраторам и модераторам; каждое действие записывается в moderation_actions.
+func (s *TopPetService) Moderate(ctx context.Context, actorID model.UserID, action model.ModerationActionType, targetType model.ModerationTargetType, targetID string, reason string) (*model.ModerationAction, error) {
+	if !s.isStaff(ctx, actorID) {
+		return nil, fmt.Errorf("%w: moderator role required", model.ErrorForbidden)
+	}
+
+	switch action {
+	case model.ModerationActionHide, model.ModerationActionUnhide, model.ModerationActionDelete:
+	default:
+		return nil, fmt.Errorf("%w: invalid action %q", model.ErrBadRequest, action)
+	}
+
+	reason = strings.TrimSpace(reason)
+	if len([]rune(reason)) > maxModerationReasonLength {
+		return nil, fmt.Errorf("%w: reason is too long", model.ErrBadRequest)
+	}
+	if _, err := uuid.Parse(targetID); err != nil {
+		return nil, fmt.Errorf("%w: target_id must be a UUID", model.ErrBadRequest)
+	}
+
+	var err error
+	switch targetType {
+	case model.ModerationTargetContest:
+		_, err = s.repository.GetContest(ctx, model.ContestID(targetID))
+	case model.ModerationTargetParticipant:
+		_, err = s.repository.GetParticipant(ctx, model.ParticipantID(targetID))
+	case model.ModerationTargetComment:
+		_, err = s.repository.GetComment(ctx, model.CommentID(targetID))
+	case model.ModerationTargetChatMessage:
+	default:
+		return nil, fmt.Errorf("%w: invalid target_type %q", model.ErrBadRequest, targetType)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Действие и запись в журнал выполняются одной транзакцией
+	created, contestID, err := s.repository.ModerateContent(ctx, &model.ModerationAction{
+		ActorUserID: actorID,
+		Action:      action,
+		TargetType:  targetType,
+		TargetID:    targetID,
+		Reason:      reason,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Для клиентов чата скрытое сообщение выглядит как удаленное
+	if targetType == model.ModerationTargetChatMessage && s.hub != nil && action != model.ModerationActionUnhide {
+		payload := wsapp.MessageDeletedPayload{
+			Type:      wsapp.MessageTypeMessageDeleted,
+			ContestID: contestID,
+			MessageID: model.ChatMessageID(targetID),
+		}
+		_ = s.hub.BroadcastContestMessage(contestID, payload)
+	}
+
+	return created, nil
+}
+
+// ListModerationActions возвращает журнал модерации (только для персонала)
+func (s *TopPetService) ListModerationActions(ctx context.Context, actorID model.UserID, limit, offset int) ([]*model.ModerationAction, int64, error) {
+	if !s.isStaff(ctx, actorID) {
+		return nil, 0, fmt.Errorf("%w: moderator role required", model.ErrorForbidden)
+	}
+
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	return s.repository.ListModerationActions(ctx, limit, offset)
+}
+
+// GrantRole выдает роль пользователю (только администратор)
+func (s *TopPetService) GrantRole(ctx context.Context, actorID, userID model.UserID, role model.Role) error {
+	if err := s.checkRoleChange(ctx, actorID, userID, role); err != nil {
+		return err
+	}
+	return s.repository.AddUserRole(ctx, userID, role, actorID)
+}
+
+// RevokeRole отзывает роль у пользователя (только администратор). Снять роль admin с самого себя нельзя,
+// чтобы не остаться без администраторов.
+func (s *TopPetService) RevokeRole(ctx context.Context, actorID, userID model.UserID, role model.Role) error {
+	if err := s.checkRoleChange(ctx, actorID, userID, role); err != nil {
+		return err
+	}
+	if actorID == userID && role == model.RoleAdmin {
+		return fmt.Errorf("%w: cannot revoke own admin role", model.ErrBadRequest)
+	}
+	return s.repository.RemoveUserRole(ctx, userID, role)
+}
+
+func (s *TopPetService) checkRoleChange(ctx context.Context, actorID, userID model.UserID, role model.Role) error {
+	if !s.isAdmin(ctx, actorID) {
+		return fmt.Errorf("%w: admin role required", model.ErrorForbidden)
+	}
+	switch role {
+	case model.RoleAdmin, model.RoleModerator:
+	default:
+		return fmt.Errorf("%w: invalid role %q", model.ErrBadRequest, role)
+	}
+	if _, err := s.repository.GetUser(ctx, userID); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"toppet/server/internal/app/defenitions"
+	"toppet/server/internal/model"
+)
+
+// moderatedContestID ID конкурса в тестах модерации: target_id должен быть UUID
+const moderatedContestID = "6f1c1e9a-3b8e-4c5d-9f2a-7d4e8b1a2c3d"
+
+func TestTopPetService_Moderate(t *testing.T) {
+	tests := []struct {
+		name     string
+		actorID  model.UserID
+		action   model.ModerationActionType
+		targetID string
+		wantErr  error
+	}{
+		{name: "moderator hides contest", actorID: 2, action: model.ModerationActionHide, targetID: moderatedContestID},
+		{name: "regular user is forbidden", actorID: 3, action: model.ModerationActionHide, targetID: moderatedContestID, wantErr: model.ErrorForbidden},
+		{name: "invalid action", actorID: 2, action: "ban", targetID: moderatedContestID, wantErr: model.ErrBadRequest},
+		{name: "malformed target id", actorID: 2, action: model.ModerationActionHide, targetID: "contest-id", wantErr: model.ErrBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockRepository{
+				userRoles: map[model.UserID][]model.Role{2: {model.RoleModerator}},
+				getContestFunc: func(ctx context.Context, contestID model.ContestID) (*model.Contest, error) {
+					return &model.Contest{ID: contestID, CreatedByUserID: 1}, nil
+				},
+			}
+			service := &TopPetService{repository: mockRepo}
+
+			action, err := service.Moderate(context.Background(), tt.actorID, tt.action, model.ModerationTargetContest, tt.targetID, "spam")
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+				}
+				if len(mockRepo.moderationActions) != 0 {
+					t.Errorf("Expected no moderation actions to be recorded")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if action.ActorUserID != tt.actorID || action.Reason != "spam" {
+				t.Errorf("Unexpected action recorded: %+v", action)
+			}
+		})
+	}
+}
+
+func TestTopPetService_DeleteContestByModerator(t *testing.T) {
+	deleted := false
+	mockRepo := &mockRepository{
+		userRoles: map[model.UserID][]model.Role{2: {model.RoleModerator}},
+		getContestFunc: func(ctx context.Context, contestID model.ContestID) (*model.Contest, error) {
+			return &model.Contest{ID: contestID, CreatedByUserID: 1}, nil
+		},
+		deleteContestFunc: func(ctx context.Context, contestID model.ContestID) error {
+			deleted = true
+			return nil
+		},
+	}
+	service := &TopPetService{repository: mockRepo}
+
+	if err := service.DeleteContest(context.Background(), moderatedContestID, 2); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !deleted {
+		t.Errorf("Expected contest to be deleted")
+	}
+	if len(mockRepo.moderationActions) != 1 || mockRepo.moderationActions[0].Action != model.ModerationActionDelete {
+		t.Errorf("Expected delete to be recorded in moderation log, got %+v", mockRepo.moderationActions)
+	}
+}
+
+func TestTopPetService_GetHiddenContest(t *testing.T) {
+	mockRepo := &mockRepository{
+		getContestFunc: func(ctx context.Context, contestID model.ContestID) (*model.Contest, error) {
+			return &model.Contest{ID: contestID, CreatedByUserID: 1, Hidden: true}, nil
+		},
+	}
+	service := &TopPetService{repository: mockRepo}
+
+	if _, err := service.GetContest(context.Background(), "contest-id"); !errors.Is(err, model.ErrorNotFound) {
+		t.Errorf("Expected not found for anonymous viewer, got %v", err)
+	}
+
+	// Роли модератора берутся из контекста (claims access токена)
+	ctx := context.WithValue(context.Background(), defenitions.UserID, model.UserID(2))
+	ctx = context.WithValue(ctx, defenitions.Roles, []model.Role{model.RoleModerator})
+	if _, err := service.GetContest(ctx, "contest-id"); err != nil {
+		t.Errorf("Expected moderator to see hidden contest, got %v", err)
+	}
+}
+
+func TestTopPetService_HiddenContestListings(t *testing.T) {
+	mockRepo := &mockRepository{
+		getContestFunc: func(ctx context.Context, contestID model.ContestID) (*model.Contest, error) {
+			return &model.Contest{ID: contestID, CreatedByUserID: 1, Status: model.ContestStatusVoting, Hidden: true}, nil
+		},
+		participants: map[model.ParticipantID]*model.Participant{
+			"cat": {ID: "cat", ContestID: "contest-id", UserID: 10, ModerationStatus: model.EntryModerationApproved},
+		},
+		contestParticipants: []*model.Participant{{ID: "cat", ContestID: "contest-id", UserID: 10}},
+	}
+	service := &TopPetService{repository: mockRepo}
+
+	// Участники, комментарии и чат скрытого конкурса скрыты вместе с ним
+	ctx := context.Background()
+	if _, err := service.ListParticipantsByContest(ctx, "contest-id", model.ParticipantFilter{}); !errors.Is(err, model.ErrorNotFound) {
+		t.Errorf("Expected not found for participants, got %v", err)
+	}
+	if _, _, err := service.ListComments(ctx, "cat", 20, 0); !errors.Is(err, model.ErrorNotFound) {
+		t.Errorf("Expected not found for comments, got %v", err)
+	}
+	if _, _, err := service.ListChatMessages(ctx, "contest-id", 50, 0); !errors.Is(err, model.ErrorNotFound) {
+		t.Errorf("Expected not found for chat, got %v", err)
+	}
+
+	ctx = context.WithValue(ctx, defenitions.UserID, model.UserID(2))
+	ctx = context.WithValue(ctx, defenitions.Roles, []model.Role{model.RoleModerator})
+	if participants, err := service.ListParticipantsByContest(ctx, "contest-id", model.ParticipantFilter{}); err != nil || len(participants) != 1 {
+		t.Errorf("Expected moderator to see participants, got %v, %v", participants, err)
+	}
+	if _, _, err := service.ListComments(ctx, "cat", 20, 0); err != nil {
+		t.Errorf("Expected moderator to see comments, got %v", err)
+	}
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"log"
+
+	wsapp "toppet/server/internal/app/ws"
+	"toppet/server/internal/model"
+)
+
+// notificationExcerptLength сколько символов комментария сохраняется в уведомлении
+const notificationExcerptLength = 140
+
+func (s *TopPetService) ListNotifications(ctx context.Context, userID model.UserID, unreadOnly bool, limit, offset int) ([]*model.Notification, int64, int64, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	notifications, err := s.repository.ListNotifications(ctx, userID, unreadOnly, limit, offset)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	total, unread, err := s.repository.CountNotifications(ctx, userID)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if unreadOnly {
+		total = unread
+	}
+	return notifications, total, unread, nil
+}
+
+func (s *TopPetService) CountUnreadNotifications(ctx context.Context, userID model.UserID) (int64, error) {
+	_, unread, err := s.repository.CountNotifications(ctx, userID)
+	return unread, err
+}
+
+// MarkNotificationRead отмечает уведомление прочитанным и возвращает оставшееся число непрочитанных.
+// Остальные вкладки пользователя получают новый счетчик по WebSocket.
+func (s *TopPetService) MarkNotificationRead(ctx context.Context, userID model.UserID, notificationID string) (int64, error) {
+	if err := s.repository.MarkNotificationRead(ctx, userID, notificationID); err != nil {
+		return 0, err
+	}
+	return s.notificationsRead(ctx, userID, notificationID)
+}
+
+// MarkAllNotificationsRead отмечает прочитанными все уведомления пользователя
+func (s *TopPetService) MarkAllNotificationsRead(ctx context.Context, userID model.UserID) (int64, error) {
+	if _, err := s.repository.MarkAllNotificationsRead(ctx, userID); err != nil {
+		return 0, err
+	}
+	return s.notificationsRead(ctx, userID, "")
+}
+
+func (s *TopPetService) notificationsRead(ctx context.Context, userID model.UserID, notificationID string) (int64, error) {
+	unread, err := s.CountUnreadNotifications(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	if s.hub != nil {
+		_ = s.hub.SendUserMessage(userID, wsapp.NotificationsReadPayload{
+			Type:           wsapp.MessageTypeNotificationsRead,
+			NotificationID: notificationID,
+			UnreadCount:    unread,
+		})
+	}
+	return unread, nil
+}
+
+// notifyContestStatus уведомляет участников конкурса о начале голосования и об итогах: во входящих
+// и письмом. Telegram получает и остальные смены статуса, кроме возврата в черновик.
+func (s *TopPetService) notifyContestStatus(ctx context.Context, contest *model.Contest, actorID model.UserID) {
+	s.enqueueTelegramContestStatus(ctx, contest)
+
+	var notificationType model.NotificationType
+	switch contest.Status {
+	case model.ContestStatusVoting:
+		notificationType = model.NotificationVotingStarted
+	case model.ContestStatusFinished:
+		notificationType = model.NotificationResultsPublished
+	default:
+		return
+	}
+
+	participants, err := s.repository.ListParticipantsByContest(ctx, contest.ID)
+	if err != nil {
+		log.Printf("[Service] notifyContestStatus: contestID=%s: %v", contest.ID, err)
+		return
+	}
+	recipients := make([]model.UserID, 0, len(participants))
+	for _, participant := range participants {
+		recipients = append(recipients, participant.UserID)
+	}
+
+	s.notify(ctx, recipients, &model.Notification{
+		Type:        notificationType,
+		ContestID:   contest.ID,
+		ActorUserID: &actorID,
+		Title:       contest.Title,
+	})
+	s.enqueueContestEmails(ctx, contest, notificationType, uniqueRecipients(recipients, &actorID))
+}
+
+// notifyComment уведомляет владельца заявки о новом комментарии, а остальных комментаторов заявки - о продолжении обсуждения
+func (s *TopPetService) notifyComment(ctx context.Context, participant *model.Participant, comment *model.Comment) {
+	notification := model.Notification{
+		ContestID:     participant.ContestID,
+		ParticipantID: participant.ID,
+		CommentID:     comment.ID,
+		ActorUserID:   &comment.UserID,
+		Title:         participant.PetName,
+		Text:          excerpt(comment.Text, notificationExcerptLength),
+	}
+
+	owner := notification
+	owner.Type = model.NotificationEntryComment
+	s.notify(ctx, []model.UserID{participant.UserID}, &owner)
+
+	commenters, err := s.repository.ListParticipantCommenters(ctx, participant.ID)
+	if err != nil {
+		log.Printf("[Service] notifyComment: participantID=%s: %v", participant.ID, err)
+		return
+	}
+	others := make([]model.UserID, 0, len(commenters))
+	for _, userID := range commenters {
+		if userID != participant.UserID {
+			others = append(others, userID)
+		}
+	}
+	discussion := notification
+	discussion.Type = model.NotificationEntryDiscussion
+	s.notify(ctx, others, &discussion)
+}
+
+// notify сохраняет уведомление для получателей (без автора события и повторов) и доставляет его
+// в активные соединения. Ошибки только логируются: уведомления не должны ломать основное действие.
+func (s *TopPetService) notify(ctx context.Context, recipients []model.UserID, notification *model.Notification) {
+	userIDs := uniqueRecipients(recipients, notification.ActorUserID)
+	if len(userIDs) == 0 {
+		return
+	}
+
+	created, err := s.repository.CreateNotifications(ctx, userIDs, notification)
+	if err != nil {
+		log.Printf("[Service] notify: type=%s, recipients=%d: %v", notification.Type, len(userIDs), err)
+		return
+	}
+	if s.hub == nil {
+		return
+	}
+	for _, n := range created {
+		_ = s.hub.SendUserMessage(n.UserID, wsapp.NotificationPayload{
+			Type:         wsapp.MessageTypeNotification,
+			Notification: n,
+		})
+	}
+}
+
+// uniqueRecipients убирает повторы и автора события actorID (если задан), сохраняя порядок
+func uniqueRecipients(recipients []model.UserID, actorID *model.UserID) []model.UserID {
+	seen := make(map[model.UserID]struct{}, len(recipients))
+	userIDs := make([]model.UserID, 0, len(recipients))
+	for _, userID := range recipients {
+		if _, ok := seen[userID]; ok {
+			continue
+		}
+		seen[userID] = struct{}{}
+		if actorID != nil && userID == *actorID {
+			continue
+		}
+		userIDs = append(userIDs, userID)
+	}
+	return userIDs
+}
+
+// excerpt обрезает текст до limit символов
+func excerpt(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit]) + "…"
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	wsapp "toppet/server/internal/app/ws"
+	"toppet/server/internal/model"
+)
+
+func notificationsOf(notifications []*model.Notification, userID model.UserID) []*model.Notification {
+	var result []*model.Notification
+	for _, n := range notifications {
+		if n.UserID == userID {
+			result = append(result, n)
+		}
+	}
+	return result
+}
+
+func TestTopPetService_ContestStatusNotifications(t *testing.T) {
+	contest := &model.Contest{ID: "contest-id", CreatedByUserID: 1, Title: "Самый пушистый", Status: model.ContestStatusRegistration}
+	mockRepo := &mockRepository{
+		getContestFunc: func(ctx context.Context, contestID model.ContestID) (*model.Contest, error) {
+			copied := *contest
+			return &copied, nil
+		},
+		updateContestStatusFunc: func(ctx context.Context, contestID model.ContestID, status model.ContestStatus) (*model.Contest, error) {
+			contest.Status = status
+			copied := *contest
+			return &copied, nil
+		},
+		contestParticipants: []*model.Participant{
+			{ID: "p1", UserID: 10, ModerationStatus: model.EntryModerationApproved},
+			{ID: "p2", UserID: 10, ModerationStatus: model.EntryModerationApproved},
+			{ID: "p3", UserID: 11, ModerationStatus: model.EntryModerationApproved},
+			{ID: "p5", UserID: 1, ModerationStatus: model.EntryModerationApproved},
+		},
+	}
+	hub := &recordingHub{}
+	service := &TopPetService{repository: mockRepo, hub: hub}
+	ctx := context.Background()
+
+	if _, err := service.UpdateContestStatus(ctx, "contest-id", 1, model.ContestStatusVoting); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// Один раз на участника, без самого организатора
+	if len(mockRepo.notifications) != 2 {
+		t.Fatalf("Expected 2 notifications, got %d", len(mockRepo.notifications))
+	}
+	for _, userID := range []model.UserID{10, 11} {
+		got := notificationsOf(mockRepo.notifications, userID)
+		if len(got) != 1 || got[0].Type != model.NotificationVotingStarted || got[0].Title != "Самый пушистый" {
+			t.Errorf("User %d: expected voting_started notification, got %+v", userID, got)
+		}
+		if len(hub.personalMessages[userID]) != 1 {
+			t.Errorf("User %d: expected real-time delivery, got %v", userID, hub.personalMessages[userID])
+		} else if payload, ok := hub.personalMessages[userID][0].(wsapp.NotificationPayload); !ok || payload.Type != wsapp.MessageTypeNotification {
+			t.Errorf("User %d: unexpected payload %#v", userID, hub.personalMessages[userID][0])
+		}
+	}
+
+	// Повторная установка того же статуса уведомлений не создает
+	if _, err := service.UpdateContestStatus(ctx, "contest-id", 1, model.ContestStatusVoting); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(mockRepo.notifications) != 2 {
+		t.Errorf("Expected no notifications for unchanged status, got %d", len(mockRepo.notifications))
+	}
+
+	if _, err := service.FinishContest(ctx, "contest-id", 1); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := notificationsOf(mockRepo.notifications, 11); len(got) != 2 || got[1].Type != model.NotificationResultsPublished {
+		t.Errorf("Expected results_published notification, got %+v", got)
+	}
+}
+
+func TestTopPetService_CommentNotifications(t *testing.T) {
+	mockRepo := &mockRepository{
+		participants: map[model.ParticipantID]*model.Participant{
+			"cat": {ID: "cat", ContestID: "contest-id", UserID: 10, PetName: "Мурка"},
+		},
+		getContestFunc: func(ctx context.Context, contestID model.ContestID) (*model.Contest, error) {
+			return &model.Contest{ID: contestID, CreatedByUserID: 1, Status: model.ContestStatusVoting}, nil
+		},
+	}
+	service := &TopPetService{repository: mockRepo, hub: &recordingHub{}}
+	ctx := context.Background()
+
+	// Владелец отвечает под своей заявкой - уведомлять некого
+	if _, err := service.CreateComment(ctx, "cat", 10, "Спасибо!"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(mockRepo.notifications) != 0 {
+		t.Errorf("Expected no notifications for own comment, got %+v", mockRepo.notifications)
+	}
+
+	if _, err := service.CreateComment(ctx, "cat", 20, "Красавица"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	owner := notificationsOf(mockRepo.notifications, 10)
+	if len(owner) != 1 || owner[0].Type != model.NotificationEntryComment || owner[0].Title != "Мурка" || owner[0].Text != "Красавица" {
+		t.Fatalf("Expected entry_comment for owner, got %+v", owner)
+	}
+	if owner[0].ActorUserID == nil || *owner[0].ActorUserID != 20 {
+		t.Errorf("Expected actor 20, got %v", owner[0].ActorUserID)
+	}
+
+	long := strings.Repeat("я", notificationExcerptLength+10)
+	if _, err := service.CreateComment(ctx, "cat", 30, long); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	discussion := notificationsOf(mockRepo.notifications, 20)
+	if len(discussion) != 1 || discussion[0].Type != model.NotificationEntryDiscussion {
+		t.Fatalf("Expected entry_discussion for previous commenter, got %+v", discussion)
+	}
+	if got := []rune(discussion[0].Text); len(got) != notificationExcerptLength+1 {
+		t.Errorf("Expected excerpt of %d runes plus ellipsis, got %d", notificationExcerptLength, len(got))
+	}
+	if len(notificationsOf(mockRepo.notifications, 30)) != 0 {
+		t.Errorf("Expected no notification for the comment author")
+	}
+}
+
+func TestTopPetService_MarkNotificationsRead(t *testing.T) {
+	mockRepo := &mockRepository{}
+	hub := &recordingHub{}
+	service := &TopPetService{repository: mockRepo, hub: hub}
+	ctx := context.Background()
+
+	service.notify(ctx, []model.UserID{10, 11}, &model.Notification{Type: model.NotificationVotingStarted, ContestID: "a"})
+	service.notify(ctx, []model.UserID{10}, &model.Notification{Type: model.NotificationResultsPublished, ContestID: "a"})
+
+	items, total, unread, err := service.ListNotifications(ctx, 10, false, 0, 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(items) != 2 || total != 2 || unread != 2 {
+		t.Fatalf("Expected 2 unread notifications, got %d items, total %d, unread %d", len(items), total, unread)
+	}
+
+	// Чужое уведомление отметить нельзя
+	other := notificationsOf(mockRepo.notifications, 11)[0]
+	if _, err := service.MarkNotificationRead(ctx, 10, other.ID); !errors.Is(err, model.ErrorNotFound) {
+		t.Errorf("Expected not found for another user's notification, got %v", err)
+	}
+
+	left, err := service.MarkNotificationRead(ctx, 10, items[0].ID)
+	if err != nil || left != 1 {
+		t.Fatalf("Expected 1 unread left, got %d, %v", left, err)
+	}
+	if payloads := hub.personalMessages[10]; len(payloads) == 0 {
+		t.Errorf("Expected read state synced to other connections")
+	} else if payload, ok := payloads[len(payloads)-1].(wsapp.NotificationsReadPayload); !ok || payload.UnreadCount != 1 || payload.NotificationID != items[0].ID {
+		t.Errorf("Unexpected read payload %#v", payloads[len(payloads)-1])
+	}
+
+	left, err = service.MarkAllNotificationsRead(ctx, 10)
+	if err != nil || left != 0 {
+		t.Fatalf("Expected all read, got %d, %v", left, err)
+	}
+	if unread, _ := service.CountUnreadNotifications(ctx, 11); unread != 1 {
+		t.Errorf("Expected other user's notifications untouched, got %d unread", unread)
+	}
+}
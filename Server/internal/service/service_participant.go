@@ -3,18 +3,47 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log"
+	"slices"
+	"time"
 
 	"toppet/server/internal/model"
 )
 
-func (s *TopPetService) CreateParticipant(ctx context.Context, contestID model.ContestID, userID model.UserID, petName, petDescription string) (*model.Participant, error) {
-	log.Printf("[Service] CreateParticipant: contestID=%s, userID=%d, petName=%s", contestID, userID, petName)
-	
+// CreateParticipant подает заявку в конкурс. С petID заявка подается от профиля питомца: пустые имя, описание и признаки
+// берутся из профиля, а petPhotoIDs - фото из галереи питомца для этой заявки. Без petID профиль создается автоматически.
+func (s *TopPetService) CreateParticipant(ctx context.Context, contestID model.ContestID, userID model.UserID, petID model.PetID, petName, petDescription string, attrs model.PetAttributes, petPhotoIDs []string) (*model.Participant, error) {
+	log.Printf("[Service] CreateParticipant: contestID=%s, userID=%d, petID=%s, petName=%s", contestID, userID, petID, petName)
+
+	var pet *model.Pet
+	if petID != "" {
+		var err error
+		if pet, err = s.ownedPet(ctx, petID, userID); err != nil {
+			return nil, err
+		}
+		if petName == "" {
+			petName = pet.Name
+		}
+		if petDescription == "" {
+			petDescription = pet.Bio
+		}
+		attrs = mergePetAttributes(attrs, pet)
+	} else if len(petPhotoIDs) > 0 {
+		return nil, fmt.Errorf("%w: photo_ids require pet_id", model.ErrBadRequest)
+	}
+
 	if petName == "" {
 		log.Printf("[Service] CreateParticipant: ERROR - pet_name is required")
 		return nil, errors.New("pet_name is required")
 	}
+	now := time.Now()
+	if err := normalizePetAttributes(&attrs, now); err != nil {
+		return nil, err
+	}
+	if err := s.checkTaxonomy(ctx, &attrs); err != nil {
+		return nil, err
+	}
 
 	// Check contest exists and is not finished
 	log.Printf("[Service] CreateParticipant: Checking contest %s", contestID)
@@ -25,20 +54,78 @@ func (s *TopPetService) CreateParticipant(ctx context.Context, contestID model.C
 	}
 	log.Printf("[Service] CreateParticipant: Contest found: status=%s", contest.Status)
 
-	if contest.Status != model.ContestStatusDraft && contest.Status != model.ContestStatusRegistration {
-		log.Printf("[Service] CreateParticipant: ERROR - Contest status does not allow adding participants")
-		return nil, errors.New("can only add participants in draft or registration status")
+	rules, err := s.entryRules(ctx, contestID)
+	if err != nil {
+		log.Printf("[Service] CreateParticipant: ERROR - Failed to get entry rules: %v", err)
+		return nil, err
+	}
+	if err := checkEntryOpen(contest, rules, now); err != nil {
+		log.Printf("[Service] CreateParticipant: ERROR - Contest does not accept entries: %v", err)
+		return nil, err
+	}
+	if err := checkRequiredFields(rules, petDescription); err != nil {
+		return nil, err
+	}
+	if err := checkPetRestrictions(rules, attrs, now); err != nil {
+		return nil, err
+	}
+	selectedPhotos, err := s.selectPetPhotos(ctx, petID, petPhotoIDs)
+	if err != nil {
+		return nil, err
+	}
+	if rules.MaxPhotosPerEntry > 0 && len(selectedPhotos) > rules.MaxPhotosPerEntry {
+		return nil, model.NewCodedError(model.ErrBadRequest, model.EntryRejectedMaxPhotos,
+			fmt.Sprintf("an entry can have at most %d photos", rules.MaxPhotosPerEntry))
+	}
+	// Лимиты окончательно проверяет вставка заявки; ранняя проверка не создает лишний профиль питомца
+	if rules.MaxEntries > 0 {
+		count, err := s.repository.CountParticipantsByContest(ctx, contestID)
+		if err != nil {
+			return nil, err
+		}
+		if count >= int64(rules.MaxEntries) {
+			return nil, model.NewCodedError(model.ErrorForbidden, model.EntryRejectedMaxEntries, "contest has reached the maximum number of entries")
+		}
+	}
+	if rules.MaxEntriesPerUser > 0 {
+		count, err := s.repository.CountParticipantsByContestAndUser(ctx, contestID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if count >= int64(rules.MaxEntriesPerUser) {
+			return nil, model.NewCodedError(model.ErrorForbidden, model.EntryRejectedMaxUserEntries,
+				fmt.Sprintf("you can submit at most %d entries to this contest", rules.MaxEntriesPerUser))
+		}
 	}
 
 	// Create participant
 	log.Printf("[Service] CreateParticipant: Creating participant in repository")
-	participant, err := s.repository.CreateParticipant(ctx, contestID, userID, petName, petDescription)
+	// При премодерации заявка не появится в публичном списке до одобрения
+	status := model.EntryModerationApproved
+	if rules.RequireApproval {
+		status = model.EntryModerationPending
+	}
+	if pet == nil {
+		if pet, err = s.repository.CreatePet(ctx, &model.Pet{OwnerUserID: userID, Name: petName, PetAttributes: attrs, Bio: petDescription}); err != nil {
+			log.Printf("[Service] CreateParticipant: ERROR - Failed to create pet profile: %v", err)
+			return nil, err
+		}
+	}
+	participant, err := s.repository.CreateParticipant(ctx, contestID, userID, pet.ID, petName, petDescription, attrs, status, rules.MaxEntries, rules.MaxEntriesPerUser)
 	if err != nil {
 		log.Printf("[Service] CreateParticipant: ERROR - Failed to create participant in repository: %v", err)
 		return nil, err
 	}
 	log.Printf("[Service] CreateParticipant: Participant created successfully: participantID=%s", participant.ID)
 
+	// Выбранные фото галереи копируются в заявку: дальше заявка живет своей жизнью
+	for _, photo := range selectedPhotos {
+		if _, err := s.repository.AddParticipantPhoto(ctx, participant.ID, photo.URL, photo.ThumbURL); err != nil {
+			log.Printf("[Service] CreateParticipant: ERROR - Failed to copy pet photo %s: %v", photo.ID, err)
+			return nil, err
+		}
+	}
+
 	// Load photos and video
 	log.Printf("[Service] CreateParticipant: Loading photos and video for participant %s", participant.ID)
 	photos, _ := s.repository.GetPhotosByParticipantID(ctx, participant.ID)
@@ -51,6 +138,10 @@ func (s *TopPetService) CreateParticipant(ctx context.Context, contestID model.C
 		log.Printf("[Service] CreateParticipant: Loaded video: videoID=%s", video.ID)
 	}
 
+	if status == model.EntryModerationApproved {
+		s.emitParticipantCreatedWebhook(ctx, participant)
+	}
+
 	log.Printf("[Service] CreateParticipant: Successfully created participant %s", participant.ID)
 	return participant, nil
 }
@@ -60,6 +151,12 @@ func (s *TopPetService) GetParticipant(ctx context.Context, participantID model.
 	if err != nil {
 		return nil, err
 	}
+	if participant.Hidden && !s.canViewHidden(ctx) {
+		return nil, model.ErrorNotFound
+	}
+	if !s.canViewEntry(ctx, participant) {
+		return nil, model.ErrorNotFound
+	}
 
 	// Load photos and video
 	photos, _ := s.repository.GetPhotosByParticipantID(ctx, participantID)
@@ -71,10 +168,7 @@ func (s *TopPetService) GetParticipant(ctx context.Context, participantID model.
 	}
 
 	// Add total votes count
-	totalVotes, err := s.repository.CountVotesByParticipant(ctx, participantID)
-	if err == nil {
-		participant.TotalVotes = totalVotes
-	}
+	s.fillParticipantVotes(ctx, s.participantVotingMode(ctx, participant.ContestID), participant)
 
 	return participant, nil
 }
@@ -84,6 +178,12 @@ func (s *TopPetService) GetParticipantWithLikes(ctx context.Context, participant
 	if err != nil {
 		return nil, err
 	}
+	if participant.Hidden && !s.canViewHidden(ctx) {
+		return nil, model.ErrorNotFound
+	}
+	if !s.canViewEntry(ctx, participant) {
+		return nil, model.ErrorNotFound
+	}
 
 	// Load photos and video
 	photos, _ := s.repository.GetPhotosByParticipantID(ctx, participantID)
@@ -125,21 +225,30 @@ func (s *TopPetService) GetParticipantWithLikes(ctx context.Context, participant
 	}
 
 	// Add total votes count
-	totalVotes, err := s.repository.CountVotesByParticipant(ctx, participantID)
-	if err == nil {
-		participant.TotalVotes = totalVotes
-	}
+	s.fillParticipantVotes(ctx, s.participantVotingMode(ctx, participant.ContestID), participant)
 
 	return participant, nil
 }
 
-func (s *TopPetService) ListParticipantsByContest(ctx context.Context, contestID model.ContestID) ([]*model.Participant, error) {
+// ListParticipantsByContest публичный список участников конкурса, отфильтрованный по виду, породе, полу и возрасту
+func (s *TopPetService) ListParticipantsByContest(ctx context.Context, contestID model.ContestID, filter model.ParticipantFilter) ([]*model.Participant, error) {
+	if _, err := s.visibleContest(ctx, contestID); err != nil {
+		return nil, err
+	}
+
 	participants, err := s.repository.ListParticipantsByContest(ctx, contestID)
 	if err != nil {
 		return nil, err
 	}
+	if filter != (model.ParticipantFilter{}) {
+		now := time.Now()
+		participants = slices.DeleteFunc(participants, func(p *model.Participant) bool {
+			return !matchesParticipantFilter(p, filter, now)
+		})
+	}
 
 	// Load photos, videos, and vote counts for each participant
+	mode := s.participantVotingMode(ctx, contestID)
 	for _, p := range participants {
 		photos, _ := s.repository.GetPhotosByParticipantID(ctx, p.ID)
 		p.Photos = photos
@@ -149,14 +258,15 @@ func (s *TopPetService) ListParticipantsByContest(ctx context.Context, contestID
 			p.Video = video
 		}
 
-		totalVotes, _ := s.repository.CountVotesByParticipant(ctx, p.ID)
-		p.TotalVotes = totalVotes
+		s.fillParticipantVotes(ctx, mode, p)
 	}
 
 	return participants, nil
 }
 
-func (s *TopPetService) UpdateParticipant(ctx context.Context, participantID model.ParticipantID, userID model.UserID, petName, petDescription string) (*model.Participant, error) {
+// UpdateParticipant меняет имя, описание и признаки питомца в заявке. Признаки проверяются
+// по ограничениям конкурса так же, как при подаче заявки.
+func (s *TopPetService) UpdateParticipant(ctx context.Context, participantID model.ParticipantID, userID model.UserID, petName, petDescription string, attrs model.PetAttributes) (*model.Participant, error) {
 	log.Printf("[Service] UpdateParticipant: participantID=%s, userID=%d", participantID, userID)
 	
 	participant, err := s.repository.GetParticipant(ctx, participantID)
@@ -186,14 +296,36 @@ func (s *TopPetService) UpdateParticipant(ctx context.Context, participantID mod
 		return nil, errors.New("can only update participant in draft or registration status")
 	}
 
+	rules, err := s.entryRules(ctx, participant.ContestID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkRequiredFields(rules, petDescription); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if err := normalizePetAttributes(&attrs, now); err != nil {
+		return nil, err
+	}
+	if err := s.checkTaxonomy(ctx, &attrs); err != nil {
+		return nil, err
+	}
+	if err := checkPetRestrictions(rules, attrs, now); err != nil {
+		return nil, err
+	}
+
 	log.Printf("[Service] UpdateParticipant: Updating participant in repository")
-	updated, err := s.repository.UpdateParticipant(ctx, participantID, petName, petDescription)
+	updated, err := s.repository.UpdateParticipant(ctx, participantID, petName, petDescription, attrs)
 	if err != nil {
 		log.Printf("[Service] UpdateParticipant: ERROR - Failed to update participant: %v", err)
 		return nil, err
 	}
 	log.Printf("[Service] UpdateParticipant: Participant updated successfully: participantID=%s", updated.ID)
 
+	if err := s.resubmitEntry(ctx, updated, rules); err != nil {
+		return nil, err
+	}
+
 	// Load photos and video
 	log.Printf("[Service] UpdateParticipant: Loading photos and video for participant %s", updated.ID)
 	photos, _ := s.repository.GetPhotosByParticipantID(ctx, updated.ID)
@@ -209,48 +341,126 @@ func (s *TopPetService) UpdateParticipant(ctx context.Context, participantID mod
 	return updated, nil
 }
 
-func (s *TopPetService) AddParticipantPhoto(ctx context.Context, participantID model.ParticipantID, userID model.UserID, url string, thumbURL *string) (*model.Photo, error) {
-	participant, err := s.repository.GetParticipant(ctx, participantID)
+// CheckParticipantPhotoUpload проверяет правила конкурса до загрузки фото в хранилище,
+// чтобы отклоненный файл не оставался в бакете
+func (s *TopPetService) CheckParticipantPhotoUpload(ctx context.Context, participantID model.ParticipantID, userID model.UserID, upload model.MediaUpload) error {
+	_, _, err := s.checkParticipantPhoto(ctx, participantID, userID, upload)
+	return err
+}
+
+func (s *TopPetService) AddParticipantPhoto(ctx context.Context, participantID model.ParticipantID, userID model.UserID, url string, thumbURL *string, upload model.MediaUpload) (*model.Photo, error) {
+	participant, rules, err := s.checkParticipantPhoto(ctx, participantID, userID, upload)
 	if err != nil {
 		return nil, err
 	}
 
+	photo, err := s.repository.AddParticipantPhoto(ctx, participantID, url, thumbURL)
+	if err != nil {
+		return nil, err
+	}
+	// Новое фото проверяется заново, даже если заявка уже была одобрена
+	if err := s.resubmitEntry(ctx, participant, rules); err != nil {
+		return nil, err
+	}
+	return photo, nil
+}
+
+// checkParticipantPhoto проверяет права и правила конкурса для нового фото заявки
+func (s *TopPetService) checkParticipantPhoto(ctx context.Context, participantID model.ParticipantID, userID model.UserID, upload model.MediaUpload) (*model.Participant, *model.EntryRules, error) {
+	participant, err := s.repository.GetParticipant(ctx, participantID)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	// Only owner can add photos
 	if participant.UserID != userID {
-		return nil, errors.New("only participant owner can add photos")
+		return nil, nil, errors.New("only participant owner can add photos")
 	}
 
 	contest, err := s.repository.GetContest(ctx, participant.ContestID)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	if contest.Status != model.ContestStatusDraft && contest.Status != model.ContestStatusRegistration {
-		return nil, errors.New("can only add photos during draft or registration")
+	rules, err := s.entryRules(ctx, contest.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := checkEntryOpen(contest, rules, time.Now()); err != nil {
+		return nil, nil, err
+	}
+	if err := checkUploadSize(upload, rules.MaxPhotoSizeMB); err != nil {
+		return nil, nil, err
+	}
+	if rules.MaxPhotosPerEntry > 0 {
+		count, err := s.repository.CountPhotosByParticipant(ctx, participantID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if count >= int64(rules.MaxPhotosPerEntry) {
+			return nil, nil, model.NewCodedError(model.ErrBadRequest, model.EntryRejectedMaxPhotos,
+				fmt.Sprintf("an entry can have at most %d photos", rules.MaxPhotosPerEntry))
+		}
 	}
+	return participant, rules, nil
+}
 
-	return s.repository.AddParticipantPhoto(ctx, participantID, url, thumbURL)
+// CheckParticipantVideoUpload проверяет правила конкурса до загрузки видео в хранилище
+func (s *TopPetService) CheckParticipantVideoUpload(ctx context.Context, participantID model.ParticipantID, userID model.UserID, upload model.MediaUpload) error {
+	_, _, err := s.checkParticipantVideo(ctx, participantID, userID, upload)
+	return err
 }
 
-func (s *TopPetService) AddParticipantVideo(ctx context.Context, participantID model.ParticipantID, userID model.UserID, url string) (*model.Video, error) {
-	participant, err := s.repository.GetParticipant(ctx, participantID)
+func (s *TopPetService) AddParticipantVideo(ctx context.Context, participantID model.ParticipantID, userID model.UserID, url string, upload model.MediaUpload) (*model.Video, error) {
+	participant, rules, err := s.checkParticipantVideo(ctx, participantID, userID, upload)
 	if err != nil {
 		return nil, err
 	}
 
+	video, err := s.repository.UpsertParticipantVideo(ctx, participantID, url)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.resubmitEntry(ctx, participant, rules); err != nil {
+		return nil, err
+	}
+	return video, nil
+}
+
+// checkParticipantVideo проверяет права и правила конкурса для видео заявки
+func (s *TopPetService) checkParticipantVideo(ctx context.Context, participantID model.ParticipantID, userID model.UserID, upload model.MediaUpload) (*model.Participant, *model.EntryRules, error) {
+	participant, err := s.repository.GetParticipant(ctx, participantID)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	// Only owner can add video
 	if participant.UserID != userID {
-		return nil, errors.New("only participant owner can add video")
+		return nil, nil, errors.New("only participant owner can add video")
 	}
 
 	contest, err := s.repository.GetContest(ctx, participant.ContestID)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	if contest.Status != model.ContestStatusDraft && contest.Status != model.ContestStatusRegistration {
-		return nil, errors.New("can only add videos during draft or registration")
+	rules, err := s.entryRules(ctx, contest.ID)
+	if err != nil {
+		return nil, nil, err
 	}
-
-	return s.repository.UpsertParticipantVideo(ctx, participantID, url)
+	if err := checkEntryOpen(contest, rules, time.Now()); err != nil {
+		return nil, nil, err
+	}
+	if !rules.VideoAllowed {
+		return nil, nil, model.NewCodedError(model.ErrBadRequest, model.EntryRejectedVideoNotAllowed, "videos are not allowed in this contest")
+	}
+	if err := checkUploadSize(upload, rules.MaxVideoSizeMB); err != nil {
+		return nil, nil, err
+	}
+	// Длительность, которую не удалось определить, при заданном лимите не принимаем
+	if limit := time.Duration(rules.MaxVideoDurationSec) * time.Second; limit > 0 && (upload.Duration <= 0 || upload.Duration > limit) {
+		return nil, nil, model.NewCodedError(model.ErrBadRequest, model.EntryRejectedVideoTooLong,
+			fmt.Sprintf("video must be an MP4 no longer than %d seconds", rules.MaxVideoDurationSec))
+	}
+	return participant, rules, nil
 }
 
 func (s *TopPetService) DeleteParticipant(ctx context.Context, participantID model.ParticipantID, userID model.UserID) error {
@@ -265,6 +475,11 @@ func (s *TopPetService) DeleteParticipant(ctx context.Context, participantID mod
 
 	// Only owner can delete
 	if participant.UserID != userID {
+		if s.isStaff(ctx, userID) {
+			log.Printf("[Service] DeleteParticipant: User %d deletes participant as moderator", userID)
+			_, err = s.Moderate(ctx, userID, model.ModerationActionDelete, model.ModerationTargetParticipant, string(participantID), "")
+			return err
+		}
 		log.Printf("[Service] DeleteParticipant: ERROR - User %d is not the owner (owner is %d)", userID, participant.UserID)
 		return errors.New("only participant owner can delete")
 	}
@@ -439,3 +654,17 @@ func (s *TopPetService) UpdateParticipantPhotoOrder(ctx context.Context, partici
 	log.Printf("[Service] UpdateParticipantPhotoOrder: Order updated successfully")
 	return nil
 }
+
+// participantVotingMode режим голосования конкурса участника (single, если конкурс не загрузился)
+func (s *TopPetService) participantVotingMode(ctx context.Context, contestID model.ContestID) model.VotingMode {
+	contest, err := s.repository.GetContest(ctx, contestID)
+	if err != nil {
+		return model.VotingModeSingle
+	}
+	return contestVotingMode(contest)
+}
+
+// fillParticipantVotes заполняет счетчик голосов участника по режиму голосования
+func (s *TopPetService) fillParticipantVotes(ctx context.Context, mode model.VotingMode, participant *model.Participant) {
+	participant.TotalVotes, participant.AverageStars = s.participantVoteCount(ctx, mode, "", participant.ID)
+}
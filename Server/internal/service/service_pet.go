@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"toppet/server/internal/model"
+)
+
+const (
+	maxPetNameLength = 100
+	maxPetBioLength  = 2000
+	maxPetPhotos     = 30
+)
+
+// CreatePet заводит профиль питомца текущего пользователя
+func (s *TopPetService) CreatePet(ctx context.Context, userID model.UserID, pet *model.Pet) (*model.Pet, error) {
+	if err := normalizePet(pet, time.Now()); err != nil {
+		return nil, err
+	}
+	if err := s.checkTaxonomy(ctx, &pet.PetAttributes); err != nil {
+		return nil, err
+	}
+	pet.OwnerUserID = userID
+	return s.repository.CreatePet(ctx, pet)
+}
+
+// ListUserPets питомцы пользователя вместе с галереями
+func (s *TopPetService) ListUserPets(ctx context.Context, userID model.UserID) ([]*model.Pet, error) {
+	pets, err := s.repository.ListPetsByOwner(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, pet := range pets {
+		photos, _ := s.repository.ListPetPhotos(ctx, pet.ID)
+		pet.Photos = photos
+	}
+	return pets, nil
+}
+
+// GetPet карточка питомца: галерея, история участия в конкурсах и победы.
+// Место и победа считаются по итогам главного приза завершенных конкурсов.
+func (s *TopPetService) GetPet(ctx context.Context, petID model.PetID) (*model.Pet, error) {
+	pet, err := s.repository.GetPet(ctx, petID)
+	if err != nil {
+		return nil, err
+	}
+
+	photos, _ := s.repository.ListPetPhotos(ctx, petID)
+	pet.Photos = photos
+
+	if err := s.fillPetEntries(ctx, pet, make(map[model.ContestID]*model.ContestResults)); err != nil {
+		return nil, err
+	}
+	return pet, nil
+}
+
+// fillPetEntries заполняет историю участия питомца, места и победы.
+// results кеширует итоги конкурсов между вызовами, если в одном конкурсе участвует несколько питомцев.
+func (s *TopPetService) fillPetEntries(ctx context.Context, pet *model.Pet, results map[model.ContestID]*model.ContestResults) error {
+	entries, err := s.repository.ListPetEntries(ctx, pet.ID)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		entry.PetID = pet.ID
+		if entry.ContestStatus != model.ContestStatusFinished {
+			continue
+		}
+		contestResults, ok := results[entry.ContestID]
+		if !ok {
+			contestResults, err = s.GetContestResults(ctx, entry.ContestID, "")
+			if err != nil {
+				log.Printf("[Service] fillPetEntries: failed to get results of contest %s: %v", entry.ContestID, err)
+				continue
+			}
+			results[entry.ContestID] = contestResults
+		}
+		for _, item := range contestResults.Items {
+			if item.ParticipantID == entry.ParticipantID {
+				entry.Place = item.Place
+				break
+			}
+		}
+		if contestResults.WinnerID == entry.ParticipantID {
+			entry.Won = true
+			pet.Wins++
+		}
+	}
+	pet.Entries = entries
+	return nil
+}
+
+// UpdatePet изменяет профиль питомца. Уже поданные заявки сохраняют имя и описание на момент подачи.
+func (s *TopPetService) UpdatePet(ctx context.Context, petID model.PetID, userID model.UserID, update *model.Pet) (*model.Pet, error) {
+	if _, err := s.ownedPet(ctx, petID, userID); err != nil {
+		return nil, err
+	}
+	if err := normalizePet(update, time.Now()); err != nil {
+		return nil, err
+	}
+	if err := s.checkTaxonomy(ctx, &update.PetAttributes); err != nil {
+		return nil, err
+	}
+	update.ID = petID
+	return s.repository.UpdatePet(ctx, update)
+}
+
+// DeletePet удаляет профиль питомца; заявки в конкурсах остаются без ссылки на профиль
+func (s *TopPetService) DeletePet(ctx context.Context, petID model.PetID, userID model.UserID) error {
+	if _, err := s.ownedPet(ctx, petID, userID); err != nil {
+		return err
+	}
+	return s.repository.DeletePet(ctx, petID)
+}
+
+// AddPetPhoto добавляет загруженное фото в галерею питомца
+func (s *TopPetService) AddPetPhoto(ctx context.Context, petID model.PetID, userID model.UserID, url string, upload model.MediaUpload) (*model.PetPhoto, error) {
+	if _, err := s.ownedPet(ctx, petID, userID); err != nil {
+		return nil, err
+	}
+	if upload.Size > model.MaxPhotoUploadMB<<20 {
+		return nil, fmt.Errorf("%w: photo must be at most %d MB", model.ErrBadRequest, model.MaxPhotoUploadMB)
+	}
+	count, err := s.repository.CountPetPhotos(ctx, petID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= maxPetPhotos {
+		return nil, fmt.Errorf("%w: a pet can have at most %d photos", model.ErrBadRequest, maxPetPhotos)
+	}
+	return s.repository.AddPetPhoto(ctx, petID, url, nil)
+}
+
+// DeletePetPhoto удаляет фото из галереи; копии фото в заявках не затрагиваются
+func (s *TopPetService) DeletePetPhoto(ctx context.Context, petID model.PetID, userID model.UserID, photoID string) error {
+	if _, err := s.ownedPet(ctx, petID, userID); err != nil {
+		return err
+	}
+	return s.repository.DeletePetPhoto(ctx, petID, photoID)
+}
+
+// ownedPet возвращает питомца, если он принадлежит пользователю
+func (s *TopPetService) ownedPet(ctx context.Context, petID model.PetID, userID model.UserID) (*model.Pet, error) {
+	pet, err := s.repository.GetPet(ctx, petID)
+	if err != nil {
+		return nil, err
+	}
+	if pet.OwnerUserID != userID {
+		return nil, fmt.Errorf("%w: pet belongs to another user", model.ErrorForbidden)
+	}
+	return pet, nil
+}
+
+// selectPetPhotos фото галереи питомца, выбранные для заявки, в порядке выбора
+func (s *TopPetService) selectPetPhotos(ctx context.Context, petID model.PetID, photoIDs []string) ([]*model.PetPhoto, error) {
+	if len(photoIDs) == 0 {
+		return nil, nil
+	}
+	gallery, err := s.repository.ListPetPhotos(ctx, petID)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*model.PetPhoto, len(gallery))
+	for _, photo := range gallery {
+		byID[photo.ID] = photo
+	}
+
+	selected := make([]*model.PetPhoto, 0, len(photoIDs))
+	seen := make(map[string]bool, len(photoIDs))
+	for _, photoID := range photoIDs {
+		photo, ok := byID[photoID]
+		if !ok {
+			return nil, fmt.Errorf("%w: photo %q is not in the pet gallery", model.ErrBadRequest, photoID)
+		}
+		if seen[photoID] {
+			continue
+		}
+		seen[photoID] = true
+		selected = append(selected, photo)
+	}
+	return selected, nil
+}
+
+// normalizePet обрезает пробелы и проверяет поля профиля питомца. Коды вида и породы сверяет checkTaxonomy.
+func normalizePet(pet *model.Pet, now time.Time) error {
+	pet.Name = strings.TrimSpace(pet.Name)
+	pet.Bio = strings.TrimSpace(pet.Bio)
+
+	if pet.Name == "" {
+		return fmt.Errorf("%w: name is required", model.ErrBadRequest)
+	}
+	if utf8.RuneCountInString(pet.Name) > maxPetNameLength {
+		return fmt.Errorf("%w: name must be at most %d characters", model.ErrBadRequest, maxPetNameLength)
+	}
+	if utf8.RuneCountInString(pet.Bio) > maxPetBioLength {
+		return fmt.Errorf("%w: bio must be at most %d characters", model.ErrBadRequest, maxPetBioLength)
+	}
+	return normalizePetAttributes(&pet.PetAttributes, now)
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"toppet/server/internal/model"
+)
+
+func TestNormalizePet(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		pet     model.Pet
+		wantErr bool
+	}{
+		{name: "valid", pet: model.Pet{Name: "  Барсик ", PetAttributes: model.PetAttributes{Species: " Cat", BirthDate: "2020-05-17"}}},
+		{name: "name required", pet: model.Pet{Name: "   "}, wantErr: true},
+		{name: "name too long", pet: model.Pet{Name: strings.Repeat("я", maxPetNameLength+1)}, wantErr: true},
+		{name: "bad birth date", pet: model.Pet{Name: "Барсик", PetAttributes: model.PetAttributes{BirthDate: "17.05.2020"}}, wantErr: true},
+		{name: "unknown sex", pet: model.Pet{Name: "Барсик", PetAttributes: model.PetAttributes{Sex: "boy"}}, wantErr: true},
+		{name: "birth date in future", pet: model.Pet{Name: "Барсик", PetAttributes: model.PetAttributes{BirthDate: "2025-03-02"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pet := tt.pet
+			err := normalizePet(&pet, now)
+			if tt.wantErr {
+				if !errors.Is(err, model.ErrBadRequest) {
+					t.Errorf("Expected bad request, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if pet.Name != "Барсик" || pet.Species != "cat" {
+				t.Errorf("Expected trimmed name and species code, got %q, %q", pet.Name, pet.Species)
+			}
+		})
+	}
+}
+
+func TestTopPetService_PetOwnership(t *testing.T) {
+	mockRepo := &mockRepository{
+		pets: map[model.PetID]*model.Pet{
+			"pet-1": {ID: "pet-1", OwnerUserID: 10, Name: "Барсик"},
+		},
+		species: []*model.Species{{Code: "cat"}, {Code: "dog"}},
+		breeds:  []*model.Breed{{Code: "cat_maine_coon", Species: "cat"}},
+	}
+	service := &TopPetService{repository: mockRepo}
+	ctx := context.Background()
+
+	if _, err := service.UpdatePet(ctx, "pet-1", 11, &model.Pet{Name: "Мурзик"}); !errors.Is(err, model.ErrorForbidden) {
+		t.Errorf("Expected forbidden update for another user, got %v", err)
+	}
+	if err := service.DeletePet(ctx, "pet-1", 11); !errors.Is(err, model.ErrorForbidden) {
+		t.Errorf("Expected forbidden delete for another user, got %v", err)
+	}
+
+	if _, err := service.UpdatePet(ctx, "pet-1", 10, &model.Pet{Name: "Мурзик", PetAttributes: model.PetAttributes{Species: "dog", Breed: "cat_maine_coon"}}); !errors.Is(err, model.ErrBadRequest) {
+		t.Errorf("Expected bad request for breed of another species, got %v", err)
+	}
+
+	// Вид подставляется из породы
+	updated, err := service.UpdatePet(ctx, "pet-1", 10, &model.Pet{Name: "Мурзик", PetAttributes: model.PetAttributes{Breed: "cat_maine_coon"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if updated.ID != "pet-1" || updated.Name != "Мурзик" || updated.Species != "cat" {
+		t.Errorf("Expected updated pet-1 with species from breed, got %+v", updated)
+	}
+}
+
+func TestTopPetService_CreateParticipantFromPet(t *testing.T) {
+	mockRepo := &mockRepository{
+		pets: map[model.PetID]*model.Pet{
+			"pet-1": {ID: "pet-1", OwnerUserID: 10, Name: "Барсик", Bio: "Любит коробки"},
+		},
+		petPhotos: []*model.PetPhoto{
+			{ID: "photo-1", PetID: "pet-1", URL: "https://cdn/1.jpg"},
+			{ID: "photo-2", PetID: "pet-1", URL: "https://cdn/2.jpg"},
+			{ID: "photo-3", PetID: "pet-1", URL: "https://cdn/3.jpg"},
+		},
+		entryRules: &model.EntryRules{MaxPhotosPerEntry: 2},
+		getContestFunc: func(ctx context.Context, contestID model.ContestID) (*model.Contest, error) {
+			return &model.Contest{ID: contestID, Status: model.ContestStatusRegistration}, nil
+		},
+	}
+	service := &TopPetService{repository: mockRepo}
+	ctx := context.Background()
+
+	if _, err := service.CreateParticipant(ctx, "contest-id", 11, "pet-1", "", "", model.PetAttributes{}, nil); !errors.Is(err, model.ErrorForbidden) {
+		t.Errorf("Expected forbidden for someone else's pet, got %v", err)
+	}
+	if _, err := service.CreateParticipant(ctx, "contest-id", 10, "pet-1", "", "", model.PetAttributes{}, []string{"photo-9"}); !errors.Is(err, model.ErrBadRequest) {
+		t.Errorf("Expected bad request for photo outside the gallery, got %v", err)
+	}
+	_, err := service.CreateParticipant(ctx, "contest-id", 10, "pet-1", "", "", model.PetAttributes{}, []string{"photo-1", "photo-2", "photo-3"})
+	var coded *model.CodedError
+	if !errors.As(err, &coded) || coded.Code != model.EntryRejectedMaxPhotos {
+		t.Errorf("Expected max photos code, got %v", err)
+	}
+
+	participant, err := service.CreateParticipant(ctx, "contest-id", 10, "pet-1", "", "", model.PetAttributes{}, []string{"photo-2", "photo-1", "photo-2"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if participant.PetID != "pet-1" || participant.PetName != "Барсик" || participant.PetDescription != "Любит коробки" {
+		t.Errorf("Expected entry filled from pet profile, got %+v", participant)
+	}
+	if mockRepo.photoCount != 2 {
+		t.Errorf("Expected 2 photos copied to the entry, got %d", mockRepo.photoCount)
+	}
+
+	// Заявка без профиля заводит питомца автоматически
+	participant, err = service.CreateParticipant(ctx, "contest-id", 12, "", "Шарик", "", model.PetAttributes{}, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	pet, ok := mockRepo.pets[participant.PetID]
+	if !ok || pet.OwnerUserID != 12 || pet.Name != "Шарик" {
+		t.Errorf("Expected auto-created pet for user 12, got %+v", pet)
+	}
+	if _, err := service.CreateParticipant(ctx, "contest-id", 12, "", "Шарик", "", model.PetAttributes{}, []string{"photo-1"}); !errors.Is(err, model.ErrBadRequest) {
+		t.Errorf("Expected bad request for photo_ids without pet_id, got %v", err)
+	}
+}
+
+func TestTopPetService_GetPetHistory(t *testing.T) {
+	mockRepo := &mockRepository{
+		pets: map[model.PetID]*model.Pet{
+			"pet-1": {ID: "pet-1", OwnerUserID: 10, Name: "Барсик"},
+		},
+		petEntries: []*model.PetEntry{
+			{ParticipantID: "cat", ContestID: "finished", ContestStatus: model.ContestStatusFinished},
+			{ParticipantID: "cat-2", ContestID: "voting", ContestStatus: model.ContestStatusVoting},
+		},
+		contestParticipants: []*model.Participant{{ID: "cat"}, {ID: "dog"}},
+		ballots: []*model.Ballot{
+			{VoteID: "v1", Choices: []model.BallotChoice{{ParticipantID: "cat"}}},
+			{VoteID: "v2", Choices: []model.BallotChoice{{ParticipantID: "cat"}}},
+			{VoteID: "v3", Choices: []model.BallotChoice{{ParticipantID: "dog"}}},
+		},
+		getContestFunc: func(ctx context.Context, contestID model.ContestID) (*model.Contest, error) {
+			return &model.Contest{ID: contestID, Status: model.ContestStatusFinished}, nil
+		},
+	}
+	service := &TopPetService{repository: mockRepo}
+
+	pet, err := service.GetPet(context.Background(), "pet-1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(pet.Entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(pet.Entries))
+	}
+	if finished := pet.Entries[0]; !finished.Won || finished.Place != 1 {
+		t.Errorf("Expected win in finished contest, got %+v", finished)
+	}
+	if voting := pet.Entries[1]; voting.Won || voting.Place != 0 {
+		t.Errorf("Expected no result for contest in progress, got %+v", voting)
+	}
+	if pet.Wins != 1 {
+		t.Errorf("Expected 1 win, got %d", pet.Wins)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
+	"slices"
 	"strings"
 
 	"toppet/server/internal/model"
@@ -21,6 +23,13 @@ func (s *TopPetService) GetUserProfile(ctx context.Context, userID model.UserID)
 		return nil, err
 	}
 
+	// Roles are read from the database so that a freshly granted role is visible before token refresh
+	roles, err := s.repository.ListUserRoles(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	user.Roles = roles
+
 	// Get user's auth providers
 	providers, err := s.repository.GetUserAuthProvidersByUserID(ctx, userID)
 	if err != nil {
@@ -67,10 +76,90 @@ func (s *TopPetService) LinkAuthProvider(ctx context.Context, userID model.UserI
 	return authProvider, nil
 }
 
-func (s *TopPetService) UpdateUserName(ctx context.Context, userID model.UserID, name string) (*model.User, error) {
-	trimmed := strings.TrimSpace(name)
-	if trimmed == "" {
-		return nil, errors.New("name is required")
+// UpdateCurrentUser меняет имя, био и язык текущего пользователя; непереданные поля сохраняются
+func (s *TopPetService) UpdateCurrentUser(ctx context.Context, userID model.UserID, update *model.UserUpdate) (*model.User, error) {
+	user, err := s.repository.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	name, bio, locale := user.Name, user.Bio, user.Locale
+	if update.Name != nil {
+		name = strings.TrimSpace(*update.Name)
+		if name == "" {
+			return nil, fmt.Errorf("%w: name is required", model.ErrBadRequest)
+		}
+	}
+	if update.Bio != nil {
+		bio = strings.TrimSpace(*update.Bio)
+	}
+	if update.Locale != nil {
+		if !slices.Contains(model.SupportedLocales, *update.Locale) {
+			return nil, fmt.Errorf("%w: unsupported locale %q", model.ErrBadRequest, *update.Locale)
+		}
+		locale = *update.Locale
+	}
+	if locale == "" {
+		locale = model.LocaleRU
+	}
+
+	return s.repository.UpdateUserProfile(ctx, userID, name, bio, locale)
+}
+
+// SetUserAvatar сохраняет загруженный аватар и его уменьшенную копию; прежние файлы аватара удаляются из хранилища
+func (s *TopPetService) SetUserAvatar(ctx context.Context, userID model.UserID, avatarURL, thumbURL string) (*model.User, error) {
+	previous, err := s.repository.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	replaced := []string{previous.AvatarURL, previous.AvatarThumbURL}
+
+	user, err := s.repository.SetUserAvatar(ctx, userID, &avatarURL, &thumbURL)
+	if err != nil {
+		return nil, err
+	}
+	s.deleteReplacedAvatar(ctx, user, replaced)
+	return user, nil
+}
+
+// ResetUserAvatar возвращает аватар из провайдера, через который пользователь входил последним.
+// Если провайдер аватар не передавал, аватар сбрасывается.
+func (s *TopPetService) ResetUserAvatar(ctx context.Context, userID model.UserID) (*model.User, error) {
+	providerAvatar, err := s.repository.GetLatestProviderAvatar(ctx, userID)
+	if err != nil && !errors.Is(err, model.ErrorNotFound) {
+		return nil, err
+	}
+
+	previous, err := s.repository.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	replaced := []string{previous.AvatarURL, previous.AvatarThumbURL}
+
+	var avatarURL *string
+	if providerAvatar != "" {
+		avatarURL = &providerAvatar
+	}
+	user, err := s.repository.SetUserAvatar(ctx, userID, avatarURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	s.deleteReplacedAvatar(ctx, user, replaced)
+	return user, nil
+}
+
+// deleteReplacedAvatar удаляет из хранилища прежние файлы аватара, которые больше не используются.
+// Аватары провайдеров хранилище пропускает само. Ошибки только логируются: аватар уже заменен.
+func (s *TopPetService) deleteReplacedAvatar(ctx context.Context, user *model.User, replaced []string) {
+	if s.storage == nil {
+		return
+	}
+	for _, url := range replaced {
+		if url == "" || url == user.AvatarURL || url == user.AvatarThumbURL {
+			continue
+		}
+		if err := s.storage.Delete(ctx, url); err != nil {
+			log.Printf("[Service] deleteReplacedAvatar: userID=%d, url=%s: %v", user.ID, url, err)
+		}
 	}
-	return s.repository.UpdateUserName(ctx, userID, trimmed)
 }
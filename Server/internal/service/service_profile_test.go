@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"toppet/server/internal/model"
+)
+
+func TestTopPetService_UpdateCurrentUser(t *testing.T) {
+	mockRepo := &mockRepository{
+		users: map[model.UserID]*model.User{
+			10: {ID: 10, Name: "Анна", Bio: "Кошатница", Locale: model.LocaleRU},
+		},
+	}
+	service := &TopPetService{repository: mockRepo}
+	ctx := context.Background()
+
+	blank := "  "
+	if _, err := service.UpdateCurrentUser(ctx, 10, &model.UserUpdate{Name: &blank}); !errors.Is(err, model.ErrBadRequest) {
+		t.Errorf("Expected bad request for blank name, got %v", err)
+	}
+	unknown := model.Locale("de")
+	if _, err := service.UpdateCurrentUser(ctx, 10, &model.UserUpdate{Locale: &unknown}); !errors.Is(err, model.ErrBadRequest) {
+		t.Errorf("Expected bad request for unsupported locale, got %v", err)
+	}
+
+	// Непереданные поля сохраняются
+	locale := model.LocaleEN
+	user, err := service.UpdateCurrentUser(ctx, 10, &model.UserUpdate{Locale: &locale})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if user.Name != "Анна" || user.Bio != "Кошатница" || user.Locale != model.LocaleEN {
+		t.Errorf("Expected only locale changed, got %+v", user)
+	}
+}
+
+func TestTopPetService_ResetUserAvatar(t *testing.T) {
+	providerAvatar := "https://provider/avatar.jpg"
+	mockRepo := &mockRepository{
+		users: map[model.UserID]*model.User{
+			10: {ID: 10, AvatarURL: "https://cdn/custom.jpg", AvatarThumbURL: "https://cdn/custom_thumb.jpg"},
+			11: {ID: 11, AvatarURL: "https://cdn/custom.jpg"},
+		},
+		authProviders: map[model.UserID][]*model.UserAuthProvider{
+			10: {{UserID: 10, Provider: "vk"}, {UserID: 10, Provider: "yandex", AvatarURL: &providerAvatar}},
+		},
+	}
+	storage := &fakeMediaStorage{}
+	service := &TopPetService{repository: mockRepo, storage: storage}
+	ctx := context.Background()
+
+	user, err := service.ResetUserAvatar(ctx, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if user.AvatarURL != providerAvatar || user.AvatarThumbURL != "" {
+		t.Errorf("Expected provider avatar without thumbnail, got %+v", user)
+	}
+	if len(storage.deleted) != 2 || storage.deleted[0] != "https://cdn/custom.jpg" || storage.deleted[1] != "https://cdn/custom_thumb.jpg" {
+		t.Errorf("Expected uploaded avatar files deleted, got %v", storage.deleted)
+	}
+
+	// Провайдер аватар не передавал - аватар сбрасывается
+	user, err = service.ResetUserAvatar(ctx, 11)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if user.AvatarURL != "" {
+		t.Errorf("Expected avatar cleared, got %q", user.AvatarURL)
+	}
+}
+
+func TestTopPetService_SetUserAvatar(t *testing.T) {
+	mockRepo := &mockRepository{
+		users: map[model.UserID]*model.User{
+			10: {ID: 10, AvatarURL: "https://cdn/old.jpg", AvatarThumbURL: "https://cdn/old_thumb.jpg"},
+		},
+	}
+	storage := &fakeMediaStorage{}
+	service := &TopPetService{repository: mockRepo, storage: storage}
+
+	user, err := service.SetUserAvatar(context.Background(), 10, "https://cdn/new.jpg", "https://cdn/new_thumb.jpg")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if user.AvatarURL != "https://cdn/new.jpg" || user.AvatarThumbURL != "https://cdn/new_thumb.jpg" {
+		t.Errorf("Expected new avatar, got %+v", user)
+	}
+	// Прежний аватар не остается в хранилище
+	if len(storage.deleted) != 2 || storage.deleted[0] != "https://cdn/old.jpg" || storage.deleted[1] != "https://cdn/old_thumb.jpg" {
+		t.Errorf("Expected previous avatar files deleted, got %v", storage.deleted)
+	}
+
+	// Ошибка хранилища не отменяет замену аватара
+	storage.err = errors.New("storage unavailable")
+	if _, err := service.SetUserAvatar(context.Background(), 10, "https://cdn/newer.jpg", "https://cdn/newer_thumb.jpg"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"toppet/server/internal/app/defenitions"
+	"toppet/server/internal/model"
+)
+
+// maxProfileContests сколько последних конкурсов пользователя показывать в профиле
+const maxProfileContests = 50
+
+// GetPublicUserProfile публичный профиль пользователя: имя, аватар, био и разделы,
+// открытые настройками приватности. Сам пользователь и персонал видят все разделы.
+func (s *TopPetService) GetPublicUserProfile(ctx context.Context, userID model.UserID) (*model.PublicUserProfile, error) {
+	user, err := s.repository.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.DeletedAt != nil {
+		return nil, fmt.Errorf("%w: user is deleted", model.ErrorNotFound)
+	}
+	privacy, err := s.privacySettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	profile := &model.PublicUserProfile{
+		ID:        user.ID,
+		Name:      user.Name,
+		AvatarURL: user.AvatarURL,
+		Bio:       user.Bio,
+		CreatedAt: user.CreatedAt,
+	}
+
+	viewerID, _ := ctx.Value(defenitions.UserID).(model.UserID)
+	isOwner := viewerID != 0 && viewerID == userID
+	full := isOwner || (viewerID != 0 && s.isStaff(ctx, viewerID))
+	if isOwner {
+		profile.Privacy = privacy
+	}
+
+	if full || privacy.ShowContests {
+		contests, err := s.repository.ListPublicContestsByCreator(ctx, userID, maxProfileContests)
+		if err != nil {
+			return nil, err
+		}
+		if contests == nil {
+			contests = []*model.Contest{}
+		}
+		profile.Contests = contests
+	}
+
+	showPets := full || privacy.ShowPets
+	showResults := full || privacy.ShowResults
+	if !showPets && !showResults {
+		return profile, nil
+	}
+
+	pets, err := s.repository.ListPetsByOwner(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	results := &model.UserResults{Placements: []*model.PetEntry{}}
+	contestResults := make(map[model.ContestID]*model.ContestResults)
+	for _, pet := range pets {
+		if err := s.fillPetEntries(ctx, pet, contestResults); err != nil {
+			return nil, err
+		}
+		results.Entries += len(pet.Entries)
+		results.Wins += pet.Wins
+		for _, entry := range pet.Entries {
+			if entry.Place == 0 {
+				continue
+			}
+			results.Placements = append(results.Placements, entry)
+			if entry.Place <= 3 {
+				results.Podiums++
+			}
+		}
+	}
+
+	if showResults {
+		sort.SliceStable(results.Placements, func(i, j int) bool {
+			if results.Placements[i].Place != results.Placements[j].Place {
+				return results.Placements[i].Place < results.Placements[j].Place
+			}
+			return results.Placements[i].CreatedAt.After(results.Placements[j].CreatedAt)
+		})
+		profile.Results = results
+	}
+	if showPets {
+		for _, pet := range pets {
+			photos, _ := s.repository.ListPetPhotos(ctx, pet.ID)
+			pet.Photos = photos
+			if !showResults {
+				hidePetResults(pet)
+			}
+		}
+		if pets == nil {
+			pets = []*model.Pet{}
+		}
+		profile.Pets = pets
+	}
+	return profile, nil
+}
+
+// GetPrivacySettings настройки приватности профиля текущего пользователя
+func (s *TopPetService) GetPrivacySettings(ctx context.Context, userID model.UserID) (*model.PrivacySettings, error) {
+	return s.privacySettings(ctx, userID)
+}
+
+// UpdatePrivacySettings сохраняет настройки приватности профиля
+func (s *TopPetService) UpdatePrivacySettings(ctx context.Context, userID model.UserID, settings *model.PrivacySettings) (*model.PrivacySettings, error) {
+	return s.repository.UpsertUserPrivacySettings(ctx, userID, settings)
+}
+
+func (s *TopPetService) privacySettings(ctx context.Context, userID model.UserID) (*model.PrivacySettings, error) {
+	settings, err := s.repository.GetUserPrivacySettings(ctx, userID)
+	if errors.Is(err, model.ErrorNotFound) {
+		return &model.PrivacySettings{ShowContests: true, ShowPets: true, ShowResults: true}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// hidePetResults убирает места и победы из карточки питомца, когда итоги скрыты настройками приватности
+func hidePetResults(pet *model.Pet) {
+	pet.Wins = 0
+	for _, entry := range pet.Entries {
+		entry.Place = 0
+		entry.Won = false
+	}
+}
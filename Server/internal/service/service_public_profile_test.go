@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"toppet/server/internal/app/defenitions"
+	"toppet/server/internal/model"
+)
+
+func newPublicProfileRepo(privacy *model.PrivacySettings) *mockRepository {
+	return &mockRepository{
+		users: map[model.UserID]*model.User{
+			10: {ID: 10, Name: "Анна", Bio: "Кошатница"},
+		},
+		privacy:         privacy,
+		creatorContests: []*model.Contest{{ID: "own", CreatedByUserID: 10, Status: model.ContestStatusVoting}},
+		pets: map[model.PetID]*model.Pet{
+			"pet-1": {ID: "pet-1", OwnerUserID: 10, Name: "Барсик"},
+		},
+		petEntries: []*model.PetEntry{
+			{ParticipantID: "cat", ContestID: "finished", ContestStatus: model.ContestStatusFinished},
+		},
+		contestParticipants: []*model.Participant{{ID: "cat"}, {ID: "dog"}},
+		ballots: []*model.Ballot{
+			{VoteID: "v1", Choices: []model.BallotChoice{{ParticipantID: "cat"}}},
+			{VoteID: "v2", Choices: []model.BallotChoice{{ParticipantID: "cat"}}},
+			{VoteID: "v3", Choices: []model.BallotChoice{{ParticipantID: "dog"}}},
+		},
+		getContestFunc: func(ctx context.Context, contestID model.ContestID) (*model.Contest, error) {
+			return &model.Contest{ID: contestID, Status: model.ContestStatusFinished}, nil
+		},
+	}
+}
+
+func TestTopPetService_GetPublicUserProfile(t *testing.T) {
+	restricted := &model.PrivacySettings{ShowPets: true}
+
+	t.Run("defaults show everything", func(t *testing.T) {
+		service := &TopPetService{repository: newPublicProfileRepo(nil)}
+		profile, err := service.GetPublicUserProfile(context.Background(), 10)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if profile.Name != "Анна" || profile.Bio != "Кошатница" {
+			t.Errorf("Expected name and bio, got %+v", profile)
+		}
+		if len(profile.Contests) != 1 || len(profile.Pets) != 1 {
+			t.Errorf("Expected contests and pets, got %d, %d", len(profile.Contests), len(profile.Pets))
+		}
+		if profile.Results == nil || profile.Results.Wins != 1 || profile.Results.Podiums != 1 || len(profile.Results.Placements) != 1 {
+			t.Fatalf("Expected one win with placement, got %+v", profile.Results)
+		}
+		if profile.Results.Placements[0].PetID != "pet-1" {
+			t.Errorf("Expected placement linked to pet-1, got %q", profile.Results.Placements[0].PetID)
+		}
+		if profile.Privacy != nil {
+			t.Error("Expected privacy settings hidden from other users")
+		}
+	})
+
+	t.Run("privacy hides sections", func(t *testing.T) {
+		service := &TopPetService{repository: newPublicProfileRepo(restricted)}
+		profile, err := service.GetPublicUserProfile(context.Background(), 10)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if profile.Contests != nil || profile.Results != nil {
+			t.Errorf("Expected hidden contests and results, got %+v", profile)
+		}
+		if len(profile.Pets) != 1 {
+			t.Fatalf("Expected pets section, got %d pets", len(profile.Pets))
+		}
+		pet := profile.Pets[0]
+		if pet.Wins != 0 || len(pet.Entries) != 1 || pet.Entries[0].Won || pet.Entries[0].Place != 0 {
+			t.Errorf("Expected entries without results, got %+v", pet)
+		}
+	})
+
+	t.Run("owner sees everything", func(t *testing.T) {
+		service := &TopPetService{repository: newPublicProfileRepo(restricted)}
+		ctx := context.WithValue(context.Background(), defenitions.UserID, model.UserID(10))
+		profile, err := service.GetPublicUserProfile(ctx, 10)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if profile.Contests == nil || profile.Results == nil || profile.Privacy == nil {
+			t.Errorf("Expected full profile with privacy settings for owner, got %+v", profile)
+		}
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		service := &TopPetService{repository: newPublicProfileRepo(nil)}
+		if _, err := service.GetPublicUserProfile(context.Background(), 11); !errors.Is(err, model.ErrorNotFound) {
+			t.Errorf("Expected not found, got %v", err)
+		}
+	})
+}
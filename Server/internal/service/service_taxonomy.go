@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"toppet/server/internal/model"
+)
+
+// maxAgeMonthsLimit верхняя граница возрастных ограничений конкурса (30 лет)
+const maxAgeMonthsLimit = 360
+
+// ListSpecies справочник видов
+func (s *TopPetService) ListSpecies(ctx context.Context) ([]*model.Species, error) {
+	return s.repository.ListSpecies(ctx)
+}
+
+// ListBreeds породы вида из справочника
+func (s *TopPetService) ListBreeds(ctx context.Context, species string) ([]*model.Breed, error) {
+	species = strings.ToLower(strings.TrimSpace(species))
+	if err := s.checkSpecies(ctx, species); err != nil {
+		return nil, fmt.Errorf("%w: unknown species %q", model.ErrorNotFound, species)
+	}
+	return s.repository.ListBreeds(ctx, species)
+}
+
+// checkSpecies проверяет, что код вида есть в справочнике
+func (s *TopPetService) checkSpecies(ctx context.Context, code string) error {
+	species, err := s.repository.ListSpecies(ctx)
+	if err != nil {
+		return err
+	}
+	if !slices.ContainsFunc(species, func(sp *model.Species) bool { return sp.Code == code }) {
+		return fmt.Errorf("%w: unknown species %q", model.ErrBadRequest, code)
+	}
+	return nil
+}
+
+// checkTaxonomy сверяет вид и породу со справочником. Если указана только порода, вид берется из нее.
+func (s *TopPetService) checkTaxonomy(ctx context.Context, attrs *model.PetAttributes) error {
+	if attrs.Breed != "" {
+		breed, err := s.repository.GetBreed(ctx, attrs.Breed)
+		if errors.Is(err, model.ErrorNotFound) {
+			return fmt.Errorf("%w: unknown breed %q", model.ErrBadRequest, attrs.Breed)
+		}
+		if err != nil {
+			return err
+		}
+		if attrs.Species == "" {
+			attrs.Species = breed.Species
+		}
+		if breed.Species != attrs.Species {
+			return fmt.Errorf("%w: breed %q does not belong to species %q", model.ErrBadRequest, attrs.Breed, attrs.Species)
+		}
+		return nil
+	}
+	if attrs.Species != "" {
+		return s.checkSpecies(ctx, attrs.Species)
+	}
+	return nil
+}
+
+// normalizePetAttributes приводит коды к нижнему регистру и проверяет пол и дату рождения
+func normalizePetAttributes(attrs *model.PetAttributes, now time.Time) error {
+	attrs.Species = strings.ToLower(strings.TrimSpace(attrs.Species))
+	attrs.Breed = strings.ToLower(strings.TrimSpace(attrs.Breed))
+	attrs.Sex = model.PetSex(strings.ToLower(strings.TrimSpace(string(attrs.Sex))))
+	attrs.BirthDate = strings.TrimSpace(attrs.BirthDate)
+
+	if attrs.Sex != "" && attrs.Sex != model.PetSexMale && attrs.Sex != model.PetSexFemale {
+		return fmt.Errorf("%w: sex must be %q or %q", model.ErrBadRequest, model.PetSexMale, model.PetSexFemale)
+	}
+	if attrs.BirthDate != "" {
+		birthDate, err := time.Parse(time.DateOnly, attrs.BirthDate)
+		if err != nil {
+			return fmt.Errorf("%w: birth_date must be in YYYY-MM-DD format", model.ErrBadRequest)
+		}
+		if birthDate.After(now) {
+			return fmt.Errorf("%w: birth_date is in the future", model.ErrBadRequest)
+		}
+	}
+	return nil
+}
+
+// mergePetAttributes дополняет признаки заявки признаками из профиля питомца
+func mergePetAttributes(attrs model.PetAttributes, pet *model.Pet) model.PetAttributes {
+	if attrs.Species == "" && attrs.Breed == "" {
+		attrs.Species = pet.Species
+		attrs.Breed = pet.Breed
+	}
+	if attrs.Sex == "" {
+		attrs.Sex = pet.Sex
+	}
+	if attrs.BirthDate == "" {
+		attrs.BirthDate = pet.BirthDate
+	}
+	return attrs
+}
+
+// ageInMonths возраст в полных месяцах; false - дата рождения не указана
+func ageInMonths(birthDate string, now time.Time) (int, bool) {
+	born, err := time.Parse(time.DateOnly, birthDate)
+	if err != nil {
+		return 0, false
+	}
+	months := (now.Year()-born.Year())*12 + int(now.Month()) - int(born.Month())
+	if now.Day() < born.Day() {
+		months--
+	}
+	return max(months, 0), true
+}
+
+// checkPetRestrictions проверяет вид и возраст питомца по правилам конкурса
+func checkPetRestrictions(rules *model.EntryRules, attrs model.PetAttributes, now time.Time) error {
+	if len(rules.AllowedSpecies) > 0 && !slices.Contains(rules.AllowedSpecies, attrs.Species) {
+		if attrs.Species == "" {
+			return model.NewCodedError(model.ErrBadRequest, model.EntryRejectedSpecies, "species is required in this contest")
+		}
+		return model.NewCodedError(model.ErrBadRequest, model.EntryRejectedSpecies,
+			fmt.Sprintf("species %q is not allowed in this contest", attrs.Species))
+	}
+	if rules.MinAgeMonths == 0 && rules.MaxAgeMonths == 0 {
+		return nil
+	}
+	age, ok := ageInMonths(attrs.BirthDate, now)
+	if !ok {
+		return model.NewCodedError(model.ErrBadRequest, model.EntryRejectedAge, "birth_date is required in this contest")
+	}
+	if age < rules.MinAgeMonths || (rules.MaxAgeMonths > 0 && age > rules.MaxAgeMonths) {
+		return model.NewCodedError(model.ErrBadRequest, model.EntryRejectedAge,
+			fmt.Sprintf("pet age %d months is outside the allowed range", age))
+	}
+	return nil
+}
+
+// matchesParticipantFilter проверяет участника по фильтру списка. Без даты рождения участник
+// не проходит возрастной фильтр.
+func matchesParticipantFilter(participant *model.Participant, filter model.ParticipantFilter, now time.Time) bool {
+	if filter.Species != "" && participant.Species != filter.Species {
+		return false
+	}
+	if filter.Breed != "" && participant.Breed != filter.Breed {
+		return false
+	}
+	if filter.Sex != "" && participant.Sex != filter.Sex {
+		return false
+	}
+	if filter.MinAgeMonths == 0 && filter.MaxAgeMonths == 0 {
+		return true
+	}
+	age, ok := ageInMonths(participant.BirthDate, now)
+	if !ok {
+		return false
+	}
+	return age >= filter.MinAgeMonths && (filter.MaxAgeMonths == 0 || age <= filter.MaxAgeMonths)
+}
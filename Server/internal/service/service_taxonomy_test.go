@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"toppet/server/internal/model"
+)
+
+func TestAgeInMonths(t *testing.T) {
+	now := time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		birthDate string
+		want      int
+		wantOK    bool
+	}{
+		{birthDate: "2024-03-15", want: 12, wantOK: true},
+		{birthDate: "2024-03-16", want: 11, wantOK: true},
+		{birthDate: "2025-02-28", want: 0, wantOK: true},
+		{birthDate: "2020-01-01", want: 62, wantOK: true},
+		{birthDate: "", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.birthDate, func(t *testing.T) {
+			got, ok := ageInMonths(tt.birthDate, now)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("ageInMonths(%q) = %d, %v; want %d, %v", tt.birthDate, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestCheckPetRestrictions(t *testing.T) {
+	now := time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC)
+	// "Щенки до года": только собаки от 0 до 11 полных месяцев
+	rules := &model.EntryRules{AllowedSpecies: []string{"dog"}, MaxAgeMonths: 11}
+
+	tests := []struct {
+		name     string
+		attrs    model.PetAttributes
+		wantCode string
+	}{
+		{name: "puppy", attrs: model.PetAttributes{Species: "dog", BirthDate: "2024-09-01"}},
+		{name: "cat", attrs: model.PetAttributes{Species: "cat", BirthDate: "2024-09-01"}, wantCode: model.EntryRejectedSpecies},
+		{name: "no species", attrs: model.PetAttributes{BirthDate: "2024-09-01"}, wantCode: model.EntryRejectedSpecies},
+		{name: "adult dog", attrs: model.PetAttributes{Species: "dog", BirthDate: "2024-03-15"}, wantCode: model.EntryRejectedAge},
+		{name: "no birth date", attrs: model.PetAttributes{Species: "dog"}, wantCode: model.EntryRejectedAge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkPetRestrictions(rules, tt.attrs, now)
+			if tt.wantCode == "" {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+			var coded *model.CodedError
+			if !errors.As(err, &coded) || coded.Code != tt.wantCode {
+				t.Errorf("Expected code %s, got %v", tt.wantCode, err)
+			}
+		})
+	}
+}
+
+func TestTopPetService_CreateParticipantSpeciesRestriction(t *testing.T) {
+	mockRepo := &mockRepository{
+		species:    []*model.Species{{Code: "cat"}, {Code: "dog"}},
+		breeds:     []*model.Breed{{Code: "cat_siamese", Species: "cat"}},
+		entryRules: &model.EntryRules{AllowedSpecies: []string{"cat"}},
+		pets: map[model.PetID]*model.Pet{
+			"rex": {ID: "rex", OwnerUserID: 10, Name: "Рекс", PetAttributes: model.PetAttributes{Species: "dog"}},
+		},
+		getContestFunc: func(ctx context.Context, contestID model.ContestID) (*model.Contest, error) {
+			return &model.Contest{ID: contestID, Status: model.ContestStatusRegistration}, nil
+		},
+	}
+	service := &TopPetService{repository: mockRepo}
+	ctx := context.Background()
+
+	// Вид берется из профиля питомца
+	_, err := service.CreateParticipant(ctx, "contest-id", 10, "rex", "", "", model.PetAttributes{}, nil)
+	var coded *model.CodedError
+	if !errors.As(err, &coded) || coded.Code != model.EntryRejectedSpecies {
+		t.Errorf("Expected species_not_allowed for a dog, got %v", err)
+	}
+	if _, err := service.CreateParticipant(ctx, "contest-id", 10, "", "Мурка", "", model.PetAttributes{Species: "unicorn"}, nil); !errors.Is(err, model.ErrBadRequest) {
+		t.Errorf("Expected bad request for unknown species, got %v", err)
+	}
+
+	participant, err := service.CreateParticipant(ctx, "contest-id", 10, "", "Мурка", "", model.PetAttributes{Breed: "cat_siamese", Sex: "female"}, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if participant.Species != "cat" || participant.Breed != "cat_siamese" || participant.Sex != model.PetSexFemale {
+		t.Errorf("Expected structured attributes on entry, got %+v", participant.PetAttributes)
+	}
+}
+
+func TestTopPetService_UpdateParticipantSpeciesRestriction(t *testing.T) {
+	mockRepo := &mockRepository{
+		species:    []*model.Species{{Code: "cat"}, {Code: "dog"}},
+		entryRules: &model.EntryRules{AllowedSpecies: []string{"cat"}},
+		participants: map[model.ParticipantID]*model.Participant{
+			"murka": {ID: "murka", ContestID: "contest-id", UserID: 10, PetName: "Мурка", PetAttributes: model.PetAttributes{Species: "cat"}},
+		},
+		getContestFunc: func(ctx context.Context, contestID model.ContestID) (*model.Contest, error) {
+			return &model.Contest{ID: contestID, Status: model.ContestStatusRegistration}, nil
+		},
+	}
+	service := &TopPetService{repository: mockRepo}
+	ctx := context.Background()
+
+	// Правка заявки не обходит ограничения конкурса
+	_, err := service.UpdateParticipant(ctx, "murka", 10, "Мурка", "", model.PetAttributes{Species: "dog"})
+	var coded *model.CodedError
+	if !errors.As(err, &coded) || coded.Code != model.EntryRejectedSpecies {
+		t.Errorf("Expected species_not_allowed for a dog, got %v", err)
+	}
+
+	participant, err := service.UpdateParticipant(ctx, "murka", 10, "Мурка", "", model.PetAttributes{Species: "Cat", Sex: "female"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if participant.Species != "cat" || participant.Sex != model.PetSexFemale {
+		t.Errorf("Expected updated attributes on entry, got %+v", participant.PetAttributes)
+	}
+}
+
+func TestTopPetService_ListParticipantsFilter(t *testing.T) {
+	birthDate := time.Now().AddDate(0, -6, -1).Format(time.DateOnly)
+	participants := []*model.Participant{
+		{ID: "kitten", PetAttributes: model.PetAttributes{Species: "cat", Sex: model.PetSexFemale, BirthDate: birthDate}},
+		{ID: "cat", PetAttributes: model.PetAttributes{Species: "cat", Sex: model.PetSexMale, BirthDate: "2015-01-01"}},
+		{ID: "dog", PetAttributes: model.PetAttributes{Species: "dog"}},
+	}
+	mockRepo := &mockRepository{
+		getContestFunc: func(ctx context.Context, contestID model.ContestID) (*model.Contest, error) {
+			return &model.Contest{ID: contestID, Status: model.ContestStatusVoting}, nil
+		},
+	}
+	service := &TopPetService{repository: mockRepo}
+
+	tests := []struct {
+		name   string
+		filter model.ParticipantFilter
+		want   []model.ParticipantID
+	}{
+		{name: "species", filter: model.ParticipantFilter{Species: "cat"}, want: []model.ParticipantID{"kitten", "cat"}},
+		{name: "sex", filter: model.ParticipantFilter{Sex: model.PetSexMale}, want: []model.ParticipantID{"cat"}},
+		{name: "age", filter: model.ParticipantFilter{MaxAgeMonths: 11}, want: []model.ParticipantID{"kitten"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo.contestParticipants = append([]*model.Participant(nil), participants...)
+			got, err := service.ListParticipantsByContest(context.Background(), "contest-id", tt.filter)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Expected %v, got %d participants", tt.want, len(got))
+			}
+			for i, p := range got {
+				if p.ID != tt.want[i] {
+					t.Errorf("Expected %v, got %s at %d", tt.want, p.ID, i)
+				}
+			}
+		})
+	}
+}
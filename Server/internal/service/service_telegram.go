@@ -0,0 +1,489 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"toppet/server/internal/model"
+)
+
+const (
+	// telegramLinkTTL время жизни ссылки привязки аккаунта
+	telegramLinkTTL = 15 * time.Minute
+	// telegramLinkPrefix и telegramContestPrefix - виды параметра /start: привязка аккаунта и подписка на конкурс
+	telegramLinkPrefix    = "link_"
+	telegramContestPrefix = "c_"
+
+	// telegramPollTimeout сколько Bot API держит запрос getUpdates без новых сообщений
+	telegramPollTimeout = 25 * time.Second
+	// telegramPollRetryDelay пауза после ошибки getUpdates
+	telegramPollRetryDelay = 5 * time.Second
+
+	telegramOutboxBatchSize = 50
+	telegramOutboxLease     = 5 * time.Minute
+	telegramMaxAttempts     = 8
+	telegramRetryBase       = 30 * time.Second
+	telegramRetryMax        = time.Hour
+
+	// telegramResultsTop сколько призеров перечисляется в сообщении об итогах
+	telegramResultsTop = 3
+	// telegramAnnouncementMaxLength ограничение текста анонса (лимит сообщения Telegram - 4096 символов)
+	telegramAnnouncementMaxLength = 3000
+	// telegramAnnouncementCooldown минимальный интервал между анонсами одного конкурса
+	telegramAnnouncementCooldown = time.Minute
+)
+
+// telegramContestIDPattern ID конкурса в тексте команды или в ссылке на конкурс
+var telegramContestIDPattern = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`)
+
+const telegramHelpText = `Я присылаю новости конкурсов TopPet: начало голосования и итоги.
+
+/subscribe &lt;ссылка или ID конкурса&gt; - подписаться на конкурс
+/unsubscribe &lt;ссылка или ID конкурса&gt; - отписаться
+/subscriptions - мои подписки
+/unlink - отвязать аккаунт TopPet
+
+Чтобы получать новости о конкурсах, в которых участвуют ваши питомцы, привяжите аккаунт в настройках профиля на сайте.`
+
+func (s *TopPetService) telegramEnabled() bool {
+	return s.telegram.Bot != nil && s.telegram.BotUsername != ""
+}
+
+// GetTelegramStatus состояние привязки Telegram для страницы настроек
+func (s *TopPetService) GetTelegramStatus(ctx context.Context, userID model.UserID) (*model.TelegramStatus, error) {
+	status := &model.TelegramStatus{Enabled: s.telegramEnabled()}
+	if !status.Enabled {
+		return status, nil
+	}
+	status.BotUsername = s.telegram.BotUsername
+
+	account, err := s.repository.GetTelegramAccount(ctx, userID)
+	if errors.Is(err, model.ErrorNotFound) {
+		return status, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	status.Linked = true
+	status.Username = account.Username
+	status.LinkedAt = &account.LinkedAt
+	return status, nil
+}
+
+// CreateTelegramLink выдает одноразовую ссылку на бота; аккаунт привязывается, когда пользователь
+// нажимает Start в чате с ботом. Новая ссылка отменяет выданные ранее.
+func (s *TopPetService) CreateTelegramLink(ctx context.Context, userID model.UserID) (*model.TelegramLink, error) {
+	if !s.telegramEnabled() {
+		return nil, fmt.Errorf("%w: telegram bot is disabled", model.ErrorNotFound)
+	}
+
+	// параметр /start ограничен 64 символами [A-Za-z0-9_-]: base64url от 32 байт - 43 символа
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+	expiresAt := time.Now().Add(telegramLinkTTL)
+	if err := s.repository.CreateTelegramLinkToken(ctx, hashToken(token), userID, expiresAt); err != nil {
+		return nil, err
+	}
+
+	return &model.TelegramLink{
+		URL:       "https://t.me/" + s.telegram.BotUsername + "?start=" + telegramLinkPrefix + token,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// UnlinkTelegram отвязывает Telegram от аккаунта
+func (s *TopPetService) UnlinkTelegram(ctx context.Context, userID model.UserID) error {
+	deleted, err := s.repository.DeleteTelegramAccount(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return fmt.Errorf("%w: telegram is not linked", model.ErrorNotFound)
+	}
+	return nil
+}
+
+// RunTelegramBot получает сообщения боту через long polling и отвечает на команды. Работает до отмены ctx.
+// Bot API не позволяет двум процессам одновременно получать обновления одного бота, поэтому
+// опрос включается только на одном экземпляре.
+func (s *TopPetService) RunTelegramBot(ctx context.Context) {
+	var offset int64
+	for {
+		updates, err := s.telegram.Bot.GetUpdates(ctx, offset, telegramPollTimeout)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("[Service] RunTelegramBot: %v", err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(telegramPollRetryDelay):
+			}
+			continue
+		}
+		for _, update := range updates {
+			s.HandleTelegramUpdate(ctx, update)
+			offset = update.UpdateID + 1
+		}
+	}
+}
+
+// HandleTelegramUpdate отвечает на команду в личном чате с ботом. Сообщения из групп и каналов
+// и обычный текст игнорируются.
+func (s *TopPetService) HandleTelegramUpdate(ctx context.Context, update *model.TelegramUpdate) {
+	if update.ChatID == 0 || update.ChatType != "private" || !strings.HasPrefix(update.Text, "/") {
+		return
+	}
+
+	command, arg, _ := strings.Cut(strings.TrimSpace(update.Text), " ")
+	// в группах команда приходит как /command@bot_username
+	command, _, _ = strings.Cut(strings.ToLower(command), "@")
+	arg = strings.TrimSpace(arg)
+
+	var reply string
+	switch command {
+	case "/start":
+		reply = s.telegramStart(ctx, update, arg)
+	case "/subscribe":
+		reply = s.telegramSubscribe(ctx, update.ChatID, arg)
+	case "/unsubscribe":
+		reply = s.telegramUnsubscribe(ctx, update.ChatID, arg)
+	case "/subscriptions":
+		reply = s.telegramSubscriptions(ctx, update.ChatID)
+	case "/unlink":
+		reply = s.telegramUnlink(ctx, update.ChatID)
+	default:
+		reply = telegramHelpText
+	}
+
+	if err := s.telegram.Bot.SendMessage(ctx, strconv.FormatInt(update.ChatID, 10), reply); err != nil {
+		log.Printf("[Service] HandleTelegramUpdate: chatID=%d, command=%s: %v", update.ChatID, command, err)
+	}
+}
+
+// telegramStart обрабатывает /start: без параметра - приветствие, link_<token> - привязка аккаунта
+// по ссылке с сайта, c_<contestID> - подписка по ссылке со страницы конкурса
+func (s *TopPetService) telegramStart(ctx context.Context, update *model.TelegramUpdate, payload string) string {
+	switch {
+	case strings.HasPrefix(payload, telegramLinkPrefix):
+		userID, err := s.repository.ConsumeTelegramLinkToken(ctx, hashToken(strings.TrimPrefix(payload, telegramLinkPrefix)))
+		if errors.Is(err, model.ErrorNotFound) {
+			return "Ссылка недействительна или устарела. Получите новую в настройках профиля на сайте."
+		}
+		if err == nil {
+			_, err = s.repository.LinkTelegramAccount(ctx, userID, update.ChatID, update.Username)
+		}
+		if err != nil {
+			log.Printf("[Service] telegramStart: link chatID=%d: %v", update.ChatID, err)
+			return "Не удалось привязать аккаунт, попробуйте позже."
+		}
+		return "Аккаунт TopPet привязан. Я буду присылать новости конкурсов, в которых участвуют ваши питомцы.\n\n" + telegramHelpText
+	case strings.HasPrefix(payload, telegramContestPrefix):
+		return s.telegramSubscribe(ctx, update.ChatID, strings.TrimPrefix(payload, telegramContestPrefix))
+	}
+	return "Привет! " + telegramHelpText
+}
+
+func (s *TopPetService) telegramSubscribe(ctx context.Context, chatID int64, arg string) string {
+	contestID, ok := parseTelegramContestRef(arg)
+	if !ok {
+		return "Укажите ссылку на конкурс или его ID: /subscribe &lt;ссылка&gt;"
+	}
+	contest, err := s.GetContest(ctx, contestID)
+	if err != nil || contest.Status == model.ContestStatusDraft {
+		return "Конкурс не найден."
+	}
+	if err := s.repository.SubscribeTelegramChat(ctx, chatID, contest.ID); err != nil {
+		log.Printf("[Service] telegramSubscribe: chatID=%d, contestID=%s: %v", chatID, contest.ID, err)
+		return "Не удалось подписаться, попробуйте позже."
+	}
+	return fmt.Sprintf("Вы подписаны на конкурс <b>%s</b>. Пришлю сообщение, когда начнется голосование и когда появятся итоги.",
+		html.EscapeString(contest.Title))
+}
+
+func (s *TopPetService) telegramUnsubscribe(ctx context.Context, chatID int64, arg string) string {
+	contestID, ok := parseTelegramContestRef(arg)
+	if !ok {
+		return "Укажите ссылку на конкурс или его ID: /unsubscribe &lt;ссылка&gt;"
+	}
+	deleted, err := s.repository.UnsubscribeTelegramChat(ctx, chatID, contestID)
+	if err != nil {
+		log.Printf("[Service] telegramUnsubscribe: chatID=%d, contestID=%s: %v", chatID, contestID, err)
+		return "Не удалось отписаться, попробуйте позже."
+	}
+	if deleted == 0 {
+		return "Вы не подписаны на этот конкурс."
+	}
+	return "Вы отписались от конкурса."
+}
+
+func (s *TopPetService) telegramSubscriptions(ctx context.Context, chatID int64) string {
+	subscriptions, err := s.repository.ListTelegramSubscriptions(ctx, chatID)
+	if err != nil {
+		log.Printf("[Service] telegramSubscriptions: chatID=%d: %v", chatID, err)
+		return "Не удалось получить подписки, попробуйте позже."
+	}
+	if len(subscriptions) == 0 {
+		return "У вас нет подписок. Подписаться: /subscribe &lt;ссылка на конкурс&gt;"
+	}
+	var b strings.Builder
+	b.WriteString("Ваши подписки:\n")
+	for _, subscription := range subscriptions {
+		fmt.Fprintf(&b, "\n<a href=\"%s\">%s</a> - %s", html.EscapeString(s.telegramContestURL(subscription.ContestID)),
+			html.EscapeString(subscription.Title), telegramContestStatusName(subscription.Status))
+	}
+	return b.String()
+}
+
+func (s *TopPetService) telegramUnlink(ctx context.Context, chatID int64) string {
+	account, err := s.repository.GetTelegramAccountByChat(ctx, chatID)
+	if errors.Is(err, model.ErrorNotFound) {
+		return "Этот чат не привязан к аккаунту TopPet."
+	}
+	if err == nil {
+		_, err = s.repository.DeleteTelegramAccount(ctx, account.UserID)
+	}
+	if err != nil {
+		log.Printf("[Service] telegramUnlink: chatID=%d: %v", chatID, err)
+		return "Не удалось отвязать аккаунт, попробуйте позже."
+	}
+	return "Аккаунт TopPet отвязан. Подписки на конкурсы сохранены."
+}
+
+// enqueueTelegramContestStatus ставит в очередь сообщение о смене статуса конкурса для подписчиков
+// и привязанных чатов участников. Ошибки только логируются.
+func (s *TopPetService) enqueueTelegramContestStatus(ctx context.Context, contest *model.Contest) {
+	if !s.telegramEnabled() || contest.Hidden {
+		return
+	}
+	text := s.telegramContestStatusText(ctx, contest)
+	if text == "" {
+		return
+	}
+
+	chatIDs, err := s.repository.ListTelegramContestChats(ctx, contest.ID)
+	if err != nil {
+		log.Printf("[Service] enqueueTelegramContestStatus: contestID=%s: %v", contest.ID, err)
+		return
+	}
+	messages := make([]*model.TelegramMessage, len(chatIDs))
+	for i, chatID := range chatIDs {
+		messages[i] = &model.TelegramMessage{ChatID: strconv.FormatInt(chatID, 10), Text: text}
+	}
+	if err := s.repository.EnqueueTelegramMessages(ctx, messages); err != nil {
+		log.Printf("[Service] enqueueTelegramContestStatus: contestID=%s, messages=%d: %v", contest.ID, len(messages), err)
+	}
+}
+
+func (s *TopPetService) telegramContestStatusText(ctx context.Context, contest *model.Contest) string {
+	title := html.EscapeString(contest.Title)
+	link := html.EscapeString(s.telegramContestURL(contest.ID))
+	switch contest.Status {
+	case model.ContestStatusRegistration:
+		return fmt.Sprintf("Открыт прием заявок в конкурсе <b>%s</b>\n%s", title, link)
+	case model.ContestStatusVoting:
+		return fmt.Sprintf("Началось голосование в конкурсе <b>%s</b>\n%s", title, link)
+	case model.ContestStatusFinished:
+		var b strings.Builder
+		fmt.Fprintf(&b, "Подведены итоги конкурса <b>%s</b>\n", title)
+		for _, line := range s.telegramResultLines(ctx, contest.ID) {
+			b.WriteString("\n" + line)
+		}
+		b.WriteString("\n\n" + link)
+		return b.String()
+	}
+	return ""
+}
+
+// telegramResultLines строки с призерами; скрытые заявки не упоминаются
+func (s *TopPetService) telegramResultLines(ctx context.Context, contestID model.ContestID) []string {
+	results, err := s.GetContestResults(ctx, contestID, "")
+	if err != nil {
+		log.Printf("[Service] telegramResultLines: contestID=%s: %v", contestID, err)
+		return nil
+	}
+	participants, err := s.repository.ListParticipantsByContest(ctx, contestID)
+	if err != nil {
+		log.Printf("[Service] telegramResultLines: contestID=%s: %v", contestID, err)
+		return nil
+	}
+	names := make(map[model.ParticipantID]string, len(participants))
+	for _, participant := range participants {
+		if !participant.Hidden {
+			names[participant.ID] = participant.PetName
+		}
+	}
+
+	items := make([]*model.ParticipantResult, 0, len(results.Items))
+	for _, item := range results.Items {
+		if _, ok := names[item.ParticipantID]; ok && item.Place > 0 {
+			items = append(items, item)
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].Place < items[j].Place })
+
+	lines := make([]string, 0, telegramResultsTop)
+	for _, item := range items {
+		if item.Place > telegramResultsTop {
+			break
+		}
+		lines = append(lines, fmt.Sprintf("%d. %s", item.Place, html.EscapeString(names[item.ParticipantID])))
+	}
+	return lines
+}
+
+// ProcessTelegramOutbox отправляет сообщения, готовые к отправке на момент now; повторы и аренда
+// устроены так же, как в ProcessEmailOutbox.
+func (s *TopPetService) ProcessTelegramOutbox(ctx context.Context, now time.Time) error {
+	if s.telegram.Bot == nil {
+		return nil
+	}
+	for {
+		messages, err := s.repository.ClaimTelegramOutbox(ctx, now, now.Add(telegramOutboxLease), telegramOutboxBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, message := range messages {
+			s.sendTelegramMessage(ctx, message, now)
+		}
+		if len(messages) < telegramOutboxBatchSize {
+			return nil
+		}
+	}
+}
+
+// RunTelegramOutboxWorker периодически отправляет сообщения бота из очереди
+func (s *TopPetService) RunTelegramOutboxWorker(ctx context.Context, interval time.Duration) {
+	runPeriodically(ctx, interval, func(ctx context.Context) {
+		if err := s.ProcessTelegramOutbox(ctx, time.Now()); err != nil {
+			log.Printf("[Service] RunTelegramOutboxWorker: %v", err)
+		}
+	})
+}
+
+func (s *TopPetService) sendTelegramMessage(ctx context.Context, message *model.TelegramMessage, now time.Time) {
+	sendErr := s.telegram.Bot.SendMessage(ctx, message.ChatID, message.Text)
+	if sendErr == nil {
+		if err := s.repository.MarkTelegramMessageSent(ctx, message.ID); err != nil {
+			log.Printf("[Service] sendTelegramMessage: messageID=%s: failed to mark sent: %v", message.ID, err)
+		}
+		return
+	}
+
+	log.Printf("[Service] sendTelegramMessage: messageID=%s, attempt=%d: %v", message.ID, message.Attempts, sendErr)
+	status := model.OutboxPending
+	nextAttemptAt := now.Add(retryDelay(message.Attempts, telegramRetryBase, telegramRetryMax))
+	var limited interface{ RetryAfter() time.Duration }
+	switch {
+	case errors.Is(sendErr, model.ErrTelegramChatUnavailable):
+		// пользователь заблокировал бота: повторять бессмысленно, а чат нужно забыть
+		status = model.OutboxFailed
+		if chatID, err := strconv.ParseInt(message.ChatID, 10, 64); err == nil {
+			if err := s.repository.DeleteTelegramChat(ctx, chatID); err != nil {
+				log.Printf("[Service] sendTelegramMessage: chatID=%d: failed to forget chat: %v", chatID, err)
+			}
+		}
+	case message.Attempts >= telegramMaxAttempts:
+		status = model.OutboxFailed
+	case errors.As(sendErr, &limited) && limited.RetryAfter() > 0:
+		nextAttemptAt = now.Add(limited.RetryAfter())
+	}
+	if err := s.repository.FailTelegramMessageAttempt(ctx, message.ID, status, sendErr.Error(), nextAttemptAt); err != nil {
+		log.Printf("[Service] sendTelegramMessage: messageID=%s: failed to save status %s: %v", message.ID, status, err)
+	}
+}
+
+// AnnounceContestTelegram публикует анонс конкурса в канале. Без text анонс собирается из названия
+// и описания конкурса; свой текст (только для персонала) публикуется как есть (без разметки) со ссылкой на конкурс.
+func (s *TopPetService) AnnounceContestTelegram(ctx context.Context, contestID model.ContestID, userID model.UserID, text string) (*model.TelegramAnnouncement, error) {
+	if s.telegram.Bot == nil || s.telegram.ChannelID == "" {
+		return nil, fmt.Errorf("%w: telegram channel is not configured", model.ErrorNotFound)
+	}
+	contest, err := s.repository.GetContest(ctx, contestID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.CanManageContest(ctx, contest, userID) {
+		return nil, fmt.Errorf("%w: only contest admin can post announcements", model.ErrorForbidden)
+	}
+	if contest.Status == model.ContestStatusDraft || contest.Hidden {
+		return nil, fmt.Errorf("%w: contest must be published to be announced", model.ErrBadRequest)
+	}
+	text = strings.TrimSpace(text)
+	// Канал общий для всей платформы: организаторы публикуют только анонс по шаблону
+	if text != "" && !s.isStaff(ctx, userID) {
+		return nil, fmt.Errorf("%w: only staff can post custom announcement text", model.ErrorForbidden)
+	}
+	if len([]rune(text)) > telegramAnnouncementMaxLength {
+		return nil, fmt.Errorf("%w: text must be at most %d characters", model.ErrBadRequest, telegramAnnouncementMaxLength)
+	}
+
+	if text == "" {
+		text = fmt.Sprintf("<b>%s</b>", html.EscapeString(contest.Title))
+		if description := strings.TrimSpace(contest.Description); description != "" {
+			text += "\n\n" + html.EscapeString(excerpt(description, telegramAnnouncementMaxLength))
+		}
+	} else {
+		text = html.EscapeString(text)
+	}
+	text += "\n\n" + html.EscapeString(s.telegramContestURL(contest.ID))
+
+	return s.repository.CreateTelegramAnnouncement(ctx, &model.TelegramAnnouncement{
+		ContestID:      contest.ID,
+		ChatID:         s.telegram.ChannelID,
+		Text:           text,
+		PostedByUserID: &userID,
+	}, telegramAnnouncementCooldown)
+}
+
+// ListTelegramAnnouncements последние анонсы конкурса со статусом доставки; доступны организаторам
+func (s *TopPetService) ListTelegramAnnouncements(ctx context.Context, contestID model.ContestID, userID model.UserID) ([]*model.TelegramAnnouncement, error) {
+	contest, err := s.repository.GetContest(ctx, contestID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.CanManageContest(ctx, contest, userID) {
+		return nil, fmt.Errorf("%w: only contest admin can view announcements", model.ErrorForbidden)
+	}
+	return s.repository.ListTelegramAnnouncements(ctx, contestID, 50)
+}
+
+func (s *TopPetService) telegramContestURL(contestID model.ContestID) string {
+	return strings.TrimSuffix(s.telegram.SiteURL, "/") + "/contests/" + url.PathEscape(string(contestID))
+}
+
+// parseTelegramContestRef достает ID конкурса из аргумента команды: сам ID или ссылка на конкурс
+func parseTelegramContestRef(arg string) (model.ContestID, bool) {
+	id := telegramContestIDPattern.FindString(arg)
+	if id == "" {
+		return "", false
+	}
+	return model.ContestID(strings.ToLower(id)), true
+}
+
+func telegramContestStatusName(status model.ContestStatus) string {
+	switch status {
+	case model.ContestStatusRegistration:
+		return "прием заявок"
+	case model.ContestStatusVoting:
+		return "голосование"
+	case model.ContestStatusFinished:
+		return "завершен"
+	}
+	return string(status)
+}
@@ -0,0 +1,309 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"toppet/server/internal/model"
+)
+
+const telegramTestContestID = "0f8b6a3e-4c1d-4f5e-9a2b-7c3d1e0f9a8b"
+
+// fakeTelegramBot запоминает отправленные сообщения; errs задает ошибку отправки в конкретный чат
+type fakeTelegramBot struct {
+	sent []fakeTelegramSent
+	errs map[string]error
+}
+
+type fakeTelegramSent struct {
+	chatID string
+	text   string
+}
+
+// fakeRetryAfterError ошибка ограничения частоты, как ее возвращает клиент Bot API
+type fakeRetryAfterError struct{ after time.Duration }
+
+func (e fakeRetryAfterError) Error() string             { return "429 Too Many Requests" }
+func (e fakeRetryAfterError) RetryAfter() time.Duration { return e.after }
+
+func (b *fakeTelegramBot) GetUpdates(ctx context.Context, offset int64, timeout time.Duration) ([]*model.TelegramUpdate, error) {
+	return nil, nil
+}
+
+func (b *fakeTelegramBot) SendMessage(ctx context.Context, chatID, text string) error {
+	if err := b.errs[chatID]; err != nil {
+		return err
+	}
+	b.sent = append(b.sent, fakeTelegramSent{chatID: chatID, text: text})
+	return nil
+}
+
+func (b *fakeTelegramBot) lastText() string {
+	if len(b.sent) == 0 {
+		return ""
+	}
+	return b.sent[len(b.sent)-1].text
+}
+
+func newTelegramService(mockRepo *mockRepository, bot *fakeTelegramBot) *TopPetService {
+	return &TopPetService{
+		repository: mockRepo,
+		telegram: TelegramConfig{
+			Bot:         bot,
+			BotUsername: "TopPetBot",
+			ChannelID:   "@toppet",
+			SiteURL:     "https://top-pet.ru/",
+		},
+	}
+}
+
+func newTelegramTestContest(status model.ContestStatus) (*model.Contest, func(ctx context.Context, contestID model.ContestID) (*model.Contest, error)) {
+	contest := &model.Contest{ID: telegramTestContestID, CreatedByUserID: 1, Title: "Кот & пес", Description: "Лучшие <питомцы>", Status: status}
+	return contest, func(ctx context.Context, contestID model.ContestID) (*model.Contest, error) {
+		if contestID != contest.ID {
+			return nil, model.ErrorNotFound
+		}
+		copied := *contest
+		return &copied, nil
+	}
+}
+
+func privateMessage(chatID int64, text string) *model.TelegramUpdate {
+	return &model.TelegramUpdate{UpdateID: 1, ChatID: chatID, ChatType: "private", Username: "alice", Text: text}
+}
+
+func TestTopPetService_TelegramLinkAccount(t *testing.T) {
+	mockRepo := &mockRepository{}
+	bot := &fakeTelegramBot{}
+	service := newTelegramService(mockRepo, bot)
+	ctx := context.Background()
+
+	link, err := service.CreateTelegramLink(ctx, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	payload, ok := strings.CutPrefix(link.URL, "https://t.me/TopPetBot?start=")
+	if !ok || !strings.HasPrefix(payload, telegramLinkPrefix) || len(payload) > 64 {
+		t.Fatalf("Unexpected link %q", link.URL)
+	}
+
+	service.HandleTelegramUpdate(ctx, privateMessage(500, "/start "+payload))
+	if !strings.Contains(bot.lastText(), "привязан") {
+		t.Errorf("Expected link confirmation, got %q", bot.lastText())
+	}
+	status, err := service.GetTelegramStatus(ctx, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !status.Enabled || !status.Linked || status.Username != "alice" || status.BotUsername != "TopPetBot" {
+		t.Errorf("Unexpected status %+v", status)
+	}
+
+	// Ссылка одноразовая
+	service.HandleTelegramUpdate(ctx, privateMessage(501, "/start "+payload))
+	if !strings.Contains(bot.lastText(), "недействительна") {
+		t.Errorf("Expected used link to be rejected, got %q", bot.lastText())
+	}
+
+	service.HandleTelegramUpdate(ctx, privateMessage(500, "/unlink"))
+	if _, err := mockRepo.GetTelegramAccount(ctx, 10); !errors.Is(err, model.ErrorNotFound) {
+		t.Errorf("Expected account to be unlinked, got %v", err)
+	}
+	if err := service.UnlinkTelegram(ctx, 10); !errors.Is(err, model.ErrorNotFound) {
+		t.Errorf("Expected ErrorNotFound for unlinked account, got %v", err)
+	}
+}
+
+func TestTopPetService_TelegramDisabled(t *testing.T) {
+	service := newTelegramService(&mockRepository{}, &fakeTelegramBot{})
+	service.telegram = TelegramConfig{}
+
+	status, err := service.GetTelegramStatus(context.Background(), 10)
+	if err != nil || status.Enabled {
+		t.Errorf("Expected disabled status, got %+v, %v", status, err)
+	}
+	if _, err := service.CreateTelegramLink(context.Background(), 10); !errors.Is(err, model.ErrorNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestTopPetService_TelegramSubscriptions(t *testing.T) {
+	_, getContest := newTelegramTestContest(model.ContestStatusRegistration)
+	mockRepo := &mockRepository{getContestFunc: getContest}
+	bot := &fakeTelegramBot{}
+	service := newTelegramService(mockRepo, bot)
+	ctx := context.Background()
+
+	service.HandleTelegramUpdate(ctx, privateMessage(500, "/subscribe@TopPetBot https://top-pet.ru/contests/"+telegramTestContestID))
+	if !strings.Contains(bot.lastText(), "<b>Кот &amp; пес</b>") {
+		t.Errorf("Expected subscription confirmation with escaped title, got %q", bot.lastText())
+	}
+	service.HandleTelegramUpdate(ctx, privateMessage(501, "/start "+telegramContestPrefix+telegramTestContestID))
+	if chats, _ := mockRepo.ListTelegramContestChats(ctx, telegramTestContestID); len(chats) != 2 {
+		t.Errorf("Expected 2 subscribed chats, got %v", chats)
+	}
+
+	service.HandleTelegramUpdate(ctx, privateMessage(500, "/subscribe 11111111-2222-3333-4444-555555555555"))
+	if !strings.Contains(bot.lastText(), "не найден") {
+		t.Errorf("Expected unknown contest reply, got %q", bot.lastText())
+	}
+
+	service.HandleTelegramUpdate(ctx, privateMessage(500, "/unsubscribe "+telegramTestContestID))
+	service.HandleTelegramUpdate(ctx, privateMessage(500, "/unsubscribe "+telegramTestContestID))
+	if !strings.Contains(bot.lastText(), "не подписаны") {
+		t.Errorf("Expected not subscribed reply, got %q", bot.lastText())
+	}
+
+	// Сообщения в группах и обычный текст бот не обрабатывает
+	sent := len(bot.sent)
+	service.HandleTelegramUpdate(ctx, &model.TelegramUpdate{ChatID: -100, ChatType: "group", Text: "/subscribe " + telegramTestContestID})
+	service.HandleTelegramUpdate(ctx, privateMessage(500, "привет"))
+	if len(bot.sent) != sent {
+		t.Errorf("Expected no replies, got %v", bot.sent[sent:])
+	}
+}
+
+func TestTopPetService_TelegramContestStatusMessages(t *testing.T) {
+	contest, getContest := newTelegramTestContest(model.ContestStatusRegistration)
+	mockRepo := &mockRepository{
+		getContestFunc: getContest,
+		updateContestStatusFunc: func(ctx context.Context, contestID model.ContestID, status model.ContestStatus) (*model.Contest, error) {
+			contest.Status = status
+			copied := *contest
+			return &copied, nil
+		},
+		contestParticipants: []*model.Participant{
+			{ID: "cat", UserID: 10, PetName: "Барсик", ModerationStatus: model.EntryModerationApproved},
+			{ID: "dog", UserID: 11, PetName: "Шарик", ModerationStatus: model.EntryModerationApproved},
+			{ID: "hidden", UserID: 12, PetName: "Скрытый", ModerationStatus: model.EntryModerationApproved, Hidden: true},
+			{ID: "rejected", UserID: 13, PetName: "Отклоненный", ModerationStatus: model.EntryModerationRejected},
+		},
+		ballots: []*model.Ballot{
+			{VoteID: "v1", Choices: []model.BallotChoice{{ParticipantID: "dog"}}},
+			{VoteID: "v2", Choices: []model.BallotChoice{{ParticipantID: "dog"}}},
+			{VoteID: "v3", Choices: []model.BallotChoice{{ParticipantID: "cat"}}},
+			{VoteID: "v4", Choices: []model.BallotChoice{{ParticipantID: "hidden"}}},
+		},
+		telegramAccounts: map[model.UserID]*model.TelegramAccount{
+			10: {UserID: 10, ChatID: 600},
+			13: {UserID: 13, ChatID: 700},
+		},
+		telegramSubscriptions: map[int64][]model.ContestID{
+			500: {telegramTestContestID},
+			600: {telegramTestContestID},
+		},
+	}
+	service := newTelegramService(mockRepo, &fakeTelegramBot{})
+	ctx := context.Background()
+
+	if _, err := service.UpdateContestStatus(ctx, telegramTestContestID, 1, model.ContestStatusVoting); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// подписчик 500 и участник 10 (он же подписчик 600); отклоненная заявка 13 сообщений не получает
+	if len(mockRepo.telegramOutbox) != 2 || mockRepo.telegramOutbox[0].ChatID != "500" || mockRepo.telegramOutbox[1].ChatID != "600" {
+		t.Fatalf("Expected messages to chats 500 and 600, got %d", len(mockRepo.telegramOutbox))
+	}
+	voting := mockRepo.telegramOutbox[0].Text
+	if !strings.Contains(voting, "Началось голосование") || !strings.Contains(voting, "https://top-pet.ru/contests/"+telegramTestContestID) {
+		t.Errorf("Unexpected voting message %q", voting)
+	}
+
+	if _, err := service.FinishContest(ctx, telegramTestContestID, 1); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	results := mockRepo.telegramOutbox[len(mockRepo.telegramOutbox)-1].Text
+	if !strings.Contains(results, "1. Шарик\n2. Барсик") || strings.Contains(results, "Скрытый") {
+		t.Errorf("Unexpected results message %q", results)
+	}
+}
+
+func TestTopPetService_ProcessTelegramOutbox(t *testing.T) {
+	mockRepo := &mockRepository{
+		telegramAccounts:      map[model.UserID]*model.TelegramAccount{10: {UserID: 10, ChatID: 600}},
+		telegramSubscriptions: map[int64][]model.ContestID{600: {telegramTestContestID}},
+	}
+	bot := &fakeTelegramBot{errs: map[string]error{
+		"600": fmt.Errorf("telegram sendMessage: %w", model.ErrTelegramChatUnavailable),
+		"700": fakeRetryAfterError{after: 7 * time.Second},
+		"800": errors.New("connection reset"),
+	}}
+	service := newTelegramService(mockRepo, bot)
+	ctx := context.Background()
+	now := time.Now()
+
+	_ = mockRepo.EnqueueTelegramMessages(ctx, []*model.TelegramMessage{
+		{ChatID: "500", Text: "ok"},
+		{ChatID: "600", Text: "blocked"},
+		{ChatID: "700", Text: "flood"},
+		{ChatID: "800", Text: "network"},
+	})
+	if err := service.ProcessTelegramOutbox(ctx, now); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	ok, blocked, flood, network := mockRepo.telegramOutbox[0], mockRepo.telegramOutbox[1], mockRepo.telegramOutbox[2], mockRepo.telegramOutbox[3]
+	if ok.status != model.OutboxSent || len(bot.sent) != 1 {
+		t.Errorf("Expected message to 500 to be sent, got %s", ok.status)
+	}
+	if blocked.status != model.OutboxFailed {
+		t.Errorf("Expected message to blocked chat to fail, got %s", blocked.status)
+	}
+	if _, err := mockRepo.GetTelegramAccount(ctx, 10); !errors.Is(err, model.ErrorNotFound) || len(mockRepo.telegramSubscriptions[600]) != 0 {
+		t.Errorf("Expected blocked chat to be forgotten")
+	}
+	if flood.status != model.OutboxPending || !flood.nextAttemptAt.Equal(now.Add(7*time.Second)) {
+		t.Errorf("Expected retry after 7s, got %s at %s", flood.status, flood.nextAttemptAt.Sub(now))
+	}
+	if network.status != model.OutboxPending || !network.nextAttemptAt.Equal(now.Add(telegramRetryBase)) {
+		t.Errorf("Expected retry after %s, got %s at %s", telegramRetryBase, network.status, network.nextAttemptAt.Sub(now))
+	}
+}
+
+func TestTopPetService_AnnounceContestTelegram(t *testing.T) {
+	contest, getContest := newTelegramTestContest(model.ContestStatusDraft)
+	mockRepo := &mockRepository{getContestFunc: getContest}
+	service := newTelegramService(mockRepo, &fakeTelegramBot{})
+	ctx := context.Background()
+
+	if _, err := service.AnnounceContestTelegram(ctx, contest.ID, 1, ""); !errors.Is(err, model.ErrBadRequest) {
+		t.Errorf("Expected ErrBadRequest for draft contest, got %v", err)
+	}
+	contest.Status = model.ContestStatusRegistration
+	if _, err := service.AnnounceContestTelegram(ctx, contest.ID, 2, ""); !errors.Is(err, model.ErrorForbidden) {
+		t.Errorf("Expected ErrorForbidden for non-manager, got %v", err)
+	}
+
+	announcement, err := service.AnnounceContestTelegram(ctx, contest.ID, 1, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := "<b>Кот &amp; пес</b>\n\nЛучшие &lt;питомцы&gt;\n\nhttps://top-pet.ru/contests/" + telegramTestContestID
+	if announcement.ChatID != "@toppet" || announcement.Text != want || announcement.Status != model.OutboxPending {
+		t.Errorf("Unexpected announcement %+v", announcement)
+	}
+	if len(mockRepo.telegramOutbox) != 1 || mockRepo.telegramOutbox[0].ChatID != "@toppet" {
+		t.Errorf("Expected announcement to be queued to the channel")
+	}
+
+	if _, err := service.AnnounceContestTelegram(ctx, contest.ID, 1, ""); !errors.Is(err, model.ErrTooManyRequests) {
+		t.Errorf("Expected ErrTooManyRequests for repeated announcement, got %v", err)
+	}
+	mockRepo.telegramAnnouncements[0].CreatedAt = time.Now().Add(-telegramAnnouncementCooldown)
+
+	// Свой текст в общий канал публикует только персонал
+	if _, err := service.AnnounceContestTelegram(ctx, contest.ID, 1, "<b>Скоро</b> финал"); !errors.Is(err, model.ErrorForbidden) {
+		t.Errorf("Expected ErrorForbidden for organizer custom text, got %v", err)
+	}
+	mockRepo.userRoles = map[model.UserID][]model.Role{1: {model.RoleModerator}}
+	custom, err := service.AnnounceContestTelegram(ctx, contest.ID, 1, "<b>Скоро</b> финал")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(custom.Text, "&lt;b&gt;Скоро&lt;/b&gt; финал\n\n") {
+		t.Errorf("Expected custom text to be escaped, got %q", custom.Text)
+	}
+}
@@ -3,69 +3,85 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	wsapp "toppet/server/internal/app/ws"
 	"toppet/server/internal/model"
 )
 
-func (s *TopPetService) Vote(ctx context.Context, contestID model.ContestID, participantID model.ParticipantID, userID model.UserID) (*model.Vote, error) {
-	// Check contest exists and is in voting status
+// Vote отдает (или переносит) голос пользователя в номинации categoryID (пустая - главный приз).
+// В каждой номинации у пользователя свой голос. choices - бюллетень по режиму конкурса:
+// один участник в single, список в approval/ranked (порядок - места), оценки в stars.
+// Проверяются правила голосования конкурса (ошибки model.CodedError с кодом причины);
+// вместе с голосом сохраняются метаданные для антифрода; аннулированный голос изменить нельзя.
+func (s *TopPetService) Vote(ctx context.Context, contestID model.ContestID, categoryID model.CategoryID, userID model.UserID, choices []model.BallotChoice, client model.VoteClient) (*model.Vote, error) {
 	contest, err := s.repository.GetContest(ctx, contestID)
 	if err != nil {
 		return nil, err
 	}
-
-	if contest.Status != model.ContestStatusVoting {
-		return nil, errors.New("voting is only allowed during voting stage")
+	if err := s.checkContestCategory(ctx, contestID, categoryID); err != nil {
+		return nil, err
 	}
 
-	// Check participant exists and belongs to contest
-	participant, err := s.repository.GetParticipant(ctx, participantID)
+	choices, err = normalizeBallot(contest, choices)
 	if err != nil {
 		return nil, err
 	}
 
-	if participant.ContestID != contestID {
-		return nil, errors.New("participant does not belong to this contest")
+	// Check participants exist and belong to contest
+	participants := make([]*model.Participant, 0, len(choices))
+	for _, choice := range choices {
+		participant, err := s.repository.GetParticipant(ctx, choice.ParticipantID)
+		if err != nil {
+			return nil, err
+		}
+		if participant.ContestID != contestID {
+			return nil, errors.New("participant does not belong to this contest")
+		}
+		if !isEntryPublic(participant) {
+			return nil, fmt.Errorf("%w: participant is not approved", model.ErrBadRequest)
+		}
+		participants = append(participants, participant)
+	}
+
+	// Status, account age, providers, own pet, invite-only list
+	if err := s.checkVoteEligibility(ctx, contest, participants, userID); err != nil {
+		return nil, err
 	}
 
-	var previousParticipantID model.ParticipantID
-	if existingVote, err := s.repository.GetContestVoteByUser(ctx, contestID, userID); err == nil {
-		previousParticipantID = existingVote.ParticipantID
+	var previousParticipantIDs []model.ParticipantID
+	if existingVote, err := s.repository.GetContestVoteByUser(ctx, contestID, categoryID, userID); err == nil && existingVote != nil {
+		if existingVote.VoidedAt != nil && !existingVote.Recastable {
+			return nil, model.NewCodedError(model.ErrorForbidden, model.VoteIneligibleVoided, "your vote in this contest was voided")
+		}
+		previousParticipantIDs = s.ballotParticipantIDs(ctx, contest, existingVote)
 	}
 
-	// Upsert vote (last vote wins)
-	vote, err := s.repository.UpsertContestVote(ctx, contestID, participantID, userID)
+	// Upsert vote (last ballot wins), первый выбор хранится в participant_id голоса
+	var ballotChoices []model.BallotChoice
+	if contestVotingMode(contest) != model.VotingModeSingle {
+		ballotChoices = choices
+	}
+	vote, err := s.repository.UpsertContestVote(ctx, contestID, categoryID, choices[0].ParticipantID, userID, s.voteMetadata(ctx, userID, client), ballotChoices)
 	if err != nil {
 		return nil, err
 	}
+	vote.Choices = ballotChoices
+
+	affected := make([]model.ParticipantID, 0, len(choices)+len(previousParticipantIDs))
+	for _, choice := range choices {
+		affected = append(affected, choice.ParticipantID)
+	}
+	affected = append(affected, previousParticipantIDs...)
+	s.broadcastVoteCounts(ctx, contest, categoryID, wsapp.MessageTypeVoteCreated, affected)
 
 	if s.hub != nil {
-		contestTotalVotes, _ := s.repository.CountVotesByContest(ctx, contestID)
-		participantTotalVotes, _ := s.repository.CountVotesByParticipant(ctx, participantID)
-		payload := wsapp.VoteCountsUpdatedPayload{
-			Type:                wsapp.MessageTypeVoteCreated,
-			ContestID:           contestID,
-			ParticipantID:       participantID,
-			ParticipantTotalVotes: participantTotalVotes,
-			ContestTotalVotes:   contestTotalVotes,
-		}
-		_ = s.hub.BroadcastContestMessage(contestID, payload)
-		if previousParticipantID != "" && previousParticipantID != participantID {
-			previousTotalVotes, _ := s.repository.CountVotesByParticipant(ctx, previousParticipantID)
-			prevPayload := wsapp.VoteCountsUpdatedPayload{
-				Type:                wsapp.MessageTypeVoteCreated,
-				ContestID:           contestID,
-				ParticipantID:       previousParticipantID,
-				ParticipantTotalVotes: previousTotalVotes,
-				ContestTotalVotes:   contestTotalVotes,
-			}
-			_ = s.hub.BroadcastContestMessage(contestID, prevPayload)
-		}
 		userPayload := wsapp.UserVoteUpdatedPayload{
 			Type:          wsapp.MessageTypeVoteCreated,
 			ContestID:     contestID,
-			ParticipantID: participantID,
+			CategoryID:    categoryID,
+			ParticipantID: vote.ParticipantID,
+			Choices:       vote.Choices,
 		}
 		_ = s.hub.SendContestMessageToUser(contestID, userID, userPayload)
 	}
@@ -73,45 +89,60 @@ func (s *TopPetService) Vote(ctx context.Context, contestID model.ContestID, par
 	return vote, nil
 }
 
-func (s *TopPetService) GetUserVote(ctx context.Context, contestID model.ContestID, userID model.UserID) (*model.Vote, error) {
-	vote, err := s.repository.GetContestVoteByUser(ctx, contestID, userID)
+// GetUserVote возвращает голос пользователя в номинации вместе с бюллетенем (для approval/ranked/stars)
+func (s *TopPetService) GetUserVote(ctx context.Context, contestID model.ContestID, categoryID model.CategoryID, userID model.UserID) (*model.Vote, error) {
+	vote, err := s.repository.GetContestVoteByUser(ctx, contestID, categoryID, userID)
 	if err != nil {
 		return nil, err
 	}
+	// Голос, аннулированный из-за дисквалификации участника, считается отсутствующим - можно голосовать заново
+	if vote != nil && vote.VoidedAt != nil && vote.Recastable {
+		return nil, nil
+	}
+	if vote != nil {
+		choices, err := s.repository.ListContestVoteChoices(ctx, vote.ID)
+		if err != nil {
+			return nil, err
+		}
+		if len(choices) > 0 {
+			vote.Choices = choices
+		}
+	}
 	return vote, nil
 }
 
-func (s *TopPetService) Unvote(ctx context.Context, contestID model.ContestID, userID model.UserID) (model.ParticipantID, error) {
+// Unvote отзывает бюллетень пользователя в номинации целиком. Возвращает первый выбор отозванного бюллетеня.
+func (s *TopPetService) Unvote(ctx context.Context, contestID model.ContestID, categoryID model.CategoryID, userID model.UserID) (model.ParticipantID, error) {
 	contest, err := s.repository.GetContest(ctx, contestID)
 	if err != nil {
 		return "", err
 	}
 
 	if contest.Status != model.ContestStatusVoting {
-		return "", errors.New("voting is only allowed during voting stage")
+		return "", model.NewCodedError(model.ErrorForbidden, model.VoteIneligibleVotingClosed, "voting is only allowed during voting stage")
+	}
+
+	// Выбор бюллетеня удаляется каскадно, поэтому запоминаем его до удаления голоса
+	var previousParticipantIDs []model.ParticipantID
+	if s.voteCountsWatched() && contestVotingMode(contest) != model.VotingModeSingle {
+		if existingVote, err := s.repository.GetContestVoteByUser(ctx, contestID, categoryID, userID); err == nil && existingVote != nil && existingVote.VoidedAt == nil {
+			previousParticipantIDs = s.ballotParticipantIDs(ctx, contest, existingVote)
+		}
 	}
 
-	participantID, err := s.repository.DeleteContestVoteByUser(ctx, contestID, userID)
+	participantID, err := s.repository.DeleteContestVoteByUser(ctx, contestID, categoryID, userID)
 	if err != nil {
 		return "", err
 	}
 
+	if participantID != "" {
+		s.broadcastVoteCounts(ctx, contest, categoryID, wsapp.MessageTypeVoteDeleted, append([]model.ParticipantID{participantID}, previousParticipantIDs...))
+	}
 	if s.hub != nil {
-		contestTotalVotes, _ := s.repository.CountVotesByContest(ctx, contestID)
-		if participantID != "" {
-			participantTotalVotes, _ := s.repository.CountVotesByParticipant(ctx, participantID)
-			payload := wsapp.VoteCountsUpdatedPayload{
-				Type:                wsapp.MessageTypeVoteDeleted,
-				ContestID:           contestID,
-				ParticipantID:       participantID,
-				ParticipantTotalVotes: participantTotalVotes,
-				ContestTotalVotes:   contestTotalVotes,
-			}
-			_ = s.hub.BroadcastContestMessage(contestID, payload)
-		}
 		userPayload := wsapp.UserVoteUpdatedPayload{
 			Type:          wsapp.MessageTypeVoteDeleted,
 			ContestID:     contestID,
+			CategoryID:    categoryID,
 			ParticipantID: "",
 		}
 		_ = s.hub.SendContestMessageToUser(contestID, userID, userPayload)
@@ -120,13 +151,13 @@ func (s *TopPetService) Unvote(ctx context.Context, contestID model.ContestID, u
 	return participantID, nil
 }
 
-func (s *TopPetService) ListVotersForParticipant(ctx context.Context, contestID model.ContestID, participantID model.ParticipantID, userID model.UserID) ([]*model.VoterInfo, error) {
+func (s *TopPetService) ListVotersForParticipant(ctx context.Context, contestID model.ContestID, categoryID model.CategoryID, participantID model.ParticipantID, userID model.UserID) ([]*model.VoterInfo, error) {
 	contest, err := s.repository.GetContest(ctx, contestID)
 	if err != nil {
 		return nil, err
 	}
-	if contest.CreatedByUserID != userID {
+	if !s.CanManageContest(ctx, contest, userID) {
 		return nil, model.ErrorForbidden
 	}
-	return s.repository.ListVotersByParticipant(ctx, contestID, participantID)
+	return s.repository.ListVotersByParticipant(ctx, contestID, categoryID, participantID)
 }
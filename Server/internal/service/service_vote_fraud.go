@@ -0,0 +1,324 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	wsapp "toppet/server/internal/app/ws"
+	"toppet/server/internal/model"
+)
+
+const (
+	// Веса признаков подозрительности голоса
+	voteFraudNewAccountScore   = 20
+	voteFraudBurstScore        = 40
+	voteFraudSharedIPScore     = 40
+	voteFraudSharedIPHighScore = 60
+	voteFraudNoUserAgentScore  = 10
+
+	maxVoidVotesPerRequest = 100
+	maxUserAgentLength     = 512
+)
+
+// DefaultVoteFraudConfig возвращает пороги антифрода по умолчанию
+func DefaultVoteFraudConfig() VoteFraudConfig {
+	return VoteFraudConfig{
+		NewAccountAge:  72 * time.Hour,
+		BurstWindow:    10 * time.Minute,
+		BurstSize:      5,
+		SharedIPVoters: 3,
+		FlagScore:      50,
+	}
+}
+
+func (c VoteFraudConfig) withDefaults() VoteFraudConfig {
+	defaults := DefaultVoteFraudConfig()
+	if c.NewAccountAge <= 0 {
+		c.NewAccountAge = defaults.NewAccountAge
+	}
+	if c.BurstWindow <= 0 {
+		c.BurstWindow = defaults.BurstWindow
+	}
+	if c.BurstSize <= 0 {
+		c.BurstSize = defaults.BurstSize
+	}
+	if c.SharedIPVoters <= 0 {
+		c.SharedIPVoters = defaults.SharedIPVoters
+	}
+	if c.FlagScore <= 0 {
+		c.FlagScore = defaults.FlagScore
+	}
+	return c
+}
+
+// voteMetadata собирает метаданные голоса: хэш IP, user agent, возраст аккаунта и провайдер входа.
+// Ошибки чтения профиля не мешают голосованию - метаданные просто будут неполными.
+func (s *TopPetService) voteMetadata(ctx context.Context, userID model.UserID, client model.VoteClient) *model.VoteMetadata {
+	meta := &model.VoteMetadata{
+		IPHash:    s.hashVoterIP(client.IP),
+		UserAgent: truncateString(client.UserAgent, maxUserAgentLength),
+	}
+
+	if user, err := s.repository.GetUser(ctx, userID); err == nil && user != nil && !user.CreatedAt.IsZero() {
+		createdAt := user.CreatedAt
+		meta.AccountCreatedAt = &createdAt
+	}
+
+	if providers, err := s.repository.GetUserAuthProvidersByUserID(ctx, userID); err == nil {
+		names := make([]string, 0, len(providers))
+		for _, provider := range providers {
+			if provider != nil && !slices.Contains(names, provider.Provider) {
+				names = append(names, provider.Provider)
+			}
+		}
+		sort.Strings(names)
+		meta.AuthProvider = strings.Join(names, ",")
+	}
+
+	return meta
+}
+
+// hashVoterIP - IP хранится только в виде HMAC, чтобы по базе нельзя было восстановить адрес
+func (s *TopPetService) hashVoterIP(ip string) string {
+	if ip == "" {
+		return ""
+	}
+	mac := hmac.New(sha256.New, s.voteFraud.IPHashSecret)
+	mac.Write([]byte(ip))
+	return hex.EncodeToString(mac.Sum(nil))[:32]
+}
+
+func truncateString(value string, max int) string {
+	if len(value) <= max {
+		return value
+	}
+	return value[:max]
+}
+
+// voteFraudScore оценка одного голоса
+type voteFraudScore struct {
+	score   int
+	reasons []string
+}
+
+// scoreVotes оценивает голоса одного конкурса: новые аккаунты, всплески голосов новых аккаунтов
+// за одного участника и общий IP у нескольких голосующих.
+func scoreVotes(votes []*model.VoteAudit, cfg VoteFraudConfig) map[string]voteFraudScore {
+	scores := make(map[string]voteFraudScore, len(votes))
+	add := func(voteID string, points int, reason string) {
+		score := scores[voteID]
+		score.score += points
+		score.reasons = append(score.reasons, reason)
+		scores[voteID] = score
+	}
+
+	votersByIP := make(map[string]map[model.UserID]struct{})
+	newAccountVotes := make(map[model.ParticipantID][]*model.VoteAudit)
+	for _, vote := range votes {
+		scores[vote.ID] = voteFraudScore{}
+
+		if vote.AccountCreatedAt != nil && vote.VotedAt.Sub(*vote.AccountCreatedAt) < cfg.NewAccountAge {
+			add(vote.ID, voteFraudNewAccountScore, model.VoteFraudNewAccount)
+			newAccountVotes[vote.ParticipantID] = append(newAccountVotes[vote.ParticipantID], vote)
+		}
+		if vote.UserAgent == "" {
+			add(vote.ID, voteFraudNoUserAgentScore, model.VoteFraudNoUserAgent)
+		}
+		if vote.IPHash != "" {
+			if votersByIP[vote.IPHash] == nil {
+				votersByIP[vote.IPHash] = make(map[model.UserID]struct{})
+			}
+			votersByIP[vote.IPHash][vote.UserID] = struct{}{}
+		}
+	}
+
+	// Всплеск: не меньше BurstSize голосов новых аккаунтов за участника внутри окна BurstWindow
+	for _, participantVotes := range newAccountVotes {
+		if len(participantVotes) < cfg.BurstSize {
+			continue
+		}
+		sort.Slice(participantVotes, func(i, j int) bool {
+			return participantVotes[i].VotedAt.Before(participantVotes[j].VotedAt)
+		})
+		inBurst := make([]bool, len(participantVotes))
+		end := 0
+		for start := range participantVotes {
+			if end < start {
+				end = start
+			}
+			for end+1 < len(participantVotes) && participantVotes[end+1].VotedAt.Sub(participantVotes[start].VotedAt) <= cfg.BurstWindow {
+				end++
+			}
+			if end-start+1 >= cfg.BurstSize {
+				for i := start; i <= end; i++ {
+					inBurst[i] = true
+				}
+			}
+		}
+		for i, vote := range participantVotes {
+			if inBurst[i] {
+				add(vote.ID, voteFraudBurstScore, model.VoteFraudNewAccountBurst)
+			}
+		}
+	}
+
+	for _, vote := range votes {
+		voters := len(votersByIP[vote.IPHash])
+		switch {
+		case vote.IPHash == "" || voters < cfg.SharedIPVoters:
+		case voters >= 2*cfg.SharedIPVoters:
+			add(vote.ID, voteFraudSharedIPHighScore, model.VoteFraudSharedIP)
+		default:
+			add(vote.ID, voteFraudSharedIPScore, model.VoteFraudSharedIP)
+		}
+	}
+
+	return scores
+}
+
+// ScoreContestVotes пересчитывает оценки всех действующих голосов конкурса.
+// Обновляются только голоса, у которых изменилась оценка.
+func (s *TopPetService) ScoreContestVotes(ctx context.Context, contestID model.ContestID) (int, error) {
+	votes, err := s.repository.ListContestVotesForFraudScoring(ctx, contestID)
+	if err != nil {
+		return 0, err
+	}
+
+	scores := scoreVotes(votes, s.voteFraud)
+	flagged := 0
+	for _, vote := range votes {
+		score := scores[vote.ID]
+		if score.score >= s.voteFraud.FlagScore {
+			flagged++
+		}
+		if score.score == vote.FraudScore && slices.Equal(score.reasons, vote.FraudReasons) {
+			continue
+		}
+		if err := s.repository.UpdateContestVoteFraudScore(ctx, vote.ID, score.score, score.reasons, score.score >= s.voteFraud.FlagScore); err != nil {
+			return flagged, err
+		}
+	}
+	return flagged, nil
+}
+
+// RunVoteFraudScoring периодически пересчитывает оценки голосов в конкурсах, где с прошлого
+// прохода появились или изменились голоса
+func (s *TopPetService) RunVoteFraudScoring(ctx context.Context, interval time.Duration) {
+	since := time.Now().Add(-24 * time.Hour)
+	runPeriodically(ctx, interval, func(ctx context.Context) {
+		startedAt := time.Now()
+		contestIDs, err := s.repository.ListContestIDsWithVotesSince(ctx, since)
+		if err != nil {
+			log.Printf("[Service] RunVoteFraudScoring: list contests: %v", err)
+			return
+		}
+		failed := false
+		for _, contestID := range contestIDs {
+			flagged, err := s.ScoreContestVotes(ctx, contestID)
+			if err != nil {
+				log.Printf("[Service] RunVoteFraudScoring: contestID=%s: %v", contestID, err)
+				failed = true
+				continue
+			}
+			if flagged > 0 {
+				log.Printf("[Service] RunVoteFraudScoring: contestID=%s, flagged=%d", contestID, flagged)
+			}
+		}
+		// При ошибке конкурсы будут пересчитаны на следующем проходе
+		if !failed {
+			since = startedAt
+		}
+	})
+}
+
+// ListFlaggedVotes возвращает помеченные голоса конкурса для проверки организатором или персоналом
+func (s *TopPetService) ListFlaggedVotes(ctx context.Context, contestID model.ContestID, actorID model.UserID) ([]*model.VoteAudit, error) {
+	contest, err := s.repository.GetContest(ctx, contestID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.CanManageContest(ctx, contest, actorID) && !s.isStaff(ctx, actorID) {
+		return nil, model.ErrorForbidden
+	}
+
+	return s.repository.ListFlaggedContestVotes(ctx, contestID)
+}
+
+// VoidVotes аннулирует голоса конкурса: они перестают учитываться в счетчиках, а автор не может
+// проголосовать заново. Участникам конкурса рассылаются пересчитанные счетчики.
+func (s *TopPetService) VoidVotes(ctx context.Context, contestID model.ContestID, actorID model.UserID, voteIDs []string, reason string) (int, error) {
+	if len(voteIDs) == 0 {
+		return 0, fmt.Errorf("%w: vote_ids are required", model.ErrBadRequest)
+	}
+	if len(voteIDs) > maxVoidVotesPerRequest {
+		return 0, fmt.Errorf("%w: at most %d votes per request", model.ErrBadRequest, maxVoidVotesPerRequest)
+	}
+
+	contest, err := s.repository.GetContest(ctx, contestID)
+	if err != nil {
+		return 0, err
+	}
+	if !s.CanManageContest(ctx, contest, actorID) && !s.isStaff(ctx, actorID) {
+		return 0, model.ErrorForbidden
+	}
+
+	voided, err := s.repository.VoidContestVotes(ctx, contestID, voteIDs, actorID, strings.TrimSpace(reason))
+	if err != nil {
+		return 0, err
+	}
+
+	s.notifyVotesVoided(ctx, contest, voided, false)
+
+	return len(voided), nil
+}
+
+// notifyVotesVoided рассылает пересчитанные счетчики и сообщает авторам аннулированных голосов.
+// canRecast - автор может проголосовать заново (голос аннулирован из-за дисквалификации участника).
+func (s *TopPetService) notifyVotesVoided(ctx context.Context, contest *model.Contest, voided []*model.Vote, canRecast bool) {
+	if !s.voteCountsWatched() || len(voided) == 0 {
+		return
+	}
+
+	contestID := contest.ID
+
+	// Счетчики пересчитываются отдельно в каждой номинации, где были аннулированы голоса.
+	// В режимах с бюллетенем голос затрагивает нескольких участников - обновляем счетчики всех.
+	byCategory := make(map[model.CategoryID][]model.ParticipantID)
+	for _, vote := range voided {
+		byCategory[vote.CategoryID] = append(byCategory[vote.CategoryID], vote.ParticipantID)
+	}
+	var allParticipantIDs []model.ParticipantID
+	if contestVotingMode(contest) != model.VotingModeSingle {
+		if participants, err := s.repository.ListParticipantsByContest(ctx, contestID); err == nil {
+			for _, participant := range participants {
+				allParticipantIDs = append(allParticipantIDs, participant.ID)
+			}
+		}
+	}
+	for categoryID, participantIDs := range byCategory {
+		if allParticipantIDs != nil {
+			participantIDs = allParticipantIDs
+		}
+		s.broadcastVoteCounts(ctx, contest, categoryID, wsapp.MessageTypeVoteDeleted, participantIDs)
+	}
+
+	if s.hub == nil {
+		return
+	}
+	for _, vote := range voided {
+		_ = s.hub.SendContestMessageToUser(contestID, vote.UserID, wsapp.UserVoteUpdatedPayload{
+			Type:       wsapp.MessageTypeVoteDeleted,
+			ContestID:  contestID,
+			CategoryID: vote.CategoryID,
+			CanRecast:  canRecast,
+		})
+	}
+}
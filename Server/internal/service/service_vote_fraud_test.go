@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"toppet/server/internal/model"
+)
+
+func TestScoreVotes(t *testing.T) {
+	cfg := DefaultVoteFraudConfig()
+	start := time.Date(2025, 1, 24, 12, 0, 0, 0, time.UTC)
+	oldAccount := start.Add(-365 * 24 * time.Hour)
+	newAccount := start.Add(-time.Hour)
+
+	var votes []*model.VoteAudit
+	// Пять новых аккаунтов голосуют за одного участника в течение пяти минут
+	for i := 0; i < 5; i++ {
+		votes = append(votes, &model.VoteAudit{
+			ID:               fmt.Sprintf("burst-%d", i),
+			ParticipantID:    "p1",
+			UserID:           model.UserID(100 + i),
+			IPHash:           fmt.Sprintf("ip-%d", i),
+			UserAgent:        "Mozilla/5.0",
+			AccountCreatedAt: &newAccount,
+			VotedAt:          start.Add(time.Duration(i) * time.Minute),
+		})
+	}
+	// Три старых аккаунта с одного IP
+	for i := 0; i < 3; i++ {
+		votes = append(votes, &model.VoteAudit{
+			ID:               fmt.Sprintf("shared-%d", i),
+			ParticipantID:    "p2",
+			UserID:           model.UserID(200 + i),
+			IPHash:           "shared-ip",
+			UserAgent:        "Mozilla/5.0",
+			AccountCreatedAt: &oldAccount,
+			VotedAt:          start,
+		})
+	}
+	// Обычный голос и новый аккаунт без всплеска
+	votes = append(votes,
+		&model.VoteAudit{ID: "regular", ParticipantID: "p2", UserID: 300, IPHash: "ip-regular", UserAgent: "Mozilla/5.0", AccountCreatedAt: &oldAccount, VotedAt: start},
+		&model.VoteAudit{ID: "lonely-new", ParticipantID: "p3", UserID: 301, IPHash: "ip-lonely", UserAgent: "Mozilla/5.0", AccountCreatedAt: &newAccount, VotedAt: start},
+	)
+
+	scores := scoreVotes(votes, cfg)
+
+	burst := scores["burst-0"]
+	if burst.score < cfg.FlagScore || !slices.Contains(burst.reasons, model.VoteFraudNewAccountBurst) {
+		t.Errorf("burst vote should be flagged, got %+v", burst)
+	}
+	shared := scores["shared-0"]
+	if !slices.Contains(shared.reasons, model.VoteFraudSharedIP) {
+		t.Errorf("shared IP vote should have shared_ip reason, got %+v", shared)
+	}
+	if scores["regular"].score != 0 {
+		t.Errorf("regular vote should not be scored, got %+v", scores["regular"])
+	}
+	lonely := scores["lonely-new"]
+	if lonely.score >= cfg.FlagScore || slices.Contains(lonely.reasons, model.VoteFraudNewAccountBurst) {
+		t.Errorf("single new account vote should not be flagged, got %+v", lonely)
+	}
+}
+
+func TestTopPetService_VoidVotes(t *testing.T) {
+	tests := []struct {
+		name    string
+		actorID model.UserID
+		voteIDs []string
+		wantErr error
+	}{
+		{name: "owner voids votes", actorID: 1, voteIDs: []string{"v1", "v2"}},
+		{name: "moderator voids votes", actorID: 2, voteIDs: []string{"v1"}},
+		{name: "stranger is forbidden", actorID: 3, voteIDs: []string{"v1"}, wantErr: model.ErrorForbidden},
+		{name: "empty request", actorID: 1, wantErr: model.ErrBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockRepository{
+				userRoles: map[model.UserID][]model.Role{2: {model.RoleModerator}},
+				getContestFunc: func(ctx context.Context, contestID model.ContestID) (*model.Contest, error) {
+					return &model.Contest{ID: contestID, CreatedByUserID: 1, Status: model.ContestStatusVoting}, nil
+				},
+			}
+			service := &TopPetService{repository: mockRepo}
+
+			voided, err := service.VoidVotes(context.Background(), "contest-id", tt.actorID, tt.voteIDs, "farm")
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+				}
+				if len(mockRepo.voidedVoteIDs) != 0 {
+					t.Errorf("Expected no votes to be voided")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if voided != len(tt.voteIDs) {
+				t.Errorf("Expected %d voided votes, got %d", len(tt.voteIDs), voided)
+			}
+		})
+	}
+}
@@ -0,0 +1,480 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"slices"
+
+	wsapp "toppet/server/internal/app/ws"
+	"toppet/server/internal/model"
+)
+
+const (
+	maxBallotChoices = 100
+	maxMinBallots    = 100000
+	minStars         = 1
+	maxStars         = 5
+)
+
+// contestVotingMode режим голосования конкурса; у конкурсов без режима - single
+func contestVotingMode(contest *model.Contest) model.VotingMode {
+	if contest == nil || contest.VotingMode == "" {
+		return model.VotingModeSingle
+	}
+	return contest.VotingMode
+}
+
+// UpdateVotingMode меняет режим голосования. Режим можно менять только до начала голосования,
+// чтобы в конкурсе не смешивались бюллетени разных режимов.
+//   - single: один голос, последний выбор заменяет предыдущий
+//   - approval: до maxChoices участников, каждый получает один голос
+//   - ranked: ранжированный бюллетень (maxChoices мест, 0 - без ограничения), итог мгновенным вторым туром
+//   - stars: оценки 1-5 (maxChoices участников, 0 - без ограничения), в рейтинг попадают участники
+//     не менее чем с minBallots оценками
+func (s *TopPetService) UpdateVotingMode(ctx context.Context, contestID model.ContestID, actorID model.UserID, mode model.VotingMode, maxChoices, minBallots int) (*model.Contest, error) {
+	contest, err := s.repository.GetContest(ctx, contestID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.CanManageContest(ctx, contest, actorID) {
+		return nil, model.ErrorForbidden
+	}
+	if contest.Status != model.ContestStatusDraft && contest.Status != model.ContestStatusRegistration {
+		return nil, fmt.Errorf("%w: voting mode can only be changed before voting starts", model.ErrBadRequest)
+	}
+
+	if maxChoices < 0 || maxChoices > maxBallotChoices {
+		return nil, fmt.Errorf("%w: max_choices must be between 0 and %d", model.ErrBadRequest, maxBallotChoices)
+	}
+	if minBallots < 0 || minBallots > maxMinBallots {
+		return nil, fmt.Errorf("%w: min_ballots must be between 0 and %d", model.ErrBadRequest, maxMinBallots)
+	}
+	if minBallots > 0 && mode != model.VotingModeStars {
+		return nil, fmt.Errorf("%w: min_ballots is only supported in stars mode", model.ErrBadRequest)
+	}
+
+	switch mode {
+	case model.VotingModeSingle:
+		maxChoices = 1
+	case model.VotingModeApproval:
+		if maxChoices == 0 {
+			return nil, fmt.Errorf("%w: max_choices is required in approval mode", model.ErrBadRequest)
+		}
+	case model.VotingModeRanked, model.VotingModeStars:
+	default:
+		return nil, fmt.Errorf("%w: unknown voting mode %q", model.ErrBadRequest, mode)
+	}
+
+	return s.repository.UpdateContestVotingMode(ctx, contestID, mode, maxChoices, minBallots)
+}
+
+// normalizeBallot проверяет бюллетень по режиму конкурса. В ranked места проставляются
+// по порядку участников, в stars бюллетень сортируется по убыванию оценки - первый выбор
+// сохраняется как participant_id голоса.
+func normalizeBallot(contest *model.Contest, choices []model.BallotChoice) ([]model.BallotChoice, error) {
+	mode := contestVotingMode(contest)
+	if len(choices) == 0 {
+		return nil, fmt.Errorf("%w: participant_id is required", model.ErrBadRequest)
+	}
+
+	limit := contest.MaxChoices
+	if mode == model.VotingModeSingle {
+		limit = 1
+	}
+	if limit <= 0 || limit > maxBallotChoices {
+		limit = maxBallotChoices
+	}
+	if len(choices) > limit {
+		return nil, fmt.Errorf("%w: at most %d participants per ballot", model.ErrBadRequest, limit)
+	}
+
+	result := make([]model.BallotChoice, 0, len(choices))
+	for i, choice := range choices {
+		if choice.ParticipantID == "" {
+			return nil, fmt.Errorf("%w: participant_id is required", model.ErrBadRequest)
+		}
+		if slices.ContainsFunc(result, func(c model.BallotChoice) bool { return c.ParticipantID == choice.ParticipantID }) {
+			return nil, fmt.Errorf("%w: duplicate participant %s", model.ErrBadRequest, choice.ParticipantID)
+		}
+
+		normalized := model.BallotChoice{ParticipantID: choice.ParticipantID}
+		switch mode {
+		case model.VotingModeRanked:
+			normalized.Rank = i + 1
+		case model.VotingModeStars:
+			if choice.Stars < minStars || choice.Stars > maxStars {
+				return nil, fmt.Errorf("%w: stars must be between %d and %d", model.ErrBadRequest, minStars, maxStars)
+			}
+			normalized.Stars = choice.Stars
+		default:
+			if choice.Stars != 0 {
+				return nil, fmt.Errorf("%w: stars are only allowed in stars mode", model.ErrBadRequest)
+			}
+		}
+		result = append(result, normalized)
+	}
+
+	if mode == model.VotingModeStars {
+		slices.SortStableFunc(result, func(a, b model.BallotChoice) int { return cmp.Compare(b.Stars, a.Stars) })
+	}
+	return result, nil
+}
+
+// ballotParticipantIDs участники, выбранные в голосе: в single - participant_id голоса,
+// в остальных режимах - все строки бюллетеня
+func (s *TopPetService) ballotParticipantIDs(ctx context.Context, contest *model.Contest, vote *model.Vote) []model.ParticipantID {
+	if vote == nil {
+		return nil
+	}
+	if contestVotingMode(contest) == model.VotingModeSingle {
+		return []model.ParticipantID{vote.ParticipantID}
+	}
+	choices, err := s.repository.ListContestVoteChoices(ctx, vote.ID)
+	if err != nil || len(choices) == 0 {
+		return []model.ParticipantID{vote.ParticipantID}
+	}
+	ids := make([]model.ParticipantID, 0, len(choices))
+	for _, choice := range choices {
+		ids = append(ids, choice.ParticipantID)
+	}
+	return ids
+}
+
+// participantVoteCount счетчик участника в номинации для режима: голоса (single), одобрения (approval),
+// первые места (ranked) или число оценок и средняя оценка (stars)
+func (s *TopPetService) participantVoteCount(ctx context.Context, mode model.VotingMode, categoryID model.CategoryID, participantID model.ParticipantID) (int64, *float64) {
+	switch mode {
+	case model.VotingModeApproval, model.VotingModeStars:
+		count, average, err := s.repository.CountVoteChoicesByParticipant(ctx, participantID, categoryID)
+		if err != nil {
+			return 0, nil
+		}
+		if mode == model.VotingModeStars && count > 0 {
+			average = roundScore(average)
+			return count, &average
+		}
+		return count, nil
+	default:
+		count, _ := s.repository.CountVotesByParticipant(ctx, participantID, categoryID)
+		return count, nil
+	}
+}
+
+// broadcastVoteCounts рассылает обновленные счетчики номинации по каждому затронутому участнику.
+// Webhooks получают одно событие со счетчиками всех затронутых участников.
+func (s *TopPetService) broadcastVoteCounts(ctx context.Context, contest *model.Contest, categoryID model.CategoryID, messageType wsapp.MessageType, participantIDs []model.ParticipantID) {
+	if !s.voteCountsWatched() {
+		return
+	}
+	mode := contestVotingMode(contest)
+	contestTotalVotes, _ := s.repository.CountVotesByContest(ctx, contest.ID)
+	event := webhookVotesData{
+		CategoryID:        categoryID,
+		VotingMode:        mode,
+		ContestTotalVotes: contestTotalVotes,
+		Participants:      make([]webhookVoteCount, 0, len(participantIDs)),
+	}
+	sent := make(map[model.ParticipantID]bool, len(participantIDs))
+	for _, participantID := range participantIDs {
+		if participantID == "" || sent[participantID] {
+			continue
+		}
+		sent[participantID] = true
+		participantTotalVotes, averageStars := s.participantVoteCount(ctx, mode, categoryID, participantID)
+		event.Participants = append(event.Participants, webhookVoteCount{
+			ParticipantID: participantID,
+			Votes:         participantTotalVotes,
+			AverageStars:  averageStars,
+		})
+		if s.hub == nil {
+			continue
+		}
+		_ = s.hub.BroadcastContestMessage(contest.ID, wsapp.VoteCountsUpdatedPayload{
+			Type:                  messageType,
+			ContestID:             contest.ID,
+			CategoryID:            categoryID,
+			ParticipantID:         participantID,
+			ParticipantTotalVotes: participantTotalVotes,
+			ContestTotalVotes:     contestTotalVotes,
+			VotingMode:            mode,
+			AverageStars:          averageStars,
+		})
+	}
+	if len(event.Participants) > 0 {
+		s.emitWebhookEvent(ctx, contest.ID, model.WebhookEventVotesUpdated, event)
+	}
+}
+
+// voteCountsWatched есть ли кому сообщать об изменении счетчиков: подписчикам WebSocket или webhooks
+func (s *TopPetService) voteCountsWatched() bool {
+	return s.hub != nil || s.webhooks.Sender != nil
+}
+
+// GetContestResults подсчитывает итоги голосования номинации по режиму конкурса. Для главного приза
+// (пустая номинация) учитываются и оценки жюри.
+func (s *TopPetService) GetContestResults(ctx context.Context, contestID model.ContestID, categoryID model.CategoryID) (*model.ContestResults, error) {
+	contest, err := s.GetContest(ctx, contestID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkContestCategory(ctx, contestID, categoryID); err != nil {
+		return nil, err
+	}
+
+	participants, err := s.repository.ListParticipantsByContest(ctx, contestID)
+	if err != nil {
+		return nil, err
+	}
+	participantIDs := make([]model.ParticipantID, 0, len(participants))
+	for _, participant := range participants {
+		if participant.Hidden && !s.canViewHidden(ctx) {
+			continue
+		}
+		participantIDs = append(participantIDs, participant.ID)
+	}
+
+	ballots, err := s.repository.ListContestBallots(ctx, contestID, categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	results := tallyResults(contest, participantIDs, ballots)
+	if categoryID != "" {
+		results.CategoryID = categoryID
+		return results, nil
+	}
+	if err := s.addJuryResults(ctx, contest, results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// tallyResults считает итоги. Выбор за участников не из participantIDs не учитывается.
+func tallyResults(contest *model.Contest, participantIDs []model.ParticipantID, ballots []*model.Ballot) *model.ContestResults {
+	mode := contestVotingMode(contest)
+	results := &model.ContestResults{
+		ContestID:    contest.ID,
+		VotingMode:   mode,
+		TotalBallots: int64(len(ballots)),
+		Items:        make([]*model.ParticipantResult, 0, len(participantIDs)),
+	}
+
+	items := make(map[model.ParticipantID]*model.ParticipantResult, len(participantIDs))
+	for _, participantID := range participantIDs {
+		item := &model.ParticipantResult{ParticipantID: participantID, Qualified: true}
+		items[participantID] = item
+		results.Items = append(results.Items, item)
+	}
+
+	switch mode {
+	case model.VotingModeRanked:
+		tallyInstantRunoff(results, items, ballots)
+	case model.VotingModeStars:
+		results.MinBallots = contest.MinBallots
+		tallyStars(results, items, ballots)
+	default:
+		tallyVotes(results, items, ballots)
+	}
+	return results
+}
+
+// tallyVotes single/approval: каждый выбор - один голос, равные места делятся
+func tallyVotes(results *model.ContestResults, items map[model.ParticipantID]*model.ParticipantResult, ballots []*model.Ballot) {
+	for _, ballot := range ballots {
+		for _, choice := range ballot.Choices {
+			if item, ok := items[choice.ParticipantID]; ok {
+				item.Votes++
+			}
+		}
+	}
+
+	slices.SortStableFunc(results.Items, func(a, b *model.ParticipantResult) int {
+		return cmp.Or(cmp.Compare(b.Votes, a.Votes), cmp.Compare(a.ParticipantID, b.ParticipantID))
+	})
+	assignPlaces(results, func(a, b *model.ParticipantResult) bool { return a.Votes == b.Votes })
+}
+
+// tallyStars средняя оценка; участники с числом оценок меньше порога не участвуют в рейтинге
+func tallyStars(results *model.ContestResults, items map[model.ParticipantID]*model.ParticipantResult, ballots []*model.Ballot) {
+	sums := make(map[model.ParticipantID]int64, len(items))
+	for _, ballot := range ballots {
+		for _, choice := range ballot.Choices {
+			if item, ok := items[choice.ParticipantID]; ok && choice.Stars > 0 {
+				item.Votes++
+				sums[choice.ParticipantID] += int64(choice.Stars)
+			}
+		}
+	}
+
+	minBallots := int64(max(results.MinBallots, 1))
+	for participantID, item := range items {
+		item.Qualified = item.Votes >= minBallots
+		if item.Votes > 0 {
+			average := roundScore(float64(sums[participantID]) / float64(item.Votes))
+			item.AverageStars = &average
+		}
+	}
+
+	averageOf := func(item *model.ParticipantResult) float64 {
+		if item.AverageStars == nil {
+			return 0
+		}
+		return *item.AverageStars
+	}
+	slices.SortStableFunc(results.Items, func(a, b *model.ParticipantResult) int {
+		if a.Qualified != b.Qualified {
+			if a.Qualified {
+				return -1
+			}
+			return 1
+		}
+		return cmp.Or(
+			cmp.Compare(averageOf(b), averageOf(a)),
+			cmp.Compare(b.Votes, a.Votes),
+			cmp.Compare(a.ParticipantID, b.ParticipantID),
+		)
+	})
+	assignPlaces(results, func(a, b *model.ParticipantResult) bool {
+		return averageOf(a) == averageOf(b) && a.Votes == b.Votes
+	})
+}
+
+// tallyInstantRunoff мгновенный второй тур: в каждом туре бюллетень отдается первому
+// оставшемуся участнику; побеждает набравший больше половины действующих бюллетеней,
+// иначе выбывает участник с наименьшим числом голосов (при равенстве - с меньшим числом
+// голосов в первом туре, затем по идентификатору).
+func tallyInstantRunoff(results *model.ContestResults, items map[model.ParticipantID]*model.ParticipantResult, ballots []*model.Ballot) {
+	for _, ballot := range ballots {
+		slices.SortStableFunc(ballot.Choices, func(a, b model.BallotChoice) int { return cmp.Compare(a.Rank, b.Rank) })
+	}
+
+	continuing := make(map[model.ParticipantID]bool, len(items))
+	for participantID := range items {
+		continuing[participantID] = true
+	}
+
+	var firstRound map[model.ParticipantID]int64
+	var eliminated []model.ParticipantID
+	var lastCounts map[model.ParticipantID]int64
+	for round := 1; len(continuing) > 0; round++ {
+		counts := make(map[model.ParticipantID]int64, len(continuing))
+		for participantID := range continuing {
+			counts[participantID] = 0
+		}
+		var exhausted int64
+		for _, ballot := range ballots {
+			idx := slices.IndexFunc(ballot.Choices, func(c model.BallotChoice) bool { return continuing[c.ParticipantID] })
+			if idx < 0 {
+				exhausted++
+				continue
+			}
+			counts[ballot.Choices[idx].ParticipantID]++
+		}
+		if firstRound == nil {
+			firstRound = counts
+		}
+		lastCounts = counts
+
+		runoffRound := &model.RunoffRound{Round: round, Counts: counts, Exhausted: exhausted}
+		results.Rounds = append(results.Rounds, runoffRound)
+
+		active := int64(len(ballots)) - exhausted
+		if active == 0 {
+			break
+		}
+		leader := pickRunoffCandidate(counts, firstRound, 1)
+		if counts[leader]*2 > active || len(continuing) == 1 {
+			results.WinnerID = leader
+			break
+		}
+
+		loser := pickRunoffCandidate(counts, firstRound, -1)
+		runoffRound.Eliminated = loser
+		delete(continuing, loser)
+		eliminated = append(eliminated, loser)
+		items[loser].Votes = counts[loser]
+		items[loser].EliminatedRound = round
+	}
+
+	for participantID := range continuing {
+		items[participantID].Votes = lastCounts[participantID]
+	}
+
+	// Оставшиеся - по голосам последнего тура, выбывшие - в обратном порядке выбывания
+	eliminatedAt := make(map[model.ParticipantID]int, len(eliminated))
+	for i, participantID := range eliminated {
+		eliminatedAt[participantID] = i
+	}
+	slices.SortStableFunc(results.Items, func(a, b *model.ParticipantResult) int {
+		aOut, bOut := a.EliminatedRound > 0, b.EliminatedRound > 0
+		switch {
+		case aOut && bOut:
+			return cmp.Compare(eliminatedAt[b.ParticipantID], eliminatedAt[a.ParticipantID])
+		case aOut:
+			return 1
+		case bOut:
+			return -1
+		}
+		if a.ParticipantID == results.WinnerID {
+			return -1
+		}
+		if b.ParticipantID == results.WinnerID {
+			return 1
+		}
+		return cmp.Or(cmp.Compare(b.Votes, a.Votes), cmp.Compare(a.ParticipantID, b.ParticipantID))
+	})
+	if results.TotalBallots > 0 {
+		for i, item := range results.Items {
+			item.Place = i + 1
+		}
+	}
+}
+
+// pickRunoffCandidate выбирает лидера (direction = 1) или выбывающего (direction = -1)
+func pickRunoffCandidate(counts, firstRound map[model.ParticipantID]int64, direction int) model.ParticipantID {
+	var best model.ParticipantID
+	for participantID := range counts {
+		if best == "" {
+			best = participantID
+			continue
+		}
+		c := cmp.Or(
+			cmp.Compare(counts[participantID], counts[best]),
+			cmp.Compare(firstRound[participantID], firstRound[best]),
+			// при полном равенстве выбывает больший идентификатор, лидирует меньший
+			cmp.Compare(best, participantID),
+		)
+		if c*direction > 0 {
+			best = participantID
+		}
+	}
+	return best
+}
+
+// assignPlaces проставляет места по отсортированному списку: равные результаты делят место,
+// участники вне рейтинга (Qualified = false) места не получают. Победитель - единственный первый.
+func assignPlaces(results *model.ContestResults, equal func(a, b *model.ParticipantResult) bool) {
+	if results.TotalBallots == 0 {
+		return
+	}
+	for i, item := range results.Items {
+		if !item.Qualified || item.Votes == 0 {
+			continue
+		}
+		item.Place = i + 1
+		if i > 0 && results.Items[i-1].Place > 0 && equal(results.Items[i-1], item) {
+			item.Place = results.Items[i-1].Place
+		}
+	}
+	if len(results.Items) > 0 && results.Items[0].Place == 1 &&
+		(len(results.Items) == 1 || results.Items[1].Place != 1) {
+		results.WinnerID = results.Items[0].ParticipantID
+	}
+}
+
+// roundScore округляет оценку до сотых
+func roundScore(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,273 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"toppet/server/internal/model"
+)
+
+func ballot(voteID string, participantIDs ...model.ParticipantID) *model.Ballot {
+	b := &model.Ballot{VoteID: voteID}
+	for i, participantID := range participantIDs {
+		b.Choices = append(b.Choices, model.BallotChoice{ParticipantID: participantID, Rank: i + 1})
+	}
+	return b
+}
+
+func ratings(voteID string, stars map[model.ParticipantID]int) *model.Ballot {
+	b := &model.Ballot{VoteID: voteID}
+	for participantID, value := range stars {
+		b.Choices = append(b.Choices, model.BallotChoice{ParticipantID: participantID, Stars: value})
+	}
+	return b
+}
+
+func resultByID(results *model.ContestResults, participantID model.ParticipantID) *model.ParticipantResult {
+	for _, item := range results.Items {
+		if item.ParticipantID == participantID {
+			return item
+		}
+	}
+	return nil
+}
+
+func TestTallyResults_Approval(t *testing.T) {
+	contest := &model.Contest{ID: "contest-id", VotingMode: model.VotingModeApproval, MaxChoices: 2}
+	ballots := []*model.Ballot{
+		ballot("v1", "a", "b"),
+		ballot("v2", "a"),
+		ballot("v3", "b", "c"),
+		ballot("v4", "a", "unknown"),
+	}
+
+	results := tallyResults(contest, []model.ParticipantID{"a", "b", "c"}, ballots)
+
+	if results.WinnerID != "a" || results.TotalBallots != 4 {
+		t.Fatalf("Expected winner a of 4 ballots, got %+v", results)
+	}
+	want := map[model.ParticipantID]struct {
+		votes int64
+		place int
+	}{"a": {3, 1}, "b": {2, 2}, "c": {1, 3}}
+	for participantID, w := range want {
+		item := resultByID(results, participantID)
+		if item.Votes != w.votes || item.Place != w.place {
+			t.Errorf("%s: expected %d votes place %d, got %+v", participantID, w.votes, w.place, item)
+		}
+	}
+}
+
+func TestTallyResults_RankedInstantRunoff(t *testing.T) {
+	contest := &model.Contest{ID: "contest-id", VotingMode: model.VotingModeRanked}
+	// Первый тур: a - 4, b - 3, c - 2. Ни у кого нет большинства, выбывает c,
+	// его голоса уходят b, и b побеждает 5:4.
+	ballots := []*model.Ballot{
+		ballot("v1", "a"),
+		ballot("v2", "a", "b"),
+		ballot("v3", "a", "c"),
+		ballot("v4", "a"),
+		ballot("v5", "b"),
+		ballot("v6", "b", "a"),
+		ballot("v7", "b"),
+		ballot("v8", "c", "b"),
+		ballot("v9", "c", "b", "a"),
+	}
+
+	results := tallyResults(contest, []model.ParticipantID{"a", "b", "c"}, ballots)
+
+	if results.WinnerID != "b" {
+		t.Fatalf("Expected b to win after runoff, got %q", results.WinnerID)
+	}
+	if len(results.Rounds) != 2 || results.Rounds[0].Eliminated != "c" {
+		t.Fatalf("Expected two rounds with c eliminated first, got %+v", results.Rounds)
+	}
+	if got := results.Rounds[1].Counts; got["b"] != 5 || got["a"] != 4 {
+		t.Errorf("Unexpected final round counts: %v", got)
+	}
+	order := []model.ParticipantID{"b", "a", "c"}
+	for i, participantID := range order {
+		if results.Items[i].ParticipantID != participantID || results.Items[i].Place != i+1 {
+			t.Errorf("Place %d: expected %s, got %+v", i+1, participantID, results.Items[i])
+		}
+	}
+	if c := resultByID(results, "c"); c.EliminatedRound != 1 {
+		t.Errorf("Expected c eliminated in round 1, got %+v", c)
+	}
+}
+
+func TestTallyResults_StarsMinBallots(t *testing.T) {
+	contest := &model.Contest{ID: "contest-id", VotingMode: model.VotingModeStars, MinBallots: 2}
+	ballots := []*model.Ballot{
+		ratings("v1", map[model.ParticipantID]int{"a": 4, "b": 5, "c": 5}),
+		ratings("v2", map[model.ParticipantID]int{"a": 5, "b": 3}),
+		ratings("v3", map[model.ParticipantID]int{"a": 3, "b": 5}),
+	}
+
+	results := tallyResults(contest, []model.ParticipantID{"a", "b", "c"}, ballots)
+
+	if results.WinnerID != "b" {
+		t.Fatalf("Expected b to win, got %q", results.WinnerID)
+	}
+	b := resultByID(results, "b")
+	if b.AverageStars == nil || *b.AverageStars != 4.33 || b.Votes != 3 || b.Place != 1 {
+		t.Errorf("Unexpected result for b: %+v", b)
+	}
+	a := resultByID(results, "a")
+	if a.AverageStars == nil || *a.AverageStars != 4 || a.Place != 2 {
+		t.Errorf("Unexpected result for a: %+v", a)
+	}
+	// c с единственной оценкой 5 не проходит порог и не получает места
+	c := resultByID(results, "c")
+	if c.Qualified || c.Place != 0 || results.Items[len(results.Items)-1] != c {
+		t.Errorf("Expected c to be unqualified and last, got %+v", c)
+	}
+}
+
+func TestNormalizeBallot(t *testing.T) {
+	tests := []struct {
+		name    string
+		contest *model.Contest
+		choices []model.BallotChoice
+		want    []model.BallotChoice
+		wantErr bool
+	}{
+		{
+			name:    "single accepts one participant",
+			contest: &model.Contest{},
+			choices: []model.BallotChoice{{ParticipantID: "a"}},
+			want:    []model.BallotChoice{{ParticipantID: "a"}},
+		},
+		{
+			name:    "single rejects several participants",
+			contest: &model.Contest{VotingMode: model.VotingModeSingle, MaxChoices: 1},
+			choices: []model.BallotChoice{{ParticipantID: "a"}, {ParticipantID: "b"}},
+			wantErr: true,
+		},
+		{
+			name:    "approval over the limit",
+			contest: &model.Contest{VotingMode: model.VotingModeApproval, MaxChoices: 2},
+			choices: []model.BallotChoice{{ParticipantID: "a"}, {ParticipantID: "b"}, {ParticipantID: "c"}},
+			wantErr: true,
+		},
+		{
+			name:    "ranked assigns ranks in order",
+			contest: &model.Contest{VotingMode: model.VotingModeRanked},
+			choices: []model.BallotChoice{{ParticipantID: "b", Rank: 7}, {ParticipantID: "a"}},
+			want:    []model.BallotChoice{{ParticipantID: "b", Rank: 1}, {ParticipantID: "a", Rank: 2}},
+		},
+		{
+			name:    "duplicate participant",
+			contest: &model.Contest{VotingMode: model.VotingModeRanked},
+			choices: []model.BallotChoice{{ParticipantID: "a"}, {ParticipantID: "a"}},
+			wantErr: true,
+		},
+		{
+			name:    "stars sorted by score",
+			contest: &model.Contest{VotingMode: model.VotingModeStars},
+			choices: []model.BallotChoice{{ParticipantID: "a", Stars: 3}, {ParticipantID: "b", Stars: 5}},
+			want:    []model.BallotChoice{{ParticipantID: "b", Stars: 5}, {ParticipantID: "a", Stars: 3}},
+		},
+		{
+			name:    "stars out of range",
+			contest: &model.Contest{VotingMode: model.VotingModeStars},
+			choices: []model.BallotChoice{{ParticipantID: "a", Stars: 6}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeBallot(tt.contest, tt.choices)
+			if tt.wantErr {
+				if !errors.Is(err, model.ErrBadRequest) {
+					t.Fatalf("Expected bad request, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Expected %v, got %v", tt.want, got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Choice %d: expected %+v, got %+v", i, tt.want[i], got[i])
+				}
+			}
+		})
+	}
+}
+
+func TestTopPetService_UpdateVotingMode(t *testing.T) {
+	status := model.ContestStatusRegistration
+	mockRepo := &mockRepository{
+		getContestFunc: func(ctx context.Context, contestID model.ContestID) (*model.Contest, error) {
+			return &model.Contest{ID: contestID, CreatedByUserID: 1, Status: status}, nil
+		},
+	}
+	service := &TopPetService{repository: mockRepo}
+	ctx := context.Background()
+
+	if _, err := service.UpdateVotingMode(ctx, "contest-id", 2, model.VotingModeRanked, 3, 0); !errors.Is(err, model.ErrorForbidden) {
+		t.Errorf("Expected forbidden for non-organizer, got %v", err)
+	}
+	if _, err := service.UpdateVotingMode(ctx, "contest-id", 1, "plurality", 0, 0); !errors.Is(err, model.ErrBadRequest) {
+		t.Errorf("Expected bad request for unknown mode, got %v", err)
+	}
+	if _, err := service.UpdateVotingMode(ctx, "contest-id", 1, model.VotingModeApproval, 0, 0); !errors.Is(err, model.ErrBadRequest) {
+		t.Errorf("Expected bad request for approval without max_choices, got %v", err)
+	}
+	if _, err := service.UpdateVotingMode(ctx, "contest-id", 1, model.VotingModeRanked, 0, 5); !errors.Is(err, model.ErrBadRequest) {
+		t.Errorf("Expected bad request for min_ballots outside stars mode, got %v", err)
+	}
+
+	contest, err := service.UpdateVotingMode(ctx, "contest-id", 1, model.VotingModeSingle, 5, 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if contest.MaxChoices != 1 {
+		t.Errorf("Expected single mode to force max_choices=1, got %d", contest.MaxChoices)
+	}
+
+	status = model.ContestStatusVoting
+	if _, err := service.UpdateVotingMode(ctx, "contest-id", 1, model.VotingModeStars, 0, 3); !errors.Is(err, model.ErrBadRequest) {
+		t.Errorf("Expected bad request once voting started, got %v", err)
+	}
+	if mockRepo.votingModeUpdates != 1 {
+		t.Errorf("Expected exactly one saved update, got %d", mockRepo.votingModeUpdates)
+	}
+}
+
+func TestTopPetService_VoteRankedBallot(t *testing.T) {
+	mockRepo := &mockRepository{
+		participants: map[model.ParticipantID]*model.Participant{
+			"a": {ID: "a", ContestID: "contest-id", UserID: 3},
+			"b": {ID: "b", ContestID: "contest-id", UserID: 4},
+			"x": {ID: "x", ContestID: "other-contest", UserID: 5},
+		},
+		getContestFunc: func(ctx context.Context, contestID model.ContestID) (*model.Contest, error) {
+			return &model.Contest{ID: contestID, Status: model.ContestStatusVoting, VotingMode: model.VotingModeRanked, MaxChoices: 3}, nil
+		},
+	}
+	service := &TopPetService{repository: mockRepo}
+	ctx := context.Background()
+
+	if _, err := service.Vote(ctx, "contest-id", "", 2, []model.BallotChoice{{ParticipantID: "a"}, {ParticipantID: "x"}}, model.VoteClient{}); err == nil {
+		t.Fatal("Expected error for participant from another contest")
+	}
+
+	vote, err := service.Vote(ctx, "contest-id", "", 2, []model.BallotChoice{{ParticipantID: "b"}, {ParticipantID: "a"}}, model.VoteClient{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if vote.ParticipantID != "b" {
+		t.Errorf("Expected first choice b as vote participant, got %s", vote.ParticipantID)
+	}
+	want := []model.BallotChoice{{ParticipantID: "b", Rank: 1}, {ParticipantID: "a", Rank: 2}}
+	if len(mockRepo.voteChoices) != len(want) || mockRepo.voteChoices[0] != want[0] || mockRepo.voteChoices[1] != want[1] {
+		t.Errorf("Expected stored choices %v, got %v", want, mockRepo.voteChoices)
+	}
+}
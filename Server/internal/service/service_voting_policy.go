@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"toppet/server/internal/model"
+)
+
+const (
+	maxMinAccountAgeHours   = 24 * 365
+	maxRequiredProviders    = 10
+	maxInvitedVotersPerCall = 100
+)
+
+// GetVotingPolicy возвращает правила голосования конкурса. Если правила не заданы - ограничений нет.
+func (s *TopPetService) GetVotingPolicy(ctx context.Context, contestID model.ContestID) (*model.VotingPolicy, error) {
+	if _, err := s.GetContest(ctx, contestID); err != nil {
+		return nil, err
+	}
+	return s.votingPolicy(ctx, contestID)
+}
+
+func (s *TopPetService) votingPolicy(ctx context.Context, contestID model.ContestID) (*model.VotingPolicy, error) {
+	policy, err := s.repository.GetContestVotingPolicy(ctx, contestID)
+	if errors.Is(err, model.ErrorNotFound) {
+		return &model.VotingPolicy{ContestID: contestID, RequiredProviders: []string{}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// UpdateVotingPolicy сохраняет правила голосования (владелец и организаторы конкурса)
+func (s *TopPetService) UpdateVotingPolicy(ctx context.Context, contestID model.ContestID, actorID model.UserID, policy *model.VotingPolicy) (*model.VotingPolicy, error) {
+	contest, err := s.repository.GetContest(ctx, contestID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.CanManageContest(ctx, contest, actorID) {
+		return nil, model.ErrorForbidden
+	}
+	if contest.Status == model.ContestStatusFinished {
+		return nil, fmt.Errorf("%w: contest is finished", model.ErrBadRequest)
+	}
+
+	if policy.MinAccountAgeHours < 0 || policy.MinAccountAgeHours > maxMinAccountAgeHours {
+		return nil, fmt.Errorf("%w: min_account_age_hours must be between 0 and %d", model.ErrBadRequest, maxMinAccountAgeHours)
+	}
+	providers, err := s.normalizeRequiredProviders(policy.RequiredProviders)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repository.UpsertContestVotingPolicy(ctx, &model.VotingPolicy{
+		ContestID:              contestID,
+		MinAccountAgeHours:     policy.MinAccountAgeHours,
+		RequiredProviders:      providers,
+		ExcludeOwnParticipants: policy.ExcludeOwnParticipants,
+		InviteOnly:             policy.InviteOnly,
+	})
+}
+
+// normalizeRequiredProviders приводит имена провайдеров к нижнему регистру, убирает дубли
+// и проверяет, что провайдер настроен на сервере
+func (s *TopPetService) normalizeRequiredProviders(providers []string) ([]string, error) {
+	if len(providers) > maxRequiredProviders {
+		return nil, fmt.Errorf("%w: at most %d required providers", model.ErrBadRequest, maxRequiredProviders)
+	}
+
+	result := make([]string, 0, len(providers))
+	for _, provider := range providers {
+		provider = strings.ToLower(strings.TrimSpace(provider))
+		if provider == "" {
+			return nil, fmt.Errorf("%w: empty provider name", model.ErrBadRequest)
+		}
+		if _, ok := s.providersUserData[provider]; len(s.providersUserData) > 0 && !ok && provider != EmailProviderName {
+			return nil, fmt.Errorf("%w: unknown provider %q", model.ErrBadRequest, provider)
+		}
+		if !slices.Contains(result, provider) {
+			result = append(result, provider)
+		}
+	}
+	return result, nil
+}
+
+// GetVoteEligibility сообщает, может ли пользователь голосовать в номинации categoryID (пустая - главный приз),
+// и код причины отказа
+func (s *TopPetService) GetVoteEligibility(ctx context.Context, contestID model.ContestID, categoryID model.CategoryID, userID model.UserID) (*model.VoteEligibility, error) {
+	contest, err := s.GetContest(ctx, contestID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkContestCategory(ctx, contestID, categoryID); err != nil {
+		return nil, err
+	}
+
+	if vote, err := s.repository.GetContestVoteByUser(ctx, contestID, categoryID, userID); err == nil && vote != nil && vote.VoidedAt != nil && !vote.Recastable {
+		return &model.VoteEligibility{Reason: model.VoteIneligibleVoided}, nil
+	}
+
+	if err := s.checkVoteEligibility(ctx, contest, nil, userID); err != nil {
+		var codedErr *model.CodedError
+		if errors.As(err, &codedErr) {
+			return &model.VoteEligibility{Reason: codedErr.Code}, nil
+		}
+		return nil, err
+	}
+	return &model.VoteEligibility{Eligible: true}, nil
+}
+
+// checkVoteEligibility проверяет статус конкурса и правила голосования. participants - участники
+// из бюллетеня; если их нет, правило о голосовании за собственного питомца не проверяется.
+func (s *TopPetService) checkVoteEligibility(ctx context.Context, contest *model.Contest, participants []*model.Participant, userID model.UserID) error {
+	if contest.Status != model.ContestStatusVoting {
+		return model.NewCodedError(model.ErrorForbidden, model.VoteIneligibleVotingClosed, "voting is only allowed during voting stage")
+	}
+
+	policy, err := s.votingPolicy(ctx, contest.ID)
+	if err != nil {
+		return err
+	}
+
+	if policy.ExcludeOwnParticipants {
+		for _, participant := range participants {
+			if participant.UserID == userID {
+				return model.NewCodedError(model.ErrorForbidden, model.VoteIneligibleOwnParticipant, "you cannot vote for your own pet")
+			}
+		}
+	}
+
+	if policy.MinAccountAgeHours > 0 {
+		user, err := s.repository.GetUser(ctx, userID)
+		if err != nil {
+			return err
+		}
+		minAge := time.Duration(policy.MinAccountAgeHours) * time.Hour
+		if user == nil || time.Since(user.CreatedAt) < minAge {
+			return model.NewCodedError(model.ErrorForbidden, model.VoteIneligibleAccountTooNew,
+				fmt.Sprintf("account must be at least %d hours old to vote", policy.MinAccountAgeHours))
+		}
+	}
+
+	if len(policy.RequiredProviders) > 0 {
+		providers, err := s.repository.GetUserAuthProvidersByUserID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		linked := slices.ContainsFunc(providers, func(provider *model.UserAuthProvider) bool {
+			return provider != nil && slices.Contains(policy.RequiredProviders, provider.Provider)
+		})
+		if !linked {
+			return model.NewCodedError(model.ErrorForbidden, model.VoteIneligibleProviderRequired,
+				"sign in with one of: "+strings.Join(policy.RequiredProviders, ", "))
+		}
+	}
+
+	if policy.InviteOnly {
+		invited, err := s.repository.IsContestInvitedVoter(ctx, contest.ID, userID)
+		if err != nil {
+			return err
+		}
+		if !invited {
+			return model.NewCodedError(model.ErrorForbidden, model.VoteIneligibleNotInvited, "voting in this contest is by invitation only")
+		}
+	}
+
+	return nil
+}
+
+// ListInvitedVoters возвращает список приглашенных голосующих (владелец и организаторы)
+func (s *TopPetService) ListInvitedVoters(ctx context.Context, contestID model.ContestID, actorID model.UserID) ([]*model.InvitedVoter, error) {
+	contest, err := s.repository.GetContest(ctx, contestID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.CanManageContest(ctx, contest, actorID) {
+		return nil, model.ErrorForbidden
+	}
+	return s.repository.ListContestInvitedVoters(ctx, contestID)
+}
+
+// AddInvitedVoters добавляет пользователей в список голосующих. Повторное добавление не ошибка.
+func (s *TopPetService) AddInvitedVoters(ctx context.Context, contestID model.ContestID, actorID model.UserID, userIDs []model.UserID) error {
+	if len(userIDs) == 0 {
+		return fmt.Errorf("%w: user_ids are required", model.ErrBadRequest)
+	}
+	if len(userIDs) > maxInvitedVotersPerCall {
+		return fmt.Errorf("%w: at most %d users per request", model.ErrBadRequest, maxInvitedVotersPerCall)
+	}
+
+	contest, err := s.repository.GetContest(ctx, contestID)
+	if err != nil {
+		return err
+	}
+	if !s.CanManageContest(ctx, contest, actorID) {
+		return model.ErrorForbidden
+	}
+
+	for _, userID := range userIDs {
+		if _, err := s.repository.GetUser(ctx, userID); err != nil {
+			return err
+		}
+		if err := s.repository.AddContestInvitedVoter(ctx, contestID, userID, actorID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RemoveInvitedVoter убирает пользователя из списка голосующих. Уже отданный голос остается.
+func (s *TopPetService) RemoveInvitedVoter(ctx context.Context, contestID model.ContestID, actorID, userID model.UserID) error {
+	contest, err := s.repository.GetContest(ctx, contestID)
+	if err != nil {
+		return err
+	}
+	if !s.CanManageContest(ctx, contest, actorID) {
+		return model.ErrorForbidden
+	}
+	return s.repository.DeleteContestInvitedVoter(ctx, contestID, userID)
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"toppet/server/internal/model"
+)
+
+func TestTopPetService_VoteEligibility(t *testing.T) {
+	oldAccount := time.Now().Add(-30 * 24 * time.Hour)
+	newAccount := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name       string
+		policy     *model.VotingPolicy
+		userID     model.UserID
+		status     model.ContestStatus
+		wantReason string
+	}{
+		{name: "no policy", userID: 2, status: model.ContestStatusVoting},
+		{name: "voting closed", userID: 2, status: model.ContestStatusRegistration, wantReason: model.VoteIneligibleVotingClosed},
+		{name: "account too new", policy: &model.VotingPolicy{MinAccountAgeHours: 72}, userID: 3, status: model.ContestStatusVoting, wantReason: model.VoteIneligibleAccountTooNew},
+		{name: "old account", policy: &model.VotingPolicy{MinAccountAgeHours: 72}, userID: 2, status: model.ContestStatusVoting},
+		{name: "provider required", policy: &model.VotingPolicy{RequiredProviders: []string{"yandex"}}, userID: 3, status: model.ContestStatusVoting, wantReason: model.VoteIneligibleProviderRequired},
+		{name: "provider linked", policy: &model.VotingPolicy{RequiredProviders: []string{"yandex"}}, userID: 2, status: model.ContestStatusVoting},
+		{name: "not invited", policy: &model.VotingPolicy{InviteOnly: true}, userID: 3, status: model.ContestStatusVoting, wantReason: model.VoteIneligibleNotInvited},
+		{name: "invited", policy: &model.VotingPolicy{InviteOnly: true}, userID: 2, status: model.ContestStatusVoting},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockRepository{
+				users: map[model.UserID]*model.User{
+					2: {ID: 2, CreatedAt: oldAccount},
+					3: {ID: 3, CreatedAt: newAccount},
+				},
+				authProviders: map[model.UserID][]*model.UserAuthProvider{
+					2: {{UserID: 2, Provider: "yandex"}},
+					3: {{UserID: 3, Provider: "vk"}},
+				},
+				invitedVoters: map[model.UserID]bool{2: true},
+				votingPolicy:  tt.policy,
+				getContestFunc: func(ctx context.Context, contestID model.ContestID) (*model.Contest, error) {
+					return &model.Contest{ID: contestID, CreatedByUserID: 1, Status: tt.status}, nil
+				},
+			}
+			service := &TopPetService{repository: mockRepo}
+
+			eligibility, err := service.GetVoteEligibility(context.Background(), "contest-id", "", tt.userID)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if eligibility.Eligible != (tt.wantReason == "") || eligibility.Reason != tt.wantReason {
+				t.Errorf("Expected reason %q, got %+v", tt.wantReason, eligibility)
+			}
+		})
+	}
+}
+
+func TestTopPetService_VoteForOwnParticipant(t *testing.T) {
+	mockRepo := &mockRepository{
+		votingPolicy: &model.VotingPolicy{ExcludeOwnParticipants: true},
+		participants: map[model.ParticipantID]*model.Participant{
+			"own":   {ID: "own", ContestID: "contest-id", UserID: 2},
+			"other": {ID: "other", ContestID: "contest-id", UserID: 3},
+		},
+		getContestFunc: func(ctx context.Context, contestID model.ContestID) (*model.Contest, error) {
+			return &model.Contest{ID: contestID, CreatedByUserID: 1, Status: model.ContestStatusVoting}, nil
+		},
+	}
+	service := &TopPetService{repository: mockRepo}
+	ctx := context.Background()
+
+	_, err := service.Vote(ctx, "contest-id", "", 2, []model.BallotChoice{{ParticipantID: "own"}}, model.VoteClient{})
+	var codedErr *model.CodedError
+	if !errors.As(err, &codedErr) || codedErr.Code != model.VoteIneligibleOwnParticipant || !errors.Is(err, model.ErrorForbidden) {
+		t.Fatalf("Expected own_participant error, got %v", err)
+	}
+
+	if _, err := service.Vote(ctx, "contest-id", "", 2, []model.BallotChoice{{ParticipantID: "other"}}, model.VoteClient{}); err != nil {
+		t.Errorf("Unexpected error voting for another pet: %v", err)
+	}
+}
+
+func TestTopPetService_UpdateVotingPolicy(t *testing.T) {
+	mockRepo := &mockRepository{
+		getContestFunc: func(ctx context.Context, contestID model.ContestID) (*model.Contest, error) {
+			return &model.Contest{ID: contestID, CreatedByUserID: 1, Status: model.ContestStatusRegistration}, nil
+		},
+	}
+	service := &TopPetService{repository: mockRepo, providersUserData: map[string]ProviderUserData{"yandex": nil, "vk": nil}}
+	ctx := context.Background()
+
+	if _, err := service.UpdateVotingPolicy(ctx, "contest-id", 2, &model.VotingPolicy{}); !errors.Is(err, model.ErrorForbidden) {
+		t.Errorf("Expected forbidden for non-organizer, got %v", err)
+	}
+	if _, err := service.UpdateVotingPolicy(ctx, "contest-id", 1, &model.VotingPolicy{RequiredProviders: []string{"myspace"}}); !errors.Is(err, model.ErrBadRequest) {
+		t.Errorf("Expected bad request for unknown provider, got %v", err)
+	}
+
+	policy, err := service.UpdateVotingPolicy(ctx, "contest-id", 1, &model.VotingPolicy{RequiredProviders: []string{" Yandex", "yandex", "email"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(policy.RequiredProviders) != 2 || policy.RequiredProviders[0] != "yandex" || policy.RequiredProviders[1] != "email" {
+		t.Errorf("Expected normalized providers, got %v", policy.RequiredProviders)
+	}
+}
+
+func TestTopPetService_VoteEligibilityByCategory(t *testing.T) {
+	voidedAt := time.Now()
+	mockRepo := &mockRepository{
+		categories: []*model.ContestCategory{{ID: "best-photo", ContestID: "contest-id"}},
+		userVote:   &model.Vote{ID: "vote-1", CategoryID: "best-photo", UserID: 2, VoidedAt: &voidedAt},
+		getContestFunc: func(ctx context.Context, contestID model.ContestID) (*model.Contest, error) {
+			return &model.Contest{ID: contestID, CreatedByUserID: 1, Status: model.ContestStatusVoting}, nil
+		},
+	}
+	service := &TopPetService{repository: mockRepo}
+	ctx := context.Background()
+
+	// Аннулированный голос в номинации не мешает голосовать за главный приз
+	eligibility, err := service.GetVoteEligibility(ctx, "contest-id", "best-photo", 2)
+	if err != nil || eligibility.Eligible || eligibility.Reason != model.VoteIneligibleVoided {
+		t.Errorf("Expected vote_voided in category, got %+v, %v", eligibility, err)
+	}
+	eligibility, err = service.GetVoteEligibility(ctx, "contest-id", "", 2)
+	if err != nil || !eligibility.Eligible {
+		t.Errorf("Expected eligible for main prize, got %+v, %v", eligibility, err)
+	}
+	if _, err := service.GetVoteEligibility(ctx, "contest-id", "unknown", 2); !errors.Is(err, model.ErrorNotFound) {
+		t.Errorf("Expected not found for unknown category, got %v", err)
+	}
+}
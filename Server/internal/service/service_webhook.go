@@ -0,0 +1,391 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"toppet/server/internal/model"
+)
+
+const (
+	// webhookMaxEndpoints сколько webhooks можно зарегистрировать на один конкурс
+	webhookMaxEndpoints  = 10
+	webhookMaxURLLength  = 2048
+	webhookSecretPrefix  = "whsec_"
+	webhookSecretBytes   = 32
+	webhookDeliveryBatch = 20
+	// webhookDeliveryLease аренда пачки: webhookDeliveryBatch запросов с таймаутом клиента должны в нее уложиться
+	webhookDeliveryLease = 5 * time.Minute
+	webhookMaxAttempts   = 10
+	webhookRetryBase     = 30 * time.Second
+	webhookRetryMax      = 6 * time.Hour
+)
+
+type (
+	webhookParticipantData struct {
+		ParticipantID model.ParticipantID `json:"participant_id"`
+		PetName       string              `json:"pet_name"`
+	}
+
+	webhookDisqualificationData struct {
+		ParticipantID model.ParticipantID `json:"participant_id"`
+		Reason        string              `json:"reason"`
+		VoidedVotes   int                 `json:"voided_votes"`
+	}
+
+	webhookContestStatusData struct {
+		Status         model.ContestStatus `json:"status"`
+		PreviousStatus model.ContestStatus `json:"previous_status"`
+	}
+
+	// webhookVotesData счетчики участников, затронутых голосом; votes считается так же, как в WebSocket vote_created
+	webhookVotesData struct {
+		CategoryID        model.CategoryID   `json:"category_id,omitempty"`
+		VotingMode        model.VotingMode   `json:"voting_mode"`
+		ContestTotalVotes int64              `json:"contest_total_votes"`
+		Participants      []webhookVoteCount `json:"participants"`
+	}
+
+	webhookVoteCount struct {
+		ParticipantID model.ParticipantID `json:"participant_id"`
+		Votes         int64               `json:"votes"`
+		AverageStars  *float64            `json:"average_stars,omitempty"`
+	}
+
+	webhookRoundStartedData struct {
+		Round    int                     `json:"round"`
+		StartsAt time.Time               `json:"starts_at"`
+		EndsAt   time.Time               `json:"ends_at"`
+		Matchups []*model.BracketMatchup `json:"matchups"`
+	}
+
+	webhookMatchupDecidedData struct {
+		Round      int                 `json:"round"`
+		MatchupID  string              `json:"matchup_id"`
+		WinnerID   model.ParticipantID `json:"winner_id,omitempty"`
+		VotesA     int64               `json:"votes_a"`
+		VotesB     int64               `json:"votes_b"`
+		ChampionID model.ParticipantID `json:"champion_id,omitempty"`
+	}
+)
+
+// ListWebhooks webhooks конкурса без ключей подписи. Доступно организаторам конкурса.
+func (s *TopPetService) ListWebhooks(ctx context.Context, contestID model.ContestID, userID model.UserID) ([]*model.WebhookEndpoint, error) {
+	if _, err := s.webhookManagedContest(ctx, contestID, userID); err != nil {
+		return nil, err
+	}
+	endpoints, err := s.repository.ListWebhookEndpoints(ctx, contestID)
+	if err != nil {
+		return nil, err
+	}
+	for _, endpoint := range endpoints {
+		endpoint.Secret = ""
+	}
+	return endpoints, nil
+}
+
+// CreateWebhook регистрирует webhook конкурса. Ключ подписи генерируется сервером и виден только в ответе.
+func (s *TopPetService) CreateWebhook(ctx context.Context, contestID model.ContestID, userID model.UserID, rawURL string, eventTypes []model.WebhookEventType) (*model.WebhookEndpoint, error) {
+	if _, err := s.webhookManagedContest(ctx, contestID, userID); err != nil {
+		return nil, err
+	}
+	webhookURL, err := s.validateWebhookURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if eventTypes, err = normalizeWebhookEventTypes(eventTypes); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repository.ListWebhookEndpoints(ctx, contestID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= webhookMaxEndpoints {
+		return nil, fmt.Errorf("%w: contest can have at most %d webhooks", model.ErrBadRequest, webhookMaxEndpoints)
+	}
+
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+	return s.repository.CreateWebhookEndpoint(ctx, &model.WebhookEndpoint{
+		ContestID:       contestID,
+		URL:             webhookURL,
+		Secret:          secret,
+		EventTypes:      eventTypes,
+		CreatedByUserID: &userID,
+	})
+}
+
+// UpdateWebhook меняет адрес, события или включает/выключает webhook. С rotateSecret выдается новый ключ
+// подписи (он возвращается в ответе), прежний перестает действовать сразу.
+func (s *TopPetService) UpdateWebhook(ctx context.Context, webhookID string, userID model.UserID, update *model.WebhookEndpointUpdate, rotateSecret bool) (*model.WebhookEndpoint, error) {
+	endpoint, err := s.managedWebhook(ctx, webhookID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if update.URL != nil {
+		if endpoint.URL, err = s.validateWebhookURL(*update.URL); err != nil {
+			return nil, err
+		}
+	}
+	if update.EventTypes != nil {
+		if endpoint.EventTypes, err = normalizeWebhookEventTypes(*update.EventTypes); err != nil {
+			return nil, err
+		}
+	}
+	if update.Active != nil {
+		endpoint.Active = *update.Active
+	}
+	if rotateSecret {
+		if endpoint.Secret, err = newWebhookSecret(); err != nil {
+			return nil, err
+		}
+	}
+
+	updated, err := s.repository.UpdateWebhookEndpoint(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if !rotateSecret {
+		updated.Secret = ""
+	}
+	return updated, nil
+}
+
+// DeleteWebhook удаляет webhook вместе с недоставленными событиями и журналом
+func (s *TopPetService) DeleteWebhook(ctx context.Context, webhookID string, userID model.UserID) error {
+	if _, err := s.managedWebhook(ctx, webhookID, userID); err != nil {
+		return err
+	}
+	return s.repository.DeleteWebhookEndpoint(ctx, webhookID)
+}
+
+// ListWebhookDeliveries журнал доставок webhook, новые первыми
+func (s *TopPetService) ListWebhookDeliveries(ctx context.Context, webhookID string, userID model.UserID, limit, offset int) ([]*model.WebhookDelivery, int64, error) {
+	if _, err := s.managedWebhook(ctx, webhookID, userID); err != nil {
+		return nil, 0, err
+	}
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return s.repository.ListWebhookDeliveries(ctx, webhookID, limit, offset)
+}
+
+// RedeliverWebhook ставит событие доставки в очередь еще раз. Создается новая доставка, прежняя остается в журнале.
+func (s *TopPetService) RedeliverWebhook(ctx context.Context, webhookID, deliveryID string, userID model.UserID) (*model.WebhookDelivery, error) {
+	endpoint, err := s.managedWebhook(ctx, webhookID, userID)
+	if err != nil {
+		return nil, err
+	}
+	delivery, err := s.repository.GetWebhookDelivery(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if delivery.EndpointID != endpoint.ID {
+		return nil, fmt.Errorf("%w: delivery does not belong to this webhook", model.ErrorNotFound)
+	}
+	if delivery.Status == model.OutboxPending {
+		return nil, fmt.Errorf("%w: delivery is still pending", model.ErrBadRequest)
+	}
+	if !endpoint.Active {
+		return nil, fmt.Errorf("%w: webhook is disabled", model.ErrBadRequest)
+	}
+	return s.repository.RedeliverWebhook(ctx, deliveryID)
+}
+
+// ProcessWebhookDeliveries отправляет доставки, готовые к отправке на момент now. Доставки забираются
+// через SKIP LOCKED с арендой, поэтому воркер может работать на нескольких экземплярах.
+func (s *TopPetService) ProcessWebhookDeliveries(ctx context.Context, now time.Time) error {
+	if s.webhooks.Sender == nil {
+		return nil
+	}
+	for {
+		jobs, err := s.repository.ClaimWebhookDeliveries(ctx, now, now.Add(webhookDeliveryLease), webhookDeliveryBatch)
+		if err != nil {
+			return err
+		}
+		for _, job := range jobs {
+			s.sendWebhookDelivery(ctx, job, now)
+		}
+		if len(jobs) < webhookDeliveryBatch {
+			return nil
+		}
+	}
+}
+
+// RunWebhookDeliveryWorker периодически отправляет события на webhooks
+func (s *TopPetService) RunWebhookDeliveryWorker(ctx context.Context, interval time.Duration) {
+	runPeriodically(ctx, interval, func(ctx context.Context) {
+		if err := s.ProcessWebhookDeliveries(ctx, time.Now()); err != nil {
+			log.Printf("[Service] RunWebhookDeliveryWorker: %v", err)
+		}
+	})
+}
+
+func (s *TopPetService) sendWebhookDelivery(ctx context.Context, job *model.WebhookDeliveryJob, now time.Time) {
+	body, err := json.Marshal(job.Event)
+	if err != nil {
+		log.Printf("[Service] sendWebhookDelivery: deliveryID=%s: %v", job.ID, err)
+		return
+	}
+	// время подписи - момент отправки: пачка может отправляться дольше, чем получатель допускает расхождение
+	response, sendErr := s.webhooks.Sender.Send(ctx, &model.WebhookRequest{
+		URL:        job.URL,
+		Secret:     job.Secret,
+		DeliveryID: job.ID,
+		EventType:  job.Event.Type,
+		Body:       body,
+		Timestamp:  time.Now(),
+	})
+
+	status, lastError, nextAttemptAt := model.OutboxSent, "", now
+	if sendErr != nil {
+		log.Printf("[Service] sendWebhookDelivery: deliveryID=%s, attempt=%d: %v", job.ID, job.Attempts, sendErr)
+		status, lastError = model.OutboxPending, sendErr.Error()
+		nextAttemptAt = now.Add(retryDelay(job.Attempts, webhookRetryBase, webhookRetryMax))
+		if job.Attempts >= webhookMaxAttempts {
+			status = model.OutboxFailed
+		}
+	}
+	if err := s.repository.FinishWebhookDeliveryAttempt(ctx, job.ID, status, response, lastError, nextAttemptAt); err != nil {
+		log.Printf("[Service] sendWebhookDelivery: deliveryID=%s: failed to save status %s: %v", job.ID, status, err)
+	}
+}
+
+// emitWebhookEvent ставит событие конкурса в очередь доставки подписанным webhooks. Ошибка только
+// логируется: сбой очереди не должен отменять действие, которое породило событие.
+func (s *TopPetService) emitWebhookEvent(ctx context.Context, contestID model.ContestID, eventType model.WebhookEventType, data any) {
+	if s.webhooks.Sender == nil {
+		return
+	}
+	payload, err := json.Marshal(data)
+	if err != nil {
+		log.Printf("[Service] emitWebhookEvent: contestID=%s, type=%s: %v", contestID, eventType, err)
+		return
+	}
+	if _, err := s.repository.EnqueueWebhookEvent(ctx, contestID, eventType, payload); err != nil {
+		log.Printf("[Service] emitWebhookEvent: contestID=%s, type=%s: %v", contestID, eventType, err)
+	}
+}
+
+// emitContestStatusWebhooks смена статуса конкурса; при завершении следом отправляются итоги
+func (s *TopPetService) emitContestStatusWebhooks(ctx context.Context, contest *model.Contest, previous model.ContestStatus) {
+	if s.webhooks.Sender == nil {
+		return
+	}
+	s.emitWebhookEvent(ctx, contest.ID, model.WebhookEventContestStatusUpdated, webhookContestStatusData{
+		Status:         contest.Status,
+		PreviousStatus: previous,
+	})
+	if contest.Status != model.ContestStatusFinished {
+		return
+	}
+	results, err := s.GetContestResults(ctx, contest.ID, "")
+	if err != nil {
+		log.Printf("[Service] emitContestStatusWebhooks: contestID=%s: results: %v", contest.ID, err)
+		return
+	}
+	s.emitWebhookEvent(ctx, contest.ID, model.WebhookEventResultsPublished, results)
+}
+
+// emitParticipantCreatedWebhook заявка стала видна всем: принята без премодерации или впервые одобрена
+func (s *TopPetService) emitParticipantCreatedWebhook(ctx context.Context, participant *model.Participant) {
+	s.emitWebhookEvent(ctx, participant.ContestID, model.WebhookEventParticipantCreated, webhookParticipantData{
+		ParticipantID: participant.ID,
+		PetName:       participant.PetName,
+	})
+}
+
+// webhookManagedContest конкурс, webhooks которого может настраивать userID
+func (s *TopPetService) webhookManagedContest(ctx context.Context, contestID model.ContestID, userID model.UserID) (*model.Contest, error) {
+	if s.webhooks.Sender == nil {
+		return nil, fmt.Errorf("%w: webhooks are disabled", model.ErrorNotFound)
+	}
+	contest, err := s.repository.GetContest(ctx, contestID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.CanManageContest(ctx, contest, userID) {
+		return nil, fmt.Errorf("%w: only contest admin can manage webhooks", model.ErrorForbidden)
+	}
+	return contest, nil
+}
+
+// managedWebhook webhook с ключом подписи, если userID может управлять его конкурсом
+func (s *TopPetService) managedWebhook(ctx context.Context, webhookID string, userID model.UserID) (*model.WebhookEndpoint, error) {
+	if s.webhooks.Sender == nil {
+		return nil, fmt.Errorf("%w: webhooks are disabled", model.ErrorNotFound)
+	}
+	endpoint, err := s.repository.GetWebhookEndpoint(ctx, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.webhookManagedContest(ctx, endpoint.ContestID, userID); err != nil {
+		return nil, err
+	}
+	return endpoint, nil
+}
+
+// validateWebhookURL абсолютный https адрес без логина и пароля; http - только с AllowInsecureURLs
+func (s *TopPetService) validateWebhookURL(rawURL string) (string, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	if rawURL == "" || len(rawURL) > webhookMaxURLLength {
+		return "", fmt.Errorf("%w: url is required and must be at most %d characters", model.ErrBadRequest, webhookMaxURLLength)
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host == "" || parsed.Hostname() == "" {
+		return "", fmt.Errorf("%w: invalid url", model.ErrBadRequest)
+	}
+	if parsed.User != nil {
+		return "", fmt.Errorf("%w: url must not contain credentials", model.ErrBadRequest)
+	}
+	switch parsed.Scheme {
+	case "https":
+	case "http":
+		if !s.webhooks.AllowInsecureURLs {
+			return "", fmt.Errorf("%w: url must use https", model.ErrBadRequest)
+		}
+	default:
+		return "", fmt.Errorf("%w: url must use https", model.ErrBadRequest)
+	}
+	parsed.Fragment = ""
+	return parsed.String(), nil
+}
+
+// normalizeWebhookEventTypes проверяет типы событий и убирает повторы; пустой список - все события
+func normalizeWebhookEventTypes(eventTypes []model.WebhookEventType) ([]model.WebhookEventType, error) {
+	result := make([]model.WebhookEventType, 0, len(eventTypes))
+	for _, eventType := range eventTypes {
+		if !slices.Contains(model.WebhookEventTypes, eventType) {
+			return nil, fmt.Errorf("%w: unknown event type %q", model.ErrBadRequest, eventType)
+		}
+		if !slices.Contains(result, eventType) {
+			result = append(result, eventType)
+		}
+	}
+	return result, nil
+}
+
+func newWebhookSecret() (string, error) {
+	raw := make([]byte, webhookSecretBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return webhookSecretPrefix + hex.EncodeToString(raw), nil
+}
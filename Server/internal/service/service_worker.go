@@ -0,0 +1,22 @@
+package service
+
+import (
+	"context"
+	"time"
+)
+
+// runPeriodically вызывает fn сразу и затем каждые interval. Работает до отмены ctx.
+func runPeriodically(ctx context.Context, interval time.Duration, fn func(ctx context.Context)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		fn(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
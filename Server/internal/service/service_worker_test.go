@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRunPeriodically(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := make(chan struct{}, 1)
+	done := make(chan struct{})
+	go func() {
+		runPeriodically(ctx, time.Millisecond, func(ctx context.Context) {
+			select {
+			case calls <- struct{}{}:
+			default:
+			}
+		})
+		close(done)
+	}()
+
+	// Первый вызов сразу, следующие - по тикеру
+	for i := 0; i < 2; i++ {
+		select {
+		case <-calls:
+		case <-time.After(time.Second):
+			t.Fatalf("Expected call %d", i+1)
+		}
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Expected worker to stop after ctx is canceled")
+	}
+}
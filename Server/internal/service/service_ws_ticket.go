@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"toppet/server/internal/model"
+)
+
+// WSTicketTTL время жизни билета на подключение к WebSocket
+const WSTicketTTL = 30 * time.Second
+
+// IssueWSTicket выдает одноразовый короткоживущий билет для подключения к WebSocket.
+// Билет передается в ?ticket= вместо access токена, поэтому в логах остается только он.
+func (s *TopPetService) IssueWSTicket(ctx context.Context, userID model.UserID) (*model.WSTicket, error) {
+	// Заодно чистим просроченные билеты, отдельный джоб для этого не нужен
+	_ = s.repository.DeleteExpiredWSTickets(ctx)
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, err
+	}
+	ticket := base64.RawURLEncoding.EncodeToString(raw)
+	expiresAt := time.Now().Add(WSTicketTTL)
+
+	if err := s.repository.CreateWSTicket(ctx, hashToken(ticket), userID, expiresAt); err != nil {
+		return nil, err
+	}
+
+	return &model.WSTicket{Ticket: ticket, ExpiresAt: expiresAt}, nil
+}
+
+// ExchangeWSTicket гасит билет и возвращает ID пользователя, которому он был выдан.
+// Билет удаленного аккаунта не принимается.
+func (s *TopPetService) ExchangeWSTicket(ctx context.Context, ticket string) (model.UserID, error) {
+	if ticket == "" {
+		return 0, fmt.Errorf("%w: ticket is required", model.ErrUnauthorized)
+	}
+	userID, err := s.repository.ConsumeWSTicket(ctx, hashToken(ticket))
+	if err != nil {
+		return 0, fmt.Errorf("%w: ticket is invalid, expired or already used", model.ErrUnauthorized)
+	}
+	if _, err := s.activeUserRoles(ctx, userID); err != nil {
+		return 0, err
+	}
+	return userID, nil
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"toppet/server/internal/model"
+)
+
+func TestTopPetService_ExchangeWSTicket(t *testing.T) {
+	mockRepo := &mockRepository{}
+	service := &TopPetService{repository: mockRepo}
+	ctx := context.Background()
+
+	if _, err := service.ExchangeWSTicket(ctx, ""); !errors.Is(err, model.ErrUnauthorized) {
+		t.Errorf("Expected ErrUnauthorized for empty ticket, got %v", err)
+	}
+
+	ticket, err := service.IssueWSTicket(ctx, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, ok := mockRepo.wsTickets[ticket.Ticket]; ok {
+		t.Error("Expected only ticket hash to be stored")
+	}
+	userID, err := service.ExchangeWSTicket(ctx, ticket.Ticket)
+	if err != nil || userID != 10 {
+		t.Fatalf("Expected user 10, got %d, %v", userID, err)
+	}
+
+	// Билет одноразовый
+	if _, err := service.ExchangeWSTicket(ctx, ticket.Ticket); !errors.Is(err, model.ErrUnauthorized) {
+		t.Errorf("Expected ErrUnauthorized for reused ticket, got %v", err)
+	}
+
+	expired, err := service.IssueWSTicket(ctx, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	mockRepo.wsTickets[hashToken(expired.Ticket)].expiresAt = time.Now().Add(-time.Second)
+	if _, err := service.ExchangeWSTicket(ctx, expired.Ticket); !errors.Is(err, model.ErrUnauthorized) {
+		t.Errorf("Expected ErrUnauthorized for expired ticket, got %v", err)
+	}
+
+	// Билет, выданный до удаления аккаунта, не пускает в WebSocket
+	deleted, err := service.IssueWSTicket(ctx, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	deletedAt := time.Now()
+	mockRepo.users = map[model.UserID]*model.User{10: {ID: 10, DeletedAt: &deletedAt}}
+	if _, err := service.ExchangeWSTicket(ctx, deleted.Ticket); !errors.Is(err, model.ErrUnauthorized) {
+		t.Errorf("Expected ErrUnauthorized for deleted account, got %v", err)
+	}
+}
@@ -68,6 +68,34 @@ func (u *Uploader) GetPublicURL(ctx context.Context, storedURL string, expiry ti
 	return presignedURL.String(), nil
 }
 
+// Delete removes a file previously returned by Upload. URLs that don't point to this
+// storage (e.g. OAuth provider avatars) are skipped; a missing object is not an error.
+func (u *Uploader) Delete(ctx context.Context, storedURL string) error {
+	key, ok := u.ownKey(storedURL)
+	if !ok {
+		return nil
+	}
+	return u.client.RemoveObject(ctx, u.bucket, key, minio.RemoveObjectOptions{})
+}
+
+// ownKey returns the object key of a URL produced by Upload for this bucket
+func (u *Uploader) ownKey(storedURL string) (string, bool) {
+	if u.cdnBase != "" && strings.HasPrefix(storedURL, u.cdnBase+"/") {
+		key := strings.TrimPrefix(storedURL, u.cdnBase+"/")
+		return key, key != ""
+	}
+	parsed, err := url.Parse(storedURL)
+	if err != nil {
+		return "", false
+	}
+	host := u.client.EndpointURL().Host
+	if parsed.Host != u.bucket+"."+host && parsed.Host != host {
+		return "", false
+	}
+	key := u.extractKeyFromURL(storedURL)
+	return key, key != ""
+}
+
 // extractKeyFromURL extracts the object key from a storage URL.
 // Handles formats like:
 // - https://bucket.endpoint.com/key
@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE user_roles (
+    user_id BIGINT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    role TEXT NOT NULL CHECK (role IN ('admin', 'moderator')),
+    granted_by_user_id BIGINT NULL REFERENCES users(user_id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, role)
+);
+
+CREATE TABLE moderation_actions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    actor_user_id BIGINT NOT NULL REFERENCES users(user_id),
+    action TEXT NOT NULL CHECK (action IN ('hide', 'unhide', 'delete')),
+    target_type TEXT NOT NULL CHECK (target_type IN ('contest', 'participant', 'comment', 'chat_message')),
+    target_id TEXT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_moderation_actions_created_at ON moderation_actions (created_at DESC);
+CREATE INDEX idx_moderation_actions_target ON moderation_actions (target_type, target_id);
+
+ALTER TABLE contests ADD COLUMN hidden_at TIMESTAMPTZ NULL;
+ALTER TABLE contest_participants ADD COLUMN hidden_at TIMESTAMPTZ NULL;
+ALTER TABLE contest_comments ADD COLUMN hidden_at TIMESTAMPTZ NULL;
+ALTER TABLE contest_chat_messages ADD COLUMN hidden_at TIMESTAMPTZ NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE contest_chat_messages DROP COLUMN IF EXISTS hidden_at;
+ALTER TABLE contest_comments DROP COLUMN IF EXISTS hidden_at;
+ALTER TABLE contest_participants DROP COLUMN IF EXISTS hidden_at;
+ALTER TABLE contests DROP COLUMN IF EXISTS hidden_at;
+
+DROP INDEX IF EXISTS idx_moderation_actions_target;
+DROP INDEX IF EXISTS idx_moderation_actions_created_at;
+DROP TABLE IF EXISTS moderation_actions;
+DROP TABLE IF EXISTS user_roles;
+-- +goose StatementEnd
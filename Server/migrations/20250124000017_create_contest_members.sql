@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE contest_members (
+    contest_id UUID NOT NULL REFERENCES contests(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    role TEXT NOT NULL CHECK (role IN ('owner', 'organizer', 'moderator')),
+    status TEXT NOT NULL CHECK (status IN ('invited', 'active')),
+    invited_by_user_id BIGINT NULL REFERENCES users(user_id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    accepted_at TIMESTAMPTZ NULL,
+    PRIMARY KEY (contest_id, user_id)
+);
+
+CREATE INDEX idx_contest_members_user_id ON contest_members (user_id);
+CREATE UNIQUE INDEX idx_contest_members_owner ON contest_members (contest_id) WHERE role = 'owner';
+
+-- Создатель существующих конкурсов становится владельцем
+INSERT INTO contest_members (contest_id, user_id, role, status, accepted_at)
+SELECT c.id, c.created_by_user_id, 'owner', 'active', c.created_at
+FROM contests c
+JOIN users u ON u.user_id = c.created_by_user_id
+ON CONFLICT DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_contest_members_owner;
+DROP INDEX IF EXISTS idx_contest_members_user_id;
+DROP TABLE IF EXISTS contest_members;
+-- +goose StatementEnd
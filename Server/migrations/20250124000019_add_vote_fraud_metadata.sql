@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE contest_votes
+    ADD COLUMN ip_hash TEXT NOT NULL DEFAULT '',
+    ADD COLUMN user_agent TEXT NOT NULL DEFAULT '',
+    ADD COLUMN auth_provider TEXT NOT NULL DEFAULT '',
+    ADD COLUMN account_created_at TIMESTAMPTZ NULL,
+    ADD COLUMN fraud_score INT NOT NULL DEFAULT 0,
+    ADD COLUMN fraud_reasons TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN flagged_at TIMESTAMPTZ NULL,
+    ADD COLUMN voided_at TIMESTAMPTZ NULL,
+    ADD COLUMN voided_by_user_id BIGINT NULL REFERENCES users(user_id) ON DELETE SET NULL,
+    ADD COLUMN void_reason TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX idx_votes_contest_ip_hash ON contest_votes (contest_id, ip_hash) WHERE ip_hash <> '';
+CREATE INDEX idx_votes_flagged ON contest_votes (contest_id, fraud_score DESC) WHERE flagged_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_votes_flagged;
+DROP INDEX IF EXISTS idx_votes_contest_ip_hash;
+
+ALTER TABLE contest_votes
+    DROP COLUMN IF EXISTS void_reason,
+    DROP COLUMN IF EXISTS voided_by_user_id,
+    DROP COLUMN IF EXISTS voided_at,
+    DROP COLUMN IF EXISTS flagged_at,
+    DROP COLUMN IF EXISTS fraud_reasons,
+    DROP COLUMN IF EXISTS fraud_score,
+    DROP COLUMN IF EXISTS account_created_at,
+    DROP COLUMN IF EXISTS auth_provider,
+    DROP COLUMN IF EXISTS user_agent,
+    DROP COLUMN IF EXISTS ip_hash;
+-- +goose StatementEnd
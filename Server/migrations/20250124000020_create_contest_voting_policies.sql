@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE contest_voting_policies (
+    contest_id UUID PRIMARY KEY REFERENCES contests(id) ON DELETE CASCADE,
+    min_account_age_hours INT NOT NULL DEFAULT 0 CHECK (min_account_age_hours >= 0),
+    required_providers TEXT[] NOT NULL DEFAULT '{}',
+    exclude_own_participants BOOLEAN NOT NULL DEFAULT FALSE,
+    invite_only BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE contest_invited_voters (
+    contest_id UUID NOT NULL REFERENCES contests(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    added_by_user_id BIGINT NULL REFERENCES users(user_id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (contest_id, user_id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS contest_invited_voters;
+DROP TABLE IF EXISTS contest_voting_policies;
+-- +goose StatementEnd
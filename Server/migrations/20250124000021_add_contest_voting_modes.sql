@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE contests
+    ADD COLUMN voting_mode TEXT NOT NULL DEFAULT 'single',
+    ADD COLUMN max_choices INT NOT NULL DEFAULT 1,
+    ADD COLUMN min_ballots INT NOT NULL DEFAULT 0;
+
+ALTER TABLE contests
+    ADD CONSTRAINT contests_voting_mode_check CHECK (voting_mode IN ('single', 'approval', 'ranked', 'stars')),
+    ADD CONSTRAINT contests_max_choices_check CHECK (max_choices >= 0),
+    ADD CONSTRAINT contests_min_ballots_check CHECK (min_ballots >= 0);
+
+-- Бюллетень в режимах approval/ranked/stars: contest_votes остается заголовком бюллетеня
+-- (одна запись на пользователя, метаданные антифрода, аннулирование), participant_id в нем -
+-- первый выбор, а все выбранные участники хранятся здесь.
+CREATE TABLE contest_vote_choices (
+    vote_id UUID NOT NULL REFERENCES contest_votes(id) ON DELETE CASCADE,
+    participant_id UUID NOT NULL REFERENCES contest_participants(id) ON DELETE CASCADE,
+    rank INT NOT NULL DEFAULT 0,
+    stars INT NULL CHECK (stars BETWEEN 1 AND 5),
+    PRIMARY KEY (vote_id, participant_id)
+);
+
+CREATE INDEX idx_vote_choices_participant_id ON contest_vote_choices (participant_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS contest_vote_choices;
+
+ALTER TABLE contests
+    DROP CONSTRAINT IF EXISTS contests_min_ballots_check,
+    DROP CONSTRAINT IF EXISTS contests_max_choices_check,
+    DROP CONSTRAINT IF EXISTS contests_voting_mode_check,
+    DROP COLUMN IF EXISTS min_ballots,
+    DROP COLUMN IF EXISTS max_choices,
+    DROP COLUMN IF EXISTS voting_mode;
+-- +goose StatementEnd
@@ -0,0 +1,48 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE contest_members DROP CONSTRAINT IF EXISTS contest_members_role_check;
+ALTER TABLE contest_members
+    ADD CONSTRAINT contest_members_role_check CHECK (role IN ('owner', 'organizer', 'moderator', 'juror'));
+
+-- Доля оценки жюри в итоговом результате, в процентах
+ALTER TABLE contests
+    ADD COLUMN jury_weight INT NOT NULL DEFAULT 0 CHECK (jury_weight BETWEEN 0 AND 100);
+
+CREATE TABLE contest_jury_criteria (
+    id UUID PRIMARY KEY,
+    contest_id UUID NOT NULL REFERENCES contests(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    weight INT NOT NULL DEFAULT 1 CHECK (weight BETWEEN 1 AND 100),
+    max_score INT NOT NULL DEFAULT 10 CHECK (max_score BETWEEN 1 AND 100),
+    position INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_jury_criteria_contest_id ON contest_jury_criteria (contest_id, position);
+
+CREATE TABLE contest_jury_scores (
+    criterion_id UUID NOT NULL REFERENCES contest_jury_criteria(id) ON DELETE CASCADE,
+    participant_id UUID NOT NULL REFERENCES contest_participants(id) ON DELETE CASCADE,
+    juror_user_id BIGINT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    contest_id UUID NOT NULL REFERENCES contests(id) ON DELETE CASCADE,
+    score INT NOT NULL CHECK (score >= 0),
+    comment TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (criterion_id, participant_id, juror_user_id)
+);
+
+CREATE INDEX idx_jury_scores_contest_id ON contest_jury_scores (contest_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS contest_jury_scores;
+DROP TABLE IF EXISTS contest_jury_criteria;
+ALTER TABLE contests DROP COLUMN IF EXISTS jury_weight;
+DELETE FROM contest_members WHERE role = 'juror';
+ALTER TABLE contest_members DROP CONSTRAINT IF EXISTS contest_members_role_check;
+ALTER TABLE contest_members
+    ADD CONSTRAINT contest_members_role_check CHECK (role IN ('owner', 'organizer', 'moderator'));
+-- +goose StatementEnd
@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Номинации конкурса. Голос без номинации (category_id IS NULL) - голос за главный приз.
+CREATE TABLE contest_categories (
+    id UUID PRIMARY KEY,
+    contest_id UUID NOT NULL REFERENCES contests(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    position INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX uniq_contest_categories_name ON contest_categories (contest_id, lower(name));
+CREATE INDEX idx_contest_categories_contest_id ON contest_categories (contest_id, position);
+
+ALTER TABLE contest_votes
+    ADD COLUMN category_id UUID NULL REFERENCES contest_categories(id) ON DELETE CASCADE;
+
+-- Один голос пользователя на номинацию; NULL (главный приз) считается одной номинацией
+DROP INDEX IF EXISTS uniq_votes_contest_user;
+CREATE UNIQUE INDEX uniq_votes_contest_category_user ON contest_votes (contest_id, category_id, user_id) NULLS NOT DISTINCT;
+CREATE INDEX idx_votes_category_id ON contest_votes (category_id) WHERE category_id IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM contest_votes WHERE category_id IS NOT NULL;
+DROP INDEX IF EXISTS idx_votes_category_id;
+DROP INDEX IF EXISTS uniq_votes_contest_category_user;
+CREATE UNIQUE INDEX uniq_votes_contest_user ON contest_votes (contest_id, user_id);
+ALTER TABLE contest_votes DROP COLUMN IF EXISTS category_id;
+DROP TABLE IF EXISTS contest_categories;
+-- +goose StatementEnd
@@ -0,0 +1,62 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Турнирная сетка конкурса: участники разбиваются на пары, в каждой паре голосуют отдельно,
+-- победители проходят в следующий раунд
+CREATE TABLE contest_brackets (
+    contest_id UUID PRIMARY KEY REFERENCES contests(id) ON DELETE CASCADE,
+    seeding TEXT NOT NULL DEFAULT 'random' CHECK (seeding IN ('random', 'likes')),
+    round_duration_minutes INT NOT NULL DEFAULT 1440 CHECK (round_duration_minutes BETWEEN 5 AND 10080),
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'running', 'finished')),
+    current_round INT NOT NULL DEFAULT 0,
+    champion_participant_id UUID NULL REFERENCES contest_participants(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE contest_bracket_rounds (
+    contest_id UUID NOT NULL REFERENCES contest_brackets(contest_id) ON DELETE CASCADE,
+    round INT NOT NULL CHECK (round >= 1),
+    status TEXT NOT NULL DEFAULT 'scheduled' CHECK (status IN ('scheduled', 'active', 'finished')),
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (contest_id, round)
+);
+
+CREATE INDEX idx_bracket_rounds_pending ON contest_bracket_rounds (starts_at, ends_at) WHERE status <> 'finished';
+
+-- Пара раунда. Пустая сторона - проход без соперника (bye), победитель известен сразу.
+-- seed_a/seed_b - посев участников, при равенстве голосов проходит участник с меньшим посевом.
+CREATE TABLE contest_bracket_matchups (
+    id UUID PRIMARY KEY,
+    contest_id UUID NOT NULL,
+    round INT NOT NULL,
+    position INT NOT NULL,
+    participant_a_id UUID NULL REFERENCES contest_participants(id) ON DELETE SET NULL,
+    participant_b_id UUID NULL REFERENCES contest_participants(id) ON DELETE SET NULL,
+    seed_a INT NOT NULL DEFAULT 0,
+    seed_b INT NOT NULL DEFAULT 0,
+    winner_id UUID NULL REFERENCES contest_participants(id) ON DELETE SET NULL,
+    decided_at TIMESTAMPTZ NULL,
+    FOREIGN KEY (contest_id, round) REFERENCES contest_bracket_rounds(contest_id, round) ON DELETE CASCADE,
+    UNIQUE (contest_id, round, position)
+);
+
+CREATE TABLE contest_bracket_votes (
+    matchup_id UUID NOT NULL REFERENCES contest_bracket_matchups(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    participant_id UUID NOT NULL REFERENCES contest_participants(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (matchup_id, user_id)
+);
+
+CREATE INDEX idx_bracket_votes_user_id ON contest_bracket_votes (user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS contest_bracket_votes;
+DROP TABLE IF EXISTS contest_bracket_matchups;
+DROP TABLE IF EXISTS contest_bracket_rounds;
+DROP TABLE IF EXISTS contest_brackets;
+-- +goose StatementEnd
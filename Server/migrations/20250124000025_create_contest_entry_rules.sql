@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Правила подачи заявок в конкурс. Нулевые лимиты - без ограничений.
+CREATE TABLE contest_entry_rules (
+    contest_id UUID PRIMARY KEY REFERENCES contests(id) ON DELETE CASCADE,
+    max_entries_per_user INT NOT NULL DEFAULT 0 CHECK (max_entries_per_user >= 0),
+    max_entries INT NOT NULL DEFAULT 0 CHECK (max_entries >= 0),
+    max_photos_per_entry INT NOT NULL DEFAULT 0 CHECK (max_photos_per_entry >= 0),
+    max_photo_size_mb INT NOT NULL DEFAULT 10 CHECK (max_photo_size_mb BETWEEN 1 AND 20),
+    video_allowed BOOLEAN NOT NULL DEFAULT TRUE,
+    max_video_duration_sec INT NOT NULL DEFAULT 0 CHECK (max_video_duration_sec >= 0),
+    max_video_size_mb INT NOT NULL DEFAULT 100 CHECK (max_video_size_mb BETWEEN 1 AND 500),
+    required_fields TEXT[] NOT NULL DEFAULT '{}',
+    entry_deadline TIMESTAMPTZ NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS contest_entry_rules;
+-- +goose StatementEnd
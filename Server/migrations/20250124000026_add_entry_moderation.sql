@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Премодерация заявок: при require_approval новая заявка ждет решения организатора
+-- и не видна в публичном списке участников, пока ее не одобрят
+ALTER TABLE contest_entry_rules ADD COLUMN require_approval BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE contest_participants
+    ADD COLUMN moderation_status TEXT NOT NULL DEFAULT 'approved' CHECK (moderation_status IN ('pending', 'approved', 'rejected')),
+    ADD COLUMN moderation_reason TEXT NOT NULL DEFAULT '',
+    ADD COLUMN moderated_by_user_id BIGINT NULL REFERENCES users(user_id) ON DELETE SET NULL,
+    ADD COLUMN moderated_at TIMESTAMPTZ NULL;
+
+CREATE INDEX idx_participants_pending ON contest_participants (contest_id, created_at) WHERE moderation_status = 'pending';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_participants_pending;
+
+ALTER TABLE contest_participants
+    DROP COLUMN IF EXISTS moderated_at,
+    DROP COLUMN IF EXISTS moderated_by_user_id,
+    DROP COLUMN IF EXISTS moderation_reason,
+    DROP COLUMN IF EXISTS moderation_status;
+
+ALTER TABLE contest_entry_rules DROP COLUMN IF EXISTS require_approval;
+-- +goose StatementEnd
@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Дисквалификация участника: запись остается в конкурсе с причиной, но исключается из списков и итогов
+ALTER TABLE contest_participants
+    ADD COLUMN disqualified_at TIMESTAMPTZ NULL,
+    ADD COLUMN disqualified_by_user_id BIGINT NULL REFERENCES users(user_id) ON DELETE SET NULL,
+    ADD COLUMN disqualification_reason TEXT NOT NULL DEFAULT '';
+
+-- Голоса, аннулированные из-за дисквалификации, не лишают автора права проголосовать заново
+ALTER TABLE contest_votes ADD COLUMN recastable BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE moderation_actions
+    DROP CONSTRAINT IF EXISTS moderation_actions_action_check;
+
+ALTER TABLE moderation_actions
+    ADD CONSTRAINT moderation_actions_action_check
+    CHECK (action IN ('hide', 'unhide', 'delete', 'disqualify'));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM moderation_actions WHERE action = 'disqualify';
+
+ALTER TABLE moderation_actions
+    DROP CONSTRAINT IF EXISTS moderation_actions_action_check;
+
+ALTER TABLE moderation_actions
+    ADD CONSTRAINT moderation_actions_action_check
+    CHECK (action IN ('hide', 'unhide', 'delete'));
+
+ALTER TABLE contest_votes DROP COLUMN IF EXISTS recastable;
+
+ALTER TABLE contest_participants
+    DROP COLUMN IF EXISTS disqualification_reason,
+    DROP COLUMN IF EXISTS disqualified_by_user_id,
+    DROP COLUMN IF EXISTS disqualified_at;
+-- +goose StatementEnd
@@ -0,0 +1,73 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Профиль питомца: владелец заводит его один раз и подает в разные конкурсы
+CREATE TABLE pets (
+    id UUID PRIMARY KEY,
+    owner_user_id BIGINT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    species TEXT NOT NULL DEFAULT '',
+    breed TEXT NOT NULL DEFAULT '',
+    birth_date DATE NULL,
+    bio TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_pets_owner ON pets (owner_user_id, created_at);
+
+-- Галерея питомца: из нее выбираются фото для заявки в конкретный конкурс
+CREATE TABLE pet_photos (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    pet_id UUID NOT NULL REFERENCES pets(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    thumb_url TEXT NULL,
+    position INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_pet_photos_pet ON pet_photos (pet_id, position);
+
+-- Заявка хранит снимок имени и описания на момент подачи, а pet_id связывает ее с профилем
+ALTER TABLE contest_participants ADD COLUMN pet_id UUID NULL REFERENCES pets(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_participants_pet ON contest_participants (pet_id);
+
+-- Перенос существующих заявок: один питомец на владельца и имя (без учета регистра),
+-- описание берется из последней заявки
+INSERT INTO pets (id, owner_user_id, name, bio, created_at, updated_at)
+SELECT gen_random_uuid(), latest.user_id, latest.name, latest.pet_description, latest.first_created_at, latest.created_at
+FROM (
+    SELECT DISTINCT ON (user_id, lower(btrim(pet_name)))
+        user_id,
+        btrim(pet_name) AS name,
+        pet_description,
+        created_at,
+        min(created_at) OVER (PARTITION BY user_id, lower(btrim(pet_name))) AS first_created_at
+    FROM contest_participants
+    ORDER BY user_id, lower(btrim(pet_name)), created_at DESC
+) latest;
+
+UPDATE contest_participants cp
+SET pet_id = p.id
+FROM pets p
+WHERE p.owner_user_id = cp.user_id AND lower(p.name) = lower(btrim(cp.pet_name));
+
+-- Фото заявок переносятся в галерею питомца без повторов
+INSERT INTO pet_photos (pet_id, url, thumb_url, position, created_at)
+SELECT pet_id, url, thumb_url, row_number() OVER (PARTITION BY pet_id ORDER BY created_at, url) - 1, created_at
+FROM (
+    SELECT DISTINCT ON (cp.pet_id, ph.url) cp.pet_id, ph.url, ph.thumb_url, ph.created_at
+    FROM contest_participant_photos ph
+    JOIN contest_participants cp ON cp.id = ph.participant_id
+    WHERE cp.pet_id IS NOT NULL
+    ORDER BY cp.pet_id, ph.url, ph.created_at
+) photos;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_participants_pet;
+ALTER TABLE contest_participants DROP COLUMN IF EXISTS pet_id;
+DROP TABLE IF EXISTS pet_photos;
+DROP TABLE IF EXISTS pets;
+-- +goose StatementEnd
@@ -0,0 +1,144 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Справочник видов и пород. Коды стабильны и используются в фильтрах и правилах конкурсов.
+CREATE TABLE species (
+    code TEXT PRIMARY KEY,
+    name_ru TEXT NOT NULL,
+    name_en TEXT NOT NULL,
+    position INT NOT NULL DEFAULT 0
+);
+
+CREATE TABLE breeds (
+    code TEXT PRIMARY KEY,
+    species_code TEXT NOT NULL REFERENCES species(code) ON DELETE CASCADE,
+    name_ru TEXT NOT NULL,
+    name_en TEXT NOT NULL
+);
+
+CREATE INDEX idx_breeds_species ON breeds (species_code, name_ru);
+
+INSERT INTO species (code, name_ru, name_en, position) VALUES
+    ('cat', 'Кошка', 'Cat', 1),
+    ('dog', 'Собака', 'Dog', 2),
+    ('rabbit', 'Кролик', 'Rabbit', 3),
+    ('rodent', 'Грызун', 'Rodent', 4),
+    ('ferret', 'Хорек', 'Ferret', 5),
+    ('bird', 'Птица', 'Bird', 6),
+    ('reptile', 'Рептилия', 'Reptile', 7),
+    ('fish', 'Рыба', 'Fish', 8),
+    ('horse', 'Лошадь', 'Horse', 9),
+    ('other', 'Другое', 'Other', 100);
+
+INSERT INTO breeds (code, species_code, name_ru, name_en) VALUES
+    ('cat_mixed', 'cat', 'Беспородная', 'Mixed breed'),
+    ('cat_abyssinian', 'cat', 'Абиссинская', 'Abyssinian'),
+    ('cat_bengal', 'cat', 'Бенгальская', 'Bengal'),
+    ('cat_british_shorthair', 'cat', 'Британская короткошерстная', 'British Shorthair'),
+    ('cat_maine_coon', 'cat', 'Мейн-кун', 'Maine Coon'),
+    ('cat_persian', 'cat', 'Персидская', 'Persian'),
+    ('cat_ragdoll', 'cat', 'Рэгдолл', 'Ragdoll'),
+    ('cat_russian_blue', 'cat', 'Русская голубая', 'Russian Blue'),
+    ('cat_scottish_fold', 'cat', 'Шотландская вислоухая', 'Scottish Fold'),
+    ('cat_siamese', 'cat', 'Сиамская', 'Siamese'),
+    ('cat_siberian', 'cat', 'Сибирская', 'Siberian'),
+    ('cat_sphynx', 'cat', 'Сфинкс', 'Sphynx'),
+    ('dog_mixed', 'dog', 'Беспородная', 'Mixed breed'),
+    ('dog_beagle', 'dog', 'Бигль', 'Beagle'),
+    ('dog_border_collie', 'dog', 'Бордер-колли', 'Border Collie'),
+    ('dog_chihuahua', 'dog', 'Чихуахуа', 'Chihuahua'),
+    ('dog_corgi', 'dog', 'Вельш-корги', 'Welsh Corgi'),
+    ('dog_dachshund', 'dog', 'Такса', 'Dachshund'),
+    ('dog_french_bulldog', 'dog', 'Французский бульдог', 'French Bulldog'),
+    ('dog_german_shepherd', 'dog', 'Немецкая овчарка', 'German Shepherd'),
+    ('dog_golden_retriever', 'dog', 'Золотистый ретривер', 'Golden Retriever'),
+    ('dog_husky', 'dog', 'Сибирский хаски', 'Siberian Husky'),
+    ('dog_labrador', 'dog', 'Лабрадор-ретривер', 'Labrador Retriever'),
+    ('dog_pomeranian', 'dog', 'Померанский шпиц', 'Pomeranian'),
+    ('dog_poodle', 'dog', 'Пудель', 'Poodle'),
+    ('dog_pug', 'dog', 'Мопс', 'Pug'),
+    ('dog_yorkshire_terrier', 'dog', 'Йоркширский терьер', 'Yorkshire Terrier'),
+    ('rabbit_mixed', 'rabbit', 'Беспородный', 'Mixed breed'),
+    ('rabbit_dwarf', 'rabbit', 'Декоративный карликовый', 'Netherland Dwarf'),
+    ('rabbit_lop', 'rabbit', 'Вислоухий', 'Lop'),
+    ('rodent_hamster', 'rodent', 'Хомяк', 'Hamster'),
+    ('rodent_guinea_pig', 'rodent', 'Морская свинка', 'Guinea pig'),
+    ('rodent_rat', 'rodent', 'Крыса', 'Rat'),
+    ('rodent_chinchilla', 'rodent', 'Шиншилла', 'Chinchilla'),
+    ('bird_budgerigar', 'bird', 'Волнистый попугай', 'Budgerigar'),
+    ('bird_cockatiel', 'bird', 'Корелла', 'Cockatiel'),
+    ('bird_canary', 'bird', 'Канарейка', 'Canary'),
+    ('reptile_turtle', 'reptile', 'Черепаха', 'Turtle'),
+    ('reptile_gecko', 'reptile', 'Геккон', 'Gecko'),
+    ('reptile_snake', 'reptile', 'Змея', 'Snake');
+
+-- Структурированные признаки заявки: снимок на момент подачи, как имя и описание
+ALTER TABLE contest_participants
+    ADD COLUMN species TEXT NULL REFERENCES species(code),
+    ADD COLUMN breed TEXT NULL REFERENCES breeds(code),
+    ADD COLUMN sex TEXT NULL CHECK (sex IN ('male', 'female')),
+    ADD COLUMN birth_date DATE NULL;
+
+CREATE INDEX idx_participants_species ON contest_participants (contest_id, species);
+
+-- Вид и порода профиля питомца переходят со свободного текста на коды справочника.
+-- Текст, который удалось сопоставить с названием вида или породы, сохраняется, остальное сбрасывается.
+ALTER TABLE pets
+    ALTER COLUMN species DROP NOT NULL,
+    ALTER COLUMN species DROP DEFAULT,
+    ALTER COLUMN breed DROP NOT NULL,
+    ALTER COLUMN breed DROP DEFAULT,
+    ADD COLUMN sex TEXT NULL CHECK (sex IN ('male', 'female'));
+
+UPDATE pets p
+SET species = s.code
+FROM species s
+WHERE lower(btrim(p.species)) IN (s.code, lower(s.name_ru), lower(s.name_en));
+
+UPDATE pets p
+SET breed = b.code
+FROM breeds b
+WHERE b.species_code = p.species
+  AND lower(btrim(p.breed)) IN (b.code, lower(b.name_ru), lower(b.name_en));
+
+UPDATE pets SET species = NULL WHERE species NOT IN (SELECT code FROM species);
+UPDATE pets SET breed = NULL WHERE breed NOT IN (SELECT code FROM breeds);
+
+ALTER TABLE pets
+    ADD CONSTRAINT pets_species_fkey FOREIGN KEY (species) REFERENCES species(code),
+    ADD CONSTRAINT pets_breed_fkey FOREIGN KEY (breed) REFERENCES breeds(code);
+
+-- Ограничения конкурса по виду и возрасту (в полных месяцах); пустой список и нули - без ограничений
+ALTER TABLE contest_entry_rules
+    ADD COLUMN allowed_species TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN min_age_months INT NOT NULL DEFAULT 0 CHECK (min_age_months >= 0),
+    ADD COLUMN max_age_months INT NOT NULL DEFAULT 0 CHECK (max_age_months >= 0);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE contest_entry_rules
+    DROP COLUMN IF EXISTS max_age_months,
+    DROP COLUMN IF EXISTS min_age_months,
+    DROP COLUMN IF EXISTS allowed_species;
+
+ALTER TABLE pets
+    DROP CONSTRAINT IF EXISTS pets_breed_fkey,
+    DROP CONSTRAINT IF EXISTS pets_species_fkey,
+    DROP COLUMN IF EXISTS sex;
+UPDATE pets SET species = COALESCE(species, ''), breed = COALESCE(breed, '');
+ALTER TABLE pets
+    ALTER COLUMN species SET DEFAULT '',
+    ALTER COLUMN species SET NOT NULL,
+    ALTER COLUMN breed SET DEFAULT '',
+    ALTER COLUMN breed SET NOT NULL;
+
+DROP INDEX IF EXISTS idx_participants_species;
+ALTER TABLE contest_participants
+    DROP COLUMN IF EXISTS birth_date,
+    DROP COLUMN IF EXISTS sex,
+    DROP COLUMN IF EXISTS breed,
+    DROP COLUMN IF EXISTS species;
+
+DROP TABLE IF EXISTS breeds;
+DROP TABLE IF EXISTS species;
+-- +goose StatementEnd
@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Аватар (пока только из OAuth провайдера) и био для публичного профиля
+ALTER TABLE users
+    ADD COLUMN avatar_url TEXT NULL,
+    ADD COLUMN bio TEXT NOT NULL DEFAULT '';
+
+-- Что показывать в публичном профиле. Нет строки - все разделы открыты.
+CREATE TABLE user_privacy_settings (
+    user_id BIGINT PRIMARY KEY REFERENCES users(user_id) ON DELETE CASCADE,
+    show_contests BOOLEAN NOT NULL DEFAULT TRUE,
+    show_pets BOOLEAN NOT NULL DEFAULT TRUE,
+    show_results BOOLEAN NOT NULL DEFAULT TRUE,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_contests_created_by ON contests (created_by_user_id, created_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_contests_created_by;
+DROP TABLE IF EXISTS user_privacy_settings;
+ALTER TABLE users
+    DROP COLUMN IF EXISTS bio,
+    DROP COLUMN IF EXISTS avatar_url;
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Загруженный аватар хранится в двух размерах; у аватара провайдера уменьшенной копии нет
+ALTER TABLE users
+    ADD COLUMN avatar_thumb_url TEXT NULL,
+    ADD COLUMN locale TEXT NOT NULL DEFAULT 'ru' CHECK (locale IN ('ru', 'en'));
+
+-- Аватар из OAuth провайдера запоминается при каждом входе, чтобы к нему можно было вернуться
+-- после загрузки своего. Для входов до этой миграции аватар появится при следующем входе.
+ALTER TABLE user_auth_providers
+    ADD COLUMN avatar_url TEXT NULL,
+    ADD COLUMN avatar_updated_at TIMESTAMPTZ NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE user_auth_providers
+    DROP COLUMN IF EXISTS avatar_updated_at,
+    DROP COLUMN IF EXISTS avatar_url;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS locale,
+    DROP COLUMN IF EXISTS avatar_thumb_url;
+-- +goose StatementEnd
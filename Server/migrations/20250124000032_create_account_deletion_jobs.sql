@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Удаленный пользователь остается строкой users, чтобы его конкурсы, комментарии и сообщения
+-- не потеряли автора; имя и профиль обезличиваются, вход и обновление токенов запрещены
+ALTER TABLE users ADD COLUMN deleted_at TIMESTAMPTZ NULL;
+
+-- Фоновое удаление аккаунта: задание выполняется воркером, клиент опрашивает статус по ID
+CREATE TABLE account_deletion_jobs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id BIGINT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'running', 'completed', 'failed')),
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    started_at TIMESTAMPTZ NULL,
+    finished_at TIMESTAMPTZ NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Не больше одного незавершенного задания на пользователя
+CREATE UNIQUE INDEX uniq_account_deletion_jobs_active_user ON account_deletion_jobs (user_id)
+    WHERE status IN ('pending', 'running');
+CREATE INDEX idx_account_deletion_jobs_status_created_at ON account_deletion_jobs (status, created_at);
+
+-- Выгрузка и удаление данных ищут их по автору
+CREATE INDEX idx_votes_user_id ON contest_votes (user_id);
+CREATE INDEX idx_comments_user_id ON contest_comments (user_id);
+CREATE INDEX idx_chat_user_id ON contest_chat_messages (user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_chat_user_id;
+DROP INDEX IF EXISTS idx_comments_user_id;
+DROP INDEX IF EXISTS idx_votes_user_id;
+DROP INDEX IF EXISTS idx_account_deletion_jobs_status_created_at;
+DROP INDEX IF EXISTS uniq_account_deletion_jobs_active_user;
+DROP TABLE IF EXISTS account_deletion_jobs;
+
+ALTER TABLE users DROP COLUMN IF EXISTS deleted_at;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Входящие уведомления пользователя. title/text - снимок на момент события (название конкурса,
+-- кличка питомца, начало комментария), чтобы список не требовал join'ов и переживал переименования
+CREATE TABLE notifications (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id BIGINT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    type TEXT NOT NULL CHECK (type IN ('voting_started', 'results_published', 'entry_comment', 'comment_reply')),
+    contest_id UUID NULL REFERENCES contests(id) ON DELETE CASCADE,
+    participant_id UUID NULL REFERENCES contest_participants(id) ON DELETE CASCADE,
+    comment_id UUID NULL REFERENCES contest_comments(id) ON DELETE CASCADE,
+    actor_user_id BIGINT NULL REFERENCES users(user_id) ON DELETE SET NULL,
+    title TEXT NOT NULL DEFAULT '',
+    text TEXT NOT NULL DEFAULT '',
+    read_at TIMESTAMPTZ NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_notifications_user_created_at ON notifications (user_id, created_at DESC);
+CREATE INDEX idx_notifications_user_unread ON notifications (user_id) WHERE read_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_notifications_user_unread;
+DROP INDEX IF EXISTS idx_notifications_user_created_at;
+DROP TABLE IF EXISTS notifications;
+-- +goose StatementEnd
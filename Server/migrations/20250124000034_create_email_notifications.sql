@@ -0,0 +1,47 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Настройки уведомлений пользователя. email - адрес из провайдера входа, запоминается при первом входе,
+-- в котором провайдер его передал. Нет строки - письма включены, но адрес неизвестен.
+CREATE TABLE notification_preferences (
+    user_id BIGINT PRIMARY KEY REFERENCES users(user_id) ON DELETE CASCADE,
+    email TEXT NULL,
+    email_voting_started BOOLEAN NOT NULL DEFAULT TRUE,
+    email_results_published BOOLEAN NOT NULL DEFAULT TRUE,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Для входа по ссылке из письма адрес известен и подтвержден
+INSERT INTO notification_preferences (user_id, email)
+SELECT DISTINCT ON (user_id) user_id, provider_uid
+FROM user_auth_providers
+WHERE provider = 'email'
+ORDER BY user_id, provider_uid
+ON CONFLICT (user_id) DO NOTHING;
+
+-- Исходящие письма. Письмо рендерится при постановке в очередь, воркер только отправляет,
+-- поэтому после перезапуска ничего не теряется. Неудачная отправка повторяется с растущей задержкой.
+CREATE TABLE email_outbox (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id BIGINT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    to_email TEXT NOT NULL,
+    subject TEXT NOT NULL,
+    text_body TEXT NOT NULL,
+    html_body TEXT NOT NULL DEFAULT '',
+    unsubscribe_url TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'sent', 'failed')),
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    sent_at TIMESTAMPTZ NULL
+);
+
+CREATE INDEX idx_email_outbox_pending ON email_outbox (next_attempt_at) WHERE status = 'pending';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_email_outbox_pending;
+DROP TABLE IF EXISTS email_outbox;
+DROP TABLE IF EXISTS notification_preferences;
+-- +goose StatementEnd
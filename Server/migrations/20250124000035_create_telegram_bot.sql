@@ -0,0 +1,73 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Одноразовые токены привязки аккаунта: сайт выдает ссылку t.me/<bot>?start=link_<token>,
+-- бот гасит токен при /start. Хранится только хеш.
+CREATE TABLE telegram_link_tokens (
+    token_hash TEXT PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_telegram_link_tokens_user_id ON telegram_link_tokens (user_id);
+
+-- Привязанный личный чат с ботом. Один чат - один аккаунт.
+CREATE TABLE telegram_accounts (
+    user_id BIGINT PRIMARY KEY REFERENCES users(user_id) ON DELETE CASCADE,
+    chat_id BIGINT NOT NULL UNIQUE,
+    username TEXT NOT NULL DEFAULT '',
+    linked_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Подписки чатов на конкурсы. Подписаться можно и без привязки аккаунта.
+CREATE TABLE telegram_subscriptions (
+    chat_id BIGINT NOT NULL,
+    contest_id UUID NOT NULL REFERENCES contests(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (chat_id, contest_id)
+);
+
+CREATE INDEX idx_telegram_subscriptions_contest_id ON telegram_subscriptions (contest_id);
+
+-- Исходящие сообщения бота, устроены так же, как email_outbox. chat_id - числовой ID чата
+-- или @username канала.
+CREATE TABLE telegram_outbox (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    chat_id TEXT NOT NULL,
+    text TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'sent', 'failed')),
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    sent_at TIMESTAMPTZ NULL
+);
+
+CREATE INDEX idx_telegram_outbox_pending ON telegram_outbox (next_attempt_at) WHERE status = 'pending';
+
+-- Анонсы конкурсов в канале от организаторов; статус доставки берется из telegram_outbox
+CREATE TABLE telegram_announcements (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    contest_id UUID NOT NULL REFERENCES contests(id) ON DELETE CASCADE,
+    outbox_id UUID NULL REFERENCES telegram_outbox(id) ON DELETE SET NULL,
+    chat_id TEXT NOT NULL,
+    text TEXT NOT NULL,
+    posted_by_user_id BIGINT NULL REFERENCES users(user_id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_telegram_announcements_contest_id ON telegram_announcements (contest_id, created_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_telegram_announcements_contest_id;
+DROP TABLE IF EXISTS telegram_announcements;
+DROP INDEX IF EXISTS idx_telegram_outbox_pending;
+DROP TABLE IF EXISTS telegram_outbox;
+DROP INDEX IF EXISTS idx_telegram_subscriptions_contest_id;
+DROP TABLE IF EXISTS telegram_subscriptions;
+DROP TABLE IF EXISTS telegram_accounts;
+DROP INDEX IF EXISTS idx_telegram_link_tokens_user_id;
+DROP TABLE IF EXISTS telegram_link_tokens;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Комментарии не образуют веток, поэтому comment_reply переименован в entry_discussion:
+-- остальные комментаторы заявки узнают о продолжении обсуждения, а не об ответе им.
+ALTER TABLE notifications DROP CONSTRAINT notifications_type_check;
+UPDATE notifications SET type = 'entry_discussion' WHERE type = 'comment_reply';
+ALTER TABLE notifications ADD CONSTRAINT notifications_type_check
+    CHECK (type IN ('voting_started', 'results_published', 'entry_comment', 'entry_discussion'));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE notifications DROP CONSTRAINT notifications_type_check;
+UPDATE notifications SET type = 'comment_reply' WHERE type = 'entry_discussion';
+ALTER TABLE notifications ADD CONSTRAINT notifications_type_check
+    CHECK (type IN ('voting_started', 'results_published', 'entry_comment', 'comment_reply'));
+-- +goose StatementEnd
@@ -22,3 +22,8 @@ export const updateCurrentUser = async (data: { name: string }): Promise<User> =
   const response = await axiosClient.patch<User>('/auth/me', data);
   return response.data;
 };
+
+export const getWSTicket = async (): Promise<{ ticket: string; expires_at: string }> => {
+  const response = await axiosClient.post<{ ticket: string; expires_at: string }>('/ws/ticket');
+  return response.data;
+};
@@ -3,16 +3,63 @@
 export type UserID = number;
 export type ContestID = string;
 export type ParticipantID = string;
+export type CategoryID = string;
+export type PetID = string;
 export type CommentID = string;
 export type ChatMessageID = string;
 
 export type ContestStatus = 'draft' | 'registration' | 'voting' | 'finished';
 
+export type VotingMode = 'single' | 'approval' | 'ranked' | 'stars';
+
 export interface User {
   id: UserID;
   name: string;
   avatar_url?: string;
+  avatar_thumb_url?: string;
+  bio?: string;
+  locale?: 'ru' | 'en';
+  roles?: Array<'admin' | 'moderator'>;
+  deleted_at?: string;
+  created_at: string;
+}
+
+export type AccountDeletionStatus = 'pending' | 'running' | 'completed' | 'failed';
+
+export interface AccountDeletionJob {
+  id: string;
+  status: AccountDeletionStatus;
+  attempts: number;
+  created_at: string;
+  started_at?: string;
+  finished_at?: string;
+}
+
+export interface PrivacySettings {
+  show_contests: boolean;
+  show_pets: boolean;
+  show_results: boolean;
+  updated_at?: string;
+}
+
+export interface UserResults {
+  entries: number;
+  wins: number;
+  podiums: number;
+  placements: PetEntry[];
+}
+
+// Разделы, скрытые настройками приватности, равны null
+export interface PublicUserProfile {
+  id: UserID;
+  name: string;
+  avatar_url?: string;
+  bio?: string;
   created_at: string;
+  contests: Contest[] | null;
+  pets: Pet[] | null;
+  results: UserResults | null;
+  privacy?: PrivacySettings;
 }
 
 export interface Contest {
@@ -21,21 +68,90 @@ export interface Contest {
   title: string;
   description: string;
   status: ContestStatus;
+  voting_mode?: VotingMode;
+  max_choices?: number;
+  min_ballots?: number;
+  jury_weight?: number;
   total_votes?: number;
   created_at: string;
   updated_at: string;
 }
 
-export interface Participant {
+export type EntryModerationStatus = 'pending' | 'approved' | 'rejected';
+
+export type PetSex = 'male' | 'female';
+
+// Structured pet attributes: species and breed are taxonomy codes
+export interface PetAttributes {
+  species?: string;
+  breed?: string;
+  sex?: PetSex;
+  birth_date?: string;
+}
+
+export interface Species {
+  code: string;
+  name_ru: string;
+  name_en: string;
+}
+
+export interface Breed {
+  code: string;
+  species: string;
+  name_ru: string;
+  name_en: string;
+}
+
+export interface Participant extends PetAttributes {
   id: ParticipantID;
   contest_id: ContestID;
   user_id: UserID;
   user_name?: string;
+  pet_id?: PetID;
   pet_name: string;
   pet_description: string;
   photos?: Photo[];
   video?: Video;
   total_votes?: number;
+  average_stars?: number;
+  moderation_status?: EntryModerationStatus;
+  moderation_reason?: string;
+  moderated_at?: string;
+  disqualified_at?: string;
+  disqualification_reason?: string;
+  created_at: string;
+  updated_at: string;
+}
+
+export interface PetPhoto {
+  id: string;
+  pet_id: PetID;
+  url: string;
+  thumb_url?: string;
+  position: number;
+  created_at: string;
+}
+
+export interface PetEntry {
+  participant_id: ParticipantID;
+  contest_id: ContestID;
+  contest_title: string;
+  contest_status: ContestStatus;
+  pet_id?: PetID;
+  pet_name: string;
+  place?: number;
+  won?: boolean;
+  created_at: string;
+}
+
+export interface Pet extends PetAttributes {
+  id: PetID;
+  owner_user_id: UserID;
+  name: string;
+  bio?: string;
+  photos?: PetPhoto[];
+  entries?: PetEntry[];
+  wins: number;
   created_at: string;
   updated_at: string;
 }
@@ -69,6 +185,7 @@ export interface Video {
 export interface Vote {
   id: string;
   contest_id: ContestID;
+  category_id?: CategoryID;
   participant_id: ParticipantID;
   user_id: UserID;
   created_at: string;
@@ -113,8 +230,183 @@ export interface PaginatedResponse<T> {
   total: number;
 }
 
+export type NotificationType = 'voting_started' | 'results_published' | 'entry_comment' | 'entry_discussion';
+
+export interface Notification {
+  id: string;
+  type: NotificationType;
+  contest_id?: ContestID;
+  participant_id?: ParticipantID;
+  comment_id?: string;
+  actor_user_id?: UserID;
+  actor_name?: string;
+  title: string;
+  text?: string;
+  read_at?: string;
+  created_at: string;
+}
+
+export interface NotificationList extends PaginatedResponse<Notification> {
+  unread_count: number;
+}
+
+export interface NotificationPreferences {
+  email?: string;
+  email_voting_started: boolean;
+  email_results_published: boolean;
+  updated_at?: string;
+}
+
+export interface TelegramStatus {
+  enabled: boolean;
+  bot_username?: string;
+  linked: boolean;
+  username?: string;
+  linked_at?: string;
+}
+
+export interface TelegramLink {
+  url: string;
+  expires_at: string;
+}
+
+export type TelegramAnnouncementStatus = 'pending' | 'sent' | 'failed';
+
+export interface TelegramAnnouncement {
+  id: string;
+  contest_id: ContestID;
+  chat_id: string;
+  text: string;
+  posted_by_user_id?: UserID;
+  status: TelegramAnnouncementStatus;
+  last_error?: string;
+  created_at: string;
+  sent_at?: string;
+}
+
+export type WebhookEventType =
+  | 'participant.created'
+  | 'participant.disqualified'
+  | 'contest.status_updated'
+  | 'contest.votes_updated'
+  | 'contest.results_published'
+  | 'bracket.round_started'
+  | 'bracket.matchup_decided';
+
+export interface WebhookEndpoint {
+  id: string;
+  contest_id: ContestID;
+  url: string;
+  secret?: string;
+  event_types: WebhookEventType[];
+  active: boolean;
+  created_by_user_id?: UserID;
+  created_at: string;
+  updated_at: string;
+}
+
+export type WebhookDeliveryStatus = 'pending' | 'sent' | 'failed';
+
+export interface WebhookDelivery {
+  id: string;
+  webhook_id: string;
+  event_id: string;
+  event_type: WebhookEventType;
+  redelivery: boolean;
+  status: WebhookDeliveryStatus;
+  attempts: number;
+  response_status?: number;
+  response_body?: string;
+  duration_ms: number;
+  last_error?: string;
+  next_attempt_at?: string;
+  last_attempt_at?: string;
+  created_at: string;
+  delivered_at?: string;
+}
+
+export interface ContestCategory {
+  id: CategoryID;
+  contest_id: ContestID;
+  name: string;
+  description: string;
+  position: number;
+  created_at: string;
+  updated_at: string;
+}
+
+export type EntryRequiredField = 'pet_description';
+
+export interface EntryRules {
+  contest_id: ContestID;
+  max_entries_per_user: number;
+  max_entries: number;
+  max_photos_per_entry: number;
+  max_photo_size_mb: number;
+  video_allowed: boolean;
+  max_video_duration_sec: number;
+  max_video_size_mb: number;
+  required_fields: EntryRequiredField[];
+  entry_deadline?: string;
+  require_approval: boolean;
+  allowed_species: string[];
+  min_age_months: number;
+  max_age_months: number;
+  updated_at?: string;
+}
+
+export type BracketSeeding = 'random' | 'likes';
+export type BracketStatus = 'pending' | 'running' | 'finished';
+export type BracketRoundStatus = 'scheduled' | 'active' | 'finished';
+
+export interface BracketMatchup {
+  id: string;
+  round: number;
+  position: number;
+  participant_a_id?: ParticipantID;
+  participant_b_id?: ParticipantID;
+  seed_a?: number;
+  seed_b?: number;
+  votes_a: number;
+  votes_b: number;
+  winner_id?: ParticipantID;
+  decided_at?: string;
+  user_vote?: ParticipantID;
+}
+
+export interface BracketRound {
+  round: number;
+  status: BracketRoundStatus;
+  starts_at: string;
+  ends_at: string;
+  matchups: BracketMatchup[];
+}
+
+export interface Bracket {
+  contest_id: ContestID;
+  seeding: BracketSeeding;
+  round_duration_minutes: number;
+  status: BracketStatus;
+  current_round: number;
+  champion_id?: ParticipantID;
+  rounds: BracketRound[];
+  created_at: string;
+  updated_at: string;
+}
+
+export interface BallotChoice {
+  participant_id: ParticipantID;
+  rank?: number;
+  stars?: number;
+}
+
 export interface VoteResponse {
+  category_id?: CategoryID;
   participant_id: string;
+  choices?: BallotChoice[];
+  voided?: boolean;
+  eligible?: boolean;
+  reason?: string;
 }
 
 export interface PhotoLikeResponse {
@@ -1,3 +1,5 @@
+import { Notification } from './models';
+
 // WebSocket message types
 
 export type WSConnectionState = 'CONNECTING' | 'CONNECTED' | 'DISCONNECTED' | 'RECONNECTING';
@@ -25,3 +27,15 @@ export interface WSOutgoingMessage {
     updated_at: string;
   };
 }
+
+// Сообщения личного канала пользователя: приходят без подписки на конкурс
+export interface WSNotificationMessage {
+  type: 'notification';
+  notification: Notification;
+}
+
+export interface WSNotificationsReadMessage {
+  type: 'notifications_read';
+  notification_id?: string;
+  unread_count: number;
+}
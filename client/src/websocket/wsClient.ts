@@ -1,5 +1,6 @@
 import { WSConnectionState, WSIncomingMessage } from '../types/ws';
 import { tokenStorage } from '../utils/tokenStorage';
+import { getWSTicket } from '../api/authApi';
 import { ChatMessage, ContestStatus } from '../types/models';
 
 const WS_URL = process.env.REACT_APP_WS_URL || 'ws://localhost:8080/api';
@@ -80,7 +81,7 @@ export class WebSocketClient {
     }
   }
 
-  private getWebSocketUrl(contestId: string): string {
+  private getWebSocketUrl(contestId: string, ticket: string): string {
     // WS_URL should already be ws:// or wss://, but handle http:// case
     let baseUrl = WS_URL;
     if (baseUrl.startsWith('http://')) {
@@ -89,10 +90,9 @@ export class WebSocketClient {
       baseUrl = baseUrl.replace('https://', 'wss://');
     }
     
+    // Single-use ticket instead of the access token, so tokens never end up in URLs/logs
     const url = new URL(`${baseUrl}/contests/${contestId}/chat/ws`);
-    if (this.accessToken) {
-      url.searchParams.set('accessToken', this.accessToken);
-    }
+    url.searchParams.set('ticket', ticket);
     return url.toString();
   }
 
@@ -132,8 +132,26 @@ export class WebSocketClient {
 
     this.setConnectionState('CONNECTING');
 
+    const contestId = this.contestId;
+    getWSTicket()
+      .then(({ ticket }) => {
+        // Disconnected or switched contest while the ticket was being issued
+        if (this.contestId !== contestId) {
+          return;
+        }
+        this.openSocket(this.getWebSocketUrl(contestId, ticket));
+      })
+      .catch((error) => {
+        console.error('WebSocket: Failed to get ticket', error);
+        this.setConnectionState('DISCONNECTED');
+        if (this.reconnectAttempts < MAX_RECONNECT_ATTEMPTS) {
+          this.scheduleReconnect();
+        }
+      });
+  }
+
+  private openSocket(url: string): void {
     try {
-      const url = this.getWebSocketUrl(this.contestId);
       this.ws = new WebSocket(url);
 
       this.ws.onopen = () => {
@@ -235,7 +253,8 @@ export class WebSocketClient {
     }
     // Fix: Server sends "vote_created" and "vote_deleted" types, not "vote_counts_updated"
     if ((data.type === 'vote_counts_updated' || data.type === 'vote_created' || data.type === 'vote_deleted') && data.contest_id) {
-      if (this.onVoteCountsUpdatedHandler) {
+      // Counters of award categories are not shown yet; only the main prize updates participant totals
+      if (this.onVoteCountsUpdatedHandler && !data.category_id) {
         this.onVoteCountsUpdatedHandler(
           String(data.contest_id),
           data.participant_id ? String(data.participant_id) : undefined,